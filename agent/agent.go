@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	"sync"
 	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
@@ -19,6 +20,7 @@ import (
 	"github.com/orb-community/orb/agent/backend"
 	"github.com/orb-community/orb/agent/cloud_config"
 	"github.com/orb-community/orb/agent/config"
+	"github.com/orb-community/orb/agent/otel/otlpmqttexporter"
 	manager "github.com/orb-community/orb/agent/policyMgr"
 	"github.com/orb-community/orb/buildinfo"
 	"go.uber.org/zap"
@@ -32,7 +34,7 @@ type Agent interface {
 	Start(ctx context.Context, cancelFunc context.CancelFunc) error
 	Stop(ctx context.Context)
 	RestartAll(ctx context.Context, reason string) error
-	RestartBackend(ctx context.Context, backend string, reason string) error
+	RestartBackend(ctx context.Context, backend string, reason string, reapplyPolicies bool) error
 }
 
 type orbAgent struct {
@@ -48,7 +50,10 @@ type orbAgent struct {
 
 	asyncContext context.Context
 
+	// hbMu guards the heartbeat ticker and interval, reconfigured from the RPC goroutine
+	hbMu            sync.Mutex
 	hbTicker        *time.Ticker
+	hbInterval      time.Duration
 	heartbeatCtx    context.Context
 	heartbeatCancel context.CancelFunc
 
@@ -69,7 +74,19 @@ type orbAgent struct {
 	// AgentGroup channels sent from core
 	groupsInfos map[string]GroupInfo
 
+	// request of the key rotation whose key is pending, waiting for its commit from core,
+	// guarded by keyRotationMu as acknowledgements and commits are handled by separate RPC goroutines
+	keyRotationMu sync.Mutex
+	keyRotationID string
+
 	policyManager manager.PolicyManager
+
+	// telemetry held on disk while the MQTT connection is unavailable
+	spool *otlpmqttexporter.Spool
+
+	// agent and backend logs shipped over the log topic, rawLogger is not teed to it
+	logShipper *logShipper
+	rawLogger  *zap.Logger
 }
 
 const retryRequestDuration = time.Second
@@ -100,7 +117,25 @@ func New(logger *zap.Logger, c config.Config) (Agent, error) {
 		logger.Error("policy manager failed to get repository", zap.Error(err))
 		return nil, err
 	}
-	return &orbAgent{logger: logger, config: c, policyManager: pm, db: db, groupsInfos: make(map[string]GroupInfo)}, nil
+	var spool *otlpmqttexporter.Spool
+	if c.OrbAgent.Spool.Enable {
+		spool, err = otlpmqttexporter.NewSpool(logger, c.OrbAgent.Spool.Directory, c.OrbAgent.Spool.MaxSizeMB*1024*1024, c.OrbAgent.Spool.MaxAge)
+		if err != nil {
+			logger.Error("error during create telemetry spool, exiting", zap.Error(err))
+			return nil, err
+		}
+	}
+	rawLogger := logger
+	var shipper *logShipper
+	if c.OrbAgent.Logs.Enable {
+		shipper, err = newLogShipper(c.OrbAgent.Logs.Level, c.OrbAgent.Logs.RateLimit)
+		if err != nil {
+			logger.Error("invalid log shipping level, exiting", zap.String("level", c.OrbAgent.Logs.Level), zap.Error(err))
+			return nil, err
+		}
+		logger = logger.WithOptions(zap.WrapCore(shipper.wrapCore))
+	}
+	return &orbAgent{logger: logger, rawLogger: rawLogger, logShipper: shipper, config: c, policyManager: pm, db: db, spool: spool, groupsInfos: make(map[string]GroupInfo)}, nil
 }
 
 func (a *orbAgent) startBackends(agentCtx context.Context) error {
@@ -118,7 +153,8 @@ func (a *orbAgent) startBackends(agentCtx context.Context) error {
 		be := backend.GetBackend(name)
 		configuration := structs.Map(a.config.OrbAgent.Otel)
 		configuration["agent_tags"] = a.config.OrbAgent.Tags
-		if err := be.Configure(a.logger, a.policyManager.GetRepo(), configurationEntry, configuration); err != nil {
+		configuration["spool"] = a.spool
+		if err := be.Configure(a.logger.Named(name), a.policyManager.GetRepo(), configurationEntry, configuration); err != nil {
 			a.logger.Info("failed to configure backend", zap.String("backend", name), zap.Error(err))
 			return err
 		}
@@ -147,6 +183,10 @@ func (a *orbAgent) startBackends(agentCtx context.Context) error {
 			}
 			return err
 		}
+		// re-apply the last known policies for this backend, without waiting for the control plane
+		if err := a.policyManager.ApplyBackendPolicies(name, be); err != nil {
+			a.logger.Error("failed to apply stored policies", zap.String("backend", name), zap.Error(err))
+		}
 	}
 	return nil
 }
@@ -185,6 +225,10 @@ func (a *orbAgent) Start(ctx context.Context, cancelFunc context.CancelFunc) err
 		return err
 	}
 
+	if a.logShipper != nil {
+		go a.sendLogs(context.WithValue(agentCtx, "routine", "logs"))
+	}
+
 	if err := a.startBackends(ctx); err != nil {
 		return err
 	}
@@ -195,9 +239,12 @@ func (a *orbAgent) Start(ctx context.Context, cancelFunc context.CancelFunc) err
 }
 
 func (a *orbAgent) logonWithHeartbeat() {
-	a.hbTicker = time.NewTicker(HeartbeatFreq)
+	a.hbMu.Lock()
+	a.hbTicker = time.NewTicker(a.heartbeatIntervalLocked())
+	ticker := a.hbTicker
+	a.hbMu.Unlock()
 	a.heartbeatCtx, a.heartbeatCancel = a.extendContext("heartbeat")
-	go a.sendHeartbeats(a.heartbeatCtx, a.heartbeatCancel)
+	go a.sendHeartbeats(a.heartbeatCtx, a.heartbeatCancel, ticker)
 	a.logger.Info("heartbeat routine started")
 }
 
@@ -240,23 +287,29 @@ func (a *orbAgent) Stop(ctx context.Context) {
 	defer a.cancelFunction()
 }
 
-func (a *orbAgent) RestartBackend(ctx context.Context, name string, reason string) error {
+// RestartBackend restarts a single backend, leaving the other ones running. When reapplyPolicies is set the
+// backend policies are dropped and requested again from the control plane, otherwise the stored ones are re-applied.
+func (a *orbAgent) RestartBackend(ctx context.Context, name string, reason string, reapplyPolicies bool) error {
 	if !backend.HaveBackend(name) {
 		return errors.New("specified backend does not exist: " + name)
 	}
 
-	be := a.backends[name]
+	be, ok := a.backends[name]
+	if !ok {
+		return errors.New("specified backend is not running on this agent: " + name)
+	}
 	a.logger.Info("restarting backend", zap.String("backend", name), zap.String("reason", reason))
 	a.backendState[name].RestartCount += 1
 	a.backendState[name].LastRestartTS = time.Now()
 	a.backendState[name].LastRestartReason = reason
 	a.logger.Info("removing policies", zap.String("backend", name))
-	if err := a.policyManager.RemoveBackendPolicies(be, true); err != nil {
+	if err := a.policyManager.RemoveBackendPolicies(name, be, reapplyPolicies); err != nil {
 		a.logger.Error("failed to remove policies", zap.String("backend", name), zap.Error(err))
 	}
 	configuration := structs.Map(a.config.OrbAgent.Otel)
 	configuration["agent_tags"] = a.config.OrbAgent.Tags
-	if err := be.Configure(a.logger, a.policyManager.GetRepo(), a.config.OrbAgent.Backends[name], configuration); err != nil {
+	configuration["spool"] = a.spool
+	if err := be.Configure(a.logger.Named(name), a.policyManager.GetRepo(), a.config.OrbAgent.Backends[name], configuration); err != nil {
 		return err
 	}
 	a.logger.Info("resetting backend", zap.String("backend", name))
@@ -267,6 +320,12 @@ func (a *orbAgent) RestartBackend(ctx context.Context, name string, reason strin
 	}
 	be.SetCommsClient(a.agent_id, &a.client, fmt.Sprintf("%s/?/%s", a.baseTopic, name))
 
+	if !reapplyPolicies {
+		if err := a.policyManager.ApplyBackendPolicies(name, be); err != nil {
+			a.logger.Error("failed to apply stored policies", zap.String("backend", name), zap.Error(err))
+		}
+		return nil
+	}
 	if err := a.sendAgentPoliciesReq(); err != nil {
 		a.logger.Error("failed to send agent policies request", zap.Error(err))
 	}
@@ -305,7 +364,7 @@ func (a *orbAgent) RestartAll(ctx context.Context, reason string) error {
 	}
 	for name := range a.backends {
 		a.logger.Info("restarting backend", zap.String("backend", name), zap.String("reason", reason))
-		err := a.RestartBackend(ctx, name, reason)
+		err := a.RestartBackend(ctx, name, reason, true)
 		if err != nil {
 			a.logger.Error("failed to restart backend", zap.Error(err))
 		}
@@ -71,6 +71,12 @@ type Backend interface {
 	RemovePolicy(data policies.PolicyData) error
 }
 
+// MetricsSnapshotter is implemented by backends able to report the metrics a policy is seeing right now,
+// the format tells how the returned metrics are encoded
+type MetricsSnapshotter interface {
+	PolicyMetrics(ctx context.Context, data policies.PolicyData) (format string, metrics []byte, err error)
+}
+
 var registry = make(map[string]Backend)
 
 func Register(name string, b Backend) {
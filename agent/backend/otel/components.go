@@ -0,0 +1,83 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-cmd/cmd"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// componentKinds are the otelcol-contrib components reported in the capabilities, so policies
+// can be validated against what the collector binary was built with
+var componentKinds = []string{"receivers", "processors", "extensions"}
+
+// components lists the components built into the otelcol-contrib binary, the result is cached
+// since it only changes with the binary
+func (o *openTelemetryBackend) components() (map[string][]string, error) {
+	if o.otelComponents != nil {
+		return o.otelComponents, nil
+	}
+	parent := o.mainContext
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, 60*time.Second)
+	defer cancel()
+
+	command := cmd.NewCmd(o.otelExecutablePath, "components")
+	status := command.Start()
+	select {
+	case finalStatus := <-status:
+		if finalStatus.Error != nil {
+			o.logger.Error("error during call of otelcol-contrib components", zap.Error(finalStatus.Error))
+			return nil, finalStatus.Error
+		}
+		components, err := parseComponents(finalStatus.Stdout)
+		if err != nil {
+			o.logger.Error("failed to parse otelcol-contrib components", zap.Error(err))
+			return nil, err
+		}
+		o.otelComponents = components
+		return components, nil
+	case <-ctx.Done():
+		_ = command.Stop()
+		o.logger.Error("timeout during getting components", zap.Error(ctx.Err()))
+		return nil, ctx.Err()
+	}
+}
+
+// parseComponents reads the output of "otelcol-contrib components", older collectors list the component
+// names while newer ones list objects with the name and the stability of each signal
+func parseComponents(output []string) (map[string][]string, error) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal([]byte(strings.Join(output, "\n")), &doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, errors.New("empty components output")
+	}
+
+	components := make(map[string][]string, len(componentKinds))
+	for _, kind := range componentKinds {
+		list, _ := doc[kind].([]interface{})
+		names := make([]string, 0, len(list))
+		for _, item := range list {
+			switch v := item.(type) {
+			case string:
+				names = append(names, v)
+			case map[string]interface{}:
+				if name, ok := v["name"].(string); ok {
+					names = append(names, name)
+				}
+			}
+		}
+		sort.Strings(names)
+		components[kind] = names
+	}
+	return components, nil
+}
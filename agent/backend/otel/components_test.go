@@ -0,0 +1,75 @@
+package otel
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseComponents(t *testing.T) {
+	cases := map[string]struct {
+		output   string
+		expected map[string][]string
+		wantErr  bool
+	}{
+		"names with stability": {
+			output: `buildinfo:
+    command: otelcol-contrib
+    description: OpenTelemetry Collector Contrib
+    version: 0.91.0
+receivers:
+    - name: otlp
+      stability:
+        logs: Beta
+        metrics: Stable
+    - name: hostmetrics
+      stability:
+        metrics: Beta
+processors:
+    - name: batch
+      stability:
+        metrics: Beta
+exporters:
+    - name: otlp
+      stability:
+        metrics: Stable`,
+			expected: map[string][]string{
+				"receivers":  {"hostmetrics", "otlp"},
+				"processors": {"batch"},
+				"extensions": {},
+			},
+		},
+		"plain names": {
+			output: `receivers:
+    - prometheus
+    - otlp
+processors:
+    - filter
+extensions:
+    - health_check`,
+			expected: map[string][]string{
+				"receivers":  {"otlp", "prometheus"},
+				"processors": {"filter"},
+				"extensions": {"health_check"},
+			},
+		},
+		"empty output": {
+			output:  "",
+			wantErr: true,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			components, err := parseComponents(strings.Split(tc.output, "\n"))
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, components)
+		})
+	}
+}
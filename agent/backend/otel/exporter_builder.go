@@ -99,13 +99,18 @@ func (e *exporterBuilder) MergeDefaultValueWithPolicy(config openTelemetryConfig
 	if config.Processors == nil {
 		config.Processors = make(map[string]interface{})
 	}
+	policyStatements := []string{
+		`set(attributes["policy_id"], "` + policyId + `")`,
+		`set(attributes["policy_name"], "` + policyName + `")`,
+	}
 	config.Processors["transform/policy_data"] = map[string]interface{}{
 		"metric_statements": map[string]interface{}{
-			"context": "scope",
-			"statements": []string{
-				`set(attributes["policy_id"], "` + policyId + `")`,
-				`set(attributes["policy_name"], "` + policyName + `")`,
-			},
+			"context":    "scope",
+			"statements": policyStatements,
+		},
+		"trace_statements": map[string]interface{}{
+			"context":    "scope",
+			"statements": policyStatements,
 		},
 	}
 	if config.Extensions == nil {
@@ -59,9 +59,15 @@ service:
 			if err != nil {
 				t.Errorf("failed to merge default value with policy: %v", err)
 			}
-			if _, ok := expectedStruct.Processors["transform/policy_data"]; !ok {
+			processor, ok := expectedStruct.Processors["transform/policy_data"]
+			if !ok {
 				t.Error("missing required attributes/policy_data processor", err)
 			}
+			for _, statements := range []string{"metric_statements", "trace_statements"} {
+				if _, ok := processor.(map[string]interface{})[statements]; !ok {
+					t.Errorf("missing %s on attributes/policy_data processor", statements)
+				}
+			}
 
 		})
 	}
@@ -21,6 +21,7 @@ import (
 )
 
 var _ backend.Backend = (*openTelemetryBackend)(nil)
+var _ backend.MetricsSnapshotter = (*openTelemetryBackend)(nil)
 
 const DefaultPath = "/usr/local/bin/otelcol-contrib"
 const DefaultHost = "localhost"
@@ -47,19 +48,22 @@ type openTelemetryBackend struct {
 	otlpMetricsTopic string
 	otlpTracesTopic  string
 	otlpLogsTopic    string
+	spool            *otlpmqttexporter.Spool
 	otelReceiverTaps []string
 	otelCurrVersion  string
+	otelComponents   map[string][]string
 
 	otelReceiverHost   string
 	otelReceiverPort   int
 	otelExecutablePath string
 
-	metricsReceiver receiver.Metrics
-	metricsExporter exporter.Metrics
-	tracesReceiver  receiver.Traces
-	tracesExporter  exporter.Traces
-	logsReceiver    receiver.Logs
-	logsExporter    exporter.Logs
+	otlpReceiverConfig component.Config
+	metricsReceiver    receiver.Metrics
+	metricsExporter    exporter.Metrics
+	tracesReceiver     receiver.Traces
+	tracesExporter     exporter.Traces
+	logsReceiver       receiver.Logs
+	logsExporter       exporter.Logs
 }
 
 // Configure initializes the backend with the given configuration
@@ -83,6 +87,9 @@ func (o *openTelemetryBackend) Configure(logger *zap.Logger, repo policies.Polic
 	if agentTags, ok := otelConfig["agent_tags"]; ok {
 		o.agentTags = agentTags.(map[string]string)
 	}
+	if spool, ok := otelConfig["spool"]; ok {
+		o.spool = spool.(*otlpmqttexporter.Spool)
+	}
 	if otelPort, ok := config["otlp_port"]; ok {
 		o.otelReceiverPort, err = strconv.Atoi(otelPort)
 		if err != nil {
@@ -151,20 +158,6 @@ func (o *openTelemetryBackend) Start(ctx context.Context, cancelFunc context.Can
 	}
 	o.receiveOtlp()
 	o.logger.Info("starting open-telemetry backend using version", zap.String("version", currentVersion))
-	policiesData, err := o.policyRepo.GetAll()
-	if err != nil {
-		cancelFunc()
-		o.logger.Error("failed to start otel backend, policies are absent")
-		return err
-	}
-	for _, policyData := range policiesData {
-		if err := o.ApplyPolicy(policyData, true); err != nil {
-			o.logger.Error("failed to start otel backend, failed to apply policy", zap.Error(err))
-			cancelFunc()
-			return err
-		}
-		o.logger.Info("policy applied successfully", zap.String("policy_id", policyData.ID))
-	}
 
 	return nil
 }
@@ -197,10 +190,19 @@ func (o *openTelemetryBackend) GetStartTime() time.Time {
 	return o.startTime
 }
 
-// GetCapabilities this will only print a default backend config
+// GetCapabilities prints a default backend config and the components available on the collector
 func (o *openTelemetryBackend) GetCapabilities() (capabilities map[string]interface{}, err error) {
 	capabilities = make(map[string]interface{})
 	capabilities["taps"] = o.otelReceiverTaps
+	components, err := o.components()
+	if err != nil {
+		// components are only used to validate policies, the agent still works without them
+		o.logger.Warn("failed to retrieve otelcol-contrib components, they will not be reported", zap.Error(err))
+		return capabilities, nil
+	}
+	for kind, names := range components {
+		capabilities[kind] = names
+	}
 	return
 }
 
@@ -222,6 +224,7 @@ func (o *openTelemetryBackend) createOtlpMetricMqttExporter(ctx context.Context,
 		cfg = otlpmqttexporter.CreateConfig(o.mqttConfig.Address, o.mqttConfig.Id, o.mqttConfig.Key,
 			o.mqttConfig.ChannelID, "", o.otlpMetricsTopic, bridgeService)
 	}
+	cfg.(*otlpmqttexporter.Config).Spool = o.spool
 
 	set := otlpmqttexporter.CreateDefaultSettings(o.logger)
 	// Create the OTLP metrics exporter that'll receive and verify the metrics produced.
@@ -233,6 +236,7 @@ func (o *openTelemetryBackend) createOtlpTraceMqttExporter(ctx context.Context,
 	bridgeService := otel.NewBridgeService(ctx, cancelFunc, &o.policyRepo, o.agentTags)
 	if o.mqttClient != nil {
 		cfg := otlpmqttexporter.CreateConfigClient(o.mqttClient, o.otlpTracesTopic, "", bridgeService)
+		cfg.(*otlpmqttexporter.Config).Spool = o.spool
 		set := otlpmqttexporter.CreateDefaultSettings(o.logger)
 		// Create the OTLP metrics metricsExporter that'll receive and verify the metrics produced.
 		tracerExporter, err := otlpmqttexporter.CreateTracesExporter(ctx, set, cfg)
@@ -243,6 +247,7 @@ func (o *openTelemetryBackend) createOtlpTraceMqttExporter(ctx context.Context,
 	} else {
 		cfg := otlpmqttexporter.CreateConfig(o.mqttConfig.Address, o.mqttConfig.Id, o.mqttConfig.Key,
 			o.mqttConfig.ChannelID, "", o.otlpTracesTopic, bridgeService)
+		cfg.(*otlpmqttexporter.Config).Spool = o.spool
 		set := otlpmqttexporter.CreateDefaultSettings(o.logger)
 		// Create the OTLP metrics exporter that'll receive and verify the metrics produced.
 		tracerExporter, err := otlpmqttexporter.CreateTracesExporter(ctx, set, cfg)
@@ -258,6 +263,7 @@ func (o *openTelemetryBackend) createOtlpLogsMqttExporter(ctx context.Context, c
 	bridgeService := otel.NewBridgeService(ctx, cancelFunc, &o.policyRepo, o.agentTags)
 	if o.mqttClient != nil {
 		cfg := otlpmqttexporter.CreateConfigClient(o.mqttClient, o.otlpLogsTopic, "", bridgeService)
+		cfg.(*otlpmqttexporter.Config).Spool = o.spool
 		set := otlpmqttexporter.CreateDefaultSettings(o.logger)
 		// Create the OTLP metrics metricsExporter that'll receive and verify the metrics produced.
 		exporter, err := otlpmqttexporter.CreateLogsExporter(ctx, set, cfg)
@@ -268,6 +274,7 @@ func (o *openTelemetryBackend) createOtlpLogsMqttExporter(ctx context.Context, c
 	} else {
 		cfg := otlpmqttexporter.CreateConfig(o.mqttConfig.Address, o.mqttConfig.Id, o.mqttConfig.Key,
 			o.mqttConfig.ChannelID, "", o.otlpLogsTopic, bridgeService)
+		cfg.(*otlpmqttexporter.Config).Spool = o.spool
 		set := otlpmqttexporter.CreateDefaultSettings(o.logger)
 		// Create the OTLP metrics exporter that'll receive and verify the metrics produced.
 		exporter, err := otlpmqttexporter.CreateLogsExporter(ctx, set, cfg)
@@ -4,7 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/go-cmd/cmd"
 	"github.com/orb-community/orb/agent/policies"
@@ -13,7 +17,10 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-const tempFileNamePattern = "otel-%s-config.yml"
+const (
+	tempFileNamePattern = "otel-%s-config.yml"
+	snapshotTimeout     = 5 * time.Second
+)
 
 type runningPolicy struct {
 	ctx           context.Context
@@ -43,6 +50,14 @@ func (o *openTelemetryBackend) ApplyPolicy(newPolicyData policies.PolicyData, up
 	if err != nil {
 		return err
 	}
+	// the collector exposes its own metrics on a local port, so the policy metrics can be snapshot on demand
+	telemetryPort, err := getFreeLocalPort()
+	if err != nil {
+		return err
+	}
+	if otelConfig.Service != nil {
+		otelConfig.Service.Telemetry.Metrics = &metrics{Level: "basic", Address: fmt.Sprintf("localhost:%d", telemetryPort)}
+	}
 	newPolicyYaml, err := yaml.Marshal(otelConfig)
 	if err != nil {
 		return err
@@ -56,7 +71,7 @@ func (o *openTelemetryBackend) ApplyPolicy(newPolicyData policies.PolicyData, up
 		if err := os.WriteFile(newPolicyPath, newPolicyYaml, os.ModeTemporary); err != nil {
 			return err
 		}
-		if err = o.addRunner(newPolicyData, newPolicyPath); err != nil {
+		if err = o.addRunner(newPolicyData, newPolicyPath, telemetryPort); err != nil {
 			return err
 		}
 	} else {
@@ -76,7 +91,7 @@ func (o *openTelemetryBackend) ApplyPolicy(newPolicyData policies.PolicyData, up
 			if err := os.WriteFile(currentPolicyPath, newPolicyYaml, os.ModeTemporary); err != nil {
 				return err
 			}
-			if err := o.addRunner(newPolicyData, currentPolicyPath); err != nil {
+			if err := o.addRunner(newPolicyData, currentPolicyPath, telemetryPort); err != nil {
 				return err
 			}
 			if err := o.policyRepo.Update(newPolicyData); err != nil {
@@ -93,7 +108,7 @@ func (o *openTelemetryBackend) ApplyPolicy(newPolicyData policies.PolicyData, up
 	return nil
 }
 
-func (o *openTelemetryBackend) addRunner(policyData policies.PolicyData, policyFilePath string) error {
+func (o *openTelemetryBackend) addRunner(policyData policies.PolicyData, policyFilePath string, telemetryPort int) error {
 	policyContext, policyCancel := context.WithCancel(context.WithValue(o.mainContext, "policy_id", policyData.ID))
 	command := cmd.NewCmdOptions(cmd.Options{Buffered: false, Streaming: true}, o.otelExecutablePath, "--config", policyFilePath)
 	go func(ctx context.Context, logger *zap.Logger) {
@@ -124,11 +139,12 @@ func (o *openTelemetryBackend) addRunner(policyData policies.PolicyData, policyF
 	}(policyContext, o.logger)
 	status := command.Status()
 	policyEntry := runningPolicy{
-		ctx:        policyContext,
-		cancel:     policyCancel,
-		policyId:   policyData.ID,
-		policyData: policyData,
-		statusChan: &status,
+		ctx:           policyContext,
+		cancel:        policyCancel,
+		policyId:      policyData.ID,
+		telemetryPort: telemetryPort,
+		policyData:    policyData,
+		statusChan:    &status,
 	}
 	o.addPolicyControl(policyEntry, policyData.ID)
 
@@ -175,3 +191,39 @@ func (o *openTelemetryBackend) ValidatePolicy(otelConfig openTelemetryConfig) er
 
 	return nil
 }
+
+// PolicyMetrics retrieves the internal metrics of the collector running the policy, in prometheus text format
+func (o *openTelemetryBackend) PolicyMetrics(ctx context.Context, data policies.PolicyData) (string, []byte, error) {
+	policy, ok := o.runningCollectors[data.ID]
+	if !ok || policy.telemetryPort == 0 {
+		return "", nil, errors.New("no running collector for policy " + data.ID)
+	}
+	ctx, cancel := context.WithTimeout(ctx, snapshotTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://localhost:%d/metrics", policy.telemetryPort), http.NoBody)
+	if err != nil {
+		return "", nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("collector metrics endpoint answered with status %d", res.StatusCode)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", nil, err
+	}
+	return "prometheus", body, nil
+}
+
+func getFreeLocalPort() (int, error) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
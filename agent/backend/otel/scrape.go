@@ -26,9 +26,10 @@ func (o *openTelemetryBackend) receiveOtlp() {
 					o.logger.Error("failed to start otel metric")
 					return
 				}
-				//if o.startOtelTraces(exeCtx, execCancelF) {
-				//	return
-				//}
+				if ok := o.startOtelTraces(exeCtx, execCancelF); !ok {
+					o.logger.Error("failed to start otel traces")
+					return
+				}
 				//if ok := o.startOtelLogs(exeCtx, execCancelF); !ok {
 				//	return
 				//}
@@ -68,7 +69,45 @@ func (o *openTelemetryBackend) startOtelMetric(exeCtx context.Context, execCance
 		return false
 	}
 	pFactory := otlpreceiver.NewFactory()
-	cfg := pFactory.CreateDefaultConfig()
+	cfg := o.getOtlpReceiverConfig(pFactory)
+	set := receiver.CreateSettings{
+		TelemetrySettings: component.TelemetrySettings{
+			Logger:         o.logger,
+			TracerProvider: noop.NewTracerProvider(),
+			MeterProvider:  metric.NewMeterProvider(),
+			ReportComponentStatus: func(*component.StatusEvent) error {
+				return nil
+			},
+		},
+		BuildInfo: component.NewDefaultBuildInfo(),
+	}
+	o.metricsReceiver, err = pFactory.CreateMetricsReceiver(exeCtx, set, cfg, o.metricsExporter)
+	if err != nil {
+		o.logger.Error("failed to create a receiver", zap.Error(err))
+		return false
+	}
+	err = o.metricsExporter.Start(exeCtx, nil)
+	if err != nil {
+		o.logger.Error("otel mqtt exporter startup error", zap.Error(err))
+		return false
+	}
+	o.logger.Info("Started receiver for OTLP in orb-agent",
+		zap.String("host", o.otelReceiverHost), zap.Int("port", o.otelReceiverPort))
+	err = o.metricsReceiver.Start(exeCtx, nil)
+	if err != nil {
+		o.logger.Error("otel receiver startup error", zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// getOtlpReceiverConfig returns the configuration of the agent OTLP receiver. The same configuration must be used
+// for every signal, so the otlp receiver factory shares a single gRPC server between the metrics and traces pipelines.
+func (o *openTelemetryBackend) getOtlpReceiverConfig(factory receiver.Factory) component.Config {
+	if o.otlpReceiverConfig != nil {
+		return o.otlpReceiverConfig
+	}
+	cfg := factory.CreateDefaultConfig()
 	cfg.(*otlpreceiver.Config).Protocols = otlpreceiver.Protocols{
 		GRPC: &configgrpc.GRPCServerSettings{
 			NetAddr: confignet.NetAddr{
@@ -77,6 +116,19 @@ func (o *openTelemetryBackend) startOtelMetric(exeCtx context.Context, execCance
 			},
 		},
 	}
+	o.otlpReceiverConfig = cfg
+	return cfg
+}
+
+func (o *openTelemetryBackend) startOtelTraces(exeCtx context.Context, execCancelF context.CancelCauseFunc) bool {
+	var err error
+	o.tracesExporter, err = o.createOtlpTraceMqttExporter(exeCtx, execCancelF)
+	if err != nil {
+		o.logger.Error("failed to create a traces exporter", zap.Error(err))
+		return false
+	}
+	pFactory := otlpreceiver.NewFactory()
+	cfg := o.getOtlpReceiverConfig(pFactory)
 	set := receiver.CreateSettings{
 		TelemetrySettings: component.TelemetrySettings{
 			Logger:         o.logger,
@@ -88,78 +140,27 @@ func (o *openTelemetryBackend) startOtelMetric(exeCtx context.Context, execCance
 		},
 		BuildInfo: component.NewDefaultBuildInfo(),
 	}
-	o.metricsReceiver, err = pFactory.CreateMetricsReceiver(exeCtx, set, cfg, o.metricsExporter)
+	o.tracesReceiver, err = pFactory.CreateTracesReceiver(exeCtx, set, cfg, o.tracesExporter)
 	if err != nil {
-		o.logger.Error("failed to create a receiver", zap.Error(err))
+		o.logger.Error("failed to create a traces receiver", zap.Error(err))
 		return false
 	}
-	err = o.metricsExporter.Start(exeCtx, nil)
+	err = o.tracesExporter.Start(exeCtx, nil)
 	if err != nil {
-		o.logger.Error("otel mqtt exporter startup error", zap.Error(err))
+		o.logger.Error("otel mqtt traces exporter startup error", zap.Error(err))
 		return false
 	}
-	o.logger.Info("Started receiver for OTLP in orb-agent",
+	o.logger.Info("Started traces receiver for OTLP in orb-agent",
 		zap.String("host", o.otelReceiverHost), zap.Int("port", o.otelReceiverPort))
-	err = o.metricsReceiver.Start(exeCtx, nil)
+	// the gRPC server is shared with the metrics receiver, starting it again is a no-op
+	err = o.tracesReceiver.Start(exeCtx, nil)
 	if err != nil {
-		o.logger.Error("otel receiver startup error", zap.Error(err))
+		o.logger.Error("otel traces receiver startup error", zap.Error(err))
 		return false
 	}
 	return true
 }
 
-// TODO fix when create otlpmqtt trace
-//func (o *openTelemetryBackend) startOtelTraces(exeCtx context.Context, execCancelF context.CancelFunc) bool {
-//	if o.tracesExporter != nil {
-//		return true
-//	}
-//	var err error
-//	o.tracesExporter, err = o.createOtlpTraceMqttExporter(exeCtx, execCancelF)
-//	if err != nil {
-//		o.logger.Error("failed to create a exporter", zap.Error(err))
-//		return true
-//	}
-//	pFactory := otlpreceiver.NewFactory()
-//	cfg := pFactory.CreateDefaultConfig()
-//	cfg.(*otlpreceiver.Config).Protocols = otlpreceiver.Protocols{
-//		GRPC: &configgrpc.GRPCServerSettings{
-//			NetAddr: confignet.NetAddr{
-//				Endpoint:  o.otelReceiverHost + ":" + strconv.Itoa(o.otelReceiverPort),
-//				Transport: "tcp",
-//			},
-//		},
-//	}
-//	set := receiver.CreateSettings{
-//		TelemetrySettings: component.TelemetrySettings{
-//			Logger:         o.logger,
-//			TracerProvider: trace.NewNoopTracerProvider(),
-//			MeterProvider:  metric.NewMeterProvider(),
-//			ReportComponentStatus: func(*component.StatusEvent) error {
-//				return nil
-//			},
-//		},
-//		BuildInfo: component.NewDefaultBuildInfo(),
-//	}
-//	o.tracesReceiver, err = pFactory.CreateTracesReceiver(exeCtx, set, cfg, o.tracesExporter)
-//	if err != nil {
-//		o.logger.Error("failed to create a receiver", zap.Error(err))
-//		return true
-//	}
-//	err = o.metricsExporter.Start(exeCtx, nil)
-//	if err != nil {
-//		o.logger.Error("otel mqtt exporter startup error", zap.Error(err))
-//		return true
-//	}
-//	o.logger.Info("Started receiver for OTLP in orb-agent",
-//		zap.String("host", o.otelReceiverHost), zap.Int("port", o.otelReceiverPort))
-//	err = o.metricsReceiver.Start(exeCtx, nil)
-//	if err != nil {
-//		o.logger.Error("otel receiver startup error", zap.Error(err))
-//		return true
-//	}
-//	return false
-//}
-//
 //func (o *openTelemetryBackend) startOtelLogs(exeCtx context.Context, execCancelF context.CancelFunc) bool {
 //	if o.logsExporter != nil {
 //		return true
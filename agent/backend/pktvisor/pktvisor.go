@@ -19,6 +19,7 @@ import (
 	"github.com/go-cmd/cmd"
 	"github.com/orb-community/orb/agent/backend"
 	"github.com/orb-community/orb/agent/config"
+	"github.com/orb-community/orb/agent/otel/otlpmqttexporter"
 	"github.com/orb-community/orb/agent/policies"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/receiver"
@@ -26,6 +27,7 @@ import (
 )
 
 var _ backend.Backend = (*pktvisorBackend)(nil)
+var _ backend.MetricsSnapshotter = (*pktvisorBackend)(nil)
 
 const (
 	DefaultBinary       = "/usr/local/sbin/pktvisord"
@@ -66,6 +68,7 @@ type pktvisorBackend struct {
 	mqttClient       *mqtt.Client
 	metricsTopic     string
 	otlpMetricsTopic string
+	spool            *otlpmqttexporter.Spool
 	policyRepo       policies.PolicyRepo
 
 	adminAPIHost     string
@@ -295,6 +298,9 @@ func (p *pktvisorBackend) Configure(logger *zap.Logger, repo policies.PolicyRepo
 	if agentTags, ok := otelConfig["agent_tags"]; ok {
 		p.agentTags = agentTags.(map[string]string)
 	}
+	if spool, ok := otelConfig["spool"]; ok {
+		p.spool = spool.(*otlpmqttexporter.Spool)
+	}
 
 	for k, v := range otelConfig {
 		switch k {
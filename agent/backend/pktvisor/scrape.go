@@ -6,6 +6,7 @@ package pktvisor
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"go.opentelemetry.io/otel/trace/noop"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/orb-community/orb/agent/otel"
 	"github.com/orb-community/orb/agent/otel/otlpmqttexporter"
+	"github.com/orb-community/orb/agent/policies"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/exporter"
@@ -33,6 +35,20 @@ func (p *pktvisorBackend) scrapeMetrics(period uint) (map[string]interface{}, er
 	return metrics, nil
 }
 
+// PolicyMetrics retrieves the metrics of the current bucket of the policy from the pktvisor REST API
+func (p *pktvisorBackend) PolicyMetrics(_ context.Context, data policies.PolicyData) (string, []byte, error) {
+	var metrics map[string]interface{}
+	err := p.request(fmt.Sprintf("policies/%s/metrics/bucket/0", data.Name), &metrics, http.MethodGet, http.NoBody, "application/json", ScrapeTimeout)
+	if err != nil {
+		return "", nil, err
+	}
+	body, err := json.Marshal(metrics)
+	if err != nil {
+		return "", nil, err
+	}
+	return "json", body, nil
+}
+
 func (p *pktvisorBackend) createOtlpMqttExporter(ctx context.Context, cancelFunc context.CancelCauseFunc) (exporter.Metrics, error) {
 	bridgeService := otel.NewBridgeService(ctx, cancelFunc, &p.policyRepo, p.agentTags)
 	var cfg component.Config
@@ -42,6 +58,7 @@ func (p *pktvisorBackend) createOtlpMqttExporter(ctx context.Context, cancelFunc
 		cfg = otlpmqttexporter.CreateConfig(p.mqttConfig.Address, p.mqttConfig.Id, p.mqttConfig.Key,
 			p.mqttConfig.ChannelID, p.pktvisorVersion, p.otlpMetricsTopic, bridgeService)
 	}
+	cfg.(*otlpmqttexporter.Config).Spool = p.spool
 
 	set := otlpmqttexporter.CreateDefaultSettings(p.logger)
 	// Create the OTLP metrics exporter that'll receive and verify the metrics produced.
@@ -22,8 +22,21 @@ import (
 	"time"
 )
 
+const (
+	// agentsPath provisions an agent with a user API token
+	agentsPath = "/api/v1/agents"
+	// enrollPath provisions an agent with an enrollment token, which is only accepted there
+	enrollPath = "/api/v1/agents/enroll"
+)
+
 type CloudConfigManager interface {
 	GetCloudConfig() (config.MQTTConfig, error)
+	// SavePendingKey persists a rotated key next to the current key of the agent until it is committed or dropped
+	SavePendingKey(mqtt config.MQTTConfig, key string) error
+	// CommitPendingKey replaces the current key of the agent with its pending key
+	CommitPendingKey(id string) error
+	// DropPendingKey discards the pending key of the agent, keeping its current key
+	DropPendingKey(id string) error
 }
 
 var _ CloudConfigManager = (*cloudConfigManager)(nil)
@@ -56,6 +69,15 @@ func (cc *cloudConfigManager) migrateDB() error {
 					"DROP TABLE cloud_config",
 				},
 			},
+			{
+				Id: "cloud_config_2",
+				Up: []string{
+					`ALTER TABLE cloud_config ADD COLUMN pending_key TEXT NOT NULL DEFAULT ''`,
+				},
+				Down: []string{
+					`ALTER TABLE cloud_config DROP COLUMN pending_key`,
+				},
+			},
 		},
 	}
 
@@ -64,7 +86,7 @@ func (cc *cloudConfigManager) migrateDB() error {
 	return err
 }
 
-func (cc *cloudConfigManager) request(address string, token string, response interface{}, method string, body []byte) error {
+func (cc *cloudConfigManager) request(address string, path string, token string, response interface{}, method string, body []byte) error {
 	tlsConfig := &tls.Config{InsecureSkipVerify: false}
 	if !cc.config.OrbAgent.TLS.Verify {
 		tlsConfig.InsecureSkipVerify = true
@@ -74,7 +96,7 @@ func (cc *cloudConfigManager) request(address string, token string, response int
 		Timeout:   time.Second * 10,
 		Transport: transport,
 	}
-	URL := fmt.Sprintf("%s/api/v1/agents", address)
+	URL := fmt.Sprintf("%s%s", address, path)
 
 	req, err := http.NewRequest(method, URL, bytes.NewBuffer(body))
 	if err != nil {
@@ -112,7 +134,7 @@ func (cc *cloudConfigManager) request(address string, token string, response int
 	return nil
 }
 
-func (cc *cloudConfigManager) autoProvision(apiAddress string, token string) (config.MQTTConfig, error) {
+func (cc *cloudConfigManager) autoProvision(apiAddress string, path string, token string) (config.MQTTConfig, error) {
 
 	type AgentRes struct {
 		ID        string `json:"id"`
@@ -140,17 +162,17 @@ func (cc *cloudConfigManager) autoProvision(apiAddress string, token string) (co
 		return config.MQTTConfig{}, err
 	}
 
-	cc.logger.Info("attempting auto provision", zap.String("address", apiAddress))
+	cc.logger.Info("attempting auto provision", zap.String("address", apiAddress), zap.String("path", path))
 
 	var result AgentRes
-	err = cc.request(apiAddress, token, &result, http.MethodPost, body)
+	err = cc.request(apiAddress, path, token, &result, http.MethodPost, body)
 	if err != nil {
 		return config.MQTTConfig{}, err
 	}
 
 	// save to local config
 	address := ""
-	_, err = cc.db.Exec(`INSERT INTO cloud_config VALUES ($1, $2, $3, $4, datetime('now'))`, address, result.ID, result.Key, result.ChannelID)
+	_, err = cc.db.Exec(`INSERT INTO cloud_config (address, id, key, channel, ts_created) VALUES ($1, $2, $3, $4, datetime('now'))`, address, result.ID, result.Key, result.ChannelID)
 	if err != nil {
 		return config.MQTTConfig{}, err
 	}
@@ -170,15 +192,29 @@ func (cc *cloudConfigManager) GetCloudConfig() (config.MQTTConfig, error) {
 	mqtt := cc.config.OrbAgent.Cloud.MQTT
 
 	if len(mqtt.Id) > 0 && len(mqtt.Key) > 0 && len(mqtt.ChannelID) > 0 {
-		cc.logger.Info("using explicitly specified cloud configuration",
-			zap.String("address", mqtt.Address),
-			zap.String("id", mqtt.Id))
-		return config.MQTTConfig{
+		result := config.MQTTConfig{
 			Address:   mqtt.Address,
 			Id:        mqtt.Id,
 			Key:       mqtt.Key,
 			ChannelID: mqtt.ChannelID,
-		}, nil
+		}
+		if err := cc.migrateDB(); err != nil {
+			return config.MQTTConfig{}, err
+		}
+		// a key rotated by the control plane supersedes the key specified for the same agent
+		q := `SELECT key, pending_key FROM cloud_config WHERE id = $1 ORDER BY ts_created DESC LIMIT 1`
+		var key string
+		if err := cc.db.QueryRowx(q, mqtt.Id).Scan(&key, &result.PendingKey); err != nil {
+			if err != sql.ErrNoRows {
+				return config.MQTTConfig{}, err
+			}
+		} else if len(key) > 0 {
+			result.Key = key
+		}
+		cc.logger.Info("using explicitly specified cloud configuration",
+			zap.String("address", mqtt.Address),
+			zap.String("id", mqtt.Id))
+		return result, nil
 	}
 
 	// if full config is not available, possibly attempt auto provision configuration
@@ -192,9 +228,9 @@ func (cc *cloudConfigManager) GetCloudConfig() (config.MQTTConfig, error) {
 	}
 
 	// see if we have an existing auto provisioned configuration saved locally
-	q := `SELECT id, key, channel FROM cloud_config ORDER BY ts_created DESC LIMIT 1`
+	q := `SELECT id, key, channel, pending_key FROM cloud_config ORDER BY ts_created DESC LIMIT 1`
 	dba := config.MQTTConfig{}
-	if err := cc.db.QueryRowx(q).Scan(&dba.Id, &dba.Key, &dba.ChannelID); err != nil {
+	if err := cc.db.QueryRowx(q).Scan(&dba.Id, &dba.Key, &dba.ChannelID, &dba.PendingKey); err != nil {
 		if err != sql.ErrNoRows {
 			return config.MQTTConfig{}, err
 		}
@@ -209,11 +245,15 @@ func (cc *cloudConfigManager) GetCloudConfig() (config.MQTTConfig, error) {
 
 	// attempt a live auto provision
 	apiConfig := cc.config.OrbAgent.Cloud.API
-	if len(apiConfig.Token) == 0 {
-		return config.MQTTConfig{}, errors.New("wanted to auto provision, but no API token was available")
+	var result config.MQTTConfig
+	switch {
+	case len(apiConfig.EnrollmentToken) > 0:
+		result, err = cc.autoProvision(apiConfig.Address, enrollPath, apiConfig.EnrollmentToken)
+	case len(apiConfig.Token) > 0:
+		result, err = cc.autoProvision(apiConfig.Address, agentsPath, apiConfig.Token)
+	default:
+		return config.MQTTConfig{}, errors.New("wanted to auto provision, but no enrollment token or API token was available")
 	}
-
-	result, err := cc.autoProvision(apiConfig.Address, apiConfig.Token)
 	if err != nil {
 		return config.MQTTConfig{}, err
 	}
@@ -225,3 +265,50 @@ func (cc *cloudConfigManager) GetCloudConfig() (config.MQTTConfig, error) {
 	return result, nil
 
 }
+
+func (cc *cloudConfigManager) SavePendingKey(mqtt config.MQTTConfig, key string) error {
+	if err := cc.migrateDB(); err != nil {
+		return err
+	}
+	res, err := cc.db.Exec(`UPDATE cloud_config SET pending_key = $1 WHERE id = $2`, key, mqtt.Id)
+	if err != nil {
+		return err
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if cnt > 0 {
+		return nil
+	}
+	// explicitly specified configurations are not stored until their key is first rotated
+	_, err = cc.db.Exec(`INSERT INTO cloud_config (address, id, key, channel, ts_created, pending_key) VALUES ($1, $2, $3, $4, datetime('now'), $5)`,
+		"", mqtt.Id, mqtt.Key, mqtt.ChannelID, key)
+	return err
+}
+
+func (cc *cloudConfigManager) CommitPendingKey(id string) error {
+	if err := cc.migrateDB(); err != nil {
+		return err
+	}
+	res, err := cc.db.Exec(`UPDATE cloud_config SET key = pending_key, pending_key = '' WHERE id = $1 AND pending_key <> ''`, id)
+	if err != nil {
+		return err
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if cnt == 0 {
+		return errors.New("no pending key to commit")
+	}
+	return nil
+}
+
+func (cc *cloudConfigManager) DropPendingKey(id string) error {
+	if err := cc.migrateDB(); err != nil {
+		return err
+	}
+	_, err := cc.db.Exec(`UPDATE cloud_config SET pending_key = '' WHERE id = $1`, id)
+	return err
+}
@@ -12,6 +12,7 @@ import (
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 	"github.com/orb-community/orb/agent/backend"
+	"github.com/orb-community/orb/agent/cloud_config"
 	"github.com/orb-community/orb/agent/config"
 	"github.com/orb-community/orb/fleet"
 	"go.uber.org/zap"
@@ -149,6 +150,22 @@ func (a *orbAgent) requestReconnection(ctx context.Context, client mqtt.Client,
 	if err != nil {
 		a.logger.Error("failed to send group membership request", zap.Error(err))
 	}
+	go a.replaySpool(client)
+}
+
+func (a *orbAgent) replaySpool(client mqtt.Client) {
+	if a.spool == nil || a.spool.Len() == 0 {
+		return
+	}
+	err := a.spool.Replay(func(topic string, payload []byte) error {
+		if token := client.Publish(topic, 1, false, payload); token.Wait() && token.Error() != nil {
+			return token.Error()
+		}
+		return nil
+	})
+	if err != nil {
+		a.logger.Warn("failed to replay spooled telemetry, will retry on next export", zap.Error(err))
+	}
 }
 
 func (a *orbAgent) nameAgentRPCTopics(channelId string) {
@@ -199,6 +216,14 @@ func (a *orbAgent) startComms(ctx context.Context, config config.MQTTConfig) err
 	a.logger.Debug("starting mqtt connection")
 	if a.client == nil || !a.client.IsConnected() {
 		a.client, err = a.connect(ctx, config)
+		if err != nil && config.PendingKey != "" {
+			// the rotated key may have been set on the Thing without the agent receiving its commit
+			a.logger.Warn("connection failed, retrying with pending rotated key", zap.String("agent_id", config.Id), zap.Error(err))
+			config.Key = config.PendingKey
+			if a.client, err = a.connect(ctx, config); err == nil {
+				a.commitPendingKey(config.Id)
+			}
+		}
 		if err != nil {
 			a.logger.Error("connection failed", zap.String("channel", config.ChannelID), zap.String("agent_id", config.Id), zap.Error(err))
 			return ErrMqttConnection
@@ -215,6 +240,16 @@ func (a *orbAgent) startComms(ctx context.Context, config config.MQTTConfig) err
 	return nil
 }
 
+func (a *orbAgent) commitPendingKey(id string) {
+	ccm, err := cloud_config.New(a.logger, a.config, a.db)
+	if err == nil {
+		err = ccm.CommitPendingKey(id)
+	}
+	if err != nil {
+		a.logger.Error("failed to commit pending rotated key", zap.String("agent_id", id), zap.Error(err))
+	}
+}
+
 func (a *orbAgent) subscribeGroupChannels(groups []fleet.GroupMembershipData) {
 	for _, groupData := range groups {
 
@@ -4,13 +4,16 @@
 
 package config
 
+import "time"
+
 type TLS struct {
 	Verify bool `mapstructure:"verify"`
 }
 
 type APIConfig struct {
-	Address string `mapstructure:"address"`
-	Token   string `mapstructure:"token"`
+	Address         string `mapstructure:"address"`
+	Token           string `mapstructure:"token"`
+	EnrollmentToken string `mapstructure:"enrollment_token"`
 }
 
 type DBConfig struct {
@@ -22,6 +25,8 @@ type MQTTConfig struct {
 	Id        string `mapstructure:"id"`
 	Key       string `mapstructure:"key"`
 	ChannelID string `mapstructure:"channel_id"`
+	// PendingKey is a rotated key received from the control plane and not yet confirmed set on the Thing
+	PendingKey string `mapstructure:"-"`
 }
 
 type CloudConfig struct {
@@ -40,6 +45,19 @@ type Opentelemetry struct {
 	Port int    `mapstructure:"port"`
 }
 
+type Spool struct {
+	Enable    bool          `mapstructure:"enable"`
+	Directory string        `mapstructure:"directory"`
+	MaxSizeMB int64         `mapstructure:"max_size_mb"`
+	MaxAge    time.Duration `mapstructure:"max_age"`
+}
+
+type Logs struct {
+	Enable    bool   `mapstructure:"enable"`
+	Level     string `mapstructure:"level"`
+	RateLimit int    `mapstructure:"rate_limit"`
+}
+
 type Debug struct {
 	Enable bool `mapstructure:"enable"`
 }
@@ -51,7 +69,9 @@ type OrbAgent struct {
 	TLS      TLS                          `mapstructure:"tls"`
 	DB       DBConfig                     `mapstructure:"db"`
 	Otel     Opentelemetry                `mapstructure:"otel"`
+	Spool    Spool                        `mapstructure:"spool"`
 	Debug    Debug                        `mapstructure:"debug"`
+	Logs     Logs                         `mapstructure:"logs"`
 }
 
 type Config struct {
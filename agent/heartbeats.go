@@ -15,7 +15,7 @@ import (
 	"time"
 )
 
-// HeartbeatFreq how often to heartbeat
+// HeartbeatFreq how often to heartbeat until the control plane sends a heartbeat configuration
 const HeartbeatFreq = 50 * time.Second
 
 // RestartTimeMin minimum time to wait between restarts
@@ -56,7 +56,7 @@ func (a *orbAgent) sendSingleHeartbeat(ctx context.Context, t time.Time, agentsS
 				} else {
 					ctx = context.WithValue(ctx, "agent_id", "auto-provisioning-without-id")
 				}
-				err := a.RestartBackend(ctx, name, "failed during heartbeat")
+				err := a.RestartBackend(ctx, name, "failed during heartbeat", true)
 				if err != nil {
 					a.logger.Error("failed to restart backend", zap.Error(err), zap.String("backend", name))
 				}
@@ -128,6 +128,17 @@ func (a *orbAgent) sendSingleHeartbeat(ctx context.Context, t time.Time, agentsS
 		GroupState:    ag,
 	}
 
+	if a.spool != nil {
+		stats := a.spool.Stats()
+		hbData.SpoolState = &fleet.SpoolStateInfo{
+			Spooled:      stats.Spooled,
+			Replayed:     stats.Replayed,
+			Dropped:      stats.Dropped,
+			Pending:      stats.Pending,
+			PendingBytes: stats.PendingBytes,
+		}
+	}
+
 	body, err := json.Marshal(hbData)
 	if err != nil {
 		a.logger.Error("error marshalling heartbeat", zap.Error(err))
@@ -144,7 +155,7 @@ func (a *orbAgent) sendSingleHeartbeat(ctx context.Context, t time.Time, agentsS
 	}
 }
 
-func (a *orbAgent) sendHeartbeats(ctx context.Context, cancelFunc context.CancelFunc) {
+func (a *orbAgent) sendHeartbeats(ctx context.Context, cancelFunc context.CancelFunc, ticker *time.Ticker) {
 	a.logger.Debug("start heartbeats routine", zap.Any("routine", ctx.Value("routine")))
 	a.sendSingleHeartbeat(ctx, time.Now(), fleet.Online)
 	defer func() {
@@ -157,8 +168,22 @@ func (a *orbAgent) sendHeartbeats(ctx context.Context, cancelFunc context.Cancel
 			a.sendSingleHeartbeat(ctx, time.Now(), fleet.Offline)
 			a.heartbeatCtx = nil
 			return
-		case t := <-a.hbTicker.C:
+		case t := <-ticker.C:
 			a.sendSingleHeartbeat(ctx, t, fleet.Online)
 		}
 	}
 }
+
+func (a *orbAgent) heartbeatInterval() time.Duration {
+	a.hbMu.Lock()
+	defer a.hbMu.Unlock()
+	return a.heartbeatIntervalLocked()
+}
+
+// heartbeatIntervalLocked is heartbeatInterval for callers already holding hbMu
+func (a *orbAgent) heartbeatIntervalLocked() time.Duration {
+	if a.hbInterval > 0 {
+		return a.hbInterval
+	}
+	return HeartbeatFreq
+}
@@ -0,0 +1,41 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package agent
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/orb-community/orb/fleet"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHandleHeartbeatConfig(t *testing.T) {
+	a := &orbAgent{logger: zap.NewNop(), hbTicker: time.NewTicker(HeartbeatFreq)}
+	defer a.hbTicker.Stop()
+
+	a.handleHeartbeatConfig(fleet.HeartbeatConfigRPCPayload{HeartbeatInterval: 1})
+	require.Equal(t, HeartbeatFreq, a.heartbeatInterval(), "too short interval must be ignored")
+
+	// the RPC goroutine reconfigures the interval while the heartbeat routine reads it
+	var wg sync.WaitGroup
+	for i := int64(0); i < 10; i++ {
+		wg.Add(2)
+		go func(interval int64) {
+			defer wg.Done()
+			a.handleHeartbeatConfig(fleet.HeartbeatConfigRPCPayload{HeartbeatInterval: interval})
+		}(20 + i)
+		go func() {
+			defer wg.Done()
+			a.heartbeatInterval()
+		}()
+	}
+	wg.Wait()
+
+	a.handleHeartbeatConfig(fleet.HeartbeatConfigRPCPayload{HeartbeatInterval: 45})
+	require.Equal(t, 45*time.Second, a.heartbeatInterval())
+}
@@ -0,0 +1,220 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/orb-community/orb/fleet"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// LogFlushFreq how often buffered log entries are shipped to the control plane
+	LogFlushFreq = 5 * time.Second
+	// logBufferSize bounds the entries waiting to be shipped, entries above it are dropped
+	logBufferSize = 1000
+	// logEntryOverhead rough size of the json envelope of an entry, used to keep batches under the payload limit
+	logEntryOverhead = 64
+)
+
+// logShipper collects agent and backend log entries at or above the configured level, keeping at most
+// rateLimit entries per second, so they can be shipped to the control plane over the log topic
+type logShipper struct {
+	level     zapcore.Level
+	rateLimit int
+	entries   chan fleet.AgentLogEntry
+
+	mu          sync.Mutex
+	window      time.Time
+	windowCount int
+	dropped     int
+}
+
+func newLogShipper(level string, rateLimit int) (*logShipper, error) {
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, err
+	}
+	return &logShipper{
+		level:     lvl,
+		rateLimit: rateLimit,
+		entries:   make(chan fleet.AgentLogEntry, logBufferSize),
+	}, nil
+}
+
+// wrapCore tees the given core with the shipper, to be used with zap.WrapCore
+func (s *logShipper) wrapCore(core zapcore.Core) zapcore.Core {
+	return zapcore.NewTee(core, &logShipperCore{shipper: s})
+}
+
+// allow applies the rate limit over fixed one second windows, counting the dropped entries
+func (s *logShipper) allow(t time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t.Sub(s.window) >= time.Second {
+		s.window = t
+		s.windowCount = 0
+	}
+	if s.rateLimit > 0 && s.windowCount >= s.rateLimit {
+		s.dropped++
+		return false
+	}
+	s.windowCount++
+	return true
+}
+
+func (s *logShipper) push(e fleet.AgentLogEntry) {
+	select {
+	case s.entries <- e:
+	default:
+		s.mu.Lock()
+		s.dropped++
+		s.mu.Unlock()
+	}
+}
+
+// drain takes the buffered entries, with a warning entry when some were dropped since the last drain
+func (s *logShipper) drain() []fleet.AgentLogEntry {
+	var entries []fleet.AgentLogEntry
+loop:
+	for {
+		select {
+		case e := <-s.entries:
+			entries = append(entries, e)
+		default:
+			break loop
+		}
+	}
+	s.mu.Lock()
+	dropped := s.dropped
+	s.dropped = 0
+	s.mu.Unlock()
+	if dropped > 0 {
+		entries = append(entries, fleet.AgentLogEntry{
+			TimeStamp: time.Now(),
+			Level:     zapcore.WarnLevel.String(),
+			Source:    "agent",
+			Message:   "log entries dropped by rate limiting or a full buffer",
+			Fields:    map[string]interface{}{"dropped": dropped},
+		})
+	}
+	return entries
+}
+
+// batches splits entries so each published message stays under the payload size fleet accepts
+func batches(entries []fleet.AgentLogEntry) [][]fleet.AgentLogEntry {
+	var result [][]fleet.AgentLogEntry
+	var current []fleet.AgentLogEntry
+	size := 0
+	for _, e := range entries {
+		e.Message = fleet.TruncateAgentLogMessage(e.Message)
+		es := len(e.Message) + len(e.Source) + logEntryOverhead
+		if fs, err := json.Marshal(e.Fields); err == nil {
+			es += len(fs)
+		}
+		if len(current) > 0 && size+es > fleet.MaxMsgPayloadSize/2 {
+			result = append(result, current)
+			current = nil
+			size = 0
+		}
+		current = append(current, e)
+		size += es
+	}
+	if len(current) > 0 {
+		result = append(result, current)
+	}
+	return result
+}
+
+type logShipperCore struct {
+	shipper *logShipper
+	fields  []zapcore.Field
+}
+
+var _ zapcore.Core = (*logShipperCore)(nil)
+
+func (c *logShipperCore) Enabled(lvl zapcore.Level) bool {
+	return lvl >= c.shipper.level
+}
+
+func (c *logShipperCore) With(fields []zapcore.Field) zapcore.Core {
+	f := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	f = append(f, c.fields...)
+	f = append(f, fields...)
+	return &logShipperCore{shipper: c.shipper, fields: f}
+}
+
+func (c *logShipperCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(e.Level) {
+		return ce.AddCore(e, c)
+	}
+	return ce
+}
+
+func (c *logShipperCore) Write(e zapcore.Entry, fields []zapcore.Field) error {
+	if !c.shipper.allow(e.Time) {
+		return nil
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	source := e.LoggerName
+	if source == "" {
+		source = "agent"
+	}
+	entry := fleet.AgentLogEntry{
+		TimeStamp: e.Time,
+		Level:     e.Level.String(),
+		Source:    source,
+		Message:   e.Message,
+	}
+	if len(enc.Fields) > 0 {
+		entry.Fields = enc.Fields
+	}
+	c.shipper.push(entry)
+	return nil
+}
+
+func (c *logShipperCore) Sync() error {
+	return nil
+}
+
+// sendLogs ships the buffered log entries every LogFlushFreq while connected, entries buffered while
+// disconnected are kept up to logBufferSize
+func (a *orbAgent) sendLogs(ctx context.Context) {
+	// errors are logged on the un-teed logger, shipping them would feed back into the buffer
+	logger := a.rawLogger
+	ticker := time.NewTicker(LogFlushFreq)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if a.client == nil || !a.client.IsConnected() || a.logTopic == "" {
+				continue
+			}
+			for _, batch := range batches(a.logShipper.drain()) {
+				body, err := json.Marshal(fleet.AgentLogs{SchemaVersion: fleet.CurrentLogSchemaVersion, Entries: batch})
+				if err != nil {
+					logger.Error("error marshalling log entries", zap.Error(err))
+					continue
+				}
+				if token := a.client.Publish(a.logTopic, 1, false, body); token.Wait() && token.Error() != nil {
+					logger.Warn("error sending log entries", zap.Int("entries", len(batch)), zap.Error(token.Error()))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package agent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/orb-community/orb/fleet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogShipper(t *testing.T) {
+	shipper, err := newLogShipper("info", 2)
+	require.Nil(t, err, "unexpected error: %s", err)
+
+	logger := zap.New(zapcore.NewNopCore()).WithOptions(zap.WrapCore(shipper.wrapCore))
+	logger.Debug("below level")
+	logger.Named("pktvisor").With(zap.String("policy_id", "p1")).Warn("pktvisor stderr", zap.String("log", "line"))
+	logger.Info("agent started")
+	logger.Info("rate limited")
+
+	entries := shipper.drain()
+	require.Len(t, entries, 3)
+	assert.Equal(t, "pktvisor", entries[0].Source)
+	assert.Equal(t, "warn", entries[0].Level)
+	assert.Equal(t, map[string]interface{}{"policy_id": "p1", "log": "line"}, entries[0].Fields)
+	assert.Equal(t, "agent", entries[1].Source)
+	assert.Equal(t, 1, entries[2].Fields["dropped"])
+	assert.Empty(t, shipper.drain())
+}
+
+func TestNewLogShipperInvalidLevel(t *testing.T) {
+	_, err := newLogShipper("verbose", 0)
+	assert.NotNil(t, err)
+}
+
+func TestLogBatches(t *testing.T) {
+	var entries []fleet.AgentLogEntry
+	for i := 0; i < 50; i++ {
+		entries = append(entries, fleet.AgentLogEntry{Level: "info", Source: "otel", Message: strings.Repeat("x", 4096)})
+	}
+
+	result := batches(entries)
+	total := 0
+	for _, b := range result {
+		total += len(b)
+		for _, e := range b {
+			assert.Len(t, e.Message, fleet.MaxAgentLogMessageSize)
+		}
+	}
+	assert.Greater(t, len(result), 1)
+	assert.Equal(t, len(entries), total)
+}
@@ -29,6 +29,9 @@ type Config struct {
 	// Specific for ORB Agent
 	PktVisorVersion string `mapstructure:"pktvisor_version"`
 	OrbAgentService otel.AgentBridgeService
+
+	// Spool holds the payloads while the MQTT connection is unavailable, optional
+	Spool *Spool
 }
 
 var _ component.Config = (*Config)(nil)
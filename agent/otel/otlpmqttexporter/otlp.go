@@ -256,7 +256,9 @@ func (e *baseExporter) pushTraces(ctx context.Context, td ptrace.Traces) error {
 	scopes := ptraceotlp.NewExportRequestFromTraces(td).Traces().ResourceSpans().At(0).ScopeSpans()
 	for i := 0; i < scopes.Len(); i++ {
 		scope := scopes.At(i)
-		policyName := scope.Scope().Name()
+		// policy_name is set on the scope by the transform/policy_data processor of the agent collector
+		policyNameAttr, _ := scope.Scope().Attributes().Get("policy_name")
+		policyName := policyNameAttr.AsString()
 		agentData, err := e.config.OrbAgentService.RetrieveAgentInfoByPolicyName(policyName)
 		if err != nil {
 			e.logger.Warn("Policy is not managed by orb", zap.String("policyName", policyName))
@@ -296,11 +298,25 @@ func (e *baseExporter) pushTraces(ctx context.Context, td ptrace.Traces) error {
 
 func (e *baseExporter) export(ctx context.Context, topic string, request []byte) error {
 	compressedPayload := e.compressBrotli(request)
-	c := *e.config.Client
-	if token := c.Publish(topic, 1, false, compressedPayload); token.Wait() && token.Error() != nil {
-		e.logger.Error("error sending metrics RPC", zap.String("topic", topic), zap.Error(token.Error()))
-		e.config.OrbAgentService.NotifyAgentDisconnection(ctx, token.Error())
-		return token.Error()
+	if spool := e.config.Spool; spool != nil {
+		c := *e.config.Client
+		if !c.IsConnectionOpen() {
+			e.logger.Debug("mqtt connection unavailable, spooling telemetry", zap.String("topic", topic))
+			return spool.Add(topic, compressedPayload)
+		}
+		// keep the original order, anything spooled while disconnected goes out first
+		if err := spool.Replay(e.publish); err != nil {
+			e.logger.Warn("failed to replay spooled telemetry, spooling", zap.String("topic", topic), zap.Error(err))
+			return spool.Add(topic, compressedPayload)
+		}
+	}
+	if err := e.publish(topic, compressedPayload); err != nil {
+		e.logger.Error("error sending metrics RPC", zap.String("topic", topic), zap.Error(err))
+		if e.config.Spool != nil {
+			return e.config.Spool.Add(topic, compressedPayload)
+		}
+		e.config.OrbAgentService.NotifyAgentDisconnection(ctx, err)
+		return err
 	}
 	e.logger.Debug("scraped and published telemetry", zap.String("topic", topic),
 		zap.Int("payload_size_b", len(request)),
@@ -308,3 +324,11 @@ func (e *baseExporter) export(ctx context.Context, topic string, request []byte)
 
 	return nil
 }
+
+func (e *baseExporter) publish(topic string, payload []byte) error {
+	c := *e.config.Client
+	if token := c.Publish(topic, 1, false, payload); token.Wait() && token.Error() != nil {
+		return token.Error()
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package otlpmqttexporter
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	spoolFileExt    = ".otlp"
+	spoolTmpFileExt = ".tmp"
+)
+
+var errSpoolEntryMalformed = errors.New("spool entry malformed")
+
+// SpoolStats counters of the payloads handled by the spool since the agent started
+type SpoolStats struct {
+	Spooled      int64
+	Replayed     int64
+	Dropped      int64
+	Pending      int64
+	PendingBytes int64
+}
+
+// PublishFunc publishes a payload on the given MQTT topic
+type PublishFunc func(topic string, payload []byte) error
+
+// Spool is a bounded on-disk FIFO queue that holds the compressed OTLP payloads
+// which could not be published while the MQTT connection was unavailable.
+// Entries older than maxAge, or exceeding maxBytes in total, are dropped oldest first.
+type Spool struct {
+	logger   *zap.Logger
+	dir      string
+	maxBytes int64
+	maxAge   time.Duration
+
+	// replayMu serializes replays, so an entry is not published twice
+	replayMu sync.Mutex
+
+	mu      sync.Mutex
+	seq     uint64
+	entries []spoolEntry
+	size    int64
+	stats   SpoolStats
+}
+
+type spoolEntry struct {
+	path string
+	size int64
+	ts   time.Time
+}
+
+// NewSpool creates the spool directory if needed and loads the entries left over from a previous run
+func NewSpool(logger *zap.Logger, dir string, maxBytes int64, maxAge time.Duration) (*Spool, error) {
+	if maxBytes <= 0 {
+		return nil, errors.New("spool max size must be greater than zero")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	s := &Spool{
+		logger:   logger,
+		dir:      dir,
+		maxBytes: maxBytes,
+		maxAge:   maxAge,
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Spool) load() error {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		switch filepath.Ext(f.Name()) {
+		case spoolFileExt:
+			names = append(names, f.Name())
+		case spoolTmpFileExt:
+			// partially written entry from a previous run
+			_ = os.Remove(filepath.Join(s.dir, f.Name()))
+		}
+	}
+	// names are zero padded sequence numbers, so lexical order is spool order
+	sort.Strings(names)
+	for _, name := range names {
+		seq, ts, err := parseSpoolFileName(name)
+		if err != nil {
+			s.logger.Warn("ignoring unknown file in spool directory", zap.String("file", name))
+			continue
+		}
+		info, err := os.Stat(filepath.Join(s.dir, name))
+		if err != nil {
+			return err
+		}
+		s.entries = append(s.entries, spoolEntry{path: filepath.Join(s.dir, name), size: info.Size(), ts: ts})
+		s.size += info.Size()
+		if seq > s.seq {
+			s.seq = seq
+		}
+	}
+	s.stats.Pending = int64(len(s.entries))
+	s.stats.PendingBytes = s.size
+	if len(s.entries) > 0 {
+		s.logger.Info("loaded spooled telemetry from previous run", zap.Int("entries", len(s.entries)), zap.Int64("bytes", s.size))
+	}
+	return nil
+}
+
+func parseSpoolFileName(name string) (uint64, time.Time, error) {
+	parts := strings.SplitN(strings.TrimSuffix(name, spoolFileExt), "-", 2)
+	if len(parts) != 2 {
+		return 0, time.Time{}, errSpoolEntryMalformed
+	}
+	seq, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	ts, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return seq, time.Unix(0, ts), nil
+}
+
+// Add appends a payload to the end of the spool, evicting the oldest entries when the spool is full
+func (s *Spool) Add(topic string, payload []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire()
+
+	// entry layout: topic length (uint16), topic, payload
+	entrySize := int64(2 + len(topic) + len(payload))
+	if entrySize > s.maxBytes {
+		s.stats.Dropped++
+		s.logger.Warn("telemetry payload is bigger than the spool, dropping", zap.String("topic", topic), zap.Int64("size", entrySize))
+		return nil
+	}
+	for s.size+entrySize > s.maxBytes && len(s.entries) > 0 {
+		s.removeHead()
+		s.stats.Dropped++
+	}
+
+	s.seq++
+	now := time.Now()
+	path := filepath.Join(s.dir, fmt.Sprintf("%020d-%d%s", s.seq, now.UnixNano(), spoolFileExt))
+	buf := make([]byte, 0, entrySize)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(topic)))
+	buf = append(buf, topic...)
+	buf = append(buf, payload...)
+	if err := os.WriteFile(path+spoolTmpFileExt, buf, 0o640); err != nil {
+		s.stats.Dropped++
+		return err
+	}
+	if err := os.Rename(path+spoolTmpFileExt, path); err != nil {
+		s.stats.Dropped++
+		return err
+	}
+	s.entries = append(s.entries, spoolEntry{path: path, size: entrySize, ts: now})
+	s.size += entrySize
+	s.stats.Spooled++
+	s.updatePending()
+	return nil
+}
+
+// Replay publishes the spooled payloads in order, stopping at the first publish failure.
+// Entries which were not published remain in the spool. The lock is not held while publishing,
+// so payloads keep being spooled while the broker acknowledges the replayed ones.
+func (s *Spool) Replay(publish PublishFunc) error {
+	s.replayMu.Lock()
+	defer s.replayMu.Unlock()
+
+	s.mu.Lock()
+	s.expire()
+	pending := make([]spoolEntry, len(s.entries))
+	copy(pending, s.entries)
+	size := s.size
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+	s.logger.Info("replaying spooled telemetry", zap.Int("entries", len(pending)), zap.Int64("bytes", size))
+	for _, entry := range pending {
+		topic, payload, err := s.readEntry(entry)
+		if err != nil {
+			s.mu.Lock()
+			// the entry may have been evicted by Add meanwhile, it is then already accounted as dropped
+			if s.trim(entry) {
+				s.logger.Warn("failed to read spooled telemetry, dropping", zap.String("file", entry.path), zap.Error(err))
+				s.stats.Dropped++
+			}
+			s.mu.Unlock()
+			continue
+		}
+		if err := publish(topic, payload); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.trim(entry)
+		s.stats.Replayed++
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// Len returns the number of payloads waiting to be replayed
+func (s *Spool) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// Stats returns a snapshot of the spool counters
+func (s *Spool) Stats() SpoolStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+func (s *Spool) readEntry(entry spoolEntry) (string, []byte, error) {
+	data, err := os.ReadFile(entry.path)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(data) < 2 {
+		return "", nil, errSpoolEntryMalformed
+	}
+	topicLen := int(binary.BigEndian.Uint16(data[:2]))
+	if len(data) < 2+topicLen {
+		return "", nil, errSpoolEntryMalformed
+	}
+	return string(data[2 : 2+topicLen]), data[2+topicLen:], nil
+}
+
+// expire drops the entries older than maxAge, must be called with the lock held
+func (s *Spool) expire() {
+	if s.maxAge <= 0 {
+		return
+	}
+	cutoff := time.Now().Add(-s.maxAge)
+	for len(s.entries) > 0 && s.entries[0].ts.Before(cutoff) {
+		s.removeHead()
+		s.stats.Dropped++
+	}
+}
+
+// trim removes the replayed entry unless it was already evicted, entries only ever leave the spool from its head.
+// Must be called with the lock held
+func (s *Spool) trim(entry spoolEntry) bool {
+	if len(s.entries) == 0 || s.entries[0].path != entry.path {
+		return false
+	}
+	s.removeHead()
+	return true
+}
+
+// removeHead removes the oldest entry, must be called with the lock held
+func (s *Spool) removeHead() {
+	head := s.entries[0]
+	if err := os.Remove(head.path); err != nil && !os.IsNotExist(err) {
+		s.logger.Warn("failed to remove spool entry", zap.String("file", head.path), zap.Error(err))
+	}
+	s.entries = s.entries[1:]
+	s.size -= head.size
+	s.updatePending()
+}
+
+func (s *Spool) updatePending() {
+	s.stats.Pending = int64(len(s.entries))
+	s.stats.PendingBytes = s.size
+}
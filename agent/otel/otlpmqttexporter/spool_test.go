@@ -0,0 +1,113 @@
+package otlpmqttexporter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type published struct {
+	topic   string
+	payload string
+}
+
+func TestSpoolReplayInOrder(t *testing.T) {
+	dir := t.TempDir()
+	spool, err := NewSpool(zap.NewNop(), dir, 1024, time.Hour)
+	require.NoError(t, err)
+
+	require.NoError(t, spool.Add("topic/a", []byte("one")))
+	require.NoError(t, spool.Add("topic/b", []byte("two")))
+
+	// entries must survive an agent restart
+	spool, err = NewSpool(zap.NewNop(), dir, 1024, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, spool.Add("topic/a", []byte("three")))
+	assert.Equal(t, 3, spool.Len())
+
+	var got []published
+	err = spool.Replay(func(topic string, payload []byte) error {
+		got = append(got, published{topic, string(payload)})
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []published{{"topic/a", "one"}, {"topic/b", "two"}, {"topic/a", "three"}}, got)
+	assert.Equal(t, 0, spool.Len())
+
+	stats := spool.Stats()
+	assert.Equal(t, int64(1), stats.Spooled)
+	assert.Equal(t, int64(3), stats.Replayed)
+	assert.Equal(t, int64(0), stats.Pending)
+}
+
+func TestSpoolReplayStopsOnFailure(t *testing.T) {
+	spool, err := NewSpool(zap.NewNop(), t.TempDir(), 1024, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, spool.Add("topic", []byte("one")))
+	require.NoError(t, spool.Add("topic", []byte("two")))
+
+	calls := 0
+	err = spool.Replay(func(topic string, payload []byte) error {
+		calls++
+		if calls == 2 {
+			return errors.New("not connected")
+		}
+		return nil
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, spool.Len())
+	assert.Equal(t, int64(1), spool.Stats().Replayed)
+}
+
+func TestSpoolAddWhileReplaying(t *testing.T) {
+	spool, err := NewSpool(zap.NewNop(), t.TempDir(), 1024, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, spool.Add("topic", []byte("one")))
+	require.NoError(t, spool.Add("topic", []byte("two")))
+
+	// telemetry produced while the broker acknowledges replayed payloads is spooled, not blocked
+	var got []string
+	err = spool.Replay(func(topic string, payload []byte) error {
+		got = append(got, string(payload))
+		return spool.Add("topic", []byte("new "+string(payload)))
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two"}, got)
+	assert.Equal(t, 2, spool.Len())
+
+	got = nil
+	err = spool.Replay(func(topic string, payload []byte) error {
+		got = append(got, string(payload))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new one", "new two"}, got)
+	assert.Equal(t, 0, spool.Len())
+}
+
+func TestSpoolLimits(t *testing.T) {
+	// each entry is 2 bytes of header, 5 bytes of topic and 3 bytes of payload
+	spool, err := NewSpool(zap.NewNop(), t.TempDir(), 25, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, spool.Add("topic", []byte("one")))
+	require.NoError(t, spool.Add("topic", []byte("two")))
+	require.NoError(t, spool.Add("topic", []byte("six")))
+	assert.Equal(t, 2, spool.Len())
+	assert.Equal(t, int64(1), spool.Stats().Dropped)
+
+	require.NoError(t, spool.Add("topic", make([]byte, 100)))
+	assert.Equal(t, int64(2), spool.Stats().Dropped)
+
+	spool.maxAge = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	require.NoError(t, spool.Replay(func(string, []byte) error {
+		t.Fatal("expired entries must not be replayed")
+		return nil
+	}))
+	assert.Equal(t, 0, spool.Len())
+	assert.Equal(t, int64(4), spool.Stats().Dropped)
+}
@@ -0,0 +1,296 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package policies
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	migrate "github.com/rubenv/sql-migrate"
+	"go.uber.org/zap"
+)
+
+// policySqliteRepo persists agent policies in the local agent database, so they survive agent restarts
+type policySqliteRepo struct {
+	logger *zap.Logger
+	db     *sqlx.DB
+}
+
+var _ PolicyRepo = (*policySqliteRepo)(nil)
+
+type dbPolicy struct {
+	ID              string         `db:"id"`
+	Name            string         `db:"name"`
+	Backend         string         `db:"backend"`
+	Version         int32          `db:"version"`
+	Data            string         `db:"data"`
+	State           PolicyState    `db:"state"`
+	BackendErr      string         `db:"backend_err"`
+	LastScrapeBytes int64          `db:"last_scrape_bytes"`
+	LastScrapeTS    sql.NullInt64  `db:"last_scrape_ts"`
+	PreviousName    sql.NullString `db:"previous_name"`
+}
+
+func NewSqliteRepo(logger *zap.Logger, db *sqlx.DB) (PolicyRepo, error) {
+	r := &policySqliteRepo{
+		logger: logger,
+		db:     db,
+	}
+	if err := r.migrateDB(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r policySqliteRepo) migrateDB() error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "policies_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS agent_policies (
+						id TEXT PRIMARY KEY,
+						name TEXT NOT NULL,
+						backend TEXT NOT NULL,
+						version INTEGER NOT NULL DEFAULT 0,
+						data TEXT NOT NULL,
+						state TEXT NOT NULL,
+						backend_err TEXT NOT NULL DEFAULT '',
+						last_scrape_bytes INTEGER NOT NULL DEFAULT 0,
+						last_scrape_ts INTEGER,
+						previous_name TEXT
+						)`,
+					`CREATE UNIQUE INDEX IF NOT EXISTS agent_policies_name_idx ON agent_policies (name)`,
+					`CREATE TABLE IF NOT EXISTS agent_policy_datasets (
+						policy_id TEXT NOT NULL,
+						dataset_id TEXT NOT NULL,
+						PRIMARY KEY (policy_id, dataset_id)
+						)`,
+					`CREATE TABLE IF NOT EXISTS agent_policy_groups (
+						policy_id TEXT NOT NULL,
+						group_id TEXT NOT NULL,
+						PRIMARY KEY (policy_id, group_id)
+						)`,
+				},
+				Down: []string{
+					"DROP TABLE agent_policy_groups",
+					"DROP TABLE agent_policy_datasets",
+					"DROP TABLE agent_policies",
+				},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(r.db.DB, "sqlite3", migrations, migrate.Up)
+
+	return err
+}
+
+func (r policySqliteRepo) Exists(policyID string) bool {
+	var count int
+	if err := r.db.Get(&count, `SELECT COUNT(*) FROM agent_policies WHERE id = $1`, policyID); err != nil {
+		r.logger.Error("failed to check policy existence", zap.String("policy_id", policyID), zap.Error(err))
+		return false
+	}
+	return count > 0
+}
+
+func (r policySqliteRepo) Get(policyID string) (PolicyData, error) {
+	return r.retrieve(`SELECT * FROM agent_policies WHERE id = $1`, policyID)
+}
+
+func (r policySqliteRepo) GetByName(policyName string) (PolicyData, error) {
+	pd, err := r.retrieve(`SELECT * FROM agent_policies WHERE name = $1`, policyName)
+	if err != nil {
+		return PolicyData{}, errors.New("policy name not found")
+	}
+	return pd, nil
+}
+
+func (r policySqliteRepo) GetAll() ([]PolicyData, error) {
+	var dbps []dbPolicy
+	if err := r.db.Select(&dbps, `SELECT * FROM agent_policies ORDER BY name`); err != nil {
+		return nil, err
+	}
+	ret := make([]PolicyData, 0, len(dbps))
+	for _, dbp := range dbps {
+		pd, err := r.toPolicyData(dbp)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, pd)
+	}
+	return ret, nil
+}
+
+func (r policySqliteRepo) Remove(policyID string) error {
+	if !r.Exists(policyID) {
+		return errors.New("unknown policy ID")
+	}
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	for _, q := range []string{
+		`DELETE FROM agent_policy_datasets WHERE policy_id = $1`,
+		`DELETE FROM agent_policy_groups WHERE policy_id = $1`,
+		`DELETE FROM agent_policies WHERE id = $1`,
+	} {
+		if _, err := tx.Exec(q, policyID); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r policySqliteRepo) Update(data PolicyData) error {
+	policyData, err := json.Marshal(data.Data)
+	if err != nil {
+		return err
+	}
+	dbp := dbPolicy{
+		ID:              data.ID,
+		Name:            data.Name,
+		Backend:         data.Backend,
+		Version:         data.Version,
+		Data:            string(policyData),
+		State:           data.State,
+		BackendErr:      data.BackendErr,
+		LastScrapeBytes: data.LastScrapeBytes,
+	}
+	if !data.LastScrapeTS.IsZero() {
+		dbp.LastScrapeTS = sql.NullInt64{Int64: data.LastScrapeTS.UnixNano(), Valid: true}
+	}
+	if data.PreviousPolicyData != nil {
+		dbp.PreviousName = sql.NullString{String: data.PreviousPolicyData.Name, Valid: true}
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	// a policy renamed on the control plane may collide with a stale local entry holding the same name
+	if _, err := tx.Exec(`DELETE FROM agent_policies WHERE name = $1 AND id <> $2`, data.Name, data.ID); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	q := `INSERT INTO agent_policies (id, name, backend, version, data, state, backend_err, last_scrape_bytes, last_scrape_ts, previous_name)
+		VALUES (:id, :name, :backend, :version, :data, :state, :backend_err, :last_scrape_bytes, :last_scrape_ts, :previous_name)
+		ON CONFLICT (id) DO UPDATE SET name = excluded.name, backend = excluded.backend, version = excluded.version,
+			data = excluded.data, state = excluded.state, backend_err = excluded.backend_err,
+			last_scrape_bytes = excluded.last_scrape_bytes, last_scrape_ts = excluded.last_scrape_ts,
+			previous_name = excluded.previous_name`
+	if _, err := tx.NamedExec(q, dbp); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM agent_policy_datasets WHERE policy_id = $1`, data.ID); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	for datasetID := range data.Datasets {
+		if _, err := tx.Exec(`INSERT INTO agent_policy_datasets (policy_id, dataset_id) VALUES ($1, $2)`, data.ID, datasetID); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	if _, err := tx.Exec(`DELETE FROM agent_policy_groups WHERE policy_id = $1`, data.ID); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	for groupID := range data.GroupIds {
+		if _, err := tx.Exec(`INSERT INTO agent_policy_groups (policy_id, group_id) VALUES ($1, $2)`, data.ID, groupID); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r policySqliteRepo) EnsureDataset(policyID string, datasetID string) error {
+	if !r.Exists(policyID) {
+		return errors.New("unknown policy ID")
+	}
+	_, err := r.db.Exec(`INSERT OR IGNORE INTO agent_policy_datasets (policy_id, dataset_id) VALUES ($1, $2)`, policyID, datasetID)
+	return err
+}
+
+func (r policySqliteRepo) RemoveDataset(policyID string, datasetID string) (bool, error) {
+	if !r.Exists(policyID) {
+		return false, errors.New("unknown policy ID")
+	}
+	if _, err := r.db.Exec(`DELETE FROM agent_policy_datasets WHERE policy_id = $1 AND dataset_id = $2`, policyID, datasetID); err != nil {
+		return false, err
+	}
+	var count int
+	if err := r.db.Get(&count, `SELECT COUNT(*) FROM agent_policy_datasets WHERE policy_id = $1`, policyID); err != nil {
+		return false, err
+	}
+	// If after remove the policy it doesn't have others datasets,
+	// we can remove the policy from the agent
+	return count == 0, nil
+}
+
+func (r policySqliteRepo) EnsureGroupID(policyID string, agentGroupID string) error {
+	if !r.Exists(policyID) {
+		return errors.New("unknown policy ID")
+	}
+	_, err := r.db.Exec(`INSERT OR IGNORE INTO agent_policy_groups (policy_id, group_id) VALUES ($1, $2)`, policyID, agentGroupID)
+	return err
+}
+
+func (r policySqliteRepo) retrieve(query string, arg string) (PolicyData, error) {
+	var dbp dbPolicy
+	if err := r.db.QueryRowx(query, arg).StructScan(&dbp); err != nil {
+		if err == sql.ErrNoRows {
+			return PolicyData{}, errors.New("unknown policy ID")
+		}
+		return PolicyData{}, err
+	}
+	return r.toPolicyData(dbp)
+}
+
+func (r policySqliteRepo) toPolicyData(dbp dbPolicy) (PolicyData, error) {
+	pd := PolicyData{
+		ID:              dbp.ID,
+		Name:            dbp.Name,
+		Backend:         dbp.Backend,
+		Version:         dbp.Version,
+		State:           dbp.State,
+		BackendErr:      dbp.BackendErr,
+		LastScrapeBytes: dbp.LastScrapeBytes,
+		Datasets:        make(map[string]bool),
+		GroupIds:        make(map[string]bool),
+	}
+	if err := json.Unmarshal([]byte(dbp.Data), &pd.Data); err != nil {
+		return PolicyData{}, err
+	}
+	if dbp.LastScrapeTS.Valid {
+		pd.LastScrapeTS = time.Unix(0, dbp.LastScrapeTS.Int64)
+	}
+	if dbp.PreviousName.Valid {
+		pd.PreviousPolicyData = &PolicyData{Name: dbp.PreviousName.String}
+	}
+
+	var datasets []string
+	if err := r.db.Select(&datasets, `SELECT dataset_id FROM agent_policy_datasets WHERE policy_id = $1`, dbp.ID); err != nil {
+		return PolicyData{}, err
+	}
+	for _, id := range datasets {
+		pd.Datasets[id] = true
+	}
+	var groups []string
+	if err := r.db.Select(&groups, `SELECT group_id FROM agent_policy_groups WHERE policy_id = $1`, dbp.ID); err != nil {
+		return PolicyData{}, err
+	}
+	for _, id := range groups {
+		pd.GroupIds[id] = true
+	}
+	return pd, nil
+}
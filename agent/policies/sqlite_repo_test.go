@@ -0,0 +1,91 @@
+package policies
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestSqliteRepo(t *testing.T, file string) PolicyRepo {
+	db, err := sqlx.Connect("sqlite3", file)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	repo, err := NewSqliteRepo(zap.NewNop(), db)
+	require.NoError(t, err)
+	return repo
+}
+
+func TestSqliteRepoPersistsPolicies(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "orb-agent.db")
+	repo := newTestSqliteRepo(t, file)
+
+	ts := time.Now()
+	pd := PolicyData{
+		ID:              "policy-1",
+		Name:            "default_dns",
+		Backend:         "pktvisor",
+		Version:         2,
+		Data:            map[string]interface{}{"kind": "collection"},
+		State:           Running,
+		Datasets:        map[string]bool{"dataset-1": true},
+		GroupIds:        map[string]bool{"group-1": true},
+		LastScrapeBytes: 1024,
+		LastScrapeTS:    ts,
+	}
+	require.NoError(t, repo.Update(pd))
+	require.NoError(t, repo.EnsureDataset(pd.ID, "dataset-2"))
+	require.NoError(t, repo.EnsureGroupID(pd.ID, "group-2"))
+
+	// a fresh repo on the same file simulates an agent restart
+	restarted := newTestSqliteRepo(t, file)
+	assert.True(t, restarted.Exists(pd.ID))
+
+	got, err := restarted.GetByName(pd.Name)
+	require.NoError(t, err)
+	assert.Equal(t, pd.ID, got.ID)
+	assert.Equal(t, pd.Backend, got.Backend)
+	assert.Equal(t, pd.Version, got.Version)
+	assert.Equal(t, Running, got.State)
+	assert.Equal(t, map[string]interface{}{"kind": "collection"}, got.Data)
+	assert.Equal(t, map[string]bool{"dataset-1": true, "dataset-2": true}, got.Datasets)
+	assert.Equal(t, map[string]bool{"group-1": true, "group-2": true}, got.GroupIds)
+	assert.Equal(t, pd.LastScrapeBytes, got.LastScrapeBytes)
+	assert.Equal(t, ts.UnixNano(), got.LastScrapeTS.UnixNano())
+
+	all, err := restarted.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
+func TestSqliteRepoRemoveDataset(t *testing.T) {
+	repo := newTestSqliteRepo(t, filepath.Join(t.TempDir(), "orb-agent.db"))
+
+	pd := PolicyData{
+		ID:       "policy-1",
+		Name:     "default_dns",
+		Backend:  "pktvisor",
+		Data:     map[string]interface{}{},
+		Datasets: map[string]bool{"dataset-1": true, "dataset-2": true},
+	}
+	require.NoError(t, repo.Update(pd))
+
+	empty, err := repo.RemoveDataset(pd.ID, "dataset-1")
+	require.NoError(t, err)
+	assert.False(t, empty)
+
+	empty, err = repo.RemoveDataset(pd.ID, "dataset-2")
+	require.NoError(t, err)
+	assert.True(t, empty)
+
+	require.NoError(t, repo.Remove(pd.ID))
+	assert.False(t, repo.Exists(pd.ID))
+	_, err = repo.Get(pd.ID)
+	assert.Error(t, err)
+	_, err = repo.RemoveDataset(pd.ID, "dataset-1")
+	assert.Error(t, err)
+}
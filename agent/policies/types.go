@@ -67,7 +67,12 @@ func (s PolicyState) String() string {
 }
 
 func (s *PolicyState) Scan(value interface{}) error {
-	*s = policyStateRevMap[string(value.([]byte))]
+	switch v := value.(type) {
+	case []byte:
+		*s = policyStateRevMap[string(v)]
+	case string:
+		*s = policyStateRevMap[v]
+	}
 	return nil
 }
 func (s PolicyState) Value() (driver.Value, error) { return s.String(), nil }
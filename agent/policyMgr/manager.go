@@ -22,8 +22,8 @@ type PolicyManager interface {
 	RemovePolicyDataset(policyID string, datasetID string, be backend.Backend)
 	GetPolicyState() ([]policies.PolicyData, error)
 	GetRepo() policies.PolicyRepo
-	ApplyBackendPolicies(be backend.Backend) error
-	RemoveBackendPolicies(be backend.Backend, permanently bool) error
+	ApplyBackendPolicies(name string, be backend.Backend) error
+	RemoveBackendPolicies(name string, be backend.Backend, permanently bool) error
 	RemovePolicy(policyID string, policyName string, beName string) error
 }
 
@@ -45,11 +45,42 @@ func (a *policyManager) GetPolicyState() ([]policies.PolicyData, error) {
 }
 
 func New(logger *zap.Logger, c config.Config, db *sqlx.DB) (PolicyManager, error) {
-	repo, err := policies.NewMemRepo(logger)
+	if db == nil {
+		repo, err := policies.NewMemRepo(logger)
+		if err != nil {
+			return nil, err
+		}
+		return &policyManager{logger: logger, config: c, repo: repo}, nil
+	}
+	repo, err := policies.NewSqliteRepo(logger, db)
 	if err != nil {
 		return nil, err
 	}
-	return &policyManager{logger: logger, config: c, repo: repo}, nil
+	pm := &policyManager{logger: logger, config: c, repo: repo}
+	if err := pm.resetPolicyStates(); err != nil {
+		return nil, err
+	}
+	return pm, nil
+}
+
+// resetPolicyStates marks the policies restored from local storage as not yet applied, since no backend
+// is running them when the agent starts
+func (a *policyManager) resetPolicyStates() error {
+	plcies, err := a.repo.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, plcy := range plcies {
+		plcy.State = policies.Unknown
+		plcy.BackendErr = ""
+		if err := a.repo.Update(plcy); err != nil {
+			return err
+		}
+	}
+	if len(plcies) > 0 {
+		a.logger.Info("restored policies from local storage", zap.Int("count", len(plcies)))
+	}
+	return nil
 }
 
 func (a *policyManager) ManagePolicy(payload fleet.AgentPolicyRPCPayload) {
@@ -213,7 +244,7 @@ func (a *policyManager) applyPolicy(payload fleet.AgentPolicyRPCPayload, be back
 	}
 }
 
-func (a *policyManager) RemoveBackendPolicies(be backend.Backend, permanently bool) error {
+func (a *policyManager) RemoveBackendPolicies(name string, be backend.Backend, permanently bool) error {
 	plcies, err := a.repo.GetAll()
 	if err != nil {
 		a.logger.Error("failed to retrieve list of policies", zap.Error(err))
@@ -221,6 +252,10 @@ func (a *policyManager) RemoveBackendPolicies(be backend.Backend, permanently bo
 	}
 
 	for _, plcy := range plcies {
+		// policies of the other backends are left running
+		if plcy.Backend != name {
+			continue
+		}
 		err := be.RemovePolicy(plcy)
 		if err != nil {
 			a.logger.Error("failed to remove policy from backend", zap.String("policy_id", plcy.ID), zap.String("policy_name", plcy.Name), zap.Error(err))
@@ -242,7 +277,7 @@ func (a *policyManager) RemoveBackendPolicies(be backend.Backend, permanently bo
 	return nil
 }
 
-func (a *policyManager) ApplyBackendPolicies(be backend.Backend) error {
+func (a *policyManager) ApplyBackendPolicies(name string, be backend.Backend) error {
 	plcies, err := a.repo.GetAll()
 	if err != nil {
 		a.logger.Error("failed to retrieve list of policies", zap.Error(err))
@@ -250,6 +285,9 @@ func (a *policyManager) ApplyBackendPolicies(be backend.Backend) error {
 	}
 
 	for _, policy := range plcies {
+		if policy.Backend != name {
+			continue
+		}
 		err := be.ApplyPolicy(policy, false)
 		if err != nil {
 			a.logger.Warn("policy failed to apply", zap.String("policy_id", policy.ID), zap.String("policy_name", policy.Name), zap.Error(err))
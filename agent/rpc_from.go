@@ -7,10 +7,14 @@ package agent
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/eclipse/paho.mqtt.golang"
+	"github.com/orb-community/orb/agent/backend"
+	"github.com/orb-community/orb/agent/cloud_config"
 	"github.com/orb-community/orb/fleet"
 	"go.uber.org/zap"
+	"time"
 )
 
 func (a *orbAgent) handleGroupMembership(rpc fleet.GroupMembershipRPCPayload) {
@@ -155,6 +159,9 @@ func (a *orbAgent) handleAgentGroupRemoval(rpc fleet.GroupRemovedRPCPayload) {
 				continue
 			}
 		} else {
+			if err = a.policyManager.GetRepo().Update(policy); err != nil {
+				a.logger.Warn("failed to remove group from policy", zap.String("policy_id", policy.ID), zap.String("agent_group_id", rpc.AgentGroupID), zap.Error(err))
+			}
 			for _, datasetID := range rpc.Datasets {
 				a.removeDatasetFromPolicy(datasetID, policy.ID)
 			}
@@ -173,8 +180,143 @@ func (a *orbAgent) handleAgentReset(ctx context.Context, payload fleet.AgentRese
 			a.logger.Error("RestartAll failure", zap.Error(err))
 		}
 	} else {
-		// TODO backend specific restart
-		// a.RestartBackend()
+		err := a.RestartBackend(ctx, payload.Backend, payload.Reason, payload.ReapplyPolicies)
+		if err != nil {
+			a.logger.Error("RestartBackend failure", zap.String("backend", payload.Backend), zap.Error(err))
+		}
+	}
+}
+
+func (a *orbAgent) handleHeartbeatConfig(payload fleet.HeartbeatConfigRPCPayload) {
+	interval := time.Duration(payload.HeartbeatInterval) * time.Second
+	if interval < fleet.MinHeartbeatInterval {
+		a.logger.Warn("ignoring heartbeat configuration with a too short interval", zap.Duration("interval", interval))
+		return
+	}
+	a.hbMu.Lock()
+	defer a.hbMu.Unlock()
+	if interval == a.heartbeatIntervalLocked() {
+		return
+	}
+	a.logger.Info("heartbeat interval configured by control plane", zap.Duration("interval", interval),
+		zap.Duration("stale_timeout", time.Duration(payload.StaleTimeout)*time.Second))
+	a.hbInterval = interval
+	if a.hbTicker != nil {
+		a.hbTicker.Reset(interval)
+	}
+}
+
+func (a *orbAgent) handleAgentMetricsReq(ctx context.Context, payload fleet.AgentMetricsReqRPCPayload) {
+	answer := fleet.AgentMetricsRPCPayload{
+		RequestID: payload.RequestID,
+		PolicyID:  payload.PolicyID,
+	}
+	if err := a.snapshotPolicyMetrics(ctx, &answer); err != nil {
+		a.logger.Warn("failed to retrieve policy metrics", zap.String("policy_id", payload.PolicyID), zap.Error(err))
+		answer.Error = err.Error()
+	}
+	if err := a.sendPolicyMetrics(payload.RequestID, answer); err != nil {
+		a.logger.Error("failed to send policy metrics", zap.String("policy_id", payload.PolicyID), zap.Error(err))
+	}
+}
+
+func (a *orbAgent) snapshotPolicyMetrics(ctx context.Context, answer *fleet.AgentMetricsRPCPayload) error {
+	pd, err := a.policyManager.GetRepo().Get(answer.PolicyID)
+	if err != nil {
+		return err
+	}
+	answer.PolicyName = pd.Name
+	answer.Datasets = pd.GetDatasetIDs()
+
+	be, ok := a.backends[pd.Backend]
+	if !ok {
+		return errors.New("policy backend is not running on this agent: " + pd.Backend)
+	}
+	snapshotter, ok := be.(backend.MetricsSnapshotter)
+	if !ok {
+		return errors.New("policy backend does not support metrics snapshots: " + pd.Backend)
+	}
+	if answer.BEVersion, err = be.Version(); err != nil {
+		return err
+	}
+	answer.Format, answer.Data, err = snapshotter.PolicyMetrics(ctx, pd)
+	if err != nil {
+		return err
+	}
+	if len(answer.Data) > fleet.MaxPolicySnapshotSize/2 {
+		answer.Data = nil
+		return errors.New("policy metrics are too large to be sent")
+	}
+	return nil
+}
+
+func (a *orbAgent) handleAgentKeyRotation(payload fleet.AgentKeyRotationRPCPayload) {
+	err := a.saveRotatedKey(payload.Key)
+	if err != nil {
+		a.logger.Error("failed to persist rotated key", zap.Error(err))
+	} else {
+		a.keyRotationMu.Lock()
+		a.keyRotationID = payload.RequestID
+		a.keyRotationMu.Unlock()
+	}
+	if err := a.sendKeyRotationAck(payload.RequestID, err); err != nil {
+		a.logger.Error("failed to send key rotation acknowledgement", zap.Error(err))
+	}
+}
+
+func (a *orbAgent) saveRotatedKey(key string) error {
+	if key == "" {
+		return errors.New("empty key")
+	}
+	ccm, err := cloud_config.New(a.logger, a.config, a.db)
+	if err != nil {
+		return err
+	}
+	return ccm.SavePendingKey(a.config.OrbAgent.Cloud.MQTT, key)
+}
+
+// takeKeyRotation clears the pending key rotation when it is the given request, so a commit is only applied once
+func (a *orbAgent) takeKeyRotation(requestID string) bool {
+	a.keyRotationMu.Lock()
+	defer a.keyRotationMu.Unlock()
+	if requestID == "" || requestID != a.keyRotationID {
+		return false
+	}
+	a.keyRotationID = ""
+	return true
+}
+
+func (a *orbAgent) handleAgentKeyRotationCommit(ctx context.Context, payload fleet.AgentKeyRotationCommitRPCPayload) {
+	if !a.takeKeyRotation(payload.RequestID) {
+		a.logger.Warn("ignoring commit of an unknown key rotation", zap.String("request_id", payload.RequestID))
+		return
+	}
+
+	ccm, err := cloud_config.New(a.logger, a.config, a.db)
+	if err != nil {
+		a.logger.Error("failed to open cloud configuration", zap.Error(err))
+		return
+	}
+	if !payload.Committed {
+		a.logger.Info("key rotation aborted by control plane, keeping current key")
+		if err := ccm.DropPendingKey(a.config.OrbAgent.Cloud.MQTT.Id); err != nil {
+			a.logger.Error("failed to drop pending key", zap.Error(err))
+		}
+		return
+	}
+	if err := ccm.CommitPendingKey(a.config.OrbAgent.Cloud.MQTT.Id); err != nil {
+		a.logger.Error("failed to commit rotated key", zap.Error(err))
+		return
+	}
+
+	// the previous key is revoked, reconnect so the session is authenticated with the new one
+	a.logger.Info("key rotated by control plane, reconnecting")
+	if a.client != nil && a.client.IsConnected() {
+		a.unsubscribeGroupChannels()
+		a.client.Disconnect(250)
+	}
+	if err := a.restartComms(ctx); err != nil {
+		a.logger.Error("failed to reconnect with rotated key", zap.Error(err))
 	}
 }
 
@@ -234,6 +376,34 @@ func (a *orbAgent) handleRPCFromCore(client mqtt.Client, message mqtt.Message) {
 				return
 			}
 			a.handleAgentReset(ctx, r.Payload)
+		case fleet.HeartbeatConfigRPCFunc:
+			var r fleet.HeartbeatConfigRPC
+			if err := json.Unmarshal(message.Payload(), &r); err != nil {
+				a.logger.Error("error decoding heartbeat configuration message from core", zap.Error(fleet.ErrSchemaMalformed))
+				return
+			}
+			a.handleHeartbeatConfig(r.Payload)
+		case fleet.AgentMetricsReqRPCFunc:
+			var r fleet.AgentMetricsReqRPC
+			if err := json.Unmarshal(message.Payload(), &r); err != nil {
+				a.logger.Error("error decoding agent metrics request message from core", zap.Error(fleet.ErrSchemaMalformed))
+				return
+			}
+			a.handleAgentMetricsReq(ctx, r.Payload)
+		case fleet.AgentKeyRotationRPCFunc:
+			var r fleet.AgentKeyRotationRPC
+			if err := json.Unmarshal(message.Payload(), &r); err != nil {
+				a.logger.Error("error decoding agent key rotation message from core", zap.Error(fleet.ErrSchemaMalformed))
+				return
+			}
+			a.handleAgentKeyRotation(r.Payload)
+		case fleet.AgentKeyRotationCommitRPCFunc:
+			var r fleet.AgentKeyRotationCommitRPC
+			if err := json.Unmarshal(message.Payload(), &r); err != nil {
+				a.logger.Error("error decoding agent key rotation commit message from core", zap.Error(fleet.ErrSchemaMalformed))
+				return
+			}
+			a.handleAgentKeyRotationCommit(ctx, r.Payload)
 		default:
 			a.logger.Warn("unsupported/unhandled core RPC, ignoring",
 				zap.String("func", rpc.Func),
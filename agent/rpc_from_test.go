@@ -0,0 +1,37 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package agent
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTakeKeyRotation(t *testing.T) {
+	a := &orbAgent{}
+	require.False(t, a.takeKeyRotation(""), "no rotation is pending")
+
+	a.keyRotationID = "rotation-1"
+	require.False(t, a.takeKeyRotation("rotation-2"), "unknown rotation must not be taken")
+
+	// concurrent commits of the same rotation are only applied once
+	var taken int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if a.takeKeyRotation("rotation-1") {
+				atomic.AddInt32(&taken, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), taken)
+	require.False(t, a.takeKeyRotation("rotation-1"))
+}
@@ -76,6 +76,46 @@ func (a *orbAgent) sendGroupMembershipRequest() error {
 	return nil
 }
 
+// sendPolicyMetrics answers a policy metrics request on the topic the requesting fleet replica listens to
+func (a *orbAgent) sendPolicyMetrics(requestID string, payload fleet.AgentMetricsRPCPayload) error {
+	data := fleet.AgentMetricsRPC{
+		SchemaVersion: fleet.CurrentRPCSchemaVersion,
+		Func:          fleet.AgentMetricsRPCFunc,
+		Payload:       []fleet.AgentMetricsRPCPayload{payload},
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if token := a.client.Publish(fmt.Sprintf("%s/%s", a.rpcToCoreTopic, requestID), 1, false, body); token.Wait() && token.Error() != nil {
+		return token.Error()
+	}
+	return nil
+}
+
+// sendKeyRotationAck confirms the rotated key was persisted, or why it was not, on the topic of the rotation request
+func (a *orbAgent) sendKeyRotationAck(requestID string, ackErr error) error {
+	payload := fleet.AgentKeyRotationAckRPCPayload{RequestID: requestID}
+	if ackErr != nil {
+		payload.Error = ackErr.Error()
+	}
+	data := fleet.AgentKeyRotationAckRPC{
+		SchemaVersion: fleet.CurrentRPCSchemaVersion,
+		Func:          fleet.AgentKeyRotationAckRPCFunc,
+		Payload:       payload,
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if token := a.client.Publish(fmt.Sprintf("%s/%s", a.rpcToCoreTopic, requestID), 1, false, body); token.Wait() && token.Error() != nil {
+		return token.Error()
+	}
+	return nil
+}
+
 func (a *orbAgent) sendGroupMembershipReq() error {
 	defer a.retryGroupMembershipRequest()
 	return a.sendGroupMembershipRequest()
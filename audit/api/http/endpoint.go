@@ -0,0 +1,48 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package http
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/orb-community/orb/audit"
+)
+
+func listEventsEndpoint(svc audit.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listEventsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListEvents(ctx, req.token, req.pageMetadata)
+		if err != nil {
+			return nil, err
+		}
+
+		res := eventsPageRes{
+			Total:  page.Total,
+			Offset: page.Offset,
+			Limit:  page.Limit,
+			Events: []eventRes{},
+		}
+		for _, e := range page.Events {
+			res.Events = append(res.Events, eventRes{
+				ID:        e.ID,
+				ActorID:   e.ActorID,
+				Actor:     e.Actor,
+				Service:   e.Service,
+				Entity:    e.Entity,
+				EntityID:  e.EntityID,
+				Action:    e.Action,
+				Diff:      e.Diff,
+				Timestamp: e.Timestamp,
+			})
+		}
+
+		return res, nil
+	}
+}
@@ -0,0 +1,142 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/orb-community/orb/audit"
+	"github.com/orb-community/orb/audit/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	token        = "token"
+	invalidToken = "invalid"
+	email        = "user@example.com"
+)
+
+type testRequest struct {
+	client *http.Client
+	method string
+	url    string
+	token  string
+	body   io.Reader
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
+	if err != nil {
+		return nil, err
+	}
+	if tr.token != "" {
+		req.Header.Set("Authorization", tr.token)
+	}
+	return tr.client.Do(req)
+}
+
+func newService(tokens map[string]string) audit.Service {
+	auth := mocks.NewAuthService(tokens)
+	return audit.NewService(zap.NewNop(), auth, mocks.NewEventRepository())
+}
+
+func newServer(svc audit.Service) *httptest.Server {
+	mux := MakeHandler(mocktracer.New(), "audit", svc)
+	return httptest.NewServer(mux)
+}
+
+func TestListEvents(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	server := newServer(svc)
+	defer server.Close()
+
+	start := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	events := []audit.Event{
+		{OwnerID: email, Actor: "alice@example.com", Entity: audit.EntityAgentGroup, EntityID: "group-1", Action: audit.ActionCreate, Timestamp: start},
+		{OwnerID: email, Actor: "bob@example.com", Entity: audit.EntityAgentGroup, EntityID: "group-1", Action: audit.ActionUpdate, Timestamp: start.Add(time.Minute)},
+		{OwnerID: email, Actor: "bob@example.com", Entity: audit.EntitySink, EntityID: "sink-1", Action: audit.ActionRemove, Timestamp: start.Add(2 * time.Minute)},
+	}
+	for _, e := range events {
+		require.Nil(t, svc.SaveEvent(context.Background(), e), "unexpected error saving event")
+	}
+
+	cases := map[string]struct {
+		auth   string
+		query  string
+		status int
+		total  uint64
+	}{
+		"list audit events": {
+			auth:   token,
+			query:  "",
+			status: http.StatusOK,
+			total:  3,
+		},
+		"list audit events of an entity": {
+			auth:   token,
+			query:  fmt.Sprintf("?entity=%s", audit.EntityAgentGroup),
+			status: http.StatusOK,
+			total:  2,
+		},
+		"list audit events of an actor since a time": {
+			auth:   token,
+			query:  fmt.Sprintf("?actor=%s&since=%s", url.QueryEscape("bob@example.com"), url.QueryEscape(start.Add(90*time.Second).Format(time.RFC3339))),
+			status: http.StatusOK,
+			total:  1,
+		},
+		"list audit events with an invalid since": {
+			auth:   token,
+			query:  "?since=yesterday",
+			status: http.StatusBadRequest,
+		},
+		"list audit events with a limit too large": {
+			auth:   token,
+			query:  "?limit=1000",
+			status: http.StatusBadRequest,
+		},
+		"list audit events with an invalid token": {
+			auth:   invalidToken,
+			status: http.StatusUnauthorized,
+		},
+		"list audit events without a token": {
+			auth:   "",
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			req := testRequest{
+				client: server.Client(),
+				method: http.MethodGet,
+				url:    fmt.Sprintf("%s/audit%s", server.URL, tc.query),
+				token:  fmt.Sprintf("Bearer %s", tc.auth),
+			}
+			res, err := req.make()
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+			assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var page eventsPageRes
+			err = json.NewDecoder(res.Body).Decode(&page)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error decoding response %s", desc, err))
+			assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d", desc, tc.total, page.Total))
+			assert.Len(t, page.Events, int(tc.total), fmt.Sprintf("%s: expected %d events", desc, tc.total))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/orb-community/orb/audit"
+	"go.uber.org/zap"
+)
+
+var _ audit.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger *zap.Logger
+	svc    audit.Service
+}
+
+func (l loggingMiddleware) ListEvents(ctx context.Context, token string, pm audit.PageMetadata) (_ audit.Page, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: list_events",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: list_events",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ListEvents(ctx, token, pm)
+}
+
+func (l loggingMiddleware) SaveEvent(ctx context.Context, e audit.Event) (err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: save_event",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: save_event",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.SaveEvent(ctx, e)
+}
+
+func NewLoggingMiddleware(svc audit.Service, logger *zap.Logger) audit.Service {
+	return &loggingMiddleware{logger, svc}
+}
@@ -0,0 +1,81 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux"
+	"github.com/orb-community/orb/audit"
+	"github.com/orb-community/orb/pkg/errors"
+)
+
+var _ audit.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	auth    mainflux.AuthServiceClient
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     audit.Service
+}
+
+func (m metricsMiddleware) ListEvents(ctx context.Context, token string, pm audit.PageMetadata) (audit.Page, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return audit.Page{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "listEvents",
+			"owner_id", ownerID,
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ListEvents(ctx, token, pm)
+}
+
+func (m metricsMiddleware) SaveEvent(ctx context.Context, e audit.Event) error {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "saveEvent",
+			"owner_id", e.OwnerID,
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.SaveEvent(ctx, e)
+}
+
+func (m metricsMiddleware) identify(token string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	res, err := m.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", errors.Wrap(errors.ErrUnauthorizedAccess, err)
+	}
+
+	return res.GetId(), nil
+}
+
+// MetricsMiddleware instruments core service by tracking request count and latency.
+func MetricsMiddleware(auth mainflux.AuthServiceClient, svc audit.Service, counter metrics.Counter, latency metrics.Histogram) audit.Service {
+	return &metricsMiddleware{
+		auth:    auth,
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
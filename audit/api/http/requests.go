@@ -0,0 +1,29 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package http
+
+import (
+	"github.com/orb-community/orb/audit"
+	"github.com/orb-community/orb/pkg/errors"
+)
+
+const maxLimitSize = 100
+
+type listEventsReq struct {
+	token        string
+	pageMetadata audit.PageMetadata
+}
+
+func (req listEventsReq) validate() error {
+	if req.token == "" {
+		return errors.ErrUnauthorizedAccess
+	}
+
+	if req.pageMetadata.Limit == 0 || req.pageMetadata.Limit > maxLimitSize {
+		return errors.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/orb-community/orb/pkg/types"
+)
+
+type eventRes struct {
+	ID        string         `json:"id"`
+	ActorID   string         `json:"actor_id"`
+	Actor     string         `json:"actor"`
+	Service   string         `json:"service"`
+	Entity    string         `json:"entity"`
+	EntityID  string         `json:"entity_id"`
+	Action    string         `json:"action"`
+	Diff      types.Metadata `json:"diff"`
+	Timestamp time.Time      `json:"ts_created"`
+}
+
+type eventsPageRes struct {
+	Total  uint64     `json:"total"`
+	Offset uint64     `json:"offset"`
+	Limit  uint64     `json:"limit"`
+	Events []eventRes `json:"events"`
+}
+
+func (res eventsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res eventsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res eventsPageRes) Empty() bool {
+	return false
+}
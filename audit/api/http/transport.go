@@ -0,0 +1,139 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	kitot "github.com/go-kit/kit/tracing/opentracing"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/opentracing/opentracing-go"
+	"github.com/orb-community/orb/audit"
+	"github.com/orb-community/orb/buildinfo"
+	"github.com/orb-community/orb/internal/httputil"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	offsetKey   = "offset"
+	limitKey    = "limit"
+	entityKey   = "entity"
+	entityIDKey = "entity_id"
+	actorKey    = "actor"
+	sinceKey    = "since"
+	defOffset   = 0
+	defLimit    = 10
+)
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(tracer opentracing.Tracer, svcName string, svc audit.Service) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	r := bone.New()
+	r.Get("/audit", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_audit_events")(listEventsEndpoint(svc)),
+		decodeListEvents,
+		types.EncodeResponse,
+		opts...,
+	))
+
+	r.GetFunc("/version", buildinfo.Version(svcName))
+	r.Handle("/metrics", promhttp.Handler())
+
+	return r
+}
+
+func decodeListEvents(_ context.Context, r *http.Request) (interface{}, error) {
+	o, err := httputil.ReadUintQuery(r, offsetKey, defOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := httputil.ReadUintQuery(r, limitKey, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err := httputil.ReadStringQuery(r, entityKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	entityID, err := httputil.ReadStringQuery(r, entityIDKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := httputil.ReadStringQuery(r, actorKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := httputil.ReadStringQuery(r, sinceKey, "")
+	if err != nil {
+		return nil, err
+	}
+	var since time.Time
+	if s != "" {
+		since, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+
+	req := listEventsReq{
+		token: parseJwt(r),
+		pageMetadata: audit.PageMetadata{
+			Offset:   o,
+			Limit:    l,
+			Entity:   entity,
+			EntityID: entityID,
+			Actor:    actor,
+			Since:    since,
+		},
+	}
+
+	return req, nil
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	switch errorVal := err.(type) {
+	case errors.Error:
+		w.Header().Set("Content-Type", types.ContentType)
+		switch {
+		case errors.Contains(errorVal, errors.ErrUnauthorizedAccess):
+			w.WriteHeader(http.StatusUnauthorized)
+		case errors.Contains(errorVal, errors.ErrInvalidQueryParams):
+			w.WriteHeader(http.StatusBadRequest)
+		case errors.Contains(errorVal, errors.ErrMalformedEntity):
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		if errorVal.Msg() != "" {
+			if err := json.NewEncoder(w).Encode(types.ErrorRes{Err: errorVal.Msg()}); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func parseJwt(r *http.Request) (token string) {
+	if strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		token = r.Header.Get("Authorization")[7:]
+	}
+	return
+}
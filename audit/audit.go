@@ -0,0 +1,175 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+)
+
+const (
+	// StreamID is the Redis stream every service publishes its audit events to
+	StreamID = "orb.audit"
+
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionRemove = "remove"
+
+	EntityAgent           = "agent"
+	EntityAgentGroup      = "agent_group"
+	EntityHeartbeatConfig = "heartbeat_config"
+	EntityEnrollmentToken = "enrollment_token"
+	EntityBulkJob         = "bulk_job"
+	EntityPolicy          = "policy"
+	EntityDataset         = "dataset"
+	EntitySink            = "sink"
+)
+
+var (
+	// ErrMalformedEvent indicates an audit event read from the stream which can not be decoded
+	ErrMalformedEvent = errors.New("malformed audit event")
+)
+
+// Event records a change made to an entity of an owner, along with who made it
+type Event struct {
+	ID      string
+	OwnerID string
+	// ActorID and Actor identify the user whose token made the change, Actor is the email
+	ActorID  string
+	Actor    string
+	Service  string
+	Entity   string
+	EntityID string
+	Action   string
+	// Diff maps each changed field to its value before and after the change
+	Diff      types.Metadata
+	Timestamp time.Time
+}
+
+// PageMetadata filters the audit events of an owner, zero values match every event
+type PageMetadata struct {
+	Offset   uint64
+	Limit    uint64
+	Entity   string
+	EntityID string
+	Actor    string
+	Since    time.Time
+}
+
+// Page holds a page of audit events, newest first
+type Page struct {
+	PageMetadata
+	Total  uint64
+	Events []Event
+}
+
+type Service interface {
+	// ListEvents retrieves the audit events of the owner of the token
+	ListEvents(ctx context.Context, token string, pm PageMetadata) (Page, error)
+	// SaveEvent persists an audit event read from the stream
+	SaveEvent(ctx context.Context, e Event) error
+}
+
+type Repository interface {
+	// Save persists an audit event, returning its id
+	Save(ctx context.Context, e Event) (string, error)
+	// RetrieveAll retrieves the audit events of the owner matching the page metadata
+	RetrieveAll(ctx context.Context, ownerID string, pm PageMetadata) (Page, error)
+}
+
+// Diff compares two snapshots of an entity field by field, as encoded in JSON. A nil snapshot stands for an entity
+// which does not exist, so a creation lists every field with no value before and a removal with no value after
+func Diff(before interface{}, after interface{}) types.Metadata {
+	b := snapshot(before)
+	a := snapshot(after)
+
+	diff := types.Metadata{}
+	for k, bv := range b {
+		if av, ok := a[k]; !ok || !reflect.DeepEqual(bv, av) {
+			diff[k] = map[string]interface{}{"before": bv, "after": av}
+		}
+	}
+	for k, av := range a {
+		if _, ok := b[k]; !ok {
+			diff[k] = map[string]interface{}{"before": nil, "after": av}
+		}
+	}
+	return diff
+}
+
+func snapshot(v interface{}) map[string]interface{} {
+	m := map[string]interface{}{}
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil() {
+		return m
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return m
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return map[string]interface{}{}
+	}
+	return m
+}
+
+// Encode lays the event out as the values of a stream message
+func (e Event) Encode() (map[string]interface{}, error) {
+	diff, err := json.Marshal(e.Diff)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"owner_id":  e.OwnerID,
+		"actor_id":  e.ActorID,
+		"actor":     e.Actor,
+		"service":   e.Service,
+		"entity":    e.Entity,
+		"entity_id": e.EntityID,
+		"action":    e.Action,
+		"diff":      string(diff),
+		"timestamp": e.Timestamp.UnixNano(),
+	}, nil
+}
+
+// Decode reads an event out of the values of a stream message
+func Decode(values map[string]interface{}) (Event, error) {
+	read := func(key string) string {
+		val, _ := values[key].(string)
+		return val
+	}
+
+	e := Event{
+		OwnerID:  read("owner_id"),
+		ActorID:  read("actor_id"),
+		Actor:    read("actor"),
+		Service:  read("service"),
+		Entity:   read("entity"),
+		EntityID: read("entity_id"),
+		Action:   read("action"),
+		Diff:     types.Metadata{},
+	}
+	if e.OwnerID == "" || e.Entity == "" || e.Action == "" {
+		return Event{}, ErrMalformedEvent
+	}
+
+	if diff := read("diff"); diff != "" {
+		if err := json.Unmarshal([]byte(diff), &e.Diff); err != nil {
+			return Event{}, errors.Wrap(ErrMalformedEvent, err)
+		}
+	}
+	ts, err := strconv.ParseInt(read("timestamp"), 10, 64)
+	if err != nil {
+		return Event{}, errors.Wrap(ErrMalformedEvent, err)
+	}
+	e.Timestamp = time.Unix(0, ts).UTC()
+
+	return e, nil
+}
@@ -0,0 +1,161 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package audit_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/orb-community/orb/audit"
+	"github.com/orb-community/orb/audit/mocks"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	token        = "token"
+	invalidToken = "invalid"
+	email        = "user@example.com"
+)
+
+type snapshot struct {
+	Name string     `json:"name"`
+	Tags types.Tags `json:"tags"`
+}
+
+func TestDiff(t *testing.T) {
+	cases := map[string]struct {
+		before interface{}
+		after  interface{}
+		diff   types.Metadata
+	}{
+		"diff of a creation": {
+			before: nil,
+			after:  snapshot{Name: "dns", Tags: types.Tags{"region": "us"}},
+			diff: types.Metadata{
+				"name": map[string]interface{}{"before": nil, "after": "dns"},
+				"tags": map[string]interface{}{"before": nil, "after": map[string]interface{}{"region": "us"}},
+			},
+		},
+		"diff of an update": {
+			before: snapshot{Name: "dns", Tags: types.Tags{"region": "us"}},
+			after:  snapshot{Name: "dns", Tags: types.Tags{"region": "eu"}},
+			diff: types.Metadata{
+				"tags": map[string]interface{}{"before": map[string]interface{}{"region": "us"}, "after": map[string]interface{}{"region": "eu"}},
+			},
+		},
+		"diff of a removal": {
+			before: snapshot{Name: "dns"},
+			after:  nil,
+			diff: types.Metadata{
+				"name": map[string]interface{}{"before": "dns", "after": nil},
+				"tags": map[string]interface{}{"before": nil, "after": nil},
+			},
+		},
+		"diff without changes": {
+			before: snapshot{Name: "dns"},
+			after:  &snapshot{Name: "dns"},
+			diff:   types.Metadata{},
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			diff := audit.Diff(tc.before, tc.after)
+			assert.Equal(t, tc.diff, diff, fmt.Sprintf("%s: unexpected diff", desc))
+		})
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	e := audit.Event{
+		OwnerID:   "owner",
+		ActorID:   "owner",
+		Actor:     email,
+		Service:   "policies",
+		Entity:    audit.EntityPolicy,
+		EntityID:  "policy-1",
+		Action:    audit.ActionUpdate,
+		Diff:      audit.Diff(snapshot{Name: "a"}, snapshot{Name: "b"}),
+		Timestamp: time.Unix(1700000000, 42).UTC(),
+	}
+
+	values, err := e.Encode()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	// the values of stream messages are read back as strings
+	read := map[string]interface{}{}
+	for k, v := range values {
+		read[k] = fmt.Sprint(v)
+	}
+	decoded, err := audit.Decode(read)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, e, decoded, "expected the decoded event to match the encoded one")
+
+	delete(read, "owner_id")
+	_, err = audit.Decode(read)
+	assert.True(t, errors.Contains(err, audit.ErrMalformedEvent), fmt.Sprintf("expected %s got %s", audit.ErrMalformedEvent, err))
+}
+
+func TestListEvents(t *testing.T) {
+	svc := audit.NewService(zap.NewNop(), mocks.NewAuthService(map[string]string{token: email}), mocks.NewEventRepository())
+
+	start := time.Now().Add(-time.Hour)
+	events := []audit.Event{
+		{OwnerID: email, Actor: "alice@example.com", Entity: audit.EntityPolicy, EntityID: "policy-1", Action: audit.ActionCreate, Timestamp: start},
+		{OwnerID: email, Actor: "bob@example.com", Entity: audit.EntityPolicy, EntityID: "policy-1", Action: audit.ActionUpdate, Timestamp: start.Add(time.Minute)},
+		{OwnerID: email, Actor: "bob@example.com", Entity: audit.EntitySink, EntityID: "sink-1", Action: audit.ActionRemove, Timestamp: start.Add(2 * time.Minute)},
+		{OwnerID: "other", Actor: "eve@example.com", Entity: audit.EntitySink, EntityID: "sink-2", Action: audit.ActionRemove, Timestamp: start},
+	}
+	for _, e := range events {
+		require.Nil(t, svc.SaveEvent(context.Background(), e), "unexpected error saving event")
+	}
+	err := svc.SaveEvent(context.Background(), audit.Event{Entity: audit.EntitySink})
+	assert.True(t, errors.Contains(err, audit.ErrMalformedEvent), fmt.Sprintf("expected %s got %s", audit.ErrMalformedEvent, err))
+
+	cases := map[string]struct {
+		token string
+		pm    audit.PageMetadata
+		size  int
+		err   error
+	}{
+		"list the audit events of the owner": {
+			token: token,
+			pm:    audit.PageMetadata{Limit: 10},
+			size:  3,
+			err:   nil,
+		},
+		"list the audit events of an entity": {
+			token: token,
+			pm:    audit.PageMetadata{Limit: 10, Entity: audit.EntityPolicy, EntityID: "policy-1"},
+			size:  2,
+			err:   nil,
+		},
+		"list the audit events of an actor since a time": {
+			token: token,
+			pm:    audit.PageMetadata{Limit: 10, Actor: "bob@example.com", Since: start.Add(90 * time.Second)},
+			size:  1,
+			err:   nil,
+		},
+		"list the audit events with wrong credentials": {
+			token: invalidToken,
+			pm:    audit.PageMetadata{Limit: 10},
+			err:   errors.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			page, err := svc.ListEvents(context.Background(), tc.token, tc.pm)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+			assert.Len(t, page.Events, tc.size, fmt.Sprintf("%s: expected %d events got %d", desc, tc.size, len(page.Events)))
+		})
+	}
+}
@@ -0,0 +1,70 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/orb-community/orb/audit"
+	"github.com/orb-community/orb/pkg/errors"
+)
+
+var _ audit.Repository = (*eventRepositoryMock)(nil)
+
+type eventRepositoryMock struct {
+	mu     sync.Mutex
+	events []audit.Event
+}
+
+// NewEventRepository returns an in memory repository of audit events
+func NewEventRepository() audit.Repository {
+	return &eventRepositoryMock{}
+}
+
+func (r *eventRepositoryMock) Save(_ context.Context, e audit.Event) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e.OwnerID == "" || e.Entity == "" || e.Action == "" {
+		return "", errors.ErrMalformedEntity
+	}
+	e.ID = strconv.Itoa(len(r.events) + 1)
+	r.events = append(r.events, e)
+	return e.ID, nil
+}
+
+func (r *eventRepositoryMock) RetrieveAll(_ context.Context, ownerID string, pm audit.PageMetadata) (audit.Page, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var matching []audit.Event
+	for _, e := range r.events {
+		if e.OwnerID != ownerID ||
+			pm.Entity != "" && e.Entity != pm.Entity ||
+			pm.EntityID != "" && e.EntityID != pm.EntityID ||
+			pm.Actor != "" && e.Actor != pm.Actor && e.ActorID != pm.Actor ||
+			!pm.Since.IsZero() && e.Timestamp.Before(pm.Since) {
+			continue
+		}
+		matching = append(matching, e)
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].Timestamp.After(matching[j].Timestamp)
+	})
+
+	page := audit.Page{PageMetadata: pm, Total: uint64(len(matching))}
+	if pm.Offset >= uint64(len(matching)) {
+		return page, nil
+	}
+	end := pm.Offset + pm.Limit
+	if end > uint64(len(matching)) {
+		end = uint64(len(matching))
+	}
+	page.Events = matching[pm.Offset:end]
+	return page, nil
+}
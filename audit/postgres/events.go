@@ -0,0 +1,181 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/orb-community/orb/audit"
+	"github.com/orb-community/orb/pkg/db"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"go.uber.org/zap"
+)
+
+var _ audit.Repository = (*eventRepository)(nil)
+
+type eventRepository struct {
+	db     Database
+	logger *zap.Logger
+}
+
+// NewEventRepository returns the repository of audit events
+func NewEventRepository(db Database, logger *zap.Logger) audit.Repository {
+	return &eventRepository{db: db, logger: logger}
+}
+
+func (r eventRepository) Save(ctx context.Context, e audit.Event) (string, error) {
+	q := `INSERT INTO audit_events (mf_owner_id, actor_id, actor, service, entity, entity_id, action, diff, ts_created)
+			VALUES (:mf_owner_id, :actor_id, :actor, :service, :entity, :entity_id, :action, :diff, :ts_created) RETURNING id`
+
+	if e.OwnerID == "" || e.Entity == "" || e.Action == "" {
+		return "", errors.ErrMalformedEntity
+	}
+
+	rows, err := r.db.NamedQueryContext(ctx, q, toDBEvent(e))
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case db.ErrInvalid, db.ErrTruncation:
+				return "", errors.Wrap(errors.ErrMalformedEntity, err)
+			}
+		}
+		return "", errors.Wrap(db.ErrSaveDB, err)
+	}
+	defer rows.Close()
+
+	var id string
+	if rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return "", errors.Wrap(db.ErrSaveDB, err)
+		}
+	}
+
+	return id, nil
+}
+
+func (r eventRepository) RetrieveAll(ctx context.Context, ownerID string, pm audit.PageMetadata) (audit.Page, error) {
+	filter := ""
+	params := map[string]interface{}{
+		"mf_owner_id": ownerID,
+		"limit":       pm.Limit,
+		"offset":      pm.Offset,
+	}
+	if pm.Entity != "" {
+		filter += " AND entity = :entity"
+		params["entity"] = pm.Entity
+	}
+	if pm.EntityID != "" {
+		filter += " AND entity_id = :entity_id"
+		params["entity_id"] = pm.EntityID
+	}
+	if pm.Actor != "" {
+		filter += " AND (actor = :actor OR actor_id = :actor)"
+		params["actor"] = pm.Actor
+	}
+	if !pm.Since.IsZero() {
+		filter += " AND ts_created >= :since"
+		params["since"] = pm.Since
+	}
+
+	q := fmt.Sprintf(`SELECT id, mf_owner_id, actor_id, actor, service, entity, entity_id, action, diff, ts_created
+			FROM audit_events WHERE mf_owner_id = :mf_owner_id%s
+			ORDER BY ts_created DESC LIMIT :limit OFFSET :offset`, filter)
+
+	rows, err := r.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return audit.Page{}, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+	defer rows.Close()
+
+	var items []audit.Event
+	for rows.Next() {
+		var dbe dbEvent
+		if err := rows.StructScan(&dbe); err != nil {
+			return audit.Page{}, errors.Wrap(errors.ErrSelectEntity, err)
+		}
+		items = append(items, toEvent(dbe))
+	}
+
+	count := fmt.Sprintf(`SELECT COUNT(*) FROM audit_events WHERE mf_owner_id = :mf_owner_id%s`, filter)
+	total, err := total(ctx, r.db, count, params)
+	if err != nil {
+		return audit.Page{}, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+
+	return audit.Page{
+		PageMetadata: pm,
+		Total:        total,
+		Events:       items,
+	}, nil
+}
+
+func total(ctx context.Context, db Database, query string, params interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+type dbEvent struct {
+	ID        string      `db:"id"`
+	MFOwnerID string      `db:"mf_owner_id"`
+	ActorID   string      `db:"actor_id"`
+	Actor     string      `db:"actor"`
+	Service   string      `db:"service"`
+	Entity    string      `db:"entity"`
+	EntityID  string      `db:"entity_id"`
+	Action    string      `db:"action"`
+	Diff      db.Metadata `db:"diff"`
+	Created   time.Time   `db:"ts_created"`
+}
+
+func toDBEvent(e audit.Event) dbEvent {
+	// a nil diff is stored as NULL
+	diff := db.Metadata{}
+	if e.Diff != nil {
+		diff = db.Metadata(e.Diff)
+	}
+
+	return dbEvent{
+		ID:        e.ID,
+		MFOwnerID: e.OwnerID,
+		ActorID:   e.ActorID,
+		Actor:     e.Actor,
+		Service:   e.Service,
+		Entity:    e.Entity,
+		EntityID:  e.EntityID,
+		Action:    e.Action,
+		Diff:      diff,
+		Created:   e.Timestamp,
+	}
+}
+
+func toEvent(dbe dbEvent) audit.Event {
+	return audit.Event{
+		ID:        dbe.ID,
+		OwnerID:   dbe.MFOwnerID,
+		ActorID:   dbe.ActorID,
+		Actor:     dbe.Actor,
+		Service:   dbe.Service,
+		Entity:    dbe.Entity,
+		EntityID:  dbe.EntityID,
+		Action:    dbe.Action,
+		Diff:      types.Metadata(dbe.Diff),
+		Timestamp: dbe.Created,
+	}
+}
@@ -0,0 +1,133 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/orb-community/orb/audit"
+	"github.com/orb-community/orb/audit/postgres"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var logger, _ = zap.NewDevelopment()
+
+func TestEventSave(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.NewEventRepository(dbMiddleware, logger)
+
+	oID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := map[string]struct {
+		event audit.Event
+		err   error
+	}{
+		"save an audit event": {
+			event: audit.Event{
+				OwnerID:   oID.String(),
+				ActorID:   oID.String(),
+				Actor:     "user@example.com",
+				Service:   "policies",
+				Entity:    audit.EntityPolicy,
+				EntityID:  "policy-1",
+				Action:    audit.ActionUpdate,
+				Diff:      types.Metadata{"name": map[string]interface{}{"before": "a", "after": "b"}},
+				Timestamp: time.Now(),
+			},
+			err: nil,
+		},
+		"save an audit event without owner": {
+			event: audit.Event{
+				Entity: audit.EntityPolicy,
+				Action: audit.ActionUpdate,
+			},
+			err: errors.ErrMalformedEntity,
+		},
+		"save an audit event with an invalid owner": {
+			event: audit.Event{
+				OwnerID:   "not-a-uuid",
+				Entity:    audit.EntityPolicy,
+				Action:    audit.ActionUpdate,
+				Timestamp: time.Now(),
+			},
+			err: errors.ErrMalformedEntity,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			_, err := repo.Save(context.Background(), tc.event)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+		})
+	}
+}
+
+func TestEventRetrieveAll(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.NewEventRepository(dbMiddleware, logger)
+
+	oID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	start := time.Now().Add(-time.Hour)
+	events := []audit.Event{
+		{Actor: "alice@example.com", Entity: audit.EntityPolicy, EntityID: "policy-1", Action: audit.ActionCreate, Timestamp: start},
+		{Actor: "bob@example.com", Entity: audit.EntityPolicy, EntityID: "policy-1", Action: audit.ActionUpdate, Timestamp: start.Add(time.Minute)},
+		{Actor: "bob@example.com", Entity: audit.EntitySink, EntityID: "sink-1", Action: audit.ActionRemove, Timestamp: start.Add(2 * time.Minute)},
+	}
+	for _, e := range events {
+		e.OwnerID = oID.String()
+		_, err := repo.Save(context.Background(), e)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	cases := map[string]struct {
+		pm    audit.PageMetadata
+		total uint64
+	}{
+		"retrieve all audit events": {
+			pm:    audit.PageMetadata{Limit: 10},
+			total: 3,
+		},
+		"retrieve audit events of an entity": {
+			pm:    audit.PageMetadata{Limit: 10, Entity: audit.EntityPolicy, EntityID: "policy-1"},
+			total: 2,
+		},
+		"retrieve audit events of an actor": {
+			pm:    audit.PageMetadata{Limit: 10, Actor: "bob@example.com"},
+			total: 2,
+		},
+		"retrieve audit events since a time": {
+			pm:    audit.PageMetadata{Limit: 10, Since: start.Add(90 * time.Second)},
+			total: 1,
+		},
+		"retrieve audit events with a page": {
+			pm:    audit.PageMetadata{Limit: 1},
+			total: 3,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			page, err := repo.RetrieveAll(context.Background(), oID.String(), tc.pm)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+			assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d", desc, tc.total, page.Total))
+			size := tc.total
+			if size > tc.pm.Limit {
+				size = tc.pm.Limit
+			}
+			assert.Len(t, page.Events, int(size), fmt.Sprintf("%s: expected %d events", desc, size))
+		})
+	}
+}
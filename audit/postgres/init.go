@@ -0,0 +1,67 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // required for SQL access
+	"github.com/orb-community/orb/pkg/config"
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Connect creates a connection to the PostgreSQL instance and applies any
+// unapplied database migrations. A non-nil error is returned to indicate
+// failure.
+func Connect(cfg config.PostgresConfig) (*sqlx.DB, error) {
+	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.Host, cfg.Port, cfg.User, cfg.DB, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+
+	db, err := sqlx.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "audit_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS audit_events (
+						id          UUID NOT NULL DEFAULT gen_random_uuid(),
+						mf_owner_id UUID NOT NULL,
+						actor_id    TEXT NOT NULL DEFAULT '',
+						actor       TEXT NOT NULL DEFAULT '',
+						service     TEXT NOT NULL DEFAULT '',
+						entity      TEXT NOT NULL,
+						entity_id   TEXT NOT NULL DEFAULT '',
+						action      TEXT NOT NULL,
+						diff        JSONB NOT NULL DEFAULT '{}',
+						ts_created  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+						PRIMARY KEY (id)
+					)`,
+					`CREATE INDEX ON audit_events (mf_owner_id, ts_created DESC)`,
+					`CREATE INDEX ON audit_events (mf_owner_id, entity, entity_id)`,
+					`CREATE INDEX ON audit_events (mf_owner_id, actor)`,
+				},
+				Down: []string{
+					"DROP TABLE audit_events",
+				},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+
+	return err
+}
@@ -0,0 +1,92 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/mainflux/mainflux"
+	"go.uber.org/zap"
+)
+
+const streamLen = 10000
+
+// Recorder publishes the audit events of a service
+type Recorder interface {
+	// Record publishes a change made with the token, the owner and actor are those of its identity. The change is
+	// already done, so failures are logged rather than returned
+	Record(ctx context.Context, token string, entity string, entityID string, action string, before interface{}, after interface{})
+	// RecordInternal publishes a change the service made on behalf of the owner, without a token of the actor
+	RecordInternal(ctx context.Context, ownerID string, actor string, entity string, entityID string, action string, before interface{}, after interface{})
+}
+
+var _ Recorder = (*streamRecorder)(nil)
+
+type streamRecorder struct {
+	service string
+	auth    mainflux.AuthServiceClient
+	client  *redis.Client
+	logger  *zap.Logger
+}
+
+// NewRecorder returns a recorder publishing the audit events of the service to the audit stream
+func NewRecorder(service string, auth mainflux.AuthServiceClient, client *redis.Client, logger *zap.Logger) Recorder {
+	return streamRecorder{
+		service: service,
+		auth:    auth,
+		client:  client,
+		logger:  logger.Named("audit_recorder"),
+	}
+}
+
+func (r streamRecorder) Record(ctx context.Context, token string, entity string, entityID string, action string, before interface{}, after interface{}) {
+	identifyCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	identity, err := r.auth.Identify(identifyCtx, &mainflux.Token{Value: token})
+	if err != nil {
+		r.logger.Error("failed to identify the actor of an audit event", zap.String("entity", entity),
+			zap.String("entity_id", entityID), zap.String("action", action), zap.Error(err))
+		return
+	}
+
+	r.publish(ctx, identity.GetId(), identity.GetEmail(), entity, entityID, action, before, after)
+}
+
+func (r streamRecorder) RecordInternal(ctx context.Context, ownerID string, actor string, entity string, entityID string, action string, before interface{}, after interface{}) {
+	r.publish(ctx, ownerID, actor, entity, entityID, action, before, after)
+}
+
+func (r streamRecorder) publish(ctx context.Context, ownerID string, actor string, entity string, entityID string, action string, before interface{}, after interface{}) {
+	event := Event{
+		OwnerID:   ownerID,
+		ActorID:   ownerID,
+		Actor:     actor,
+		Service:   r.service,
+		Entity:    entity,
+		EntityID:  entityID,
+		Action:    action,
+		Diff:      Diff(before, after),
+		Timestamp: time.Now(),
+	}
+	values, err := event.Encode()
+	if err != nil {
+		r.logger.Error("error encoding audit event", zap.Error(err))
+		return
+	}
+
+	record := &redis.XAddArgs{
+		Stream: StreamID,
+		MaxLen: streamLen,
+		Approx: true,
+		Values: values,
+	}
+	if err := r.client.XAdd(ctx, record).Err(); err != nil {
+		r.logger.Error("error sending event to audit event store", zap.String("entity", entity),
+			zap.String("entity_id", entityID), zap.String("action", action), zap.Error(err))
+	}
+}
@@ -0,0 +1,55 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package consumer
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/orb-community/orb/audit"
+	"github.com/orb-community/orb/pkg/streams"
+	"go.uber.org/zap"
+)
+
+const group = "orb.audit"
+
+type Subscriber interface {
+	Subscribe(context context.Context) error
+}
+
+type eventStore struct {
+	auditService audit.Service
+	client       *redis.Client
+	esconsumer   string
+	logger       *zap.Logger
+}
+
+func NewEventStore(auditService audit.Service, client *redis.Client, esconsumer string, logger *zap.Logger) Subscriber {
+	return eventStore{
+		auditService: auditService,
+		client:       client,
+		esconsumer:   esconsumer,
+		logger:       logger,
+	}
+}
+
+func (es eventStore) Subscribe(context context.Context) error {
+	return streams.Consume(context, es.client, audit.StreamID, group, es.esconsumer, es.logger, es.handle)
+}
+
+func (es eventStore) handle(ctx context.Context, msg redis.XMessage) error {
+	event, err := audit.Decode(msg.Values)
+	if err != nil {
+		// a malformed event never decodes, it is dropped rather than retried
+		es.logger.Error("failed to decode audit event", zap.String("message_id", msg.ID), zap.Error(err))
+		return nil
+	}
+	if err := es.auditService.SaveEvent(ctx, event); err != nil {
+		es.logger.Error("failed to save audit event", zap.String("entity", event.Entity),
+			zap.String("entity_id", event.EntityID), zap.String("action", event.Action), zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/orb-community/orb/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var _ Service = (*auditService)(nil)
+
+type auditService struct {
+	logger *zap.Logger
+	// for AuthN/AuthZ
+	auth mainflux.AuthServiceClient
+	repo Repository
+}
+
+// NewService returns the audit service
+func NewService(logger *zap.Logger, auth mainflux.AuthServiceClient, repo Repository) Service {
+	return &auditService{
+		logger: logger,
+		auth:   auth,
+		repo:   repo,
+	}
+}
+
+func (svc auditService) identify(token string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	res, err := svc.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", errors.Wrap(errors.ErrUnauthorizedAccess, err)
+	}
+
+	return res.GetId(), nil
+}
+
+func (svc auditService) ListEvents(ctx context.Context, token string, pm PageMetadata) (Page, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return Page{}, err
+	}
+
+	return svc.repo.RetrieveAll(ctx, ownerID, pm)
+}
+
+func (svc auditService) SaveEvent(ctx context.Context, e Event) error {
+	if e.OwnerID == "" || e.Entity == "" || e.Action == "" {
+		return ErrMalformedEvent
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+
+	_, err := svc.repo.Save(ctx, e)
+	return err
+}
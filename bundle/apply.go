@@ -0,0 +1,128 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package bundle
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"go.uber.org/zap"
+)
+
+// ErrApply indicates a change of the plan was rejected, the changes before it remain applied
+var ErrApply = errors.New("failed to apply change")
+
+// Client reads and changes the resources of the owner on the fleet, policies and sinks services
+type Client interface {
+	// State retrieves every sink, agent group, policy and dataset of the owner
+	State(ctx context.Context) (State, error)
+	// Create creates a resource of the given kind, returning its id
+	Create(ctx context.Context, kind string, body interface{}) (string, error)
+	// Update edits the resource of the given kind and id
+	Update(ctx context.Context, kind string, id string, body interface{}) error
+	// Delete removes the resource of the given kind and id
+	Delete(ctx context.Context, kind string, id string) error
+}
+
+type datasetBody struct {
+	Name         string     `json:"name"`
+	AgentGroupID string     `json:"agent_group_id,omitempty"`
+	PolicyID     string     `json:"agent_policy_id,omitempty"`
+	SinkIDs      []string   `json:"sink_ids"`
+	Tags         types.Tags `json:"tags"`
+}
+
+// Apply runs the changes of the plan in order, stopping at the first one which fails
+func Apply(ctx context.Context, logger *zap.Logger, c Client, p Plan) error {
+	ids := map[string]map[string]string{}
+	for kind, names := range p.ids {
+		ids[kind] = map[string]string{}
+		for name, id := range names {
+			ids[kind][name] = id
+		}
+	}
+
+	for _, ch := range p.Changes {
+		if err := applyChange(ctx, c, ch, ids); err != nil {
+			return errors.Wrap(ErrApply, errors.Wrap(fmt.Errorf("%s %s %q", ch.Action, ch.Kind, ch.Name), err))
+		}
+		logger.Info("applied change", zap.String("action", ch.Action), zap.String("kind", ch.Kind), zap.String("name", ch.Name))
+	}
+	return nil
+}
+
+func applyChange(ctx context.Context, c Client, ch Change, ids map[string]map[string]string) error {
+	switch ch.Action {
+	case ActionDelete:
+		if err := c.Delete(ctx, ch.Kind, ch.ID); err != nil {
+			return err
+		}
+		delete(ids[ch.Kind], ch.Name)
+		return nil
+	case ActionReplace:
+		if err := c.Delete(ctx, ch.Kind, ch.ID); err != nil {
+			return err
+		}
+		delete(ids[ch.Kind], ch.Name)
+	}
+
+	body, err := requestBody(ch, ids)
+	if err != nil {
+		return err
+	}
+	if ch.Action == ActionUpdate {
+		return c.Update(ctx, ch.Kind, ch.ID, body)
+	}
+	id, err := c.Create(ctx, ch.Kind, body)
+	if err != nil {
+		return err
+	}
+	ids[ch.Kind][ch.Name] = id
+	return nil
+}
+
+// requestBody builds the request of the service out of the declared resource, the references of datasets are
+// resolved to the ids of resources which already exist or were created earlier in the plan
+func requestBody(ch Change, ids map[string]map[string]string) (interface{}, error) {
+	switch r := ch.resource.(type) {
+	case Sink:
+		r.Tags = tagsOrEmpty(r.Tags)
+		return r, nil
+	case AgentGroup:
+		r.Tags = tagsOrEmpty(r.Tags)
+		return r, nil
+	case Policy:
+		r.Tags = tagsOrEmpty(r.Tags)
+		return r, nil
+	case Dataset:
+		body := datasetBody{
+			Name:    r.Name,
+			SinkIDs: make([]string, len(r.Sinks)),
+			Tags:    tagsOrEmpty(r.Tags),
+		}
+		for i, name := range r.Sinks {
+			id, ok := ids[KindSink][name]
+			if !ok {
+				return nil, errors.Wrap(ErrUnresolvedReference, fmt.Errorf("sink %q", name))
+			}
+			body.SinkIDs[i] = id
+		}
+		if ch.Action == ActionUpdate {
+			// the agent group and policy of a dataset can not be updated
+			return body, nil
+		}
+		var ok bool
+		if body.AgentGroupID, ok = ids[KindAgentGroup][r.AgentGroup]; !ok {
+			return nil, errors.Wrap(ErrUnresolvedReference, fmt.Errorf("agent group %q", r.AgentGroup))
+		}
+		if body.PolicyID, ok = ids[KindPolicy][r.Policy]; !ok {
+			return nil, errors.Wrap(ErrUnresolvedReference, fmt.Errorf("policy %q", r.Policy))
+		}
+		return body, nil
+	}
+	return nil, fmt.Errorf("unknown resource of %s %q", ch.Kind, ch.Name)
+}
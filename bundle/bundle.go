@@ -0,0 +1,164 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package bundle
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/orb-community/orb/fleet"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+)
+
+const (
+	KindSink       = "sink"
+	KindAgentGroup = "agent_group"
+	KindPolicy     = "policy"
+	KindDataset    = "dataset"
+)
+
+var (
+	// ErrInvalidBundle indicates a bundle that can not be parsed or holds invalid resources
+	ErrInvalidBundle = errors.New("invalid bundle")
+	// ErrUnresolvedReference indicates a dataset referencing a resource which is neither in the bundle nor in the fleet
+	ErrUnresolvedReference = errors.New("unresolved reference")
+)
+
+// Bundle declares the sinks, agent groups, policies and datasets of an owner, every resource is identified by its name
+type Bundle struct {
+	Sinks       []Sink       `json:"sinks,omitempty"`
+	AgentGroups []AgentGroup `json:"agent_groups,omitempty"`
+	Policies    []Policy     `json:"policies,omitempty"`
+	Datasets    []Dataset    `json:"datasets,omitempty"`
+}
+
+type Sink struct {
+	ID          string         `json:"id,omitempty"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Tags        types.Tags     `json:"tags,omitempty"`
+	Backend     string         `json:"backend"`
+	Config      types.Metadata `json:"config"`
+}
+
+type AgentGroup struct {
+	ID          string            `json:"id,omitempty"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Tags        types.Tags        `json:"tags,omitempty"`
+	Selector    fleet.TagSelector `json:"selector,omitempty"`
+}
+
+type Policy struct {
+	ID            string         `json:"id,omitempty"`
+	Name          string         `json:"name"`
+	Description   string         `json:"description,omitempty"`
+	Tags          types.Tags     `json:"tags,omitempty"`
+	Backend       string         `json:"backend"`
+	SchemaVersion string         `json:"schema_version,omitempty"`
+	Policy        types.Metadata `json:"policy"`
+}
+
+// Dataset references its agent group, policy and sinks by name
+type Dataset struct {
+	ID         string     `json:"id,omitempty"`
+	Name       string     `json:"name"`
+	Tags       types.Tags `json:"tags,omitempty"`
+	AgentGroup string     `json:"agent_group"`
+	Policy     string     `json:"policy"`
+	Sinks      []string   `json:"sinks"`
+}
+
+// Load reads a YAML bundle from the given file, "-" reads the standard input
+func Load(path string) (Bundle, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return Bundle{}, err
+		}
+		defer f.Close()
+		r = f
+	}
+	return Parse(r)
+}
+
+// Parse decodes and validates a YAML bundle
+func Parse(r io.Reader) (Bundle, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Bundle{}, err
+	}
+	var b Bundle
+	if err := yaml.Unmarshal(data, &b); err != nil {
+		return Bundle{}, errors.Wrap(ErrInvalidBundle, err)
+	}
+	if err := b.Validate(); err != nil {
+		return Bundle{}, err
+	}
+	return b, nil
+}
+
+// Validate checks every resource carries what its service requires and names are unique within each kind.
+// References of datasets are resolved by the plan, as they may target resources which are not part of the bundle
+func (b Bundle) Validate() error {
+	names := map[string]map[string]bool{
+		KindSink:       {},
+		KindAgentGroup: {},
+		KindPolicy:     {},
+		KindDataset:    {},
+	}
+	unique := func(kind, name string) error {
+		if _, err := types.NewIdentifier(name); err != nil {
+			return errors.Wrap(ErrInvalidBundle, fmt.Errorf("%s %q: %w", kind, name, err))
+		}
+		if names[kind][name] {
+			return errors.Wrap(ErrInvalidBundle, fmt.Errorf("%s %q is declared more than once", kind, name))
+		}
+		names[kind][name] = true
+		return nil
+	}
+
+	for _, s := range b.Sinks {
+		if err := unique(KindSink, s.Name); err != nil {
+			return err
+		}
+		if s.Backend == "" || len(s.Config) == 0 {
+			return errors.Wrap(ErrInvalidBundle, fmt.Errorf("sink %q requires a backend and a config", s.Name))
+		}
+	}
+	for _, g := range b.AgentGroups {
+		if err := unique(KindAgentGroup, g.Name); err != nil {
+			return err
+		}
+		if len(g.Tags) == 0 && len(g.Selector) == 0 {
+			return errors.Wrap(ErrInvalidBundle, fmt.Errorf("agent group %q requires tags or a selector", g.Name))
+		}
+		if err := g.Selector.Validate(); err != nil {
+			return errors.Wrap(ErrInvalidBundle, fmt.Errorf("agent group %q: %w", g.Name, err))
+		}
+	}
+	for _, p := range b.Policies {
+		if err := unique(KindPolicy, p.Name); err != nil {
+			return err
+		}
+		if p.Backend == "" || len(p.Policy) == 0 {
+			return errors.Wrap(ErrInvalidBundle, fmt.Errorf("policy %q requires a backend and a policy", p.Name))
+		}
+	}
+	for _, d := range b.Datasets {
+		if err := unique(KindDataset, d.Name); err != nil {
+			return err
+		}
+		if d.AgentGroup == "" || d.Policy == "" || len(d.Sinks) == 0 {
+			return errors.Wrap(ErrInvalidBundle, fmt.Errorf("dataset %q requires an agent group, a policy and sinks", d.Name))
+		}
+	}
+
+	return nil
+}
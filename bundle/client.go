@@ -0,0 +1,259 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+)
+
+// pageLimit is the largest page the services list
+const pageLimit = 100
+
+// ErrRequest indicates a request to the Orb API failed
+var ErrRequest = errors.New("orb api request failed")
+
+var _ Client = (*httpClient)(nil)
+
+// paths of each kind of resource, relative to the Orb API
+var paths = map[string]string{
+	KindSink:       "/sinks",
+	KindAgentGroup: "/agent_groups",
+	KindPolicy:     "/policies/agent",
+	KindDataset:    "/policies/dataset",
+}
+
+type httpClient struct {
+	apiURL string
+	token  string
+	client *http.Client
+}
+
+// NewClient returns a client of the Orb API at apiURL, such as https://orb.live/api/v1, authenticated by token
+func NewClient(apiURL string, token string, timeout time.Duration) Client {
+	return &httpClient{
+		apiURL: strings.TrimSuffix(apiURL, "/"),
+		token:  token,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+type pageRes struct {
+	Total uint64 `json:"total"`
+}
+
+type sinkRes struct {
+	Sink
+	Format     string `json:"format,omitempty"`
+	ConfigData string `json:"config_data,omitempty"`
+}
+
+type policyRes struct {
+	Policy
+	Format     string `json:"format,omitempty"`
+	PolicyData string `json:"policy_data,omitempty"`
+}
+
+type datasetRes struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	AgentGroupID string     `json:"agent_group_id"`
+	PolicyID     string     `json:"agent_policy_id"`
+	SinkIDs      []string   `json:"sink_ids"`
+	Tags         types.Tags `json:"tags"`
+}
+
+func (c *httpClient) State(ctx context.Context) (State, error) {
+	var s State
+
+	var sinks []sinkRes
+	if err := c.list(ctx, KindSink, "sinks", &sinks); err != nil {
+		return State{}, err
+	}
+	for _, r := range sinks {
+		if len(r.Config) == 0 && r.Format == "yaml" {
+			if err := yaml.Unmarshal([]byte(r.ConfigData), &r.Sink.Config); err != nil {
+				return State{}, errors.Wrap(ErrRequest, fmt.Errorf("config of sink %q: %s", r.Name, err))
+			}
+		}
+		s.Sinks = append(s.Sinks, r.Sink)
+	}
+
+	if err := c.list(ctx, KindAgentGroup, "agentGroups", &s.AgentGroups); err != nil {
+		return State{}, err
+	}
+
+	// the policy itself is only returned by the view of each policy
+	var policies []policyRes
+	if err := c.list(ctx, KindPolicy, "data", &policies); err != nil {
+		return State{}, err
+	}
+	for _, r := range policies {
+		var view policyRes
+		if err := c.do(ctx, http.MethodGet, paths[KindPolicy]+"/"+r.ID, nil, &view); err != nil {
+			return State{}, err
+		}
+		if len(view.Policy.Policy) == 0 && view.Format == "yaml" {
+			if err := yaml.Unmarshal([]byte(view.PolicyData), &view.Policy.Policy); err != nil {
+				return State{}, errors.Wrap(ErrRequest, fmt.Errorf("policy %q: %s", view.Name, err))
+			}
+		}
+		s.Policies = append(s.Policies, view.Policy)
+	}
+
+	var datasets []datasetRes
+	if err := c.list(ctx, KindDataset, "datasets", &datasets); err != nil {
+		return State{}, err
+	}
+	// references are resolved to names, a dangling reference keeps its id so it never matches a declared name
+	names := func(kind string, id string) string {
+		switch kind {
+		case KindSink:
+			for _, r := range s.Sinks {
+				if r.ID == id {
+					return r.Name
+				}
+			}
+		case KindAgentGroup:
+			for _, r := range s.AgentGroups {
+				if r.ID == id {
+					return r.Name
+				}
+			}
+		case KindPolicy:
+			for _, r := range s.Policies {
+				if r.ID == id {
+					return r.Name
+				}
+			}
+		}
+		return id
+	}
+	for _, r := range datasets {
+		d := Dataset{
+			ID:         r.ID,
+			Name:       r.Name,
+			Tags:       r.Tags,
+			AgentGroup: names(KindAgentGroup, r.AgentGroupID),
+			Policy:     names(KindPolicy, r.PolicyID),
+		}
+		for _, id := range r.SinkIDs {
+			d.Sinks = append(d.Sinks, names(KindSink, id))
+		}
+		s.Datasets = append(s.Datasets, d)
+	}
+
+	return s, nil
+}
+
+func (c *httpClient) Create(ctx context.Context, kind string, body interface{}) (string, error) {
+	var res struct {
+		ID string `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, paths[kind], body, &res); err != nil {
+		return "", err
+	}
+	return res.ID, nil
+}
+
+func (c *httpClient) Update(ctx context.Context, kind string, id string, body interface{}) error {
+	return c.do(ctx, http.MethodPut, paths[kind]+"/"+id, body, nil)
+}
+
+func (c *httpClient) Delete(ctx context.Context, kind string, id string) error {
+	return c.do(ctx, http.MethodDelete, paths[kind]+"/"+id, nil, nil)
+}
+
+// list retrieves every page of the resources of the given kind into items, a pointer to a slice
+func (c *httpClient) list(ctx context.Context, kind string, key string, items interface{}) error {
+	var all []json.RawMessage
+	for offset := uint64(0); ; offset += pageLimit {
+		var page map[string]json.RawMessage
+		path := fmt.Sprintf("%s?limit=%d&offset=%d", paths[kind], pageLimit, offset)
+		if err := c.do(ctx, http.MethodGet, path, nil, &page); err != nil {
+			return err
+		}
+
+		var meta pageRes
+		var raw []json.RawMessage
+		if err := json.Unmarshal(page[key], &raw); err != nil {
+			return errors.Wrap(ErrRequest, err)
+		}
+		if b, ok := page["total"]; ok {
+			if err := json.Unmarshal(b, &meta.Total); err != nil {
+				return errors.Wrap(ErrRequest, err)
+			}
+		}
+		all = append(all, raw...)
+		if len(raw) < pageLimit || uint64(len(all)) >= meta.Total {
+			break
+		}
+	}
+
+	b, err := json.Marshal(all)
+	if err != nil {
+		return errors.Wrap(ErrRequest, err)
+	}
+	if err := json.Unmarshal(b, items); err != nil {
+		return errors.Wrap(ErrRequest, err)
+	}
+	return nil
+}
+
+func (c *httpClient) do(ctx context.Context, method string, path string, body interface{}, res interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(ErrRequest, err)
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.apiURL+path, reader)
+	if err != nil {
+		return errors.Wrap(ErrRequest, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return errors.Wrap(ErrRequest, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(ErrRequest, err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		var e types.ErrorRes
+		if json.Unmarshal(data, &e) != nil || e.Err == "" {
+			e.Err = http.StatusText(resp.StatusCode)
+		}
+		return errors.Wrap(ErrRequest, fmt.Errorf("%s %s: %d %s", method, path, resp.StatusCode, e.Err))
+	}
+
+	if res == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, res); err != nil {
+		return errors.Wrap(ErrRequest, err)
+	}
+	return nil
+}
@@ -0,0 +1,407 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+)
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	// ActionReplace deletes and creates a dataset again, as its agent group and policy can not be updated
+	ActionReplace = "replace"
+	ActionDelete  = "delete"
+)
+
+// ErrImmutableField indicates a change to a field its service can not update
+var ErrImmutableField = errors.New("field can not be updated")
+
+// State is the current sinks, agent groups, policies and datasets of the owner, the references of the
+// datasets are resolved to names
+type State struct {
+	Sinks       []Sink
+	AgentGroups []AgentGroup
+	Policies    []Policy
+	Datasets    []Dataset
+}
+
+// Change is a single step of a plan
+type Change struct {
+	Action string
+	Kind   string
+	Name   string
+	// ID of the resource in the fleet, empty when it is created
+	ID string
+	// Diff lists the fields changed by an update or a replacement
+	Diff []string
+	// resource is the declared resource, nil when it is deleted
+	resource interface{}
+}
+
+// Plan holds the changes bringing the fleet to the bundle, in the order they must be applied: sinks, agent
+// groups and policies are created or updated before the datasets referencing them, and deleted after
+type Plan struct {
+	Changes []Change
+	// ids resolves the names of the resources in the fleet, per kind
+	ids map[string]map[string]string
+}
+
+// NewPlan compares the bundle to the state of the fleet. Resources of the fleet which are not declared in the
+// bundle are left untouched, unless prune is set
+func NewPlan(b Bundle, s State, prune bool) (Plan, error) {
+	p := Plan{ids: map[string]map[string]string{
+		KindSink:       {},
+		KindAgentGroup: {},
+		KindPolicy:     {},
+		KindDataset:    {},
+	}}
+
+	sinks := map[string]Sink{}
+	for _, r := range s.Sinks {
+		sinks[r.Name] = r
+		p.ids[KindSink][r.Name] = r.ID
+	}
+	groups := map[string]AgentGroup{}
+	for _, r := range s.AgentGroups {
+		groups[r.Name] = r
+		p.ids[KindAgentGroup][r.Name] = r.ID
+	}
+	policies := map[string]Policy{}
+	for _, r := range s.Policies {
+		policies[r.Name] = r
+		p.ids[KindPolicy][r.Name] = r.ID
+	}
+	datasets := map[string]Dataset{}
+	for _, r := range s.Datasets {
+		datasets[r.Name] = r
+		p.ids[KindDataset][r.Name] = r.ID
+	}
+
+	if err := b.resolve(p.ids, prune); err != nil {
+		return Plan{}, err
+	}
+
+	for _, want := range b.Sinks {
+		have, ok := sinks[want.Name]
+		if !ok {
+			p.add(ActionCreate, KindSink, want.Name, "", nil, want)
+			continue
+		}
+		if want.Backend != have.Backend {
+			return Plan{}, errors.Wrap(ErrImmutableField, fmt.Errorf("backend of sink %q, declare a new sink instead", want.Name))
+		}
+		if diff := sinkDiff(want, have); len(diff) > 0 {
+			p.add(ActionUpdate, KindSink, want.Name, have.ID, diff, want)
+		}
+	}
+	for _, want := range b.AgentGroups {
+		have, ok := groups[want.Name]
+		if !ok {
+			p.add(ActionCreate, KindAgentGroup, want.Name, "", nil, want)
+			continue
+		}
+		diff := diffField("description", want.Description, have.Description)
+		diff = append(diff, diffField("tags", want.Tags, have.Tags)...)
+		diff = append(diff, diffField("selector", want.Selector, have.Selector)...)
+		if len(diff) > 0 {
+			p.add(ActionUpdate, KindAgentGroup, want.Name, have.ID, diff, want)
+		}
+	}
+	for _, want := range b.Policies {
+		have, ok := policies[want.Name]
+		if !ok {
+			p.add(ActionCreate, KindPolicy, want.Name, "", nil, want)
+			continue
+		}
+		if want.Backend != have.Backend {
+			return Plan{}, errors.Wrap(ErrImmutableField, fmt.Errorf("backend of policy %q, declare a new policy instead", want.Name))
+		}
+		diff := diffField("description", want.Description, have.Description)
+		diff = append(diff, diffField("tags", want.Tags, have.Tags)...)
+		diff = append(diff, diffField("policy", want.Policy, have.Policy)...)
+		if len(diff) > 0 {
+			p.add(ActionUpdate, KindPolicy, want.Name, have.ID, diff, want)
+		}
+	}
+
+	declared := b.names()
+	if prune {
+		p.prune(KindDataset, declared)
+	}
+	for _, want := range b.Datasets {
+		have, ok := datasets[want.Name]
+		if !ok {
+			p.add(ActionCreate, KindDataset, want.Name, "", nil, want)
+			continue
+		}
+		moved := diffField("agent_group", want.AgentGroup, have.AgentGroup)
+		moved = append(moved, diffField("policy", want.Policy, have.Policy)...)
+		diff := append(moved, diffField("tags", want.Tags, have.Tags)...)
+		diff = append(diff, diffField("sinks", sortedNames(want.Sinks), sortedNames(have.Sinks))...)
+		switch {
+		case len(moved) > 0:
+			p.add(ActionReplace, KindDataset, want.Name, have.ID, diff, want)
+		case len(diff) > 0:
+			p.add(ActionUpdate, KindDataset, want.Name, have.ID, diff, want)
+		}
+	}
+	if prune {
+		p.prune(KindPolicy, declared)
+		p.prune(KindAgentGroup, declared)
+		p.prune(KindSink, declared)
+	}
+
+	return p, nil
+}
+
+// Empty tells whether the fleet already matches the bundle
+func (p Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Count returns how many changes of the plan have the given action
+func (p Plan) Count(action string) int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Write prints the plan as a diff, one line per change followed by the changed fields
+func (p Plan) Write(w io.Writer) error {
+	if p.Empty() {
+		_, err := fmt.Fprintln(w, "No changes, the fleet matches the bundle.")
+		return err
+	}
+
+	symbols := map[string]string{
+		ActionCreate:  "+",
+		ActionUpdate:  "~",
+		ActionReplace: "-/+",
+		ActionDelete:  "-",
+	}
+	var sb strings.Builder
+	for _, c := range p.Changes {
+		fmt.Fprintf(&sb, "%s %s %s %q\n", symbols[c.Action], c.Action, c.Kind, c.Name)
+		for _, d := range c.Diff {
+			fmt.Fprintf(&sb, "      %s\n", d)
+		}
+	}
+	fmt.Fprintf(&sb, "\nPlan: %d to create, %d to update, %d to replace, %d to delete.\n",
+		p.Count(ActionCreate), p.Count(ActionUpdate), p.Count(ActionReplace), p.Count(ActionDelete))
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (p *Plan) add(action, kind, name, id string, diff []string, resource interface{}) {
+	p.Changes = append(p.Changes, Change{
+		Action:   action,
+		Kind:     kind,
+		Name:     name,
+		ID:       id,
+		Diff:     diff,
+		resource: resource,
+	})
+}
+
+// prune deletes the resources of the fleet which are not declared in the bundle, by name
+func (p *Plan) prune(kind string, declared map[string]map[string]bool) {
+	var names []string
+	for name := range p.ids[kind] {
+		if !declared[kind][name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p.add(ActionDelete, kind, name, p.ids[kind][name], nil, nil)
+	}
+}
+
+func (b Bundle) names() map[string]map[string]bool {
+	names := map[string]map[string]bool{
+		KindSink:       {},
+		KindAgentGroup: {},
+		KindPolicy:     {},
+		KindDataset:    {},
+	}
+	for _, r := range b.Sinks {
+		names[KindSink][r.Name] = true
+	}
+	for _, r := range b.AgentGroups {
+		names[KindAgentGroup][r.Name] = true
+	}
+	for _, r := range b.Policies {
+		names[KindPolicy][r.Name] = true
+	}
+	for _, r := range b.Datasets {
+		names[KindDataset][r.Name] = true
+	}
+	return names
+}
+
+// resolve checks every reference of the datasets targets a resource of the bundle, or of the fleet when it is
+// not pruned
+func (b Bundle) resolve(ids map[string]map[string]string, prune bool) error {
+	declared := b.names()
+	exists := func(kind, name string) bool {
+		if declared[kind][name] {
+			return true
+		}
+		_, ok := ids[kind][name]
+		return ok && !prune
+	}
+
+	for _, d := range b.Datasets {
+		if !exists(KindAgentGroup, d.AgentGroup) {
+			return errors.Wrap(ErrUnresolvedReference, fmt.Errorf("agent group %q of dataset %q", d.AgentGroup, d.Name))
+		}
+		if !exists(KindPolicy, d.Policy) {
+			return errors.Wrap(ErrUnresolvedReference, fmt.Errorf("policy %q of dataset %q", d.Policy, d.Name))
+		}
+		for _, s := range d.Sinks {
+			if !exists(KindSink, s) {
+				return errors.Wrap(ErrUnresolvedReference, fmt.Errorf("sink %q of dataset %q", s, d.Name))
+			}
+		}
+	}
+	return nil
+}
+
+// sinkDiff compares only the config keys declared in the bundle, as the sinks service adds its own keys.
+// Secrets are omitted from the config the service returns, so a declared secret is only sent along with
+// another change of the sink
+func sinkDiff(want, have Sink) []string {
+	diff := diffField("description", want.Description, have.Description)
+	diff = append(diff, diffField("tags", want.Tags, have.Tags)...)
+
+	config := normalize(want.Config)
+	current := normalize(have.Config)
+	wantAuth, wok := mapAt(config, "authentication")
+	haveAuth, hok := mapAt(current, "authentication")
+	if wok && hok {
+		for k, v := range haveAuth {
+			if v == "" {
+				delete(wantAuth, k)
+			}
+		}
+	}
+
+	return append(diff, diffValues("config", config, current, true)...)
+}
+
+func mapAt(v interface{}, key string) (map[string]interface{}, bool) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	sub, ok := m[key].(map[string]interface{})
+	return sub, ok
+}
+
+func diffField(path string, want, have interface{}) []string {
+	return diffValues(path, normalize(want), normalize(have), false)
+}
+
+// diffValues walks both values, as decoded from JSON, and lists every differing leaf. A subset comparison ignores
+// the keys of maps which are only present in the current value
+func diffValues(path string, want, have interface{}, subset bool) []string {
+	if isEmpty(want) && isEmpty(have) {
+		return nil
+	}
+
+	wm, wok := want.(map[string]interface{})
+	hm, hok := have.(map[string]interface{})
+	if wok && hok {
+		keys := make([]string, 0, len(wm)+len(hm))
+		for k := range wm {
+			keys = append(keys, k)
+		}
+		if !subset {
+			for k := range hm {
+				if _, ok := wm[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+		}
+		sort.Strings(keys)
+
+		var diff []string
+		for _, k := range keys {
+			diff = append(diff, diffValues(path+"."+k, wm[k], hm[k], subset)...)
+		}
+		return diff
+	}
+
+	if reflect.DeepEqual(want, have) {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s: %s => %s", path, formatValue(have), formatValue(want))}
+}
+
+// normalize round trips the value through JSON so declared and current values compare alike
+func normalize(v interface{}) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var n interface{}
+	if err := json.Unmarshal(b, &n); err != nil {
+		return v
+	}
+	return n
+}
+
+func isEmpty(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case map[string]interface{}:
+		return len(t) == 0
+	case []interface{}:
+		return len(t) == 0
+	}
+	return false
+}
+
+func formatValue(v interface{}) string {
+	if isEmpty(v) {
+		return "(none)"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+func sortedNames(names []string) []string {
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// tagsOrEmpty sends empty tags rather than null, which the services reject
+func tagsOrEmpty(tags types.Tags) types.Tags {
+	if tags == nil {
+		return types.Tags{}
+	}
+	return tags
+}
@@ -0,0 +1,333 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package bundle_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/orb-community/orb/bundle"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const bundleYAML = `
+sinks:
+  - name: prom-us
+    backend: prometheus
+    config:
+      exporter:
+        remote_host: https://prom.example.com/api/v1/write
+      authentication:
+        type: basicauth
+        username: orb
+        password: s3cr3t
+agent_groups:
+  - name: dns-us
+    tags:
+      region: us
+      node_type: dns
+policies:
+  - name: dns-basic
+    backend: pktvisor
+    policy:
+      handlers:
+        modules:
+          dns:
+            type: dns
+datasets:
+  - name: dns-us-basic
+    agent_group: dns-us
+    policy: dns-basic
+    sinks: [prom-us]
+`
+
+func currentState() bundle.State {
+	return bundle.State{
+		Sinks: []bundle.Sink{{
+			ID:      "sink-1",
+			Name:    "prom-us",
+			Backend: "prometheus",
+			Config: types.Metadata{
+				"exporter":       map[string]interface{}{"remote_host": "https://prom.example.com/api/v1/write"},
+				"authentication": map[string]interface{}{"type": "basicauth", "username": "orb", "password": ""},
+				"opentelemetry":  "enabled",
+			},
+		}},
+		AgentGroups: []bundle.AgentGroup{{
+			ID:   "group-1",
+			Name: "dns-us",
+			Tags: types.Tags{"region": "us", "node_type": "dns"},
+		}},
+		Policies: []bundle.Policy{{
+			ID:      "policy-1",
+			Name:    "dns-basic",
+			Backend: "pktvisor",
+			Policy:  types.Metadata{"handlers": map[string]interface{}{"modules": map[string]interface{}{"dns": map[string]interface{}{"type": "dns"}}}},
+		}},
+		Datasets: []bundle.Dataset{{
+			ID:         "dataset-1",
+			Name:       "dns-us-basic",
+			AgentGroup: "dns-us",
+			Policy:     "dns-basic",
+			Sinks:      []string{"prom-us"},
+		}},
+	}
+}
+
+type change struct {
+	action string
+	kind   string
+	name   string
+}
+
+func changesOf(p bundle.Plan) []change {
+	var changes []change
+	for _, c := range p.Changes {
+		changes = append(changes, change{c.Action, c.Kind, c.Name})
+	}
+	return changes
+}
+
+func TestParse(t *testing.T) {
+	cases := map[string]struct {
+		yaml string
+		err  error
+	}{
+		"parse a valid bundle": {
+			yaml: bundleYAML,
+			err:  nil,
+		},
+		"parse a bundle with a duplicated sink": {
+			yaml: "sinks:\n  - {name: s1, backend: prometheus, config: {a: b}}\n  - {name: s1, backend: prometheus, config: {a: b}}\n",
+			err:  bundle.ErrInvalidBundle,
+		},
+		"parse a bundle with an invalid name": {
+			yaml: "agent_groups:\n  - {name: 'not valid', tags: {a: b}}\n",
+			err:  bundle.ErrInvalidBundle,
+		},
+		"parse a bundle with a dataset without sinks": {
+			yaml: "datasets:\n  - {name: d1, agent_group: g1, policy: p1}\n",
+			err:  bundle.ErrInvalidBundle,
+		},
+		"parse a bundle with an invalid selector": {
+			yaml: "agent_groups:\n  - {name: g1, selector: [{key: region, operator: in}]}\n",
+			err:  bundle.ErrInvalidBundle,
+		},
+		"parse an invalid document": {
+			yaml: "sinks: {",
+			err:  bundle.ErrInvalidBundle,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			_, err := bundle.Parse(strings.NewReader(tc.yaml))
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+		})
+	}
+}
+
+func TestNewPlan(t *testing.T) {
+	b, err := bundle.Parse(strings.NewReader(bundleYAML))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	changedState := currentState()
+	changedState.Sinks[0].Description = "old"
+	changedState.AgentGroups[0].Tags = types.Tags{"region": "eu", "node_type": "dns"}
+	changedState.Datasets[0].Policy = "dns-old"
+	changedState.Policies = append(changedState.Policies, bundle.Policy{ID: "policy-2", Name: "dns-old", Backend: "pktvisor"})
+
+	extraState := currentState()
+	extraState.Sinks = append(extraState.Sinks, bundle.Sink{ID: "sink-2", Name: "prom-eu", Backend: "prometheus"})
+	extraState.Datasets = append(extraState.Datasets, bundle.Dataset{ID: "dataset-2", Name: "dns-eu", AgentGroup: "dns-us",
+		Policy: "dns-basic", Sinks: []string{"prom-eu"}})
+
+	orphan := b
+	orphan.Datasets = []bundle.Dataset{{Name: "dns-eu", AgentGroup: "dns-us", Policy: "dns-basic", Sinks: []string{"prom-eu"}}}
+
+	otherBackend := currentState()
+	otherBackend.Sinks[0].Backend = "otlphttp"
+
+	cases := map[string]struct {
+		bundle  bundle.Bundle
+		state   bundle.State
+		prune   bool
+		changes []change
+		err     error
+	}{
+		"plan against an empty fleet creates in dependency order": {
+			bundle: b,
+			state:  bundle.State{},
+			changes: []change{
+				{bundle.ActionCreate, bundle.KindSink, "prom-us"},
+				{bundle.ActionCreate, bundle.KindAgentGroup, "dns-us"},
+				{bundle.ActionCreate, bundle.KindPolicy, "dns-basic"},
+				{bundle.ActionCreate, bundle.KindDataset, "dns-us-basic"},
+			},
+			err: nil,
+		},
+		"plan against a matching fleet ignores secrets and keys of the service": {
+			bundle:  b,
+			state:   currentState(),
+			changes: nil,
+			err:     nil,
+		},
+		"plan against a changed fleet updates and replaces": {
+			bundle: b,
+			state:  changedState,
+			changes: []change{
+				{bundle.ActionUpdate, bundle.KindSink, "prom-us"},
+				{bundle.ActionUpdate, bundle.KindAgentGroup, "dns-us"},
+				{bundle.ActionReplace, bundle.KindDataset, "dns-us-basic"},
+			},
+			err: nil,
+		},
+		"plan without prune keeps undeclared resources": {
+			bundle:  b,
+			state:   extraState,
+			changes: nil,
+			err:     nil,
+		},
+		"plan with prune deletes datasets before sinks": {
+			bundle: b,
+			state:  extraState,
+			prune:  true,
+			changes: []change{
+				{bundle.ActionDelete, bundle.KindDataset, "dns-eu"},
+				{bundle.ActionDelete, bundle.KindSink, "prom-eu"},
+			},
+			err: nil,
+		},
+		"plan with a reference to an undeclared resource of the fleet": {
+			bundle:  orphan,
+			state:   extraState,
+			changes: nil,
+			err:     nil,
+		},
+		"plan with prune and a reference to an undeclared resource": {
+			bundle: orphan,
+			state:  extraState,
+			prune:  true,
+			err:    bundle.ErrUnresolvedReference,
+		},
+		"plan with a reference to a missing resource": {
+			bundle: orphan,
+			state:  currentState(),
+			err:    bundle.ErrUnresolvedReference,
+		},
+		"plan changing the backend of a sink": {
+			bundle: b,
+			state:  otherBackend,
+			err:    bundle.ErrImmutableField,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			p, err := bundle.NewPlan(tc.bundle, tc.state, tc.prune)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+			if err == nil {
+				assert.Equal(t, tc.changes, changesOf(p), fmt.Sprintf("%s: unexpected changes", desc))
+			}
+		})
+	}
+}
+
+func TestPlanWrite(t *testing.T) {
+	b, err := bundle.Parse(strings.NewReader(bundleYAML))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	state := currentState()
+	state.AgentGroups[0].Tags = types.Tags{"region": "eu", "node_type": "dns"}
+
+	p, err := bundle.NewPlan(b, state, false)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	var out strings.Builder
+	require.Nil(t, p.Write(&out), "unexpected error writing the plan")
+	assert.Contains(t, out.String(), `~ update agent_group "dns-us"`)
+	assert.Contains(t, out.String(), `tags.region: "eu" => "us"`)
+	assert.Contains(t, out.String(), "Plan: 0 to create, 1 to update, 0 to replace, 0 to delete.")
+}
+
+type request struct {
+	method string
+	kind   string
+	id     string
+	body   interface{}
+}
+
+type fakeClient struct {
+	state    bundle.State
+	requests []request
+	fail     string
+}
+
+func (c *fakeClient) State(_ context.Context) (bundle.State, error) {
+	return c.state, nil
+}
+
+func (c *fakeClient) Create(_ context.Context, kind string, body interface{}) (string, error) {
+	c.requests = append(c.requests, request{"create", kind, "", body})
+	if kind == c.fail {
+		return "", errors.New("rejected")
+	}
+	return fmt.Sprintf("new-%s-%d", kind, len(c.requests)), nil
+}
+
+func (c *fakeClient) Update(_ context.Context, kind string, id string, body interface{}) error {
+	c.requests = append(c.requests, request{"update", kind, id, body})
+	return nil
+}
+
+func (c *fakeClient) Delete(_ context.Context, kind string, id string) error {
+	c.requests = append(c.requests, request{"delete", kind, id, nil})
+	return nil
+}
+
+func TestApply(t *testing.T) {
+	b, err := bundle.Parse(strings.NewReader(bundleYAML))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cli := &fakeClient{}
+	p, err := bundle.NewPlan(b, bundle.State{}, false)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = bundle.Apply(context.Background(), zap.NewNop(), cli, p)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	require.Len(t, cli.requests, 4, "expected a request per created resource")
+	body, err := json.Marshal(cli.requests[3].body)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, `{"name":"dns-us-basic","agent_group_id":"new-agent_group-2","agent_policy_id":"new-policy-3","sink_ids":["new-sink-1"],"tags":{}}`,
+		string(body), "expected the dataset to reference the created resources")
+
+	state := currentState()
+	state.Datasets[0].Policy = "dns-old"
+	state.Policies = append(state.Policies, bundle.Policy{ID: "policy-2", Name: "dns-old", Backend: "pktvisor"})
+	cli = &fakeClient{}
+	p, err = bundle.NewPlan(b, state, true)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = bundle.Apply(context.Background(), zap.NewNop(), cli, p)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	require.Len(t, cli.requests, 3, "expected the dataset to be replaced before the policy is pruned")
+	assert.Equal(t, request{"delete", bundle.KindDataset, "dataset-1", nil}, cli.requests[0])
+	assert.Equal(t, "create", cli.requests[1].method)
+	assert.Equal(t, request{"delete", bundle.KindPolicy, "policy-2", nil}, cli.requests[2])
+
+	cli = &fakeClient{fail: bundle.KindPolicy}
+	p, err = bundle.NewPlan(b, bundle.State{}, false)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = bundle.Apply(context.Background(), zap.NewNop(), cli, p)
+	assert.True(t, errors.Contains(err, bundle.ErrApply), fmt.Sprintf("expected %s got %s", bundle.ErrApply, err))
+	assert.Len(t, cli.requests, 3, "expected apply to stop at the failed change")
+}
@@ -142,6 +142,7 @@ func mergeOrError(path string) {
 	// note: viper seems to require a default (or a BindEnv) to be overridden by environment variables
 	v.SetDefault("orb.cloud.api.address", "https://orb.live")
 	v.SetDefault("orb.cloud.api.token", "")
+	v.SetDefault("orb.cloud.api.enrollment_token", "")
 	v.SetDefault("orb.cloud.config.agent_name", "")
 	v.SetDefault("orb.cloud.config.auto_provision", true)
 	v.SetDefault("orb.cloud.mqtt.address", "tls://agents.orb.live:8883")
@@ -152,7 +153,14 @@ func mergeOrError(path string) {
 	v.SetDefault("orb.tls.verify", true)
 	v.SetDefault("orb.otel.host", "localhost")
 	v.SetDefault("orb.otel.port", 0)
+	v.SetDefault("orb.spool.enable", true)
+	v.SetDefault("orb.spool.directory", "./orb-agent-spool")
+	v.SetDefault("orb.spool.max_size_mb", 100)
+	v.SetDefault("orb.spool.max_age", "24h")
 	v.SetDefault("orb.debug.enable", Debug)
+	v.SetDefault("orb.logs.enable", true)
+	v.SetDefault("orb.logs.level", "info")
+	v.SetDefault("orb.logs.rate_limit", 20)
 
 	if len(path) > 0 {
 		cobra.CheckErr(v.ReadInConfig())
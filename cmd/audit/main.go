@@ -0,0 +1,231 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	r "github.com/go-redis/redis/v8"
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+	authapi "github.com/mainflux/mainflux/auth/api/grpc"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/orb-community/orb/audit"
+	audithttp "github.com/orb-community/orb/audit/api/http"
+	"github.com/orb-community/orb/audit/postgres"
+	rediscons "github.com/orb-community/orb/audit/redis/consumer"
+	"github.com/orb-community/orb/pkg/config"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	jconfig "github.com/uber/jaeger-client-go/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	svcName     = "audit"
+	mfEnvPrefix = "mf"
+	envPrefix   = "orb_audit"
+	httpPort    = "8204"
+)
+
+func main() {
+
+	authCfg := config.LoadGRPCConfig(mfEnvPrefix, "auth")
+
+	esCfg := config.LoadEsConfig(envPrefix)
+	svcCfg := config.LoadBaseServiceConfig(envPrefix, httpPort)
+	dbCfg := config.LoadPostgresConfig(envPrefix, svcName)
+	jCfg := config.LoadJaegerConfig(envPrefix)
+
+	// logger
+	var logger *zap.Logger
+	atomicLevel := zap.NewAtomicLevel()
+	switch strings.ToLower(svcCfg.LogLevel) {
+	case "debug":
+		atomicLevel.SetLevel(zap.DebugLevel)
+	case "warn":
+		atomicLevel.SetLevel(zap.WarnLevel)
+	case "info":
+		atomicLevel.SetLevel(zap.InfoLevel)
+	default:
+		atomicLevel.SetLevel(zap.InfoLevel)
+	}
+	encoderCfg := zap.NewProductionEncoderConfig()
+	encoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(encoderCfg),
+		os.Stdout,
+		atomicLevel,
+	)
+	logger = zap.New(core, zap.AddCaller())
+	defer func(logger *zap.Logger) {
+		_ = logger.Sync()
+	}(logger)
+
+	db := connectToDB(dbCfg, logger)
+	defer db.Close()
+
+	esClient := connectToRedis(esCfg.URL, esCfg.Pass, esCfg.DB, logger)
+	defer esClient.Close()
+
+	tracer, tracerCloser := initJaeger(svcName, jCfg.URL, logger)
+	defer tracerCloser.Close()
+
+	authConn := connectToAuth(authCfg, logger)
+	defer authConn.Close()
+
+	authTimeout, err := time.ParseDuration(authCfg.Timeout)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", authCfg.Timeout, err.Error())
+	}
+	auth := authapi.NewClient(tracer, authConn, authTimeout)
+
+	eventRepo := postgres.NewEventRepository(postgres.NewDatabase(db), logger)
+	svc := newAuditService(auth, logger, eventRepo)
+	errs := make(chan error, 2)
+
+	go startHTTPServer(tracer, svc, svcCfg, logger, errs)
+	go subscribeToAuditES(svc, esClient, esCfg, logger)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Audit service terminated: %s", err))
+}
+
+func connectToDB(cfg config.PostgresConfig, logger *zap.Logger) *sqlx.DB {
+	db, err := postgres.Connect(cfg)
+	if err != nil {
+		logger.Error("Failed to connect to postgres", zap.Error(err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func connectToRedis(redisURL, redisPass, redisDB string, logger *zap.Logger) *r.Client {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error("Failed to connect to redis", zap.Error(err))
+		os.Exit(1)
+	}
+
+	return r.NewClient(&r.Options{
+		Addr:     redisURL,
+		Password: redisPass,
+		DB:       db,
+	})
+}
+
+func initJaeger(svcName, url string, logger *zap.Logger) (opentracing.Tracer, io.Closer) {
+	if url == "" {
+		return opentracing.NoopTracer{}, io.NopCloser(nil)
+	}
+
+	tracer, closer, err := jconfig.Configuration{
+		ServiceName: svcName,
+		Sampler: &jconfig.SamplerConfig{
+			Type:  "const",
+			Param: 1,
+		},
+		Reporter: &jconfig.ReporterConfig{
+			LocalAgentHostPort: url,
+			LogSpans:           true,
+		},
+	}.NewTracer()
+	if err != nil {
+		logger.Error("Failed to init Jaeger client", zap.Error(err))
+		os.Exit(1)
+	}
+
+	return tracer, closer
+}
+
+func newAuditService(auth mainflux.AuthServiceClient, logger *zap.Logger, repo audit.Repository) audit.Service {
+	svc := audit.NewService(logger, auth, repo)
+	svc = audithttp.NewLoggingMiddleware(svc, logger)
+	svc = audithttp.MetricsMiddleware(
+		auth,
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "audit",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method", "owner_id"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "audit",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method", "owner_id"}),
+	)
+	return svc
+}
+
+func connectToAuth(cfg config.GRPCConfig, logger *zap.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	tls, err := strconv.ParseBool(cfg.ClientTLS)
+	if err != nil {
+		tls = false
+	}
+	if tls {
+		if cfg.CaCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.CaCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.URL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to auth service: %s", err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func startHTTPServer(tracer opentracing.Tracer, svc audit.Service, cfg config.BaseSvcConfig, logger *zap.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.HttpPort)
+	if cfg.HttpServerCert != "" || cfg.HttpServerKey != "" {
+		logger.Info(fmt.Sprintf("Audit service started using https on port %s with cert %s key %s",
+			cfg.HttpPort, cfg.HttpServerCert, cfg.HttpServerKey))
+		errs <- http.ListenAndServeTLS(p, cfg.HttpServerCert, cfg.HttpServerKey, audithttp.MakeHandler(tracer, svcName, svc))
+		return
+	}
+	logger.Info(fmt.Sprintf("Audit service started using http on port %s", cfg.HttpPort))
+	errs <- http.ListenAndServe(p, audithttp.MakeHandler(tracer, svcName, svc))
+}
+
+func subscribeToAuditES(svc audit.Service, client *r.Client, cfg config.EsConfig, logger *zap.Logger) {
+	eventStore := rediscons.NewEventStore(svc, client, cfg.Consumer, logger)
+	logger.Info("Subscribed to Redis Event Store for audit events")
+	if err := eventStore.Subscribe(context.Background()); err != nil {
+		logger.Error("Audit service failed to subscribe to event sourcing", zap.Error(err))
+	}
+}
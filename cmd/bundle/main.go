@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/orb-community/orb/buildinfo"
+	"github.com/orb-community/orb/bundle"
+	"github.com/orb-community/orb/pkg/config"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	envPrefix = "orb_bundle"
+
+	envAPIURL = "ORB_BUNDLE_API_URL"
+	envToken  = "ORB_BUNDLE_TOKEN"
+)
+
+var log *zap.Logger
+
+func init() {
+	atomicLevel := zap.NewAtomicLevel()
+	svcCfg := config.LoadBaseServiceConfig(envPrefix, "")
+
+	switch strings.ToLower(svcCfg.LogLevel) {
+	case "debug":
+		atomicLevel.SetLevel(zap.DebugLevel)
+	case "warn":
+		atomicLevel.SetLevel(zap.WarnLevel)
+	case "info":
+		atomicLevel.SetLevel(zap.InfoLevel)
+	default:
+		atomicLevel.SetLevel(zap.InfoLevel)
+	}
+
+	encoderCfg := zap.NewProductionEncoderConfig()
+	encoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+	// the plan is printed on stdout, logs go to stderr
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(encoderCfg),
+		os.Stderr,
+		atomicLevel,
+	)
+
+	log = zap.New(core, zap.AddCaller())
+}
+
+func main() {
+	var (
+		file    string
+		apiURL  string
+		token   string
+		timeout time.Duration
+		prune   bool
+		dryRun  bool
+	)
+
+	rootCmd := &cobra.Command{
+		Use:   "orb-bundle",
+		Short: "Manage sinks, agent groups, policies and datasets from a declarative YAML bundle",
+	}
+	rootCmd.PersistentFlags().StringVarP(&file, "file", "f", "bundle.yaml", "bundle to apply, - reads the standard input")
+	rootCmd.PersistentFlags().StringVar(&apiURL, "api", os.Getenv(envAPIURL), "Orb API url, such as https://orb.live/api/v1 (env "+envAPIURL+")")
+	rootCmd.PersistentFlags().StringVar(&token, "token", os.Getenv(envToken), "Orb API token (env "+envToken+")")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 30*time.Second, "timeout of each request to the Orb API")
+	rootCmd.PersistentFlags().BoolVar(&prune, "prune", false, "delete the resources of the fleet which are not declared in the bundle")
+
+	versionCmd := &cobra.Command{
+		Use:   "version",
+		Short: "Show bundle version",
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Printf("orb-bundle %s\n", buildinfo.GetVersion())
+		},
+	}
+
+	planCmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Show the changes bringing the fleet to the bundle",
+		Long:  "Compare the bundle to the current sinks, agent groups, policies and datasets and show the changes as a diff",
+		Run: func(cmd *cobra.Command, args []string) {
+			if _, _, err := plan(cmd.Context(), file, apiURL, token, timeout, prune); err != nil {
+				log.Error("error planning bundle", zap.Error(err))
+				os.Exit(1)
+			}
+		},
+	}
+
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply the changes bringing the fleet to the bundle",
+		Long:  "Show the changes bringing the fleet to the bundle and apply them, sinks and agent groups and policies before datasets",
+		Run: func(cmd *cobra.Command, args []string) {
+			p, client, err := plan(cmd.Context(), file, apiURL, token, timeout, prune)
+			if err != nil {
+				log.Error("error planning bundle", zap.Error(err))
+				os.Exit(1)
+			}
+			if dryRun || p.Empty() {
+				return
+			}
+			if err := bundle.Apply(cmd.Context(), log, client, p); err != nil {
+				log.Error("error applying bundle", zap.Error(err))
+				os.Exit(1)
+			}
+			fmt.Println("Bundle applied.")
+		},
+	}
+	applyCmd.Flags().BoolVar(&dryRun, "dry-run", false, "only show the changes")
+
+	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(planCmd)
+	rootCmd.AddCommand(applyCmd)
+
+	if err := rootCmd.ExecuteContext(context.Background()); err != nil {
+		log.Error("error on command exit", zap.Error(err))
+		fmt.Printf("error on command exit: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+func plan(ctx context.Context, file string, apiURL string, token string, timeout time.Duration, prune bool) (bundle.Plan, bundle.Client, error) {
+	if apiURL == "" || token == "" {
+		return bundle.Plan{}, nil, fmt.Errorf("the Orb API url and token are required")
+	}
+
+	b, err := bundle.Load(file)
+	if err != nil {
+		return bundle.Plan{}, nil, err
+	}
+
+	client := bundle.NewClient(apiURL, token, timeout)
+	state, err := client.State(ctx)
+	if err != nil {
+		return bundle.Plan{}, nil, err
+	}
+	log.Debug("retrieved fleet state", zap.Int("sinks", len(state.Sinks)),
+		zap.Int("agent_groups", len(state.AgentGroups)), zap.Int("policies", len(state.Policies)),
+		zap.Int("datasets", len(state.Datasets)))
+
+	p, err := bundle.NewPlan(b, state, prune)
+	if err != nil {
+		return bundle.Plan{}, nil, err
+	}
+	if err := p.Write(os.Stdout); err != nil {
+		return bundle.Plan{}, nil, err
+	}
+	return p, client, nil
+}
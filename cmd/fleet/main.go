@@ -11,6 +11,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"github.com/orb-community/orb/audit"
 	"github.com/orb-community/orb/fleet/backend/otel"
 	"io"
 	"log"
@@ -35,6 +36,7 @@ import (
 	"github.com/orb-community/orb/fleet/postgres"
 	rediscons "github.com/orb-community/orb/fleet/redis/consumer"
 	redisprod "github.com/orb-community/orb/fleet/redis/producer"
+	"github.com/orb-community/orb/notifications"
 	"github.com/orb-community/orb/pkg/config"
 	policiesgrpc "github.com/orb-community/orb/policies/api/grpc"
 	"go.uber.org/zap"
@@ -139,8 +141,11 @@ func main() {
 
 	agentRepo := postgres.NewAgentRepository(db, logger)
 	agentGroupRepo := postgres.NewAgentGroupRepository(db, logger)
+	rolloutRepo := postgres.NewPolicyRolloutRepository(db, logger)
 
-	commsSvc := fleet.NewFleetCommsService(logger, policiesGRPCClient, agentRepo, agentGroupRepo, pubSub)
+	notifier := notifications.NewPublisher(esClient, logger)
+
+	commsSvc := fleet.NewFleetCommsService(logger, policiesGRPCClient, agentRepo, agentGroupRepo, rolloutRepo, pubSub, notifier)
 	commsSvc = fleet.CommsMetricsMiddleware(
 		commsSvc,
 		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
@@ -158,13 +163,15 @@ func main() {
 
 	aDone := make(chan bool)
 
-	svc := newFleetService(authGRPCClient, db, logger, esClient, sdkCfg, agentRepo, agentGroupRepo, commsSvc, aDone)
+	svc := newFleetService(authGRPCClient, db, logger, esClient, sdkCfg, agentRepo, agentGroupRepo, rolloutRepo, commsSvc, notifier, aDone)
 	defer commsSvc.Stop()
 
 	errs := make(chan error, 2)
 
 	go startHTTPServer(tracer, svc, svcCfg, logger, errs)
 	go subscribeToPoliciesES(svc, commsSvc, esClient, esCfg, logger)
+	go checkPolicyRollouts(svc, logger)
+	go runBulkJobs(svc, logger)
 	go startGRPCServer(svc, tracer, fleetGRPCCfg, logger, errs)
 
 	err = commsSvc.Start()
@@ -231,7 +238,7 @@ func initJaeger(svcName, url string, logger *zap.Logger) (opentracing.Tracer, io
 	return tracer, closer
 }
 
-func newFleetService(auth mainflux.AuthServiceClient, db *sqlx.DB, logger *zap.Logger, esClient *r.Client, sdkCfg config.MFSDKConfig, agentRepo fleet.AgentRepository, agentGroupRepo fleet.AgentGroupRepository, agentComms fleet.AgentCommsService, aDone chan bool) fleet.Service {
+func newFleetService(auth mainflux.AuthServiceClient, db *sqlx.DB, logger *zap.Logger, esClient *r.Client, sdkCfg config.MFSDKConfig, agentRepo fleet.AgentRepository, agentGroupRepo fleet.AgentGroupRepository, rolloutRepo fleet.PolicyRolloutRepository, agentComms fleet.AgentCommsService, notifier notifications.Publisher, aDone chan bool) fleet.Service {
 
 	config := mfsdk.Config{
 		ThingsURL: sdkCfg.ThingsURL,
@@ -242,8 +249,8 @@ func newFleetService(auth mainflux.AuthServiceClient, db *sqlx.DB, logger *zap.L
 	pktvisor.Register(auth, agentRepo)
 	otel.Register(auth, agentRepo)
 
-	svc := fleet.NewFleetService(logger, auth, agentRepo, agentGroupRepo, agentComms, mfsdk, aDone)
-	svc = redisprod.NewEventStoreMiddleware(svc, esClient, logger)
+	svc := fleet.NewFleetService(logger, auth, agentRepo, agentGroupRepo, rolloutRepo, agentComms, mfsdk, fleet.NewThingKeyUpdater(sdkCfg.ThingsURL), notifier, aDone)
+	svc = redisprod.NewEventStoreMiddleware(svc, esClient, audit.NewRecorder(svcName, auth, esClient, logger), logger)
 	svc = fleethttp.NewLoggingMiddleware(svc, logger)
 	svc = fleethttp.MetricsMiddleware(
 		auth,
@@ -313,6 +320,36 @@ func subscribeToPoliciesES(svc fleet.Service, commsSvc fleet.AgentCommsService,
 	}
 }
 
+// checkPolicyRollouts goes through the staged policy rollouts every heartbeat period, it runs on the
+// event store middleware so the policies service is asked to roll back the failed ones. Every replica runs
+// it, the rollout repository lets a single one check at a time
+func checkPolicyRollouts(svc fleet.Service, logger *zap.Logger) {
+	ticker := time.NewTicker(fleet.HeartbeatFreq)
+	defer ticker.Stop()
+	for range ticker.C {
+		if _, err := svc.CheckPolicyRolloutsInternal(context.Background()); err != nil {
+			logger.Error("failed to check policy rollouts", zap.Error(err))
+		}
+	}
+}
+
+// runBulkJobs runs the bulk agent jobs claimed by this replica, after failing those left running by a replica which
+// stopped. The per agent changes go through the wrapped service so they are audited as the single agent operations
+func runBulkJobs(svc fleet.Service, logger *zap.Logger) {
+	if err := svc.FailStaleBulkJobsInternal(context.Background()); err != nil {
+		logger.Error("failed to fail stale bulk jobs", zap.Error(err))
+	}
+
+	ticker := time.NewTicker(fleet.BulkJobPollInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		fleet.RunPendingBulkJobs(context.Background(), svc, logger)
+		if err := svc.FailStaleBulkJobsInternal(context.Background()); err != nil {
+			logger.Error("failed to fail stale bulk jobs", zap.Error(err))
+		}
+	}
+}
+
 func startGRPCServer(svc fleet.Service, tracer opentracing.Tracer, cfg config.GRPCConfig, logger *zap.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", cfg.Port)
 	listener, err := net.Listen("tcp", p)
@@ -30,6 +30,7 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/orb-community/orb/maestro"
+	"github.com/orb-community/orb/maestro/kubecontrol"
 	"github.com/orb-community/orb/pkg/config"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -116,10 +117,16 @@ func main() {
 	}
 	sinksGRPCClient := sinksgrpc.NewClient(tracer, sinksGRPCConn, sinksGRPCTimeout, logger)
 	otelCfg := config.LoadOtelConfig(envPrefix)
+	monitorCfg := config.LoadMonitorConfig(envPrefix)
 	db := connectToDB(dbCfg, logger)
 	defer db.Close()
 
-	svc := maestro.NewMaestroService(logger, streamEsClient, sinkerEsClient, sinksGRPCClient, otelCfg, db, svcCfg)
+	collectorRuntime, err := kubecontrol.NewRuntime(logger, config.LoadCollectorRuntimeConfig(envPrefix))
+	if err != nil {
+		log.Fatalf("failed to create the collector runtime: %s", err.Error())
+	}
+
+	svc := maestro.NewMaestroService(logger, streamEsClient, sinkerEsClient, sinksGRPCClient, otelCfg, db, svcCfg, collectorRuntime, monitorCfg)
 	errs := make(chan error, 2)
 
 	mainContext, mainCancelFunction := context.WithCancel(context.Background())
@@ -13,6 +13,7 @@ import (
 	"fmt"
 	authapi "github.com/mainflux/mainflux/auth/api/grpc"
 	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/orb-community/orb/audit"
 	fleetgrpc "github.com/orb-community/orb/fleet/api/grpc"
 	fleetpb "github.com/orb-community/orb/fleet/pb"
 	"github.com/orb-community/orb/pkg/config"
@@ -199,8 +200,11 @@ func initJaeger(svcName, url string, logger *zap.Logger) (opentracing.Tracer, io
 func newService(auth mainflux.AuthServiceClient, db *sqlx.DB, logger *zap.Logger, esClient *r.Client, fleetGrpcClient fleetpb.FleetServiceClient, sinksGrpcClient sinkspb.SinkServiceClient) policies.Service {
 	thingsRepo := postgres.NewPoliciesRepository(db, logger)
 
-	svc := policies.New(logger, auth, thingsRepo, fleetGrpcClient, sinksGrpcClient)
-	svc = redisprod.NewEventStoreMiddleware(svc, esClient, logger)
+	svc, err := policies.New(logger, auth, thingsRepo, fleetGrpcClient, sinksGrpcClient)
+	if err != nil {
+		logger.Fatal("failed to create policies service", zap.Error(err))
+	}
+	svc = redisprod.NewEventStoreMiddleware(svc, esClient, audit.NewRecorder(svcName, auth, esClient, logger), logger)
 	svc = policieshttp.NewLoggingMiddleware(svc, logger)
 	svc = policieshttp.MetricsMiddleware(
 		auth,
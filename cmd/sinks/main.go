@@ -25,6 +25,8 @@ import (
 	authapi "github.com/mainflux/mainflux/auth/api/grpc"
 	mfsdk "github.com/mainflux/mainflux/pkg/sdk/go"
 	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/orb-community/orb/audit"
+	"github.com/orb-community/orb/notifications"
 	"github.com/orb-community/orb/pkg/config"
 	"github.com/orb-community/orb/sinks"
 	sinksgrpc "github.com/orb-community/orb/sinks/api/grpc"
@@ -194,7 +196,7 @@ func newSinkService(auth mainflux.AuthServiceClient, logger *zap.Logger, esClien
 	mfsdk := mfsdk.NewSDK(config)
 
 	svc := sinks.NewSinkService(logger, auth, repoSink, mfsdk, passwordService)
-	svc = redisprod.NewSinkStreamProducerMiddleware(svc, esClient)
+	svc = redisprod.NewSinkStreamProducerMiddleware(svc, esClient, audit.NewRecorder(svcName, auth, esClient, logger), notifications.NewPublisher(esClient, logger))
 	svc = sinkshttp.NewLoggingMiddleware(svc, logger)
 	svc = sinkshttp.MetricsMiddleware(
 		auth,
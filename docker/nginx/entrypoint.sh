@@ -14,6 +14,8 @@ envsubst '
     ${ORB_FLEET_HTTP_PORT}
     ${ORB_SINKS_HTTP_PORT}
     ${ORB_POLICIES_HTTP_PORT}
+    ${ORB_AUDIT_HTTP_PORT}
+    ${ORB_NOTIFICATIONS_HTTP_PORT}
     ${MF_THINGS_HTTP_PORT}
     ${MF_THINGS_AUTH_HTTP_PORT}
     ${MF_HTTP_ADAPTER_PORT}
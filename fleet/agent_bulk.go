@@ -0,0 +1,325 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// BulkAddTags merges the given orb tags into the orb tags of each agent
+	BulkAddTags = "add_tags"
+	// BulkRemoveTags removes the given keys from the orb tags of each agent
+	BulkRemoveTags = "remove_tags"
+	// BulkReset restarts every backend of each agent
+	BulkReset = "reset"
+	// BulkDelete removes each agent along with its Thing and Channel
+	BulkDelete = "delete"
+	// BulkStop requests each agent to terminate
+	BulkStop = "stop"
+
+	BulkJobRunning   = "running"
+	BulkJobCompleted = "completed"
+	// BulkJobFailed is the state of the jobs whose replica stopped before applying them to every agent
+	BulkJobFailed = "failed"
+
+	// MaxBulkAgents bounds how many agents a single bulk operation can match
+	MaxBulkAgents = 10000
+
+	// BulkJobPollInterval is how often the replicas look for bulk jobs to run
+	BulkJobPollInterval = 2 * time.Second
+	// bulkJobStaleTimeout is how long a running job can go without progress before it is considered abandoned
+	bulkJobStaleTimeout = 10 * time.Minute
+
+	bulkDefaultReason = "Bulk operation initiated from control plane"
+)
+
+var (
+	// ErrBulkJobNotFound indicates the bulk job does not exist or belongs to another owner
+	ErrBulkJobNotFound = errors.New("bulk job not found")
+	// ErrInvalidBulkOperation indicates an unknown operation, a missing filter or missing operation parameters
+	ErrInvalidBulkOperation = errors.New("invalid bulk operation")
+)
+
+// BulkOperation is an operation to apply to every agent of the owner whose agent or orb tags contain Filter
+type BulkOperation struct {
+	Operation string
+	Filter    types.Tags
+	// OrbTags are merged into the orb tags of the agents by BulkAddTags
+	OrbTags types.Tags
+	// TagKeys are removed from the orb tags of the agents by BulkRemoveTags
+	TagKeys []string
+	Reason  string
+	DryRun  bool
+}
+
+// Validate checks the operation is known, has a non-empty filter so it never targets the whole fleet by
+// accident, and carries the parameters its operation needs
+func (op BulkOperation) Validate() error {
+	if len(op.Filter) == 0 {
+		return errors.Wrap(ErrInvalidBulkOperation, errors.New("a tag filter is required"))
+	}
+	switch op.Operation {
+	case BulkAddTags:
+		if len(op.OrbTags) == 0 {
+			return errors.Wrap(ErrInvalidBulkOperation, errors.New("orb tags to add are required"))
+		}
+	case BulkRemoveTags:
+		if len(op.TagKeys) == 0 {
+			return errors.Wrap(ErrInvalidBulkOperation, errors.New("tag keys to remove are required"))
+		}
+	case BulkReset, BulkDelete, BulkStop:
+	default:
+		return errors.Wrap(ErrInvalidBulkOperation, fmt.Errorf("unknown operation %q", op.Operation))
+	}
+	return nil
+}
+
+// BulkAgentFailure records why the operation failed on one agent
+type BulkAgentFailure struct {
+	AgentID string `json:"agent_id"`
+	Error   string `json:"error"`
+}
+
+// BulkJob tracks a bulk operation running in the background over the agents matching its filter at creation
+type BulkJob struct {
+	ID        string
+	MFOwnerID string
+	// MFOwnerEmail is the owner the agents are changed on behalf of, no user token is kept for the job
+	MFOwnerEmail string
+	Operation    string
+	Filter       types.Tags
+	OrbTags      types.Tags
+	TagKeys      []string
+	Reason       string
+	State        string
+	AgentIDs     []string
+	Succeeded    uint64
+	Failed       uint64
+	Failures     []BulkAgentFailure
+	Created      time.Time
+	LastModified time.Time
+}
+
+// Total is the number of agents the job applies to
+func (j BulkJob) Total() uint64 {
+	return uint64(len(j.AgentIDs))
+}
+
+type AgentBulkService interface {
+	// BulkAgentOperation applies an operation to every agent of the owner matching the tag filter. A dry run only
+	// returns the matching agents, otherwise the operation runs in the background and the returned job tracks it
+	BulkAgentOperation(ctx context.Context, token string, op BulkOperation) (BulkJob, []Agent, error)
+	// ViewBulkJob retrieves the progress and per agent failures of a bulk job
+	ViewBulkJob(ctx context.Context, token string, id string) (BulkJob, error)
+	// ClaimBulkJobInternal takes the next bulk job to run, so no other replica runs it, ErrBulkJobNotFound when there is none
+	ClaimBulkJobInternal(ctx context.Context) (BulkJob, error)
+	// ApplyBulkOperationInternal applies the operation of the job to one of its agents and records the job progress
+	ApplyBulkOperationInternal(ctx context.Context, job BulkJob, agentID string) error
+	// CompleteBulkJobInternal marks the job completed once applied to every agent
+	CompleteBulkJobInternal(ctx context.Context, job BulkJob) error
+	// FailStaleBulkJobsInternal fails the jobs left running by a replica which stopped
+	FailStaleBulkJobsInternal(ctx context.Context) error
+}
+
+type BulkJobRepository interface {
+	// SaveBulkJob persists the bulk job, returning its id
+	SaveBulkJob(ctx context.Context, job BulkJob) (string, error)
+	// RetrieveBulkJob retrieves a bulk job of the owner
+	RetrieveBulkJob(ctx context.Context, ownerID string, id string) (BulkJob, error)
+	// RecordBulkJobProgress counts one more agent done, as failed when failure is not nil
+	RecordBulkJobProgress(ctx context.Context, id string, failure *BulkAgentFailure) error
+	// UpdateBulkJobState changes the state of a bulk job
+	UpdateBulkJobState(ctx context.Context, id string, state string) error
+	// ClaimBulkJob takes the oldest running bulk job not taken yet, ErrBulkJobNotFound when there is none
+	ClaimBulkJob(ctx context.Context) (BulkJob, error)
+	// FailStaleBulkJobs fails the taken jobs still running without progress since the given time, returning how many
+	FailStaleBulkJobs(ctx context.Context, before time.Time) (uint64, error)
+}
+
+func (svc fleetService) BulkAgentOperation(ctx context.Context, token string, op BulkOperation) (BulkJob, []Agent, error) {
+	identity, err := svc.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return BulkJob{}, nil, errors.Wrap(errors.ErrUnauthorizedAccess, err)
+	}
+	ownerID := identity.GetId()
+
+	if err := op.Validate(); err != nil {
+		return BulkJob{}, nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	page, err := svc.agentRepo.RetrieveAll(ctx, ownerID, PageMetadata{
+		Limit: MaxBulkAgents + 1,
+		Order: "name",
+		Dir:   "asc",
+		Tags:  op.Filter,
+	})
+	if err != nil {
+		return BulkJob{}, nil, err
+	}
+	if len(page.Agents) > MaxBulkAgents {
+		return BulkJob{}, nil, errors.Wrap(errors.ErrMalformedEntity,
+			errors.Wrap(ErrInvalidBulkOperation, fmt.Errorf("the filter matches more than %d agents", MaxBulkAgents)))
+	}
+
+	if op.Reason == "" {
+		op.Reason = bulkDefaultReason
+	}
+	job := BulkJob{
+		MFOwnerID:    ownerID,
+		MFOwnerEmail: identity.GetEmail(),
+		Operation:    op.Operation,
+		Filter:       op.Filter,
+		OrbTags:      op.OrbTags,
+		TagKeys:      op.TagKeys,
+		Reason:       op.Reason,
+		State:        BulkJobRunning,
+		AgentIDs:     make([]string, len(page.Agents)),
+	}
+	for i, a := range page.Agents {
+		job.AgentIDs[i] = a.MFThingID
+	}
+
+	if op.DryRun {
+		return job, page.Agents, nil
+	}
+
+	if len(page.Agents) == 0 {
+		job.State = BulkJobCompleted
+	}
+	// a running job is picked up by RunPendingBulkJobs on one of the replicas
+	job.ID, err = svc.agentRepo.SaveBulkJob(ctx, job)
+	if err != nil {
+		return BulkJob{}, nil, err
+	}
+
+	return job, page.Agents, nil
+}
+
+func (svc fleetService) ViewBulkJob(ctx context.Context, token string, id string) (BulkJob, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return BulkJob{}, err
+	}
+	return svc.agentRepo.RetrieveBulkJob(ctx, ownerID, id)
+}
+
+func (svc fleetService) ClaimBulkJobInternal(ctx context.Context) (BulkJob, error) {
+	return svc.agentRepo.ClaimBulkJob(ctx)
+}
+
+func (svc fleetService) ApplyBulkOperationInternal(ctx context.Context, job BulkJob, agentID string) error {
+	var failure *BulkAgentFailure
+	err := svc.applyBulkOperation(ctx, job, agentID)
+	if err != nil {
+		failure = &BulkAgentFailure{AgentID: agentID, Error: err.Error()}
+	}
+	if err := svc.agentRepo.RecordBulkJobProgress(ctx, job.ID, failure); err != nil {
+		svc.logger.Error("failed to record bulk job progress", zap.String("job_id", job.ID), zap.Error(err))
+	}
+	return err
+}
+
+func (svc fleetService) CompleteBulkJobInternal(ctx context.Context, job BulkJob) error {
+	if err := svc.agentRepo.UpdateBulkJobState(ctx, job.ID, BulkJobCompleted); err != nil {
+		return err
+	}
+	svc.logger.Info("bulk job completed", zap.String("job_id", job.ID), zap.String("operation", job.Operation),
+		zap.Uint64("agents", job.Total()))
+	return nil
+}
+
+func (svc fleetService) FailStaleBulkJobsInternal(ctx context.Context) error {
+	failed, err := svc.agentRepo.FailStaleBulkJobs(ctx, time.Now().Add(-bulkJobStaleTimeout))
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		svc.logger.Warn("failed bulk jobs left running by a stopped replica", zap.Uint64("jobs", failed))
+	}
+	return nil
+}
+
+// RunPendingBulkJobs runs the bulk jobs waiting to be run, one after the other. svc must be the service wrapped in its
+// middlewares, so the change made to each agent is recorded as the single agent operations are
+func RunPendingBulkJobs(ctx context.Context, svc AgentBulkService, logger *zap.Logger) {
+	for {
+		job, err := svc.ClaimBulkJobInternal(ctx)
+		if err != nil {
+			if !errors.Contains(err, ErrBulkJobNotFound) {
+				logger.Error("failed to claim bulk job", zap.Error(err))
+			}
+			return
+		}
+
+		for _, agentID := range job.AgentIDs {
+			if err := svc.ApplyBulkOperationInternal(ctx, job, agentID); err != nil {
+				logger.Warn("bulk operation failed on agent", zap.String("job_id", job.ID), zap.String("operation", job.Operation),
+					zap.String("agent_id", agentID), zap.Error(err))
+			}
+		}
+
+		if err := svc.CompleteBulkJobInternal(ctx, job); err != nil {
+			logger.Error("failed to complete bulk job", zap.String("job_id", job.ID), zap.Error(err))
+		}
+	}
+}
+
+// applyBulkOperation applies the operation to the agent as it is now, it may have changed since the job was created
+func (svc fleetService) applyBulkOperation(ctx context.Context, job BulkJob, agentID string) error {
+	a, err := svc.agentRepo.RetrieveByID(ctx, job.MFOwnerID, agentID)
+	if err != nil {
+		return err
+	}
+
+	switch job.Operation {
+	case BulkAddTags, BulkRemoveTags:
+		orbTags := types.Tags{}
+		if a.OrbTags != nil {
+			for k, v := range *a.OrbTags {
+				orbTags[k] = v
+			}
+		}
+		for k, v := range job.OrbTags {
+			orbTags[k] = v
+		}
+		for _, k := range job.TagKeys {
+			delete(orbTags, k)
+		}
+		key, err := svc.ownerKey(ctx, job)
+		if err != nil {
+			return err
+		}
+		_, err = svc.editAgent(ctx, key, Agent{MFThingID: a.MFThingID, MFOwnerID: a.MFOwnerID, OrbTags: &orbTags})
+		return err
+	case BulkReset:
+		return svc.agentComms.NotifyAgentReset(ctx, a, "", true, job.Reason)
+	case BulkDelete:
+		key, err := svc.ownerKey(ctx, job)
+		if err != nil {
+			return err
+		}
+		return svc.removeAgent(ctx, key, a)
+	case BulkStop:
+		return svc.agentComms.NotifyAgentStop(ctx, a, job.Reason)
+	}
+	return ErrInvalidBulkOperation
+}
+
+// ownerKey issues a short-lived key of the job owner for the Things and Channels of its agents, as enrollment does
+func (svc fleetService) ownerKey(ctx context.Context, job BulkJob) (string, error) {
+	key, err := svc.auth.Issue(ctx, &mainflux.IssueReq{Id: job.MFOwnerID, Email: job.MFOwnerEmail, Type: loginKeyType})
+	if err != nil {
+		return "", errors.Wrap(errors.ErrUnauthorizedAccess, err)
+	}
+	return key.GetValue(), nil
+}
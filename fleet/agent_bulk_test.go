@@ -0,0 +1,204 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package fleet_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/orb-community/orb/fleet"
+	flmocks "github.com/orb-community/orb/fleet/mocks"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func createTaggedAgent(t *testing.T, name string, orbTags types.Tags, svc fleet.Service) fleet.Agent {
+	t.Helper()
+	validName, err := types.NewIdentifier(name)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	a, err := svc.CreateAgent(context.Background(), token, fleet.Agent{Name: validName, OrbTags: &orbTags})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	return a
+}
+
+func runBulkJob(t *testing.T, svc fleet.Service, id string) fleet.BulkJob {
+	t.Helper()
+	fleet.RunPendingBulkJobs(context.Background(), svc, zap.NewNop())
+	job, err := svc.ViewBulkJob(context.Background(), token, id)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	return job
+}
+
+func TestBulkAgentOperation(t *testing.T) {
+	users := flmocks.NewAuthService(map[string]string{token: email})
+	thingsServer := newThingsServer(newThingsService(users))
+	defer thingsServer.Close()
+
+	fleetService := newFleetService(users, thingsServer.URL, flmocks.NewAgentGroupRepository(), flmocks.NewAgentRepositoryMock())
+
+	createTaggedAgent(t, "bulk-eu-1", types.Tags{"region": "eu"}, fleetService)
+	createTaggedAgent(t, "bulk-eu-2", types.Tags{"region": "eu"}, fleetService)
+	createTaggedAgent(t, "bulk-us-1", types.Tags{"region": "us"}, fleetService)
+
+	cases := map[string]struct {
+		op     fleet.BulkOperation
+		token  string
+		agents int
+		err    error
+	}{
+		"dry run of a reset on matching agents": {
+			op:     fleet.BulkOperation{Operation: fleet.BulkReset, Filter: types.Tags{"region": "eu"}, DryRun: true},
+			token:  token,
+			agents: 2,
+			err:    nil,
+		},
+		"dry run matching no agents": {
+			op:     fleet.BulkOperation{Operation: fleet.BulkStop, Filter: types.Tags{"region": "ap"}, DryRun: true},
+			token:  token,
+			agents: 0,
+			err:    nil,
+		},
+		"bulk operation without filter": {
+			op:    fleet.BulkOperation{Operation: fleet.BulkDelete, DryRun: true},
+			token: token,
+			err:   errors.ErrMalformedEntity,
+		},
+		"bulk operation unknown": {
+			op:    fleet.BulkOperation{Operation: "upgrade", Filter: types.Tags{"region": "eu"}, DryRun: true},
+			token: token,
+			err:   errors.ErrMalformedEntity,
+		},
+		"bulk add of tags without tags": {
+			op:    fleet.BulkOperation{Operation: fleet.BulkAddTags, Filter: types.Tags{"region": "eu"}, DryRun: true},
+			token: token,
+			err:   errors.ErrMalformedEntity,
+		},
+		"bulk removal of tags without keys": {
+			op:    fleet.BulkOperation{Operation: fleet.BulkRemoveTags, Filter: types.Tags{"region": "eu"}, DryRun: true},
+			token: token,
+			err:   errors.ErrMalformedEntity,
+		},
+		"bulk operation with wrong credentials": {
+			op:    fleet.BulkOperation{Operation: fleet.BulkReset, Filter: types.Tags{"region": "eu"}, DryRun: true},
+			token: invalidToken,
+			err:   errors.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			job, agents, err := fleetService.BulkAgentOperation(context.Background(), tc.token, tc.op)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+			if err == nil {
+				assert.Len(t, agents, tc.agents, fmt.Sprintf("%s: expected %d agents got %d", desc, tc.agents, len(agents)))
+				assert.Equal(t, uint64(tc.agents), job.Total(), fmt.Sprintf("%s: expected a total of %d agents", desc, tc.agents))
+				assert.Empty(t, job.ID, fmt.Sprintf("%s: expected no job to be started", desc))
+			}
+		})
+	}
+}
+
+func TestBulkAgentJob(t *testing.T) {
+	users := flmocks.NewAuthService(map[string]string{token: email})
+	thingsServer := newThingsServer(newThingsService(users))
+	defer thingsServer.Close()
+
+	fleetService := newFleetService(users, thingsServer.URL, flmocks.NewAgentGroupRepository(), flmocks.NewAgentRepositoryMock())
+
+	eu1 := createTaggedAgent(t, "bulk-eu-1", types.Tags{"region": "eu", "tier": "edge"}, fleetService)
+	eu2 := createTaggedAgent(t, "bulk-eu-2", types.Tags{"region": "eu", "tier": "edge"}, fleetService)
+	us1 := createTaggedAgent(t, "bulk-us-1", types.Tags{"region": "us", "tier": "edge"}, fleetService)
+
+	job, _, err := fleetService.BulkAgentOperation(context.Background(), token, fleet.BulkOperation{
+		Operation: fleet.BulkAddTags,
+		Filter:    types.Tags{"region": "eu"},
+		OrbTags:   types.Tags{"pop": "gru"},
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.NotEmpty(t, job.ID, "expected a job to be started")
+
+	job = runBulkJob(t, fleetService, job.ID)
+	assert.Equal(t, fleet.BulkJobCompleted, job.State, "expected the job to complete")
+	assert.Equal(t, uint64(2), job.Succeeded, "expected the job to succeed on both matching agents")
+	assert.Equal(t, uint64(0), job.Failed, "expected no failures")
+
+	for _, id := range []string{eu1.MFThingID, eu2.MFThingID} {
+		a, err := fleetService.ViewAgentByID(context.Background(), token, id)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		assert.Equal(t, types.Tags{"region": "eu", "tier": "edge", "pop": "gru"}, *a.OrbTags, "expected the tag to be added")
+	}
+
+	job, _, err = fleetService.BulkAgentOperation(context.Background(), token, fleet.BulkOperation{
+		Operation: fleet.BulkRemoveTags,
+		Filter:    types.Tags{"tier": "edge"},
+		TagKeys:   []string{"pop", "tier"},
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	job = runBulkJob(t, fleetService, job.ID)
+	assert.Equal(t, uint64(3), job.Succeeded, "expected the job to succeed on every matching agent")
+
+	a, err := fleetService.ViewAgentByID(context.Background(), token, us1.MFThingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, types.Tags{"region": "us"}, *a.OrbTags, "expected the tags to be removed")
+
+	job, _, err = fleetService.BulkAgentOperation(context.Background(), token, fleet.BulkOperation{
+		Operation: fleet.BulkDelete,
+		Filter:    types.Tags{"region": "eu"},
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	job = runBulkJob(t, fleetService, job.ID)
+	assert.Equal(t, uint64(2), job.Succeeded, "expected both matching agents to be deleted")
+
+	_, err = fleetService.ViewAgentByID(context.Background(), token, eu1.MFThingID)
+	assert.True(t, errors.Contains(err, errors.ErrNotFound), fmt.Sprintf("expected %s got %s", errors.ErrNotFound, err))
+	_, err = fleetService.ViewAgentByID(context.Background(), token, us1.MFThingID)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	// the job is only run once
+	_, err = fleetService.ClaimBulkJobInternal(context.Background())
+	assert.True(t, errors.Contains(err, fleet.ErrBulkJobNotFound), fmt.Sprintf("expected %s got %s", fleet.ErrBulkJobNotFound, err))
+
+	_, err = fleetService.ViewBulkJob(context.Background(), token, "9bb1b244-a199-93c2-aa03-28067b431e2c")
+	assert.True(t, errors.Contains(err, errors.ErrNotFound), fmt.Sprintf("expected %s got %s", errors.ErrNotFound, err))
+}
+
+func TestBulkAgentJobRereadsAgents(t *testing.T) {
+	users := flmocks.NewAuthService(map[string]string{token: email})
+	thingsServer := newThingsServer(newThingsService(users))
+	defer thingsServer.Close()
+
+	fleetService := newFleetService(users, thingsServer.URL, flmocks.NewAgentGroupRepository(), flmocks.NewAgentRepositoryMock())
+
+	eu1 := createTaggedAgent(t, "bulk-eu-1", types.Tags{"region": "eu"}, fleetService)
+	eu2 := createTaggedAgent(t, "bulk-eu-2", types.Tags{"region": "eu"}, fleetService)
+
+	job, _, err := fleetService.BulkAgentOperation(context.Background(), token, fleet.BulkOperation{
+		Operation: fleet.BulkAddTags,
+		Filter:    types.Tags{"region": "eu"},
+		OrbTags:   types.Tags{"pop": "gru"},
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	// the agents change between the creation of the job and its run
+	err = fleetService.RemoveAgent(context.Background(), token, eu1.MFThingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	orbTags := types.Tags{"region": "eu", "tier": "core"}
+	_, err = fleetService.EditAgent(context.Background(), token, fleet.Agent{MFThingID: eu2.MFThingID, Name: eu2.Name, OrbTags: &orbTags})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	job = runBulkJob(t, fleetService, job.ID)
+	assert.Equal(t, fleet.BulkJobCompleted, job.State, "expected the job to complete")
+	assert.Equal(t, uint64(1), job.Succeeded, "expected the job to succeed on the remaining agent")
+	require.Len(t, job.Failures, 1, "expected the removed agent to fail")
+	assert.Equal(t, eu1.MFThingID, job.Failures[0].AgentID)
+
+	a, err := fleetService.ViewAgentByID(context.Background(), token, eu2.MFThingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, types.Tags{"region": "eu", "tier": "core", "pop": "gru"}, *a.OrbTags, "expected the edit made meanwhile to be kept")
+}
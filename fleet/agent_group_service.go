@@ -12,6 +12,7 @@ import (
 	"context"
 	mfsdk "github.com/mainflux/mainflux/pkg/sdk/go"
 	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
 	"go.uber.org/zap"
 	"reflect"
 	"strings"
@@ -131,8 +132,14 @@ func (svc fleetService) EditAgentGroup(ctx context.Context, token string, group
 
 	if group.Tags == nil {
 		group.Tags = currentAgentGroup.Tags
-	} else if group.Tags != nil && len(*group.Tags) == 0 {
-		return AgentGroup{}, errors.Wrap(errors.ErrMalformedEntity, errors.New("group tags can not be empty"))
+	}
+
+	if group.Selector == nil {
+		group.Selector = currentAgentGroup.Selector
+	}
+
+	if err := validateGroupSelection(group); err != nil {
+		return AgentGroup{}, err
 	}
 
 	ag, err := svc.agentGroupRepository.Update(ctx, ownerID, group)
@@ -183,10 +190,23 @@ func (svc fleetService) EditAgentGroup(ctx context.Context, token string, group
 			svc.logger.Error("failure during agent group membership comms", zap.Error(err))
 		}
 	}
+	// a new membership may change the heartbeat configuration in effect for the agents
+	svc.notifyHeartbeatConfig(ctx, list)
 
 	return ag, nil
 }
 
+// validateGroupSelection ensures the group selects its agents by tags, by tag selector or both
+func validateGroupSelection(group AgentGroup) error {
+	if (group.Tags == nil || len(*group.Tags) == 0) && len(group.Selector) == 0 {
+		return errors.Wrap(errors.ErrMalformedEntity, errors.New("group tags and selector can not be both empty"))
+	}
+	if err := group.Selector.Validate(); err != nil {
+		return errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return nil
+}
+
 func removeDuplicates(sliceA []Agent, sliceB []Agent) []Agent {
 	keys := make(map[string]bool)
 	var list []Agent
@@ -213,6 +233,10 @@ func (svc fleetService) CreateAgentGroup(ctx context.Context, token string, s Ag
 
 	s.MFOwnerID = mfOwnerID
 
+	if err := validateGroupSelection(s); err != nil {
+		return AgentGroup{}, err
+	}
+
 	md := map[string]interface{}{"type": "orb_agent_group"}
 
 	// create main Group RPC Channel
@@ -302,7 +326,16 @@ func (svc fleetService) ValidateAgentGroup(ctx context.Context, token string, ag
 	}
 
 	ag.MFOwnerID = mfOwnerID
-	res, err := svc.agentRepo.RetrieveMatchingAgents(ctx, mfOwnerID, *ag.Tags)
+
+	if err := validateGroupSelection(ag); err != nil {
+		return AgentGroup{}, err
+	}
+
+	var tags types.Tags
+	if ag.Tags != nil {
+		tags = *ag.Tags
+	}
+	res, err := svc.agentRepo.RetrieveMatchingAgents(ctx, mfOwnerID, tags, ag.Selector)
 	if err != nil {
 		return AgentGroup{}, err
 	}
@@ -21,6 +21,7 @@ import (
 	"github.com/orb-community/orb/fleet"
 	"github.com/orb-community/orb/fleet/backend/pktvisor"
 	flmocks "github.com/orb-community/orb/fleet/mocks"
+	notifmocks "github.com/orb-community/orb/notifications/mocks"
 	"github.com/orb-community/orb/pkg/errors"
 	"github.com/orb-community/orb/pkg/types"
 	"github.com/stretchr/testify/assert"
@@ -85,6 +86,7 @@ func newThingsServer(svc things.Service) *httptest.Server {
 func newService(auth mainflux.AuthServiceClient, url string) fleet.Service {
 	agentGroupRepo := flmocks.NewAgentGroupRepository()
 	agentRepo := flmocks.NewAgentRepositoryMock()
+	rolloutRepo := flmocks.NewPolicyRolloutRepository()
 	agentComms := flmocks.NewFleetCommService(agentRepo, agentGroupRepo)
 	logger, _ := zap.NewDevelopment()
 	config := mfsdk.Config{
@@ -94,7 +96,7 @@ func newService(auth mainflux.AuthServiceClient, url string) fleet.Service {
 	mfsdk := mfsdk.NewSDK(config)
 	pktvisor.Register(auth, agentRepo)
 	aDone := make(chan bool)
-	return fleet.NewFleetService(logger, auth, agentRepo, agentGroupRepo, agentComms, mfsdk, aDone)
+	return fleet.NewFleetService(logger, auth, agentRepo, agentGroupRepo, rolloutRepo, agentComms, mfsdk, fleet.NewThingKeyUpdater(url), notifmocks.NewPublisher(), aDone)
 }
 
 func TestCreateAgentGroup(t *testing.T) {
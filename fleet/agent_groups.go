@@ -18,6 +18,7 @@ type AgentGroup struct {
 	Description    *string
 	MFChannelID    string
 	Tags           *types.Tags
+	Selector       TagSelector
 	Created        time.Time
 	MatchingAgents types.Metadata
 }
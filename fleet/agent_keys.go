@@ -0,0 +1,118 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package fleet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orb-community/orb/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// KeyRotationTimeout bounds how long fleet waits for an agent to persist a new key
+	KeyRotationTimeout = 10 * time.Second
+
+	thingKeyRequestTimeout = 10 * time.Second
+)
+
+var (
+	// ErrKeyRotation indicates the agent could not persist the new key, its current key is kept
+	ErrKeyRotation = errors.New("agent failed to persist the new key")
+	// ErrKeyRotationTimeout indicates the agent did not confirm the new key in KeyRotationTimeout
+	ErrKeyRotationTimeout = errors.New("agent did not confirm the new key in time")
+)
+
+// ThingKeyUpdater sets the key of a Mainflux Thing, which the Mainflux SDK does not cover
+type ThingKeyUpdater interface {
+	UpdateThingKey(token string, thingID string, key string) error
+}
+
+var _ ThingKeyUpdater = (*thingKeyUpdater)(nil)
+
+type thingKeyUpdater struct {
+	thingsURL string
+	client    *http.Client
+}
+
+// NewThingKeyUpdater returns a ThingKeyUpdater using the Mainflux Things HTTP API
+func NewThingKeyUpdater(thingsURL string) ThingKeyUpdater {
+	return &thingKeyUpdater{
+		thingsURL: thingsURL,
+		client:    &http.Client{Timeout: thingKeyRequestTimeout},
+	}
+}
+
+func (u *thingKeyUpdater) UpdateThingKey(token string, thingID string, key string) error {
+	body, err := json.Marshal(map[string]string{"key": key})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/things/%s/key", u.thingsURL, thingID), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := u.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to update thing key: %s", res.Status)
+	}
+	return nil
+}
+
+type AgentKeyService interface {
+	// RotateAgentKey issues a new key to an online agent. The key is delivered to the agent first and set on its
+	// Thing, revoking the previous key, only once the agent confirmed it persisted it. The agent then reconnects with it.
+	RotateAgentKey(ctx context.Context, token string, thingID string) (Agent, error)
+}
+
+func (svc fleetService) RotateAgentKey(ctx context.Context, token string, thingID string) (Agent, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return Agent{}, err
+	}
+
+	agent, err := svc.agentRepo.RetrieveByID(ctx, ownerID, thingID)
+	if err != nil {
+		return Agent{}, err
+	}
+	// an agent not receiving the new key would be locked out once the previous one is revoked
+	if agent.State != Online {
+		return Agent{}, errors.Wrap(errors.ErrConflict, ErrAgentNotOnline)
+	}
+
+	key := uuid.NewString()
+	requestID, err := svc.agentComms.RequestAgentKeyRotation(ctx, agent, key)
+	if err != nil {
+		return Agent{}, err
+	}
+
+	if err := svc.thingKeys.UpdateThingKey(token, agent.MFThingID, key); err != nil {
+		if errN := svc.agentComms.NotifyAgentKeyRotationCommit(ctx, agent, requestID, false); errN != nil {
+			svc.logger.Error("failed to notify agent of aborted key rotation", zap.String("agent_id", agent.MFThingID), zap.Error(errN))
+		}
+		return Agent{}, errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	if err := svc.agentComms.NotifyAgentKeyRotationCommit(ctx, agent, requestID, true); err != nil {
+		// the agent falls back to the persisted key when reconnecting with the previous one fails
+		svc.logger.Error("failed to notify agent of committed key rotation", zap.String("agent_id", agent.MFThingID), zap.Error(err))
+	}
+
+	agent.MFKeyID = key
+	return agent, nil
+}
@@ -0,0 +1,74 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package fleet_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/orb-community/orb/fleet"
+	flmocks "github.com/orb-community/orb/fleet/mocks"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateAgentKey(t *testing.T) {
+	users := flmocks.NewAuthService(map[string]string{token: email})
+	thingsServer := newThingsServer(newThingsService(users))
+	defer thingsServer.Close()
+
+	agentRepo := flmocks.NewAgentRepositoryMock()
+	fleetService := newFleetService(users, thingsServer.URL, flmocks.NewAgentGroupRepository(), agentRepo)
+
+	online, err := createAgent(t, "rotate-agent", fleetService)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	online.State = fleet.Online
+	err = agentRepo.UpdateHeartbeatByIDWithChannel(context.Background(), online)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	offline, err := createAgent(t, "rotate-offline-agent", fleetService)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		id    string
+		token string
+		err   error
+	}{
+		"rotate key of an online agent": {
+			id:    online.MFThingID,
+			token: token,
+			err:   nil,
+		},
+		"rotate key of an offline agent": {
+			id:    offline.MFThingID,
+			token: token,
+			err:   fleet.ErrAgentNotOnline,
+		},
+		"rotate key of non-existing agent": {
+			id:    "9bb1b244-a199-93c2-aa03-28067b431e2c",
+			token: token,
+			err:   fleet.ErrNotFound,
+		},
+		"rotate key with wrong credentials": {
+			id:    online.MFThingID,
+			token: "wrong",
+			err:   fleet.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			agent, err := fleetService.RotateAgentKey(context.Background(), tc.token, tc.id)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+			if err == nil {
+				assert.Equal(t, tc.id, agent.MFThingID, fmt.Sprintf("%s: expected agent %s got %s", desc, tc.id, agent.MFThingID))
+				assert.NotEmpty(t, agent.MFKeyID, fmt.Sprintf("%s: expected a new key", desc))
+				assert.NotEqual(t, online.MFKeyID, agent.MFKeyID, fmt.Sprintf("%s: expected the key to change", desc))
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package fleet
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"github.com/orb-community/orb/pkg/errors"
+)
+
+const (
+	// MaxAgentLogEntries bounds the log entries kept for each agent, older entries are discarded
+	MaxAgentLogEntries = 1000
+	// MaxAgentLogMessageSize bounds the size of the message of a log entry received from an agent
+	MaxAgentLogMessageSize = 2048
+)
+
+var (
+	// ErrInvalidLogLevel indicates a log level other than debug, info, warn or error
+	ErrInvalidLogLevel = errors.New("invalid log level")
+)
+
+// logLevels known log levels, from the least to the most severe, the agent logs with zap level names
+var logLevels = []string{"debug", "info", "warn", "error", "dpanic", "panic", "fatal"}
+
+type AgentLogService interface {
+	// ViewAgentLogs retrieves the latest log entries of the agent and its backends since the given time, at or above the given level
+	ViewAgentLogs(ctx context.Context, token string, thingID string, since time.Time, level string, limit uint64) ([]AgentLogEntry, error)
+}
+
+type AgentLogRepository interface {
+	// SaveAgentLogs appends log entries to the agent log buffer, discarding the oldest ones above MaxAgentLogEntries
+	SaveAgentLogs(ctx context.Context, ownerID string, thingID string, entries []AgentLogEntry) error
+	// RetrieveAgentLogs retrieves the latest log entries of the agent since the given time having one of the levels, oldest first
+	RetrieveAgentLogs(ctx context.Context, ownerID string, thingID string, since time.Time, levels []string, limit uint64) ([]AgentLogEntry, error)
+}
+
+// LogLevelsFrom lists the log levels at or above the given one, every level when it is empty
+func LogLevelsFrom(level string) ([]string, error) {
+	if level == "" {
+		return logLevels, nil
+	}
+	for i, l := range logLevels {
+		if l == level {
+			return logLevels[i:], nil
+		}
+	}
+	return nil, ErrInvalidLogLevel
+}
+
+func (svc fleetService) ViewAgentLogs(ctx context.Context, token string, thingID string, since time.Time, level string, limit uint64) ([]AgentLogEntry, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	levels, err := LogLevelsFrom(level)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	if _, err := svc.agentRepo.RetrieveByID(ctx, ownerID, thingID); err != nil {
+		return nil, err
+	}
+
+	return svc.agentRepo.RetrieveAgentLogs(ctx, ownerID, thingID, since, levels, limit)
+}
+
+// TruncateAgentLogMessage bounds the message to MaxAgentLogMessageSize bytes, cutting it before a rune rather than inside one
+func TruncateAgentLogMessage(message string) string {
+	if len(message) <= MaxAgentLogMessageSize {
+		return message
+	}
+	end := MaxAgentLogMessageSize
+	for end > 0 && !utf8.RuneStart(message[end]) {
+		end--
+	}
+	return message[:end]
+}
+
+// sanitizeAgentLogs drops entries beyond the agent log buffer and bounds the fields coming from the agent
+func sanitizeAgentLogs(entries []AgentLogEntry) []AgentLogEntry {
+	if len(entries) > MaxAgentLogEntries {
+		entries = entries[len(entries)-MaxAgentLogEntries:]
+	}
+	for i := range entries {
+		if _, err := LogLevelsFrom(entries[i].Level); err != nil || entries[i].Level == "" {
+			entries[i].Level = "info"
+		}
+		if entries[i].Source == "" {
+			entries[i].Source = "agent"
+		}
+		entries[i].Message = TruncateAgentLogMessage(entries[i].Message)
+		if entries[i].TimeStamp.IsZero() {
+			entries[i].TimeStamp = time.Now()
+		}
+	}
+	return entries
+}
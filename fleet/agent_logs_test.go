@@ -0,0 +1,167 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package fleet_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/orb-community/orb/fleet"
+	flmocks "github.com/orb-community/orb/fleet/mocks"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogLevelsFrom(t *testing.T) {
+	cases := map[string]struct {
+		level  string
+		levels []string
+		err    error
+	}{
+		"levels from empty level": {
+			level:  "",
+			levels: []string{"debug", "info", "warn", "error", "dpanic", "panic", "fatal"},
+			err:    nil,
+		},
+		"levels from warn": {
+			level:  "warn",
+			levels: []string{"warn", "error", "dpanic", "panic", "fatal"},
+			err:    nil,
+		},
+		"levels from unknown level": {
+			level: "verbose",
+			err:   fleet.ErrInvalidLogLevel,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			levels, err := fleet.LogLevelsFrom(tc.level)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+			assert.Equal(t, tc.levels, levels, fmt.Sprintf("%s: expected %v got %v", desc, tc.levels, levels))
+		})
+	}
+}
+
+func TestTruncateAgentLogMessage(t *testing.T) {
+	cases := map[string]struct {
+		message string
+		size    int
+	}{
+		"short message": {
+			message: "agent started",
+			size:    len("agent started"),
+		},
+		"ascii message": {
+			message: strings.Repeat("x", fleet.MaxAgentLogMessageSize+10),
+			size:    fleet.MaxAgentLogMessageSize,
+		},
+		"multi-byte rune across the limit": {
+			// the 3 bytes rune starts one byte before the limit, so it is dropped whole
+			message: strings.Repeat("x", fleet.MaxAgentLogMessageSize-1) + "€" + "x",
+			size:    fleet.MaxAgentLogMessageSize - 1,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			message := fleet.TruncateAgentLogMessage(tc.message)
+			assert.Len(t, message, tc.size, fmt.Sprintf("%s: expected %d bytes got %d", desc, tc.size, len(message)))
+			assert.True(t, utf8.ValidString(message), fmt.Sprintf("%s: expected valid UTF-8", desc))
+		})
+	}
+}
+
+func TestViewAgentLogs(t *testing.T) {
+	users := flmocks.NewAuthService(map[string]string{token: email})
+	thingsServer := newThingsServer(newThingsService(users))
+	defer thingsServer.Close()
+
+	agentRepo := flmocks.NewAgentRepositoryMock()
+	fleetService := newFleetService(users, thingsServer.URL, flmocks.NewAgentGroupRepository(), agentRepo)
+
+	ag, err := createAgent(t, "logs-agent", fleetService)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	now := time.Now()
+	entries := []fleet.AgentLogEntry{
+		{TimeStamp: now.Add(-2 * time.Hour), Level: "info", Source: "agent", Message: "agent started"},
+		{TimeStamp: now.Add(-time.Hour), Level: "warn", Source: "pktvisor", Message: "pktvisor stderr"},
+		{TimeStamp: now, Level: "error", Source: "otel", Message: "otel stderr"},
+	}
+	err = agentRepo.SaveAgentLogs(context.Background(), ag.MFOwnerID, ag.MFThingID, entries)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		id       string
+		token    string
+		since    time.Time
+		level    string
+		limit    uint64
+		messages []string
+		err      error
+	}{
+		"view logs of an existing agent": {
+			id:       ag.MFThingID,
+			token:    token,
+			messages: []string{"agent started", "pktvisor stderr", "otel stderr"},
+			err:      nil,
+		},
+		"view logs since a given time": {
+			id:       ag.MFThingID,
+			token:    token,
+			since:    now.Add(-90 * time.Minute),
+			messages: []string{"pktvisor stderr", "otel stderr"},
+			err:      nil,
+		},
+		"view logs at or above a given level": {
+			id:       ag.MFThingID,
+			token:    token,
+			level:    "error",
+			messages: []string{"otel stderr"},
+			err:      nil,
+		},
+		"view latest logs within limit": {
+			id:       ag.MFThingID,
+			token:    token,
+			limit:    1,
+			messages: []string{"otel stderr"},
+			err:      nil,
+		},
+		"view logs with invalid level": {
+			id:    ag.MFThingID,
+			token: token,
+			level: "verbose",
+			err:   errors.ErrMalformedEntity,
+		},
+		"view logs with wrong credentials": {
+			id:    ag.MFThingID,
+			token: "wrong",
+			err:   fleet.ErrUnauthorizedAccess,
+		},
+		"view logs of non-existing agent": {
+			id:    "9bb1b244-a199-93c2-aa03-28067b431e2c",
+			token: token,
+			err:   fleet.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			logs, err := fleetService.ViewAgentLogs(context.Background(), tc.token, tc.id, tc.since, tc.level, tc.limit)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+			var messages []string
+			for _, e := range logs {
+				messages = append(messages, e.Message)
+			}
+			assert.Equal(t, tc.messages, messages, fmt.Sprintf("%s: expected %v got %v", desc, tc.messages, messages))
+		})
+	}
+}
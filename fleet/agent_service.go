@@ -16,6 +16,7 @@ import (
 	"github.com/orb-community/orb/fleet/backend"
 	"github.com/orb-community/orb/pkg/errors"
 	"go.uber.org/zap"
+	"sort"
 	"strings"
 )
 
@@ -26,6 +27,9 @@ var (
 	// It can be due to networking error or invalid/unauthorized request.
 	ErrThings = errors.New("failed to receive response from Things service")
 
+	// ErrInvalidBackend indicates a backend unknown to fleet or not run by the agent
+	ErrInvalidBackend = errors.New("invalid backend")
+
 	errCreateThing   = errors.New("failed to create thing")
 	errThingNotFound = errors.New("thing not found")
 )
@@ -82,18 +86,29 @@ func (svc fleetService) ViewAgentMatchingGroupsByID(ctx context.Context, token s
 	return matchingGroups, nil
 }
 
-func (svc fleetService) ResetAgent(ctx context.Context, token string, agentID string) error {
+func (svc fleetService) ResetAgent(ctx context.Context, token string, agentID string, backendName string, reapplyPolicies bool) error {
 	ownerID, err := svc.identify(token)
 	if err != nil {
 		return err
 	}
 
+	if backendName != "" && !backend.HaveBackend(backendName) {
+		return errors.Wrap(errors.ErrMalformedEntity, ErrInvalidBackend)
+	}
+
 	agent, err := svc.agentRepo.RetrieveByID(ctx, ownerID, agentID)
 	if err != nil {
 		return err
 	}
 
-	return svc.agentComms.NotifyAgentReset(ctx, agent, true, "Reset initiated from control plane")
+	// agents which reported their capabilities can only restart the backends they run
+	if backends, ok := agent.AgentMetadata["backends"].(map[string]interface{}); ok && backendName != "" {
+		if _, ok := backends[backendName]; !ok {
+			return errors.Wrap(errors.ErrMalformedEntity, ErrInvalidBackend)
+		}
+	}
+
+	return svc.agentComms.NotifyAgentReset(ctx, agent, backendName, reapplyPolicies, "Reset initiated from control plane")
 }
 
 func (svc fleetService) ViewAgentByIDInternal(ctx context.Context, ownerID string, id string) (Agent, error) {
@@ -186,6 +201,12 @@ func (svc fleetService) EditAgent(ctx context.Context, token string, agent Agent
 	}
 	agent.MFOwnerID = ownerID
 
+	return svc.editAgent(ctx, token, agent)
+}
+
+// editAgent updates the name and orb tags of an agent of agent.MFOwnerID and refreshes its group memberships
+func (svc fleetService) editAgent(ctx context.Context, token string, agent Agent) (Agent, error) {
+	ownerID := agent.MFOwnerID
 	currentAgent, err := svc.agentRepo.RetrieveByID(ctx, ownerID, agent.MFThingID)
 	if err != nil {
 		return Agent{}, err
@@ -244,6 +265,11 @@ func (svc fleetService) RemoveAgent(ctx context.Context, token, thingID string)
 		return nil
 	}
 
+	return svc.removeAgent(ctx, token, res)
+}
+
+// removeAgent deletes the Thing and RPC Channel of the agent along with the agent itself
+func (svc fleetService) removeAgent(ctx context.Context, token string, res Agent) error {
 	if errT := svc.mfsdk.DeleteThing(res.MFThingID, token); errT != nil {
 		svc.logger.Error("failed to delete thing", zap.Error(errT), zap.String("thing_id", res.MFThingID))
 	}
@@ -252,12 +278,7 @@ func (svc fleetService) RemoveAgent(ctx context.Context, token, thingID string)
 		svc.logger.Error("failed to delete channel", zap.Error(errT), zap.String("channel_id", res.MFChannelID))
 	}
 
-	err = svc.agentRepo.Delete(ctx, ownerID, thingID)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return svc.agentRepo.Delete(ctx, res.MFOwnerID, res.MFThingID)
 }
 
 func (svc fleetService) ListAgentBackends(ctx context.Context, token string) ([]string, error) {
@@ -287,6 +308,48 @@ func (svc fleetService) ViewAgentInfoByChannelIDInternal(ctx context.Context, ch
 	return res, nil
 }
 
+func (svc fleetService) ViewBackendComponentsInternal(ctx context.Context, ownerID string, backendName string) (map[string][]string, error) {
+	metadata, err := svc.agentRepo.RetrieveAgentMetadataByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]map[string]bool)
+	for _, md := range metadata {
+		backends, _ := md["backends"].(map[string]interface{})
+		be, _ := backends[backendName].(map[string]interface{})
+		data, _ := be["data"].(map[string]interface{})
+		for kind, value := range data {
+			// only lists of names are components, anything else (i.e. pktvisor taps) is skipped
+			list, ok := value.([]interface{})
+			if !ok {
+				continue
+			}
+			for _, v := range list {
+				name, ok := v.(string)
+				if !ok {
+					continue
+				}
+				if seen[kind] == nil {
+					seen[kind] = make(map[string]bool)
+				}
+				seen[kind][name] = true
+			}
+		}
+	}
+
+	components := make(map[string][]string, len(seen))
+	for kind, names := range seen {
+		list := make([]string, 0, len(names))
+		for name := range names {
+			list = append(list, name)
+		}
+		sort.Strings(list)
+		components[kind] = list
+	}
+	return components, nil
+}
+
 func (svc fleetService) GetPolicyState(ctx context.Context, agent Agent) (map[string]interface{}, error) {
 
 	jsonHb, err := json.Marshal(agent.LastHBData)
@@ -540,6 +540,58 @@ func TestViewAgentBackend(t *testing.T) {
 	}
 }
 
+func TestResetAgent(t *testing.T) {
+	users := flmocks.NewAuthService(map[string]string{token: email})
+
+	thingsServer := newThingsServer(newThingsService(users))
+	fleetService := newService(users, thingsServer.URL)
+
+	ag, err := createAgent(t, "reset-agent", fleetService)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		id      string
+		backend string
+		token   string
+		err     error
+	}{
+		"reset existing agent": {
+			id:    ag.MFThingID,
+			token: token,
+			err:   nil,
+		},
+		"reset a backend of existing agent": {
+			id:      ag.MFThingID,
+			backend: "pktvisor",
+			token:   token,
+			err:     nil,
+		},
+		"reset a backend not registered": {
+			id:      ag.MFThingID,
+			backend: "invalid",
+			token:   token,
+			err:     fleet.ErrInvalidBackend,
+		},
+		"reset non-existing agent": {
+			id:    wrongID,
+			token: token,
+			err:   fleet.ErrNotFound,
+		},
+		"reset agent with wrong credentials": {
+			id:    ag.MFThingID,
+			token: invalidToken,
+			err:   fleet.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			err := fleetService.ResetAgent(context.Background(), tc.token, tc.id, tc.backend, false)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+		})
+	}
+}
+
 func TestViewAgentInfoByChannelIDInternal(t *testing.T) {
 	users := flmocks.NewAuthService(map[string]string{token: email})
 
@@ -3,23 +3,36 @@ package fleet
 import (
 	"context"
 	"fmt"
+	"github.com/orb-community/orb/notifications"
 	"go.uber.org/zap"
 	"time"
 )
 
 const (
-	HeartbeatFreq  = 60 * time.Second
+	// HeartbeatFreq how often to check for stale agents
+	HeartbeatFreq = 60 * time.Second
+	// DefaultTimeout stale timeout of agents whose owner and groups have no heartbeat configuration
 	DefaultTimeout = 300 * time.Second
 )
 
 func (svc *fleetService) checkState(t time.Time) {
 	svc.logger.Info("checking for stale agents")
-	count, err := svc.agentRepo.SetStaleStatus(context.Background(), DefaultTimeout)
+	stale, err := svc.agentRepo.SetStaleStatus(context.Background(), DefaultTimeout)
 	if err != nil {
 		svc.logger.Error("failed to change agents status to stale", zap.Error(err))
 	}
-	if count > 0 {
-		svc.logger.Info(fmt.Sprintf("%d agents with more than %v without heartbeats had their state changed to stale", count, DefaultTimeout))
+	if len(stale) > 0 {
+		svc.logger.Info(fmt.Sprintf("%d agents without heartbeats within their stale timeout had their state changed to stale", len(stale)))
+	}
+	for _, agent := range stale {
+		svc.notifier.Publish(context.Background(), notifications.StateEvent{
+			OwnerID:    agent.MFOwnerID,
+			Entity:     notifications.EntityAgent,
+			EntityID:   agent.MFThingID,
+			EntityName: agent.Name.String(),
+			State:      notifications.StateStale,
+			Timestamp:  t,
+		})
 	}
 }
 
@@ -0,0 +1,168 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package fleet
+
+import (
+	"context"
+	"time"
+
+	"github.com/orb-community/orb/pkg/errors"
+)
+
+const (
+	// DefaultHistoryWindow is the time window used when the history or availability is requested without one
+	DefaultHistoryWindow = 24 * time.Hour
+	// MaxHistoryWindow bounds the time window of the history and availability of an agent
+	MaxHistoryWindow = 90 * 24 * time.Hour
+)
+
+var (
+	// ErrInvalidHistoryWindow indicates a time window ending before it starts or longer than MaxHistoryWindow
+	ErrInvalidHistoryWindow = errors.New("invalid agent history time window")
+)
+
+// AgentStateEvent is a state transition of an agent, or of one of its backends when Backend is set, recorded by
+// the database every time the state changes
+type AgentStateEvent struct {
+	ID            int64
+	MFThingID     string
+	MFOwnerID     string
+	State         string
+	PreviousState string
+	Backend       string
+	Reason        string
+	Created       time.Time
+}
+
+// AgentAvailability summarizes the agent states over a time window
+type AgentAvailability struct {
+	From           time.Time
+	To             time.Time
+	UptimePercent  float64
+	FlapCount      int
+	StateDurations map[string]time.Duration
+}
+
+type AgentStateHistoryService interface {
+	// ViewAgentHistory retrieves the state transitions of the agent and its backends within the time window
+	ViewAgentHistory(ctx context.Context, token string, thingID string, from time.Time, to time.Time, limit uint64) ([]AgentStateEvent, error)
+	// ViewAgentAvailability summarizes the time the agent spent on each state within the time window
+	ViewAgentAvailability(ctx context.Context, token string, thingID string, from time.Time, to time.Time) (AgentAvailability, error)
+}
+
+type AgentStateEventRepository interface {
+	// RetrieveStateEvents retrieves the state transitions of the agent within the time window, oldest first.
+	// When limited, the latest transitions of the window are retrieved
+	RetrieveStateEvents(ctx context.Context, ownerID string, thingID string, from time.Time, to time.Time, limit uint64) ([]AgentStateEvent, error)
+	// RetrieveLastStateEvent retrieves the last transition of the agent state, ignoring backends, at or before the given time
+	RetrieveLastStateEvent(ctx context.Context, ownerID string, thingID string, at time.Time) (AgentStateEvent, error)
+}
+
+func (svc fleetService) ViewAgentHistory(ctx context.Context, token string, thingID string, from time.Time, to time.Time, limit uint64) ([]AgentStateEvent, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateHistoryWindow(from, to); err != nil {
+		return nil, err
+	}
+
+	if _, err := svc.agentRepo.RetrieveByID(ctx, ownerID, thingID); err != nil {
+		return nil, err
+	}
+
+	return svc.agentRepo.RetrieveStateEvents(ctx, ownerID, thingID, from, to, limit)
+}
+
+func (svc fleetService) ViewAgentAvailability(ctx context.Context, token string, thingID string, from time.Time, to time.Time) (AgentAvailability, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return AgentAvailability{}, err
+	}
+
+	if err := validateHistoryWindow(from, to); err != nil {
+		return AgentAvailability{}, err
+	}
+
+	agent, err := svc.agentRepo.RetrieveByID(ctx, ownerID, thingID)
+	if err != nil {
+		return AgentAvailability{}, err
+	}
+
+	// the agent did not exist before it was provisioned, that time does not count as downtime
+	if from.Before(agent.Created) {
+		from = agent.Created
+	}
+	if !to.After(from) {
+		return NewAgentAvailability(from, to, nil, nil), nil
+	}
+
+	var initial *AgentStateEvent
+	last, err := svc.agentRepo.RetrieveLastStateEvent(ctx, ownerID, thingID, from)
+	if err != nil && !errors.Contains(err, errors.ErrNotFound) {
+		return AgentAvailability{}, err
+	}
+	if err == nil {
+		initial = &last
+	}
+
+	events, err := svc.agentRepo.RetrieveStateEvents(ctx, ownerID, thingID, from, to, 0)
+	if err != nil {
+		return AgentAvailability{}, err
+	}
+
+	return NewAgentAvailability(from, to, initial, events), nil
+}
+
+// NewAgentAvailability computes the availability over the time window from the state the agent was in when the
+// window starts and the transitions within it, backend transitions are ignored. Time before the first known state
+// is not accounted for.
+func NewAgentAvailability(from time.Time, to time.Time, initial *AgentStateEvent, events []AgentStateEvent) AgentAvailability {
+	availability := AgentAvailability{
+		From:           from,
+		To:             to,
+		StateDurations: make(map[string]time.Duration),
+	}
+
+	state := ""
+	if initial != nil {
+		state = initial.State
+	}
+	since := from
+	for _, e := range events {
+		if e.Backend != "" || e.Created.Before(from) || e.Created.After(to) {
+			continue
+		}
+		if state != "" {
+			availability.StateDurations[state] += e.Created.Sub(since)
+		}
+		if state == Online.String() && e.State != Online.String() {
+			availability.FlapCount++
+		}
+		state = e.State
+		since = e.Created
+	}
+	if state != "" {
+		availability.StateDurations[state] += to.Sub(since)
+	}
+
+	var known time.Duration
+	for _, d := range availability.StateDurations {
+		known += d
+	}
+	if known > 0 {
+		availability.UptimePercent = float64(availability.StateDurations[Online.String()]) / float64(known) * 100
+	}
+
+	return availability
+}
+
+func validateHistoryWindow(from time.Time, to time.Time) error {
+	if from.IsZero() || to.IsZero() || to.Before(from) || to.Sub(from) > MaxHistoryWindow {
+		return errors.Wrap(errors.ErrMalformedEntity, ErrInvalidHistoryWindow)
+	}
+	return nil
+}
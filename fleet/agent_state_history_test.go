@@ -0,0 +1,203 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package fleet_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/orb-community/orb/fleet"
+	flmocks "github.com/orb-community/orb/fleet/mocks"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAgentAvailability(t *testing.T) {
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(10 * time.Hour)
+	at := func(h int, state string) fleet.AgentStateEvent {
+		return fleet.AgentStateEvent{State: state, Created: from.Add(time.Duration(h) * time.Hour)}
+	}
+	online := fleet.AgentStateEvent{State: "online", Created: from.Add(-time.Hour)}
+
+	cases := map[string]struct {
+		initial   *fleet.AgentStateEvent
+		events    []fleet.AgentStateEvent
+		uptime    float64
+		flaps     int
+		durations map[string]time.Duration
+	}{
+		"availability of an agent online the whole window": {
+			initial:   &online,
+			uptime:    100,
+			flaps:     0,
+			durations: map[string]time.Duration{"online": 10 * time.Hour},
+		},
+		"availability of an agent going stale and back": {
+			initial: &online,
+			events:  []fleet.AgentStateEvent{at(2, "stale"), at(4, "online"), at(9, "offline")},
+			uptime:  70,
+			flaps:   2,
+			durations: map[string]time.Duration{
+				"online":  7 * time.Hour,
+				"stale":   2 * time.Hour,
+				"offline": time.Hour,
+			},
+		},
+		"availability of an agent provisioned within the window": {
+			events: []fleet.AgentStateEvent{at(5, "new"), at(6, "online")},
+			uptime: 80,
+			flaps:  0,
+			durations: map[string]time.Duration{
+				"new":    time.Hour,
+				"online": 4 * time.Hour,
+			},
+		},
+		"availability ignoring backend transitions": {
+			initial: &online,
+			events: []fleet.AgentStateEvent{
+				{State: "backend_error", Backend: "pktvisor", Created: from.Add(time.Hour)},
+				at(5, "stale"),
+			},
+			uptime: 50,
+			flaps:  1,
+			durations: map[string]time.Duration{
+				"online": 5 * time.Hour,
+				"stale":  5 * time.Hour,
+			},
+		},
+		"availability without any known state": {
+			uptime:    0,
+			flaps:     0,
+			durations: map[string]time.Duration{},
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			availability := fleet.NewAgentAvailability(from, to, tc.initial, tc.events)
+			assert.InDelta(t, tc.uptime, availability.UptimePercent, 0.001, fmt.Sprintf("%s: expected uptime %f got %f", desc, tc.uptime, availability.UptimePercent))
+			assert.Equal(t, tc.flaps, availability.FlapCount, fmt.Sprintf("%s: expected %d flaps got %d", desc, tc.flaps, availability.FlapCount))
+			assert.Equal(t, tc.durations, availability.StateDurations, fmt.Sprintf("%s: expected %v got %v", desc, tc.durations, availability.StateDurations))
+		})
+	}
+}
+
+func TestViewAgentHistory(t *testing.T) {
+	users := flmocks.NewAuthService(map[string]string{token: email})
+	thingsServer := newThingsServer(newThingsService(users))
+	defer thingsServer.Close()
+
+	agentRepo := flmocks.NewAgentRepositoryMock()
+	fleetService := newFleetService(users, thingsServer.URL, flmocks.NewAgentGroupRepository(), agentRepo)
+
+	ag, err := createAgent(t, "history-agent", fleetService)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	for _, state := range []fleet.State{fleet.Online, fleet.Online, fleet.Offline} {
+		ag.State = state
+		ag.LastHBData = types.Metadata{}
+		err := agentRepo.UpdateHeartbeatByIDWithChannel(context.Background(), ag)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	now := time.Now().Add(time.Minute)
+	cases := map[string]struct {
+		id     string
+		token  string
+		from   time.Time
+		to     time.Time
+		limit  uint64
+		states []string
+		err    error
+	}{
+		"view history of an existing agent": {
+			id:     ag.MFThingID,
+			token:  token,
+			from:   now.Add(-time.Hour),
+			to:     now,
+			states: []string{"new", "online", "offline"},
+			err:    nil,
+		},
+		"view the latest events of a window holding more than the limit": {
+			id:     ag.MFThingID,
+			token:  token,
+			from:   now.Add(-time.Hour),
+			to:     now,
+			limit:  2,
+			states: []string{"online", "offline"},
+			err:    nil,
+		},
+		"view history before the agent existed": {
+			id:     ag.MFThingID,
+			token:  token,
+			from:   now.Add(-3 * time.Hour),
+			to:     now.Add(-2 * time.Hour),
+			states: nil,
+			err:    nil,
+		},
+		"view history with wrong credentials": {
+			id:    ag.MFThingID,
+			token: "wrong",
+			from:  now.Add(-time.Hour),
+			to:    now,
+			err:   fleet.ErrUnauthorizedAccess,
+		},
+		"view history of non-existing agent": {
+			id:    "9bb1b244-a199-93c2-aa03-28067b431e2c",
+			token: token,
+			from:  now.Add(-time.Hour),
+			to:    now,
+			err:   fleet.ErrNotFound,
+		},
+		"view history with a window ending before it starts": {
+			id:    ag.MFThingID,
+			token: token,
+			from:  now,
+			to:    now.Add(-time.Hour),
+			err:   fleet.ErrInvalidHistoryWindow,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			events, err := fleetService.ViewAgentHistory(context.Background(), tc.token, tc.id, tc.from, tc.to, tc.limit)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+			var states []string
+			for _, e := range events {
+				states = append(states, e.State)
+			}
+			assert.Equal(t, tc.states, states, fmt.Sprintf("%s: expected %v got %v", desc, tc.states, states))
+		})
+	}
+}
+
+func TestViewAgentAvailability(t *testing.T) {
+	users := flmocks.NewAuthService(map[string]string{token: email})
+	thingsServer := newThingsServer(newThingsService(users))
+	defer thingsServer.Close()
+
+	agentRepo := flmocks.NewAgentRepositoryMock()
+	fleetService := newFleetService(users, thingsServer.URL, flmocks.NewAgentGroupRepository(), agentRepo)
+
+	ag, err := createAgent(t, "availability-agent", fleetService)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	ag.State = fleet.Online
+	err = agentRepo.UpdateHeartbeatByIDWithChannel(context.Background(), ag)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	now := time.Now().Add(time.Minute)
+	availability, err := fleetService.ViewAgentAvailability(context.Background(), token, ag.MFThingID, now.Add(-time.Hour), now)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, 0, availability.FlapCount, fmt.Sprintf("expected no flaps got %d", availability.FlapCount))
+	assert.Greater(t, availability.UptimePercent, 90.0, fmt.Sprintf("expected the agent mostly online got %f", availability.UptimePercent))
+
+	_, err = fleetService.ViewAgentAvailability(context.Background(), token, ag.MFThingID, now.Add(-100*24*time.Hour), now)
+	assert.True(t, errors.Contains(err, fleet.ErrInvalidHistoryWindow), fmt.Sprintf("expected %s got %s", fleet.ErrInvalidHistoryWindow, err))
+}
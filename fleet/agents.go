@@ -105,16 +105,24 @@ type AgentService interface {
 	ViewAgentBackend(ctx context.Context, token string, name string) (interface{}, error)
 	//ViewAgentInfoByChannelIDInternal return a correspondent ownerID, name and agent tags by a provided channel id
 	ViewAgentInfoByChannelIDInternal(ctx context.Context, channelID string) (Agent, error)
-	// ResetAgent reset a agent on edge by a provided agent
-	ResetAgent(ct context.Context, token string, agentID string) error
+	// ResetAgent reset a agent on edge by a provided agent, or only one of its backends when backendName is set
+	ResetAgent(ct context.Context, token string, agentID string, backendName string, reapplyPolicies bool) error
 	// GetPolicyState get all policies state per agent in a formatted way from a given existent agent
 	GetPolicyState(ctx context.Context, agent Agent) (map[string]interface{}, error)
 	// ViewAgentMatchingGroupsByIDInternal Groups this Agent currently belongs to, according to matching agent and group tags
 	ViewAgentMatchingGroupsByIDInternal(ctx context.Context, agentID string, ownerID string) (MatchingGroups, error)
+	// ViewBackendComponentsInternal merges the component lists (i.e. otel receivers and processors) reported in the
+	// capabilities of the given backend by every agent of the owner
+	ViewBackendComponentsInternal(ctx context.Context, ownerID string, backendName string) (map[string][]string, error)
 }
 
 type AgentRepository interface {
 	AgentHeartbeatRepository // may move this out so it can be in e.g. redis
+	AgentStateEventRepository
+	HeartbeatConfigRepository
+	AgentLogRepository
+	EnrollmentTokenRepository
+	BulkJobRepository
 
 	// Save persists the Agent. Successful operation is indicated by non-nil
 	// error response.
@@ -127,8 +135,8 @@ type AgentRepository interface {
 	RetrieveAll(ctx context.Context, owner string, pm PageMetadata) (Page, error)
 	// RetrieveAllByAgentGroupID retrieves Agents in the specified group
 	RetrieveAllByAgentGroupID(ctx context.Context, owner string, agentGroupID string, onlinishOnly bool) ([]Agent, error)
-	// RetrieveMatchingAgents retrieve the matching agents by tags
-	RetrieveMatchingAgents(ctx context.Context, owner string, tags types.Tags) (types.Metadata, error)
+	// RetrieveMatchingAgents retrieve the matching agents by tags and tag selector
+	RetrieveMatchingAgents(ctx context.Context, owner string, tags types.Tags, selector TagSelector) (types.Metadata, error)
 	// UpdateAgentByID update the the tags and name for the Agent having provided ID and owner
 	UpdateAgentByID(ctx context.Context, ownerID string, agent Agent) error
 	// RetrieveByID retrieves the Agent having the provided ID and owner
@@ -137,8 +145,9 @@ type AgentRepository interface {
 	Delete(ctx context.Context, ownerID string, thingID string) error
 	// RetrieveAgentMetadataByOwner retrieves the Metadata having the OwnerID
 	RetrieveAgentMetadataByOwner(ctx context.Context, ownerID string) ([]types.Metadata, error)
-	// SetStaleStatus change status to stale according provided duration without heartbeats
-	SetStaleStatus(ctx context.Context, minutes time.Duration) (int64, error)
+	// SetStaleStatus change status to stale according the stale timeout of each agent, or the provided duration without heartbeats when not configured,
+	// returning the agents which went stale
+	SetStaleStatus(ctx context.Context, minutes time.Duration) ([]Agent, error)
 	// RetrieveAgentInfoByChannelID gRPC version to retrieve ownerID, name and agent tags by a provided channelID
 	RetrieveAgentInfoByChannelID(ctx context.Context, channelID string) (Agent, error)
 }
@@ -27,6 +27,7 @@ type grpcClient struct {
 	retrieveAgentGroup           endpoint.Endpoint
 	retrieveOwnerByChannelID     endpoint.Endpoint
 	retrieveAgentInfoByChannelID endpoint.Endpoint
+	retrieveBackendComponents    endpoint.Endpoint
 }
 
 func (g grpcClient) RetrieveAgent(ctx context.Context, in *pb.AgentByIDReq, opts ...grpc.CallOption) (*pb.AgentRes, error) {
@@ -93,6 +94,25 @@ func (g grpcClient) RetrieveAgentInfoByChannelID(ctx context.Context, in *pb.Age
 	return &pb.AgentInfoRes{OwnerID: ir.ownerID, AgentName: ir.agentName, AgentTags: ir.agentTags, OrbTags: ir.orbTags, AgentGroupIDs: ir.agentGroupIDs}, nil
 }
 
+func (g grpcClient) RetrieveBackendComponents(ctx context.Context, in *pb.BackendComponentsReq, opts ...grpc.CallOption) (*pb.BackendComponentsRes, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+
+	ar := accessBackendComponentsReq{OwnerID: in.OwnerID, Backend: in.Backend}
+
+	res, err := g.retrieveBackendComponents(ctx, ar)
+	if err != nil {
+		return nil, err
+	}
+
+	ir := res.(backendComponentsRes)
+	components := make(map[string]*pb.ComponentList, len(ir.components))
+	for kind, names := range ir.components {
+		components[kind] = &pb.ComponentList{Names: names}
+	}
+	return &pb.BackendComponentsRes{Components: components}, nil
+}
+
 // NewClient returns new gRPC client instance.
 func NewClient(tracer opentracing.Tracer, conn *grpc.ClientConn, timeout time.Duration) pb.FleetServiceClient {
 	svcName := "fleet.FleetService"
@@ -131,6 +151,14 @@ func NewClient(tracer opentracing.Tracer, conn *grpc.ClientConn, timeout time.Du
 			decodeAgentInfoResponse,
 			pb.AgentInfoRes{},
 		).Endpoint()),
+		retrieveBackendComponents: kitot.TraceClient(tracer, "retrieve_backend_components")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"RetrieveBackendComponents",
+			encodeRetrieveBackendComponentsRequest,
+			decodeBackendComponentsResponse,
+			pb.BackendComponentsRes{},
+		).Endpoint()),
 	}
 }
 
@@ -200,3 +228,20 @@ func decodeAgentInfoResponse(ctx context.Context, grpcRes interface{}) (interfac
 		agentGroupIDs: res.GetAgentGroupIDs(),
 	}, nil
 }
+
+func encodeRetrieveBackendComponentsRequest(ctx context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(accessBackendComponentsReq)
+	return &pb.BackendComponentsReq{
+		OwnerID: req.OwnerID,
+		Backend: req.Backend,
+	}, nil
+}
+
+func decodeBackendComponentsResponse(ctx context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*pb.BackendComponentsRes)
+	components := make(map[string][]string, len(res.GetComponents()))
+	for kind, list := range res.GetComponents() {
+		components[kind] = list.GetNames()
+	}
+	return backendComponentsRes{components: components}, nil
+}
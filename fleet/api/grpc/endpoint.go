@@ -89,3 +89,17 @@ func retrieveAgentInfoByChannelIDEndpoint(svc fleet.Service) endpoint.Endpoint {
 		return res, nil
 	}
 }
+
+func retrieveBackendComponentsEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(accessBackendComponentsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		components, err := svc.ViewBackendComponentsInternal(ctx, req.OwnerID, req.Backend)
+		if err != nil {
+			return nil, err
+		}
+		return backendComponentsRes{components: components}, nil
+	}
+}
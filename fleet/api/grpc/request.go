@@ -41,6 +41,18 @@ func (req accessOwnerByChannelIDReq) validate() error {
 	return nil
 }
 
+type accessBackendComponentsReq struct {
+	OwnerID string
+	Backend string
+}
+
+func (req accessBackendComponentsReq) validate() error {
+	if req.OwnerID == "" || req.Backend == "" {
+		return fleet.ErrMalformedEntity
+	}
+	return nil
+}
+
 type accessAgentInfoByChannelIDReq struct {
 	ChannelID string
 }
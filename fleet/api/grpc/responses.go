@@ -25,6 +25,10 @@ type agentInfoRes struct {
 	agentGroupIDs []string
 }
 
+type backendComponentsRes struct {
+	components map[string][]string
+}
+
 type emptyRes struct {
 	err error
 }
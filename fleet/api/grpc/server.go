@@ -19,6 +19,7 @@ type grpcServer struct {
 	retrieveAgentGroup           kitgrpc.Handler
 	retrieveOwnerByChannelID     kitgrpc.Handler
 	retrieveAgentInfoByChannelID kitgrpc.Handler
+	retrieveBackendComponents    kitgrpc.Handler
 }
 
 func NewServer(tracer opentracing.Tracer, svc fleet.Service) pb.FleetServiceServer {
@@ -43,6 +44,11 @@ func NewServer(tracer opentracing.Tracer, svc fleet.Service) pb.FleetServiceServ
 			decodeRetrieveAgentInfoByChannelIDRequest,
 			encodeAgentInfoResponse,
 		),
+		retrieveBackendComponents: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "retrieve_backend_components")(retrieveBackendComponentsEndpoint(svc)),
+			decodeRetrieveBackendComponentsRequest,
+			encodeBackendComponentsResponse,
+		),
 	}
 }
 
@@ -81,6 +87,15 @@ func (gs *grpcServer) RetrieveAgentInfoByChannelID(ctx context.Context, req *pb.
 	return res.(*pb.AgentInfoRes), nil
 }
 
+func (gs *grpcServer) RetrieveBackendComponents(ctx context.Context, req *pb.BackendComponentsReq) (*pb.BackendComponentsRes, error) {
+	_, res, err := gs.retrieveBackendComponents.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*pb.BackendComponentsRes), nil
+}
+
 func decodeRetrieveAgentRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*pb.AgentByIDReq)
 	return accessByIDReq{AgentID: req.AgentID, OwnerID: req.OwnerID}, nil
@@ -138,6 +153,20 @@ func encodeAgentInfoResponse(_ context.Context, grpcRes interface{}) (interface{
 	}, nil
 }
 
+func decodeRetrieveBackendComponentsRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.BackendComponentsReq)
+	return accessBackendComponentsReq{OwnerID: req.OwnerID, Backend: req.Backend}, nil
+}
+
+func encodeBackendComponentsResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(backendComponentsRes)
+	components := make(map[string]*pb.ComponentList, len(res.components))
+	for kind, names := range res.components {
+		components[kind] = &pb.ComponentList{Names: names}
+	}
+	return &pb.BackendComponentsRes{Components: components}, nil
+}
+
 func encodeError(err error) error {
 	switch err {
 	case nil:
@@ -10,10 +10,12 @@ package http
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/go-kit/kit/endpoint"
 	"github.com/orb-community/orb/fleet"
 	"github.com/orb-community/orb/pkg/errors"
 	"github.com/orb-community/orb/pkg/types"
+	"time"
 )
 
 func addAgentGroupEndpoint(svc fleet.Service) endpoint.Endpoint {
@@ -32,6 +34,7 @@ func addAgentGroupEndpoint(svc fleet.Service) endpoint.Endpoint {
 			Name:        nID,
 			Description: &req.Description,
 			Tags:        &req.Tags,
+			Selector:    req.Selector,
 		}
 		saved, err := svc.CreateAgentGroup(c, req.token, group)
 		if err != nil {
@@ -43,6 +46,7 @@ func addAgentGroupEndpoint(svc fleet.Service) endpoint.Endpoint {
 			Name:           saved.Name.String(),
 			Description:    *saved.Description,
 			Tags:           *saved.Tags,
+			Selector:       saved.Selector,
 			MatchingAgents: saved.MatchingAgents,
 			created:        true,
 		}
@@ -66,6 +70,7 @@ func viewAgentGroupEndpoint(svc fleet.Service) endpoint.Endpoint {
 			Name:           agentGroup.Name.String(),
 			Description:    *agentGroup.Description,
 			Tags:           *agentGroup.Tags,
+			Selector:       agentGroup.Selector,
 			TsCreated:      agentGroup.Created,
 			MatchingAgents: agentGroup.MatchingAgents,
 		}
@@ -101,6 +106,7 @@ func listAgentGroupsEndpoint(svc fleet.Service) endpoint.Endpoint {
 				Name:           ag.Name.String(),
 				Description:    *ag.Description,
 				Tags:           *ag.Tags,
+				Selector:       ag.Selector,
 				TsCreated:      ag.Created,
 				MatchingAgents: ag.MatchingAgents,
 			}
@@ -134,6 +140,7 @@ func editAgentGroupEndpoint(svc fleet.Service) endpoint.Endpoint {
 			Name:        validName,
 			Description: req.Description,
 			Tags:        groupTags,
+			Selector:    req.Selector,
 		}
 
 		data, err := svc.EditAgentGroup(ctx, req.token, ag)
@@ -146,6 +153,7 @@ func editAgentGroupEndpoint(svc fleet.Service) endpoint.Endpoint {
 			Name:           data.Name.String(),
 			Description:    *data.Description,
 			Tags:           *data.Tags,
+			Selector:       data.Selector,
 			TsCreated:      data.Created,
 			MatchingAgents: data.MatchingAgents,
 		}
@@ -262,13 +270,300 @@ func viewAgentMatchingGroups(svc fleet.Service) endpoint.Endpoint {
 	}
 }
 
-func resetAgentEndpoint(svc fleet.Service) endpoint.Endpoint {
+func viewPolicyRolloutEndpoint(svc fleet.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		status, err := svc.ViewPolicyRollout(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := policyRolloutRes{
+			ID:              status.ID,
+			PolicyID:        status.PolicyID,
+			Version:         status.Version,
+			PreviousVersion: status.PreviousVersion,
+			GroupIDs:        status.GroupIDs,
+			CanaryPercent:   status.CanaryPercent,
+			CanaryCount:     status.CanaryCount,
+			OnFailure:       status.OnFailure,
+			Timeout:         int(status.Timeout.Seconds()),
+			State:           status.State,
+			Reason:          status.Reason,
+			TsCreated:       status.Created,
+			TsLastModified:  status.LastModified,
+			Agents:          make([]agentRolloutRes, len(status.Agents)),
+		}
+		for i, a := range status.Agents {
+			res.Agents[i] = agentRolloutRes{
+				AgentID:      a.AgentID,
+				AgentName:    a.AgentName,
+				AgentGroupID: a.AgentGroupID,
+				Canary:       a.Canary,
+				Version:      a.Version,
+				State:        a.State,
+				Error:        a.Error,
+			}
+		}
+		return res, nil
+	}
+}
+
+func viewAgentHistoryEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(agentHistoryReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		events, err := svc.ViewAgentHistory(ctx, req.token, req.id, req.from, req.to, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := agentHistoryRes{
+			AgentID: req.id,
+			From:    req.from,
+			To:      req.to,
+			Events:  make([]agentStateEventRes, len(events)),
+		}
+		for i, e := range events {
+			res.Events[i] = agentStateEventRes{
+				State:         e.State,
+				PreviousState: e.PreviousState,
+				Backend:       e.Backend,
+				Reason:        e.Reason,
+				TsCreated:     e.Created,
+			}
+		}
+		return res, nil
+	}
+}
+
+func viewAgentAvailabilityEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(agentHistoryReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		availability, err := svc.ViewAgentAvailability(ctx, req.token, req.id, req.from, req.to)
+		if err != nil {
+			return nil, err
+		}
+
+		res := agentAvailabilityRes{
+			AgentID:        req.id,
+			From:           availability.From,
+			To:             availability.To,
+			UptimePercent:  availability.UptimePercent,
+			FlapCount:      availability.FlapCount,
+			StateDurations: make(map[string]float64, len(availability.StateDurations)),
+		}
+		for state, d := range availability.StateDurations {
+			res.StateDurations[state] = d.Seconds()
+		}
+		return res, nil
+	}
+}
+
+func viewAgentLogsEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(agentLogsReq)
+
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
-		if err := svc.ResetAgent(ctx, req.token, req.id); err != nil {
+
+		entries, err := svc.ViewAgentLogs(ctx, req.token, req.id, req.since, req.level, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := agentLogsRes{
+			AgentID: req.id,
+			Entries: make([]agentLogEntryRes, len(entries)),
+		}
+		for i, e := range entries {
+			res.Entries[i] = agentLogEntryRes{
+				TimeStamp: e.TimeStamp,
+				Level:     e.Level,
+				Source:    e.Source,
+				Message:   e.Message,
+				Fields:    e.Fields,
+			}
+		}
+		return res, nil
+	}
+}
+
+func viewAgentPolicySnapshotEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(policySnapshotReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		snapshot, err := svc.ViewAgentPolicySnapshot(ctx, req.token, req.id, req.policyID)
+		if err != nil {
+			return nil, err
+		}
+
+		res := policySnapshotRes{
+			AgentID:    snapshot.AgentID,
+			PolicyID:   snapshot.PolicyID,
+			PolicyName: snapshot.PolicyName,
+			Datasets:   snapshot.Datasets,
+			BEVersion:  snapshot.BEVersion,
+			Format:     snapshot.Format,
+			TsCreated:  snapshot.Created,
+			Data:       string(snapshot.Data),
+		}
+		// json snapshots are embedded as is, any other format is returned as text
+		if snapshot.Format == "json" && json.Valid(snapshot.Data) {
+			res.Data = json.RawMessage(snapshot.Data)
+		}
+		return res, nil
+	}
+}
+
+func viewHeartbeatConfigEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(ownerResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		cfg, err := svc.ViewHeartbeatConfig(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		return toHeartbeatConfigRes(cfg), nil
+	}
+}
+
+func editHeartbeatConfigEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(heartbeatConfigReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		cfg, err := svc.EditHeartbeatConfig(ctx, req.token, fleet.HeartbeatConfig{
+			Interval:     time.Duration(req.HeartbeatInterval) * time.Second,
+			StaleTimeout: time.Duration(req.StaleTimeout) * time.Second,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return toHeartbeatConfigRes(cfg), nil
+	}
+}
+
+func viewAgentGroupHeartbeatConfigEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		cfg, err := svc.ViewAgentGroupHeartbeatConfig(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toHeartbeatConfigRes(cfg), nil
+	}
+}
+
+func editAgentGroupHeartbeatConfigEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(heartbeatConfigReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		cfg, err := svc.EditAgentGroupHeartbeatConfig(ctx, req.token, fleet.HeartbeatConfig{
+			AgentGroupID: req.id,
+			Interval:     time.Duration(req.HeartbeatInterval) * time.Second,
+			StaleTimeout: time.Duration(req.StaleTimeout) * time.Second,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return toHeartbeatConfigRes(cfg), nil
+	}
+}
+
+func removeAgentGroupHeartbeatConfigEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveAgentGroupHeartbeatConfig(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func viewAgentHeartbeatConfigEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		cfg, err := svc.ViewAgentHeartbeatConfig(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := toHeartbeatConfigRes(cfg)
+		res.AgentID = req.id
+		return res, nil
+	}
+}
+
+func toHeartbeatConfigRes(cfg fleet.HeartbeatConfig) heartbeatConfigRes {
+	res := heartbeatConfigRes{
+		AgentGroupID:      cfg.AgentGroupID,
+		HeartbeatInterval: int64(cfg.Interval.Seconds()),
+		StaleTimeout:      int64(cfg.StaleTimeout.Seconds()),
+	}
+	if !cfg.Updated.IsZero() {
+		res.TsUpdated = &cfg.Updated
+	}
+	return res
+}
+
+func resetAgentEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(resetAgentReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.ResetAgent(ctx, req.token, req.id, req.Backend, req.ReapplyPolicies); err != nil {
 			return nil, err
 		}
 		return response, nil
@@ -335,8 +630,9 @@ func validateAgentGroupEndpoint(svc fleet.Service) endpoint.Endpoint {
 		}
 
 		group := fleet.AgentGroup{
-			Name: nID,
-			Tags: &req.Tags,
+			Name:     nID,
+			Tags:     &req.Tags,
+			Selector: req.Selector,
 		}
 		validated, err := svc.ValidateAgentGroup(c, req.token, group)
 		if err != nil {
@@ -346,6 +642,7 @@ func validateAgentGroupEndpoint(svc fleet.Service) endpoint.Endpoint {
 		res := validateAgentGroupRes{
 			Name:           validated.Name.String(),
 			Tags:           *validated.Tags,
+			Selector:       validated.Selector,
 			MatchingAgents: validated.MatchingAgents,
 		}
 
@@ -475,3 +772,210 @@ func listAgentBackendsEndpoint(svc fleet.Service) endpoint.Endpoint {
 		}, nil
 	}
 }
+
+func addEnrollmentTokenEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(addEnrollmentTokenReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		nID, err := types.NewIdentifier(req.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		et := fleet.EnrollmentToken{
+			Name:        nID,
+			MaxUses:     req.MaxUses,
+			ExpiresAt:   req.ExpiresAt,
+			OrbTags:     req.OrbTags,
+			NamePattern: req.NamePattern,
+		}
+		saved, err := svc.CreateEnrollmentToken(ctx, req.token, et)
+		if err != nil {
+			return nil, err
+		}
+
+		res := toEnrollmentTokenRes(saved)
+		res.created = true
+		return res, nil
+	}
+}
+
+func listEnrollmentTokensEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(ownerResourceReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		tokens, err := svc.ListEnrollmentTokens(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := enrollmentTokensRes{EnrollmentTokens: []enrollmentTokenRes{}}
+		for _, et := range tokens {
+			res.EnrollmentTokens = append(res.EnrollmentTokens, toEnrollmentTokenRes(et))
+		}
+		return res, nil
+	}
+}
+
+func viewEnrollmentTokenEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(viewResourceReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		et, err := svc.ViewEnrollmentToken(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toEnrollmentTokenRes(et), nil
+	}
+}
+
+func revokeEnrollmentTokenEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(viewResourceReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RevokeEnrollmentToken(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func enrollAgentEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(enrollAgentReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		nID, err := types.NewIdentifier(req.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		saved, _, err := svc.EnrollAgent(ctx, req.enrollmentToken, fleet.Agent{Name: nID, AgentTags: req.AgentTags})
+		if err != nil {
+			return nil, err
+		}
+
+		res := agentRes{
+			Name:          saved.Name.String(),
+			ID:            saved.MFThingID,
+			State:         saved.State.String(),
+			Key:           saved.MFKeyID,
+			OrbTags:       *saved.OrbTags,
+			AgentTags:     saved.AgentTags,
+			AgentMetadata: saved.AgentMetadata,
+			LastHBData:    saved.LastHBData,
+			TsCreated:     saved.Created,
+			created:       true,
+			ChannelID:     saved.MFChannelID,
+		}
+
+		return res, nil
+	}
+}
+
+func toEnrollmentTokenRes(et fleet.EnrollmentToken) enrollmentTokenRes {
+	orbTags := et.OrbTags
+	if orbTags == nil {
+		orbTags = types.Tags{}
+	}
+	return enrollmentTokenRes{
+		ID:          et.ID,
+		Name:        et.Name.String(),
+		Token:       et.Token,
+		MaxUses:     et.MaxUses,
+		Uses:        et.Uses,
+		ExpiresAt:   et.ExpiresAt,
+		OrbTags:     orbTags,
+		NamePattern: et.NamePattern,
+		Revoked:     et.Revoked,
+		TsCreated:   et.Created,
+	}
+}
+
+func bulkAgentOperationEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(bulkAgentOperationReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		op := fleet.BulkOperation{
+			Operation: req.Operation,
+			Filter:    req.Filter,
+			OrbTags:   req.OrbTags,
+			TagKeys:   req.TagKeys,
+			Reason:    req.Reason,
+			DryRun:    req.DryRun,
+		}
+		job, agents, err := svc.BulkAgentOperation(ctx, req.token, op)
+		if err != nil {
+			return nil, err
+		}
+
+		res := toBulkJobRes(job)
+		if req.DryRun {
+			// a dry run lists the agents the operation would apply to, nothing was started
+			res.State = ""
+			res.DryRun = true
+			res.Agents = make([]bulkAgentRes, len(agents))
+			for i, a := range agents {
+				res.Agents[i] = bulkAgentRes{ID: a.MFThingID, Name: a.Name.String(), State: a.State.String()}
+			}
+			return res, nil
+		}
+		res.started = true
+		return res, nil
+	}
+}
+
+func viewBulkJobEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(viewResourceReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		job, err := svc.ViewBulkJob(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toBulkJobRes(job), nil
+	}
+}
+
+func rotateAgentKeyEndpoint(svc fleet.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(viewResourceReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		agent, err := svc.RotateAgentKey(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return agentKeyRes{
+			ID:        agent.MFThingID,
+			Key:       agent.MFKeyID,
+			ChannelID: agent.MFChannelID,
+		}, nil
+	}
+}
@@ -22,6 +22,7 @@ import (
 	http2 "github.com/orb-community/orb/fleet/api/http"
 	"github.com/orb-community/orb/fleet/backend/pktvisor"
 	flmocks "github.com/orb-community/orb/fleet/mocks"
+	notifmocks "github.com/orb-community/orb/notifications/mocks"
 	"github.com/orb-community/orb/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -133,6 +134,7 @@ func newThingsServer(svc things.Service) *httptest.Server {
 func newService(auth mainflux.AuthServiceClient, url string) fleet.Service {
 	agentGroupRepo := flmocks.NewAgentGroupRepository()
 	agentRepo := flmocks.NewAgentRepositoryMock()
+	rolloutRepo := flmocks.NewPolicyRolloutRepository()
 	agentComms := flmocks.NewFleetCommService(agentRepo, agentGroupRepo)
 	logger, _ := zap.NewDevelopment()
 	config := mfsdk.Config{
@@ -142,7 +144,7 @@ func newService(auth mainflux.AuthServiceClient, url string) fleet.Service {
 	mfsdk := mfsdk.NewSDK(config)
 	pktvisor.Register(auth, agentRepo)
 	aDone := make(chan bool)
-	return fleet.NewFleetService(logger, auth, agentRepo, agentGroupRepo, agentComms, mfsdk, aDone)
+	return fleet.NewFleetService(logger, auth, agentRepo, agentGroupRepo, rolloutRepo, agentComms, mfsdk, fleet.NewThingKeyUpdater(url), notifmocks.NewPublisher(), aDone)
 }
 
 func newServer(svc fleet.Service) *httptest.Server {
@@ -161,6 +163,11 @@ func TestCreateAgentGroup(t *testing.T) {
 
 	var missingTagsJson = "{\n	\"name\": \"group\", \n	\"tags\": {}, \n	\"description\": \"An example agent group representing european dns nodes\", \n	\"validate_only\": false \n}"
 	var invalidNameJson = "{\n	\"name\": \"g\", \n	\"tags\": {\n		\"region\": \"eu\", \n		\"node_type\": \"dns\"\n	}, \n	\"description\": \"An example agent group representing european dns nodes\", \n	\"validate_only\": false \n}"
+	var selectorJson = `{"name": "selector-group", "tags": {}, "selector": [{"key": "region", "operator": "in", "values": ["eu", "us"]}, {"key": "decommissioned", "operator": "!exists"}]}`
+	var tagsAndSelectorJson = `{"name": "tags-selector-group", "tags": {"node_type": "dns"}, "selector": [{"key": "site", "operator": "prefix", "values": ["fra"]}]}`
+	var invalidOperatorJson = `{"name": "invalid-selector-group", "tags": {"node_type": "dns"}, "selector": [{"key": "region", "operator": "like", "values": ["eu"]}]}`
+	var invalidRegexJson = `{"name": "invalid-regex-group", "selector": [{"key": "region", "operator": "regex", "values": ["eu-("]}]}`
+	var missingValuesJson = `{"name": "missing-values-group", "selector": [{"key": "region", "operator": "in"}]}`
 
 	// Conflict scenario
 	createAgentGroup(t, "eu-agents-conflict", &cli)
@@ -221,6 +228,41 @@ func TestCreateAgentGroup(t *testing.T) {
 			status:      http.StatusBadRequest,
 			location:    "/agent_groups",
 		},
+		"add a agent group with a tag selector only": {
+			req:         selectorJson,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			location:    "/agent_groups",
+		},
+		"add a agent group with tags and a tag selector": {
+			req:         tagsAndSelectorJson,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			location:    "/agent_groups",
+		},
+		"add a agent group with an unknown selector operator": {
+			req:         invalidOperatorJson,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "/agent_groups",
+		},
+		"add a agent group with an invalid selector regex": {
+			req:         invalidRegexJson,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "/agent_groups",
+		},
+		"add a agent group with a selector missing values": {
+			req:         missingValuesJson,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "/agent_groups",
+		},
 	}
 
 	for desc, tc := range cases {
@@ -1767,3 +1809,556 @@ type updateAgentReq struct {
 	Name  *string     `json:"name,omitempty"`
 	Tags  *types.Tags `json:"orb_tags,omitempty"`
 }
+
+func TestUpdateHeartbeatConfig(t *testing.T) {
+	cli := newClientServer(t)
+
+	cases := map[string]struct {
+		req         string
+		contentType string
+		auth        string
+		status      int
+	}{
+		"update heartbeat config": {
+			req:         `{"heartbeat_interval": 30, "stale_timeout": 120}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		"update heartbeat config with timeout shorter than two intervals": {
+			req:         `{"heartbeat_interval": 30, "stale_timeout": 45}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		"update heartbeat config without interval": {
+			req:         `{"stale_timeout": 120}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		"update heartbeat config with invalid content type": {
+			req:         `{"heartbeat_interval": 30, "stale_timeout": 120}`,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		"update heartbeat config with invalid token": {
+			req:         `{"heartbeat_interval": 30, "stale_timeout": 120}`,
+			contentType: contentType,
+			auth:        invalidToken,
+			status:      http.StatusUnauthorized,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			req := testRequest{
+				client:      cli.server.Client(),
+				method:      http.MethodPut,
+				url:         fmt.Sprintf("%s/agents/heartbeat_config", cli.server.URL),
+				contentType: tc.contentType,
+				token:       fmt.Sprintf("Bearer %s", tc.auth),
+				body:        strings.NewReader(tc.req),
+			}
+			res, err := req.make()
+			require.Nil(t, err, "%s: unexpected error: %s", desc, err)
+			assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+		})
+	}
+}
+
+func TestViewAgentLogs(t *testing.T) {
+	cli := newClientServer(t)
+
+	ag, err := createAgent(t, "logs-agent", &cli)
+	require.Nil(t, err, "unexpected error: %s", err)
+
+	cases := map[string]struct {
+		id     string
+		query  string
+		auth   string
+		status int
+	}{
+		"view logs of existing agent": {
+			id:     ag.MFThingID,
+			auth:   token,
+			status: http.StatusOK,
+		},
+		"view logs since a given time at or above a level": {
+			id:     ag.MFThingID,
+			query:  "?since=2022-01-01T00:00:00Z&level=warn&limit=10",
+			auth:   token,
+			status: http.StatusOK,
+		},
+		"view logs with invalid level": {
+			id:     ag.MFThingID,
+			query:  "?level=verbose",
+			auth:   token,
+			status: http.StatusBadRequest,
+		},
+		"view logs with invalid since": {
+			id:     ag.MFThingID,
+			query:  "?since=yesterday",
+			auth:   token,
+			status: http.StatusBadRequest,
+		},
+		"view logs with limit above the buffer size": {
+			id:     ag.MFThingID,
+			query:  "?limit=5000",
+			auth:   token,
+			status: http.StatusBadRequest,
+		},
+		"view logs of non-existent agent": {
+			id:     wrongID,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		"view logs with invalid token": {
+			id:     ag.MFThingID,
+			auth:   invalidToken,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			req := testRequest{
+				client:      cli.server.Client(),
+				method:      http.MethodGet,
+				url:         fmt.Sprintf("%s/agents/%s/logs%s", cli.server.URL, tc.id, tc.query),
+				contentType: contentType,
+				token:       fmt.Sprintf("Bearer %s", tc.auth),
+			}
+			res, err := req.make()
+			require.Nil(t, err, "%s: unexpected error: %s", desc, err)
+			assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+		})
+	}
+}
+
+func TestResetAgent(t *testing.T) {
+	cli := newClientServer(t)
+
+	ag, err := createAgent(t, "reset-agent", &cli)
+	require.Nil(t, err, "unexpected error: %s", err)
+
+	cases := map[string]struct {
+		id          string
+		req         string
+		contentType string
+		auth        string
+		status      int
+	}{
+		"reset existing agent": {
+			id:     ag.MFThingID,
+			auth:   token,
+			status: http.StatusOK,
+		},
+		"reset a backend of existing agent": {
+			id:          ag.MFThingID,
+			req:         `{"backend": "pktvisor", "reapply_policies": true}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		"reset a backend not registered": {
+			id:          ag.MFThingID,
+			req:         `{"backend": "invalid"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		"reset agent reapplying policies without a backend": {
+			id:          ag.MFThingID,
+			req:         `{"reapply_policies": true}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		"reset a backend with invalid content type": {
+			id:          ag.MFThingID,
+			req:         `{"backend": "pktvisor"}`,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		"reset non-existent agent": {
+			id:     wrongID,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		"reset agent with invalid token": {
+			id:     ag.MFThingID,
+			auth:   invalidToken,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			req := testRequest{
+				client:      cli.server.Client(),
+				method:      http.MethodPost,
+				url:         fmt.Sprintf("%s/agents/%s/rpc/reset", cli.server.URL, tc.id),
+				contentType: tc.contentType,
+				token:       fmt.Sprintf("Bearer %s", tc.auth),
+				body:        strings.NewReader(tc.req),
+			}
+			res, err := req.make()
+			require.Nil(t, err, "%s: unexpected error: %s", desc, err)
+			assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+		})
+	}
+}
+
+func TestViewAgentPolicySnapshot(t *testing.T) {
+	cli := newClientServer(t)
+
+	ag, err := createAgent(t, "snapshot-agent", &cli)
+	require.Nil(t, err, "unexpected error: %s", err)
+
+	cases := map[string]struct {
+		id       string
+		policyID string
+		auth     string
+		status   int
+	}{
+		"view snapshot of a policy not running on the agent": {
+			id:       ag.MFThingID,
+			policyID: wrongID,
+			auth:     token,
+			status:   http.StatusNotFound,
+		},
+		"view snapshot of non-existent agent": {
+			id:       wrongID,
+			policyID: wrongID,
+			auth:     token,
+			status:   http.StatusNotFound,
+		},
+		"view snapshot with invalid token": {
+			id:       ag.MFThingID,
+			policyID: wrongID,
+			auth:     invalidToken,
+			status:   http.StatusUnauthorized,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			req := testRequest{
+				client:      cli.server.Client(),
+				method:      http.MethodGet,
+				url:         fmt.Sprintf("%s/agents/%s/policies/%s/snapshot", cli.server.URL, tc.id, tc.policyID),
+				contentType: contentType,
+				token:       fmt.Sprintf("Bearer %s", tc.auth),
+			}
+			res, err := req.make()
+			require.Nil(t, err, "%s: unexpected error: %s", desc, err)
+			assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+		})
+	}
+}
+
+func TestCreateEnrollmentToken(t *testing.T) {
+	cli := newClientServer(t)
+
+	expiresAt := time.Now().Add(time.Hour).Format(time.RFC3339)
+
+	cases := map[string]struct {
+		req         string
+		contentType string
+		auth        string
+		status      int
+	}{
+		"create a valid enrollment token": {
+			req:         fmt.Sprintf(`{"name": "edge-pops", "max_uses": 10, "expires_at": "%s", "orb_tags": {"region": "eu"}, "name_pattern": "pop-[a-z]+"}`, expiresAt),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+		},
+		"create an enrollment token without uses": {
+			req:         fmt.Sprintf(`{"name": "no-uses", "expires_at": "%s"}`, expiresAt),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		"create an enrollment token with an invalid name pattern": {
+			req:         fmt.Sprintf(`{"name": "bad-pattern", "max_uses": 10, "expires_at": "%s", "name_pattern": "pop-[a-z"}`, expiresAt),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		"create an enrollment token with invalid content type": {
+			req:         fmt.Sprintf(`{"name": "edge-pops", "max_uses": 10, "expires_at": "%s"}`, expiresAt),
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		"create an enrollment token with invalid token": {
+			req:         fmt.Sprintf(`{"name": "edge-pops", "max_uses": 10, "expires_at": "%s"}`, expiresAt),
+			contentType: contentType,
+			auth:        invalidToken,
+			status:      http.StatusUnauthorized,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			req := testRequest{
+				client:      cli.server.Client(),
+				method:      http.MethodPost,
+				url:         fmt.Sprintf("%s/agents/enrollment_tokens", cli.server.URL),
+				contentType: tc.contentType,
+				token:       fmt.Sprintf("Bearer %s", tc.auth),
+				body:        strings.NewReader(tc.req),
+			}
+			res, err := req.make()
+			require.Nil(t, err, "%s: unexpected error: %s", desc, err)
+			assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+		})
+	}
+}
+
+func TestEnrollAgent(t *testing.T) {
+	cli := newClientServer(t)
+
+	nID, err := types.NewIdentifier("edge-pops")
+	require.Nil(t, err, "unexpected error: %s", err)
+	et, err := cli.service.CreateEnrollmentToken(context.Background(), token, fleet.EnrollmentToken{
+		Name:        nID,
+		MaxUses:     10,
+		ExpiresAt:   time.Now().Add(time.Hour),
+		NamePattern: "pop-[a-z]+",
+	})
+	require.Nil(t, err, "unexpected error: %s", err)
+	err = cli.service.RevokeEnrollmentToken(context.Background(), token, et.ID)
+	require.Nil(t, err, "unexpected error: %s", err)
+	revoked := et.Token
+	et, err = cli.service.CreateEnrollmentToken(context.Background(), token, fleet.EnrollmentToken{
+		Name:        nID,
+		MaxUses:     10,
+		ExpiresAt:   time.Now().Add(time.Hour),
+		NamePattern: "pop-[a-z]+",
+	})
+	require.Nil(t, err, "unexpected error: %s", err)
+
+	cases := map[string]struct {
+		req         string
+		contentType string
+		auth        string
+		status      int
+	}{
+		"enroll an agent with a valid enrollment token": {
+			req:         `{"name": "pop-gru", "agent_tags": {"node_type": "dns"}}`,
+			contentType: contentType,
+			auth:        et.Token,
+			status:      http.StatusCreated,
+		},
+		"enroll an agent not matching the name pattern": {
+			req:         `{"name": "dc-gru"}`,
+			contentType: contentType,
+			auth:        et.Token,
+			status:      http.StatusBadRequest,
+		},
+		"enroll an agent with a revoked enrollment token": {
+			req:         `{"name": "pop-gig"}`,
+			contentType: contentType,
+			auth:        revoked,
+			status:      http.StatusUnauthorized,
+		},
+		"enroll an agent with a user token": {
+			req:         `{"name": "pop-cgh"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusUnauthorized,
+		},
+		"enroll an agent with invalid content type": {
+			req:         `{"name": "pop-sdu"}`,
+			contentType: "",
+			auth:        et.Token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			req := testRequest{
+				client:      cli.server.Client(),
+				method:      http.MethodPost,
+				url:         fmt.Sprintf("%s/agents/enroll", cli.server.URL),
+				contentType: tc.contentType,
+				token:       fmt.Sprintf("Bearer %s", tc.auth),
+				body:        strings.NewReader(tc.req),
+			}
+			res, err := req.make()
+			require.Nil(t, err, "%s: unexpected error: %s", desc, err)
+			assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+		})
+	}
+}
+
+func TestRotateAgentKey(t *testing.T) {
+	cli := newClientServer(t)
+
+	ag, err := createAgent(t, "rotate-agent", &cli)
+	require.Nil(t, err, "unexpected error: %s", err)
+
+	cases := map[string]struct {
+		id     string
+		auth   string
+		status int
+	}{
+		"rotate key of an agent not online": {
+			id:     ag.MFThingID,
+			auth:   token,
+			status: http.StatusConflict,
+		},
+		"rotate key of non-existent agent": {
+			id:     wrongID,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		"rotate key with invalid token": {
+			id:     ag.MFThingID,
+			auth:   invalidToken,
+			status: http.StatusUnauthorized,
+		},
+		"rotate key with empty token": {
+			id:     ag.MFThingID,
+			auth:   "",
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			req := testRequest{
+				client:      cli.server.Client(),
+				method:      http.MethodPost,
+				url:         fmt.Sprintf("%s/agents/%s/rotate_key", cli.server.URL, tc.id),
+				contentType: contentType,
+				token:       fmt.Sprintf("Bearer %s", tc.auth),
+			}
+			res, err := req.make()
+			require.Nil(t, err, "%s: unexpected error: %s", desc, err)
+			assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+		})
+	}
+}
+
+func TestBulkAgentOperation(t *testing.T) {
+	cli := newClientServer(t)
+
+	_, err := createAgent(t, "bulk-agent", &cli)
+	require.Nil(t, err, "unexpected error: %s", err)
+
+	cases := map[string]struct {
+		req         string
+		contentType string
+		auth        string
+		status      int
+	}{
+		"dry run of a bulk reset": {
+			req:         `{"operation": "reset", "filter": {"region": "us"}, "dry_run": true}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		"bulk add of orb tags": {
+			req:         `{"operation": "add_tags", "filter": {"region": "us"}, "orb_tags": {"pop": "gru"}}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusAccepted,
+		},
+		"bulk operation without filter": {
+			req:         `{"operation": "delete"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		"bulk operation unknown": {
+			req:         `{"operation": "upgrade", "filter": {"region": "us"}}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		"bulk operation with invalid token": {
+			req:         `{"operation": "reset", "filter": {"region": "us"}}`,
+			contentType: contentType,
+			auth:        invalidToken,
+			status:      http.StatusUnauthorized,
+		},
+		"bulk operation with invalid content type": {
+			req:         `{"operation": "reset", "filter": {"region": "us"}}`,
+			contentType: "application/xml",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			req := testRequest{
+				client:      cli.server.Client(),
+				method:      http.MethodPost,
+				url:         fmt.Sprintf("%s/agents/bulk", cli.server.URL),
+				contentType: tc.contentType,
+				token:       fmt.Sprintf("Bearer %s", tc.auth),
+				body:        strings.NewReader(tc.req),
+			}
+			res, err := req.make()
+			require.Nil(t, err, "%s: unexpected error: %s", desc, err)
+			assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+		})
+	}
+}
+
+func TestViewBulkJob(t *testing.T) {
+	cli := newClientServer(t)
+
+	_, err := createAgent(t, "bulk-agent", &cli)
+	require.Nil(t, err, "unexpected error: %s", err)
+	job, _, err := cli.service.BulkAgentOperation(context.Background(), token, fleet.BulkOperation{
+		Operation: fleet.BulkReset,
+		Filter:    types.Tags{"region": "us"},
+	})
+	require.Nil(t, err, "unexpected error: %s", err)
+
+	cases := map[string]struct {
+		id     string
+		auth   string
+		status int
+	}{
+		"view existing bulk job": {
+			id:     job.ID,
+			auth:   token,
+			status: http.StatusOK,
+		},
+		"view non-existent bulk job": {
+			id:     wrongID,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		"view bulk job with invalid token": {
+			id:     job.ID,
+			auth:   invalidToken,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			req := testRequest{
+				client:      cli.server.Client(),
+				method:      http.MethodGet,
+				url:         fmt.Sprintf("%s/agents/bulk/%s", cli.server.URL, tc.id),
+				contentType: contentType,
+				token:       fmt.Sprintf("Bearer %s", tc.auth),
+			}
+			res, err := req.make()
+			require.Nil(t, err, "%s: unexpected error: %s", desc, err)
+			assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+		})
+	}
+}
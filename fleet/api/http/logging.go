@@ -32,7 +32,385 @@ func (l loggingMiddleware) ViewAgentMatchingGroupsByIDInternal(ctx context.Conte
 	return l.svc.ViewAgentMatchingGroupsByIDInternal(ctx, agentID, ownerID)
 }
 
-func (l loggingMiddleware) ResetAgent(ct context.Context, token string, agentID string) (err error) {
+func (l loggingMiddleware) ViewBackendComponentsInternal(ctx context.Context, ownerID string, backendName string) (_ map[string][]string, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: view_backend_components_internal",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: view_backend_components_internal",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ViewBackendComponentsInternal(ctx, ownerID, backendName)
+}
+
+func (l loggingMiddleware) StartPolicyRolloutInternal(ctx context.Context, rollout fleet.PolicyRollout) (_ fleet.PolicyRollout, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: start_policy_rollout_internal",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: start_policy_rollout_internal",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.StartPolicyRolloutInternal(ctx, rollout)
+}
+
+func (l loggingMiddleware) SupersedePolicyRolloutsInternal(ctx context.Context, ownerID string, policyID string) (err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: supersede_policy_rollouts_internal",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: supersede_policy_rollouts_internal",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.SupersedePolicyRolloutsInternal(ctx, ownerID, policyID)
+}
+
+func (l loggingMiddleware) CheckPolicyRolloutsInternal(ctx context.Context) (_ []fleet.PolicyRollout, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: check_policy_rollouts_internal",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: check_policy_rollouts_internal",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.CheckPolicyRolloutsInternal(ctx)
+}
+
+func (l loggingMiddleware) ViewPolicyRollout(ctx context.Context, token string, policyID string) (_ fleet.PolicyRolloutStatus, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: view_policy_rollout",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: view_policy_rollout",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ViewPolicyRollout(ctx, token, policyID)
+}
+
+func (l loggingMiddleware) ViewAgentHistory(ctx context.Context, token string, thingID string, from time.Time, to time.Time, limit uint64) (_ []fleet.AgentStateEvent, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: view_agent_history",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: view_agent_history",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ViewAgentHistory(ctx, token, thingID, from, to, limit)
+}
+
+func (l loggingMiddleware) ViewAgentAvailability(ctx context.Context, token string, thingID string, from time.Time, to time.Time) (_ fleet.AgentAvailability, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: view_agent_availability",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: view_agent_availability",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ViewAgentAvailability(ctx, token, thingID, from, to)
+}
+
+func (l loggingMiddleware) ViewHeartbeatConfig(ctx context.Context, token string) (_ fleet.HeartbeatConfig, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: view_heartbeat_config",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: view_heartbeat_config",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ViewHeartbeatConfig(ctx, token)
+}
+
+func (l loggingMiddleware) EditHeartbeatConfig(ctx context.Context, token string, cfg fleet.HeartbeatConfig) (_ fleet.HeartbeatConfig, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: edit_heartbeat_config",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: edit_heartbeat_config",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.EditHeartbeatConfig(ctx, token, cfg)
+}
+
+func (l loggingMiddleware) ViewAgentGroupHeartbeatConfig(ctx context.Context, token string, groupID string) (_ fleet.HeartbeatConfig, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: view_agent_group_heartbeat_config",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: view_agent_group_heartbeat_config",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ViewAgentGroupHeartbeatConfig(ctx, token, groupID)
+}
+
+func (l loggingMiddleware) EditAgentGroupHeartbeatConfig(ctx context.Context, token string, cfg fleet.HeartbeatConfig) (_ fleet.HeartbeatConfig, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: edit_agent_group_heartbeat_config",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: edit_agent_group_heartbeat_config",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.EditAgentGroupHeartbeatConfig(ctx, token, cfg)
+}
+
+func (l loggingMiddleware) RemoveAgentGroupHeartbeatConfig(ctx context.Context, token string, groupID string) (err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: remove_agent_group_heartbeat_config",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: remove_agent_group_heartbeat_config",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.RemoveAgentGroupHeartbeatConfig(ctx, token, groupID)
+}
+
+func (l loggingMiddleware) ViewAgentHeartbeatConfig(ctx context.Context, token string, thingID string) (_ fleet.HeartbeatConfig, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: view_agent_heartbeat_config",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: view_agent_heartbeat_config",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ViewAgentHeartbeatConfig(ctx, token, thingID)
+}
+
+func (l loggingMiddleware) ViewAgentLogs(ctx context.Context, token string, thingID string, since time.Time, level string, limit uint64) (_ []fleet.AgentLogEntry, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: view_agent_logs",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: view_agent_logs",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ViewAgentLogs(ctx, token, thingID, since, level, limit)
+}
+
+func (l loggingMiddleware) ViewAgentPolicySnapshot(ctx context.Context, token string, thingID string, policyID string) (_ fleet.PolicySnapshot, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: view_agent_policy_snapshot",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: view_agent_policy_snapshot",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ViewAgentPolicySnapshot(ctx, token, thingID, policyID)
+}
+
+func (l loggingMiddleware) CreateEnrollmentToken(ctx context.Context, token string, et fleet.EnrollmentToken) (_ fleet.EnrollmentToken, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: create_enrollment_token",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: create_enrollment_token",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.CreateEnrollmentToken(ctx, token, et)
+}
+
+func (l loggingMiddleware) ListEnrollmentTokens(ctx context.Context, token string) (_ []fleet.EnrollmentToken, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: list_enrollment_tokens",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: list_enrollment_tokens",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ListEnrollmentTokens(ctx, token)
+}
+
+func (l loggingMiddleware) ViewEnrollmentToken(ctx context.Context, token string, id string) (_ fleet.EnrollmentToken, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: view_enrollment_token",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: view_enrollment_token",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ViewEnrollmentToken(ctx, token, id)
+}
+
+func (l loggingMiddleware) RevokeEnrollmentToken(ctx context.Context, token string, id string) (err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: revoke_enrollment_token",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: revoke_enrollment_token",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.RevokeEnrollmentToken(ctx, token, id)
+}
+
+func (l loggingMiddleware) EnrollAgent(ctx context.Context, enrollmentToken string, a fleet.Agent) (_ fleet.Agent, _ fleet.EnrollmentToken, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: enroll_agent",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: enroll_agent",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.EnrollAgent(ctx, enrollmentToken, a)
+}
+
+func (l loggingMiddleware) RotateAgentKey(ctx context.Context, token string, thingID string) (_ fleet.Agent, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: rotate_agent_key",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: rotate_agent_key",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.RotateAgentKey(ctx, token, thingID)
+}
+
+func (l loggingMiddleware) BulkAgentOperation(ctx context.Context, token string, op fleet.BulkOperation) (_ fleet.BulkJob, _ []fleet.Agent, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: bulk_agent_operation",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: bulk_agent_operation",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.BulkAgentOperation(ctx, token, op)
+}
+
+func (l loggingMiddleware) ViewBulkJob(ctx context.Context, token string, id string) (_ fleet.BulkJob, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: view_bulk_job",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: view_bulk_job",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ViewBulkJob(ctx, token, id)
+}
+
+func (l loggingMiddleware) ClaimBulkJobInternal(ctx context.Context) (_ fleet.BulkJob, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: claim_bulk_job_internal",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: claim_bulk_job_internal",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ClaimBulkJobInternal(ctx)
+}
+
+func (l loggingMiddleware) ApplyBulkOperationInternal(ctx context.Context, job fleet.BulkJob, agentID string) (err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: apply_bulk_operation_internal",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: apply_bulk_operation_internal",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ApplyBulkOperationInternal(ctx, job, agentID)
+}
+
+func (l loggingMiddleware) CompleteBulkJobInternal(ctx context.Context, job fleet.BulkJob) (err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: complete_bulk_job_internal",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: complete_bulk_job_internal",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.CompleteBulkJobInternal(ctx, job)
+}
+
+func (l loggingMiddleware) FailStaleBulkJobsInternal(ctx context.Context) (err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: fail_stale_bulk_jobs_internal",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: fail_stale_bulk_jobs_internal",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.FailStaleBulkJobsInternal(ctx)
+}
+
+func (l loggingMiddleware) ResetAgent(ct context.Context, token string, agentID string, backendName string, reapplyPolicies bool) (err error) {
 	defer func(begin time.Time) {
 		if err != nil {
 			l.logger.Warn("method call: reset_agent",
@@ -43,7 +421,7 @@ func (l loggingMiddleware) ResetAgent(ct context.Context, token string, agentID
 				zap.Duration("duration", time.Since(begin)))
 		}
 	}(time.Now())
-	return l.svc.ResetAgent(ct, token, agentID)
+	return l.svc.ResetAgent(ct, token, agentID, backendName, reapplyPolicies)
 }
 
 func (l loggingMiddleware) ViewAgentInfoByChannelIDInternal(ctx context.Context, channelID string) (_ fleet.Agent, err error) {
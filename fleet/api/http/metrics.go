@@ -39,7 +39,317 @@ func (m metricsMiddleware) ViewAgentMatchingGroupsByIDInternal(ctx context.Conte
 	return m.svc.ViewAgentMatchingGroupsByIDInternal(ctx, agentID, ownerID)
 }
 
-func (m metricsMiddleware) ResetAgent(ct context.Context, token string, agentID string) error {
+func (m metricsMiddleware) ViewBackendComponentsInternal(ctx context.Context, ownerID string, backendName string) (map[string][]string, error) {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "viewBackendComponentsInternal",
+			"owner_id", ownerID,
+			"agent_id", "",
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ViewBackendComponentsInternal(ctx, ownerID, backendName)
+}
+
+func (m metricsMiddleware) StartPolicyRolloutInternal(ctx context.Context, rollout fleet.PolicyRollout) (fleet.PolicyRollout, error) {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "startPolicyRolloutInternal",
+			"owner_id", rollout.MFOwnerID,
+			"agent_id", "",
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.StartPolicyRolloutInternal(ctx, rollout)
+}
+
+func (m metricsMiddleware) SupersedePolicyRolloutsInternal(ctx context.Context, ownerID string, policyID string) error {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "supersedePolicyRolloutsInternal",
+			"owner_id", ownerID,
+			"agent_id", "",
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.SupersedePolicyRolloutsInternal(ctx, ownerID, policyID)
+}
+
+func (m metricsMiddleware) CheckPolicyRolloutsInternal(ctx context.Context) ([]fleet.PolicyRollout, error) {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "checkPolicyRolloutsInternal",
+			"owner_id", "",
+			"agent_id", "",
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.CheckPolicyRolloutsInternal(ctx)
+}
+
+func (m metricsMiddleware) ViewPolicyRollout(ctx context.Context, token string, policyID string) (fleet.PolicyRolloutStatus, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return fleet.PolicyRolloutStatus{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "viewPolicyRollout",
+			"owner_id", ownerID,
+			"agent_id", "",
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ViewPolicyRollout(ctx, token, policyID)
+}
+
+func (m metricsMiddleware) ViewAgentHistory(ctx context.Context, token string, thingID string, from time.Time, to time.Time, limit uint64) ([]fleet.AgentStateEvent, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "viewAgentHistory",
+			"owner_id", ownerID,
+			"agent_id", thingID,
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ViewAgentHistory(ctx, token, thingID, from, to, limit)
+}
+
+func (m metricsMiddleware) ViewAgentAvailability(ctx context.Context, token string, thingID string, from time.Time, to time.Time) (fleet.AgentAvailability, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return fleet.AgentAvailability{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "viewAgentAvailability",
+			"owner_id", ownerID,
+			"agent_id", thingID,
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ViewAgentAvailability(ctx, token, thingID, from, to)
+}
+
+func (m metricsMiddleware) ViewHeartbeatConfig(ctx context.Context, token string) (fleet.HeartbeatConfig, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return fleet.HeartbeatConfig{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "viewHeartbeatConfig",
+			"owner_id", ownerID,
+			"agent_id", "",
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ViewHeartbeatConfig(ctx, token)
+}
+
+func (m metricsMiddleware) EditHeartbeatConfig(ctx context.Context, token string, cfg fleet.HeartbeatConfig) (fleet.HeartbeatConfig, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return fleet.HeartbeatConfig{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "editHeartbeatConfig",
+			"owner_id", ownerID,
+			"agent_id", "",
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.EditHeartbeatConfig(ctx, token, cfg)
+}
+
+func (m metricsMiddleware) ViewAgentGroupHeartbeatConfig(ctx context.Context, token string, groupID string) (fleet.HeartbeatConfig, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return fleet.HeartbeatConfig{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "viewAgentGroupHeartbeatConfig",
+			"owner_id", ownerID,
+			"agent_id", "",
+			"group_id", groupID,
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ViewAgentGroupHeartbeatConfig(ctx, token, groupID)
+}
+
+func (m metricsMiddleware) EditAgentGroupHeartbeatConfig(ctx context.Context, token string, cfg fleet.HeartbeatConfig) (fleet.HeartbeatConfig, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return fleet.HeartbeatConfig{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "editAgentGroupHeartbeatConfig",
+			"owner_id", ownerID,
+			"agent_id", "",
+			"group_id", cfg.AgentGroupID,
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.EditAgentGroupHeartbeatConfig(ctx, token, cfg)
+}
+
+func (m metricsMiddleware) RemoveAgentGroupHeartbeatConfig(ctx context.Context, token string, groupID string) error {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "removeAgentGroupHeartbeatConfig",
+			"owner_id", ownerID,
+			"agent_id", "",
+			"group_id", groupID,
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.RemoveAgentGroupHeartbeatConfig(ctx, token, groupID)
+}
+
+func (m metricsMiddleware) ViewAgentHeartbeatConfig(ctx context.Context, token string, thingID string) (fleet.HeartbeatConfig, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return fleet.HeartbeatConfig{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "viewAgentHeartbeatConfig",
+			"owner_id", ownerID,
+			"agent_id", thingID,
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ViewAgentHeartbeatConfig(ctx, token, thingID)
+}
+
+func (m metricsMiddleware) ViewAgentLogs(ctx context.Context, token string, thingID string, since time.Time, level string, limit uint64) ([]fleet.AgentLogEntry, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "viewAgentLogs",
+			"owner_id", ownerID,
+			"agent_id", thingID,
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ViewAgentLogs(ctx, token, thingID, since, level, limit)
+}
+
+func (m metricsMiddleware) ViewAgentPolicySnapshot(ctx context.Context, token string, thingID string, policyID string) (fleet.PolicySnapshot, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return fleet.PolicySnapshot{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "viewAgentPolicySnapshot",
+			"owner_id", ownerID,
+			"agent_id", thingID,
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ViewAgentPolicySnapshot(ctx, token, thingID, policyID)
+}
+
+func (m metricsMiddleware) ResetAgent(ct context.Context, token string, agentID string, backendName string, reapplyPolicies bool) error {
 	ownerID, err := m.identify(token)
 	if err != nil {
 		return err
@@ -58,7 +368,7 @@ func (m metricsMiddleware) ResetAgent(ct context.Context, token string, agentID
 
 	}(time.Now())
 
-	return m.svc.ResetAgent(ct, token, agentID)
+	return m.svc.ResetAgent(ct, token, agentID, backendName, reapplyPolicies)
 }
 
 func (m metricsMiddleware) ViewAgentInfoByChannelIDInternal(ctx context.Context, channelID string) (agent fleet.Agent, _ error) {
@@ -415,6 +725,245 @@ func (m metricsMiddleware) GetPolicyState(ctx context.Context, agent fleet.Agent
 	return m.svc.GetPolicyState(ctx, agent)
 }
 
+func (m metricsMiddleware) CreateEnrollmentToken(ctx context.Context, token string, et fleet.EnrollmentToken) (fleet.EnrollmentToken, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return fleet.EnrollmentToken{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "createEnrollmentToken",
+			"owner_id", ownerID,
+			"agent_id", "",
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.CreateEnrollmentToken(ctx, token, et)
+}
+
+func (m metricsMiddleware) ListEnrollmentTokens(ctx context.Context, token string) ([]fleet.EnrollmentToken, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "listEnrollmentTokens",
+			"owner_id", ownerID,
+			"agent_id", "",
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ListEnrollmentTokens(ctx, token)
+}
+
+func (m metricsMiddleware) ViewEnrollmentToken(ctx context.Context, token string, id string) (fleet.EnrollmentToken, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return fleet.EnrollmentToken{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "viewEnrollmentToken",
+			"owner_id", ownerID,
+			"agent_id", "",
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ViewEnrollmentToken(ctx, token, id)
+}
+
+func (m metricsMiddleware) RevokeEnrollmentToken(ctx context.Context, token string, id string) error {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "revokeEnrollmentToken",
+			"owner_id", ownerID,
+			"agent_id", "",
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.RevokeEnrollmentToken(ctx, token, id)
+}
+
+func (m metricsMiddleware) EnrollAgent(ctx context.Context, enrollmentToken string, a fleet.Agent) (agent fleet.Agent, _ fleet.EnrollmentToken, _ error) {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "enrollAgent",
+			"owner_id", agent.MFOwnerID,
+			"agent_id", agent.MFThingID,
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.EnrollAgent(ctx, enrollmentToken, a)
+}
+
+func (m metricsMiddleware) RotateAgentKey(ctx context.Context, token string, thingID string) (fleet.Agent, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return fleet.Agent{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "rotateAgentKey",
+			"owner_id", ownerID,
+			"agent_id", thingID,
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.RotateAgentKey(ctx, token, thingID)
+}
+
+func (m metricsMiddleware) BulkAgentOperation(ctx context.Context, token string, op fleet.BulkOperation) (fleet.BulkJob, []fleet.Agent, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return fleet.BulkJob{}, nil, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "bulkAgentOperation",
+			"owner_id", ownerID,
+			"agent_id", "",
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.BulkAgentOperation(ctx, token, op)
+}
+
+func (m metricsMiddleware) ViewBulkJob(ctx context.Context, token string, id string) (fleet.BulkJob, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return fleet.BulkJob{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "viewBulkJob",
+			"owner_id", ownerID,
+			"agent_id", "",
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ViewBulkJob(ctx, token, id)
+}
+
+func (m metricsMiddleware) ClaimBulkJobInternal(ctx context.Context) (fleet.BulkJob, error) {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "claimBulkJobInternal",
+			"owner_id", "",
+			"agent_id", "",
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ClaimBulkJobInternal(ctx)
+}
+
+func (m metricsMiddleware) ApplyBulkOperationInternal(ctx context.Context, job fleet.BulkJob, agentID string) error {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "applyBulkOperationInternal",
+			"owner_id", job.MFOwnerID,
+			"agent_id", agentID,
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ApplyBulkOperationInternal(ctx, job, agentID)
+}
+
+func (m metricsMiddleware) CompleteBulkJobInternal(ctx context.Context, job fleet.BulkJob) error {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "completeBulkJobInternal",
+			"owner_id", job.MFOwnerID,
+			"agent_id", "",
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.CompleteBulkJobInternal(ctx, job)
+}
+
+func (m metricsMiddleware) FailStaleBulkJobsInternal(ctx context.Context) error {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "failStaleBulkJobsInternal",
+			"owner_id", "",
+			"agent_id", "",
+			"group_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.FailStaleBulkJobsInternal(ctx)
+}
+
 func (m metricsMiddleware) identify(token string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
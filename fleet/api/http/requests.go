@@ -9,6 +9,8 @@
 package http
 
 import (
+	"time"
+
 	"github.com/orb-community/orb/fleet"
 	"github.com/orb-community/orb/pkg/errors"
 	"github.com/orb-community/orb/pkg/types"
@@ -21,13 +23,17 @@ const (
 	idOrder      = "id"
 	ascDir       = "asc"
 	descDir      = "desc"
+
+	// maxHistoryLimit bounds the number of state transitions returned by the agent history
+	maxHistoryLimit = 1000
 )
 
 type addAgentGroupReq struct {
 	token       string
-	Name        string     `json:"name,omitempty"`
-	Description string     `json:"description,omitempty"`
-	Tags        types.Tags `json:"tags"`
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Tags        types.Tags        `json:"tags"`
+	Selector    fleet.TagSelector `json:"selector,omitempty"`
 }
 
 func (req addAgentGroupReq) validate() error {
@@ -38,9 +44,12 @@ func (req addAgentGroupReq) validate() error {
 	if req.Name == "" {
 		return errors.ErrMalformedEntity
 	}
-	if len(req.Tags) == 0 {
+	if len(req.Tags) == 0 && len(req.Selector) == 0 {
 		return errors.ErrMalformedEntity
 	}
+	if err := req.Selector.Validate(); err != nil {
+		return errors.Wrap(errors.ErrMalformedEntity, err)
+	}
 
 	_, err := types.NewIdentifier(req.Name)
 	if err != nil {
@@ -53,9 +62,10 @@ func (req addAgentGroupReq) validate() error {
 type updateAgentGroupReq struct {
 	id          string
 	token       string
-	Name        *string     `json:"name,omitempty"`
-	Description *string     `json:"description,omitempty"`
-	Tags        *types.Tags `json:"tags"`
+	Name        *string           `json:"name,omitempty"`
+	Description *string           `json:"description,omitempty"`
+	Tags        *types.Tags       `json:"tags"`
+	Selector    fleet.TagSelector `json:"selector"`
 }
 
 func (req updateAgentGroupReq) validate() error {
@@ -63,14 +73,18 @@ func (req updateAgentGroupReq) validate() error {
 	if req.token == "" {
 		return errors.ErrUnauthorizedAccess
 	}
-	if req.Name == nil && req.Tags == nil && req.Description == nil {
+	if req.Name == nil && req.Tags == nil && req.Description == nil && req.Selector == nil {
 		return errors.ErrMalformedEntity
 	}
 	if req.Tags != nil {
-		if len(*req.Tags) == 0 {
+		// empty tags are only allowed when the group selects its agents by tag selector
+		if len(*req.Tags) == 0 && len(req.Selector) == 0 {
 			return errors.ErrMalformedEntity
 		}
 	}
+	if err := req.Selector.Validate(); err != nil {
+		return errors.Wrap(errors.ErrMalformedEntity, err)
+	}
 
 	if req.Name != nil && *req.Name == "" {
 		return errors.ErrMalformedEntity
@@ -142,6 +156,119 @@ func (req viewResourceReq) validate() error {
 	return nil
 }
 
+type resetAgentReq struct {
+	token           string
+	id              string
+	Backend         string `json:"backend,omitempty"`
+	ReapplyPolicies bool   `json:"reapply_policies,omitempty"`
+}
+
+func (req resetAgentReq) validate() error {
+	if req.token == "" {
+		return errors.ErrUnauthorizedAccess
+	}
+	if req.id == "" {
+		return errors.ErrMalformedEntity
+	}
+	// policies are always requested again on a full reset
+	if req.Backend == "" && req.ReapplyPolicies {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+type policySnapshotReq struct {
+	token    string
+	id       string
+	policyID string
+}
+
+func (req policySnapshotReq) validate() error {
+	if req.token == "" {
+		return errors.ErrUnauthorizedAccess
+	}
+	if req.id == "" || req.policyID == "" {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+type agentHistoryReq struct {
+	token string
+	id    string
+	from  time.Time
+	to    time.Time
+	limit uint64
+}
+
+func (req agentHistoryReq) validate() error {
+	if req.token == "" {
+		return errors.ErrUnauthorizedAccess
+	}
+	if req.id == "" {
+		return errors.ErrMalformedEntity
+	}
+	if req.to.Before(req.from) || req.to.Sub(req.from) > fleet.MaxHistoryWindow {
+		return errors.Wrap(errors.ErrMalformedEntity, fleet.ErrInvalidHistoryWindow)
+	}
+	if req.limit > maxHistoryLimit {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+type agentLogsReq struct {
+	token string
+	id    string
+	since time.Time
+	level string
+	limit uint64
+}
+
+func (req agentLogsReq) validate() error {
+	if req.token == "" {
+		return errors.ErrUnauthorizedAccess
+	}
+	if req.id == "" {
+		return errors.ErrMalformedEntity
+	}
+	if _, err := fleet.LogLevelsFrom(req.level); err != nil {
+		return errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	if req.limit > fleet.MaxAgentLogEntries {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+type ownerResourceReq struct {
+	token string
+}
+
+func (req ownerResourceReq) validate() error {
+	if req.token == "" {
+		return errors.ErrUnauthorizedAccess
+	}
+	return nil
+}
+
+type heartbeatConfigReq struct {
+	token             string
+	id                string
+	HeartbeatInterval int64 `json:"heartbeat_interval"`
+	StaleTimeout      int64 `json:"stale_timeout"`
+}
+
+func (req heartbeatConfigReq) validate() error {
+	if req.token == "" {
+		return errors.ErrUnauthorizedAccess
+	}
+	if req.HeartbeatInterval <= 0 || req.StaleTimeout <= 0 {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
 type listResourcesReq struct {
 	token        string
 	pageMetadata fleet.PageMetadata
@@ -187,3 +314,64 @@ func (req *listAgentBackendsReq) validate() error {
 	}
 	return nil
 }
+
+type addEnrollmentTokenReq struct {
+	token       string
+	Name        string     `json:"name"`
+	MaxUses     uint64     `json:"max_uses"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	OrbTags     types.Tags `json:"orb_tags,omitempty"`
+	NamePattern string     `json:"name_pattern,omitempty"`
+}
+
+func (req addEnrollmentTokenReq) validate() error {
+	if req.token == "" {
+		return errors.ErrUnauthorizedAccess
+	}
+	if req.Name == "" || req.MaxUses == 0 || req.ExpiresAt.IsZero() {
+		return errors.ErrMalformedEntity
+	}
+	if _, err := types.NewIdentifier(req.Name); err != nil {
+		return errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return nil
+}
+
+type bulkAgentOperationReq struct {
+	token     string
+	Operation string     `json:"operation"`
+	Filter    types.Tags `json:"filter"`
+	OrbTags   types.Tags `json:"orb_tags,omitempty"`
+	TagKeys   []string   `json:"tag_keys,omitempty"`
+	Reason    string     `json:"reason,omitempty"`
+	DryRun    bool       `json:"dry_run,omitempty"`
+}
+
+func (req bulkAgentOperationReq) validate() error {
+	if req.token == "" {
+		return errors.ErrUnauthorizedAccess
+	}
+	if req.Operation == "" || len(req.Filter) == 0 {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+type enrollAgentReq struct {
+	enrollmentToken string
+	Name            string     `json:"name"`
+	AgentTags       types.Tags `json:"agent_tags,omitempty"`
+}
+
+func (req enrollAgentReq) validate() error {
+	if req.enrollmentToken == "" {
+		return errors.ErrUnauthorizedAccess
+	}
+	if req.Name == "" {
+		return errors.ErrMalformedEntity
+	}
+	if _, err := types.NewIdentifier(req.Name); err != nil {
+		return errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	return nil
+}
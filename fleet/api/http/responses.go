@@ -5,6 +5,7 @@
 package http
 
 import (
+	"github.com/orb-community/orb/fleet"
 	"github.com/orb-community/orb/pkg/types"
 	"net/http"
 	"time"
@@ -16,12 +17,13 @@ var (
 )
 
 type agentGroupRes struct {
-	ID             string         `json:"id"`
-	Name           string         `json:"name"`
-	Description    string         `json:"description,omitempty"`
-	Tags           types.Tags     `json:"tags"`
-	TsCreated      time.Time      `json:"ts_created,omitempty"`
-	MatchingAgents types.Metadata `json:"matching_agents,omitempty"`
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Description    string            `json:"description,omitempty"`
+	Tags           types.Tags        `json:"tags"`
+	Selector       fleet.TagSelector `json:"selector,omitempty"`
+	TsCreated      time.Time         `json:"ts_created,omitempty"`
+	MatchingAgents types.Metadata    `json:"matching_agents,omitempty"`
 	created        bool
 }
 
@@ -130,11 +132,12 @@ func (r removeRes) Empty() bool {
 }
 
 type validateAgentGroupRes struct {
-	ID             string         `json:"id,omitempty"`
-	Name           string         `json:"name"`
-	Description    string         `json:"description,omitempty"`
-	Tags           types.Tags     `json:"tags"`
-	MatchingAgents types.Metadata `json:"matching_agents,omitempty"`
+	ID             string            `json:"id,omitempty"`
+	Name           string            `json:"name"`
+	Description    string            `json:"description,omitempty"`
+	Tags           types.Tags        `json:"tags"`
+	Selector       fleet.TagSelector `json:"selector,omitempty"`
+	MatchingAgents types.Metadata    `json:"matching_agents,omitempty"`
 }
 
 func (s validateAgentGroupRes) Code() int {
@@ -182,6 +185,161 @@ func (s agentBackendsRes) Empty() bool {
 	return false
 }
 
+type policyRolloutRes struct {
+	ID              string            `json:"id"`
+	PolicyID        string            `json:"policy_id"`
+	Version         int32             `json:"version"`
+	PreviousVersion int32             `json:"previous_version"`
+	GroupIDs        []string          `json:"group_ids"`
+	CanaryPercent   int               `json:"canary_percent,omitempty"`
+	CanaryCount     int               `json:"canary_count,omitempty"`
+	OnFailure       string            `json:"on_failure"`
+	Timeout         int               `json:"timeout"`
+	State           string            `json:"state"`
+	Reason          string            `json:"reason,omitempty"`
+	TsCreated       time.Time         `json:"ts_created"`
+	TsLastModified  time.Time         `json:"ts_last_modified"`
+	Agents          []agentRolloutRes `json:"agents"`
+}
+
+type agentRolloutRes struct {
+	AgentID      string `json:"agent_id"`
+	AgentName    string `json:"agent_name"`
+	AgentGroupID string `json:"agent_group_id"`
+	Canary       bool   `json:"canary"`
+	Version      int32  `json:"version,omitempty"`
+	State        string `json:"state,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+func (s policyRolloutRes) Code() int {
+	return http.StatusOK
+}
+
+func (s policyRolloutRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (s policyRolloutRes) Empty() bool {
+	return false
+}
+
+type agentStateEventRes struct {
+	State         string    `json:"state"`
+	PreviousState string    `json:"previous_state,omitempty"`
+	Backend       string    `json:"backend,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+	TsCreated     time.Time `json:"ts_created"`
+}
+
+type agentHistoryRes struct {
+	AgentID string               `json:"agent_id"`
+	From    time.Time            `json:"from"`
+	To      time.Time            `json:"to"`
+	Events  []agentStateEventRes `json:"events"`
+}
+
+func (s agentHistoryRes) Code() int {
+	return http.StatusOK
+}
+
+func (s agentHistoryRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (s agentHistoryRes) Empty() bool {
+	return false
+}
+
+type agentAvailabilityRes struct {
+	AgentID        string             `json:"agent_id"`
+	From           time.Time          `json:"from"`
+	To             time.Time          `json:"to"`
+	UptimePercent  float64            `json:"uptime_percent"`
+	FlapCount      int                `json:"flap_count"`
+	StateDurations map[string]float64 `json:"state_durations"`
+}
+
+func (s agentAvailabilityRes) Code() int {
+	return http.StatusOK
+}
+
+func (s agentAvailabilityRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (s agentAvailabilityRes) Empty() bool {
+	return false
+}
+
+type agentLogEntryRes struct {
+	TimeStamp time.Time              `json:"ts"`
+	Level     string                 `json:"level"`
+	Source    string                 `json:"source"`
+	Message   string                 `json:"msg"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+type agentLogsRes struct {
+	AgentID string             `json:"agent_id"`
+	Entries []agentLogEntryRes `json:"entries"`
+}
+
+func (s agentLogsRes) Code() int {
+	return http.StatusOK
+}
+
+func (s agentLogsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (s agentLogsRes) Empty() bool {
+	return false
+}
+
+type policySnapshotRes struct {
+	AgentID    string      `json:"agent_id"`
+	PolicyID   string      `json:"policy_id"`
+	PolicyName string      `json:"policy_name"`
+	Datasets   []string    `json:"datasets"`
+	BEVersion  string      `json:"be_version"`
+	Format     string      `json:"format"`
+	TsCreated  time.Time   `json:"ts_created"`
+	Data       interface{} `json:"data"`
+}
+
+func (s policySnapshotRes) Code() int {
+	return http.StatusOK
+}
+
+func (s policySnapshotRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (s policySnapshotRes) Empty() bool {
+	return false
+}
+
+type heartbeatConfigRes struct {
+	AgentID           string     `json:"agent_id,omitempty"`
+	AgentGroupID      string     `json:"agent_group_id,omitempty"`
+	HeartbeatInterval int64      `json:"heartbeat_interval"`
+	StaleTimeout      int64      `json:"stale_timeout"`
+	TsUpdated         *time.Time `json:"ts_updated,omitempty"`
+}
+
+func (s heartbeatConfigRes) Code() int {
+	return http.StatusOK
+}
+
+func (s heartbeatConfigRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (s heartbeatConfigRes) Empty() bool {
+	return false
+}
+
 type matchingGroupsRes struct {
 	GroupID   string `json:"group_id"`
 	GroupName string `json:"group_name"`
@@ -198,3 +356,131 @@ func (s matchingGroupsRes) Headers() map[string]string {
 func (s matchingGroupsRes) Empty() bool {
 	return false
 }
+
+type enrollmentTokenRes struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Token       string     `json:"token,omitempty"`
+	MaxUses     uint64     `json:"max_uses"`
+	Uses        uint64     `json:"uses"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	OrbTags     types.Tags `json:"orb_tags"`
+	NamePattern string     `json:"name_pattern,omitempty"`
+	Revoked     bool       `json:"revoked"`
+	TsCreated   time.Time  `json:"ts_created"`
+	created     bool
+}
+
+func (s enrollmentTokenRes) Code() int {
+	if s.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (s enrollmentTokenRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (s enrollmentTokenRes) Empty() bool {
+	return false
+}
+
+type enrollmentTokensRes struct {
+	EnrollmentTokens []enrollmentTokenRes `json:"enrollment_tokens"`
+}
+
+func (s enrollmentTokensRes) Code() int {
+	return http.StatusOK
+}
+
+func (s enrollmentTokensRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (s enrollmentTokensRes) Empty() bool {
+	return false
+}
+
+type bulkAgentRes struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+type bulkJobRes struct {
+	ID           string                   `json:"id,omitempty"`
+	Operation    string                   `json:"operation"`
+	Filter       types.Tags               `json:"filter"`
+	OrbTags      types.Tags               `json:"orb_tags,omitempty"`
+	TagKeys      []string                 `json:"tag_keys,omitempty"`
+	Reason       string                   `json:"reason,omitempty"`
+	State        string                   `json:"state,omitempty"`
+	DryRun       bool                     `json:"dry_run,omitempty"`
+	Total        uint64                   `json:"total"`
+	Succeeded    uint64                   `json:"succeeded"`
+	Failed       uint64                   `json:"failed"`
+	Failures     []fleet.BulkAgentFailure `json:"failures"`
+	Agents       []bulkAgentRes           `json:"agents,omitempty"`
+	Created      *time.Time               `json:"ts_created,omitempty"`
+	LastModified *time.Time               `json:"ts_last_modified,omitempty"`
+	started      bool
+}
+
+func (s bulkJobRes) Code() int {
+	if s.started {
+		return http.StatusAccepted
+	}
+	return http.StatusOK
+}
+
+func (s bulkJobRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (s bulkJobRes) Empty() bool {
+	return false
+}
+
+func toBulkJobRes(job fleet.BulkJob) bulkJobRes {
+	res := bulkJobRes{
+		ID:        job.ID,
+		Operation: job.Operation,
+		Filter:    job.Filter,
+		OrbTags:   job.OrbTags,
+		TagKeys:   job.TagKeys,
+		Reason:    job.Reason,
+		State:     job.State,
+		Total:     job.Total(),
+		Succeeded: job.Succeeded,
+		Failed:    job.Failed,
+		Failures:  job.Failures,
+	}
+	if res.Failures == nil {
+		res.Failures = []fleet.BulkAgentFailure{}
+	}
+	if !job.Created.IsZero() {
+		res.Created = &job.Created
+		res.LastModified = &job.LastModified
+	}
+	return res
+}
+
+type agentKeyRes struct {
+	ID        string `json:"id"`
+	Key       string `json:"key"`
+	ChannelID string `json:"channel_id"`
+}
+
+func (s agentKeyRes) Code() int {
+	return http.StatusOK
+}
+
+func (s agentKeyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (s agentKeyRes) Empty() bool {
+	return false
+}
@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	kitot "github.com/go-kit/kit/tracing/opentracing"
 	kithttp "github.com/go-kit/kit/transport/http"
@@ -34,8 +35,15 @@ const (
 	dirKey      = "dir"
 	metadataKey = "metadata"
 	tagsKey     = "tags"
+	fromKey     = "from"
+	toKey       = "to"
+	sinceKey    = "since"
+	levelKey    = "level"
 	defOffset   = 0
 	defLimit    = 10
+
+	defHistoryLimit = 100
+	defLogLimit     = 100
 )
 
 func MakeHandler(tracer opentracing.Tracer, svcName string, svc fleet.Service) http.Handler {
@@ -75,6 +83,21 @@ func MakeHandler(tracer opentracing.Tracer, svcName string, svc fleet.Service) h
 		decodeValidateAgentGroup,
 		types.EncodeResponse,
 		opts...))
+	r.Get("/agent_groups/:id/heartbeat_config", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_agent_group_heartbeat_config")(viewAgentGroupHeartbeatConfigEndpoint(svc)),
+		decodeView,
+		types.EncodeResponse,
+		opts...))
+	r.Put("/agent_groups/:id/heartbeat_config", kithttp.NewServer(
+		kitot.TraceServer(tracer, "edit_agent_group_heartbeat_config")(editAgentGroupHeartbeatConfigEndpoint(svc)),
+		decodeHeartbeatConfig,
+		types.EncodeResponse,
+		opts...))
+	r.Delete("/agent_groups/:id/heartbeat_config", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_agent_group_heartbeat_config")(removeAgentGroupHeartbeatConfigEndpoint(svc)),
+		decodeView,
+		types.EncodeResponse,
+		opts...))
 
 	r.Post("/agents", kithttp.NewServer(
 		kitot.TraceServer(tracer, "create_agent")(addAgentEndpoint(svc)),
@@ -91,8 +114,64 @@ func MakeHandler(tracer opentracing.Tracer, svcName string, svc fleet.Service) h
 		decodeListBackends,
 		types.EncodeResponse,
 		opts...))
+	r.Get("/agents/heartbeat_config", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_heartbeat_config")(viewHeartbeatConfigEndpoint(svc)),
+		decodeOwnerResource,
+		types.EncodeResponse,
+		opts...))
+	r.Put("/agents/heartbeat_config", kithttp.NewServer(
+		kitot.TraceServer(tracer, "edit_heartbeat_config")(editHeartbeatConfigEndpoint(svc)),
+		decodeHeartbeatConfig,
+		types.EncodeResponse,
+		opts...))
+	r.Post("/agents/enrollment_tokens", kithttp.NewServer(
+		kitot.TraceServer(tracer, "create_enrollment_token")(addEnrollmentTokenEndpoint(svc)),
+		decodeAddEnrollmentToken,
+		types.EncodeResponse,
+		opts...))
+	r.Get("/agents/enrollment_tokens", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_enrollment_tokens")(listEnrollmentTokensEndpoint(svc)),
+		decodeOwnerResource,
+		types.EncodeResponse,
+		opts...))
+	r.Get("/agents/enrollment_tokens/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_enrollment_token")(viewEnrollmentTokenEndpoint(svc)),
+		decodeView,
+		types.EncodeResponse,
+		opts...))
+	r.Delete("/agents/enrollment_tokens/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "revoke_enrollment_token")(revokeEnrollmentTokenEndpoint(svc)),
+		decodeView,
+		types.EncodeResponse,
+		opts...))
+	// authenticated by the enrollment token instead of a user token
+	r.Post("/agents/enroll", kithttp.NewServer(
+		kitot.TraceServer(tracer, "enroll_agent")(enrollAgentEndpoint(svc)),
+		decodeEnrollAgent,
+		types.EncodeResponse,
+		opts...))
+	r.Post("/agents/bulk", kithttp.NewServer(
+		kitot.TraceServer(tracer, "bulk_agent_operation")(bulkAgentOperationEndpoint(svc)),
+		decodeBulkAgentOperation,
+		types.EncodeResponse,
+		opts...))
+	r.Get("/agents/bulk/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_bulk_job")(viewBulkJobEndpoint(svc)),
+		decodeView,
+		types.EncodeResponse,
+		opts...))
+	r.Get("/agents/rollouts/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_policy_rollout")(viewPolicyRolloutEndpoint(svc)),
+		decodeView,
+		types.EncodeResponse,
+		opts...))
 	r.Post("/agents/:id/rpc/reset", kithttp.NewServer(
 		kitot.TraceServer(tracer, "reset_agent")(resetAgentEndpoint(svc)),
+		decodeResetAgent,
+		types.EncodeResponse,
+		opts...))
+	r.Post("/agents/:id/rotate_key", kithttp.NewServer(
+		kitot.TraceServer(tracer, "rotate_agent_key")(rotateAgentKeyEndpoint(svc)),
 		decodeView,
 		types.EncodeResponse,
 		opts...))
@@ -101,6 +180,31 @@ func MakeHandler(tracer opentracing.Tracer, svcName string, svc fleet.Service) h
 		decodeView,
 		types.EncodeResponse,
 		opts...))
+	r.Get("/agents/:id/history", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_agent_history")(viewAgentHistoryEndpoint(svc)),
+		decodeAgentHistory,
+		types.EncodeResponse,
+		opts...))
+	r.Get("/agents/:id/availability", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_agent_availability")(viewAgentAvailabilityEndpoint(svc)),
+		decodeAgentHistory,
+		types.EncodeResponse,
+		opts...))
+	r.Get("/agents/:id/heartbeat_config", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_agent_heartbeat_config")(viewAgentHeartbeatConfigEndpoint(svc)),
+		decodeView,
+		types.EncodeResponse,
+		opts...))
+	r.Get("/agents/:id/policies/:policyID/snapshot", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_agent_policy_snapshot")(viewAgentPolicySnapshotEndpoint(svc)),
+		decodePolicySnapshot,
+		types.EncodeResponse,
+		opts...))
+	r.Get("/agents/:id/logs", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_agent_logs")(viewAgentLogsEndpoint(svc)),
+		decodeAgentLogs,
+		types.EncodeResponse,
+		opts...))
 	r.Get("/agents/:id/matching_groups", kithttp.NewServer(
 		kitot.TraceServer(tracer, "matching_groups")(viewAgentMatchingGroups(svc)),
 		decodeView,
@@ -185,6 +289,45 @@ func decodeAddAgent(_ context.Context, r *http.Request) (interface{}, error) {
 	return req, nil
 }
 
+func decodeBulkAgentOperation(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	req := bulkAgentOperationReq{token: parseJwt(r)}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeAddEnrollmentToken(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	req := addEnrollmentTokenReq{token: parseJwt(r)}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeEnrollAgent(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	req := enrollAgentReq{enrollmentToken: parseJwt(r)}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
 func decodeAgentUpdate(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, errors.ErrUnsupportedContentType
@@ -205,6 +348,46 @@ func decodeListBackends(_ context.Context, r *http.Request) (interface{}, error)
 	return req, nil
 }
 
+func decodeOwnerResource(_ context.Context, r *http.Request) (interface{}, error) {
+	req := ownerResourceReq{token: parseJwt(r)}
+	return req, nil
+}
+
+func decodeHeartbeatConfig(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	req := heartbeatConfigReq{
+		token: parseJwt(r),
+		id:    bone.GetValue(r, "id"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeResetAgent(_ context.Context, r *http.Request) (interface{}, error) {
+	req := resetAgentReq{
+		token: parseJwt(r),
+		id:    bone.GetValue(r, "id"),
+	}
+	// the body is optional, a reset without it restarts the whole agent
+	if r.ContentLength == 0 {
+		return req, nil
+	}
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
 func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 	o, err := httputil.ReadUintQuery(r, offsetKey, defOffset)
 	if err != nil {
@@ -257,6 +440,87 @@ func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 	return req, nil
 }
 
+func decodeAgentHistory(_ context.Context, r *http.Request) (interface{}, error) {
+	l, err := httputil.ReadUintQuery(r, limitKey, defHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := readTimeQuery(r, toKey, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := readTimeQuery(r, fromKey, to.Add(-fleet.DefaultHistoryWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	req := agentHistoryReq{
+		token: parseJwt(r),
+		id:    bone.GetValue(r, "id"),
+		from:  from,
+		to:    to,
+		limit: l,
+	}
+
+	return req, nil
+}
+
+// decodePolicySnapshot reads the agent and the policy whose applied snapshot is requested
+func decodePolicySnapshot(_ context.Context, r *http.Request) (interface{}, error) {
+	req := policySnapshotReq{
+		token:    parseJwt(r),
+		id:       bone.GetValue(r, "id"),
+		policyID: bone.GetValue(r, "policyID"),
+	}
+	return req, nil
+}
+
+func decodeAgentLogs(_ context.Context, r *http.Request) (interface{}, error) {
+	l, err := httputil.ReadUintQuery(r, limitKey, defLogLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	since, err := readTimeQuery(r, sinceKey, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	level, err := httputil.ReadStringQuery(r, levelKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	req := agentLogsReq{
+		token: parseJwt(r),
+		id:    bone.GetValue(r, "id"),
+		since: since,
+		level: level,
+		limit: l,
+	}
+
+	return req, nil
+}
+
+// readTimeQuery reads a RFC 3339 timestamp from the query parameters
+func readTimeQuery(r *http.Request, key string, def time.Time) (time.Time, error) {
+	s, err := httputil.ReadStringQuery(r, key, "")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if s == "" {
+		return def, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, errors.Wrap(errors.ErrInvalidQueryParams, err)
+	}
+	return t, nil
+}
+
 func decodeValidateAgentGroup(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), "application/json") {
 		return nil, errors.ErrUnsupportedContentType
@@ -285,10 +549,19 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 
 		case errors.Contains(errorVal, errors.ErrMalformedEntity):
 			w.WriteHeader(http.StatusBadRequest)
-		case errors.Contains(errorVal, errors.ErrNotFound):
+		case errors.Contains(errorVal, errors.ErrNotFound),
+			errors.Contains(errorVal, fleet.ErrRolloutNotFound):
 			w.WriteHeader(http.StatusNotFound)
 		case errors.Contains(errorVal, errors.ErrConflict):
 			w.WriteHeader(http.StatusConflict)
+		case errors.Contains(errorVal, fleet.ErrPolicySnapshotTimeout):
+			w.WriteHeader(http.StatusGatewayTimeout)
+		case errors.Contains(errorVal, fleet.ErrPolicySnapshot):
+			w.WriteHeader(http.StatusBadGateway)
+		case errors.Contains(errorVal, fleet.ErrKeyRotationTimeout):
+			w.WriteHeader(http.StatusGatewayTimeout)
+		case errors.Contains(errorVal, fleet.ErrKeyRotation):
+			w.WriteHeader(http.StatusBadGateway)
 
 		case errors.Contains(errorVal, db.ErrScanMetadata):
 			w.WriteHeader(http.StatusUnprocessableEntity)
@@ -17,6 +17,8 @@ import (
 	"github.com/mainflux/mainflux/pkg/messaging"
 	mfnats "github.com/mainflux/mainflux/pkg/messaging/nats"
 	"github.com/orb-community/orb/buildinfo"
+	"github.com/orb-community/orb/notifications"
+	"github.com/orb-community/orb/pkg/errors"
 	"github.com/orb-community/orb/policies/pb"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
@@ -49,10 +51,20 @@ type AgentCommsService interface {
 	NotifyGroupDatasetRemoval(ctx context.Context, ag AgentGroup, dsID string, policyID string) error
 	// NotifyGroupPolicyUpdate RPC core -> Agent: Notify AgentGroup that a Policy has been updated
 	NotifyGroupPolicyUpdate(ctx context.Context, ag AgentGroup, policyID string, ownerID string) error
-	//NotifyAgentReset RPC core -> Agent: Notify Agent to reset the backend
-	NotifyAgentReset(ctx context.Context, agent Agent, fullReset bool, reason string) error
+	// NotifyAgentPolicyUpdate RPC core -> Agent: Notify a single Agent of an AgentGroup that a Policy has been updated, used by staged rollouts
+	NotifyAgentPolicyUpdate(ctx context.Context, a Agent, groupID string, policyID string, ownerID string) error
+	//NotifyAgentReset RPC core -> Agent: Notify Agent to reset all its backends, or only backendName when set
+	NotifyAgentReset(ctx context.Context, agent Agent, backendName string, reapplyPolicies bool, reason string) error
+	// NotifyAgentHeartbeatConfig RPC core -> Agent: Notify Agent of the heartbeat configuration in effect for it
+	NotifyAgentHeartbeatConfig(ctx context.Context, agent Agent) error
 	// NotifyGroupDatasetEdit RPC core -> Agent: Notify Agent an already created Dataset goes invalid or valid
 	NotifyGroupDatasetEdit(ctx context.Context, ag AgentGroup, datasetID, policyID, ownerID string, valid bool) error
+	// RequestAgentPolicyMetrics RPC core -> Agent -> core: Ask Agent for the current metrics of a Policy and wait for its answer
+	RequestAgentPolicyMetrics(ctx context.Context, agent Agent, policyID string) (AgentMetricsRPCPayload, error)
+	// RequestAgentKeyRotation RPC core -> Agent -> core: Deliver a new key to Agent and wait for it to be persisted, returning the request id
+	RequestAgentKeyRotation(ctx context.Context, agent Agent, key string) (string, error)
+	// NotifyAgentKeyRotationCommit RPC core -> Agent: Notify Agent whether the key delivered with requestID is now set on its Thing
+	NotifyAgentKeyRotationCommit(ctx context.Context, agent Agent, requestID string, committed bool) error
 }
 
 var _ AgentCommsService = (*fleetCommsService)(nil)
@@ -67,12 +79,15 @@ type fleetCommsService struct {
 	logger              *zap.Logger
 	agentRepo           AgentRepository
 	agentGroupRepo      AgentGroupRepository
+	rolloutRepo         PolicyRolloutRepository
 	policyClient        pb.PolicyServiceClient
 	asyncContext        context.Context
 	cancelAsyncContexts context.CancelFunc
 
 	// agent comms
 	agentPubSub mfnats.PubSub
+	// notifier publishes the policies agents fail to apply, for owners to be notified
+	notifier notifications.Publisher
 }
 
 func (svc fleetCommsService) NotifyGroupDatasetEdit(ctx context.Context, ag AgentGroup, datasetID, policyID, ownerID string, valid bool) error {
@@ -251,6 +266,10 @@ func (svc fleetCommsService) NotifyAgentAllDatasets(ctx context.Context, a Agent
 		}
 		payload = make([]AgentPolicyRPCPayload, len(p.Policies))
 		for i, policy := range p.Policies {
+			policy, err = svc.rolloutPolicyVersion(ctx, a, policy)
+			if err != nil {
+				return err
+			}
 
 			var pdata interface{}
 			svc.logger.Debug("policy format", zap.String("policy_id", policy.Id), zap.String("policy_format", policy.Format))
@@ -452,6 +471,90 @@ func (svc fleetCommsService) NotifyGroupPolicyUpdate(ctx context.Context, ag Age
 	return nil
 }
 
+func (svc fleetCommsService) NotifyAgentPolicyUpdate(ctx context.Context, a Agent, groupID string, policyID string, ownerID string) error {
+	p, err := svc.policyClient.RetrievePolicy(ctx, &pb.PolicyByIDReq{PolicyID: policyID, OwnerID: ownerID})
+	if err != nil {
+		return err
+	}
+
+	var pdata interface{}
+	if p.GetFormat() == "yaml" {
+		if err := yaml.Unmarshal(p.Data, &pdata); err != nil {
+			return err
+		}
+	} else {
+		if err := json.Unmarshal(p.Data, &pdata); err != nil {
+			return err
+		}
+	}
+
+	payload := []AgentPolicyRPCPayload{{
+		Action:       "manage",
+		ID:           policyID,
+		Name:         p.Name,
+		AgentGroupID: groupID,
+		Backend:      p.Backend,
+		Version:      p.Version,
+		Data:         pdata,
+		Format:       p.Format,
+	}}
+
+	data := AgentPolicyRPC{
+		SchemaVersion: CurrentRPCSchemaVersion,
+		Func:          AgentPolicyRPCFunc,
+		Payload:       payload,
+		FullList:      false,
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	msg := messaging.Message{
+		Channel:   a.MFChannelID,
+		Subtopic:  RPCFromCoreTopic,
+		Publisher: publisher,
+		Payload:   body,
+		Created:   time.Now().UnixNano(),
+	}
+	if err := svc.agentPubSub.Publish(msg.Channel, msg); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// rolloutPolicyVersion keeps agents that are not canaries of a staged rollout on the previous policy version
+func (svc fleetCommsService) rolloutPolicyVersion(ctx context.Context, a Agent, policy *pb.PolicyInDSRes) (*pb.PolicyInDSRes, error) {
+	rollout, err := svc.rolloutRepo.RetrieveLatestByPolicyID(ctx, a.MFOwnerID, policy.Id)
+	if err != nil {
+		if errors.Contains(err, ErrRolloutNotFound) {
+			return policy, nil
+		}
+		return nil, err
+	}
+	if !rollout.Pinned() || rollout.Version != policy.Version || rollout.IsCanary(a.MFThingID) {
+		return policy, nil
+	}
+
+	prev, err := svc.policyClient.RetrievePolicyVersion(ctx, &pb.PolicyVersionReq{PolicyID: policy.Id, OwnerID: a.MFOwnerID, Version: rollout.PreviousVersion})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.PolicyInDSRes{
+		Id:           policy.Id,
+		Name:         policy.Name,
+		Data:         prev.Data,
+		Backend:      policy.Backend,
+		Version:      prev.Version,
+		DatasetId:    policy.DatasetId,
+		AgentGroupId: policy.AgentGroupId,
+		Format:       prev.Format,
+	}, nil
+}
+
 func (svc fleetCommsService) NotifyGroupPolicyRemoval(ctx context.Context, ag AgentGroup, policyID string, policyName string, backend string) error {
 
 	var payloads []AgentPolicyRPCPayload
@@ -547,10 +650,157 @@ func (svc fleetCommsService) NotifyAgentStop(ctx context.Context, agent Agent, r
 	return nil
 }
 
-func (svc fleetCommsService) NotifyAgentReset(ctx context.Context, agent Agent, fullReset bool, reason string) error {
+// requestAgentReply publishes an RPC to the agent and waits for the raw answer it publishes on its RPCToCoreTopic
+// suffixed with requestID. The subtopic is only listened to by this replica, whichever replica receives the other
+// agent messages.
+func (svc fleetCommsService) requestAgentReply(ctx context.Context, agent Agent, requestID string, data RPC, maxSize int, timeout time.Duration, timeoutErr error) ([]byte, error) {
+	replyTopic := fmt.Sprintf("channels.%s.%s.%s", agent.MFChannelID, RPCToCoreTopic, requestID)
+	replies := make(chan []byte, 1)
+	handler := func(msg messaging.Message) error {
+		if len(msg.Payload) > maxSize {
+			return ErrPayloadTooBig
+		}
+		select {
+		case replies <- msg.Payload:
+		default:
+		}
+		return nil
+	}
+	if err := svc.agentPubSub.Subscribe(replyTopic, handler); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := svc.agentPubSub.Unsubscribe(replyTopic); err != nil {
+			svc.logger.Warn("failed to unsubscribe from agent answer", zap.String("topic", replyTopic), zap.Error(err))
+		}
+	}()
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	msg := messaging.Message{
+		Channel:   agent.MFChannelID,
+		Subtopic:  RPCFromCoreTopic,
+		Publisher: publisher,
+		Payload:   body,
+		Created:   time.Now().UnixNano(),
+	}
+	if err := svc.agentPubSub.Publish(msg.Channel, msg); err != nil {
+		return nil, err
+	}
+
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	select {
+	case reply := <-replies:
+		return reply, nil
+	case <-t.C:
+		return nil, timeoutErr
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (svc fleetCommsService) RequestAgentPolicyMetrics(ctx context.Context, agent Agent, policyID string) (AgentMetricsRPCPayload, error) {
+	requestID := uuid.NewString()
+	data := RPC{
+		SchemaVersion: CurrentRPCSchemaVersion,
+		Func:          AgentMetricsReqRPCFunc,
+		Payload: AgentMetricsReqRPCPayload{
+			RequestID: requestID,
+			PolicyID:  policyID,
+		},
+	}
+	reply, err := svc.requestAgentReply(ctx, agent, requestID, data, MaxPolicySnapshotSize, PolicySnapshotTimeout, ErrPolicySnapshotTimeout)
+	if err != nil {
+		return AgentMetricsRPCPayload{}, err
+	}
+
+	var rpc AgentMetricsRPC
+	if err := json.Unmarshal(reply, &rpc); err != nil {
+		return AgentMetricsRPCPayload{}, ErrSchemaMalformed
+	}
+	if rpc.SchemaVersion != CurrentRPCSchemaVersion {
+		return AgentMetricsRPCPayload{}, ErrSchemaVersion
+	}
+	for _, p := range rpc.Payload {
+		if p.RequestID != requestID || p.PolicyID != policyID {
+			continue
+		}
+		if p.Error != "" {
+			return AgentMetricsRPCPayload{}, errors.Wrap(ErrPolicySnapshot, errors.New(p.Error))
+		}
+		return p, nil
+	}
+	return AgentMetricsRPCPayload{}, ErrSchemaMalformed
+}
+
+func (svc fleetCommsService) RequestAgentKeyRotation(ctx context.Context, agent Agent, key string) (string, error) {
+	requestID := uuid.NewString()
+	data := RPC{
+		SchemaVersion: CurrentRPCSchemaVersion,
+		Func:          AgentKeyRotationRPCFunc,
+		Payload: AgentKeyRotationRPCPayload{
+			RequestID: requestID,
+			Key:       key,
+		},
+	}
+	reply, err := svc.requestAgentReply(ctx, agent, requestID, data, MaxMsgPayloadSize, KeyRotationTimeout, ErrKeyRotationTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	var rpc AgentKeyRotationAckRPC
+	if err := json.Unmarshal(reply, &rpc); err != nil {
+		return "", ErrSchemaMalformed
+	}
+	if rpc.SchemaVersion != CurrentRPCSchemaVersion {
+		return "", ErrSchemaVersion
+	}
+	if rpc.Payload.RequestID != requestID {
+		return "", ErrSchemaMalformed
+	}
+	if rpc.Payload.Error != "" {
+		return "", errors.Wrap(ErrKeyRotation, errors.New(rpc.Payload.Error))
+	}
+	return requestID, nil
+}
+
+func (svc fleetCommsService) NotifyAgentKeyRotationCommit(ctx context.Context, agent Agent, requestID string, committed bool) error {
+	data := RPC{
+		SchemaVersion: CurrentRPCSchemaVersion,
+		Func:          AgentKeyRotationCommitRPCFunc,
+		Payload: AgentKeyRotationCommitRPCPayload{
+			RequestID: requestID,
+			Committed: committed,
+		},
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	msg := messaging.Message{
+		Channel:   agent.MFChannelID,
+		Subtopic:  RPCFromCoreTopic,
+		Publisher: publisher,
+		Payload:   body,
+		Created:   time.Now().UnixNano(),
+	}
+	if err := svc.agentPubSub.Publish(msg.Channel, msg); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (svc fleetCommsService) NotifyAgentReset(ctx context.Context, agent Agent, backendName string, reapplyPolicies bool, reason string) error {
 	payload := AgentResetRPCPayload{
-		FullReset: fullReset,
-		Reason:    reason,
+		FullReset:       backendName == "",
+		Backend:         backendName,
+		ReapplyPolicies: reapplyPolicies,
+		Reason:          reason,
 	}
 	data := RPC{
 		SchemaVersion: CurrentRPCSchemaVersion,
@@ -576,13 +826,67 @@ func (svc fleetCommsService) NotifyAgentReset(ctx context.Context, agent Agent,
 	return nil
 }
 
-func NewFleetCommsService(logger *zap.Logger, policyClient pb.PolicyServiceClient, agentRepo AgentRepository, agentGroupRepo AgentGroupRepository, agentPubSub mfnats.PubSub) AgentCommsService {
+func (svc fleetCommsService) NotifyAgentHeartbeatConfig(ctx context.Context, agent Agent) error {
+	// agents requesting their group membership are only known by thing and channel
+	if agent.MFOwnerID == "" {
+		a, err := svc.agentRepo.RetrieveByIDWithChannel(ctx, agent.MFThingID, agent.MFChannelID)
+		if err != nil {
+			return err
+		}
+		agent = a
+	}
+
+	groups, err := svc.agentGroupRepo.RetrieveAllByAgent(ctx, agent)
+	if err != nil {
+		return err
+	}
+	configs, err := svc.agentRepo.RetrieveHeartbeatConfigs(ctx, agent.MFOwnerID)
+	if err != nil {
+		return err
+	}
+	groupIDs := make([]string, len(groups))
+	for i, g := range groups {
+		groupIDs[i] = g.ID
+	}
+	cfg := ResolveHeartbeatConfig(configs, groupIDs)
+
+	payload := HeartbeatConfigRPCPayload{
+		HeartbeatInterval: int64(cfg.Interval.Seconds()),
+		StaleTimeout:      int64(cfg.StaleTimeout.Seconds()),
+	}
+	data := RPC{
+		SchemaVersion: CurrentRPCSchemaVersion,
+		Func:          HeartbeatConfigRPCFunc,
+		Payload:       payload,
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	msg := messaging.Message{
+		Channel:   agent.MFChannelID,
+		Subtopic:  RPCFromCoreTopic,
+		Publisher: publisher,
+		Payload:   body,
+		Created:   time.Now().UnixNano(),
+	}
+	if err := svc.agentPubSub.Publish(msg.Channel, msg); err != nil {
+		return err
+	}
+	return nil
+}
+
+func NewFleetCommsService(logger *zap.Logger, policyClient pb.PolicyServiceClient, agentRepo AgentRepository, agentGroupRepo AgentGroupRepository, rolloutRepo PolicyRolloutRepository, agentPubSub mfnats.PubSub, notifier notifications.Publisher) AgentCommsService {
 	return &fleetCommsService{
 		logger:         logger,
 		agentRepo:      agentRepo,
 		agentGroupRepo: agentGroupRepo,
+		rolloutRepo:    rolloutRepo,
 		agentPubSub:    agentPubSub,
 		policyClient:   policyClient,
+		notifier:       notifier,
 	}
 }
 
@@ -668,13 +972,93 @@ func (svc fleetCommsService) handleHeartbeat(ctx context.Context, thingID string
 		agent.LastHBData["policy_state"] = hb.PolicyState
 		agent.LastHBData["group_state"] = hb.GroupState
 	}
+	if hb.SpoolState != nil {
+		agent.LastHBData["spool_state"] = hb.SpoolState
+	}
+	// the previous heartbeat tells which failing policy states are new, it is only read back when there is one to
+	// notify, so a healthy agent costs a single update per heartbeat
+	var previous Agent
+	if hasFailingPolicyState(hb) {
+		var err error
+		previous, err = svc.agentRepo.RetrieveByIDWithChannel(ctx, thingID, channelID)
+		if err != nil {
+			svc.logger.Warn("failed to retrieve agent to notify policy states", zap.String("thing_id", thingID), zap.Error(err))
+		}
+	}
 	err := svc.agentRepo.UpdateHeartbeatByIDWithChannel(context.Background(), agent)
 	if err != nil {
 		return err
 	}
+	if previous.MFOwnerID != "" {
+		svc.notifyPolicyStates(ctx, previous, hb)
+	}
 	return nil
 }
 
+func hasFailingPolicyState(hb Heartbeat) bool {
+	for _, info := range hb.PolicyState {
+		if info.State == notifications.StateFailedToApply || info.State == notifications.StateNoTapMatch {
+			return true
+		}
+	}
+	return false
+}
+
+// notifyPolicyStates publishes the policies of the heartbeat which entered a failing state since the previous heartbeat of the agent
+func (svc fleetCommsService) notifyPolicyStates(ctx context.Context, previous Agent, hb Heartbeat) {
+	var previousStates map[string]PolicyStateInfo
+	if data, ok := previous.LastHBData["policy_state"]; ok {
+		// the previous states are read back from the database as plain maps, so they go through JSON to be typed again
+		if b, err := json.Marshal(data); err == nil {
+			_ = json.Unmarshal(b, &previousStates)
+		}
+	}
+
+	for policyID, info := range hb.PolicyState {
+		if info.State != notifications.StateFailedToApply && info.State != notifications.StateNoTapMatch {
+			continue
+		}
+		if prev, ok := previousStates[policyID]; ok && prev.State == info.State {
+			continue
+		}
+		svc.notifier.Publish(ctx, notifications.StateEvent{
+			OwnerID:    previous.MFOwnerID,
+			Entity:     notifications.EntityPolicy,
+			EntityID:   policyID,
+			EntityName: info.Name,
+			State:      info.State,
+			Message:    info.Error,
+			AgentID:    previous.MFThingID,
+			AgentName:  previous.Name.String(),
+			Timestamp:  hb.TimeStamp,
+		})
+	}
+}
+
+func (svc fleetCommsService) handleLogs(ctx context.Context, thingID string, channelID string, payload []byte) error {
+	var versionCheck SchemaVersionCheck
+	if err := json.Unmarshal(payload, &versionCheck); err != nil {
+		return ErrSchemaMalformed
+	}
+	if versionCheck.SchemaVersion != CurrentLogSchemaVersion {
+		return ErrSchemaVersion
+	}
+	var logs AgentLogs
+	if err := json.Unmarshal(payload, &logs); err != nil {
+		return ErrSchemaMalformed
+	}
+	if len(logs.Entries) == 0 {
+		return nil
+	}
+
+	agent, err := svc.agentRepo.RetrieveByIDWithChannel(ctx, thingID, channelID)
+	if err != nil {
+		return err
+	}
+
+	return svc.agentRepo.SaveAgentLogs(ctx, agent.MFOwnerID, thingID, sanitizeAgentLogs(logs.Entries))
+}
+
 func (svc fleetCommsService) handleRPCToCore(ctx context.Context, thingID string, channelID string, payload []byte) error {
 	var versionCheck SchemaVersionCheck
 	if err := json.Unmarshal(payload, &versionCheck); err != nil {
@@ -695,6 +1079,11 @@ func (svc fleetCommsService) handleRPCToCore(ctx context.Context, thingID string
 			svc.logger.Error("notify group membership failure", zap.Error(err))
 			return nil
 		}
+		// agents request their group membership when connecting, the heartbeat configuration goes along
+		if err := svc.NotifyAgentHeartbeatConfig(ctx, Agent{MFThingID: thingID, MFChannelID: channelID}); err != nil {
+			svc.logger.Error("notify heartbeat configuration failure", zap.Error(err))
+			return nil
+		}
 	case AgentPoliciesReqRPCFunc:
 		if err := svc.NotifyAgentAllDatasets(ctx, Agent{MFThingID: thingID, MFChannelID: channelID}); err != nil {
 			svc.logger.Error("notify agent policies failure", zap.Error(err))
@@ -758,7 +1147,10 @@ func (svc fleetCommsService) handleMsgFromAgent(msg messaging.Message) error {
 				return
 			}
 		case LogTopic:
-			svc.logger.Error("implement me: LogChannel")
+			if err := svc.handleLogs(ctx, msg.Publisher, msg.Channel, msg.Payload); err != nil {
+				svc.logger.Error("log failure", zap.Error(err))
+				return
+			}
 		default:
 			svc.logger.Warn("unsupported/unhandled agent subtopic, ignoring",
 				zap.String("subtopic", msg.Subtopic),
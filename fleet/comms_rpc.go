@@ -95,9 +95,13 @@ type AgentStopRPC struct {
 
 const AgentResetRPCFunc = "agent_reset"
 
+// AgentResetRPCPayload restarts every backend and the agent comms on a full reset, otherwise only Backend
+// is restarted, dropping its policies and requesting them again when ReapplyPolicies is set
 type AgentResetRPCPayload struct {
-	FullReset bool   `json:"full_reset"`
-	Reason    string `json:"reason"`
+	FullReset       bool   `json:"full_reset"`
+	Backend         string `json:"backend,omitempty"`
+	ReapplyPolicies bool   `json:"reapply_policies,omitempty"`
+	Reason          string `json:"reason"`
 }
 
 type AgentResetRPC struct {
@@ -106,6 +110,65 @@ type AgentResetRPC struct {
 	Payload       AgentResetRPCPayload `json:"payload"`
 }
 
+const HeartbeatConfigRPCFunc = "heartbeat_config"
+
+// HeartbeatConfigRPCPayload durations are in seconds
+type HeartbeatConfigRPCPayload struct {
+	HeartbeatInterval int64 `json:"heartbeat_interval"`
+	StaleTimeout      int64 `json:"stale_timeout"`
+}
+
+type HeartbeatConfigRPC struct {
+	SchemaVersion string                    `json:"schema_version"`
+	Func          string                    `json:"func"`
+	Payload       HeartbeatConfigRPCPayload `json:"payload"`
+}
+
+const AgentMetricsReqRPCFunc = "agent_metrics_req"
+
+// AgentMetricsReqRPCPayload asks the agent for the current metrics of a policy, the agent answers with an
+// AgentMetricsRPC published on its RPCToCoreTopic suffixed with RequestID
+type AgentMetricsReqRPCPayload struct {
+	RequestID string `json:"request_id"`
+	PolicyID  string `json:"policy_id"`
+}
+
+type AgentMetricsReqRPC struct {
+	SchemaVersion string                    `json:"schema_version"`
+	Func          string                    `json:"func"`
+	Payload       AgentMetricsReqRPCPayload `json:"payload"`
+}
+
+const AgentKeyRotationRPCFunc = "agent_key_rotation"
+
+// AgentKeyRotationRPCPayload delivers a new key to the agent, which persists it next to its current key and answers
+// with an AgentKeyRotationAckRPC published on its RPCToCoreTopic suffixed with RequestID
+type AgentKeyRotationRPCPayload struct {
+	RequestID string `json:"request_id"`
+	Key       string `json:"key"`
+}
+
+type AgentKeyRotationRPC struct {
+	SchemaVersion string                     `json:"schema_version"`
+	Func          string                     `json:"func"`
+	Payload       AgentKeyRotationRPCPayload `json:"payload"`
+}
+
+const AgentKeyRotationCommitRPCFunc = "agent_key_rotation_commit"
+
+// AgentKeyRotationCommitRPCPayload tells the agent whether the key delivered with RequestID is now set on its Thing,
+// in which case it replaces the current key and the agent reconnects with it, otherwise it is discarded
+type AgentKeyRotationCommitRPCPayload struct {
+	RequestID string `json:"request_id"`
+	Committed bool   `json:"committed"`
+}
+
+type AgentKeyRotationCommitRPC struct {
+	SchemaVersion string                           `json:"schema_version"`
+	Func          string                           `json:"func"`
+	Payload       AgentKeyRotationCommitRPCPayload `json:"payload"`
+}
+
 // Edge -> Core
 
 const GroupMembershipReqRPCFunc = "group_membership_req"
@@ -129,6 +192,8 @@ type AgentMetricsRPC struct {
 }
 
 type AgentMetricsRPCPayload struct {
+	RequestID  string   `json:"request_id,omitempty"`
+	Error      string   `json:"error,omitempty"`
 	PolicyID   string   `json:"policy_id"`
 	PolicyName string   `json:"policy_name"`
 	Datasets   []string `json:"datasets"`
@@ -136,3 +201,16 @@ type AgentMetricsRPCPayload struct {
 	BEVersion  string   `json:"be_version"`
 	Data       []byte   `json:"data"`
 }
+
+const AgentKeyRotationAckRPCFunc = "agent_key_rotation_ack"
+
+type AgentKeyRotationAckRPC struct {
+	SchemaVersion string                        `json:"schema_version"`
+	Func          string                        `json:"func"`
+	Payload       AgentKeyRotationAckRPCPayload `json:"payload"`
+}
+
+type AgentKeyRotationAckRPCPayload struct {
+	RequestID string `json:"request_id"`
+	Error     string `json:"error,omitempty"`
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/orb-community/orb/fleet"
 	"github.com/orb-community/orb/fleet/backend/pktvisor"
 	flmocks "github.com/orb-community/orb/fleet/mocks"
+	notifmocks "github.com/orb-community/orb/notifications/mocks"
 	"github.com/orb-community/orb/pkg/config"
 	"github.com/orb-community/orb/pkg/errors"
 	"github.com/orb-community/orb/pkg/types"
@@ -53,7 +54,7 @@ func newFleetService(auth mainflux.AuthServiceClient, url string, agentGroupRepo
 	mfsdk := mfsdk.NewSDK(config)
 	pktvisor.Register(auth, agentRepo)
 	aDone := make(chan bool)
-	return fleet.NewFleetService(logger, auth, agentRepo, agentGroupRepo, agentComms, mfsdk, aDone)
+	return fleet.NewFleetService(logger, auth, agentRepo, agentGroupRepo, flmocks.NewPolicyRolloutRepository(), agentComms, mfsdk, fleet.NewThingKeyUpdater(url), notifmocks.NewPublisher(), aDone)
 }
 
 func newPoliciesService(auth mainflux.AuthServiceClient) policies.Service {
@@ -66,7 +67,11 @@ func newPoliciesService(auth mainflux.AuthServiceClient) policies.Service {
 		log.Fatalf("%v", err)
 	}
 
-	return policies.New(logger, auth, policyRepo, fleetGrpcClient, SinkServiceClient)
+	svc, err := policies.New(logger, auth, policyRepo, fleetGrpcClient, SinkServiceClient)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	return svc
 }
 
 func init() {
@@ -111,7 +116,7 @@ func newCommsService(agentGroupRepo fleet.AgentGroupRepository, agentRepo fleet.
 		log.Fatalf("Failed to create PubSub %v", err)
 	}
 
-	return fleet.NewFleetCommsService(logger, policyClient, agentRepo, agentGroupRepo, agentPubSub)
+	return fleet.NewFleetCommsService(logger, policyClient, agentRepo, agentGroupRepo, flmocks.NewPolicyRolloutRepository(), agentPubSub, notifmocks.NewPublisher())
 }
 
 func TestNotifyGroupNewDataset(t *testing.T) {
@@ -72,6 +72,15 @@ type GroupStateInfo struct {
 	GroupChannel string `json:"channel"`
 }
 
+// SpoolStateInfo counters of the telemetry spooled by the agent while disconnected from the broker
+type SpoolStateInfo struct {
+	Spooled      int64 `json:"spooled"`
+	Replayed     int64 `json:"replayed"`
+	Dropped      int64 `json:"dropped"`
+	Pending      int64 `json:"pending"`
+	PendingBytes int64 `json:"pending_bytes"`
+}
+
 type Heartbeat struct {
 	SchemaVersion string                      `json:"schema_version"`
 	TimeStamp     time.Time                   `json:"ts"`
@@ -79,4 +88,21 @@ type Heartbeat struct {
 	BackendState  map[string]BackendStateInfo `json:"backend_state"`
 	PolicyState   map[string]PolicyStateInfo  `json:"policy_state"`
 	GroupState    map[string]GroupStateInfo   `json:"group_state"`
+	SpoolState    *SpoolStateInfo             `json:"spool_state,omitempty"`
+}
+
+const CurrentLogSchemaVersion = "1.0"
+
+// AgentLogEntry a log entry of the agent, or of one of its backends as named by Source
+type AgentLogEntry struct {
+	TimeStamp time.Time              `json:"ts"`
+	Level     string                 `json:"level"`
+	Source    string                 `json:"source"`
+	Message   string                 `json:"msg"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+type AgentLogs struct {
+	SchemaVersion string          `json:"schema_version"`
+	Entries       []AgentLogEntry `json:"entries"`
 }
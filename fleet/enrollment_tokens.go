@@ -0,0 +1,270 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package fleet
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxEnrollmentTokenUses bounds how many agents a single enrollment token can provision
+	MaxEnrollmentTokenUses = 10000
+	// MaxEnrollmentTokenTTL bounds how far in the future an enrollment token can expire
+	MaxEnrollmentTokenTTL = 365 * 24 * time.Hour
+
+	enrollmentTokenSize = 32
+	// loginKeyType is the mainflux auth key type of the short-lived key fleet issues for the token owner on enrollment
+	loginKeyType uint32 = 0
+)
+
+var (
+	// ErrInvalidEnrollmentToken indicates an enrollment token with no uses, an expiry out of bounds or an invalid name pattern
+	ErrInvalidEnrollmentToken = errors.New("invalid enrollment token")
+	// ErrEnrollmentTokenRevoked indicates the enrollment token was revoked by its owner
+	ErrEnrollmentTokenRevoked = errors.New("enrollment token was revoked")
+	// ErrEnrollmentTokenExpired indicates the enrollment token is past its expiry
+	ErrEnrollmentTokenExpired = errors.New("enrollment token is expired")
+	// ErrEnrollmentTokenExhausted indicates the enrollment token provisioned as many agents as it allows
+	ErrEnrollmentTokenExhausted = errors.New("enrollment token has no uses left")
+	// ErrEnrollmentNameMismatch indicates the agent name does not match the name pattern of the enrollment token
+	ErrEnrollmentNameMismatch = errors.New("agent name does not match the enrollment token name pattern")
+)
+
+// EnrollmentToken lets agents provision themselves under its owner through the enrollment endpoint, without
+// carrying a user credential. Only the hash of the secret is stored, the secret is returned once on creation.
+type EnrollmentToken struct {
+	ID           string
+	Name         types.Identifier
+	MFOwnerID    string
+	MFOwnerEmail string
+	Token        string
+	TokenHash    string
+	MaxUses      uint64
+	Uses         uint64
+	ExpiresAt    time.Time
+	OrbTags      types.Tags
+	NamePattern  string
+	Revoked      bool
+	Created      time.Time
+}
+
+// Validate checks the token allows at least one use, expires in the future within MaxEnrollmentTokenTTL and
+// has a valid name pattern
+func (et EnrollmentToken) Validate(now time.Time) error {
+	if et.MaxUses == 0 || et.MaxUses > MaxEnrollmentTokenUses {
+		return errors.Wrap(ErrInvalidEnrollmentToken, fmt.Errorf("max uses must be between 1 and %d", MaxEnrollmentTokenUses))
+	}
+	if !et.ExpiresAt.After(now) || et.ExpiresAt.Sub(now) > MaxEnrollmentTokenTTL {
+		return errors.Wrap(ErrInvalidEnrollmentToken, fmt.Errorf("expiry must be in the future and within %v", MaxEnrollmentTokenTTL))
+	}
+	if _, err := et.nameRegexp(); err != nil {
+		return errors.Wrap(ErrInvalidEnrollmentToken, err)
+	}
+	return nil
+}
+
+// Usable checks the token can still provision an agent
+func (et EnrollmentToken) Usable(now time.Time) error {
+	switch {
+	case et.Revoked:
+		return ErrEnrollmentTokenRevoked
+	case !et.ExpiresAt.After(now):
+		return ErrEnrollmentTokenExpired
+	case et.Uses >= et.MaxUses:
+		return ErrEnrollmentTokenExhausted
+	}
+	return nil
+}
+
+// MatchName checks the agent name against the name pattern, which must match the whole name
+func (et EnrollmentToken) MatchName(name string) error {
+	re, err := et.nameRegexp()
+	if err != nil {
+		return err
+	}
+	if re != nil && !re.MatchString(name) {
+		return ErrEnrollmentNameMismatch
+	}
+	return nil
+}
+
+func (et EnrollmentToken) nameRegexp() (*regexp.Regexp, error) {
+	if et.NamePattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile("^(?:" + et.NamePattern + ")$")
+}
+
+// HashEnrollmentToken hashes the secret of an enrollment token the way it is stored
+func HashEnrollmentToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func newEnrollmentTokenSecret() (string, error) {
+	b := make([]byte, enrollmentTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+type EnrollmentTokenService interface {
+	// CreateEnrollmentToken creates an enrollment token for the owner, the returned token carries the secret
+	CreateEnrollmentToken(ctx context.Context, token string, et EnrollmentToken) (EnrollmentToken, error)
+	// ListEnrollmentTokens retrieves the enrollment tokens of the owner, without their secrets
+	ListEnrollmentTokens(ctx context.Context, token string) ([]EnrollmentToken, error)
+	// ViewEnrollmentToken retrieves an enrollment token of the owner, without its secret
+	ViewEnrollmentToken(ctx context.Context, token string, id string) (EnrollmentToken, error)
+	// RevokeEnrollmentToken revokes an enrollment token, agents already enrolled with it are kept
+	RevokeEnrollmentToken(ctx context.Context, token string, id string) error
+	// EnrollAgent creates an agent under the owner of the enrollment token, with the orb tags of the token,
+	// consuming one of its uses. The enrollment token used is returned without its secret
+	EnrollAgent(ctx context.Context, enrollmentToken string, a Agent) (Agent, EnrollmentToken, error)
+}
+
+type EnrollmentTokenRepository interface {
+	// SaveEnrollmentToken persists the enrollment token, returning its id
+	SaveEnrollmentToken(ctx context.Context, et EnrollmentToken) (string, error)
+	// RetrieveEnrollmentTokens retrieves the enrollment tokens of the owner
+	RetrieveEnrollmentTokens(ctx context.Context, ownerID string) ([]EnrollmentToken, error)
+	// RetrieveEnrollmentTokenByID retrieves an enrollment token of the owner
+	RetrieveEnrollmentTokenByID(ctx context.Context, ownerID string, id string) (EnrollmentToken, error)
+	// RetrieveEnrollmentTokenByHash retrieves the enrollment token having the given secret hash
+	RetrieveEnrollmentTokenByHash(ctx context.Context, tokenHash string) (EnrollmentToken, error)
+	// RevokeEnrollmentToken marks an enrollment token of the owner as revoked
+	RevokeEnrollmentToken(ctx context.Context, ownerID string, id string) error
+	// ConsumeEnrollmentToken atomically takes one use of a usable enrollment token, failing with
+	// ErrEnrollmentTokenExhausted when it is no longer usable
+	ConsumeEnrollmentToken(ctx context.Context, id string) error
+	// RefundEnrollmentToken gives back a use taken by ConsumeEnrollmentToken for an enrollment that failed
+	RefundEnrollmentToken(ctx context.Context, id string) error
+}
+
+func (svc fleetService) CreateEnrollmentToken(ctx context.Context, token string, et EnrollmentToken) (EnrollmentToken, error) {
+	ctxID, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	identity, err := svc.auth.Identify(ctxID, &mainflux.Token{Value: token})
+	if err != nil {
+		return EnrollmentToken{}, errors.Wrap(errors.ErrUnauthorizedAccess, err)
+	}
+
+	if err := et.Validate(time.Now()); err != nil {
+		return EnrollmentToken{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	secret, err := newEnrollmentTokenSecret()
+	if err != nil {
+		return EnrollmentToken{}, err
+	}
+
+	et.MFOwnerID = identity.GetId()
+	et.MFOwnerEmail = identity.GetEmail()
+	et.TokenHash = HashEnrollmentToken(secret)
+	et.Uses = 0
+	et.Revoked = false
+
+	id, err := svc.agentRepo.SaveEnrollmentToken(ctx, et)
+	if err != nil {
+		return EnrollmentToken{}, err
+	}
+
+	saved, err := svc.agentRepo.RetrieveEnrollmentTokenByID(ctx, et.MFOwnerID, id)
+	if err != nil {
+		return EnrollmentToken{}, err
+	}
+	saved.Token = secret
+	return saved, nil
+}
+
+func (svc fleetService) ListEnrollmentTokens(ctx context.Context, token string) ([]EnrollmentToken, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return nil, err
+	}
+	return svc.agentRepo.RetrieveEnrollmentTokens(ctx, ownerID)
+}
+
+func (svc fleetService) ViewEnrollmentToken(ctx context.Context, token string, id string) (EnrollmentToken, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return EnrollmentToken{}, err
+	}
+	return svc.agentRepo.RetrieveEnrollmentTokenByID(ctx, ownerID, id)
+}
+
+func (svc fleetService) RevokeEnrollmentToken(ctx context.Context, token string, id string) error {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return err
+	}
+	return svc.agentRepo.RevokeEnrollmentToken(ctx, ownerID, id)
+}
+
+func (svc fleetService) EnrollAgent(ctx context.Context, enrollmentToken string, a Agent) (Agent, EnrollmentToken, error) {
+	if enrollmentToken == "" {
+		return Agent{}, EnrollmentToken{}, errors.ErrUnauthorizedAccess
+	}
+
+	et, err := svc.agentRepo.RetrieveEnrollmentTokenByHash(ctx, HashEnrollmentToken(enrollmentToken))
+	if err != nil {
+		if errors.Contains(err, errors.ErrNotFound) {
+			return Agent{}, EnrollmentToken{}, errors.ErrUnauthorizedAccess
+		}
+		return Agent{}, EnrollmentToken{}, err
+	}
+	if err := et.Usable(time.Now()); err != nil {
+		return Agent{}, EnrollmentToken{}, errors.Wrap(errors.ErrUnauthorizedAccess, err)
+	}
+	if err := et.MatchName(a.Name.String()); err != nil {
+		return Agent{}, EnrollmentToken{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	// the use is taken before provisioning, so concurrent enrollments can not go over MaxUses
+	if err := svc.agentRepo.ConsumeEnrollmentToken(ctx, et.ID); err != nil {
+		return Agent{}, EnrollmentToken{}, errors.Wrap(errors.ErrUnauthorizedAccess, err)
+	}
+
+	// agents are provisioned on behalf of the token owner, with a short-lived key instead of a stored credential
+	key, err := svc.auth.Issue(ctx, &mainflux.IssueReq{Id: et.MFOwnerID, Email: et.MFOwnerEmail, Type: loginKeyType})
+	if err != nil {
+		svc.refundEnrollmentToken(ctx, et.ID)
+		return Agent{}, EnrollmentToken{}, errors.Wrap(ErrCreateAgent, err)
+	}
+
+	orbTags := types.Tags{}
+	for k, v := range et.OrbTags {
+		orbTags[k] = v
+	}
+	a.OrbTags = &orbTags
+
+	// provisioning spans the things service, so a failed enrollment gives its use back instead of sharing a transaction
+	agent, err := svc.CreateAgent(ctx, key.GetValue(), a)
+	if err != nil {
+		svc.refundEnrollmentToken(ctx, et.ID)
+		return Agent{}, EnrollmentToken{}, err
+	}
+
+	svc.logger.Info("agent enrolled", zap.String("agent_id", agent.MFThingID), zap.String("enrollment_token_id", et.ID),
+		zap.String("owner_id", et.MFOwnerID))
+	return agent, et, nil
+}
+
+func (svc fleetService) refundEnrollmentToken(ctx context.Context, id string) {
+	if err := svc.agentRepo.RefundEnrollmentToken(ctx, id); err != nil {
+		svc.logger.Error("failed to refund the use of a failed enrollment", zap.String("enrollment_token_id", id), zap.Error(err))
+	}
+}
@@ -0,0 +1,189 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package fleet_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/orb-community/orb/fleet"
+	flmocks "github.com/orb-community/orb/fleet/mocks"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEnrollmentToken(t *testing.T, name string, maxUses uint64, expiresAt time.Time, pattern string) fleet.EnrollmentToken {
+	nID, err := types.NewIdentifier(name)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	return fleet.EnrollmentToken{
+		Name:        nID,
+		MaxUses:     maxUses,
+		ExpiresAt:   expiresAt,
+		OrbTags:     types.Tags{"region": "eu"},
+		NamePattern: pattern,
+	}
+}
+
+func TestCreateEnrollmentToken(t *testing.T) {
+	users := flmocks.NewAuthService(map[string]string{token: email})
+	thingsServer := newThingsServer(newThingsService(users))
+	defer thingsServer.Close()
+
+	fleetService := newFleetService(users, thingsServer.URL, flmocks.NewAgentGroupRepository(), flmocks.NewAgentRepositoryMock())
+
+	expiresAt := time.Now().Add(time.Hour)
+	cases := map[string]struct {
+		et    fleet.EnrollmentToken
+		token string
+		err   error
+	}{
+		"create a valid enrollment token": {
+			et:    newEnrollmentToken(t, "valid", 10, expiresAt, "pop-[a-z]+"),
+			token: token,
+			err:   nil,
+		},
+		"create an enrollment token without uses": {
+			et:    newEnrollmentToken(t, "no-uses", 0, expiresAt, ""),
+			token: token,
+			err:   errors.ErrMalformedEntity,
+		},
+		"create an expired enrollment token": {
+			et:    newEnrollmentToken(t, "expired", 10, time.Now().Add(-time.Hour), ""),
+			token: token,
+			err:   errors.ErrMalformedEntity,
+		},
+		"create an enrollment token expiring too far ahead": {
+			et:    newEnrollmentToken(t, "far-ahead", 10, time.Now().Add(2*fleet.MaxEnrollmentTokenTTL), ""),
+			token: token,
+			err:   errors.ErrMalformedEntity,
+		},
+		"create an enrollment token with an invalid name pattern": {
+			et:    newEnrollmentToken(t, "bad-pattern", 10, expiresAt, "pop-[a-z"),
+			token: token,
+			err:   errors.ErrMalformedEntity,
+		},
+		"create an enrollment token with wrong credentials": {
+			et:    newEnrollmentToken(t, "wrong-credentials", 10, expiresAt, ""),
+			token: invalidToken,
+			err:   errors.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			saved, err := fleetService.CreateEnrollmentToken(context.Background(), tc.token, tc.et)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+			if err == nil {
+				assert.NotEmpty(t, saved.ID, fmt.Sprintf("%s: expected an id", desc))
+				assert.NotEmpty(t, saved.Token, fmt.Sprintf("%s: expected the secret to be returned", desc))
+				assert.Equal(t, fleet.HashEnrollmentToken(saved.Token), saved.TokenHash, fmt.Sprintf("%s: expected the secret hash to be stored", desc))
+			}
+		})
+	}
+}
+
+func TestEnrollAgent(t *testing.T) {
+	users := flmocks.NewAuthService(map[string]string{token: email})
+	thingsServer := newThingsServer(newThingsService(users))
+	defer thingsServer.Close()
+
+	agentRepo := flmocks.NewAgentRepositoryMock()
+	fleetService := newFleetService(users, thingsServer.URL, flmocks.NewAgentGroupRepository(), agentRepo)
+
+	expiresAt := time.Now().Add(time.Hour)
+	valid, err := fleetService.CreateEnrollmentToken(context.Background(), token, newEnrollmentToken(t, "valid", 10, expiresAt, "pop-[a-z]+"))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	single, err := fleetService.CreateEnrollmentToken(context.Background(), token, newEnrollmentToken(t, "single", 1, expiresAt, ""))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	revoked, err := fleetService.CreateEnrollmentToken(context.Background(), token, newEnrollmentToken(t, "revoked", 10, expiresAt, ""))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = fleetService.RevokeEnrollmentToken(context.Background(), token, revoked.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	expired := newEnrollmentToken(t, "expired", 10, time.Now().Add(-time.Minute), "")
+	expired.MFOwnerID = email
+	expired.MFOwnerEmail = email
+	expired.TokenHash = fleet.HashEnrollmentToken("expired-secret")
+	_, err = agentRepo.SaveEnrollmentToken(context.Background(), expired)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	// a single use token enrolling an agent before the test cases is exhausted
+	first, err := types.NewIdentifier("first")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, _, err = fleetService.EnrollAgent(context.Background(), single.Token, fleet.Agent{Name: first})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		secret string
+		name   string
+		err    error
+	}{
+		"enroll an agent with a valid token": {
+			secret: valid.Token,
+			name:   "pop-gru",
+			err:    nil,
+		},
+		"enroll an agent not matching the name pattern": {
+			secret: valid.Token,
+			name:   "dc-gru",
+			err:    errors.ErrMalformedEntity,
+		},
+		"enroll an agent with an unknown token": {
+			secret: "unknown-secret",
+			name:   "pop-gig",
+			err:    errors.ErrUnauthorizedAccess,
+		},
+		"enroll an agent with a revoked token": {
+			secret: revoked.Token,
+			name:   "pop-cgh",
+			err:    fleet.ErrEnrollmentTokenRevoked,
+		},
+		"enroll an agent with an expired token": {
+			secret: "expired-secret",
+			name:   "pop-sdu",
+			err:    fleet.ErrEnrollmentTokenExpired,
+		},
+		"enroll an agent with an exhausted token": {
+			secret: single.Token,
+			name:   "second",
+			err:    fleet.ErrEnrollmentTokenExhausted,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			nID, err := types.NewIdentifier(tc.name)
+			require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			agent, _, err := fleetService.EnrollAgent(context.Background(), tc.secret, fleet.Agent{Name: nID})
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+			if err == nil {
+				assert.Equal(t, email, agent.MFOwnerID, fmt.Sprintf("%s: expected the agent to belong to the token owner", desc))
+				assert.Equal(t, types.Tags{"region": "eu"}, *agent.OrbTags, fmt.Sprintf("%s: expected the orb tags of the token", desc))
+				assert.NotEmpty(t, agent.MFKeyID, fmt.Sprintf("%s: expected agent credentials", desc))
+			}
+		})
+	}
+
+	et, err := fleetService.ViewEnrollmentToken(context.Background(), token, valid.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(1), et.Uses, "expected a single use of the valid token")
+
+	// a failed enrollment gives its use back, so a single use token is not burned by a name conflict
+	retry, err := fleetService.CreateEnrollmentToken(context.Background(), token, newEnrollmentToken(t, "retry", 1, expiresAt, ""))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, _, err = fleetService.EnrollAgent(context.Background(), retry.Token, fleet.Agent{Name: first})
+	assert.True(t, errors.Contains(err, fleet.ErrConflict), fmt.Sprintf("expected %s got %s", fleet.ErrConflict, err))
+	et, err = fleetService.ViewEnrollmentToken(context.Background(), token, retry.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(0), et.Uses, "expected the use of the failed enrollment to be refunded")
+	retried, err := types.NewIdentifier("retried")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, _, err = fleetService.EnrollAgent(context.Background(), retry.Token, fleet.Agent{Name: retried})
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+}
@@ -0,0 +1,289 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/orb-community/orb/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultHeartbeatInterval is how often agents heartbeat when neither their owner nor their groups configure it
+	DefaultHeartbeatInterval = 50 * time.Second
+	// MinHeartbeatInterval bounds how often an agent can be asked to heartbeat
+	MinHeartbeatInterval = 10 * time.Second
+	// MaxStaleTimeout bounds how long an agent can go without heartbeats before it is considered stale
+	MaxStaleTimeout = 24 * time.Hour
+
+	heartbeatNotifyPageSize uint64 = 100
+)
+
+var (
+	// ErrInvalidHeartbeatConfig indicates a heartbeat interval too short or a stale timeout not leaving room for a missed heartbeat
+	ErrInvalidHeartbeatConfig = errors.New("invalid heartbeat configuration")
+)
+
+// DefaultHeartbeatConfig applies to agents when neither their owner nor their groups configure heartbeats
+var DefaultHeartbeatConfig = HeartbeatConfig{
+	Interval:     DefaultHeartbeatInterval,
+	StaleTimeout: DefaultTimeout,
+}
+
+// HeartbeatConfig sets how often agents heartbeat and how long they can go without heartbeats before being
+// considered stale. It applies to every agent of the owner, or to the agents of a group when AgentGroupID is set.
+type HeartbeatConfig struct {
+	MFOwnerID    string
+	AgentGroupID string
+	Interval     time.Duration
+	StaleTimeout time.Duration
+	Updated      time.Time
+}
+
+// Validate checks the interval is not too short and the stale timeout tolerates at least one missed heartbeat
+func (c HeartbeatConfig) Validate() error {
+	if c.Interval < MinHeartbeatInterval {
+		return errors.Wrap(ErrInvalidHeartbeatConfig, fmt.Errorf("heartbeat interval must be at least %v", MinHeartbeatInterval))
+	}
+	if c.StaleTimeout < 2*c.Interval {
+		return errors.Wrap(ErrInvalidHeartbeatConfig, fmt.Errorf("stale timeout must be at least twice the heartbeat interval"))
+	}
+	if c.StaleTimeout > MaxStaleTimeout {
+		return errors.Wrap(ErrInvalidHeartbeatConfig, fmt.Errorf("stale timeout must be at most %v", MaxStaleTimeout))
+	}
+	return nil
+}
+
+type HeartbeatConfigService interface {
+	// ViewHeartbeatConfig retrieves the heartbeat configuration applying to every agent of the owner
+	ViewHeartbeatConfig(ctx context.Context, token string) (HeartbeatConfig, error)
+	// EditHeartbeatConfig sets the heartbeat configuration applying to every agent of the owner
+	EditHeartbeatConfig(ctx context.Context, token string, cfg HeartbeatConfig) (HeartbeatConfig, error)
+	// ViewAgentGroupHeartbeatConfig retrieves the heartbeat configuration of an agent group
+	ViewAgentGroupHeartbeatConfig(ctx context.Context, token string, groupID string) (HeartbeatConfig, error)
+	// EditAgentGroupHeartbeatConfig sets the heartbeat configuration of an agent group, overriding the owner one
+	EditAgentGroupHeartbeatConfig(ctx context.Context, token string, cfg HeartbeatConfig) (HeartbeatConfig, error)
+	// RemoveAgentGroupHeartbeatConfig removes the heartbeat configuration of an agent group
+	RemoveAgentGroupHeartbeatConfig(ctx context.Context, token string, groupID string) error
+	// ViewAgentHeartbeatConfig retrieves the heartbeat configuration in effect for an agent
+	ViewAgentHeartbeatConfig(ctx context.Context, token string, thingID string) (HeartbeatConfig, error)
+}
+
+type HeartbeatConfigRepository interface {
+	// SaveHeartbeatConfig creates or replaces the heartbeat configuration of the owner, or of the group when AgentGroupID is set
+	SaveHeartbeatConfig(ctx context.Context, cfg HeartbeatConfig) error
+	// RetrieveHeartbeatConfigs retrieves the heartbeat configuration of the owner and of all its groups
+	RetrieveHeartbeatConfigs(ctx context.Context, ownerID string) ([]HeartbeatConfig, error)
+	// DeleteHeartbeatConfig removes the heartbeat configuration of a group
+	DeleteHeartbeatConfig(ctx context.Context, ownerID string, groupID string) error
+}
+
+// ResolveHeartbeatConfig picks the heartbeat configuration in effect for an agent belonging to the given groups.
+// When several of its groups are configured the strictest interval and timeout win, otherwise the owner
+// configuration applies, falling back to DefaultHeartbeatConfig. SetStaleStatus resolves the timeout the same way.
+func ResolveHeartbeatConfig(configs []HeartbeatConfig, groupIDs []string) HeartbeatConfig {
+	resolved := DefaultHeartbeatConfig
+	var owner *HeartbeatConfig
+	var group *HeartbeatConfig
+	for i, c := range configs {
+		if c.AgentGroupID == "" {
+			owner = &configs[i]
+			continue
+		}
+		if !contains(groupIDs, c.AgentGroupID) {
+			continue
+		}
+		if group == nil {
+			g := c
+			group = &g
+			continue
+		}
+		if c.Interval < group.Interval {
+			group.Interval = c.Interval
+		}
+		if c.StaleTimeout < group.StaleTimeout {
+			group.StaleTimeout = c.StaleTimeout
+		}
+	}
+	switch {
+	case group != nil:
+		resolved.Interval = group.Interval
+		resolved.StaleTimeout = group.StaleTimeout
+	case owner != nil:
+		resolved.Interval = owner.Interval
+		resolved.StaleTimeout = owner.StaleTimeout
+	}
+	return resolved
+}
+
+func (svc fleetService) ViewHeartbeatConfig(ctx context.Context, token string) (HeartbeatConfig, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return HeartbeatConfig{}, err
+	}
+
+	configs, err := svc.agentRepo.RetrieveHeartbeatConfigs(ctx, ownerID)
+	if err != nil {
+		return HeartbeatConfig{}, err
+	}
+
+	cfg := ResolveHeartbeatConfig(configs, nil)
+	cfg.MFOwnerID = ownerID
+	for _, c := range configs {
+		if c.AgentGroupID == "" {
+			cfg.Updated = c.Updated
+		}
+	}
+	return cfg, nil
+}
+
+func (svc fleetService) EditHeartbeatConfig(ctx context.Context, token string, cfg HeartbeatConfig) (HeartbeatConfig, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return HeartbeatConfig{}, err
+	}
+
+	cfg.MFOwnerID = ownerID
+	cfg.AgentGroupID = ""
+	if err := cfg.Validate(); err != nil {
+		return HeartbeatConfig{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	if err := svc.agentRepo.SaveHeartbeatConfig(ctx, cfg); err != nil {
+		return HeartbeatConfig{}, err
+	}
+
+	pm := PageMetadata{Limit: heartbeatNotifyPageSize}
+	for {
+		page, err := svc.agentRepo.RetrieveAll(ctx, ownerID, pm)
+		if err != nil {
+			svc.logger.Error("failed to retrieve agents to notify of heartbeat configuration", zap.Error(err))
+			break
+		}
+		svc.notifyHeartbeatConfig(ctx, page.Agents)
+		if uint64(len(page.Agents)) < pm.Limit {
+			break
+		}
+		pm.Offset += pm.Limit
+	}
+
+	return svc.ViewHeartbeatConfig(ctx, token)
+}
+
+func (svc fleetService) ViewAgentGroupHeartbeatConfig(ctx context.Context, token string, groupID string) (HeartbeatConfig, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return HeartbeatConfig{}, err
+	}
+
+	if _, err := svc.agentGroupRepository.RetrieveByID(ctx, groupID, ownerID); err != nil {
+		return HeartbeatConfig{}, err
+	}
+
+	configs, err := svc.agentRepo.RetrieveHeartbeatConfigs(ctx, ownerID)
+	if err != nil {
+		return HeartbeatConfig{}, err
+	}
+	for _, c := range configs {
+		if c.AgentGroupID == groupID {
+			return c, nil
+		}
+	}
+	return HeartbeatConfig{}, errors.ErrNotFound
+}
+
+func (svc fleetService) EditAgentGroupHeartbeatConfig(ctx context.Context, token string, cfg HeartbeatConfig) (HeartbeatConfig, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return HeartbeatConfig{}, err
+	}
+
+	if _, err := svc.agentGroupRepository.RetrieveByID(ctx, cfg.AgentGroupID, ownerID); err != nil {
+		return HeartbeatConfig{}, err
+	}
+
+	cfg.MFOwnerID = ownerID
+	if err := cfg.Validate(); err != nil {
+		return HeartbeatConfig{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	if err := svc.agentRepo.SaveHeartbeatConfig(ctx, cfg); err != nil {
+		return HeartbeatConfig{}, err
+	}
+
+	svc.notifyGroupHeartbeatConfig(ctx, ownerID, cfg.AgentGroupID)
+
+	return svc.ViewAgentGroupHeartbeatConfig(ctx, token, cfg.AgentGroupID)
+}
+
+func (svc fleetService) RemoveAgentGroupHeartbeatConfig(ctx context.Context, token string, groupID string) error {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return err
+	}
+
+	if err := svc.agentRepo.DeleteHeartbeatConfig(ctx, ownerID, groupID); err != nil {
+		return err
+	}
+
+	svc.notifyGroupHeartbeatConfig(ctx, ownerID, groupID)
+
+	return nil
+}
+
+func (svc fleetService) ViewAgentHeartbeatConfig(ctx context.Context, token string, thingID string) (HeartbeatConfig, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return HeartbeatConfig{}, err
+	}
+
+	agent, err := svc.agentRepo.RetrieveByID(ctx, ownerID, thingID)
+	if err != nil {
+		return HeartbeatConfig{}, err
+	}
+
+	groups, err := svc.agentGroupRepository.RetrieveAllByAgent(ctx, agent)
+	if err != nil {
+		return HeartbeatConfig{}, err
+	}
+
+	configs, err := svc.agentRepo.RetrieveHeartbeatConfigs(ctx, ownerID)
+	if err != nil {
+		return HeartbeatConfig{}, err
+	}
+
+	groupIDs := make([]string, len(groups))
+	for i, g := range groups {
+		groupIDs[i] = g.ID
+	}
+	cfg := ResolveHeartbeatConfig(configs, groupIDs)
+	cfg.MFOwnerID = ownerID
+	return cfg, nil
+}
+
+func (svc fleetService) notifyGroupHeartbeatConfig(ctx context.Context, ownerID string, groupID string) {
+	// only onlinish agents are notified, the others receive their configuration when they connect
+	list, err := svc.agentRepo.RetrieveAllByAgentGroupID(ctx, ownerID, groupID, true)
+	if err != nil {
+		svc.logger.Error("failed to retrieve agents to notify of heartbeat configuration", zap.String("agent_group_id", groupID), zap.Error(err))
+		return
+	}
+	svc.notifyHeartbeatConfig(ctx, list)
+}
+
+func (svc fleetService) notifyHeartbeatConfig(ctx context.Context, agents []Agent) {
+	for _, agent := range agents {
+		if agent.State != Online {
+			continue
+		}
+		if err := svc.agentComms.NotifyAgentHeartbeatConfig(ctx, agent); err != nil {
+			// note we will not make failure to deliver to one agent fatal, just log
+			svc.logger.Error("failure during agent heartbeat configuration comms", zap.String("agent_id", agent.MFThingID), zap.Error(err))
+		}
+	}
+}
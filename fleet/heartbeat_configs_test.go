@@ -0,0 +1,232 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package fleet_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/orb-community/orb/fleet"
+	flmocks "github.com/orb-community/orb/fleet/mocks"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeartbeatConfigValidate(t *testing.T) {
+	cases := map[string]struct {
+		cfg fleet.HeartbeatConfig
+		err error
+	}{
+		"validate heartbeat config": {
+			cfg: fleet.HeartbeatConfig{Interval: 30 * time.Second, StaleTimeout: 2 * time.Minute},
+			err: nil,
+		},
+		"validate heartbeat config with too short interval": {
+			cfg: fleet.HeartbeatConfig{Interval: time.Second, StaleTimeout: 2 * time.Minute},
+			err: fleet.ErrInvalidHeartbeatConfig,
+		},
+		"validate heartbeat config with timeout shorter than two intervals": {
+			cfg: fleet.HeartbeatConfig{Interval: time.Minute, StaleTimeout: 90 * time.Second},
+			err: fleet.ErrInvalidHeartbeatConfig,
+		},
+		"validate heartbeat config with too long timeout": {
+			cfg: fleet.HeartbeatConfig{Interval: time.Minute, StaleTimeout: 48 * time.Hour},
+			err: fleet.ErrInvalidHeartbeatConfig,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+		})
+	}
+}
+
+func TestResolveHeartbeatConfig(t *testing.T) {
+	owner := fleet.HeartbeatConfig{Interval: 40 * time.Second, StaleTimeout: 4 * time.Minute}
+	groupA := fleet.HeartbeatConfig{AgentGroupID: "a", Interval: 20 * time.Second, StaleTimeout: 3 * time.Minute}
+	groupB := fleet.HeartbeatConfig{AgentGroupID: "b", Interval: 30 * time.Second, StaleTimeout: time.Minute}
+
+	cases := map[string]struct {
+		configs  []fleet.HeartbeatConfig
+		groupIDs []string
+		interval time.Duration
+		timeout  time.Duration
+	}{
+		"resolve without any configuration": {
+			groupIDs: []string{"a"},
+			interval: fleet.DefaultHeartbeatInterval,
+			timeout:  fleet.DefaultTimeout,
+		},
+		"resolve owner configuration": {
+			configs:  []fleet.HeartbeatConfig{owner, groupA},
+			groupIDs: []string{"c"},
+			interval: owner.Interval,
+			timeout:  owner.StaleTimeout,
+		},
+		"resolve group configuration over owner one": {
+			configs:  []fleet.HeartbeatConfig{owner, groupA},
+			groupIDs: []string{"a"},
+			interval: groupA.Interval,
+			timeout:  groupA.StaleTimeout,
+		},
+		"resolve strictest of several groups": {
+			configs:  []fleet.HeartbeatConfig{owner, groupA, groupB},
+			groupIDs: []string{"a", "b"},
+			interval: groupA.Interval,
+			timeout:  groupB.StaleTimeout,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			cfg := fleet.ResolveHeartbeatConfig(tc.configs, tc.groupIDs)
+			assert.Equal(t, tc.interval, cfg.Interval, fmt.Sprintf("%s: expected interval %v got %v", desc, tc.interval, cfg.Interval))
+			assert.Equal(t, tc.timeout, cfg.StaleTimeout, fmt.Sprintf("%s: expected timeout %v got %v", desc, tc.timeout, cfg.StaleTimeout))
+		})
+	}
+}
+
+func TestEditHeartbeatConfig(t *testing.T) {
+	users := flmocks.NewAuthService(map[string]string{token: email})
+	thingsServer := newThingsServer(newThingsService(users))
+	defer thingsServer.Close()
+	fleetService := newService(users, thingsServer.URL)
+
+	cases := map[string]struct {
+		cfg   fleet.HeartbeatConfig
+		token string
+		err   error
+	}{
+		"edit owner heartbeat config": {
+			cfg:   fleet.HeartbeatConfig{Interval: 30 * time.Second, StaleTimeout: 2 * time.Minute},
+			token: token,
+			err:   nil,
+		},
+		"edit owner heartbeat config with invalid timeout": {
+			cfg:   fleet.HeartbeatConfig{Interval: 30 * time.Second, StaleTimeout: 40 * time.Second},
+			token: token,
+			err:   errors.ErrMalformedEntity,
+		},
+		"edit owner heartbeat config with wrong credentials": {
+			cfg:   fleet.HeartbeatConfig{Interval: 30 * time.Second, StaleTimeout: 2 * time.Minute},
+			token: "wrong",
+			err:   fleet.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			cfg, err := fleetService.EditHeartbeatConfig(context.Background(), tc.token, tc.cfg)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+			if err == nil {
+				assert.Equal(t, tc.cfg.Interval, cfg.Interval, fmt.Sprintf("%s: expected interval %v got %v", desc, tc.cfg.Interval, cfg.Interval))
+				assert.Equal(t, tc.cfg.StaleTimeout, cfg.StaleTimeout, fmt.Sprintf("%s: expected timeout %v got %v", desc, tc.cfg.StaleTimeout, cfg.StaleTimeout))
+			}
+		})
+	}
+}
+
+func TestEditAgentGroupHeartbeatConfig(t *testing.T) {
+	users := flmocks.NewAuthService(map[string]string{token: email})
+	thingsServer := newThingsServer(newThingsService(users))
+	defer thingsServer.Close()
+	fleetService := newService(users, thingsServer.URL)
+
+	ag, err := createAgentGroup(t, "hb-group", fleetService)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		cfg   fleet.HeartbeatConfig
+		token string
+		err   error
+	}{
+		"edit agent group heartbeat config": {
+			cfg:   fleet.HeartbeatConfig{AgentGroupID: ag.ID, Interval: 15 * time.Second, StaleTimeout: time.Minute},
+			token: token,
+			err:   nil,
+		},
+		"edit non-existing agent group heartbeat config": {
+			cfg:   fleet.HeartbeatConfig{AgentGroupID: "9bb1b244-a199-93c2-aa03-28067b431e2c", Interval: 15 * time.Second, StaleTimeout: time.Minute},
+			token: token,
+			err:   fleet.ErrNotFound,
+		},
+		"edit agent group heartbeat config with too short interval": {
+			cfg:   fleet.HeartbeatConfig{AgentGroupID: ag.ID, Interval: time.Second, StaleTimeout: time.Minute},
+			token: token,
+			err:   errors.ErrMalformedEntity,
+		},
+		"edit agent group heartbeat config with wrong credentials": {
+			cfg:   fleet.HeartbeatConfig{AgentGroupID: ag.ID, Interval: 15 * time.Second, StaleTimeout: time.Minute},
+			token: "wrong",
+			err:   fleet.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			_, err := fleetService.EditAgentGroupHeartbeatConfig(context.Background(), tc.token, tc.cfg)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+		})
+	}
+
+	cfg, err := fleetService.ViewAgentGroupHeartbeatConfig(context.Background(), token, ag.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, 15*time.Second, cfg.Interval, fmt.Sprintf("expected interval %v got %v", 15*time.Second, cfg.Interval))
+
+	err = fleetService.RemoveAgentGroupHeartbeatConfig(context.Background(), token, ag.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = fleetService.ViewAgentGroupHeartbeatConfig(context.Background(), token, ag.ID)
+	assert.True(t, errors.Contains(err, fleet.ErrNotFound), fmt.Sprintf("expected %s got %s", fleet.ErrNotFound, err))
+}
+
+func TestViewAgentHeartbeatConfig(t *testing.T) {
+	users := flmocks.NewAuthService(map[string]string{token: email})
+	thingsServer := newThingsServer(newThingsService(users))
+	defer thingsServer.Close()
+	fleetService := newService(users, thingsServer.URL)
+
+	ag, err := createAgent(t, "hb-agent", fleetService)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = fleetService.EditHeartbeatConfig(context.Background(), token, fleet.HeartbeatConfig{Interval: 30 * time.Second, StaleTimeout: 2 * time.Minute})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		id       string
+		token    string
+		interval time.Duration
+		err      error
+	}{
+		"view heartbeat config of an existing agent": {
+			id:       ag.MFThingID,
+			token:    token,
+			interval: 30 * time.Second,
+			err:      nil,
+		},
+		"view heartbeat config of non-existing agent": {
+			id:    "9bb1b244-a199-93c2-aa03-28067b431e2c",
+			token: token,
+			err:   fleet.ErrNotFound,
+		},
+		"view heartbeat config with wrong credentials": {
+			id:    ag.MFThingID,
+			token: "wrong",
+			err:   fleet.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			cfg, err := fleetService.ViewAgentHeartbeatConfig(context.Background(), tc.token, tc.id)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+			assert.Equal(t, tc.interval, cfg.Interval, fmt.Sprintf("%s: expected interval %v got %v", desc, tc.interval, cfg.Interval))
+		})
+	}
+}
@@ -204,7 +204,25 @@ func (c commsMetricsMiddleware) NotifyGroupPolicyUpdate(ctx context.Context, ag
 	return c.svc.NotifyGroupPolicyUpdate(ctx, ag, policyID, ownerID)
 }
 
-func (c commsMetricsMiddleware) NotifyAgentReset(ctx context.Context, agent Agent, fullReset bool, reason string) error {
+func (c commsMetricsMiddleware) NotifyAgentPolicyUpdate(ctx context.Context, a Agent, groupID string, policyID string, ownerID string) error {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "NotifyAgentPolicyUpdate",
+			"agent_id", a.MFThingID,
+			"agent_name", a.Name.String(),
+			"group_id", groupID,
+			"group_name", "",
+			"owner_id", ownerID,
+		}
+
+		c.requestCounter.With(labels...).Add(1)
+		c.requestLatency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+	return c.svc.NotifyAgentPolicyUpdate(ctx, a, groupID, policyID, ownerID)
+}
+
+func (c commsMetricsMiddleware) NotifyAgentReset(ctx context.Context, agent Agent, backendName string, reapplyPolicies bool, reason string) error {
 	defer func(begin time.Time) {
 		labels := []string{
 			"method", "NotifyAgentReset",
@@ -219,7 +237,79 @@ func (c commsMetricsMiddleware) NotifyAgentReset(ctx context.Context, agent Agen
 		c.requestLatency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
 
 	}(time.Now())
-	return c.svc.NotifyAgentReset(ctx, agent, fullReset, reason)
+	return c.svc.NotifyAgentReset(ctx, agent, backendName, reapplyPolicies, reason)
+}
+
+func (c commsMetricsMiddleware) NotifyAgentHeartbeatConfig(ctx context.Context, agent Agent) error {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "NotifyAgentHeartbeatConfig",
+			"agent_id", agent.MFThingID,
+			"agent_name", agent.Name.String(),
+			"group_id", "",
+			"group_name", "",
+			"owner_id", agent.MFOwnerID,
+		}
+
+		c.requestCounter.With(labels...).Add(1)
+		c.requestLatency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+	return c.svc.NotifyAgentHeartbeatConfig(ctx, agent)
+}
+
+func (c commsMetricsMiddleware) RequestAgentPolicyMetrics(ctx context.Context, agent Agent, policyID string) (AgentMetricsRPCPayload, error) {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "RequestAgentPolicyMetrics",
+			"agent_id", agent.MFThingID,
+			"agent_name", agent.Name.String(),
+			"group_id", "",
+			"group_name", "",
+			"owner_id", agent.MFOwnerID,
+		}
+
+		c.requestCounter.With(labels...).Add(1)
+		c.requestLatency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+	return c.svc.RequestAgentPolicyMetrics(ctx, agent, policyID)
+}
+
+func (c commsMetricsMiddleware) RequestAgentKeyRotation(ctx context.Context, agent Agent, key string) (string, error) {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "RequestAgentKeyRotation",
+			"agent_id", agent.MFThingID,
+			"agent_name", agent.Name.String(),
+			"group_id", "",
+			"group_name", "",
+			"owner_id", agent.MFOwnerID,
+		}
+
+		c.requestCounter.With(labels...).Add(1)
+		c.requestLatency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+	return c.svc.RequestAgentKeyRotation(ctx, agent, key)
+}
+
+func (c commsMetricsMiddleware) NotifyAgentKeyRotationCommit(ctx context.Context, agent Agent, requestID string, committed bool) error {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "NotifyAgentKeyRotationCommit",
+			"agent_id", agent.MFThingID,
+			"agent_name", agent.Name.String(),
+			"group_id", "",
+			"group_name", "",
+			"owner_id", agent.MFOwnerID,
+		}
+
+		c.requestCounter.With(labels...).Add(1)
+		c.requestLatency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+	return c.svc.NotifyAgentKeyRotationCommit(ctx, agent, requestID, committed)
 }
 
 func CommsMetricsMiddleware(svc AgentCommsService, counter metrics.Counter, latency metrics.Histogram) AgentCommsService {
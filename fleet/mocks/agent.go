@@ -2,21 +2,31 @@ package mocks
 
 import (
 	"context"
+	"github.com/gofrs/uuid"
 	"github.com/orb-community/orb/fleet"
 	"github.com/orb-community/orb/pkg/errors"
 	"github.com/orb-community/orb/pkg/types"
+	"sync"
 	"time"
 )
 
 var _ fleet.AgentRepository = (*agentRepositoryMock)(nil)
 
 type agentRepositoryMock struct {
-	counter    uint64
-	agentsMock map[string]fleet.Agent
+	counter     uint64
+	agentsMock  map[string]fleet.Agent
+	stateEvents map[string][]fleet.AgentStateEvent
+	hbConfigs   map[string]fleet.HeartbeatConfig
+	logs        map[string][]fleet.AgentLogEntry
+	enrollments map[string]fleet.EnrollmentToken
+	// bulk jobs are updated in the background while being polled
+	bulkMu      *sync.Mutex
+	bulkJobs    map[string]fleet.BulkJob
+	bulkClaimed map[string]bool
 }
 
-func (a agentRepositoryMock) SetStaleStatus(_ context.Context, _ time.Duration) (int64, error) {
-	return 0, nil
+func (a agentRepositoryMock) SetStaleStatus(_ context.Context, _ time.Duration) ([]fleet.Agent, error) {
+	return nil, nil
 }
 
 func (a agentRepositoryMock) RetrieveAgentInfoByChannelID(_ context.Context, channelID string) (fleet.Agent, error) {
@@ -43,12 +53,61 @@ func (a agentRepositoryMock) RetrieveByID(_ context.Context, ownerID string, thi
 	return fleet.Agent{}, fleet.ErrNotFound
 }
 
-func (a agentRepositoryMock) RetrieveMatchingAgents(_ context.Context, _ string, _ types.Tags) (types.Metadata, error) {
+func (a agentRepositoryMock) RetrieveMatchingAgents(_ context.Context, _ string, _ types.Tags, _ fleet.TagSelector) (types.Metadata, error) {
 	return nil, nil
 }
 
-func (a agentRepositoryMock) UpdateHeartbeatByIDWithChannel(_ context.Context, _ fleet.Agent) error {
-	panic("implement me")
+func (a agentRepositoryMock) UpdateHeartbeatByIDWithChannel(_ context.Context, agent fleet.Agent) error {
+	current, ok := a.agentsMock[agent.MFThingID]
+	if !ok || current.MFChannelID != agent.MFChannelID {
+		return fleet.ErrNotFound
+	}
+	previous := current.State
+	current.State = agent.State
+	current.LastHBData = agent.LastHBData
+	current.LastHB = time.Now()
+	a.agentsMock[agent.MFThingID] = current
+	if previous != agent.State {
+		a.recordStateEvent(current, previous.String())
+	}
+	return nil
+}
+
+// recordStateEvent mimics the trigger recording the agent state transitions in the database
+func (a agentRepositoryMock) recordStateEvent(agent fleet.Agent, previousState string) {
+	events := a.stateEvents[agent.MFThingID]
+	a.stateEvents[agent.MFThingID] = append(events, fleet.AgentStateEvent{
+		ID:            int64(len(events) + 1),
+		MFThingID:     agent.MFThingID,
+		MFOwnerID:     agent.MFOwnerID,
+		State:         agent.State.String(),
+		PreviousState: previousState,
+		Created:       time.Now(),
+	})
+}
+
+func (a agentRepositoryMock) RetrieveStateEvents(_ context.Context, ownerID string, thingID string, from time.Time, to time.Time, limit uint64) ([]fleet.AgentStateEvent, error) {
+	var events []fleet.AgentStateEvent
+	for _, e := range a.stateEvents[thingID] {
+		if e.MFOwnerID != ownerID || e.Created.Before(from) || e.Created.After(to) {
+			continue
+		}
+		events = append(events, e)
+	}
+	if limit > 0 && uint64(len(events)) > limit {
+		events = events[uint64(len(events))-limit:]
+	}
+	return events, nil
+}
+
+func (a agentRepositoryMock) RetrieveLastStateEvent(_ context.Context, ownerID string, thingID string, at time.Time) (fleet.AgentStateEvent, error) {
+	events := a.stateEvents[thingID]
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].MFOwnerID == ownerID && events[i].Backend == "" && !events[i].Created.After(at) {
+			return events[i], nil
+		}
+	}
+	return fleet.AgentStateEvent{}, fleet.ErrNotFound
 }
 
 func (a agentRepositoryMock) Save(_ context.Context, agent fleet.Agent) error {
@@ -59,6 +118,7 @@ func (a agentRepositoryMock) Save(_ context.Context, agent fleet.Agent) error {
 	}
 	a.agentsMock[agent.MFThingID] = agent
 	a.counter++
+	a.recordStateEvent(agent, "")
 	return nil
 }
 
@@ -99,7 +159,10 @@ func (a agentRepositoryMock) RetrieveAll(_ context.Context, owner string, pm fle
 	var agents []fleet.Agent
 	id := uint64(0)
 	for _, v := range a.agentsMock {
-		if v.MFOwnerID == owner && id >= first && id < last {
+		if v.MFOwnerID != owner || !matchAgentTags(v, pm.Tags) {
+			continue
+		}
+		if id >= first && id < last {
 			agents = append(agents, v)
 		}
 		id++
@@ -143,8 +206,259 @@ func (a agentRepositoryMock) Delete(_ context.Context, ownerID, thingID string)
 	return nil
 }
 
+func (a agentRepositoryMock) SaveHeartbeatConfig(_ context.Context, cfg fleet.HeartbeatConfig) error {
+	if cfg.MFOwnerID == "" {
+		return errors.ErrMalformedEntity
+	}
+	cfg.Updated = time.Now()
+	a.hbConfigs[cfg.MFOwnerID+cfg.AgentGroupID] = cfg
+	return nil
+}
+
+func (a agentRepositoryMock) RetrieveHeartbeatConfigs(_ context.Context, ownerID string) ([]fleet.HeartbeatConfig, error) {
+	var configs []fleet.HeartbeatConfig
+	for _, c := range a.hbConfigs {
+		if c.MFOwnerID == ownerID {
+			configs = append(configs, c)
+		}
+	}
+	return configs, nil
+}
+
+func (a agentRepositoryMock) DeleteHeartbeatConfig(_ context.Context, ownerID string, groupID string) error {
+	if ownerID == "" || groupID == "" {
+		return errors.ErrMalformedEntity
+	}
+	delete(a.hbConfigs, ownerID+groupID)
+	return nil
+}
+
+func (a agentRepositoryMock) SaveAgentLogs(_ context.Context, ownerID string, thingID string, entries []fleet.AgentLogEntry) error {
+	if ownerID == "" || thingID == "" {
+		return errors.ErrMalformedEntity
+	}
+	logs := append(a.logs[thingID], entries...)
+	if len(logs) > fleet.MaxAgentLogEntries {
+		logs = logs[len(logs)-fleet.MaxAgentLogEntries:]
+	}
+	a.logs[thingID] = logs
+	return nil
+}
+
+func (a agentRepositoryMock) RetrieveAgentLogs(_ context.Context, ownerID string, thingID string, since time.Time, levels []string, limit uint64) ([]fleet.AgentLogEntry, error) {
+	if ownerID == "" || thingID == "" {
+		return nil, errors.ErrMalformedEntity
+	}
+	var entries []fleet.AgentLogEntry
+	for _, e := range a.logs[thingID] {
+		if e.TimeStamp.Before(since) {
+			continue
+		}
+		for _, l := range levels {
+			if e.Level == l {
+				entries = append(entries, e)
+				break
+			}
+		}
+	}
+	if limit > 0 && uint64(len(entries)) > limit {
+		entries = entries[uint64(len(entries))-limit:]
+	}
+	return entries, nil
+}
+
+func (a agentRepositoryMock) SaveEnrollmentToken(_ context.Context, et fleet.EnrollmentToken) (string, error) {
+	if !et.Name.IsValid() || et.MFOwnerID == "" || et.TokenHash == "" {
+		return "", errors.ErrMalformedEntity
+	}
+	id, err := uuid.NewV4()
+	if err != nil {
+		return "", err
+	}
+	et.ID = id.String()
+	et.Created = time.Now()
+	a.enrollments[et.ID] = et
+	return et.ID, nil
+}
+
+func (a agentRepositoryMock) RetrieveEnrollmentTokens(_ context.Context, ownerID string) ([]fleet.EnrollmentToken, error) {
+	var items []fleet.EnrollmentToken
+	for _, et := range a.enrollments {
+		if et.MFOwnerID == ownerID {
+			items = append(items, et)
+		}
+	}
+	return items, nil
+}
+
+func (a agentRepositoryMock) RetrieveEnrollmentTokenByID(_ context.Context, ownerID string, id string) (fleet.EnrollmentToken, error) {
+	if et, ok := a.enrollments[id]; ok && et.MFOwnerID == ownerID {
+		return et, nil
+	}
+	return fleet.EnrollmentToken{}, errors.ErrNotFound
+}
+
+func (a agentRepositoryMock) RetrieveEnrollmentTokenByHash(_ context.Context, tokenHash string) (fleet.EnrollmentToken, error) {
+	for _, et := range a.enrollments {
+		if et.TokenHash == tokenHash {
+			return et, nil
+		}
+	}
+	return fleet.EnrollmentToken{}, errors.ErrNotFound
+}
+
+func (a agentRepositoryMock) RevokeEnrollmentToken(_ context.Context, ownerID string, id string) error {
+	et, ok := a.enrollments[id]
+	if !ok || et.MFOwnerID != ownerID {
+		return errors.ErrNotFound
+	}
+	et.Revoked = true
+	a.enrollments[id] = et
+	return nil
+}
+
+func (a agentRepositoryMock) ConsumeEnrollmentToken(_ context.Context, id string) error {
+	et, ok := a.enrollments[id]
+	if !ok || et.Usable(time.Now()) != nil {
+		return fleet.ErrEnrollmentTokenExhausted
+	}
+	et.Uses++
+	a.enrollments[id] = et
+	return nil
+}
+
+func (a agentRepositoryMock) RefundEnrollmentToken(_ context.Context, id string) error {
+	et, ok := a.enrollments[id]
+	if !ok || et.Uses == 0 {
+		return errors.ErrNotFound
+	}
+	et.Uses--
+	a.enrollments[id] = et
+	return nil
+}
+
+func (a agentRepositoryMock) SaveBulkJob(_ context.Context, job fleet.BulkJob) (string, error) {
+	if job.MFOwnerID == "" || job.Operation == "" || job.State == "" {
+		return "", errors.ErrMalformedEntity
+	}
+	id, err := uuid.NewV4()
+	if err != nil {
+		return "", err
+	}
+	job.ID = id.String()
+	job.Created = time.Now()
+	job.LastModified = job.Created
+	a.bulkMu.Lock()
+	defer a.bulkMu.Unlock()
+	a.bulkJobs[job.ID] = job
+	return job.ID, nil
+}
+
+func (a agentRepositoryMock) RetrieveBulkJob(_ context.Context, ownerID string, id string) (fleet.BulkJob, error) {
+	a.bulkMu.Lock()
+	defer a.bulkMu.Unlock()
+	if job, ok := a.bulkJobs[id]; ok && job.MFOwnerID == ownerID {
+		job.Failures = append([]fleet.BulkAgentFailure(nil), job.Failures...)
+		return job, nil
+	}
+	return fleet.BulkJob{}, errors.Wrap(fleet.ErrBulkJobNotFound, errors.ErrNotFound)
+}
+
+func (a agentRepositoryMock) RecordBulkJobProgress(_ context.Context, id string, failure *fleet.BulkAgentFailure) error {
+	a.bulkMu.Lock()
+	defer a.bulkMu.Unlock()
+	job, ok := a.bulkJobs[id]
+	if !ok {
+		return fleet.ErrBulkJobNotFound
+	}
+	if failure != nil {
+		job.Failed++
+		job.Failures = append(job.Failures, *failure)
+	} else {
+		job.Succeeded++
+	}
+	job.LastModified = time.Now()
+	a.bulkJobs[id] = job
+	return nil
+}
+
+func (a agentRepositoryMock) UpdateBulkJobState(_ context.Context, id string, state string) error {
+	a.bulkMu.Lock()
+	defer a.bulkMu.Unlock()
+	job, ok := a.bulkJobs[id]
+	if !ok {
+		return fleet.ErrBulkJobNotFound
+	}
+	job.State = state
+	job.LastModified = time.Now()
+	a.bulkJobs[id] = job
+	return nil
+}
+
+func (a agentRepositoryMock) ClaimBulkJob(_ context.Context) (fleet.BulkJob, error) {
+	a.bulkMu.Lock()
+	defer a.bulkMu.Unlock()
+	var claim *fleet.BulkJob
+	for _, job := range a.bulkJobs {
+		if job.State != fleet.BulkJobRunning || a.bulkClaimed[job.ID] {
+			continue
+		}
+		if claim == nil || job.Created.Before(claim.Created) {
+			job := job
+			claim = &job
+		}
+	}
+	if claim == nil {
+		return fleet.BulkJob{}, fleet.ErrBulkJobNotFound
+	}
+	a.bulkClaimed[claim.ID] = true
+	claim.LastModified = time.Now()
+	a.bulkJobs[claim.ID] = *claim
+	return *claim, nil
+}
+
+func (a agentRepositoryMock) FailStaleBulkJobs(_ context.Context, before time.Time) (uint64, error) {
+	a.bulkMu.Lock()
+	defer a.bulkMu.Unlock()
+	var failed uint64
+	for id, job := range a.bulkJobs {
+		if job.State == fleet.BulkJobRunning && a.bulkClaimed[id] && job.LastModified.Before(before) {
+			job.State = fleet.BulkJobFailed
+			job.LastModified = time.Now()
+			a.bulkJobs[id] = job
+			failed++
+		}
+	}
+	return failed, nil
+}
+
+// matchAgentTags tells whether the agent tags merged with the orb tags contain the filter, as RetrieveAll does
+func matchAgentTags(ag fleet.Agent, filter types.Tags) bool {
+	for k, v := range filter {
+		if ag.OrbTags != nil {
+			if tv, ok := (*ag.OrbTags)[k]; ok {
+				if tv != v {
+					return false
+				}
+				continue
+			}
+		}
+		if ag.AgentTags[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
 func NewAgentRepositoryMock() fleet.AgentRepository {
 	return &agentRepositoryMock{
-		agentsMock: make(map[string]fleet.Agent),
+		agentsMock:  make(map[string]fleet.Agent),
+		stateEvents: make(map[string][]fleet.AgentStateEvent),
+		hbConfigs:   make(map[string]fleet.HeartbeatConfig),
+		logs:        make(map[string][]fleet.AgentLogEntry),
+		enrollments: make(map[string]fleet.EnrollmentToken),
+		bulkMu:      &sync.Mutex{},
+		bulkJobs:    make(map[string]fleet.BulkJob),
+		bulkClaimed: make(map[string]bool),
 	}
 }
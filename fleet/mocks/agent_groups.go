@@ -100,6 +100,7 @@ func (a *agentGroupRepositoryMock) Update(ctx context.Context, ownerID string, g
 		currentGroup.Name = group.Name
 		currentGroup.Description = group.Description
 		currentGroup.Tags = group.Tags
+		currentGroup.Selector = group.Selector
 
 		a.agentGroupMock[group.ID] = currentGroup
 
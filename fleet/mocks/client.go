@@ -34,6 +34,10 @@ func (g fleetGrpcClientMock) RetrieveAgentGroup(ctx context.Context, in *pb.Agen
 	return &pb.AgentGroupRes{}, nil
 }
 
+func (g fleetGrpcClientMock) RetrieveBackendComponents(ctx context.Context, in *pb.BackendComponentsReq, opts ...grpc.CallOption) (*pb.BackendComponentsRes, error) {
+	return &pb.BackendComponentsRes{}, nil
+}
+
 func NewClient() pb.FleetServiceClient {
 	return &fleetGrpcClientMock{}
 }
@@ -18,7 +18,27 @@ func (ac agentCommsServiceMock) NotifyGroupDatasetEdit(_ context.Context, _ flee
 	return nil
 }
 
-func (ac agentCommsServiceMock) NotifyAgentReset(_ context.Context, _ fleet.Agent, _ bool, _ string) error {
+func (ac agentCommsServiceMock) NotifyAgentReset(_ context.Context, _ fleet.Agent, _ string, _ bool, _ string) error {
+	return nil
+}
+
+func (ac agentCommsServiceMock) NotifyAgentHeartbeatConfig(_ context.Context, _ fleet.Agent) error {
+	return nil
+}
+
+func (ac agentCommsServiceMock) RequestAgentPolicyMetrics(_ context.Context, _ fleet.Agent, policyID string) (fleet.AgentMetricsRPCPayload, error) {
+	return fleet.AgentMetricsRPCPayload{
+		PolicyID: policyID,
+		Format:   "json",
+		Data:     []byte(`{}`),
+	}, nil
+}
+
+func (ac agentCommsServiceMock) RequestAgentKeyRotation(_ context.Context, _ fleet.Agent, _ string) (string, error) {
+	return "rotation", nil
+}
+
+func (ac agentCommsServiceMock) NotifyAgentKeyRotationCommit(_ context.Context, _ fleet.Agent, _ string, _ bool) error {
 	return nil
 }
 
@@ -34,6 +54,10 @@ func (ac agentCommsServiceMock) NotifyGroupPolicyUpdate(_ context.Context, _ fle
 	return nil
 }
 
+func (ac agentCommsServiceMock) NotifyAgentPolicyUpdate(_ context.Context, _ fleet.Agent, _ string, _ string, _ string) error {
+	return nil
+}
+
 func (ac agentCommsServiceMock) NotifyGroupDatasetRemoval(_ context.Context, _ fleet.AgentGroup, _ string, _ string) error {
 	return nil
 }
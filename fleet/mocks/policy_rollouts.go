@@ -0,0 +1,100 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/orb-community/orb/fleet"
+	"github.com/orb-community/orb/pkg/errors"
+)
+
+var _ fleet.PolicyRolloutRepository = (*policyRolloutRepositoryMock)(nil)
+
+type policyRolloutRepositoryMock struct {
+	exclusive   sync.Mutex
+	mu          sync.Mutex
+	rollouts    []fleet.PolicyRollout
+	rolloutByID map[string]int
+}
+
+func NewPolicyRolloutRepository() fleet.PolicyRolloutRepository {
+	return &policyRolloutRepositoryMock{
+		rolloutByID: make(map[string]int),
+	}
+}
+
+func (r *policyRolloutRepositoryMock) Save(_ context.Context, rollout fleet.PolicyRollout) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if rollout.PolicyID == "" || rollout.MFOwnerID == "" || rollout.State == "" {
+		return "", errors.ErrMalformedEntity
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return "", errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	rollout.ID = id.String()
+	if rollout.Created.IsZero() {
+		rollout.Created = time.Now()
+	}
+	rollout.LastModified = rollout.Created
+
+	r.rolloutByID[rollout.ID] = len(r.rollouts)
+	r.rollouts = append(r.rollouts, rollout)
+	return rollout.ID, nil
+}
+
+func (r *policyRolloutRepositoryMock) RetrieveLatestByPolicyID(_ context.Context, ownerID string, policyID string) (fleet.PolicyRollout, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := len(r.rollouts) - 1; i >= 0; i-- {
+		if r.rollouts[i].MFOwnerID == ownerID && r.rollouts[i].PolicyID == policyID {
+			return r.rollouts[i], nil
+		}
+	}
+	return fleet.PolicyRollout{}, fleet.ErrRolloutNotFound
+}
+
+func (r *policyRolloutRepositoryMock) RetrieveAllByState(_ context.Context, state string) ([]fleet.PolicyRollout, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var items []fleet.PolicyRollout
+	for _, rollout := range r.rollouts {
+		if rollout.State == state {
+			items = append(items, rollout)
+		}
+	}
+	return items, nil
+}
+
+func (r *policyRolloutRepositoryMock) UpdateState(_ context.Context, ownerID string, rolloutID string, state string, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.rolloutByID[rolloutID]
+	if !ok || r.rollouts[i].MFOwnerID != ownerID {
+		return fleet.ErrNotFound
+	}
+	r.rollouts[i].State = state
+	r.rollouts[i].Reason = reason
+	r.rollouts[i].LastModified = time.Now()
+	return nil
+}
+
+func (r *policyRolloutRepositoryMock) RunExclusive(ctx context.Context, fn func(ctx context.Context) error) (bool, error) {
+	if !r.exclusive.TryLock() {
+		return false, nil
+	}
+	defer r.exclusive.Unlock()
+	return true, fn(ctx)
+}
@@ -141,8 +141,22 @@ func (svc *mainfluxThings) UpdateThing(context.Context, string, things.Thing) er
 	panic("not implemented")
 }
 
-func (svc *mainfluxThings) UpdateKey(context.Context, string, string, string) error {
-	panic("not implemented")
+func (svc *mainfluxThings) UpdateKey(_ context.Context, owner, id, key string) error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	userID, err := svc.auth.Identify(context.Background(), &mainflux.Token{Value: owner})
+	if err != nil {
+		return fleet.ErrUnauthorizedAccess
+	}
+
+	t, ok := svc.things[id]
+	if !ok || t.Owner != userID.Email {
+		return fleet.ErrNotFound
+	}
+	t.Key = key
+	svc.things[id] = t
+	return nil
 }
 
 func (svc *mainfluxThings) ListThings(context.Context, string, things.PageMetadata) (things.Page, error) {
@@ -0,0 +1,380 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package fleet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/orb-community/orb/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// RolloutCanary the new version only runs on the canary agents
+	RolloutCanary = "canary"
+	// RolloutPromoted every canary runs the new version, it was sent to all the agents of the groups
+	RolloutPromoted = "promoted"
+	// RolloutHalted a canary failed, they keep the new version while the other agents stay on the previous one
+	RolloutHalted = "halted"
+	// RolloutRolledBack a canary failed and the previous version was restored on the policy
+	RolloutRolledBack = "rolled_back"
+	// RolloutSuperseded a newer version of the policy was sent before the rollout finished
+	RolloutSuperseded = "superseded"
+
+	RolloutOnFailureHalt     = "halt"
+	RolloutOnFailureRollback = "rollback"
+
+	policyStateRunning       = "running"
+	policyStateFailedToApply = "failed_to_apply"
+)
+
+var (
+	// ErrRolloutNotFound indicates the policy was never rolled out in stages
+	ErrRolloutNotFound = errors.New("policy has no staged rollout")
+)
+
+// PolicyRollout tracks a policy version staged to a share of the agents of the groups running it
+type PolicyRollout struct {
+	ID              string
+	PolicyID        string
+	MFOwnerID       string
+	Version         int32
+	PreviousVersion int32
+	GroupIDs        []string
+	CanaryAgentIDs  []string
+	CanaryPercent   int
+	CanaryCount     int
+	OnFailure       string
+	Timeout         time.Duration
+	State           string
+	Reason          string
+	Created         time.Time
+	LastModified    time.Time
+}
+
+// IsCanary tells whether the agent was picked to receive the new version first
+func (r PolicyRollout) IsCanary(agentID string) bool {
+	for _, id := range r.CanaryAgentIDs {
+		if id == agentID {
+			return true
+		}
+	}
+	return false
+}
+
+// Pinned tells whether the agents that are not canaries must keep running the previous version
+func (r PolicyRollout) Pinned() bool {
+	return r.State == RolloutCanary || r.State == RolloutHalted
+}
+
+// AgentRolloutState is the policy version an agent of the rollout groups reports in its heartbeats
+type AgentRolloutState struct {
+	AgentID      string
+	AgentName    string
+	AgentGroupID string
+	Canary       bool
+	Version      int32
+	State        string
+	Error        string
+}
+
+type PolicyRolloutStatus struct {
+	PolicyRollout
+	Agents []AgentRolloutState
+}
+
+type PolicyRolloutService interface {
+	// StartPolicyRolloutInternal sends a new policy version to the canary agents of the groups, the remaining agents
+	// receive it once every canary reports it running
+	StartPolicyRolloutInternal(ctx context.Context, rollout PolicyRollout) (PolicyRollout, error)
+	// SupersedePolicyRolloutsInternal ends the rollouts of a policy still in progress, used when a version is sent to every agent
+	SupersedePolicyRolloutsInternal(ctx context.Context, ownerID string, policyID string) error
+	// CheckPolicyRolloutsInternal promotes, halts or rolls back the rollouts in progress according to the canaries
+	// heartbeats, it returns the rollouts that must be rolled back
+	CheckPolicyRolloutsInternal(ctx context.Context) ([]PolicyRollout, error)
+	// ViewPolicyRollout retrieves the latest rollout of a policy, with the version each agent of its groups runs
+	ViewPolicyRollout(ctx context.Context, token string, policyID string) (PolicyRolloutStatus, error)
+}
+
+type PolicyRolloutRepository interface {
+	// Save persists the PolicyRollout and returns its id
+	Save(ctx context.Context, rollout PolicyRollout) (string, error)
+	// RetrieveLatestByPolicyID retrieves the most recent rollout of a policy
+	RetrieveLatestByPolicyID(ctx context.Context, ownerID string, policyID string) (PolicyRollout, error)
+	// RetrieveAllByState retrieves the rollouts of every owner in the given state
+	RetrieveAllByState(ctx context.Context, state string) ([]PolicyRollout, error)
+	// UpdateState changes the state of a rollout, recording why
+	UpdateState(ctx context.Context, ownerID string, rolloutID string, state string, reason string) error
+	// RunExclusive runs fn unless another fleet replica is running it, it returns whether fn was run
+	RunExclusive(ctx context.Context, fn func(ctx context.Context) error) (bool, error)
+}
+
+func (svc fleetService) StartPolicyRolloutInternal(ctx context.Context, rollout PolicyRollout) (PolicyRollout, error) {
+	if rollout.PolicyID == "" || rollout.MFOwnerID == "" || len(rollout.GroupIDs) == 0 {
+		return PolicyRollout{}, errors.ErrMalformedEntity
+	}
+
+	if err := svc.SupersedePolicyRolloutsInternal(ctx, rollout.MFOwnerID, rollout.PolicyID); err != nil {
+		return PolicyRollout{}, err
+	}
+
+	agents, agentGroups, err := svc.rolloutAgents(ctx, rollout.MFOwnerID, rollout.GroupIDs, true)
+	if err != nil {
+		return PolicyRollout{}, err
+	}
+	canaries := agents[:canarySize(len(agents), rollout.CanaryPercent, rollout.CanaryCount)]
+
+	rollout.CanaryAgentIDs = make([]string, len(canaries))
+	for i, a := range canaries {
+		rollout.CanaryAgentIDs[i] = a.MFThingID
+	}
+	rollout.State = RolloutCanary
+	if len(canaries) == 0 {
+		rollout.State = RolloutPromoted
+		rollout.Reason = "no online agents to stage the rollout on"
+	}
+
+	id, err := svc.rolloutRepo.Save(ctx, rollout)
+	if err != nil {
+		return PolicyRollout{}, err
+	}
+	rollout.ID = id
+
+	if rollout.State == RolloutPromoted {
+		return rollout, svc.promoteRollout(ctx, rollout)
+	}
+
+	for _, a := range canaries {
+		if err := svc.agentComms.NotifyAgentPolicyUpdate(ctx, a, agentGroups[a.MFThingID], rollout.PolicyID, rollout.MFOwnerID); err != nil {
+			svc.logger.Error("failed to send policy version to canary agent", zap.String("policy_id", rollout.PolicyID),
+				zap.String("agent_id", a.MFThingID), zap.Error(err))
+		}
+	}
+
+	return rollout, nil
+}
+
+func (svc fleetService) SupersedePolicyRolloutsInternal(ctx context.Context, ownerID string, policyID string) error {
+	current, err := svc.rolloutRepo.RetrieveLatestByPolicyID(ctx, ownerID, policyID)
+	if err != nil {
+		if errors.Contains(err, ErrRolloutNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if !current.Pinned() {
+		return nil
+	}
+
+	return svc.rolloutRepo.UpdateState(ctx, ownerID, current.ID, RolloutSuperseded, "a newer policy version was sent to the agents")
+}
+
+func (svc fleetService) CheckPolicyRolloutsInternal(ctx context.Context) ([]PolicyRollout, error) {
+	// every replica checks the rollouts on its own ticker, only one of them may promote or roll back a rollout
+	var rolledBack []PolicyRollout
+	ran, err := svc.rolloutRepo.RunExclusive(ctx, func(ctx context.Context) (err error) {
+		rolledBack, err = svc.checkPolicyRollouts(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !ran {
+		svc.logger.Debug("policy rollouts are being checked by another replica")
+	}
+	return rolledBack, nil
+}
+
+func (svc fleetService) checkPolicyRollouts(ctx context.Context) ([]PolicyRollout, error) {
+	rollouts, err := svc.rolloutRepo.RetrieveAllByState(ctx, RolloutCanary)
+	if err != nil {
+		return nil, err
+	}
+
+	var rolledBack []PolicyRollout
+	for _, r := range rollouts {
+		state, reason := svc.evaluateRollout(ctx, r, time.Now())
+		if state == RolloutCanary {
+			continue
+		}
+
+		if err := svc.rolloutRepo.UpdateState(ctx, r.MFOwnerID, r.ID, state, reason); err != nil {
+			svc.logger.Error("failed to update policy rollout", zap.String("rollout_id", r.ID), zap.Error(err))
+			continue
+		}
+		r.State = state
+		r.Reason = reason
+		svc.logger.Info("policy rollout finished", zap.String("policy_id", r.PolicyID), zap.Int32("version", r.Version),
+			zap.String("state", state), zap.String("reason", reason))
+
+		switch state {
+		case RolloutPromoted:
+			if err := svc.promoteRollout(ctx, r); err != nil {
+				svc.logger.Error("failed to promote policy rollout", zap.String("rollout_id", r.ID), zap.Error(err))
+			}
+		case RolloutRolledBack:
+			rolledBack = append(rolledBack, r)
+		}
+	}
+
+	return rolledBack, nil
+}
+
+func (svc fleetService) ViewPolicyRollout(ctx context.Context, token string, policyID string) (PolicyRolloutStatus, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return PolicyRolloutStatus{}, err
+	}
+
+	rollout, err := svc.rolloutRepo.RetrieveLatestByPolicyID(ctx, ownerID, policyID)
+	if err != nil {
+		return PolicyRolloutStatus{}, err
+	}
+
+	agents, agentGroups, err := svc.rolloutAgents(ctx, ownerID, rollout.GroupIDs, false)
+	if err != nil {
+		return PolicyRolloutStatus{}, err
+	}
+
+	status := PolicyRolloutStatus{PolicyRollout: rollout, Agents: make([]AgentRolloutState, len(agents))}
+	for i, a := range agents {
+		state := AgentRolloutState{
+			AgentID:      a.MFThingID,
+			AgentName:    a.Name.String(),
+			AgentGroupID: agentGroups[a.MFThingID],
+			Canary:       rollout.IsCanary(a.MFThingID),
+		}
+		if info, ok := agentPolicyState(a, policyID); ok {
+			state.Version = info.Version
+			state.State = info.State
+			state.Error = info.Error
+		}
+		status.Agents[i] = state
+	}
+
+	return status, nil
+}
+
+// evaluateRollout decides the next state of a rollout in progress: it is promoted once every canary runs the
+// new version and fails as soon as one canary can not apply it or the canaries did not report it in time
+func (svc fleetService) evaluateRollout(ctx context.Context, r PolicyRollout, now time.Time) (string, string) {
+	failed := RolloutRolledBack
+	if r.OnFailure == RolloutOnFailureHalt {
+		failed = RolloutHalted
+	}
+
+	running := 0
+	for _, id := range r.CanaryAgentIDs {
+		a, err := svc.agentRepo.RetrieveByID(ctx, r.MFOwnerID, id)
+		if err != nil {
+			svc.logger.Warn("failed to retrieve canary agent", zap.String("rollout_id", r.ID), zap.String("agent_id", id), zap.Error(err))
+			continue
+		}
+		info, ok := agentPolicyState(a, r.PolicyID)
+		if !ok || info.Version != r.Version {
+			continue
+		}
+		switch info.State {
+		case policyStateFailedToApply:
+			return failed, fmt.Sprintf("agent %s failed to apply version %d: %s", a.Name.String(), r.Version, info.Error)
+		case policyStateRunning:
+			running++
+		}
+	}
+
+	if running == len(r.CanaryAgentIDs) {
+		return RolloutPromoted, fmt.Sprintf("%d canary agents run version %d", running, r.Version)
+	}
+
+	if r.Timeout > 0 && now.Sub(r.Created) > r.Timeout {
+		return failed, fmt.Sprintf("%d of %d canary agents run version %d after %v", running, len(r.CanaryAgentIDs), r.Version, r.Timeout)
+	}
+
+	return RolloutCanary, ""
+}
+
+// promoteRollout sends the new version to every agent of the rollout groups
+func (svc fleetService) promoteRollout(ctx context.Context, r PolicyRollout) error {
+	for _, groupID := range r.GroupIDs {
+		ag, err := svc.agentGroupRepository.RetrieveByID(ctx, groupID, r.MFOwnerID)
+		if err != nil {
+			return err
+		}
+		if err := svc.agentComms.NotifyGroupPolicyUpdate(ctx, ag, r.PolicyID, r.MFOwnerID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rolloutAgents lists the agents of the groups sorted by id, along with the first group each agent was found in
+func (svc fleetService) rolloutAgents(ctx context.Context, ownerID string, groupIDs []string, onlinishOnly bool) ([]Agent, map[string]string, error) {
+	var agents []Agent
+	agentGroups := make(map[string]string)
+	for _, groupID := range groupIDs {
+		list, err := svc.agentRepo.RetrieveAllByAgentGroupID(ctx, ownerID, groupID, onlinishOnly)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, a := range list {
+			if _, ok := agentGroups[a.MFThingID]; ok {
+				continue
+			}
+			agentGroups[a.MFThingID] = groupID
+			agents = append(agents, a)
+		}
+	}
+
+	sort.Slice(agents, func(i, j int) bool {
+		return agents[i].MFThingID < agents[j].MFThingID
+	})
+
+	return agents, agentGroups, nil
+}
+
+// canarySize is the number of agents receiving a staged version first, at least one agent is picked
+// whenever the groups have agents
+func canarySize(total int, percent int, count int) int {
+	if total == 0 {
+		return 0
+	}
+
+	size := count
+	if percent > 0 {
+		size = (total*percent + 99) / 100
+	}
+	if size < 1 {
+		size = 1
+	}
+	if size > total {
+		size = total
+	}
+	return size
+}
+
+// agentPolicyState reads the state of a policy from the last heartbeat of the agent
+func agentPolicyState(a Agent, policyID string) (PolicyStateInfo, bool) {
+	states, ok := a.LastHBData["policy_state"]
+	if !ok {
+		return PolicyStateInfo{}, false
+	}
+
+	data, err := json.Marshal(states)
+	if err != nil {
+		return PolicyStateInfo{}, false
+	}
+	var policyState map[string]PolicyStateInfo
+	if err := json.Unmarshal(data, &policyState); err != nil {
+		return PolicyStateInfo{}, false
+	}
+
+	info, ok := policyState[policyID]
+	return info, ok
+}
@@ -0,0 +1,328 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package fleet_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/orb-community/orb/fleet"
+	flmocks "github.com/orb-community/orb/fleet/mocks"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRolloutService(t *testing.T, agents int) (fleet.Service, fleet.AgentRepository, fleet.AgentGroup, []fleet.Agent) {
+	t.Helper()
+	users := flmocks.NewAuthService(map[string]string{token: email})
+	thingsServer := newThingsServer(newThingsService(users))
+	t.Cleanup(thingsServer.Close)
+
+	agentGroupRepo := flmocks.NewAgentGroupRepository()
+	agentRepo := flmocks.NewAgentRepositoryMock()
+	svc := newFleetService(users, thingsServer.URL, agentGroupRepo, agentRepo)
+
+	ag, err := createAgentGroup(t, "rollout-group", svc)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	var list []fleet.Agent
+	for i := 0; i < agents; i++ {
+		a, err := createAgent(t, fmt.Sprintf("rollout-agent-%d", i), svc)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		list = append(list, a)
+	}
+
+	return svc, agentRepo, ag, list
+}
+
+func reportPolicyState(t *testing.T, agentRepo fleet.AgentRepository, a fleet.Agent, policyID string, info fleet.PolicyStateInfo) {
+	t.Helper()
+	a.State = fleet.Online
+	a.LastHBData = types.Metadata{
+		"policy_state": map[string]fleet.PolicyStateInfo{policyID: info},
+	}
+	err := agentRepo.UpdateHeartbeatByIDWithChannel(context.Background(), a)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+}
+
+func newPolicyID(t *testing.T) string {
+	t.Helper()
+	id, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	return id.String()
+}
+
+func TestStartPolicyRollout(t *testing.T) {
+	svc, _, ag, _ := newRolloutService(t, 4)
+
+	cases := map[string]struct {
+		rollout  fleet.PolicyRollout
+		canaries int
+		state    string
+		err      error
+	}{
+		"start rollout with canary percent": {
+			rollout:  fleet.PolicyRollout{GroupIDs: []string{ag.ID}, CanaryPercent: 50},
+			canaries: 2,
+			state:    fleet.RolloutCanary,
+			err:      nil,
+		},
+		"start rollout with canary percent rounded up": {
+			rollout:  fleet.PolicyRollout{GroupIDs: []string{ag.ID}, CanaryPercent: 10},
+			canaries: 1,
+			state:    fleet.RolloutCanary,
+			err:      nil,
+		},
+		"start rollout with canary count": {
+			rollout:  fleet.PolicyRollout{GroupIDs: []string{ag.ID}, CanaryCount: 3},
+			canaries: 3,
+			state:    fleet.RolloutCanary,
+			err:      nil,
+		},
+		"start rollout with canary count above the number of agents": {
+			rollout:  fleet.PolicyRollout{GroupIDs: []string{ag.ID}, CanaryCount: 10},
+			canaries: 4,
+			state:    fleet.RolloutCanary,
+			err:      nil,
+		},
+		"start rollout without groups": {
+			rollout: fleet.PolicyRollout{CanaryCount: 1},
+			err:     errors.ErrMalformedEntity,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			tc.rollout.PolicyID = newPolicyID(t)
+			tc.rollout.MFOwnerID = email
+			tc.rollout.Version = 2
+			tc.rollout.PreviousVersion = 1
+			rollout, err := svc.StartPolicyRolloutInternal(context.Background(), tc.rollout)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+			if err == nil {
+				assert.Equal(t, tc.canaries, len(rollout.CanaryAgentIDs), fmt.Sprintf("%s: expected %d canaries got %d", desc, tc.canaries, len(rollout.CanaryAgentIDs)))
+				assert.Equal(t, tc.state, rollout.State, fmt.Sprintf("%s: expected state %s got %s", desc, tc.state, rollout.State))
+			}
+		})
+	}
+}
+
+func TestStartPolicyRolloutWithoutAgents(t *testing.T) {
+	svc, _, ag, _ := newRolloutService(t, 0)
+
+	rollout, err := svc.StartPolicyRolloutInternal(context.Background(), fleet.PolicyRollout{
+		PolicyID:      newPolicyID(t),
+		MFOwnerID:     email,
+		Version:       2,
+		GroupIDs:      []string{ag.ID},
+		CanaryPercent: 50,
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, fleet.RolloutPromoted, rollout.State, fmt.Sprintf("expected state %s got %s", fleet.RolloutPromoted, rollout.State))
+	assert.Empty(t, rollout.CanaryAgentIDs, "expected no canary agents")
+}
+
+func TestCheckPolicyRollouts(t *testing.T) {
+	cases := map[string]struct {
+		onFailure  string
+		timeout    time.Duration
+		report     []fleet.PolicyStateInfo
+		state      string
+		rolledBack bool
+	}{
+		"check rollout with every canary running the new version": {
+			onFailure: fleet.RolloutOnFailureRollback,
+			timeout:   time.Hour,
+			report:    []fleet.PolicyStateInfo{{State: "running", Version: 2}, {State: "running", Version: 2}},
+			state:     fleet.RolloutPromoted,
+		},
+		"check rollout with a canary still running the previous version": {
+			onFailure: fleet.RolloutOnFailureRollback,
+			timeout:   time.Hour,
+			report:    []fleet.PolicyStateInfo{{State: "running", Version: 2}, {State: "running", Version: 1}},
+			state:     fleet.RolloutCanary,
+		},
+		"check rollout with a canary failing to apply and rollback on failure": {
+			onFailure:  fleet.RolloutOnFailureRollback,
+			timeout:    time.Hour,
+			report:     []fleet.PolicyStateInfo{{State: "running", Version: 2}, {State: "failed_to_apply", Version: 2, Error: "invalid handler"}},
+			state:      fleet.RolloutRolledBack,
+			rolledBack: true,
+		},
+		"check rollout with a canary failing to apply and halt on failure": {
+			onFailure: fleet.RolloutOnFailureHalt,
+			timeout:   time.Hour,
+			report:    []fleet.PolicyStateInfo{{State: "failed_to_apply", Version: 2, Error: "invalid handler"}, {State: "running", Version: 2}},
+			state:     fleet.RolloutHalted,
+		},
+		"check rollout with canaries not reporting before the timeout": {
+			onFailure:  fleet.RolloutOnFailureRollback,
+			timeout:    time.Nanosecond,
+			report:     []fleet.PolicyStateInfo{{State: "running", Version: 1}, {State: "running", Version: 1}},
+			state:      fleet.RolloutRolledBack,
+			rolledBack: true,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			svc, agentRepo, ag, agents := newRolloutService(t, 2)
+			policyID := newPolicyID(t)
+
+			rollout, err := svc.StartPolicyRolloutInternal(context.Background(), fleet.PolicyRollout{
+				PolicyID:        policyID,
+				MFOwnerID:       email,
+				Version:         2,
+				PreviousVersion: 1,
+				GroupIDs:        []string{ag.ID},
+				CanaryCount:     2,
+				OnFailure:       tc.onFailure,
+				Timeout:         tc.timeout,
+			})
+			require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+			for i, a := range agents {
+				reportPolicyState(t, agentRepo, a, policyID, tc.report[i])
+			}
+
+			rolledBack, err := svc.CheckPolicyRolloutsInternal(context.Background())
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+			assert.Equal(t, tc.rolledBack, len(rolledBack) == 1, fmt.Sprintf("%s: expected rolled back %t got %v", desc, tc.rolledBack, rolledBack))
+
+			status, err := svc.ViewPolicyRollout(context.Background(), token, policyID)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+			assert.Equal(t, rollout.ID, status.ID, fmt.Sprintf("%s: expected rollout %s got %s", desc, rollout.ID, status.ID))
+			assert.Equal(t, tc.state, status.State, fmt.Sprintf("%s: expected state %s got %s", desc, tc.state, status.State))
+		})
+	}
+}
+
+func TestCheckPolicyRolloutsConcurrently(t *testing.T) {
+	svc, agentRepo, ag, agents := newRolloutService(t, 1)
+	policyID := newPolicyID(t)
+
+	_, err := svc.StartPolicyRolloutInternal(context.Background(), fleet.PolicyRollout{
+		PolicyID:        policyID,
+		MFOwnerID:       email,
+		Version:         2,
+		PreviousVersion: 1,
+		GroupIDs:        []string{ag.ID},
+		CanaryCount:     1,
+		OnFailure:       fleet.RolloutOnFailureRollback,
+		Timeout:         time.Hour,
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	reportPolicyState(t, agentRepo, agents[0], policyID, fleet.PolicyStateInfo{State: "failed_to_apply", Version: 2, Error: "invalid handler"})
+
+	// replicas checking at the same time must roll the failed rollout back once
+	var mu sync.Mutex
+	var rolledBack []fleet.PolicyRollout
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rollouts, err := svc.CheckPolicyRolloutsInternal(context.Background())
+			assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			mu.Lock()
+			rolledBack = append(rolledBack, rollouts...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, rolledBack, 1)
+}
+
+func TestSupersedePolicyRollouts(t *testing.T) {
+	svc, _, ag, _ := newRolloutService(t, 2)
+	policyID := newPolicyID(t)
+
+	first, err := svc.StartPolicyRolloutInternal(context.Background(), fleet.PolicyRollout{
+		PolicyID:    policyID,
+		MFOwnerID:   email,
+		Version:     2,
+		GroupIDs:    []string{ag.ID},
+		CanaryCount: 1,
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = svc.SupersedePolicyRolloutsInternal(context.Background(), email, policyID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	status, err := svc.ViewPolicyRollout(context.Background(), token, policyID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, first.ID, status.ID, fmt.Sprintf("expected rollout %s got %s", first.ID, status.ID))
+	assert.Equal(t, fleet.RolloutSuperseded, status.State, fmt.Sprintf("expected state %s got %s", fleet.RolloutSuperseded, status.State))
+
+	err = svc.SupersedePolicyRolloutsInternal(context.Background(), email, newPolicyID(t))
+	assert.Nil(t, err, fmt.Sprintf("unexpected error for a policy without rollouts: %s", err))
+}
+
+func TestViewPolicyRollout(t *testing.T) {
+	svc, agentRepo, ag, agents := newRolloutService(t, 3)
+	policyID := newPolicyID(t)
+
+	rollout, err := svc.StartPolicyRolloutInternal(context.Background(), fleet.PolicyRollout{
+		PolicyID:    policyID,
+		MFOwnerID:   email,
+		Version:     2,
+		GroupIDs:    []string{ag.ID},
+		CanaryCount: 1,
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	reportPolicyState(t, agentRepo, agents[0], policyID, fleet.PolicyStateInfo{State: "running", Version: 1})
+
+	cases := map[string]struct {
+		policyID string
+		token    string
+		canaries int
+		err      error
+	}{
+		"view rollout of a policy": {
+			policyID: policyID,
+			token:    token,
+			canaries: 1,
+			err:      nil,
+		},
+		"view rollout with wrong credentials": {
+			policyID: policyID,
+			token:    "wrong",
+			err:      fleet.ErrUnauthorizedAccess,
+		},
+		"view rollout of a policy never staged": {
+			policyID: newPolicyID(t),
+			token:    token,
+			err:      fleet.ErrRolloutNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			status, err := svc.ViewPolicyRollout(context.Background(), tc.token, tc.policyID)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+			if err != nil {
+				return
+			}
+			assert.Equal(t, rollout.ID, status.ID, fmt.Sprintf("%s: expected rollout %s got %s", desc, rollout.ID, status.ID))
+			assert.Equal(t, len(agents), len(status.Agents), fmt.Sprintf("%s: expected %d agents got %d", desc, len(agents), len(status.Agents)))
+			canaries := 0
+			for _, a := range status.Agents {
+				if a.Canary {
+					canaries++
+				}
+				if a.AgentID == agents[0].MFThingID {
+					assert.Equal(t, int32(1), a.Version, fmt.Sprintf("%s: expected version 1 got %d", desc, a.Version))
+				}
+			}
+			assert.Equal(t, tc.canaries, canaries, fmt.Sprintf("%s: expected %d canaries got %d", desc, tc.canaries, canaries))
+		})
+	}
+}
@@ -0,0 +1,80 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package fleet
+
+import (
+	"context"
+	"time"
+
+	"github.com/orb-community/orb/pkg/errors"
+)
+
+const (
+	// PolicySnapshotTimeout bounds how long fleet waits for an agent to answer a policy metrics request
+	PolicySnapshotTimeout = 10 * time.Second
+	// MaxPolicySnapshotSize bounds the size of a policy metrics answer received from an agent
+	MaxPolicySnapshotSize = 1024 * 1024
+)
+
+var (
+	// ErrAgentNotOnline indicates the agent can not be reached to answer a request
+	ErrAgentNotOnline = errors.New("agent is not online")
+	// ErrPolicySnapshot indicates the agent could not retrieve the metrics of the policy from its backend
+	ErrPolicySnapshot = errors.New("agent failed to retrieve policy metrics")
+	// ErrPolicySnapshotTimeout indicates the agent did not answer in PolicySnapshotTimeout
+	ErrPolicySnapshotTimeout = errors.New("agent did not answer the policy metrics request in time")
+)
+
+// PolicySnapshot the metrics a policy is seeing right now on an agent, as reported by its backend
+type PolicySnapshot struct {
+	AgentID    string
+	PolicyID   string
+	PolicyName string
+	Datasets   []string
+	Format     string
+	BEVersion  string
+	Data       []byte
+	Created    time.Time
+}
+
+type PolicySnapshotService interface {
+	// ViewAgentPolicySnapshot asks the agent for the current metrics of one of its running policies, waiting for its answer
+	ViewAgentPolicySnapshot(ctx context.Context, token string, thingID string, policyID string) (PolicySnapshot, error)
+}
+
+func (svc fleetService) ViewAgentPolicySnapshot(ctx context.Context, token string, thingID string, policyID string) (PolicySnapshot, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return PolicySnapshot{}, err
+	}
+
+	agent, err := svc.agentRepo.RetrieveByID(ctx, ownerID, thingID)
+	if err != nil {
+		return PolicySnapshot{}, err
+	}
+
+	if _, ok := agentPolicyState(agent, policyID); !ok {
+		return PolicySnapshot{}, errors.ErrNotFound
+	}
+	if agent.State != Online {
+		return PolicySnapshot{}, errors.Wrap(errors.ErrConflict, ErrAgentNotOnline)
+	}
+
+	p, err := svc.agentComms.RequestAgentPolicyMetrics(ctx, agent, policyID)
+	if err != nil {
+		return PolicySnapshot{}, err
+	}
+
+	return PolicySnapshot{
+		AgentID:    agent.MFThingID,
+		PolicyID:   p.PolicyID,
+		PolicyName: p.PolicyName,
+		Datasets:   p.Datasets,
+		Format:     p.Format,
+		BEVersion:  p.BEVersion,
+		Data:       p.Data,
+		Created:    time.Now(),
+	}, nil
+}
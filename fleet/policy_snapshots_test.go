@@ -0,0 +1,93 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package fleet_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/orb-community/orb/fleet"
+	flmocks "github.com/orb-community/orb/fleet/mocks"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewAgentPolicySnapshot(t *testing.T) {
+	users := flmocks.NewAuthService(map[string]string{token: email})
+	thingsServer := newThingsServer(newThingsService(users))
+	defer thingsServer.Close()
+
+	agentRepo := flmocks.NewAgentRepositoryMock()
+	fleetService := newFleetService(users, thingsServer.URL, flmocks.NewAgentGroupRepository(), agentRepo)
+
+	policyID := "a8fe4a2f-5d1d-4a3c-9c2e-5d1b5b3e3c8f"
+	online, err := createAgent(t, "snapshot-agent", fleetService)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	online.State = fleet.Online
+	online.LastHBData = types.Metadata{
+		"policy_state": map[string]fleet.PolicyStateInfo{policyID: {Name: "policy", State: "running"}},
+	}
+	err = agentRepo.UpdateHeartbeatByIDWithChannel(context.Background(), online)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	offline, err := createAgent(t, "snapshot-offline-agent", fleetService)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	offline.State = fleet.Offline
+	offline.LastHBData = online.LastHBData
+	err = agentRepo.UpdateHeartbeatByIDWithChannel(context.Background(), offline)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		id       string
+		policyID string
+		token    string
+		err      error
+	}{
+		"view snapshot of a policy running on an online agent": {
+			id:       online.MFThingID,
+			policyID: policyID,
+			token:    token,
+			err:      nil,
+		},
+		"view snapshot of a policy not running on the agent": {
+			id:       online.MFThingID,
+			policyID: "9bb1b244-a199-93c2-aa03-28067b431e2c",
+			token:    token,
+			err:      fleet.ErrNotFound,
+		},
+		"view snapshot of a policy on an offline agent": {
+			id:       offline.MFThingID,
+			policyID: policyID,
+			token:    token,
+			err:      fleet.ErrAgentNotOnline,
+		},
+		"view snapshot of non-existing agent": {
+			id:       "9bb1b244-a199-93c2-aa03-28067b431e2c",
+			policyID: policyID,
+			token:    token,
+			err:      fleet.ErrNotFound,
+		},
+		"view snapshot with wrong credentials": {
+			id:       online.MFThingID,
+			policyID: policyID,
+			token:    "wrong",
+			err:      fleet.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			snapshot, err := fleetService.ViewAgentPolicySnapshot(context.Background(), tc.token, tc.id, tc.policyID)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+			if err == nil {
+				assert.Equal(t, tc.policyID, snapshot.PolicyID, fmt.Sprintf("%s: expected policy %s got %s", desc, tc.policyID, snapshot.PolicyID))
+				assert.Equal(t, tc.id, snapshot.AgentID, fmt.Sprintf("%s: expected agent %s got %s", desc, tc.id, snapshot.AgentID))
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/orb-community/orb/fleet"
+	"github.com/orb-community/orb/pkg/db"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+)
+
+func (r agentRepository) SaveBulkJob(ctx context.Context, job fleet.BulkJob) (string, error) {
+	q := `INSERT INTO agent_bulk_jobs (mf_owner_id, mf_owner_email, operation, filter, orb_tags, tag_keys, reason, state, agent_ids)
+			VALUES (:mf_owner_id, :mf_owner_email, :operation, :filter, :orb_tags, :tag_keys, :reason, :state, :agent_ids) RETURNING id`
+
+	if job.MFOwnerID == "" || job.Operation == "" || job.State == "" {
+		return "", errors.ErrMalformedEntity
+	}
+
+	rows, err := r.db.NamedQueryContext(ctx, q, toDBBulkJob(job))
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case db.ErrInvalid, db.ErrTruncation:
+				return "", errors.Wrap(errors.ErrMalformedEntity, err)
+			}
+		}
+		return "", errors.Wrap(db.ErrSaveDB, err)
+	}
+	defer rows.Close()
+
+	var id string
+	if rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return "", errors.Wrap(db.ErrSaveDB, err)
+		}
+	}
+
+	return id, nil
+}
+
+func (r agentRepository) RetrieveBulkJob(ctx context.Context, ownerID string, id string) (fleet.BulkJob, error) {
+	q := `SELECT id, mf_owner_id, mf_owner_email, operation, filter, orb_tags, tag_keys, reason, state, agent_ids,
+			succeeded, failed, failures, ts_created, ts_last_modified
+		FROM agent_bulk_jobs WHERE id = $1 AND mf_owner_id = $2`
+
+	if ownerID == "" || id == "" {
+		return fleet.BulkJob{}, errors.ErrMalformedEntity
+	}
+
+	var dbj dbBulkJob
+	if err := r.db.QueryRowxContext(ctx, q, id, ownerID).StructScan(&dbj); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && db.ErrInvalid == pqErr.Code.Name() {
+			return fleet.BulkJob{}, errors.Wrap(fleet.ErrBulkJobNotFound, errors.Wrap(errors.ErrNotFound, err))
+		}
+		return fleet.BulkJob{}, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+
+	return toBulkJob(dbj)
+}
+
+func (r agentRepository) RecordBulkJobProgress(ctx context.Context, id string, failure *fleet.BulkAgentFailure) error {
+	q := `UPDATE agent_bulk_jobs SET succeeded = succeeded + 1, ts_last_modified = CURRENT_TIMESTAMP WHERE id = :id`
+	params := map[string]interface{}{
+		"id": id,
+	}
+	if failure != nil {
+		q = `UPDATE agent_bulk_jobs SET failed = failed + 1, failures = failures || CAST(:failure AS JSONB),
+				ts_last_modified = CURRENT_TIMESTAMP WHERE id = :id`
+		b, err := json.Marshal([]fleet.BulkAgentFailure{*failure})
+		if err != nil {
+			return errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		params["failure"] = string(b)
+	}
+
+	return r.updateBulkJob(ctx, q, params)
+}
+
+func (r agentRepository) UpdateBulkJobState(ctx context.Context, id string, state string) error {
+	q := `UPDATE agent_bulk_jobs SET state = :state, ts_last_modified = CURRENT_TIMESTAMP WHERE id = :id`
+
+	if state == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	params := map[string]interface{}{
+		"id":    id,
+		"state": state,
+	}
+
+	return r.updateBulkJob(ctx, q, params)
+}
+
+func (r agentRepository) ClaimBulkJob(ctx context.Context) (fleet.BulkJob, error) {
+	// SKIP LOCKED lets concurrent replicas each claim a different job
+	q := `UPDATE agent_bulk_jobs SET claimed = true, ts_last_modified = CURRENT_TIMESTAMP
+		WHERE id = (SELECT id FROM agent_bulk_jobs WHERE state = $1 AND NOT claimed
+			ORDER BY ts_created LIMIT 1 FOR UPDATE SKIP LOCKED)
+		RETURNING id, mf_owner_id, mf_owner_email, operation, filter, orb_tags, tag_keys, reason, state, agent_ids,
+			succeeded, failed, failures, ts_created, ts_last_modified`
+
+	var dbj dbBulkJob
+	if err := r.db.QueryRowxContext(ctx, q, fleet.BulkJobRunning).StructScan(&dbj); err != nil {
+		if err == sql.ErrNoRows {
+			return fleet.BulkJob{}, fleet.ErrBulkJobNotFound
+		}
+		return fleet.BulkJob{}, errors.Wrap(db.ErrUpdateDB, err)
+	}
+
+	return toBulkJob(dbj)
+}
+
+func (r agentRepository) FailStaleBulkJobs(ctx context.Context, before time.Time) (uint64, error) {
+	q := `UPDATE agent_bulk_jobs SET state = :failed, ts_last_modified = CURRENT_TIMESTAMP
+		WHERE state = :running AND claimed AND ts_last_modified < :before`
+
+	params := map[string]interface{}{
+		"failed":  fleet.BulkJobFailed,
+		"running": fleet.BulkJobRunning,
+		"before":  before,
+	}
+
+	res, err := r.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		return 0, errors.Wrap(db.ErrUpdateDB, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	return uint64(cnt), nil
+}
+
+func (r agentRepository) updateBulkJob(ctx context.Context, q string, params map[string]interface{}) error {
+	if params["id"] == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	res, err := r.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		return errors.Wrap(db.ErrUpdateDB, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	if cnt == 0 {
+		return fleet.ErrBulkJobNotFound
+	}
+
+	return nil
+}
+
+type dbBulkJob struct {
+	ID           string         `db:"id"`
+	MFOwnerID    string         `db:"mf_owner_id"`
+	MFOwnerEmail string         `db:"mf_owner_email"`
+	Operation    string         `db:"operation"`
+	Filter       db.Tags        `db:"filter"`
+	OrbTags      db.Tags        `db:"orb_tags"`
+	TagKeys      pq.StringArray `db:"tag_keys"`
+	Reason       string         `db:"reason"`
+	State        string         `db:"state"`
+	AgentIDs     pq.StringArray `db:"agent_ids"`
+	Succeeded    int64          `db:"succeeded"`
+	Failed       int64          `db:"failed"`
+	Failures     []byte         `db:"failures"`
+	Created      time.Time      `db:"ts_created"`
+	LastModified time.Time      `db:"ts_last_modified"`
+}
+
+func toDBBulkJob(job fleet.BulkJob) dbBulkJob {
+	// nil maps and arrays are stored as NULL
+	filter := db.Tags{}
+	if job.Filter != nil {
+		filter = db.Tags(job.Filter)
+	}
+	orbTags := db.Tags{}
+	if job.OrbTags != nil {
+		orbTags = db.Tags(job.OrbTags)
+	}
+	tagKeys := pq.StringArray{}
+	if job.TagKeys != nil {
+		tagKeys = job.TagKeys
+	}
+	agentIDs := pq.StringArray{}
+	if job.AgentIDs != nil {
+		agentIDs = job.AgentIDs
+	}
+
+	return dbBulkJob{
+		ID:           job.ID,
+		MFOwnerID:    job.MFOwnerID,
+		MFOwnerEmail: job.MFOwnerEmail,
+		Operation:    job.Operation,
+		Filter:       filter,
+		OrbTags:      orbTags,
+		TagKeys:      tagKeys,
+		Reason:       job.Reason,
+		State:        job.State,
+		AgentIDs:     agentIDs,
+	}
+}
+
+func toBulkJob(dbj dbBulkJob) (fleet.BulkJob, error) {
+	var failures []fleet.BulkAgentFailure
+	if len(dbj.Failures) > 0 {
+		if err := json.Unmarshal(dbj.Failures, &failures); err != nil {
+			return fleet.BulkJob{}, errors.Wrap(errors.ErrSelectEntity, err)
+		}
+	}
+
+	return fleet.BulkJob{
+		ID:           dbj.ID,
+		MFOwnerID:    dbj.MFOwnerID,
+		MFOwnerEmail: dbj.MFOwnerEmail,
+		Operation:    dbj.Operation,
+		Filter:       types.Tags(dbj.Filter),
+		OrbTags:      types.Tags(dbj.OrbTags),
+		TagKeys:      dbj.TagKeys,
+		Reason:       dbj.Reason,
+		State:        dbj.State,
+		AgentIDs:     dbj.AgentIDs,
+		Succeeded:    uint64(dbj.Succeeded),
+		Failed:       uint64(dbj.Failed),
+		Failures:     failures,
+		Created:      dbj.Created,
+		LastModified: dbj.LastModified,
+	}, nil
+}
@@ -11,6 +11,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"github.com/lib/pq"
@@ -63,6 +64,7 @@ func (a agentGroupRepository) RetrieveAllAgentGroupsByOwner(ctx context.Context,
 			mf_owner_id,
 			mf_channel_id,
 			tags,
+			selector,
 			ts_created,
 			json_build_object('total', total, 'online', online) AS matching_agents
 		from
@@ -73,6 +75,7 @@ func (a agentGroupRepository) RetrieveAllAgentGroupsByOwner(ctx context.Context,
 				ag.mf_owner_id,
 				ag.mf_channel_id,
 				ag.tags,
+				ag.selector,
 				ag.ts_created,
 				sum(case when agm.agent_groups_id is not null then 1 else 0 end) as total,
 				sum(case when agm.agent_state = 'online' then 1 else 0 end) as online
@@ -87,6 +90,7 @@ func (a agentGroupRepository) RetrieveAllAgentGroupsByOwner(ctx context.Context,
 					ag.mf_owner_id,
 					ag.mf_channel_id,
 					ag.tags,
+					ag.selector,
 					ag.ts_created)
 			as agent_groups ORDER BY %s %s LIMIT :limit OFFSET :offset;`, nameQuery, tagsQuery, metadataQuery, orderQuery, dirQuery)
 
@@ -130,6 +134,7 @@ func (a agentGroupRepository) RetrieveAllAgentGroupsByOwner(ctx context.Context,
 			ag.mf_owner_id,
 			ag.mf_channel_id,
 			ag.tags,
+			ag.selector,
 			ag.ts_created,
 			sum(case when agm.agent_groups_id is not null then 1 else 0 end) as total,
 			sum(case when agm.agent_state = 'online' then 1 else 0 end) as online
@@ -144,6 +149,7 @@ func (a agentGroupRepository) RetrieveAllAgentGroupsByOwner(ctx context.Context,
 			ag.mf_owner_id,
 			ag.mf_channel_id,
 			ag.tags,
+			ag.selector,
 			ag.ts_created) 
 		as agent_groups;`, nameQuery, tagsQuery, metadataQuery)
 
@@ -175,6 +181,7 @@ func (a agentGroupRepository) RetrieveByID(ctx context.Context, groupID string,
 		mf_owner_id,
 		mf_channel_id,
 		tags,
+		selector,
 		ts_created,
 		json_build_object('total', total, 'online', online) AS matching_agents
 	from
@@ -185,6 +192,7 @@ func (a agentGroupRepository) RetrieveByID(ctx context.Context, groupID string,
 		ag.mf_owner_id,
 		ag.mf_channel_id,
 		ag.tags,
+		ag.selector,
 		ag.ts_created,
 		sum(case when agm.agent_groups_id is not null then 1 else 0 end) as total,
 		sum(case when agm.agent_state = 'online' then 1 else 0 end) as online
@@ -199,6 +207,7 @@ func (a agentGroupRepository) RetrieveByID(ctx context.Context, groupID string,
 		ag.mf_owner_id,
 		ag.mf_channel_id,
 		ag.tags,
+		ag.selector,
 		ag.ts_created) as agent_groups`
 
 	if groupID == "" || ownerID == "" {
@@ -217,7 +226,7 @@ func (a agentGroupRepository) RetrieveByID(ctx context.Context, groupID string,
 }
 
 func (a agentGroupRepository) Update(ctx context.Context, ownerID string, group fleet.AgentGroup) (fleet.AgentGroup, error) {
-	q := `UPDATE agent_groups SET name = :name, description = :description, tags = :tags WHERE mf_owner_id = :mf_owner_id AND id = :id;`
+	q := `UPDATE agent_groups SET name = :name, description = :description, tags = :tags, selector = :selector WHERE mf_owner_id = :mf_owner_id AND id = :id;`
 	groupDB, err := toDBAgentGroup(group)
 	if err != nil {
 		return fleet.AgentGroup{}, errors.Wrap(fleet.ErrUpdateEntity, err)
@@ -297,8 +306,8 @@ func (a agentGroupRepository) Save(ctx context.Context, group fleet.AgentGroup)
 	if err != nil {
 		return "", err
 	}
-	q := `INSERT INTO agent_groups (name, description, mf_owner_id, mf_channel_id, tags)         
-			  VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	q := `INSERT INTO agent_groups (name, description, mf_owner_id, mf_channel_id, tags, selector)         
+			  VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
 
 	if !group.Name.IsValid() || group.MFOwnerID == "" || group.MFChannelID == "" {
 		return "", errors.ErrMalformedEntity
@@ -309,7 +318,7 @@ func (a agentGroupRepository) Save(ctx context.Context, group fleet.AgentGroup)
 		return "", errors.Wrap(db.ErrSaveDB, err)
 	}
 
-	row, err := tx.QueryContext(ctx, q, dba.Name, dba.Description, dba.MFOwnerID, dba.MFChannelID, dba.Tags)
+	row, err := tx.QueryContext(ctx, q, dba.Name, dba.Description, dba.MFOwnerID, dba.MFChannelID, dba.Tags, dba.Selector)
 	if err != nil {
 		tx.Rollback()
 		pqErr, ok := err.(*pq.Error)
@@ -374,6 +383,7 @@ type dbAgentGroup struct {
 	MFOwnerID      string           `db:"mf_owner_id"`
 	MFChannelID    string           `db:"mf_channel_id"`
 	Tags           db.Tags          `db:"tags"`
+	Selector       dbTagSelector    `db:"selector"`
 	Created        time.Time        `db:"ts_created"`
 	MatchingAgents db.Metadata      `db:"matching_agents"`
 }
@@ -404,6 +414,7 @@ func toDBAgentGroup(group fleet.AgentGroup) (dbAgentGroup, error) {
 		MFOwnerID:   group.MFOwnerID,
 		MFChannelID: group.MFChannelID,
 		Tags:        groupTags,
+		Selector:    dbTagSelector(group.Selector),
 	}, nil
 
 }
@@ -422,11 +433,40 @@ func toAgentGroup(dba dbAgentGroup) (fleet.AgentGroup, error) {
 		MFChannelID:    dba.MFChannelID,
 		Created:        dba.Created,
 		Tags:           &groupTags,
+		Selector:       fleet.TagSelector(dba.Selector),
 		MatchingAgents: types.Metadata(dba.MatchingAgents),
 	}, nil
 
 }
 
+// dbTagSelector stores the group tag selector as a JSONB array
+type dbTagSelector fleet.TagSelector
+
+func (s *dbTagSelector) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	b, ok := value.([]byte)
+	if !ok {
+		return db.ErrScanMetadata
+	}
+
+	return json.Unmarshal(b, s)
+}
+
+func (s dbTagSelector) Value() (driver.Value, error) {
+	if len(s) == 0 {
+		return "[]", nil
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
 func getAgentGroupOrderQuery(order string) string {
 	switch order {
 	case "name":
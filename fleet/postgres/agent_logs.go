@@ -0,0 +1,132 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/orb-community/orb/fleet"
+	"github.com/orb-community/orb/pkg/db"
+	"github.com/orb-community/orb/pkg/errors"
+)
+
+func (r agentRepository) SaveAgentLogs(ctx context.Context, ownerID string, thingID string, entries []fleet.AgentLogEntry) error {
+	q := `INSERT INTO agent_logs (mf_thing_id, mf_owner_id, ts_created, level, source, message, fields)
+			VALUES (:mf_thing_id, :mf_owner_id, :ts_created, :level, :source, :message, :fields)`
+	// keep the buffer bounded, discarding entries older than the MaxAgentLogEntries latest ones
+	dq := `DELETE FROM agent_logs WHERE mf_owner_id = $1 AND mf_thing_id = $2 AND id <= (
+			SELECT id FROM agent_logs WHERE mf_owner_id = $1 AND mf_thing_id = $2 ORDER BY id DESC OFFSET $3 LIMIT 1)`
+
+	if ownerID == "" || thingID == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(db.ErrSaveDB, err)
+	}
+
+	for _, e := range entries {
+		dbl := toDBAgentLogEntry(ownerID, thingID, e)
+		if _, err := tx.NamedExecContext(ctx, q, dbl); err != nil {
+			tx.Rollback()
+			pqErr, ok := err.(*pq.Error)
+			if ok {
+				switch pqErr.Code.Name() {
+				case db.ErrInvalid, db.ErrTruncation:
+					return errors.Wrap(errors.ErrMalformedEntity, err)
+				}
+			}
+			return errors.Wrap(db.ErrSaveDB, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, dq, ownerID, thingID, fleet.MaxAgentLogEntries); err != nil {
+		tx.Rollback()
+		return errors.Wrap(db.ErrSaveDB, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(db.ErrSaveDB, err)
+	}
+
+	return nil
+}
+
+func (r agentRepository) RetrieveAgentLogs(ctx context.Context, ownerID string, thingID string, since time.Time, levels []string, limit uint64) ([]fleet.AgentLogEntry, error) {
+	q := `SELECT id, mf_thing_id, mf_owner_id, ts_created, level, source, message, fields FROM (
+				SELECT id, mf_thing_id, mf_owner_id, ts_created, level, source, message, fields
+				FROM agent_logs
+				WHERE mf_owner_id = :mf_owner_id AND mf_thing_id = :mf_thing_id AND ts_created >= :since AND level = ANY(:levels)
+				ORDER BY id DESC LIMIT :limit) AS latest
+			ORDER BY id`
+
+	if ownerID == "" || thingID == "" {
+		return nil, errors.ErrMalformedEntity
+	}
+	if limit == 0 || limit > fleet.MaxAgentLogEntries {
+		limit = fleet.MaxAgentLogEntries
+	}
+
+	params := map[string]interface{}{
+		"mf_owner_id": ownerID,
+		"mf_thing_id": thingID,
+		"since":       since,
+		"levels":      pq.Array(levels),
+		"limit":       limit,
+	}
+
+	rows, err := r.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+	defer rows.Close()
+
+	var items []fleet.AgentLogEntry
+	for rows.Next() {
+		var dbl dbAgentLogEntry
+		if err := rows.StructScan(&dbl); err != nil {
+			return nil, errors.Wrap(errors.ErrSelectEntity, err)
+		}
+		items = append(items, toAgentLogEntry(dbl))
+	}
+
+	return items, nil
+}
+
+type dbAgentLogEntry struct {
+	ID        int64       `db:"id"`
+	MFThingID string      `db:"mf_thing_id"`
+	MFOwnerID string      `db:"mf_owner_id"`
+	Created   time.Time   `db:"ts_created"`
+	Level     string      `db:"level"`
+	Source    string      `db:"source"`
+	Message   string      `db:"message"`
+	Fields    db.Metadata `db:"fields"`
+}
+
+func toDBAgentLogEntry(ownerID string, thingID string, e fleet.AgentLogEntry) dbAgentLogEntry {
+	return dbAgentLogEntry{
+		MFThingID: thingID,
+		MFOwnerID: ownerID,
+		Created:   e.TimeStamp,
+		Level:     e.Level,
+		Source:    e.Source,
+		Message:   e.Message,
+		Fields:    db.Metadata(e.Fields),
+	}
+}
+
+func toAgentLogEntry(dbl dbAgentLogEntry) fleet.AgentLogEntry {
+	return fleet.AgentLogEntry{
+		TimeStamp: dbl.Created,
+		Level:     dbl.Level,
+		Source:    dbl.Source,
+		Message:   dbl.Message,
+		Fields:    dbl.Fields,
+	}
+}
@@ -0,0 +1,105 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/orb-community/orb/fleet"
+	"github.com/orb-community/orb/pkg/errors"
+)
+
+// agent_state_events rows are written by the record_agent_state_event trigger of the agents table
+
+func (r agentRepository) RetrieveStateEvents(ctx context.Context, ownerID string, thingID string, from time.Time, to time.Time, limit uint64) ([]fleet.AgentStateEvent, error) {
+	q := `SELECT id, mf_thing_id, mf_owner_id, state, previous_state, backend, reason, ts_created
+			FROM agent_state_events
+			WHERE mf_owner_id = :mf_owner_id AND mf_thing_id = :mf_thing_id AND ts_created >= :from AND ts_created <= :to
+			ORDER BY ts_created DESC, id DESC`
+
+	if ownerID == "" || thingID == "" {
+		return nil, errors.ErrMalformedEntity
+	}
+
+	params := map[string]interface{}{
+		"mf_owner_id": ownerID,
+		"mf_thing_id": thingID,
+		"from":        from,
+		"to":          to,
+	}
+	if limit > 0 {
+		q = q + ` LIMIT :limit`
+		params["limit"] = limit
+	}
+
+	rows, err := r.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+	defer rows.Close()
+
+	var items []fleet.AgentStateEvent
+	for rows.Next() {
+		var dbe dbAgentStateEvent
+		if err := rows.StructScan(&dbe); err != nil {
+			return nil, errors.Wrap(errors.ErrSelectEntity, err)
+		}
+		items = append(items, toAgentStateEvent(dbe))
+	}
+
+	// the latest events are selected so the limit keeps the end of the window, they are returned oldest first
+	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+		items[i], items[j] = items[j], items[i]
+	}
+
+	return items, nil
+}
+
+func (r agentRepository) RetrieveLastStateEvent(ctx context.Context, ownerID string, thingID string, at time.Time) (fleet.AgentStateEvent, error) {
+	q := `SELECT id, mf_thing_id, mf_owner_id, state, previous_state, backend, reason, ts_created
+			FROM agent_state_events
+			WHERE mf_owner_id = $1 AND mf_thing_id = $2 AND backend = '' AND ts_created <= $3
+			ORDER BY ts_created DESC, id DESC LIMIT 1`
+
+	if ownerID == "" || thingID == "" {
+		return fleet.AgentStateEvent{}, errors.ErrMalformedEntity
+	}
+
+	var dbe dbAgentStateEvent
+	if err := r.db.QueryRowxContext(ctx, q, ownerID, thingID, at).StructScan(&dbe); err != nil {
+		if err == sql.ErrNoRows {
+			return fleet.AgentStateEvent{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return fleet.AgentStateEvent{}, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+
+	return toAgentStateEvent(dbe), nil
+}
+
+type dbAgentStateEvent struct {
+	ID            int64     `db:"id"`
+	MFThingID     string    `db:"mf_thing_id"`
+	MFOwnerID     string    `db:"mf_owner_id"`
+	State         string    `db:"state"`
+	PreviousState string    `db:"previous_state"`
+	Backend       string    `db:"backend"`
+	Reason        string    `db:"reason"`
+	Created       time.Time `db:"ts_created"`
+}
+
+func toAgentStateEvent(dbe dbAgentStateEvent) fleet.AgentStateEvent {
+	return fleet.AgentStateEvent{
+		ID:            dbe.ID,
+		MFThingID:     dbe.MFThingID,
+		MFOwnerID:     dbe.MFOwnerID,
+		State:         dbe.State,
+		PreviousState: dbe.PreviousState,
+		Backend:       dbe.Backend,
+		Reason:        dbe.Reason,
+		Created:       dbe.Created,
+	}
+}
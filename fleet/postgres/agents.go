@@ -30,7 +30,7 @@ type agentRepository struct {
 	logger *zap.Logger
 }
 
-func (r agentRepository) RetrieveMatchingAgents(ctx context.Context, ownerID string, tags types.Tags) (types.Metadata, error) {
+func (r agentRepository) RetrieveMatchingAgents(ctx context.Context, ownerID string, tags types.Tags, selector fleet.TagSelector) (types.Metadata, error) {
 	t, tmq, err := getTagsQuery(tags)
 	if err != nil {
 		return types.Metadata{}, errors.Wrap(errors.ErrSelectEntity, err)
@@ -47,10 +47,11 @@ func (r agentRepository) RetrieveMatchingAgents(ctx context.Context, ownerID str
 				sum(case when state = 'online' then 1 else 0 end) as online
 			from agents where mf_owner_id = :mf_owner_id
 			group by mf_owner_id, coalesce(agent_tags || orb_tags, agent_tags, orb_tags)) agent_groups
-		WHERE 1=1 %s`, tmq)
+		WHERE agent_tags_match_selector(tags, :selector) %s`, tmq)
 
 	params := map[string]interface{}{
 		"tags":        t,
+		"selector":    dbTagSelector(selector),
 		"mf_owner_id": ownerID,
 	}
 
@@ -481,34 +482,52 @@ func (r agentRepository) RetrieveAgentInfoByChannelID(ctx context.Context, chann
 	return toAgent(ownerScan)
 }
 
-func (r agentRepository) SetStaleStatus(ctx context.Context, duration time.Duration) (int64, error) {
+func (r agentRepository) SetStaleStatus(ctx context.Context, duration time.Duration) ([]fleet.Agent, error) {
 
-	q := `UPDATE agents SET state = :state WHERE state <> 'stale' AND state <> 'offline' AND ts_last_hb <= now() - :duration * interval '1 seconds';`
+	// the timeout of each agent is the strictest one of its groups, or its owner one, falling back to the provided duration
+	q := `UPDATE agents SET state = :state WHERE state <> 'stale' AND state <> 'offline' AND ts_last_hb <= now() - coalesce(
+				(SELECT min(hc.stale_timeout) FROM heartbeat_configs hc
+					JOIN agent_group_membership agm ON agm.agent_groups_id = hc.agent_group_id
+					WHERE agm.agent_mf_thing_id = agents.mf_thing_id),
+				(SELECT hc.stale_timeout FROM heartbeat_configs hc
+					WHERE hc.mf_owner_id = agents.mf_owner_id AND hc.agent_group_id IS NULL),
+				CAST(:duration AS float8)) * interval '1 seconds'
+			RETURNING mf_thing_id, mf_owner_id, name;`
 
 	params := map[string]interface{}{
 		"duration": duration.Seconds(),
 		"state":    fleet.Stale,
 	}
-	res, err := r.db.NamedExecContext(ctx, q, params)
+	rows, err := r.db.NamedQueryContext(ctx, q, params)
 	if err != nil {
 		pqErr, ok := err.(*pq.Error)
 		if ok {
 			switch pqErr.Code.Name() {
 			case db.ErrInvalid, db.ErrTruncation:
-				return 0, errors.Wrap(errors.ErrMalformedEntity, err)
+				return nil, errors.Wrap(errors.ErrMalformedEntity, err)
 			case db.ErrDuplicate:
-				return 0, errors.Wrap(errors.ErrConflict, err)
+				return nil, errors.Wrap(errors.ErrConflict, err)
 			}
 		}
-		return 0, errors.Wrap(db.ErrUpdateDB, err)
+		return nil, errors.Wrap(db.ErrUpdateDB, err)
 	}
+	defer rows.Close()
 
-	cnt, errdb := res.RowsAffected()
-	if errdb != nil {
-		return 0, errors.Wrap(errors.ErrUpdateEntity, errdb)
+	var stale []fleet.Agent
+	for rows.Next() {
+		dbth := dbAgent{}
+		if err := rows.StructScan(&dbth); err != nil {
+			return nil, errors.Wrap(errors.ErrUpdateEntity, err)
+		}
+		stale = append(stale, fleet.Agent{
+			Name:      dbth.Name,
+			MFOwnerID: dbth.MFOwnerID,
+			MFThingID: dbth.MFThingID,
+			State:     fleet.Stale,
+		})
 	}
 
-	return cnt, nil
+	return stale, nil
 }
 
 type dbAgent struct {
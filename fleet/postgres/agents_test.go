@@ -1098,6 +1098,7 @@ func TestMatchingAgentRetrieval(t *testing.T) {
 	cases := map[string]struct {
 		owner          string
 		tag            types.Tags
+		selector       fleet.TagSelector
 		matchingAgents types.Metadata
 	}{
 		"retrieve matching agents with mix tags": {
@@ -1146,11 +1147,45 @@ func TestMatchingAgentRetrieval(t *testing.T) {
 				"online": nil,
 			},
 		},
+		"retrieve matching agents with tags and selector": {
+			owner: oID.String(),
+			tag:   orbTags,
+			selector: fleet.TagSelector{
+				{Key: "region", Operator: fleet.SelectorIn, Values: []string{"EU", "US"}},
+				{Key: "decommissioned", Operator: fleet.SelectorNotExists},
+			},
+			matchingAgents: types.Metadata{
+				"total":  float64(n),
+				"online": float64(0),
+			},
+		},
+		"retrieve matching agents with selector only": {
+			owner: oID.String(),
+			selector: fleet.TagSelector{
+				{Key: "node_type", Operator: fleet.SelectorPrefix, Values: []string{"dn"}},
+				{Key: "region", Operator: fleet.SelectorRegex, Values: []string{"^E[A-Z]$"}},
+			},
+			matchingAgents: types.Metadata{
+				"total":  float64(n),
+				"online": float64(0),
+			},
+		},
+		"retrieve unmatched agents with selector": {
+			owner: oID.String(),
+			tag:   orbTags,
+			selector: fleet.TagSelector{
+				{Key: "region", Operator: fleet.SelectorNotIn, Values: []string{"EU"}},
+			},
+			matchingAgents: types.Metadata{
+				"total":  nil,
+				"online": nil,
+			},
+		},
 	}
 
 	for desc, tc := range cases {
 		t.Run(desc, func(t *testing.T) {
-			ma, err := agentRepo.RetrieveMatchingAgents(context.Background(), tc.owner, tc.tag)
+			ma, err := agentRepo.RetrieveMatchingAgents(context.Background(), tc.owner, tc.tag, tc.selector)
 			assert.True(t, reflect.DeepEqual(tc.matchingAgents, ma), fmt.Sprintf("%s: expected %v got %v\n", desc, tc.matchingAgents, ma))
 			assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %d\n", desc, err))
 		})
@@ -1225,3 +1260,65 @@ func TestSetAgentStale(t *testing.T) {
 	}
 
 }
+
+func TestRetrieveStateEvents(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	agentRepo := postgres.NewAgentRepository(dbMiddleware, logger)
+
+	thID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	chID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	oID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	nameID, err := types.NewIdentifier("myagent")
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	agent := fleet.Agent{
+		Name:        nameID,
+		MFThingID:   thID.String(),
+		MFOwnerID:   oID.String(),
+		MFChannelID: chID.String(),
+		LastHBData:  types.Metadata{},
+	}
+
+	err = agentRepo.Save(context.Background(), agent)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	for _, state := range []fleet.State{fleet.Online, fleet.Offline, fleet.Online} {
+		agent.State = state
+		err = agentRepo.UpdateHeartbeatByIDWithChannel(context.Background(), agent)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	from := time.Now().Add(-time.Hour)
+	to := time.Now().Add(time.Hour)
+	cases := map[string]struct {
+		limit  uint64
+		states []string
+	}{
+		"retrieve all the events of the window": {
+			limit:  0,
+			states: []string{"new", "online", "offline", "online"},
+		},
+		"retrieve the latest events of a window holding more than the limit": {
+			limit:  2,
+			states: []string{"offline", "online"},
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			events, err := agentRepo.RetrieveStateEvents(context.Background(), oID.String(), thID.String(), from, to, tc.limit)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", desc, err))
+			var states []string
+			for _, e := range events {
+				states = append(states, e.State)
+			}
+			assert.Equal(t, tc.states, states, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.states, states))
+		})
+	}
+}
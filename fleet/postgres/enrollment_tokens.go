@@ -0,0 +1,253 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/orb-community/orb/fleet"
+	"github.com/orb-community/orb/pkg/db"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+)
+
+const enrollmentTokenColumns = `id, name, mf_owner_id, mf_owner_email, token_hash, max_uses, uses, ts_expires, orb_tags, name_pattern, revoked, ts_created`
+
+func (r agentRepository) SaveEnrollmentToken(ctx context.Context, et fleet.EnrollmentToken) (string, error) {
+	q := `INSERT INTO enrollment_tokens (name, mf_owner_id, mf_owner_email, token_hash, max_uses, ts_expires, orb_tags, name_pattern)
+			VALUES (:name, :mf_owner_id, :mf_owner_email, :token_hash, :max_uses, :ts_expires, :orb_tags, :name_pattern) RETURNING id`
+
+	if !et.Name.IsValid() || et.MFOwnerID == "" || et.TokenHash == "" {
+		return "", errors.ErrMalformedEntity
+	}
+
+	rows, err := r.db.NamedQueryContext(ctx, q, toDBEnrollmentToken(et))
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case db.ErrInvalid, db.ErrTruncation:
+				return "", errors.Wrap(errors.ErrMalformedEntity, err)
+			case db.ErrDuplicate:
+				return "", errors.Wrap(errors.ErrConflict, err)
+			}
+		}
+		return "", errors.Wrap(db.ErrSaveDB, err)
+	}
+	defer rows.Close()
+
+	var id string
+	if rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return "", errors.Wrap(db.ErrSaveDB, err)
+		}
+	}
+
+	return id, nil
+}
+
+func (r agentRepository) RetrieveEnrollmentTokens(ctx context.Context, ownerID string) ([]fleet.EnrollmentToken, error) {
+	q := `SELECT ` + enrollmentTokenColumns + ` FROM enrollment_tokens WHERE mf_owner_id = :mf_owner_id ORDER BY ts_created`
+
+	if ownerID == "" {
+		return nil, errors.ErrMalformedEntity
+	}
+
+	params := map[string]interface{}{
+		"mf_owner_id": ownerID,
+	}
+
+	rows, err := r.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+	defer rows.Close()
+
+	var items []fleet.EnrollmentToken
+	for rows.Next() {
+		var dbt dbEnrollmentToken
+		if err := rows.StructScan(&dbt); err != nil {
+			return nil, errors.Wrap(errors.ErrSelectEntity, err)
+		}
+		items = append(items, toEnrollmentToken(dbt))
+	}
+
+	return items, nil
+}
+
+func (r agentRepository) RetrieveEnrollmentTokenByID(ctx context.Context, ownerID string, id string) (fleet.EnrollmentToken, error) {
+	q := `SELECT ` + enrollmentTokenColumns + ` FROM enrollment_tokens WHERE id = $1 AND mf_owner_id = $2`
+
+	if ownerID == "" || id == "" {
+		return fleet.EnrollmentToken{}, errors.ErrMalformedEntity
+	}
+
+	return r.retrieveEnrollmentToken(ctx, q, id, ownerID)
+}
+
+func (r agentRepository) RetrieveEnrollmentTokenByHash(ctx context.Context, tokenHash string) (fleet.EnrollmentToken, error) {
+	q := `SELECT ` + enrollmentTokenColumns + ` FROM enrollment_tokens WHERE token_hash = $1`
+
+	if tokenHash == "" {
+		return fleet.EnrollmentToken{}, errors.ErrMalformedEntity
+	}
+
+	return r.retrieveEnrollmentToken(ctx, q, tokenHash)
+}
+
+func (r agentRepository) retrieveEnrollmentToken(ctx context.Context, q string, args ...interface{}) (fleet.EnrollmentToken, error) {
+	var dbt dbEnrollmentToken
+	if err := r.db.QueryRowxContext(ctx, q, args...).StructScan(&dbt); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && db.ErrInvalid == pqErr.Code.Name() {
+			return fleet.EnrollmentToken{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return fleet.EnrollmentToken{}, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+	return toEnrollmentToken(dbt), nil
+}
+
+func (r agentRepository) RevokeEnrollmentToken(ctx context.Context, ownerID string, id string) error {
+	q := `UPDATE enrollment_tokens SET revoked = TRUE WHERE id = :id AND mf_owner_id = :mf_owner_id`
+
+	if ownerID == "" || id == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	params := map[string]interface{}{
+		"id":          id,
+		"mf_owner_id": ownerID,
+	}
+
+	res, err := r.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && db.ErrInvalid == pqErr.Code.Name() {
+			return errors.Wrap(errors.ErrNotFound, err)
+		}
+		return errors.Wrap(db.ErrUpdateDB, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	if cnt == 0 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r agentRepository) ConsumeEnrollmentToken(ctx context.Context, id string) error {
+	// the conditions are checked again in the update so concurrent enrollments can not go over max_uses
+	q := `UPDATE enrollment_tokens SET uses = uses + 1
+			WHERE id = :id AND NOT revoked AND ts_expires > now() AND uses < max_uses`
+
+	if id == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	params := map[string]interface{}{
+		"id": id,
+	}
+
+	res, err := r.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		return errors.Wrap(db.ErrUpdateDB, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	if cnt == 0 {
+		return fleet.ErrEnrollmentTokenExhausted
+	}
+
+	return nil
+}
+
+func (r agentRepository) RefundEnrollmentToken(ctx context.Context, id string) error {
+	q := `UPDATE enrollment_tokens SET uses = uses - 1 WHERE id = :id AND uses > 0`
+
+	if id == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	params := map[string]interface{}{
+		"id": id,
+	}
+
+	res, err := r.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		return errors.Wrap(db.ErrUpdateDB, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	if cnt == 0 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+type dbEnrollmentToken struct {
+	ID           string           `db:"id"`
+	Name         types.Identifier `db:"name"`
+	MFOwnerID    string           `db:"mf_owner_id"`
+	MFOwnerEmail string           `db:"mf_owner_email"`
+	TokenHash    string           `db:"token_hash"`
+	MaxUses      int64            `db:"max_uses"`
+	Uses         int64            `db:"uses"`
+	ExpiresAt    time.Time        `db:"ts_expires"`
+	OrbTags      db.Tags          `db:"orb_tags"`
+	NamePattern  string           `db:"name_pattern"`
+	Revoked      bool             `db:"revoked"`
+	Created      time.Time        `db:"ts_created"`
+}
+
+func toDBEnrollmentToken(et fleet.EnrollmentToken) dbEnrollmentToken {
+	orbTags := db.Tags{}
+	if et.OrbTags != nil {
+		orbTags = db.Tags(et.OrbTags)
+	}
+	return dbEnrollmentToken{
+		ID:           et.ID,
+		Name:         et.Name,
+		MFOwnerID:    et.MFOwnerID,
+		MFOwnerEmail: et.MFOwnerEmail,
+		TokenHash:    et.TokenHash,
+		MaxUses:      int64(et.MaxUses),
+		Uses:         int64(et.Uses),
+		ExpiresAt:    et.ExpiresAt,
+		OrbTags:      orbTags,
+		NamePattern:  et.NamePattern,
+		Revoked:      et.Revoked,
+	}
+}
+
+func toEnrollmentToken(dbt dbEnrollmentToken) fleet.EnrollmentToken {
+	return fleet.EnrollmentToken{
+		ID:           dbt.ID,
+		Name:         dbt.Name,
+		MFOwnerID:    dbt.MFOwnerID,
+		MFOwnerEmail: dbt.MFOwnerEmail,
+		TokenHash:    dbt.TokenHash,
+		MaxUses:      uint64(dbt.MaxUses),
+		Uses:         uint64(dbt.Uses),
+		ExpiresAt:    dbt.ExpiresAt,
+		OrbTags:      types.Tags(dbt.OrbTags),
+		NamePattern:  dbt.NamePattern,
+		Revoked:      dbt.Revoked,
+		Created:      dbt.Created,
+	}
+}
@@ -0,0 +1,124 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/orb-community/orb/fleet"
+	"github.com/orb-community/orb/pkg/db"
+	"github.com/orb-community/orb/pkg/errors"
+)
+
+func (r agentRepository) SaveHeartbeatConfig(ctx context.Context, cfg fleet.HeartbeatConfig) error {
+	// owner configurations have no group, each kind is unique by its own partial index
+	q := `INSERT INTO heartbeat_configs (mf_owner_id, agent_group_id, heartbeat_interval, stale_timeout)
+			VALUES (:mf_owner_id, :agent_group_id, :heartbeat_interval, :stale_timeout)
+			ON CONFLICT (mf_owner_id) WHERE agent_group_id IS NULL
+			DO UPDATE SET heartbeat_interval = EXCLUDED.heartbeat_interval, stale_timeout = EXCLUDED.stale_timeout, ts_updated = CURRENT_TIMESTAMP`
+	if cfg.AgentGroupID != "" {
+		q = `INSERT INTO heartbeat_configs (mf_owner_id, agent_group_id, heartbeat_interval, stale_timeout)
+			VALUES (:mf_owner_id, :agent_group_id, :heartbeat_interval, :stale_timeout)
+			ON CONFLICT (mf_owner_id, agent_group_id) WHERE agent_group_id IS NOT NULL
+			DO UPDATE SET heartbeat_interval = EXCLUDED.heartbeat_interval, stale_timeout = EXCLUDED.stale_timeout, ts_updated = CURRENT_TIMESTAMP`
+	}
+
+	if cfg.MFOwnerID == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	dbc := toDBHeartbeatConfig(cfg)
+	if _, err := r.db.NamedExecContext(ctx, q, dbc); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case db.ErrInvalid, db.ErrTruncation:
+				return errors.Wrap(errors.ErrMalformedEntity, err)
+			}
+		}
+		return errors.Wrap(db.ErrSaveDB, err)
+	}
+
+	return nil
+}
+
+func (r agentRepository) RetrieveHeartbeatConfigs(ctx context.Context, ownerID string) ([]fleet.HeartbeatConfig, error) {
+	q := `SELECT mf_owner_id, agent_group_id, heartbeat_interval, stale_timeout, ts_updated
+			FROM heartbeat_configs WHERE mf_owner_id = :mf_owner_id`
+
+	if ownerID == "" {
+		return nil, errors.ErrMalformedEntity
+	}
+
+	params := map[string]interface{}{
+		"mf_owner_id": ownerID,
+	}
+
+	rows, err := r.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+	defer rows.Close()
+
+	var items []fleet.HeartbeatConfig
+	for rows.Next() {
+		var dbc dbHeartbeatConfig
+		if err := rows.StructScan(&dbc); err != nil {
+			return nil, errors.Wrap(errors.ErrSelectEntity, err)
+		}
+		items = append(items, toHeartbeatConfig(dbc))
+	}
+
+	return items, nil
+}
+
+func (r agentRepository) DeleteHeartbeatConfig(ctx context.Context, ownerID string, groupID string) error {
+	q := `DELETE FROM heartbeat_configs WHERE mf_owner_id = :mf_owner_id AND agent_group_id = :agent_group_id`
+
+	if ownerID == "" || groupID == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	params := map[string]interface{}{
+		"mf_owner_id":    ownerID,
+		"agent_group_id": groupID,
+	}
+
+	if _, err := r.db.NamedExecContext(ctx, q, params); err != nil {
+		return errors.Wrap(fleet.ErrRemoveEntity, err)
+	}
+
+	return nil
+}
+
+type dbHeartbeatConfig struct {
+	MFOwnerID         string         `db:"mf_owner_id"`
+	AgentGroupID      sql.NullString `db:"agent_group_id"`
+	HeartbeatInterval int64          `db:"heartbeat_interval"`
+	StaleTimeout      int64          `db:"stale_timeout"`
+	Updated           time.Time      `db:"ts_updated"`
+}
+
+func toDBHeartbeatConfig(cfg fleet.HeartbeatConfig) dbHeartbeatConfig {
+	return dbHeartbeatConfig{
+		MFOwnerID:         cfg.MFOwnerID,
+		AgentGroupID:      sql.NullString{String: cfg.AgentGroupID, Valid: cfg.AgentGroupID != ""},
+		HeartbeatInterval: int64(cfg.Interval.Seconds()),
+		StaleTimeout:      int64(cfg.StaleTimeout.Seconds()),
+	}
+}
+
+func toHeartbeatConfig(dbc dbHeartbeatConfig) fleet.HeartbeatConfig {
+	return fleet.HeartbeatConfig{
+		MFOwnerID:    dbc.MFOwnerID,
+		AgentGroupID: dbc.AgentGroupID.String,
+		Interval:     time.Duration(dbc.HeartbeatInterval) * time.Second,
+		StaleTimeout: time.Duration(dbc.StaleTimeout) * time.Second,
+		Updated:      dbc.Updated,
+	}
+}
@@ -116,6 +116,224 @@ func migrateDB(db *sqlx.DB) error {
 					WHERE agent_groups.mf_owner_id = agents.mf_owner_id
 					  AND (agent_groups.tags <@ coalesce(agents.agent_tags || agents.orb_tags, agents.agent_tags, agents.orb_tags))`,
 				},
+			}, {
+				Id: "fleet_3",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS policy_rollouts (
+						id                 UUID NOT NULL DEFAULT gen_random_uuid(),
+						policy_id          UUID NOT NULL,
+						mf_owner_id        UUID NOT NULL,
+						version            INTEGER NOT NULL,
+						previous_version   INTEGER NOT NULL,
+						group_ids          TEXT[] NOT NULL DEFAULT '{}',
+						canary_agent_ids   TEXT[] NOT NULL DEFAULT '{}',
+						canary_percent     INTEGER NOT NULL DEFAULT 0,
+						canary_count       INTEGER NOT NULL DEFAULT 0,
+						on_failure         TEXT NOT NULL,
+						timeout            INTEGER NOT NULL DEFAULT 0,
+						state              TEXT NOT NULL,
+						reason             TEXT NOT NULL DEFAULT '',
+						ts_created         TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+						ts_last_modified   TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+						PRIMARY KEY (id)
+					)`,
+					`CREATE INDEX ON policy_rollouts (mf_owner_id, policy_id)`,
+					`CREATE INDEX ON policy_rollouts (state)`,
+				},
+				Down: []string{
+					"DROP TABLE policy_rollouts",
+				},
+			}, {
+				Id: "fleet_4",
+				Up: []string{
+					`ALTER TABLE agent_groups ADD COLUMN IF NOT EXISTS selector JSONB NOT NULL DEFAULT '[]'`,
+					`CREATE OR REPLACE FUNCTION agent_tags_match_selector(tags JSONB, selector JSONB) RETURNS BOOLEAN AS $$
+					SELECT coalesce(bool_and(coalesce(
+						CASE req->>'operator'
+							WHEN 'in' THEN tags->>(req->>'key') IN (SELECT jsonb_array_elements_text(req->'values'))
+							WHEN 'notin' THEN NOT coalesce(tags->>(req->>'key') IN (SELECT jsonb_array_elements_text(req->'values')), false)
+							WHEN 'exists' THEN tags->(req->>'key') IS NOT NULL
+							WHEN '!exists' THEN tags->(req->>'key') IS NULL
+							WHEN 'prefix' THEN EXISTS (SELECT 1 FROM jsonb_array_elements_text(req->'values') AS v
+								WHERE left(tags->>(req->>'key'), length(v)) = v)
+							WHEN 'regex' THEN EXISTS (SELECT 1 FROM jsonb_array_elements_text(req->'values') AS v
+								WHERE tags->>(req->>'key') ~ v)
+						END, false)), true)
+					FROM jsonb_array_elements(coalesce(selector, '[]')) AS req
+					$$ LANGUAGE SQL IMMUTABLE`,
+					`CREATE or REPLACE VIEW agent_group_membership(agent_groups_id, agent_groups_name, agent_mf_thing_id, agent_mf_channel_id, group_mf_channel_id, mf_owner_id, agent_state) as
+					SELECT agent_groups.id,
+						   agent_groups.name,
+						   agents.mf_thing_id,
+						   agents.mf_channel_id,
+						   agent_groups.mf_channel_id,
+						   agent_groups.mf_owner_id,
+						   agents.state
+					FROM agents,
+						 agent_groups
+					WHERE agent_groups.mf_owner_id = agents.mf_owner_id
+					  AND (agent_groups.tags <@ coalesce(agents.agent_tags || agents.orb_tags, agents.agent_tags, agents.orb_tags))
+					  AND agent_tags_match_selector(coalesce(agents.agent_tags || agents.orb_tags, agents.agent_tags, agents.orb_tags), agent_groups.selector)`,
+				},
+				Down: []string{
+					`CREATE or REPLACE VIEW agent_group_membership(agent_groups_id, agent_groups_name, agent_mf_thing_id, agent_mf_channel_id, group_mf_channel_id, mf_owner_id, agent_state) as
+					SELECT agent_groups.id,
+						   agent_groups.name,
+						   agents.mf_thing_id,
+						   agents.mf_channel_id,
+						   agent_groups.mf_channel_id,
+						   agent_groups.mf_owner_id,
+						   agents.state
+					FROM agents,
+						 agent_groups
+					WHERE agent_groups.mf_owner_id = agents.mf_owner_id
+					  AND (agent_groups.tags <@ coalesce(agents.agent_tags || agents.orb_tags, agents.agent_tags, agents.orb_tags))`,
+					"DROP FUNCTION agent_tags_match_selector",
+					"ALTER TABLE agent_groups DROP COLUMN selector",
+				},
+			}, {
+				Id: "fleet_5",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS agent_state_events (
+						id                 BIGSERIAL NOT NULL,
+						mf_thing_id        UUID NOT NULL,
+						mf_owner_id        UUID NOT NULL,
+						state              TEXT NOT NULL,
+						previous_state     TEXT NOT NULL DEFAULT '',
+						backend            TEXT NOT NULL DEFAULT '',
+						reason             TEXT NOT NULL DEFAULT '',
+						ts_created         TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+						PRIMARY KEY (id)
+					)`,
+					`CREATE INDEX ON agent_state_events (mf_owner_id, mf_thing_id, ts_created)`,
+					`CREATE OR REPLACE FUNCTION record_agent_state_event() RETURNS TRIGGER AS $$
+					DECLARE
+						backend_name TEXT;
+						backend_info JSONB;
+						old_backend_state TEXT;
+					BEGIN
+						IF TG_OP = 'INSERT' THEN
+							INSERT INTO agent_state_events (mf_thing_id, mf_owner_id, state, reason)
+							VALUES (NEW.mf_thing_id, NEW.mf_owner_id, NEW.state::TEXT, 'agent provisioned');
+							RETURN NEW;
+						END IF;
+
+						IF NEW.state IS DISTINCT FROM OLD.state THEN
+							INSERT INTO agent_state_events (mf_thing_id, mf_owner_id, state, previous_state, reason)
+							VALUES (NEW.mf_thing_id, NEW.mf_owner_id, NEW.state::TEXT, OLD.state::TEXT,
+								CASE NEW.state::TEXT
+									WHEN 'online' THEN 'heartbeat received'
+									WHEN 'offline' THEN 'agent reported going offline'
+									WHEN 'stale' THEN 'no heartbeat received in time'
+									WHEN 'upgrade_required' THEN 'agent version below the minimum required'
+									WHEN 'removed' THEN 'agent removed'
+									ELSE ''
+								END);
+						END IF;
+
+						FOR backend_name, backend_info IN SELECT * FROM jsonb_each(coalesce(NEW.last_hb_data->'backend_state', '{}')) LOOP
+							old_backend_state := OLD.last_hb_data->'backend_state'->backend_name->>'state';
+							IF backend_info->>'state' IS DISTINCT FROM old_backend_state THEN
+								INSERT INTO agent_state_events (mf_thing_id, mf_owner_id, state, previous_state, backend, reason)
+								VALUES (NEW.mf_thing_id, NEW.mf_owner_id, coalesce(backend_info->>'state', ''), coalesce(old_backend_state, ''),
+									backend_name, coalesce(backend_info->>'error', ''));
+							END IF;
+						END LOOP;
+
+						RETURN NEW;
+					END;
+					$$ LANGUAGE plpgsql`,
+					`CREATE TRIGGER agent_state_events AFTER INSERT OR UPDATE OF state, last_hb_data ON agents
+						FOR EACH ROW EXECUTE PROCEDURE record_agent_state_event()`,
+				},
+				Down: []string{
+					"DROP TRIGGER agent_state_events ON agents",
+					"DROP FUNCTION record_agent_state_event",
+					"DROP TABLE agent_state_events",
+				},
+			}, {
+				Id: "fleet_6",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS heartbeat_configs (
+						mf_owner_id        UUID NOT NULL,
+						agent_group_id     UUID REFERENCES agent_groups (id) ON DELETE CASCADE,
+						heartbeat_interval INTEGER NOT NULL,
+						stale_timeout      INTEGER NOT NULL,
+						ts_updated         TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+					)`,
+					`CREATE UNIQUE INDEX heartbeat_configs_owner ON heartbeat_configs (mf_owner_id) WHERE agent_group_id IS NULL`,
+					`CREATE UNIQUE INDEX heartbeat_configs_group ON heartbeat_configs (mf_owner_id, agent_group_id) WHERE agent_group_id IS NOT NULL`,
+				},
+				Down: []string{
+					"DROP TABLE heartbeat_configs",
+				},
+			}, {
+				Id: "fleet_7",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS agent_logs (
+						id                 BIGSERIAL PRIMARY KEY,
+						mf_thing_id        UUID NOT NULL REFERENCES agents (mf_thing_id) ON DELETE CASCADE,
+						mf_owner_id        UUID NOT NULL,
+						ts_created         TIMESTAMPTZ NOT NULL,
+						level              TEXT NOT NULL,
+						source             TEXT NOT NULL,
+						message            TEXT NOT NULL,
+						fields             JSONB NOT NULL DEFAULT '{}'
+					)`,
+					`CREATE INDEX ON agent_logs (mf_owner_id, mf_thing_id, id)`,
+				},
+				Down: []string{
+					"DROP TABLE agent_logs",
+				},
+			}, {
+				Id: "fleet_8",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS enrollment_tokens (
+						id                 UUID NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+						name               TEXT NOT NULL,
+						mf_owner_id        UUID NOT NULL,
+						mf_owner_email     TEXT NOT NULL,
+						token_hash         TEXT NOT NULL UNIQUE,
+						max_uses           BIGINT NOT NULL,
+						uses               BIGINT NOT NULL DEFAULT 0,
+						ts_expires         TIMESTAMPTZ NOT NULL,
+						orb_tags           JSONB NOT NULL DEFAULT '{}',
+						name_pattern       TEXT NOT NULL DEFAULT '',
+						revoked            BOOLEAN NOT NULL DEFAULT FALSE,
+						ts_created         TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+					)`,
+					`CREATE INDEX ON enrollment_tokens (mf_owner_id)`,
+				},
+				Down: []string{
+					"DROP TABLE enrollment_tokens",
+				},
+			}, {
+				Id: "fleet_9",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS agent_bulk_jobs (
+						id                 UUID NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+						mf_owner_id        UUID NOT NULL,
+						mf_owner_email     TEXT NOT NULL DEFAULT '',
+						operation          TEXT NOT NULL,
+						filter             JSONB NOT NULL DEFAULT '{}',
+						orb_tags           JSONB NOT NULL DEFAULT '{}',
+						tag_keys           TEXT[] NOT NULL DEFAULT '{}',
+						reason             TEXT NOT NULL DEFAULT '',
+						state              TEXT NOT NULL,
+						agent_ids          TEXT[] NOT NULL DEFAULT '{}',
+						succeeded          BIGINT NOT NULL DEFAULT 0,
+						failed             BIGINT NOT NULL DEFAULT 0,
+						failures           JSONB NOT NULL DEFAULT '[]',
+						claimed            BOOLEAN NOT NULL DEFAULT false,
+						ts_created         TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+						ts_last_modified   TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+					)`,
+					`CREATE INDEX ON agent_bulk_jobs (mf_owner_id)`,
+					`CREATE INDEX ON agent_bulk_jobs (state, claimed)`,
+				},
+				Down: []string{
+					"DROP TABLE agent_bulk_jobs",
+				},
 			},
 		},
 	}
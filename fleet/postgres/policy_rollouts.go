@@ -0,0 +1,224 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/orb-community/orb/fleet"
+	"github.com/orb-community/orb/pkg/db"
+	"github.com/orb-community/orb/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var _ fleet.PolicyRolloutRepository = (*policyRolloutRepository)(nil)
+
+// rolloutCheckLock is the key of the advisory lock held while a fleet replica checks the rollouts
+const rolloutCheckLock int64 = 0x6f72622d726f6c6c
+
+type policyRolloutRepository struct {
+	db     Database
+	logger *zap.Logger
+}
+
+func (r policyRolloutRepository) Save(ctx context.Context, rollout fleet.PolicyRollout) (string, error) {
+	q := `INSERT INTO policy_rollouts (policy_id, mf_owner_id, version, previous_version, group_ids, canary_agent_ids,
+			canary_percent, canary_count, on_failure, timeout, state, reason)
+		VALUES (:policy_id, :mf_owner_id, :version, :previous_version, :group_ids, :canary_agent_ids,
+			:canary_percent, :canary_count, :on_failure, :timeout, :state, :reason) RETURNING id`
+
+	if rollout.PolicyID == "" || rollout.MFOwnerID == "" || rollout.State == "" {
+		return "", errors.ErrMalformedEntity
+	}
+
+	rows, err := r.db.NamedQueryContext(ctx, q, toDBPolicyRollout(rollout))
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case db.ErrInvalid, db.ErrTruncation:
+				return "", errors.Wrap(errors.ErrMalformedEntity, err)
+			}
+		}
+		return "", errors.Wrap(db.ErrSaveDB, err)
+	}
+	defer rows.Close()
+
+	var id string
+	if rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return "", errors.Wrap(db.ErrSaveDB, err)
+		}
+	}
+
+	return id, nil
+}
+
+func (r policyRolloutRepository) RetrieveLatestByPolicyID(ctx context.Context, ownerID string, policyID string) (fleet.PolicyRollout, error) {
+	q := `SELECT id, policy_id, mf_owner_id, version, previous_version, group_ids, canary_agent_ids, canary_percent,
+			canary_count, on_failure, timeout, state, reason, ts_created, ts_last_modified
+		FROM policy_rollouts WHERE mf_owner_id = $1 AND policy_id = $2 ORDER BY ts_created DESC LIMIT 1`
+
+	if ownerID == "" || policyID == "" {
+		return fleet.PolicyRollout{}, errors.ErrMalformedEntity
+	}
+
+	var dbr dbPolicyRollout
+	if err := r.db.QueryRowxContext(ctx, q, ownerID, policyID).StructScan(&dbr); err != nil {
+		if err == sql.ErrNoRows {
+			return fleet.PolicyRollout{}, errors.Wrap(fleet.ErrRolloutNotFound, err)
+		}
+		return fleet.PolicyRollout{}, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+
+	return toPolicyRollout(dbr), nil
+}
+
+func (r policyRolloutRepository) RetrieveAllByState(ctx context.Context, state string) ([]fleet.PolicyRollout, error) {
+	q := `SELECT id, policy_id, mf_owner_id, version, previous_version, group_ids, canary_agent_ids, canary_percent,
+			canary_count, on_failure, timeout, state, reason, ts_created, ts_last_modified
+		FROM policy_rollouts WHERE state = :state ORDER BY ts_created`
+
+	params := map[string]interface{}{
+		"state": state,
+	}
+
+	rows, err := r.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+	defer rows.Close()
+
+	var items []fleet.PolicyRollout
+	for rows.Next() {
+		var dbr dbPolicyRollout
+		if err := rows.StructScan(&dbr); err != nil {
+			return nil, errors.Wrap(errors.ErrSelectEntity, err)
+		}
+		items = append(items, toPolicyRollout(dbr))
+	}
+
+	return items, nil
+}
+
+func (r policyRolloutRepository) UpdateState(ctx context.Context, ownerID string, rolloutID string, state string, reason string) error {
+	q := `UPDATE policy_rollouts SET state = :state, reason = :reason, ts_last_modified = CURRENT_TIMESTAMP
+		WHERE mf_owner_id = :mf_owner_id AND id = :id`
+
+	params := map[string]interface{}{
+		"id":          rolloutID,
+		"mf_owner_id": ownerID,
+		"state":       state,
+		"reason":      reason,
+	}
+
+	res, err := r.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		return errors.Wrap(fleet.ErrUpdateEntity, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(fleet.ErrUpdateEntity, err)
+	}
+
+	if count == 0 {
+		return fleet.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r policyRolloutRepository) RunExclusive(ctx context.Context, fn func(ctx context.Context) error) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+	// the transaction only holds the lock, released when it ends
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowxContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, rolloutCheckLock).Scan(&locked); err != nil {
+		return false, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+	if !locked {
+		return false, nil
+	}
+
+	return true, fn(ctx)
+}
+
+type dbPolicyRollout struct {
+	ID              string         `db:"id"`
+	PolicyID        string         `db:"policy_id"`
+	MFOwnerID       string         `db:"mf_owner_id"`
+	Version         int32          `db:"version"`
+	PreviousVersion int32          `db:"previous_version"`
+	GroupIDs        pq.StringArray `db:"group_ids"`
+	CanaryAgentIDs  pq.StringArray `db:"canary_agent_ids"`
+	CanaryPercent   int            `db:"canary_percent"`
+	CanaryCount     int            `db:"canary_count"`
+	OnFailure       string         `db:"on_failure"`
+	Timeout         int64          `db:"timeout"`
+	State           string         `db:"state"`
+	Reason          string         `db:"reason"`
+	Created         time.Time      `db:"ts_created"`
+	LastModified    time.Time      `db:"ts_last_modified"`
+}
+
+func toDBPolicyRollout(r fleet.PolicyRollout) dbPolicyRollout {
+	// nil arrays are stored as NULL
+	groupIDs := pq.StringArray{}
+	if r.GroupIDs != nil {
+		groupIDs = r.GroupIDs
+	}
+	canaryAgentIDs := pq.StringArray{}
+	if r.CanaryAgentIDs != nil {
+		canaryAgentIDs = r.CanaryAgentIDs
+	}
+
+	return dbPolicyRollout{
+		ID:              r.ID,
+		PolicyID:        r.PolicyID,
+		MFOwnerID:       r.MFOwnerID,
+		Version:         r.Version,
+		PreviousVersion: r.PreviousVersion,
+		GroupIDs:        groupIDs,
+		CanaryAgentIDs:  canaryAgentIDs,
+		CanaryPercent:   r.CanaryPercent,
+		CanaryCount:     r.CanaryCount,
+		OnFailure:       r.OnFailure,
+		Timeout:         int64(r.Timeout.Seconds()),
+		State:           r.State,
+		Reason:          r.Reason,
+	}
+}
+
+func toPolicyRollout(dbr dbPolicyRollout) fleet.PolicyRollout {
+	return fleet.PolicyRollout{
+		ID:              dbr.ID,
+		PolicyID:        dbr.PolicyID,
+		MFOwnerID:       dbr.MFOwnerID,
+		Version:         dbr.Version,
+		PreviousVersion: dbr.PreviousVersion,
+		GroupIDs:        dbr.GroupIDs,
+		CanaryAgentIDs:  dbr.CanaryAgentIDs,
+		CanaryPercent:   dbr.CanaryPercent,
+		CanaryCount:     dbr.CanaryCount,
+		OnFailure:       dbr.OnFailure,
+		Timeout:         time.Duration(dbr.Timeout) * time.Second,
+		State:           dbr.State,
+		Reason:          dbr.Reason,
+		Created:         dbr.Created,
+		LastModified:    dbr.LastModified,
+	}
+}
+
+func NewPolicyRolloutRepository(db Database, logger *zap.Logger) fleet.PolicyRolloutRepository {
+	return &policyRolloutRepository{db: db, logger: logger}
+}
@@ -49,9 +49,18 @@ type updatePolicyEvent struct {
 	ownerID   string
 	groupsIDs []string
 	policy    types.Metadata
+	version   int32
+	rollout   *rolloutStrategy
 	timestamp time.Time
 }
 
+type rolloutStrategy struct {
+	canaryPercent int
+	canaryCount   int
+	onFailure     string
+	timeout       time.Duration
+}
+
 type removePolicyEvent struct {
 	id        string
 	ownerID   string
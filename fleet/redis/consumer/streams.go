@@ -15,6 +15,7 @@ import (
 	"go.uber.org/zap"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -182,17 +183,56 @@ func decodePolicyUpdate(event map[string]interface{}) (updatePolicyEvent, error)
 	val := updatePolicyEvent{
 		id:      read(event, "id", ""),
 		ownerID: read(event, "owner_id", ""),
+		version: int32(readInt(event, "version", 0)),
 	}
 
 	strgroups := read(event, "groups_ids", "")
 	val.groupsIDs = strings.Split(strgroups, ",")
 
+	if onFailure := read(event, "rollout_on_failure", ""); onFailure != "" {
+		val.rollout = &rolloutStrategy{
+			canaryPercent: readInt(event, "rollout_canary_percent", 0),
+			canaryCount:   readInt(event, "rollout_canary_count", 0),
+			onFailure:     onFailure,
+			timeout:       time.Duration(readInt(event, "rollout_timeout", 0)) * time.Second,
+		}
+	}
+
 	return val, nil
 }
 
 // the policy service is notifying that a policy has been updated
 // notify all agents in the AgentGroup specified in the dataset about the policy update
 func (es eventStore) handlePolicyUpdate(ctx context.Context, e updatePolicyEvent) error {
+	// staged updates only reach the canary agents, fleet sends them to the rest of the groups later
+	if e.rollout != nil && e.version > 0 {
+		var groupIDs []string
+		for _, id := range e.groupsIDs {
+			if id != "" {
+				groupIDs = append(groupIDs, id)
+			}
+		}
+		if len(groupIDs) == 0 {
+			return nil
+		}
+		_, err := es.fleetService.StartPolicyRolloutInternal(ctx, fleet.PolicyRollout{
+			PolicyID:        e.id,
+			MFOwnerID:       e.ownerID,
+			Version:         e.version,
+			PreviousVersion: e.version - 1,
+			GroupIDs:        groupIDs,
+			CanaryPercent:   e.rollout.canaryPercent,
+			CanaryCount:     e.rollout.canaryCount,
+			OnFailure:       e.rollout.onFailure,
+			Timeout:         e.rollout.timeout,
+		})
+		return err
+	}
+
+	if err := es.fleetService.SupersedePolicyRolloutsInternal(ctx, e.ownerID, e.id); err != nil {
+		es.logger.Error("failed to supersede policy rollouts", zap.String("policy_id", e.id), zap.Error(err))
+	}
+
 	for _, a := range e.groupsIDs {
 		ag, err := es.fleetService.ViewAgentGroupByIDInternal(ctx, a, e.ownerID)
 		if err != nil {
@@ -244,6 +284,20 @@ func read(event map[string]interface{}, key, def string) string {
 	return val
 }
 
+func readInt(event map[string]interface{}, key string, def int) int {
+	val, ok := event[key].(string)
+	if !ok {
+		return def
+	}
+
+	intVal, err := strconv.Atoi(val)
+	if err != nil {
+		return def
+	}
+
+	return intVal
+}
+
 func readBool(event map[string]interface{}, key string, def bool) bool {
 	val, ok := event[key].(string)
 	if !ok {
@@ -0,0 +1,107 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package producer
+
+import (
+	"time"
+
+	"github.com/orb-community/orb/fleet"
+	"github.com/orb-community/orb/pkg/types"
+)
+
+// The snapshots below are what the audit log compares to find what a change did. They only hold the fields a user
+// manages, never credentials such as the agent key or the enrollment token itself
+
+type agentSnapshot struct {
+	Name      string      `json:"name"`
+	OrbTags   *types.Tags `json:"orb_tags"`
+	AgentTags types.Tags  `json:"agent_tags"`
+}
+
+func toAgentSnapshot(a fleet.Agent) *agentSnapshot {
+	return &agentSnapshot{
+		Name:      a.Name.String(),
+		OrbTags:   a.OrbTags,
+		AgentTags: a.AgentTags,
+	}
+}
+
+type agentResetSnapshot struct {
+	Reset           bool   `json:"reset"`
+	Backend         string `json:"backend"`
+	ReapplyPolicies bool   `json:"reapply_policies"`
+}
+
+type agentKeySnapshot struct {
+	KeyRotated bool `json:"key_rotated"`
+}
+
+type agentGroupSnapshot struct {
+	Name        string            `json:"name"`
+	Description *string           `json:"description"`
+	Tags        *types.Tags       `json:"tags"`
+	Selector    fleet.TagSelector `json:"selector"`
+}
+
+func toAgentGroupSnapshot(ag fleet.AgentGroup) *agentGroupSnapshot {
+	return &agentGroupSnapshot{
+		Name:        ag.Name.String(),
+		Description: ag.Description,
+		Tags:        ag.Tags,
+		Selector:    ag.Selector,
+	}
+}
+
+type heartbeatConfigSnapshot struct {
+	Interval     string `json:"interval"`
+	StaleTimeout string `json:"stale_timeout"`
+}
+
+func toHeartbeatConfigSnapshot(cfg fleet.HeartbeatConfig) *heartbeatConfigSnapshot {
+	return &heartbeatConfigSnapshot{
+		Interval:     cfg.Interval.String(),
+		StaleTimeout: cfg.StaleTimeout.String(),
+	}
+}
+
+type enrollmentTokenSnapshot struct {
+	Name        string     `json:"name"`
+	MaxUses     uint64     `json:"max_uses"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	OrbTags     types.Tags `json:"orb_tags"`
+	NamePattern string     `json:"name_pattern"`
+	Revoked     bool       `json:"revoked"`
+}
+
+func toEnrollmentTokenSnapshot(et fleet.EnrollmentToken) *enrollmentTokenSnapshot {
+	return &enrollmentTokenSnapshot{
+		Name:        et.Name.String(),
+		MaxUses:     et.MaxUses,
+		ExpiresAt:   et.ExpiresAt,
+		OrbTags:     et.OrbTags,
+		NamePattern: et.NamePattern,
+		Revoked:     et.Revoked,
+	}
+}
+
+type bulkJobSnapshot struct {
+	Operation string     `json:"operation"`
+	Filter    types.Tags `json:"filter"`
+	OrbTags   types.Tags `json:"orb_tags"`
+	TagKeys   []string   `json:"tag_keys"`
+	Reason    string     `json:"reason"`
+	AgentIDs  []string   `json:"agent_ids"`
+}
+
+func toBulkJobSnapshot(j fleet.BulkJob) *bulkJobSnapshot {
+	return &bulkJobSnapshot{
+		Operation: j.Operation,
+		Filter:    j.Filter,
+		OrbTags:   j.OrbTags,
+		TagKeys:   j.TagKeys,
+		Reason:    j.Reason,
+		AgentIDs:  j.AgentIDs,
+	}
+}
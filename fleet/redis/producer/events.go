@@ -17,6 +17,8 @@ const (
 	AgentCreate      = AgentPrefix + "create"
 	AgentGroupPrefix = "agent_group."
 	AgentGroupRemove = AgentGroupPrefix + "remove"
+	PolicyPrefix     = "policy."
+	PolicyRollback   = PolicyPrefix + "rollback"
 )
 
 type event interface {
@@ -26,6 +28,7 @@ type event interface {
 var (
 	_ event = (*createAgentEvent)(nil)
 	_ event = (*removeAgentGroupEvent)(nil)
+	_ event = (*rollbackPolicyEvent)(nil)
 )
 
 type createAgentEvent struct {
@@ -42,6 +45,23 @@ type removeAgentGroupEvent struct {
 	timestamp time.Time
 }
 
+type rollbackPolicyEvent struct {
+	policyID  string
+	ownerID   string
+	version   int32
+	timestamp time.Time
+}
+
+func (rpe rollbackPolicyEvent) encode() map[string]interface{} {
+	return map[string]interface{}{
+		"policy_id": rpe.policyID,
+		"owner_id":  rpe.ownerID,
+		"version":   rpe.version,
+		"timestamp": rpe.timestamp.Unix(),
+		"operation": PolicyRollback,
+	}
+}
+
 func (rde removeAgentGroupEvent) encode() map[string]interface{} {
 	return map[string]interface{}{
 		"group_id":  rde.groupID,
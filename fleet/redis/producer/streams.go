@@ -10,9 +10,12 @@ package producer
 
 import (
 	"context"
+	"fmt"
 	"github.com/go-redis/redis/v8"
+	"github.com/orb-community/orb/audit"
 	"github.com/orb-community/orb/fleet"
 	"go.uber.org/zap"
+	"time"
 )
 
 const (
@@ -23,23 +26,34 @@ const (
 var _ fleet.Service = (*eventStore)(nil)
 
 type eventStore struct {
-	svc    fleet.Service
-	client *redis.Client
-	logger *zap.Logger
+	svc      fleet.Service
+	client   *redis.Client
+	recorder audit.Recorder
+	logger   *zap.Logger
 }
 
 func (es eventStore) ViewAgentMatchingGroupsByIDInternal(ctx context.Context, agentID string, ownerID string) (fleet.MatchingGroups, error) {
 	return es.svc.ViewAgentMatchingGroupsByIDInternal(ctx, agentID, ownerID)
 }
 
-func (es eventStore) ResetAgent(ct context.Context, token string, agentID string) error {
-	return es.svc.ResetAgent(ct, token, agentID)
+func (es eventStore) ResetAgent(ct context.Context, token string, agentID string, backendName string, reapplyPolicies bool) error {
+	if err := es.svc.ResetAgent(ct, token, agentID, backendName, reapplyPolicies); err != nil {
+		return err
+	}
+
+	es.recorder.Record(ct, token, audit.EntityAgent, agentID, audit.ActionUpdate, agentResetSnapshot{},
+		agentResetSnapshot{Reset: true, Backend: backendName, ReapplyPolicies: reapplyPolicies})
+	return nil
 }
 
 func (es eventStore) ViewAgentInfoByChannelIDInternal(ctx context.Context, channelID string) (fleet.Agent, error) {
 	return es.svc.ViewAgentInfoByChannelIDInternal(ctx, channelID)
 }
 
+func (es eventStore) ViewBackendComponentsInternal(ctx context.Context, ownerID string, backendName string) (map[string][]string, error) {
+	return es.svc.ViewBackendComponentsInternal(ctx, ownerID, backendName)
+}
+
 func (es eventStore) ViewAgentBackend(ctx context.Context, token string, name string) (interface{}, error) {
 	return es.svc.ViewAgentBackend(ctx, token, name)
 }
@@ -61,7 +75,18 @@ func (es eventStore) ViewAgentMatchingGroupsByID(ctx context.Context, token stri
 }
 
 func (es eventStore) EditAgent(ctx context.Context, token string, agent fleet.Agent) (fleet.Agent, error) {
-	return es.svc.EditAgent(ctx, token, agent)
+	previous, err := es.svc.ViewAgentByID(ctx, token, agent.MFThingID)
+	if err != nil {
+		return fleet.Agent{}, err
+	}
+
+	edited, err := es.svc.EditAgent(ctx, token, agent)
+	if err != nil {
+		return edited, err
+	}
+
+	es.recorder.Record(ctx, token, audit.EntityAgent, edited.MFThingID, audit.ActionUpdate, toAgentSnapshot(previous), toAgentSnapshot(edited))
+	return edited, nil
 }
 
 func (es eventStore) ViewAgentGroupByIDInternal(ctx context.Context, groupID string, ownerID string) (fleet.AgentGroup, error) {
@@ -77,7 +102,18 @@ func (es eventStore) ListAgentGroups(ctx context.Context, token string, pm fleet
 }
 
 func (es eventStore) EditAgentGroup(ctx context.Context, token string, ag fleet.AgentGroup) (fleet.AgentGroup, error) {
-	return es.svc.EditAgentGroup(ctx, token, ag)
+	previous, err := es.svc.ViewAgentGroupByID(ctx, token, ag.ID)
+	if err != nil {
+		return fleet.AgentGroup{}, err
+	}
+
+	edited, err := es.svc.EditAgentGroup(ctx, token, ag)
+	if err != nil {
+		return edited, err
+	}
+
+	es.recorder.Record(ctx, token, audit.EntityAgentGroup, edited.ID, audit.ActionUpdate, toAgentGroupSnapshot(previous), toAgentGroupSnapshot(edited))
+	return edited, nil
 }
 
 func (es eventStore) ListAgents(ctx context.Context, token string, pm fleet.PageMetadata) (fleet.Page, error) {
@@ -85,19 +121,38 @@ func (es eventStore) ListAgents(ctx context.Context, token string, pm fleet.Page
 }
 
 func (es eventStore) CreateAgent(ctx context.Context, token string, a fleet.Agent) (fleet.Agent, error) {
-	return es.svc.CreateAgent(ctx, token, a)
+	created, err := es.svc.CreateAgent(ctx, token, a)
+	if err != nil {
+		return created, err
+	}
+
+	es.recorder.Record(ctx, token, audit.EntityAgent, created.MFThingID, audit.ActionCreate, nil, toAgentSnapshot(created))
+	return created, nil
 }
 
 func (es eventStore) CreateAgentGroup(ctx context.Context, token string, s fleet.AgentGroup) (fleet.AgentGroup, error) {
-	return es.svc.CreateAgentGroup(ctx, token, s)
+	created, err := es.svc.CreateAgentGroup(ctx, token, s)
+	if err != nil {
+		return created, err
+	}
+
+	es.recorder.Record(ctx, token, audit.EntityAgentGroup, created.ID, audit.ActionCreate, nil, toAgentGroupSnapshot(created))
+	return created, nil
 }
 
 func (es eventStore) RemoveAgentGroup(ctx context.Context, token string, groupID string) (err error) {
+	previous, err := es.svc.ViewAgentGroupByID(ctx, token, groupID)
+	if err != nil {
+		return err
+	}
+
 	err = es.svc.RemoveAgentGroup(ctx, token, groupID)
 	if err != nil {
 		return err
 	}
 
+	es.recorder.Record(ctx, token, audit.EntityAgentGroup, groupID, audit.ActionRemove, toAgentGroupSnapshot(previous), nil)
+
 	event := removeAgentGroupEvent{
 		groupID: groupID,
 		token:   token,
@@ -127,20 +182,261 @@ func (es eventStore) ValidateAgent(ctx context.Context, token string, a fleet.Ag
 }
 
 func (es eventStore) RemoveAgent(ctx context.Context, token, thingID string) (err error) {
-	return es.svc.RemoveAgent(ctx, token, thingID)
+	previous, err := es.svc.ViewAgentByID(ctx, token, thingID)
+	if err != nil {
+		return err
+	}
+
+	if err := es.svc.RemoveAgent(ctx, token, thingID); err != nil {
+		return err
+	}
+
+	es.recorder.Record(ctx, token, audit.EntityAgent, thingID, audit.ActionRemove, toAgentSnapshot(previous), nil)
+	return nil
 }
 
 func (es eventStore) GetPolicyState(ctx context.Context, agent fleet.Agent) (map[string]interface{}, error) {
 	return es.svc.GetPolicyState(ctx, agent)
 }
 
+func (es eventStore) StartPolicyRolloutInternal(ctx context.Context, rollout fleet.PolicyRollout) (fleet.PolicyRollout, error) {
+	return es.svc.StartPolicyRolloutInternal(ctx, rollout)
+}
+
+func (es eventStore) SupersedePolicyRolloutsInternal(ctx context.Context, ownerID string, policyID string) error {
+	return es.svc.SupersedePolicyRolloutsInternal(ctx, ownerID, policyID)
+}
+
+func (es eventStore) CheckPolicyRolloutsInternal(ctx context.Context) ([]fleet.PolicyRollout, error) {
+	rolledBack, err := es.svc.CheckPolicyRolloutsInternal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the policies service restores the previous version and notifies the groups about it
+	for _, r := range rolledBack {
+		event := rollbackPolicyEvent{
+			policyID: r.PolicyID,
+			ownerID:  r.MFOwnerID,
+			version:  r.PreviousVersion,
+		}
+		record := &redis.XAddArgs{
+			Stream: streamID,
+			MaxLen: streamLen,
+			Approx: true,
+			Values: event.encode(),
+		}
+		if err := es.client.XAdd(ctx, record).Err(); err != nil {
+			es.logger.Error("error sending event to event store", zap.Error(err))
+			return rolledBack, err
+		}
+	}
+
+	return rolledBack, nil
+}
+
+func (es eventStore) ViewPolicyRollout(ctx context.Context, token string, policyID string) (fleet.PolicyRolloutStatus, error) {
+	return es.svc.ViewPolicyRollout(ctx, token, policyID)
+}
+
+func (es eventStore) ViewAgentHistory(ctx context.Context, token string, thingID string, from time.Time, to time.Time, limit uint64) ([]fleet.AgentStateEvent, error) {
+	return es.svc.ViewAgentHistory(ctx, token, thingID, from, to, limit)
+}
+
+func (es eventStore) ViewAgentAvailability(ctx context.Context, token string, thingID string, from time.Time, to time.Time) (fleet.AgentAvailability, error) {
+	return es.svc.ViewAgentAvailability(ctx, token, thingID, from, to)
+}
+
+func (es eventStore) ViewHeartbeatConfig(ctx context.Context, token string) (fleet.HeartbeatConfig, error) {
+	return es.svc.ViewHeartbeatConfig(ctx, token)
+}
+
+func (es eventStore) EditHeartbeatConfig(ctx context.Context, token string, cfg fleet.HeartbeatConfig) (fleet.HeartbeatConfig, error) {
+	previous, err := es.svc.ViewHeartbeatConfig(ctx, token)
+	if err != nil {
+		return fleet.HeartbeatConfig{}, err
+	}
+
+	edited, err := es.svc.EditHeartbeatConfig(ctx, token, cfg)
+	if err != nil {
+		return edited, err
+	}
+
+	// the owner wide configuration has no agent group, so no entity id
+	es.recorder.Record(ctx, token, audit.EntityHeartbeatConfig, edited.AgentGroupID, audit.ActionUpdate,
+		toHeartbeatConfigSnapshot(previous), toHeartbeatConfigSnapshot(edited))
+	return edited, nil
+}
+
+func (es eventStore) ViewAgentGroupHeartbeatConfig(ctx context.Context, token string, groupID string) (fleet.HeartbeatConfig, error) {
+	return es.svc.ViewAgentGroupHeartbeatConfig(ctx, token, groupID)
+}
+
+func (es eventStore) EditAgentGroupHeartbeatConfig(ctx context.Context, token string, cfg fleet.HeartbeatConfig) (fleet.HeartbeatConfig, error) {
+	previous, err := es.svc.ViewAgentGroupHeartbeatConfig(ctx, token, cfg.AgentGroupID)
+	if err != nil {
+		return fleet.HeartbeatConfig{}, err
+	}
+
+	edited, err := es.svc.EditAgentGroupHeartbeatConfig(ctx, token, cfg)
+	if err != nil {
+		return edited, err
+	}
+
+	es.recorder.Record(ctx, token, audit.EntityHeartbeatConfig, edited.AgentGroupID, audit.ActionUpdate,
+		toHeartbeatConfigSnapshot(previous), toHeartbeatConfigSnapshot(edited))
+	return edited, nil
+}
+
+func (es eventStore) RemoveAgentGroupHeartbeatConfig(ctx context.Context, token string, groupID string) error {
+	previous, err := es.svc.ViewAgentGroupHeartbeatConfig(ctx, token, groupID)
+	if err != nil {
+		return err
+	}
+
+	if err := es.svc.RemoveAgentGroupHeartbeatConfig(ctx, token, groupID); err != nil {
+		return err
+	}
+
+	es.recorder.Record(ctx, token, audit.EntityHeartbeatConfig, groupID, audit.ActionRemove, toHeartbeatConfigSnapshot(previous), nil)
+	return nil
+}
+
+func (es eventStore) ViewAgentHeartbeatConfig(ctx context.Context, token string, thingID string) (fleet.HeartbeatConfig, error) {
+	return es.svc.ViewAgentHeartbeatConfig(ctx, token, thingID)
+}
+
+func (es eventStore) ViewAgentLogs(ctx context.Context, token string, thingID string, since time.Time, level string, limit uint64) ([]fleet.AgentLogEntry, error) {
+	return es.svc.ViewAgentLogs(ctx, token, thingID, since, level, limit)
+}
+
+func (es eventStore) ViewAgentPolicySnapshot(ctx context.Context, token string, thingID string, policyID string) (fleet.PolicySnapshot, error) {
+	return es.svc.ViewAgentPolicySnapshot(ctx, token, thingID, policyID)
+}
+
+func (es eventStore) CreateEnrollmentToken(ctx context.Context, token string, et fleet.EnrollmentToken) (fleet.EnrollmentToken, error) {
+	created, err := es.svc.CreateEnrollmentToken(ctx, token, et)
+	if err != nil {
+		return created, err
+	}
+
+	es.recorder.Record(ctx, token, audit.EntityEnrollmentToken, created.ID, audit.ActionCreate, nil, toEnrollmentTokenSnapshot(created))
+	return created, nil
+}
+
+func (es eventStore) ListEnrollmentTokens(ctx context.Context, token string) ([]fleet.EnrollmentToken, error) {
+	return es.svc.ListEnrollmentTokens(ctx, token)
+}
+
+func (es eventStore) ViewEnrollmentToken(ctx context.Context, token string, id string) (fleet.EnrollmentToken, error) {
+	return es.svc.ViewEnrollmentToken(ctx, token, id)
+}
+
+func (es eventStore) RevokeEnrollmentToken(ctx context.Context, token string, id string) error {
+	previous, err := es.svc.ViewEnrollmentToken(ctx, token, id)
+	if err != nil {
+		return err
+	}
+
+	if err := es.svc.RevokeEnrollmentToken(ctx, token, id); err != nil {
+		return err
+	}
+
+	revoked := previous
+	revoked.Revoked = true
+	es.recorder.Record(ctx, token, audit.EntityEnrollmentToken, id, audit.ActionUpdate,
+		toEnrollmentTokenSnapshot(previous), toEnrollmentTokenSnapshot(revoked))
+	return nil
+}
+
+func (es eventStore) EnrollAgent(ctx context.Context, enrollmentToken string, a fleet.Agent) (fleet.Agent, fleet.EnrollmentToken, error) {
+	created, et, err := es.svc.EnrollAgent(ctx, enrollmentToken, a)
+	if err != nil {
+		return created, et, err
+	}
+
+	es.recorder.RecordInternal(ctx, created.MFOwnerID, fmt.Sprintf("enrollment token %s", et.ID), audit.EntityAgent,
+		created.MFThingID, audit.ActionCreate, nil, toAgentSnapshot(created))
+	return created, et, nil
+}
+
+func (es eventStore) RotateAgentKey(ctx context.Context, token string, thingID string) (fleet.Agent, error) {
+	agent, err := es.svc.RotateAgentKey(ctx, token, thingID)
+	if err != nil {
+		return agent, err
+	}
+
+	// the keys themselves are never recorded, only that one was rotated
+	es.recorder.Record(ctx, token, audit.EntityAgent, thingID, audit.ActionUpdate, agentKeySnapshot{}, agentKeySnapshot{KeyRotated: true})
+	return agent, nil
+}
+
+func (es eventStore) BulkAgentOperation(ctx context.Context, token string, op fleet.BulkOperation) (fleet.BulkJob, []fleet.Agent, error) {
+	job, agents, err := es.svc.BulkAgentOperation(ctx, token, op)
+	if err != nil || op.DryRun {
+		return job, agents, err
+	}
+
+	es.recorder.Record(ctx, token, audit.EntityBulkJob, job.ID, audit.ActionCreate, nil, toBulkJobSnapshot(job))
+	return job, agents, nil
+}
+
+func (es eventStore) ViewBulkJob(ctx context.Context, token string, id string) (fleet.BulkJob, error) {
+	return es.svc.ViewBulkJob(ctx, token, id)
+}
+
+func (es eventStore) ClaimBulkJobInternal(ctx context.Context) (fleet.BulkJob, error) {
+	return es.svc.ClaimBulkJobInternal(ctx)
+}
+
+func (es eventStore) ApplyBulkOperationInternal(ctx context.Context, job fleet.BulkJob, agentID string) error {
+	previous, err := es.svc.ViewAgentByIDInternal(ctx, job.MFOwnerID, agentID)
+	if err != nil {
+		// the operation fails the same way, and is recorded as a failure of the job
+		return es.svc.ApplyBulkOperationInternal(ctx, job, agentID)
+	}
+
+	if err := es.svc.ApplyBulkOperationInternal(ctx, job, agentID); err != nil {
+		return err
+	}
+
+	// the job runs on behalf of its owner, which is recorded as the actor
+	switch job.Operation {
+	case fleet.BulkAddTags, fleet.BulkRemoveTags:
+		edited, err := es.svc.ViewAgentByIDInternal(ctx, job.MFOwnerID, agentID)
+		if err != nil {
+			es.logger.Error("failed to retrieve the agent edited by a bulk job", zap.String("job_id", job.ID),
+				zap.String("agent_id", agentID), zap.Error(err))
+			return nil
+		}
+		es.recorder.RecordInternal(ctx, job.MFOwnerID, job.MFOwnerEmail, audit.EntityAgent, agentID, audit.ActionUpdate,
+			toAgentSnapshot(previous), toAgentSnapshot(edited))
+	case fleet.BulkDelete:
+		es.recorder.RecordInternal(ctx, job.MFOwnerID, job.MFOwnerEmail, audit.EntityAgent, agentID, audit.ActionRemove,
+			toAgentSnapshot(previous), nil)
+	case fleet.BulkReset:
+		es.recorder.RecordInternal(ctx, job.MFOwnerID, job.MFOwnerEmail, audit.EntityAgent, agentID, audit.ActionUpdate,
+			agentResetSnapshot{}, agentResetSnapshot{Reset: true, ReapplyPolicies: true})
+	}
+	return nil
+}
+
+func (es eventStore) CompleteBulkJobInternal(ctx context.Context, job fleet.BulkJob) error {
+	return es.svc.CompleteBulkJobInternal(ctx, job)
+}
+
+func (es eventStore) FailStaleBulkJobsInternal(ctx context.Context) error {
+	return es.svc.FailStaleBulkJobsInternal(ctx)
+}
+
 // NewEventStoreMiddleware returns wrapper around fleet service that sends
-// events to event store.
-func NewEventStoreMiddleware(svc fleet.Service, client *redis.Client, logger *zap.Logger) fleet.Service {
+// events to event store, and records the changes made through it to the audit log.
+func NewEventStoreMiddleware(svc fleet.Service, client *redis.Client, recorder audit.Recorder, logger *zap.Logger) fleet.Service {
 	l := logger.Named("event_store_middleware")
 	return eventStore{
-		svc:    svc,
-		client: client,
-		logger: l,
+		svc:      svc,
+		client:   client,
+		recorder: recorder,
+		logger:   l,
 	}
 }
@@ -12,6 +12,7 @@ import (
 	"context"
 	"github.com/mainflux/mainflux"
 	mfsdk "github.com/mainflux/mainflux/pkg/sdk/go"
+	"github.com/orb-community/orb/notifications"
 	"github.com/orb-community/orb/pkg/errors"
 	"github.com/orb-community/orb/pkg/types"
 	"go.uber.org/zap"
@@ -21,6 +22,14 @@ import (
 type Service interface {
 	AgentService
 	AgentGroupService
+	PolicyRolloutService
+	AgentStateHistoryService
+	HeartbeatConfigService
+	AgentLogService
+	PolicySnapshotService
+	EnrollmentTokenService
+	AgentKeyService
+	AgentBulkService
 }
 
 // PageMetadata contains page metadata that helps navigation.
@@ -42,12 +51,17 @@ type fleetService struct {
 	// for AuthN/AuthZ
 	auth mainflux.AuthServiceClient
 	// for Thing manipulation
-	mfsdk mfsdk.SDK
+	mfsdk     mfsdk.SDK
+	thingKeys ThingKeyUpdater
 	// Agents and Agent Groups
 	agentRepo            AgentRepository
 	agentGroupRepository AgentGroupRepository
+	// Staged policy rollouts
+	rolloutRepo PolicyRolloutRepository
 	// Agent Comms
 	agentComms AgentCommsService
+	// notifier publishes the agents going stale, for owners to be notified
+	notifier notifications.Publisher
 
 	aTicker *time.Ticker
 	aDone   chan bool
@@ -97,7 +111,7 @@ func (svc fleetService) thing(token, id string, name string, md map[string]inter
 	return thing, nil
 }
 
-func NewFleetService(logger *zap.Logger, auth mainflux.AuthServiceClient, agentRepo AgentRepository, agentGroupRepository AgentGroupRepository, agentComms AgentCommsService, mfsdk mfsdk.SDK, aDone chan bool) Service {
+func NewFleetService(logger *zap.Logger, auth mainflux.AuthServiceClient, agentRepo AgentRepository, agentGroupRepository AgentGroupRepository, rolloutRepo PolicyRolloutRepository, agentComms AgentCommsService, mfsdk mfsdk.SDK, thingKeys ThingKeyUpdater, notifier notifications.Publisher, aDone chan bool) Service {
 
 	aTicker := time.NewTicker(HeartbeatFreq)
 
@@ -106,8 +120,11 @@ func NewFleetService(logger *zap.Logger, auth mainflux.AuthServiceClient, agentR
 		auth:                 auth,
 		agentRepo:            agentRepo,
 		agentGroupRepository: agentGroupRepository,
+		rolloutRepo:          rolloutRepo,
 		agentComms:           agentComms,
 		mfsdk:                mfsdk,
+		thingKeys:            thingKeys,
+		notifier:             notifier,
 		aTicker:              aTicker,
 		aDone:                aDone,
 	}
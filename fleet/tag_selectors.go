@@ -0,0 +1,158 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package fleet
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+)
+
+const (
+	// SelectorIn matches agents having the tag set to one of the values
+	SelectorIn = "in"
+	// SelectorNotIn matches agents missing the tag or having it set to none of the values
+	SelectorNotIn = "notin"
+	// SelectorExists matches agents having the tag, whatever its value
+	SelectorExists = "exists"
+	// SelectorNotExists matches agents missing the tag
+	SelectorNotExists = "!exists"
+	// SelectorPrefix matches agents having the tag value starting with one of the values
+	SelectorPrefix = "prefix"
+	// SelectorRegex matches agents having the tag value matching one of the regular expressions, limited to the
+	// syntax Go and the POSIX regular expressions of the database agree on: literals, ".", bracket expressions,
+	// anchors "^" and "$", quantifiers, alternation, plain groups, the \d \w \s \n \t class escapes
+	// and escaped punctuation. Group flags, named and non capturing groups, unicode classes and the
+	// other escapes, e.g. (?i), (?P<name>...), \pL, \b or \z, are rejected
+	SelectorRegex = "regex"
+
+	// portableEscapes are the characters which can follow a backslash in a selector regular expression
+	portableEscapes = `dDwWsSnt\.^$|?*+()[]{}-/`
+)
+
+var (
+	// ErrInvalidSelector indicates a group tag selector requirement that can not be evaluated
+	ErrInvalidSelector = errors.New("invalid tag selector")
+)
+
+// TagSelectorRequirement is a single expression of a group tag selector, the json field names are
+// read by the agent_tags_match_selector function of the database
+type TagSelectorRequirement struct {
+	Key      string   `json:"key"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values,omitempty"`
+}
+
+// TagSelector selects the agents of a group along with the group tags, every requirement must match
+type TagSelector []TagSelectorRequirement
+
+// Validate checks every requirement has a key, a known operator and the values it needs
+func (s TagSelector) Validate() error {
+	for i, req := range s {
+		if strings.TrimSpace(req.Key) == "" {
+			return errors.Wrap(ErrInvalidSelector, fmt.Errorf("requirement %d has no key", i))
+		}
+		switch req.Operator {
+		case SelectorExists, SelectorNotExists:
+			if len(req.Values) > 0 {
+				return errors.Wrap(ErrInvalidSelector, fmt.Errorf("operator %s on %s takes no values", req.Operator, req.Key))
+			}
+		case SelectorIn, SelectorNotIn, SelectorPrefix:
+			if len(req.Values) == 0 {
+				return errors.Wrap(ErrInvalidSelector, fmt.Errorf("operator %s on %s requires values", req.Operator, req.Key))
+			}
+		case SelectorRegex:
+			if len(req.Values) == 0 {
+				return errors.Wrap(ErrInvalidSelector, fmt.Errorf("operator %s on %s requires values", req.Operator, req.Key))
+			}
+			for _, v := range req.Values {
+				if err := validateRegex(v); err != nil {
+					return errors.Wrap(ErrInvalidSelector, fmt.Errorf("invalid regular expression on %s: %w", req.Key, err))
+				}
+			}
+		default:
+			return errors.Wrap(ErrInvalidSelector, fmt.Errorf("unknown operator %q on %s", req.Operator, req.Key))
+		}
+	}
+	return nil
+}
+
+// validateRegex checks the expression compiles and sticks to the syntax shared with the database, so the groups
+// select the same agents whether the selector is evaluated by Matches or by agent_tags_match_selector
+func validateRegex(expr string) error {
+	if _, err := regexp.Compile(expr); err != nil {
+		return err
+	}
+	for i := 0; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			i++
+			if i < len(expr) && !strings.ContainsRune(portableEscapes, rune(expr[i])) {
+				return fmt.Errorf("escape \\%c is not supported", expr[i])
+			}
+		case '(':
+			if i+1 < len(expr) && expr[i+1] == '?' {
+				return errors.New("group flags, named and non capturing groups are not supported")
+			}
+		}
+	}
+	return nil
+}
+
+// Matches evaluates the selector against the tags of an agent, the same way the database does for the regular
+// expressions Validate accepts
+func (s TagSelector) Matches(tags types.Tags) bool {
+	for _, req := range s {
+		value, ok := tags[req.Key]
+		switch req.Operator {
+		case SelectorIn:
+			if !ok || !contains(req.Values, value) {
+				return false
+			}
+		case SelectorNotIn:
+			if ok && contains(req.Values, value) {
+				return false
+			}
+		case SelectorExists:
+			if !ok {
+				return false
+			}
+		case SelectorNotExists:
+			if ok {
+				return false
+			}
+		case SelectorPrefix:
+			if !ok || !anyValue(req.Values, func(v string) bool { return strings.HasPrefix(value, v) }) {
+				return false
+			}
+		case SelectorRegex:
+			if !ok || !anyValue(req.Values, func(v string) bool {
+				matched, err := regexp.MatchString(v, value)
+				return err == nil && matched
+			}) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	return anyValue(values, func(v string) bool { return v == value })
+}
+
+func anyValue(values []string, match func(string) bool) bool {
+	for _, v := range values {
+		if match(v) {
+			return true
+		}
+	}
+	return false
+}
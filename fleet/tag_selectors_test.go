@@ -0,0 +1,149 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package fleet_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/orb-community/orb/fleet"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagSelectorValidate(t *testing.T) {
+	cases := map[string]struct {
+		selector fleet.TagSelector
+		err      error
+	}{
+		"validate empty selector": {
+			selector: nil,
+			err:      nil,
+		},
+		"validate selector with every operator": {
+			selector: fleet.TagSelector{
+				{Key: "region", Operator: fleet.SelectorIn, Values: []string{"eu", "us"}},
+				{Key: "env", Operator: fleet.SelectorNotIn, Values: []string{"dev"}},
+				{Key: "node_type", Operator: fleet.SelectorExists},
+				{Key: "decommissioned", Operator: fleet.SelectorNotExists},
+				{Key: "site", Operator: fleet.SelectorPrefix, Values: []string{"fra"}},
+				{Key: "pop", Operator: fleet.SelectorRegex, Values: []string{"^pop-[0-9]+$"}},
+			},
+			err: nil,
+		},
+		"validate selector without key": {
+			selector: fleet.TagSelector{{Operator: fleet.SelectorExists}},
+			err:      fleet.ErrInvalidSelector,
+		},
+		"validate selector with unknown operator": {
+			selector: fleet.TagSelector{{Key: "region", Operator: "like", Values: []string{"eu"}}},
+			err:      fleet.ErrInvalidSelector,
+		},
+		"validate selector with values on exists": {
+			selector: fleet.TagSelector{{Key: "region", Operator: fleet.SelectorExists, Values: []string{"eu"}}},
+			err:      fleet.ErrInvalidSelector,
+		},
+		"validate selector without values on in": {
+			selector: fleet.TagSelector{{Key: "region", Operator: fleet.SelectorIn}},
+			err:      fleet.ErrInvalidSelector,
+		},
+		"validate selector with regex syntax shared with the database": {
+			selector: fleet.TagSelector{{Key: "pop", Operator: fleet.SelectorRegex, Values: []string{`^(fra|ams)-\d{2}\.[a-z]+$`, `a|b*?`}}},
+			err:      nil,
+		},
+		"validate selector with named group regex": {
+			selector: fleet.TagSelector{{Key: "pop", Operator: fleet.SelectorRegex, Values: []string{`^(?P<site>[a-z]+)-[0-9]+$`}}},
+			err:      fleet.ErrInvalidSelector,
+		},
+		"validate selector with case insensitive flag regex": {
+			selector: fleet.TagSelector{{Key: "pop", Operator: fleet.SelectorRegex, Values: []string{`(?i)^fra`}}},
+			err:      fleet.ErrInvalidSelector,
+		},
+		"validate selector with unicode class regex": {
+			selector: fleet.TagSelector{{Key: "pop", Operator: fleet.SelectorRegex, Values: []string{`^\pL+$`}}},
+			err:      fleet.ErrInvalidSelector,
+		},
+		"validate selector with end of text regex": {
+			selector: fleet.TagSelector{{Key: "pop", Operator: fleet.SelectorRegex, Values: []string{`fra\z`}}},
+			err:      fleet.ErrInvalidSelector,
+		},
+		"validate selector with invalid regex": {
+			selector: fleet.TagSelector{{Key: "region", Operator: fleet.SelectorRegex, Values: []string{"eu-("}}},
+			err:      fleet.ErrInvalidSelector,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			err := tc.selector.Validate()
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+		})
+	}
+}
+
+func TestTagSelectorMatches(t *testing.T) {
+	tags := types.Tags{
+		"region":    "eu",
+		"site":      "fra-02",
+		"node_type": "dns",
+	}
+
+	cases := map[string]struct {
+		selector fleet.TagSelector
+		matches  bool
+	}{
+		"match empty selector": {
+			selector: nil,
+			matches:  true,
+		},
+		"match in": {
+			selector: fleet.TagSelector{{Key: "region", Operator: fleet.SelectorIn, Values: []string{"us", "eu"}}},
+			matches:  true,
+		},
+		"match in on missing tag": {
+			selector: fleet.TagSelector{{Key: "env", Operator: fleet.SelectorIn, Values: []string{"prod"}}},
+			matches:  false,
+		},
+		"match notin": {
+			selector: fleet.TagSelector{{Key: "region", Operator: fleet.SelectorNotIn, Values: []string{"eu"}}},
+			matches:  false,
+		},
+		"match notin on missing tag": {
+			selector: fleet.TagSelector{{Key: "env", Operator: fleet.SelectorNotIn, Values: []string{"dev"}}},
+			matches:  true,
+		},
+		"match exists": {
+			selector: fleet.TagSelector{{Key: "node_type", Operator: fleet.SelectorExists}},
+			matches:  true,
+		},
+		"match not exists": {
+			selector: fleet.TagSelector{{Key: "node_type", Operator: fleet.SelectorNotExists}},
+			matches:  false,
+		},
+		"match prefix": {
+			selector: fleet.TagSelector{{Key: "site", Operator: fleet.SelectorPrefix, Values: []string{"ams", "fra"}}},
+			matches:  true,
+		},
+		"match regex": {
+			selector: fleet.TagSelector{{Key: "site", Operator: fleet.SelectorRegex, Values: []string{"^fra-[0-9]+$"}}},
+			matches:  true,
+		},
+		"match every requirement": {
+			selector: fleet.TagSelector{
+				{Key: "region", Operator: fleet.SelectorIn, Values: []string{"eu"}},
+				{Key: "site", Operator: fleet.SelectorPrefix, Values: []string{"ams"}},
+			},
+			matches: false,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			matches := tc.selector.Matches(tags)
+			assert.Equal(t, tc.matches, matches, fmt.Sprintf("%s: expected %t got %t", desc, tc.matches, matches))
+		})
+	}
+}
@@ -45,7 +45,7 @@ func TestReturnConfigYamlFromSink(t *testing.T) {
 					},
 				},
 			},
-			want:    `---\nreceivers:\n  kafka:\n    brokers:\n    - kafka:9092\n    topic: otlp_metrics-sink-id-11\n    protocol_version: 2.0.0\nextensions:\n  pprof:\n    endpoint: 0.0.0.0:1888\n  basicauth/exporter:\n    client_auth:\n      username: prom-user\n      password: dbpass\nexporters:\n  prometheusremotewrite:\n    endpoint: https://acme.com/prom/push\n    auth:\n      authenticator: basicauth/exporter\nservice:\n  extensions:\n  - pprof\n  - basicauth/exporter\n  pipelines:\n    metrics:\n      receivers:\n      - kafka\n      exporters:\n      - prometheusremotewrite\n`,
+			want:    "---\nreceivers:\n  kafka:\n    brokers:\n    - kafka:9092\n    topic: otlp_metrics-sink-id-11\n    protocol_version: 2.0.0\nextensions:\n  pprof:\n    endpoint: 0.0.0.0:1888\n  basicauth/exporter:\n    client_auth:\n      username: prom-user\n      password: dbpass\nexporters:\n  prometheusremotewrite:\n    endpoint: https://acme.com/prom/push\n    auth:\n      authenticator: basicauth/exporter\nservice:\n  extensions:\n  - pprof\n  - basicauth/exporter\n  pipelines:\n    metrics:\n      receivers:\n      - kafka\n      exporters:\n      - prometheusremotewrite\n",
 			wantErr: false,
 		},
 		{
@@ -72,7 +72,7 @@ func TestReturnConfigYamlFromSink(t *testing.T) {
 					},
 				},
 			},
-			want:    `---\nreceivers:\n  kafka:\n    brokers:\n    - kafka:9092\n    topic: otlp_metrics-sink-id-11\n    protocol_version: 2.0.0\nextensions:\n  pprof:\n    endpoint: 0.0.0.0:1888\n  basicauth/exporter:\n    client_auth:\n      username: prom-user\n      password: dbpass\nexporters:\n  prometheusremotewrite:\n    endpoint: https://acme.com/prom/push\n    headers:\n      X-Scope-OrgID: TENANT_1\n    auth:\n      authenticator: basicauth/exporter\nservice:\n  extensions:\n  - pprof\n  - basicauth/exporter\n  pipelines:\n    metrics:\n      receivers:\n      - kafka\n      exporters:\n      - prometheusremotewrite\n`,
+			want:    "---\nreceivers:\n  kafka:\n    brokers:\n    - kafka:9092\n    topic: otlp_metrics-sink-id-11\n    protocol_version: 2.0.0\nextensions:\n  pprof:\n    endpoint: 0.0.0.0:1888\n  basicauth/exporter:\n    client_auth:\n      username: prom-user\n      password: dbpass\nexporters:\n  prometheusremotewrite:\n    endpoint: https://acme.com/prom/push\n    headers:\n      X-Scope-OrgID: TENANT_1\n    auth:\n      authenticator: basicauth/exporter\nservice:\n  extensions:\n  - pprof\n  - basicauth/exporter\n  pipelines:\n    metrics:\n      receivers:\n      - kafka\n      exporters:\n      - prometheusremotewrite\n",
 			wantErr: false,
 		},
 		{
@@ -96,7 +96,7 @@ func TestReturnConfigYamlFromSink(t *testing.T) {
 					},
 				},
 			},
-			want:    `---\nreceivers:\n  kafka:\n    brokers:\n    - kafka:9092\n    topic: otlp_metrics-sink-id-22\n    protocol_version: 2.0.0\nextensions:\n  pprof:\n    endpoint: 0.0.0.0:1888\n  basicauth/exporter:\n    client_auth:\n      username: otlp-user\n      password: dbpass\nexporters:\n  otlphttp:\n    endpoint: https://acme.com/otlphttp/push\n    auth:\n      authenticator: basicauth/exporter\nservice:\n  extensions:\n  - pprof\n  - basicauth/exporter\n  pipelines:\n    metrics:\n      receivers:\n      - kafka\n      exporters:\n      - otlphttp\n`,
+			want:    "---\nreceivers:\n  kafka:\n    brokers:\n    - kafka:9092\n    topic: otlp_metrics-sink-id-22\n    protocol_version: 2.0.0\n  kafka/traces:\n    brokers:\n    - kafka:9092\n    topic: otlp_traces-sink-id-22\n    protocol_version: 2.0.0\nextensions:\n  pprof:\n    endpoint: 0.0.0.0:1888\n  basicauth/exporter:\n    client_auth:\n      username: otlp-user\n      password: dbpass\nexporters:\n  otlphttp:\n    endpoint: https://acme.com/otlphttp/push\n    auth:\n      authenticator: basicauth/exporter\nservice:\n  extensions:\n  - pprof\n  - basicauth/exporter\n  pipelines:\n    metrics:\n      receivers:\n      - kafka\n      exporters:\n      - otlphttp\n    traces:\n      receivers:\n      - kafka/traces\n      exporters:\n      - otlphttp\n",
 			wantErr: false,
 		},
 		{
@@ -120,7 +120,121 @@ func TestReturnConfigYamlFromSink(t *testing.T) {
 					},
 				},
 			},
-			want:    `---\nreceivers:\n  kafka:\n    brokers:\n    - kafka:9092\n    topic: otlp_metrics-sink-id-22\n    protocol_version: 2.0.0\nextensions:\n  pprof:\n    endpoint: 0.0.0.0:1888\n  bearertokenauth/withscheme:\n    scheme: Api-Token\n    token: abcdefg\nexporters:\n  otlphttp:\n    endpoint: https://acme.com/otlphttp/push\n    auth:\n      authenticator: bearertokenauth/withscheme\nservice:\n  extensions:\n  - pprof\n  - bearertokenauth/withscheme\n  pipelines:\n    metrics:\n      receivers:\n      - kafka\n      exporters:\n      - otlphttp\n`,
+			want:    "---\nreceivers:\n  kafka:\n    brokers:\n    - kafka:9092\n    topic: otlp_metrics-sink-id-22\n    protocol_version: 2.0.0\n  kafka/traces:\n    brokers:\n    - kafka:9092\n    topic: otlp_traces-sink-id-22\n    protocol_version: 2.0.0\nextensions:\n  pprof:\n    endpoint: 0.0.0.0:1888\n  bearertokenauth/withscheme:\n    scheme: Api-Token\n    token: abcdefg\nexporters:\n  otlphttp:\n    endpoint: https://acme.com/otlphttp/push\n    auth:\n      authenticator: bearertokenauth/withscheme\nservice:\n  extensions:\n  - pprof\n  - bearertokenauth/withscheme\n  pipelines:\n    metrics:\n      receivers:\n      - kafka\n      exporters:\n      - otlphttp\n    traces:\n      receivers:\n      - kafka/traces\n      exporters:\n      - otlphttp\n",
+			wantErr: false,
+		},
+		{
+			name: "prometheus, basicauth, with delivery settings",
+			args: args{
+				in0:            context.Background(),
+				kafkaUrlConfig: "kafka:9092",
+				sink: &DeploymentRequest{
+					SinkID:  "sink-id-11",
+					OwnerID: "11",
+					Backend: "prometheus",
+					Config: types.Metadata{
+						"exporter": types.Metadata{
+							"remote_host": "https://acme.com/prom/push",
+							"delivery": map[string]interface{}{
+								"batch":         map[string]interface{}{"send_batch_size": 1000, "timeout": "5s"},
+								"retry":         map[string]interface{}{"initial_interval": "5s", "max_interval": "30s", "max_elapsed_time": "5m"},
+								"sending_queue": map[string]interface{}{"queue_size": float64(5000), "num_consumers": 4},
+							},
+						},
+						"authentication": types.Metadata{
+							"type":     "basicauth",
+							"username": "prom-user",
+							"password": "dbpass",
+						},
+					},
+				},
+			},
+			want:    "---\nreceivers:\n  kafka:\n    brokers:\n    - kafka:9092\n    topic: otlp_metrics-sink-id-11\n    protocol_version: 2.0.0\nprocessors:\n  batch:\n    send_batch_size: 1000\n    timeout: 5s\nextensions:\n  pprof:\n    endpoint: 0.0.0.0:1888\n  basicauth/exporter:\n    client_auth:\n      username: prom-user\n      password: dbpass\nexporters:\n  prometheusremotewrite:\n    endpoint: https://acme.com/prom/push\n    auth:\n      authenticator: basicauth/exporter\n    retry_on_failure:\n      enabled: true\n      initial_interval: 5s\n      max_interval: 30s\n      max_elapsed_time: 5m\n    remote_write_queue:\n      enabled: true\n      queue_size: 5000\n      num_consumers: 4\nservice:\n  extensions:\n  - pprof\n  - basicauth/exporter\n  pipelines:\n    metrics:\n      receivers:\n      - kafka\n      processors:\n      - batch\n      exporters:\n      - prometheusremotewrite\n",
+			wantErr: false,
+		},
+		{
+			name: "otlp, token auth, with persistent sending queue",
+			args: args{
+				in0:            context.Background(),
+				kafkaUrlConfig: "kafka:9092",
+				sink: &DeploymentRequest{
+					SinkID:  "sink-id-22",
+					OwnerID: "22",
+					Backend: "otlphttp",
+					Config: types.Metadata{
+						"exporter": types.Metadata{
+							"endpoint": "https://acme.com/otlphttp/push",
+							"delivery": map[string]interface{}{
+								"batch":         map[string]interface{}{"send_batch_size": 500, "send_batch_max_size": 1000},
+								"retry":         map[string]interface{}{"enabled": false},
+								"sending_queue": map[string]interface{}{"queue_size": 2000, "persistent": true},
+							},
+						},
+						"authentication": types.Metadata{
+							"type":   "bearertokenauth",
+							"scheme": "Api-Token",
+							"token":  "abcdefg",
+						},
+					},
+				},
+			},
+			want:    "---\nreceivers:\n  kafka:\n    brokers:\n    - kafka:9092\n    topic: otlp_metrics-sink-id-22\n    protocol_version: 2.0.0\n  kafka/traces:\n    brokers:\n    - kafka:9092\n    topic: otlp_traces-sink-id-22\n    protocol_version: 2.0.0\nprocessors:\n  batch:\n    send_batch_size: 500\n    send_batch_max_size: 1000\nextensions:\n  pprof:\n    endpoint: 0.0.0.0:1888\n  bearertokenauth/withscheme:\n    scheme: Api-Token\n    token: abcdefg\n  file_storage:\n    directory: /var/lib/otelcol/file_storage\nexporters:\n  otlphttp:\n    endpoint: https://acme.com/otlphttp/push\n    auth:\n      authenticator: bearertokenauth/withscheme\n    retry_on_failure:\n      enabled: false\n    sending_queue:\n      enabled: true\n      queue_size: 2000\n      storage: file_storage\nservice:\n  extensions:\n  - pprof\n  - bearertokenauth/withscheme\n  - file_storage\n  pipelines:\n    metrics:\n      receivers:\n      - kafka\n      processors:\n      - batch\n      exporters:\n      - otlphttp\n    traces:\n      receivers:\n      - kafka/traces\n      processors:\n      - batch\n      exporters:\n      - otlphttp\n",
+			wantErr: false,
+		},
+		{
+			name: "otlp, token auth, with invalid delivery settings",
+			args: args{
+				in0:            context.Background(),
+				kafkaUrlConfig: "kafka:9092",
+				sink: &DeploymentRequest{
+					SinkID:  "sink-id-22",
+					OwnerID: "22",
+					Backend: "otlphttp",
+					Config: types.Metadata{
+						"exporter": types.Metadata{
+							"endpoint": "https://acme.com/otlphttp/push",
+							"delivery": map[string]interface{}{
+								"retry": map[string]interface{}{"initial_interval": "forever"},
+							},
+						},
+						"authentication": types.Metadata{
+							"type":   "bearertokenauth",
+							"scheme": "Api-Token",
+							"token":  "abcdefg",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "kafka, basicauth as sasl",
+			args: args{
+				in0:            context.Background(),
+				kafkaUrlConfig: "kafka:9092",
+				sink: &DeploymentRequest{
+					SinkID:  "sink-id-33",
+					OwnerID: "33",
+					Backend: "kafka",
+					Config: types.Metadata{
+						"exporter": types.Metadata{
+							"brokers":        "kafka-1.acme.com:9093,kafka-2.acme.com:9093",
+							"topic":          "orb-metrics",
+							"encoding":       "otlp_json",
+							"compression":    "zstd",
+							"sasl_mechanism": "SCRAM-SHA-512",
+							"tls":            map[string]interface{}{"insecure_skip_verify": true},
+							"delivery":       map[string]interface{}{"sending_queue": map[string]interface{}{"persistent": true}},
+						},
+						"authentication": types.Metadata{
+							"type":     "basicauth",
+							"username": "kafka-user",
+							"password": "dbpass",
+						},
+					},
+				},
+			},
+			want:    "---\nreceivers:\n  kafka:\n    brokers:\n    - kafka:9092\n    topic: otlp_metrics-sink-id-33\n    protocol_version: 2.0.0\nextensions:\n  pprof:\n    endpoint: 0.0.0.0:1888\n  file_storage:\n    directory: /var/lib/otelcol/file_storage\nexporters:\n  kafka:\n    brokers:\n    - kafka-1.acme.com:9093\n    - kafka-2.acme.com:9093\n    topic: orb-metrics\n    encoding: otlp_json\n    protocol_version: 2.0.0\n    producer:\n      compression: zstd\n    auth:\n      sasl:\n        username: kafka-user\n        password: dbpass\n        mechanism: SCRAM-SHA-512\n      tls:\n        insecure: false\n        insecure_skip_verify: true\n    sending_queue:\n      enabled: true\n      storage: file_storage\nservice:\n  extensions:\n  - pprof\n  - file_storage\n  pipelines:\n    metrics:\n      receivers:\n      - kafka\n      exporters:\n      - kafka\n",
 			wantErr: false,
 		},
 	}
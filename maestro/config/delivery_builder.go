@@ -0,0 +1,101 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/orb-community/orb/sinks/backend"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FileStorageDirectory is where the collectors keep their persistent sending queues
+	FileStorageDirectory = "/var/lib/otelcol/file_storage"
+	fileStorageExtension = "file_storage"
+	batchProcessor       = "batch"
+)
+
+// applyDelivery tunes the pipelines and exporter of the collector with the delivery settings of the sink,
+// the settings left out keep the collector defaults
+func applyDelivery(config *OtelConfigFile, exporterName string, delivery *backend.DeliveryConfig) {
+	if delivery == nil {
+		return
+	}
+	if delivery.Batch != nil {
+		config.Processors = &Processors{
+			Batch: &BatchProcessor{
+				SendBatchSize:    delivery.Batch.SendBatchSize,
+				SendBatchMaxSize: delivery.Batch.SendBatchMaxSize,
+				Timeout:          delivery.Batch.Timeout,
+			},
+		}
+		config.Service.Pipelines.Metrics.Processors = []string{batchProcessor}
+		if config.Service.Pipelines.Traces != nil {
+			config.Service.Pipelines.Traces.Processors = []string{batchProcessor}
+		}
+	}
+
+	var retry *RetryOnFailure
+	if delivery.Retry != nil {
+		retry = &RetryOnFailure{
+			Enabled:         delivery.Retry.IsEnabled(),
+			InitialInterval: delivery.Retry.InitialInterval,
+			MaxInterval:     delivery.Retry.MaxInterval,
+			MaxElapsedTime:  delivery.Retry.MaxElapsedTime,
+		}
+	}
+	queue := delivery.SendingQueue
+	switch exporterName {
+	case "prometheusremotewrite":
+		exporter := config.Exporters.PrometheusRemoteWrite
+		exporter.RetryOnFailure = retry
+		if queue != nil {
+			exporter.RemoteWriteQueue = &RemoteWriteQueue{
+				Enabled:      queue.IsEnabled(),
+				QueueSize:    queue.QueueSize,
+				NumConsumers: queue.NumConsumers,
+			}
+		}
+	case "otlphttp":
+		config.Exporters.OTLPExporter.RetryOnFailure = retry
+		config.Exporters.OTLPExporter.SendingQueue = sendingQueue(config, queue)
+	case "kafka":
+		config.Exporters.KafkaExporter.RetryOnFailure = retry
+		config.Exporters.KafkaExporter.SendingQueue = sendingQueue(config, queue)
+	}
+}
+
+// sendingQueue returns the sending queue of the exporter, adding the file storage it persists to
+func sendingQueue(config *OtelConfigFile, queue *backend.SendingQueueConfig) *SendingQueue {
+	if queue == nil {
+		return nil
+	}
+	sendingQueue := &SendingQueue{
+		Enabled:      queue.IsEnabled(),
+		NumConsumers: queue.NumConsumers,
+		QueueSize:    queue.QueueSize,
+	}
+	if queue.Persistent {
+		sendingQueue.Storage = fileStorageExtension
+		config.Extensions.FileStorage = &FileStorageExtension{Directory: FileStorageDirectory}
+		config.Service.Extensions = append(config.Service.Extensions, fileStorageExtension)
+	}
+	return sendingQueue
+}
+
+// UsesFileStorage tells whether the collector configuration persists sending queues to FileStorageDirectory, in which
+// case the runtime keeps the directory across the restarts of the collector. The extension of a pooled collector is
+// renamed after its sink
+func UsesFileStorage(collectorConfig string) bool {
+	var config struct {
+		Extensions map[string]interface{} `yaml:"extensions"`
+	}
+	if err := yaml.Unmarshal([]byte(collectorConfig), &config); err != nil {
+		return false
+	}
+	for id := range config.Extensions {
+		if id == fileStorageExtension || strings.HasPrefix(id, fileStorageExtension+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -1,17 +1,33 @@
 package config
 
-import "github.com/orb-community/orb/pkg/types"
+import (
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/orb-community/orb/sinks/backend/kafkaexporter"
+)
 
 type ExporterConfigService interface {
 	GetExportersFromMetadata(config types.Metadata, authenticationExtensionName string) (Exporters, string)
 }
 
+// tracesExporters exporters which also accept spans, besides metrics
+var tracesExporters = map[string]bool{
+	"otlphttp": true,
+}
+
+// selfAuthenticatedExporters exporters which authenticate with the sink credentials on their own,
+// without the authentication extension
+var selfAuthenticatedExporters = map[string]bool{
+	"kafka": true,
+}
+
 func FromStrategy(backend string) ExporterConfigService {
 	switch backend {
 	case "prometheus":
 		return &PrometheusExporterConfig{}
 	case "otlphttp":
 		return &OTLPHTTPExporterBuilder{}
+	case "kafka":
+		return &KafkaExporterBuilder{}
 	}
 
 	return nil
@@ -71,3 +87,49 @@ func (O *OTLPHTTPExporterBuilder) GetExportersFromMetadata(config types.Metadata
 		}, "otlphttp"
 	}
 }
+
+type KafkaExporterBuilder struct {
+}
+
+// GetExportersFromMetadata the kafka exporter sends the basic authentication of the sink as its SASL credentials
+func (k *KafkaExporterBuilder) GetExportersFromMetadata(config types.Metadata, _ string) (Exporters, string) {
+	exporterSubMeta := config.GetSubMetadata("exporter")
+	if exporterSubMeta == nil {
+		return Exporters{}, ""
+	}
+	brokers, err := kafkaexporter.GetBrokers(exporterSubMeta)
+	if err != nil || len(brokers) == 0 {
+		return Exporters{}, ""
+	}
+	topic, ok := exporterSubMeta[kafkaexporter.TopicConfigFeature].(string)
+	if !ok {
+		return Exporters{}, ""
+	}
+	exporter := &KafkaExporterConfig{
+		Brokers:         brokers,
+		Topic:           topic,
+		Encoding:        kafkaexporter.DefaultEncoding,
+		ProtocolVersion: "2.0.0",
+		Auth:            &KafkaAuth{},
+	}
+	if encoding, ok := exporterSubMeta[kafkaexporter.EncodingConfigFeature].(string); ok {
+		exporter.Encoding = encoding
+	}
+	if compression, ok := exporterSubMeta[kafkaexporter.CompressionConfigFeature].(string); ok {
+		exporter.Producer = &KafkaProducer{Compression: compression}
+	}
+	authcfg := config.GetSubMetadata(AuthenticationKey)
+	username, _ := authcfg["username"].(string)
+	password, _ := authcfg["password"].(string)
+	mechanism, ok := exporterSubMeta[kafkaexporter.SASLMechanismConfigFeature].(string)
+	if !ok {
+		mechanism = kafkaexporter.DefaultSASLMechanism
+	}
+	exporter.Auth.SASL = &KafkaSASL{Username: username, Password: password, Mechanism: mechanism}
+	if tlsCfg, ok := exporterSubMeta[kafkaexporter.TLSConfigFeature].(map[string]interface{}); ok {
+		exporter.Auth.TLS = &KafkaTLS{}
+		exporter.Auth.TLS.InsecureSkipVerify, _ = tlsCfg[kafkaexporter.TLSInsecureSkipVerifyConfig].(bool)
+		exporter.Auth.TLS.CAPem, _ = tlsCfg[kafkaexporter.TLSCAPemConfig].(string)
+	}
+	return Exporters{KafkaExporter: exporter}, "kafka"
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/orb-community/orb/sinks/backend"
+	"github.com/orb-community/orb/sinks/backend/kafkaexporter"
+	"github.com/stretchr/testify/require"
+)
+
+// describedConfig fills every feature of the backend metadata the way a client following it would
+func describedConfig(features []backend.ConfigFeature, values map[string]interface{}) map[string]interface{} {
+	config := map[string]interface{}{}
+	for _, feature := range features {
+		switch feature.Type {
+		case backend.ConfigFeatureTypeObject:
+			config[feature.Name] = describedConfig(feature.Fields, values)
+		case backend.ConfigFeatureTypeBoolean:
+			config[feature.Name] = true
+		default:
+			config[feature.Name] = values[feature.Name]
+		}
+	}
+	return config
+}
+
+func TestKafkaExporterBuilder_DescribedConfig(t *testing.T) {
+	caPem := "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+	kafka := &kafkaexporter.KafkaBackend{}
+	exporterConfig := describedConfig(kafka.CreateFeatureConfig(), map[string]interface{}{
+		kafkaexporter.BrokersConfigFeature:       "kafka-1.acme.com:9093,kafka-2.acme.com:9093",
+		kafkaexporter.TopicConfigFeature:         "orb-metrics",
+		kafkaexporter.EncodingConfigFeature:      "otlp_json",
+		kafkaexporter.CompressionConfigFeature:   "zstd",
+		kafkaexporter.SASLMechanismConfigFeature: "SCRAM-SHA-512",
+		kafkaexporter.TLSCAPemConfig:             caPem,
+	})
+	require.NoError(t, kafka.ValidateConfiguration(exporterConfig))
+
+	config := types.Metadata{
+		"exporter":        exporterConfig,
+		AuthenticationKey: map[string]interface{}{"username": "kafka-user", "password": "kafka-pass"},
+	}
+	exporters, name := (&KafkaExporterBuilder{}).GetExportersFromMetadata(config, "")
+	require.Equal(t, "kafka", name)
+	require.NotNil(t, exporters.KafkaExporter)
+	exporter := exporters.KafkaExporter
+	require.Equal(t, []string{"kafka-1.acme.com:9093", "kafka-2.acme.com:9093"}, exporter.Brokers)
+	require.Equal(t, "orb-metrics", exporter.Topic)
+	require.Equal(t, "otlp_json", exporter.Encoding)
+	require.Equal(t, &KafkaProducer{Compression: "zstd"}, exporter.Producer)
+	require.Equal(t, &KafkaSASL{Username: "kafka-user", Password: "kafka-pass", Mechanism: "SCRAM-SHA-512"}, exporter.Auth.SASL)
+	require.Equal(t, &KafkaTLS{InsecureSkipVerify: true, CAPem: caPem}, exporter.Auth.TLS)
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/orb-community/orb/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// componentSections are the sections of the collector configuration holding components, renamed after their sink
+var componentSections = []string{"receivers", "processors", "exporters", "extensions"}
+
+// sharedExtensions run once in a pooled collector, whatever the sinks it delivers for
+var sharedExtensions = map[string]bool{"pprof": true, "health_check": true, "zpages": true}
+
+// MergeCollectorConfigs returns the configuration of a pooled collector delivering for all the sinks of the configurations,
+// keyed by sink id. Each sink keeps its own pipelines, with its components renamed after it so the sinks don't collide,
+// e.g. the kafka receiver of sink 1234 becomes kafka/1234 and its basicauth/exporter extension becomes basicauth/1234_exporter
+func MergeCollectorConfigs(configs map[string]string) (string, error) {
+	sinkIDs := make([]string, 0, len(configs))
+	for sinkID := range configs {
+		sinkIDs = append(sinkIDs, sinkID)
+	}
+	sort.Strings(sinkIDs)
+
+	merged := map[string]interface{}{}
+	var serviceExtensions []string
+	pipelines := map[string]interface{}{}
+	for _, sinkID := range sinkIDs {
+		var config map[string]interface{}
+		if err := yaml.Unmarshal([]byte(configs[sinkID]), &config); err != nil {
+			return "", errors.Wrap(errors.New(fmt.Sprintf("failed to read collector config, sink: %s", sinkID)), err)
+		}
+
+		renames := map[string]string{}
+		for _, section := range componentSections {
+			components, _ := config[section].(map[string]interface{})
+			if len(components) == 0 {
+				continue
+			}
+			mergedComponents, _ := merged[section].(map[string]interface{})
+			if mergedComponents == nil {
+				mergedComponents = map[string]interface{}{}
+				merged[section] = mergedComponents
+			}
+			for id, component := range components {
+				if section == "extensions" && sharedExtensions[id] {
+					if _, ok := mergedComponents[id]; !ok {
+						mergedComponents[id] = component
+					}
+					continue
+				}
+				renames[id] = componentID(id, sinkID)
+				mergedComponents[renames[id]] = component
+			}
+		}
+		if exporters, ok := config["exporters"].(map[string]interface{}); ok {
+			for _, exporter := range exporters {
+				renameExtensionReferences(exporter, renames)
+			}
+		}
+
+		service, _ := config["service"].(map[string]interface{})
+		if extensions, ok := service["extensions"].([]interface{}); ok {
+			for _, extension := range renameAll(extensions, renames) {
+				if !contains(serviceExtensions, extension) {
+					serviceExtensions = append(serviceExtensions, extension)
+				}
+			}
+		}
+		sinkPipelines, _ := service["pipelines"].(map[string]interface{})
+		for id, pipeline := range sinkPipelines {
+			sinkPipeline, ok := pipeline.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			renamed := map[string]interface{}{}
+			for key, value := range sinkPipeline {
+				if list, ok := value.([]interface{}); ok {
+					renamed[key] = renameAll(list, renames)
+				} else {
+					renamed[key] = value
+				}
+			}
+			pipelines[componentID(id, sinkID)] = renamed
+		}
+	}
+
+	service := map[string]interface{}{"pipelines": pipelines}
+	if len(serviceExtensions) > 0 {
+		service["extensions"] = serviceExtensions
+	}
+	merged["service"] = service
+	marshal, err := yaml.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+	return "---\n" + string(marshal), nil
+}
+
+// componentID names the component, or pipeline, after the sink, keeping its former name as a suffix
+func componentID(id string, sinkID string) string {
+	componentType, name, found := strings.Cut(id, "/")
+	if found {
+		return fmt.Sprintf("%s/%s_%s", componentType, sinkID, name)
+	}
+	return fmt.Sprintf("%s/%s", componentType, sinkID)
+}
+
+// renameExtensionReferences points the exporter to the renamed authentication and storage extensions of its sink
+func renameExtensionReferences(exporter interface{}, renames map[string]string) {
+	config, ok := exporter.(map[string]interface{})
+	if !ok {
+		return
+	}
+	renameReference(config, "auth", "authenticator", renames)
+	renameReference(config, "sending_queue", "storage", renames)
+}
+
+func renameReference(config map[string]interface{}, section string, key string, renames map[string]string) {
+	settings, ok := config[section].(map[string]interface{})
+	if !ok {
+		return
+	}
+	if id, ok := settings[key].(string); ok && renames[id] != "" {
+		settings[key] = renames[id]
+	}
+}
+
+func renameAll(ids []interface{}, renames map[string]string) []string {
+	renamed := make([]string, 0, len(ids))
+	for _, id := range ids {
+		name := fmt.Sprint(id)
+		if renames[name] != "" {
+			name = renames[name]
+		}
+		renamed = append(renamed, name)
+	}
+	return renamed
+}
+
+func contains(ids []string, id string) bool {
+	for _, existing := range ids {
+		if existing == id {
+			return true
+		}
+	}
+	return false
+}
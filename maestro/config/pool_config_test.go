@@ -0,0 +1,78 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	basicAuthSinkConfig = "---\nreceivers:\n  kafka:\n    brokers:\n    - kafka:9092\n    topic: otlp_metrics-sink-1\n    protocol_version: 2.0.0\nextensions:\n  pprof:\n    endpoint: 0.0.0.0:1888\n  basicauth/exporter:\n    client_auth:\n      username: prom-user\n      password: dbpass\nexporters:\n  prometheusremotewrite:\n    endpoint: https://acme.com/prom/push\n    auth:\n      authenticator: basicauth/exporter\nservice:\n  extensions:\n  - pprof\n  - basicauth/exporter\n  pipelines:\n    metrics:\n      receivers:\n      - kafka\n      exporters:\n      - prometheusremotewrite\n"
+	deliverySinkConfig  = "---\nreceivers:\n  kafka:\n    topic: otlp_metrics-sink-3\nprocessors:\n  batch:\n    send_batch_size: 500\nextensions:\n  file_storage:\n    directory: /var/lib/otelcol/file_storage\nexporters:\n  otlphttp:\n    endpoint: https://acme.com/otlp\n    sending_queue:\n      enabled: true\n      storage: file_storage\nservice:\n  extensions:\n  - file_storage\n  pipelines:\n    metrics:\n      receivers:\n      - kafka\n      processors:\n      - batch\n      exporters:\n      - otlphttp\n"
+	tracesSinkConfig    = "---\nreceivers:\n  kafka:\n    brokers:\n    - kafka:9092\n    topic: otlp_metrics-sink-2\n    protocol_version: 2.0.0\n  kafka/traces:\n    brokers:\n    - kafka:9092\n    topic: otlp_traces-sink-2\n    protocol_version: 2.0.0\nextensions:\n  pprof:\n    endpoint: 0.0.0.0:1888\n  bearertokenauth/withscheme:\n    scheme: Bearer\n    token: secret\nexporters:\n  otlphttp:\n    endpoint: https://acme.com/otlp\n    auth:\n      authenticator: bearertokenauth/withscheme\nservice:\n  extensions:\n  - pprof\n  - bearertokenauth/withscheme\n  pipelines:\n    metrics:\n      receivers:\n      - kafka\n      exporters:\n      - otlphttp\n    traces:\n      receivers:\n      - kafka/traces\n      exporters:\n      - otlphttp\n"
+)
+
+func TestMergeCollectorConfigs(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs map[string]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "single sink",
+			configs: map[string]string{"sink-1": basicAuthSinkConfig},
+			want:    "---\nexporters:\n    prometheusremotewrite/sink-1:\n        auth:\n            authenticator: basicauth/sink-1_exporter\n        endpoint: https://acme.com/prom/push\nextensions:\n    basicauth/sink-1_exporter:\n        client_auth:\n            password: dbpass\n            username: prom-user\n    pprof:\n        endpoint: 0.0.0.0:1888\nreceivers:\n    kafka/sink-1:\n        brokers:\n            - kafka:9092\n        protocol_version: 2.0.0\n        topic: otlp_metrics-sink-1\nservice:\n    extensions:\n        - pprof\n        - basicauth/sink-1_exporter\n    pipelines:\n        metrics/sink-1:\n            exporters:\n                - prometheusremotewrite/sink-1\n            receivers:\n                - kafka/sink-1\n",
+		},
+		{
+			name:    "sinks with metrics and traces pipelines",
+			configs: map[string]string{"sink-1": basicAuthSinkConfig, "sink-2": tracesSinkConfig},
+			want:    "---\nexporters:\n    otlphttp/sink-2:\n        auth:\n            authenticator: bearertokenauth/sink-2_withscheme\n        endpoint: https://acme.com/otlp\n    prometheusremotewrite/sink-1:\n        auth:\n            authenticator: basicauth/sink-1_exporter\n        endpoint: https://acme.com/prom/push\nextensions:\n    basicauth/sink-1_exporter:\n        client_auth:\n            password: dbpass\n            username: prom-user\n    bearertokenauth/sink-2_withscheme:\n        scheme: Bearer\n        token: secret\n    pprof:\n        endpoint: 0.0.0.0:1888\nreceivers:\n    kafka/sink-1:\n        brokers:\n            - kafka:9092\n        protocol_version: 2.0.0\n        topic: otlp_metrics-sink-1\n    kafka/sink-2:\n        brokers:\n            - kafka:9092\n        protocol_version: 2.0.0\n        topic: otlp_metrics-sink-2\n    kafka/sink-2_traces:\n        brokers:\n            - kafka:9092\n        protocol_version: 2.0.0\n        topic: otlp_traces-sink-2\nservice:\n    extensions:\n        - pprof\n        - basicauth/sink-1_exporter\n        - bearertokenauth/sink-2_withscheme\n    pipelines:\n        metrics/sink-1:\n            exporters:\n                - prometheusremotewrite/sink-1\n            receivers:\n                - kafka/sink-1\n        metrics/sink-2:\n            exporters:\n                - otlphttp/sink-2\n            receivers:\n                - kafka/sink-2\n        traces/sink-2:\n            exporters:\n                - otlphttp/sink-2\n            receivers:\n                - kafka/sink-2_traces\n",
+		},
+		{
+			name:    "sink with batching and persistent sending queue",
+			configs: map[string]string{"sink-3": deliverySinkConfig},
+			want:    "---\nexporters:\n    otlphttp/sink-3:\n        endpoint: https://acme.com/otlp\n        sending_queue:\n            enabled: true\n            storage: file_storage/sink-3\nextensions:\n    file_storage/sink-3:\n        directory: /var/lib/otelcol/file_storage\nprocessors:\n    batch/sink-3:\n        send_batch_size: 500\nreceivers:\n    kafka/sink-3:\n        topic: otlp_metrics-sink-3\nservice:\n    extensions:\n        - file_storage/sink-3\n    pipelines:\n        metrics/sink-3:\n            exporters:\n                - otlphttp/sink-3\n            processors:\n                - batch/sink-3\n            receivers:\n                - kafka/sink-3\n",
+		},
+		{
+			name:    "invalid sink config",
+			configs: map[string]string{"sink-1": basicAuthSinkConfig, "sink-2": "receivers: ["},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergeCollectorConfigs(tt.configs)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			var parsed map[string]interface{}
+			require.NoError(t, yaml.Unmarshal([]byte(got), &parsed))
+		})
+	}
+}
+
+func TestUsesFileStorage(t *testing.T) {
+	pooled, err := MergeCollectorConfigs(map[string]string{"sink-1": basicAuthSinkConfig, "sink-3": deliverySinkConfig})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		config string
+		want   bool
+	}{
+		{name: "sink without persistent queue", config: basicAuthSinkConfig, want: false},
+		{name: "sink with persistent queue", config: deliverySinkConfig, want: true},
+		{name: "pooled collector with a persistent queue", config: pooled, want: true},
+		{name: "invalid config", config: "extensions: [", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, UsesFileStorage(tt.config))
+		})
+	}
+}
@@ -7,7 +7,7 @@ import (
 )
 
 type ConfigBuilder interface {
-	BuildDeploymentConfig(deployment *DeploymentRequest) (string, error)
+	BuildCollectorConfig(deployment *DeploymentRequest) (string, error)
 }
 
 type DeploymentRequest struct {
@@ -66,7 +66,8 @@ type OtelConfigFile struct {
 
 // Receivers will receive only with Kafka for now
 type Receivers struct {
-	Kafka KafkaReceiver `json:"kafka" yaml:"kafka"`
+	Kafka       KafkaReceiver  `json:"kafka" yaml:"kafka"`
+	KafkaTraces *KafkaReceiver `json:"kafka/traces,omitempty" yaml:"kafka/traces,omitempty"`
 }
 
 type KafkaReceiver struct {
@@ -76,6 +77,13 @@ type KafkaReceiver struct {
 }
 
 type Processors struct {
+	Batch *BatchProcessor `json:"batch,omitempty" yaml:"batch,omitempty"`
+}
+
+type BatchProcessor struct {
+	SendBatchSize    int    `json:"send_batch_size,omitempty" yaml:"send_batch_size,omitempty"`
+	SendBatchMaxSize int    `json:"send_batch_max_size,omitempty" yaml:"send_batch_max_size,omitempty"`
+	Timeout          string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 }
 
 type Extensions struct {
@@ -85,6 +93,12 @@ type Extensions struct {
 	// Exporters Authentication
 	BasicAuth  *BasicAuthenticationExtension `json:"basicauth/exporter,omitempty" yaml:"basicauth/exporter,omitempty" :"basic_auth"`
 	BearerAuth *BearerTokenAuthExtension     `json:"bearertokenauth/withscheme,omitempty" yaml:"bearertokenauth/withscheme,omitempty"`
+	// Persistent sending queue
+	FileStorage *FileStorageExtension `json:"file_storage,omitempty" yaml:"file_storage,omitempty"`
+}
+
+type FileStorageExtension struct {
+	Directory string `json:"directory" yaml:"directory"`
 }
 
 type HealthCheckExtension struct {
@@ -125,6 +139,39 @@ type Exporters struct {
 	PrometheusRemoteWrite *PrometheusRemoteWriteExporterConfig `json:"prometheusremotewrite,omitempty" yaml:"prometheusremotewrite,omitempty"`
 	OTLPExporter          *OTLPExporterConfig                  `json:"otlphttp,omitempty" yaml:"otlphttp,omitempty"`
 	LoggingExporter       *LoggingExporterConfig               `json:"logging,omitempty" yaml:"logging,omitempty"`
+	KafkaExporter         *KafkaExporterConfig                 `json:"kafka,omitempty" yaml:"kafka,omitempty"`
+}
+
+type KafkaExporterConfig struct {
+	Brokers         []string        `json:"brokers" yaml:"brokers"`
+	Topic           string          `json:"topic" yaml:"topic"`
+	Encoding        string          `json:"encoding" yaml:"encoding"`
+	ProtocolVersion string          `json:"protocol_version" yaml:"protocol_version"`
+	Producer        *KafkaProducer  `json:"producer,omitempty" yaml:"producer,omitempty"`
+	Auth            *KafkaAuth      `json:"auth,omitempty" yaml:"auth,omitempty"`
+	RetryOnFailure  *RetryOnFailure `json:"retry_on_failure,omitempty" yaml:"retry_on_failure,omitempty"`
+	SendingQueue    *SendingQueue   `json:"sending_queue,omitempty" yaml:"sending_queue,omitempty"`
+}
+
+type KafkaProducer struct {
+	Compression string `json:"compression,omitempty" yaml:"compression,omitempty"`
+}
+
+type KafkaAuth struct {
+	SASL *KafkaSASL `json:"sasl,omitempty" yaml:"sasl,omitempty"`
+	TLS  *KafkaTLS  `json:"tls,omitempty" yaml:"tls,omitempty"`
+}
+
+type KafkaSASL struct {
+	Username  string `json:"username" yaml:"username"`
+	Password  string `json:"password" yaml:"password"`
+	Mechanism string `json:"mechanism" yaml:"mechanism"`
+}
+
+type KafkaTLS struct {
+	Insecure           bool   `json:"insecure" yaml:"insecure"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty" yaml:"insecure_skip_verify,omitempty"`
+	CAPem              string `json:"ca_pem,omitempty" yaml:"ca_pem,omitempty"`
 }
 
 type LoggingExporterConfig struct {
@@ -139,6 +186,29 @@ type OTLPExporterConfig struct {
 	Auth     struct {
 		Authenticator string `json:"authenticator" yaml:"authenticator"`
 	}
+	RetryOnFailure *RetryOnFailure `json:"retry_on_failure,omitempty" yaml:"retry_on_failure,omitempty"`
+	SendingQueue   *SendingQueue   `json:"sending_queue,omitempty" yaml:"sending_queue,omitempty"`
+}
+
+type RetryOnFailure struct {
+	Enabled         bool   `json:"enabled" yaml:"enabled"`
+	InitialInterval string `json:"initial_interval,omitempty" yaml:"initial_interval,omitempty"`
+	MaxInterval     string `json:"max_interval,omitempty" yaml:"max_interval,omitempty"`
+	MaxElapsedTime  string `json:"max_elapsed_time,omitempty" yaml:"max_elapsed_time,omitempty"`
+}
+
+type SendingQueue struct {
+	Enabled      bool   `json:"enabled" yaml:"enabled"`
+	NumConsumers int    `json:"num_consumers,omitempty" yaml:"num_consumers,omitempty"`
+	QueueSize    int    `json:"queue_size,omitempty" yaml:"queue_size,omitempty"`
+	Storage      string `json:"storage,omitempty" yaml:"storage,omitempty"`
+}
+
+// RemoteWriteQueue is the in memory sending queue of the prometheus remote write exporter
+type RemoteWriteQueue struct {
+	Enabled      bool `json:"enabled" yaml:"enabled"`
+	QueueSize    int  `json:"queue_size,omitempty" yaml:"queue_size,omitempty"`
+	NumConsumers int  `json:"num_consumers,omitempty" yaml:"num_consumers,omitempty"`
 }
 
 type Auth struct {
@@ -151,15 +221,22 @@ type PrometheusRemoteWriteExporterConfig struct {
 	Auth     struct {
 		Authenticator string `json:"authenticator" yaml:"authenticator"`
 	}
+	RetryOnFailure   *RetryOnFailure   `json:"retry_on_failure,omitempty" yaml:"retry_on_failure,omitempty"`
+	RemoteWriteQueue *RemoteWriteQueue `json:"remote_write_queue,omitempty" yaml:"remote_write_queue,omitempty"`
 }
 
 type ServiceConfig struct {
-	Extensions []string `json:"extensions,omitempty" yaml:"extensions,omitempty"`
-	Pipelines  struct {
-		Metrics struct {
-			Receivers  []string `json:"receivers" yaml:"receivers"`
-			Processors []string `json:"processors,omitempty" yaml:"processors,omitempty"`
-			Exporters  []string `json:"exporters" yaml:"exporters"`
-		} `json:"metrics" yaml:"metrics"`
-	} `json:"pipelines" yaml:"pipelines"`
+	Extensions []string  `json:"extensions,omitempty" yaml:"extensions,omitempty"`
+	Pipelines  Pipelines `json:"pipelines" yaml:"pipelines"`
+}
+
+type Pipelines struct {
+	Metrics Pipeline  `json:"metrics" yaml:"metrics"`
+	Traces  *Pipeline `json:"traces,omitempty" yaml:"traces,omitempty"`
+}
+
+type Pipeline struct {
+	Receivers  []string `json:"receivers" yaml:"receivers"`
+	Processors []string `json:"processors,omitempty" yaml:"processors,omitempty"`
+	Exporters  []string `json:"exporters" yaml:"exporters"`
 }
@@ -0,0 +1,37 @@
+package deployment
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// DeliveryStats is the delivery of the sink metrics by its collector, scraped from the exporter self-metrics
+type DeliveryStats struct {
+	// SentItems and FailedItems count the items the exporter sent and failed to send since the collector started
+	SentItems   int64 `json:"sent_items"`
+	FailedItems int64 `json:"failed_items"`
+	// QueueSize and QueueCapacity are the batches waiting in the exporter sending queue and how many it holds
+	QueueSize     int64 `json:"queue_size"`
+	QueueCapacity int64 `json:"queue_capacity"`
+	// ItemsPerSecond and FailureRatio measure the delivery since the previous scrape
+	ItemsPerSecond float64   `json:"items_per_second"`
+	FailureRatio   float64   `json:"failure_ratio"`
+	ScrapedAt      time.Time `json:"scraped_at"`
+}
+
+func (s *DeliveryStats) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan delivery stats")
+	}
+	return json.Unmarshal(b, s)
+}
+
+func (s DeliveryStats) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
@@ -8,18 +8,19 @@ import (
 )
 
 type Deployment struct {
-	Id                      string     `db:"id" json:"id,omitempty"`
-	OwnerID                 string     `db:"owner_id" json:"ownerID,omitempty"`
-	SinkID                  string     `db:"sink_id" json:"sinkID,omitempty"`
-	Backend                 string     `db:"backend" json:"backend,omitempty"`
-	Config                  []byte     `db:"config" json:"config,omitempty"`
-	LastStatus              string     `db:"last_status" json:"lastStatus,omitempty"`
-	LastStatusUpdate        *time.Time `db:"last_status_update" json:"lastStatusUpdate"`
-	LastErrorMessage        string     `db:"last_error_message" json:"lastErrorMessage,omitempty"`
-	LastErrorTime           *time.Time `db:"last_error_time" json:"lastErrorTime"`
-	CollectorName           string     `db:"collector_name" json:"collectorName,omitempty"`
-	LastCollectorDeployTime *time.Time `db:"last_collector_deploy_time" json:"lastCollectorDeployTime"`
-	LastCollectorStopTime   *time.Time `db:"last_collector_stop_time" json:"lastCollectorStopTime"`
+	Id                      string         `db:"id" json:"id,omitempty"`
+	OwnerID                 string         `db:"owner_id" json:"ownerID,omitempty"`
+	SinkID                  string         `db:"sink_id" json:"sinkID,omitempty"`
+	Backend                 string         `db:"backend" json:"backend,omitempty"`
+	Config                  []byte         `db:"config" json:"config,omitempty"`
+	LastStatus              string         `db:"last_status" json:"lastStatus,omitempty"`
+	LastStatusUpdate        *time.Time     `db:"last_status_update" json:"lastStatusUpdate"`
+	LastErrorMessage        string         `db:"last_error_message" json:"lastErrorMessage,omitempty"`
+	LastErrorTime           *time.Time     `db:"last_error_time" json:"lastErrorTime"`
+	CollectorName           string         `db:"collector_name" json:"collectorName,omitempty"`
+	LastCollectorDeployTime *time.Time     `db:"last_collector_deploy_time" json:"lastCollectorDeployTime"`
+	LastCollectorStopTime   *time.Time     `db:"last_collector_stop_time" json:"lastCollectorStopTime"`
+	DeliveryStats           *DeliveryStats `db:"delivery_stats" json:"deliveryStats,omitempty"`
 }
 
 func NewDeployment(ownerID string, sinkID string, config types.Metadata, backend string) Deployment {
@@ -50,6 +51,9 @@ func (d *Deployment) Merge(other Deployment) error {
 		d.LastCollectorDeployTime = other.LastCollectorDeployTime
 		d.LastCollectorStopTime = other.LastCollectorStopTime
 	}
+	if other.DeliveryStats != nil {
+		d.DeliveryStats = other.DeliveryStats
+	}
 	if other.LastStatus != d.LastStatus {
 		d.LastStatus = other.LastStatus
 		d.LastStatusUpdate = other.LastStatusUpdate
@@ -57,6 +61,14 @@ func (d *Deployment) Merge(other Deployment) error {
 	return nil
 }
 
+// IsCollectorRunning tells whether the collector was deployed since it was last stopped
+func (d *Deployment) IsCollectorRunning() bool {
+	if d.LastCollectorDeployTime == nil {
+		return false
+	}
+	return d.LastCollectorStopTime == nil || d.LastCollectorStopTime.Before(*d.LastCollectorDeployTime)
+}
+
 func (d *Deployment) GetConfig() types.Metadata {
 	var config types.Metadata
 	err := json.Unmarshal(d.Config, &config)
@@ -17,6 +17,7 @@ type Repository interface {
 	Add(ctx context.Context, deployment *Deployment) (*Deployment, error)
 	Update(ctx context.Context, deployment *Deployment) (*Deployment, error)
 	UpdateStatus(ctx context.Context, ownerID string, sinkId string, status string, errorMessage string) error
+	UpdateDeliveryStats(ctx context.Context, ownerID string, sinkId string, stats DeliveryStats) error
 	Remove(ctx context.Context, ownerId string, sinkId string) error
 	FindByOwnerAndSink(ctx context.Context, ownerId string, sinkId string) (*Deployment, error)
 	FindByCollectorName(ctx context.Context, collectorName string) (*Deployment, error)
@@ -49,7 +50,8 @@ func (r *repositoryService) FetchAll(ctx context.Context) ([]Deployment, error)
 		   last_error_time,
 		   collector_name,
 		   last_collector_deploy_time,
-		   last_collector_stop_time
+		   last_collector_stop_time,
+		   delivery_stats
 	FROM deployments`
 	err := tx.SelectContext(ctx, &deployments, query, nil)
 	if err != nil {
@@ -69,9 +71,9 @@ func (r *repositoryService) Add(ctx context.Context, deployment *Deployment) (*D
 	tx := r.db.MustBeginTx(ctx, nil)
 	_, err := tx.NamedExecContext(ctx,
 		`INSERT INTO deployments (owner_id, sink_id, backend, config, last_status, last_status_update, last_error_message, 
-				last_error_time, collector_name, last_collector_deploy_time, last_collector_stop_time) 
+				last_error_time, collector_name, last_collector_deploy_time, last_collector_stop_time, delivery_stats) 
 				VALUES (:owner_id, :sink_id, :backend, :config, :last_status, :last_status_update, :last_error_message, 
-				        :last_error_time, :collector_name, :last_collector_deploy_time, :last_collector_stop_time)`,
+				        :last_error_time, :collector_name, :last_collector_deploy_time, :last_collector_stop_time, :delivery_stats)`,
 		deployment)
 	if err != nil {
 		_ = tx.Rollback()
@@ -106,7 +108,8 @@ func (r *repositoryService) Update(ctx context.Context, deployment *Deployment)
 					   last_error_time = :last_error_time, 
 					   collector_name = :collector_name, 
 					   last_collector_deploy_time = :last_collector_deploy_time, 
-					   last_collector_stop_time = :last_collector_stop_time 
+					   last_collector_stop_time = :last_collector_stop_time, 
+					   delivery_stats = :delivery_stats 
 				WHERE id = :id`,
 		deployment)
 	if err != nil {
@@ -145,6 +148,29 @@ func (r *repositoryService) UpdateStatus(ctx context.Context, ownerID string, si
 	return tx.Commit()
 }
 
+// UpdateDeliveryStats only writes the delivery stats, leaving the config and the status of the deployment untouched
+func (r *repositoryService) UpdateDeliveryStats(ctx context.Context, ownerID string, sinkId string, stats DeliveryStats) error {
+	fields := map[string]interface{}{
+		"delivery_stats": stats,
+		"owner_id":       ownerID,
+		"sink_id":        sinkId,
+	}
+	res, err := r.db.NamedExecContext(ctx,
+		`UPDATE deployments SET delivery_stats = :delivery_stats WHERE owner_id = :owner_id AND sink_id = :sink_id`,
+		fields)
+	if err != nil {
+		return err
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if cnt == 0 {
+		return maestroerrors.NotFound
+	}
+	return nil
+}
+
 func (r *repositoryService) Remove(ctx context.Context, ownerId string, sinkId string) error {
 	tx := r.db.MustBeginTx(ctx, nil)
 	tx.MustExecContext(ctx, "DELETE FROM deployments WHERE owner_id = $1 AND sink_id = $2", ownerId, sinkId)
@@ -152,6 +152,7 @@ func Test_repositoryService_AddUpdateRemove(t *testing.T) {
 					CollectorName:           "",
 					LastCollectorDeployTime: &now,
 					LastCollectorStopTime:   &now,
+					DeliveryStats:           &DeliveryStats{SentItems: 1200, FailedItems: 30, ScrapedAt: now},
 				},
 			},
 			want: &Deployment{
@@ -174,6 +175,7 @@ func Test_repositoryService_AddUpdateRemove(t *testing.T) {
 				CollectorName:           "",
 				LastCollectorDeployTime: &now,
 				LastCollectorStopTime:   &now,
+				DeliveryStats:           &DeliveryStats{SentItems: 1200, FailedItems: 30, ScrapedAt: now},
 			},
 		},
 	}
@@ -209,9 +211,46 @@ func Test_repositoryService_AddUpdateRemove(t *testing.T) {
 			require.NoError(t, err)
 			require.Equal(t, wantInterface, gotInterface)
 
+			found, err := r.FindByOwnerAndSink(ctx, tt.want.OwnerID, tt.want.SinkID)
+			require.NoError(t, err)
+			require.NotNil(t, found.DeliveryStats)
+			require.Equal(t, tt.want.DeliveryStats.SentItems, found.DeliveryStats.SentItems)
+			require.Equal(t, tt.want.DeliveryStats.FailedItems, found.DeliveryStats.FailedItems)
+
 			if err := r.Remove(ctx, tt.want.OwnerID, tt.want.SinkID); (err != nil) != tt.wantErr {
 				t.Errorf("UpdateStatus() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func Test_repositoryService_UpdateDeliveryStats(t *testing.T) {
+	now := time.Now()
+	deployCreate := &Deployment{
+		OwnerID:          "owner-20",
+		SinkID:           "sink-20",
+		Backend:          "prometheus",
+		Config:           []byte(`{"exporter": {"remote_host": "http://localhost:9090"}}`),
+		LastStatus:       "active",
+		LastStatusUpdate: &now,
+	}
+	r := &repositoryService{
+		logger: zap.NewNop(),
+		db:     pg,
+	}
+	_, err := r.Add(context.Background(), deployCreate)
+	require.NoError(t, err)
+
+	err = r.UpdateDeliveryStats(context.Background(), "owner-20", "sink-20", DeliveryStats{SentItems: 500, FailedItems: 5, ScrapedAt: now})
+	require.NoError(t, err)
+
+	found, err := r.FindByOwnerAndSink(context.Background(), "owner-20", "sink-20")
+	require.NoError(t, err)
+	require.NotNil(t, found.DeliveryStats)
+	require.Equal(t, int64(500), found.DeliveryStats.SentItems)
+	require.Equal(t, int64(5), found.DeliveryStats.FailedItems)
+	require.Equal(t, "active", found.LastStatus)
+
+	err = r.UpdateDeliveryStats(context.Background(), "owner-21", "sink-21", DeliveryStats{})
+	require.True(t, errors.Is(err, maestroerrors.NotFound))
+}
@@ -19,16 +19,20 @@ const AuthenticationKey = "authentication"
 type Service interface {
 	// CreateDeployment to be used to create the deployment when there is a sink.create
 	CreateDeployment(ctx context.Context, deployment *Deployment) error
-	// GetDeployment to be used to get the deployment information for creating the collector or monitoring the collector
+	// GetDeployment to be used to get the deployment information and the otel collector configuration for creating the collector or monitoring the collector
 	GetDeployment(ctx context.Context, ownerID string, sinkId string) (*Deployment, string, error)
 	// UpdateDeployment to be used to update the deployment when there is a sink.update
 	UpdateDeployment(ctx context.Context, deployment *Deployment) error
 	// UpdateStatus to be used to update the status of the sink, when there is an error or when the sink is running
 	UpdateStatus(ctx context.Context, ownerID string, sinkId string, status string, errorMessage string) error
+	// UpdateDeliveryStats to be used by the monitor to store the delivery stats of the collector along with the status it derived
+	UpdateDeliveryStats(ctx context.Context, ownerID string, sinkId string, status string, errorMessage string, stats DeliveryStats) error
 	// RemoveDeployment to be used to remove the deployment when there is a sink.delete
 	RemoveDeployment(ctx context.Context, ownerID string, sinkId string) error
 	// GetDeploymentByCollectorName to be used to get the deployment information for creating the collector or monitoring the collector
 	GetDeploymentByCollectorName(ctx context.Context, collectorName string) (*Deployment, error)
+	// RestoreCollectors to be used when maestro starts, to restore the collectors of the deployments left running
+	RestoreCollectors(ctx context.Context) error
 	// NotifyCollector add collector information to deployment
 	NotifyCollector(ctx context.Context, ownerID string, sinkId string, operation string, status string, errorMessage string) (string, error)
 }
@@ -107,32 +111,64 @@ func (d *deploymentService) GetDeployment(ctx context.Context, ownerID string, s
 	if err != nil {
 		return nil, "", err
 	}
+	collectorConfig, err := d.buildCollectorConfig(deployment)
+	if err != nil {
+		return nil, "", err
+	}
+	return deployment, collectorConfig, nil
+}
+
+// buildCollectorConfig decodes the authentication of the deployment config, and returns the otel collector configuration of the deployment
+func (d *deploymentService) buildCollectorConfig(deployment *Deployment) (string, error) {
 	authType := deployment.GetConfig()
 	if authType == nil {
-		return nil, "", errors.New("deployment do not have authentication information")
+		return "", errors.New("deployment do not have authentication information")
 	}
 	value := authType.GetSubMetadata(AuthenticationKey)["type"].(string)
 	authBuilder := d.getAuthBuilder(value)
 	decodedDeployment, err := authBuilder.DecodeAuth(deployment.GetConfig())
 	if err != nil {
-		return nil, "", err
+		return "", err
 	}
 	err = deployment.SetConfig(decodedDeployment)
 	if err != nil {
-		return nil, "", err
+		return "", err
 	}
 	deployReq := &config.DeploymentRequest{
-		OwnerID: ownerID,
-		SinkID:  sinkId,
+		OwnerID: deployment.OwnerID,
+		SinkID:  deployment.SinkID,
 		Config:  deployment.GetConfig(),
 		Backend: deployment.Backend,
 		Status:  deployment.LastStatus,
 	}
-	manifest, err := d.configBuilder.BuildDeploymentConfig(deployReq)
+	return d.configBuilder.BuildCollectorConfig(deployReq)
+}
+
+// RestoreCollectors hands the collectors of the deployments left running to the collector runtime
+func (d *deploymentService) RestoreCollectors(ctx context.Context) error {
+	deployments, err := d.dbRepository.FetchAll(ctx)
 	if err != nil {
-		return nil, "", err
+		return err
 	}
-	return deployment, manifest, nil
+	var collectors []kubecontrol.Collector
+	for i := range deployments {
+		deployment := &deployments[i]
+		if !deployment.IsCollectorRunning() {
+			continue
+		}
+		collectorConfig, err := d.buildCollectorConfig(deployment)
+		if err != nil {
+			d.logger.Warn("could not build collector config, skipping", zap.String("ownerID", deployment.OwnerID),
+				zap.String("sinkID", deployment.SinkID), zap.Error(err))
+			continue
+		}
+		collectors = append(collectors, kubecontrol.Collector{
+			OwnerID: deployment.OwnerID,
+			SinkID:  deployment.SinkID,
+			Config:  collectorConfig,
+		})
+	}
+	return d.kubecontrol.RestoreOtelCollectors(ctx, collectors)
 }
 
 // UpdateDeployment will stop the running collector if any, and change the deployment, it will not spin the collector back up,
@@ -177,7 +213,7 @@ func (d *deploymentService) UpdateDeployment(ctx context.Context, deployment *De
 
 func (d *deploymentService) NotifyCollector(ctx context.Context, ownerID string, sinkId string, operation string,
 	status string, errorMessage string) (string, error) {
-	got, manifest, err := d.GetDeployment(ctx, ownerID, sinkId)
+	got, collectorConfig, err := d.GetDeployment(ctx, ownerID, sinkId)
 	if err != nil {
 		return "", errors.New("could not find deployment to update")
 	}
@@ -193,8 +229,13 @@ func (d *deploymentService) NotifyCollector(ctx context.Context, ownerID string,
 		if got.LastCollectorDeployTime == nil || got.LastCollectorDeployTime.Before(now) {
 			if got.LastCollectorStopTime == nil || got.LastCollectorStopTime.Before(now) {
 				d.logger.Debug("collector is not running deploying")
-				got.CollectorName, err = d.kubecontrol.CreateOtelCollector(ctx, got.OwnerID, got.SinkID, manifest)
-				got.LastCollectorDeployTime = &now
+				collectorName, err := d.kubecontrol.CreateOtelCollector(ctx, got.OwnerID, got.SinkID, collectorConfig)
+				if err != nil {
+					d.logger.Error("could not deploy collector", zap.Error(err))
+				} else {
+					got.CollectorName = collectorName
+					got.LastCollectorDeployTime = &now
+				}
 			} else {
 				d.logger.Info("collector is already running")
 			}
@@ -264,6 +305,45 @@ func (d *deploymentService) UpdateStatus(ctx context.Context, ownerID string, si
 	return nil
 }
 
+// UpdateDeliveryStats this will store the delivery stats in postgres and notify sinks service to show them to user, the status
+// is only stored and sent along when it changed, an empty status keeps the current one
+func (d *deploymentService) UpdateDeliveryStats(ctx context.Context, ownerID string, sinkId string, status string, errorMessage string, stats DeliveryStats) error {
+	// the collector config is not needed here, so the deployment is read as stored instead of through GetDeployment
+	got, err := d.dbRepository.FindByOwnerAndSink(ctx, ownerID, sinkId)
+	if err != nil {
+		return fmt.Errorf("could not find deployment to update delivery stats: %w", err)
+	}
+	if status == "" || (status == got.LastStatus && errorMessage == got.LastErrorMessage) {
+		err = d.dbRepository.UpdateDeliveryStats(ctx, ownerID, sinkId, stats)
+		if err != nil {
+			return err
+		}
+		d.logger.Debug("updated deployment delivery stats",
+			zap.String("ownerID", ownerID), zap.String("sinkID", sinkId), zap.Any("deliveryStats", stats))
+		return d.maestroProducer.PublishSinkDeliveryStats(ctx, ownerID, sinkId, "", "", stats)
+	}
+
+	now := time.Now()
+	if status != got.LastStatus {
+		got.LastStatusUpdate = &now
+	}
+	got.LastStatus = status
+	got.LastErrorMessage = errorMessage
+	if errorMessage != "" {
+		got.LastErrorTime = &now
+	}
+	got.DeliveryStats = &stats
+	updated, err := d.dbRepository.Update(ctx, got)
+	if err != nil {
+		return err
+	}
+	d.logger.Info("updated deployment status and delivery stats",
+		zap.String("ownerID", updated.OwnerID), zap.String("sinkID", updated.SinkID),
+		zap.String("status", updated.LastStatus), zap.Any("deliveryStats", stats))
+	return d.maestroProducer.PublishSinkDeliveryStats(ctx, updated.OwnerID, updated.SinkID, updated.LastStatus,
+		updated.LastErrorMessage, stats)
+}
+
 // RemoveDeployment this will remove the deployment from postgres and redis
 func (d *deploymentService) RemoveDeployment(ctx context.Context, ownerID string, sinkId string) error {
 	err := d.dbRepository.Remove(ctx, ownerID, sinkId)
@@ -1,29 +1,19 @@
 package kubecontrol
 
 import (
-	"bufio"
 	"context"
 	"fmt"
-	"os"
-	"os/exec"
-	"strings"
 
 	_ "github.com/orb-community/orb/maestro/config"
 	"github.com/orb-community/orb/pkg/errors"
 	"go.uber.org/zap"
-	k8sappsv1 "k8s.io/api/apps/v1"
-	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
 )
 
-const namespace = "otelcollectors"
-
 var _ Service = (*deployService)(nil)
 
 type deployService struct {
-	logger    *zap.Logger
-	clientSet *kubernetes.Clientset
+	logger  *zap.Logger
+	runtime CollectorRuntime
 }
 
 const OperationDeploy CollectorOperation = iota
@@ -42,138 +32,51 @@ func (o CollectorOperation) Name() string {
 	}
 }
 
-func NewService(logger *zap.Logger) Service {
-	clusterConfig, err := rest.InClusterConfig()
-	if err != nil {
-		logger.Error("error on get cluster config", zap.Error(err))
-		return nil
-	}
-	clientSet, err := kubernetes.NewForConfig(clusterConfig)
-	if err != nil {
-		logger.Error("error on get client", zap.Error(err))
-		return nil
-	}
-	return &deployService{logger: logger, clientSet: clientSet}
+// NewService returns the service deploying the otel collectors of the sinks on the given runtime
+func NewService(logger *zap.Logger, runtime CollectorRuntime) Service {
+	return &deployService{logger: logger, runtime: runtime}
 }
 
 type Service interface {
-	// CreateOtelCollector - create an existing collector by id
-	CreateOtelCollector(ctx context.Context, ownerID, sinkID, deploymentEntry string) (string, error)
+	// CreateOtelCollector - create, or update, the collector of the sink running the given otel collector configuration
+	CreateOtelCollector(ctx context.Context, ownerID, sinkID, collectorConfig string) (string, error)
 
 	// KillOtelCollector - kill an existing collector by id, terminating by the ownerID, sinkID without the file
 	KillOtelCollector(ctx context.Context, deploymentName, sinkID string) error
-}
-
-func (svc *deployService) collectorDeploy(ctx context.Context, operation, ownerID, sinkId, manifest string) (string, error) {
-	_, status, err := svc.getDeploymentState(ctx, ownerID, sinkId)
-	fileContent := []byte(manifest)
-	tmp := strings.Split(string(fileContent), "\n")
-	newContent := strings.Join(tmp[1:], "\n")
-	if err != nil {
-		if status == "broken" {
-			operation = "delete"
-		}
-	}
-	err = os.WriteFile("/tmp/otel-collector-"+sinkId+".json", []byte(newContent), 0644)
-	if err != nil {
-		svc.logger.Error("failed to write file content", zap.Error(err))
-		return "", err
-	}
-	stdOutListenFunction := func(out *bufio.Scanner, err *bufio.Scanner) {
-		for out.Scan() {
-			svc.logger.Info("Deploy Info: " + out.Text())
-		}
-		for err.Scan() {
-			svc.logger.Info("Deploy Error: " + err.Text())
-		}
-	}
-
-	// execute action
-	cmd := exec.Command("kubectl", operation, "-f", "/tmp/otel-collector-"+sinkId+".json", "-n", namespace)
-	_, _, err = execCmd(ctx, cmd, svc.logger, stdOutListenFunction)
-	if err == nil {
-		svc.logger.Info(fmt.Sprintf("successfully %s the otel-collector for sink-id: %s", operation, sinkId))
-	}
-
-	// delete temporary file
-	os.Remove("/tmp/otel-collector-" + sinkId + ".json")
 
-	// TODO this will be retrieved once we move to K8s SDK
-	collectorName := fmt.Sprintf("otel-%s", sinkId)
-	return collectorName, nil
+	// RestoreOtelCollectors - hand the collectors that should be running to the runtimes keeping them in memory, when maestro starts
+	RestoreOtelCollectors(ctx context.Context, collectors []Collector) error
 }
 
-func execCmd(_ context.Context, cmd *exec.Cmd, logger *zap.Logger, stdOutFunc func(stdOut *bufio.Scanner, stdErr *bufio.Scanner)) (*bufio.Scanner, *bufio.Scanner, error) {
-	stdoutReader, _ := cmd.StdoutPipe()
-	stdoutScanner := bufio.NewScanner(stdoutReader)
-	stderrReader, _ := cmd.StderrPipe()
-	stderrScanner := bufio.NewScanner(stderrReader)
-	go stdOutFunc(stdoutScanner, stderrScanner)
-	err := cmd.Start()
-	if err != nil {
-		logger.Error("Collector Deploy Error", zap.Error(err))
-	}
-	err = cmd.Wait()
+func (svc *deployService) CreateOtelCollector(ctx context.Context, ownerID, sinkID, collectorConfig string) (string, error) {
+	collectorName, err := svc.runtime.Apply(ctx, Collector{OwnerID: ownerID, SinkID: sinkID, Config: collectorConfig})
 	if err != nil {
-		logger.Error("Collector Deploy Error", zap.Error(err))
-	}
-	return stdoutScanner, stderrScanner, err
-}
-
-func (svc *deployService) getDeploymentState(ctx context.Context, _, sinkId string) (deploymentName string, status string, err error) {
-	// Since this can take a while to be retrieved, we need to have a wait mechanism
-	for i := 0; i < 5; i++ {
-		deploymentList, err2 := svc.clientSet.AppsV1().Deployments(namespace).List(ctx, k8smetav1.ListOptions{})
-		if err2 != nil {
-			svc.logger.Error("error on reading pods", zap.Error(err2))
-			return "", "", err2
-		}
-		for _, deployment := range deploymentList.Items {
-			if strings.Contains(deployment.Name, sinkId) {
-				svc.logger.Info("found deployment for sink")
-				deploymentName = deployment.Name
-				if len(deployment.Status.Conditions) == 0 || deployment.Status.Conditions[0].Type == k8sappsv1.DeploymentReplicaFailure {
-					svc.logger.Error("error on retrieving collector, deployment is broken")
-					return "", "broken", errors.New("error on retrieving collector, deployment is broken")
-				}
-				status = "active"
-				return
-			}
-		}
+		svc.logger.Error("failed to deploy the otel-collector", zap.String("sink_id", sinkID), zap.Error(err))
+		return "", errors.Wrap(ErrDeployCollector, err)
 	}
-	status = "deleted"
-	return "", status, nil
+	svc.logger.Info(fmt.Sprintf("successfully deployed the otel-collector for sink-id: %s", sinkID))
+	return collectorName, nil
 }
 
-func (svc *deployService) CreateOtelCollector(ctx context.Context, ownerID, sinkID, deploymentEntry string) (string, error) {
-	col, err := svc.collectorDeploy(ctx, "apply", ownerID, sinkID, deploymentEntry)
-	if err != nil {
-		return "", err
+func (svc *deployService) KillOtelCollector(ctx context.Context, deploymentName string, sinkID string) error {
+	if err := svc.runtime.Delete(ctx, sinkID); err != nil {
+		svc.logger.Error("failed to kill the otel-collector", zap.String("sink_id", sinkID),
+			zap.String("deployment_name", deploymentName), zap.Error(err))
+		return errors.Wrap(ErrKillCollector, err)
 	}
-
-	return col, nil
+	svc.logger.Info(fmt.Sprintf("successfully killed the otel-collector for sink-id: %s", sinkID))
+	return nil
 }
 
-func (svc *deployService) KillOtelCollector(ctx context.Context, deploymentName string, sinkId string) error {
-	stdOutListenFunction := func(out *bufio.Scanner, err *bufio.Scanner) {
-		for out.Scan() {
-			svc.logger.Info("Deploy Info: " + out.Text())
-		}
-		for err.Scan() {
-			svc.logger.Info("Deploy Error: " + err.Text())
-		}
+func (svc *deployService) RestoreOtelCollectors(ctx context.Context, collectors []Collector) error {
+	restorer, ok := svc.runtime.(Restorer)
+	if !ok {
+		return nil
 	}
-
-	// execute action
-	cmdDeploy := exec.Command("kubectl", "delete", "deploy", deploymentName, "-n", namespace)
-	_, _, err := execCmd(ctx, cmdDeploy, svc.logger, stdOutListenFunction)
-	cmdService := exec.Command("kubectl", "delete", "service", deploymentName, "-n", namespace)
-	_, _, err = execCmd(ctx, cmdService, svc.logger, stdOutListenFunction)
-	cmdConfigMap := exec.Command("kubectl", "delete", "configmap", "otel-collector-config-"+sinkId, "-n", namespace)
-	_, _, err = execCmd(ctx, cmdConfigMap, svc.logger, stdOutListenFunction)
-	if err == nil {
-		svc.logger.Info(fmt.Sprintf("successfully killed the otel-collector for sink-id: %s", sinkId))
+	if err := restorer.Restore(ctx, collectors); err != nil {
+		svc.logger.Error("failed to restore the otel-collectors", zap.Error(err))
+		return errors.Wrap(ErrDeployCollector, err)
 	}
-
+	svc.logger.Info(fmt.Sprintf("successfully restored %d otel-collectors", len(collectors)))
 	return nil
 }
@@ -0,0 +1,331 @@
+package kubecontrol
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/orb-community/orb/maestro/config"
+	"github.com/orb-community/orb/pkg/errors"
+	"go.uber.org/zap"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	appsv1apply "k8s.io/client-go/applyconfigurations/apps/v1"
+	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
+	k8smetav1apply "k8s.io/client-go/applyconfigurations/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	DefaultNamespace = "otelcollectors"
+	DefaultImage     = "otel/opentelemetry-collector-contrib:0.91.0"
+	// ReloaderImage runs the sidecar signaling the hot reloaded collectors when their configuration changes
+	ReloaderImage = "busybox:1.36"
+	// DefaultStorageSize is the size of the volume of the persistent sending queues of a collector
+	DefaultStorageSize = "1Gi"
+
+	// annotationConfigHash rolls the collector pods out when their configuration changes
+	annotationConfigHash = "orb.community/config-hash"
+	configKey            = "config.yaml"
+	metricsPort          = 8888
+	healthCheckPort      = 13133
+	configDir            = "/etc/otelcol-contrib"
+	// collectorUser is the user of the collector image, the reloader runs as it to be allowed to signal the collector
+	collectorUser = 10001
+)
+
+// reloaderScript sends SIGHUP to the collector, having it reload its configuration, whenever the mounted config map changes
+var reloaderScript = fmt.Sprintf(`last=$(md5sum %[1]s/%[2]s)
+while true; do
+  sleep 5
+  current=$(md5sum %[1]s/%[2]s)
+  if [ "$current" != "$last" ] && pkill -HUP -x otelcol-contrib; then
+    last=$current
+  fi
+done`, configDir, configKey)
+
+var _ CollectorRuntime = (*kubernetesRuntime)(nil)
+
+type kubernetesRuntime struct {
+	logger       *zap.Logger
+	clientSet    kubernetes.Interface
+	namespace    string
+	image        string
+	storageClass string
+	storageSize  string
+}
+
+// NewKubernetesRuntime returns the runtime deploying each collector as a config map, a deployment and a service
+// of the namespace, created and updated with server-side apply. The collectors with persistent sending queues also
+// get a persistent volume claim of the storage class and size, the default class when empty
+func NewKubernetesRuntime(logger *zap.Logger, clientSet kubernetes.Interface, namespace, image, storageClass, storageSize string) CollectorRuntime {
+	if storageSize == "" {
+		storageSize = DefaultStorageSize
+	}
+	return &kubernetesRuntime{logger: logger, clientSet: clientSet, namespace: namespace, image: image,
+		storageClass: storageClass, storageSize: storageSize}
+}
+
+// NewInClusterRuntime returns the kubernetes runtime of the cluster maestro runs in
+func NewInClusterRuntime(logger *zap.Logger, namespace, image, storageClass, storageSize string) (CollectorRuntime, error) {
+	clusterConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, errors.Wrap(errors.New("failed to get cluster config"), err)
+	}
+	clientSet, err := kubernetes.NewForConfig(clusterConfig)
+	if err != nil {
+		return nil, errors.Wrap(errors.New("failed to create kubernetes client"), err)
+	}
+	return NewKubernetesRuntime(logger, clientSet, namespace, image, storageClass, storageSize), nil
+}
+
+func (r *kubernetesRuntime) Apply(ctx context.Context, collector Collector) (string, error) {
+	name := CollectorName(collector.SinkID)
+	broken, err := r.isBroken(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if broken {
+		r.logger.Warn("collector deployment is broken, recreating it", zap.String("sink_id", collector.SinkID))
+		if err := r.Delete(ctx, collector.SinkID); err != nil {
+			return "", err
+		}
+	}
+
+	opts := k8smetav1.ApplyOptions{FieldManager: fieldManager, Force: true}
+	labels := r.labels(collector)
+	persistent := config.UsesFileStorage(collector.Config)
+	if persistent {
+		claim, err := r.storageClaim(collector, labels)
+		if err != nil {
+			return "", err
+		}
+		if _, err := r.clientSet.CoreV1().PersistentVolumeClaims(r.namespace).Apply(ctx, claim, opts); err != nil {
+			return "", errors.Wrap(errors.New("failed to apply collector storage claim"), err)
+		}
+	}
+	configMap := corev1apply.ConfigMap(configMapName(collector.SinkID), r.namespace).
+		WithLabels(labels).
+		WithData(map[string]string{configKey: collector.Config})
+	if _, err := r.clientSet.CoreV1().ConfigMaps(r.namespace).Apply(ctx, configMap, opts); err != nil {
+		return "", errors.Wrap(errors.New("failed to apply collector config map"), err)
+	}
+	if _, err := r.clientSet.AppsV1().Deployments(r.namespace).Apply(ctx, r.deployment(collector, labels, persistent), opts); err != nil {
+		return "", errors.Wrap(errors.New("failed to apply collector deployment"), err)
+	}
+	if _, err := r.clientSet.CoreV1().Services(r.namespace).Apply(ctx, r.service(collector, labels), opts); err != nil {
+		return "", errors.Wrap(errors.New("failed to apply collector service"), err)
+	}
+	if !persistent {
+		// the queues are no longer persisted, their storage is released once the previous pod is gone
+		if err := r.deleteStorageClaim(ctx, collector.SinkID); err != nil {
+			return "", err
+		}
+	}
+	return name, nil
+}
+
+func (r *kubernetesRuntime) Delete(ctx context.Context, sinkID string) error {
+	name := CollectorName(sinkID)
+	propagation := k8smetav1.DeletePropagationBackground
+	opts := k8smetav1.DeleteOptions{PropagationPolicy: &propagation}
+	if err := r.clientSet.AppsV1().Deployments(r.namespace).Delete(ctx, name, opts); err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrap(errors.New("failed to delete collector deployment"), err)
+	}
+	if err := r.clientSet.CoreV1().Services(r.namespace).Delete(ctx, name, opts); err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrap(errors.New("failed to delete collector service"), err)
+	}
+	if err := r.clientSet.CoreV1().ConfigMaps(r.namespace).Delete(ctx, configMapName(sinkID), opts); err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrap(errors.New("failed to delete collector config map"), err)
+	}
+	return r.deleteStorageClaim(ctx, sinkID)
+}
+
+func (r *kubernetesRuntime) deleteStorageClaim(ctx context.Context, sinkID string) error {
+	err := r.clientSet.CoreV1().PersistentVolumeClaims(r.namespace).Delete(ctx, storageClaimName(sinkID), k8smetav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrap(errors.New("failed to delete collector storage claim"), err)
+	}
+	return nil
+}
+
+// storageClaim is the volume the collector persists its sending queues to, so they survive the collector pod
+func (r *kubernetesRuntime) storageClaim(collector Collector, labels map[string]string) (*corev1apply.PersistentVolumeClaimApplyConfiguration, error) {
+	size, err := resource.ParseQuantity(r.storageSize)
+	if err != nil {
+		return nil, errors.Wrap(errors.New("invalid collector storage size"), err)
+	}
+	spec := corev1apply.PersistentVolumeClaimSpec().
+		WithAccessModes(k8scorev1.ReadWriteOnce).
+		WithResources(corev1apply.VolumeResourceRequirements().WithRequests(k8scorev1.ResourceList{k8scorev1.ResourceStorage: size}))
+	if r.storageClass != "" {
+		spec.WithStorageClassName(r.storageClass)
+	}
+	return corev1apply.PersistentVolumeClaim(storageClaimName(collector.SinkID), r.namespace).
+		WithLabels(labels).
+		WithSpec(spec), nil
+}
+
+func (r *kubernetesRuntime) Running(ctx context.Context) ([]string, error) {
+	deployments, err := r.clientSet.AppsV1().Deployments(r.namespace).List(ctx, k8smetav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(errors.New("failed to list collector deployments"), err)
+	}
+	var sinkIDs []string
+	for _, deployment := range deployments.Items {
+		// collectors deployed before the runtime labelled them are only known by their name
+		sinkID, ok := deployment.Labels[LabelSinkID]
+		if !ok {
+			if !strings.HasPrefix(deployment.Name, CollectorName("")) {
+				continue
+			}
+			sinkID = strings.TrimPrefix(deployment.Name, CollectorName(""))
+		}
+		sinkIDs = append(sinkIDs, sinkID)
+	}
+	sort.Strings(sinkIDs)
+	return sinkIDs, nil
+}
+
+func (r *kubernetesRuntime) MetricsURL(sinkID string) string {
+	return fmt.Sprintf("http://%s.%s.svc:%d/metrics", CollectorName(sinkID), r.namespace, metricsPort)
+}
+
+// isBroken tells whether the deployment exists but failed to create its replicas
+func (r *kubernetesRuntime) isBroken(ctx context.Context, name string) (bool, error) {
+	deployment, err := r.clientSet.AppsV1().Deployments(r.namespace).Get(ctx, name, k8smetav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(errors.New("failed to get collector deployment"), err)
+	}
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == k8sappsv1.DeploymentReplicaFailure && condition.Status == k8scorev1.ConditionTrue {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *kubernetesRuntime) labels(collector Collector) map[string]string {
+	return map[string]string{
+		"app":                          "opentelemetry",
+		"component":                    componentLabel(collector.SinkID),
+		"app.kubernetes.io/managed-by": fieldManager,
+		LabelSinkID:                    collector.SinkID,
+		LabelOwnerID:                   collector.OwnerID,
+	}
+}
+
+func (r *kubernetesRuntime) deployment(collector Collector, labels map[string]string, persistent bool) *appsv1apply.DeploymentApplyConfiguration {
+	hash := sha256.Sum256([]byte(collector.Config))
+	// the selector of a deployment can not change, it keeps matching the collectors deployed before the owner labels
+	selector := map[string]string{"app": "opentelemetry", "component": componentLabel(collector.SinkID)}
+
+	container := corev1apply.Container().
+		WithName("otel-collector").
+		WithImage(r.image).
+		WithImagePullPolicy(k8scorev1.PullIfNotPresent).
+		WithPorts(
+			corev1apply.ContainerPort().WithContainerPort(healthCheckPort).WithProtocol(k8scorev1.ProtocolTCP),
+			corev1apply.ContainerPort().WithContainerPort(metricsPort).WithProtocol(k8scorev1.ProtocolTCP),
+		).
+		WithVolumeMounts(
+			corev1apply.VolumeMount().WithName("varlog").WithReadOnly(true).WithMountPath("/var/log"),
+			corev1apply.VolumeMount().WithName("varlibdockercontainers").WithReadOnly(true).WithMountPath("/var/lib/docker/containers"),
+			corev1apply.VolumeMount().WithName("storage").WithMountPath(config.FileStorageDirectory),
+		)
+	if collector.HotReload {
+		// files mounted with a sub path are not updated along with their config map, the whole directory is
+		container.WithVolumeMounts(corev1apply.VolumeMount().WithName("data").WithReadOnly(true).WithMountPath(configDir))
+	} else {
+		container.WithVolumeMounts(corev1apply.VolumeMount().WithName("data").WithReadOnly(true).
+			WithMountPath(configDir + "/" + configKey).WithSubPath(configKey))
+	}
+
+	podSpec := corev1apply.PodSpec().
+		WithVolumes(
+			corev1apply.Volume().WithName("varlog").
+				WithHostPath(corev1apply.HostPathVolumeSource().WithPath("/var/log")),
+			corev1apply.Volume().WithName("varlibdockercontainers").
+				WithHostPath(corev1apply.HostPathVolumeSource().WithPath("/var/lib/docker/containers")),
+			corev1apply.Volume().WithName("data").
+				WithConfigMap(corev1apply.ConfigMapVolumeSource().WithName(configMapName(collector.SinkID)).WithDefaultMode(420)),
+		).
+		WithContainers(container).
+		WithRestartPolicy(k8scorev1.RestartPolicyAlways)
+	var strategy *appsv1apply.DeploymentStrategyApplyConfiguration
+	if persistent {
+		podSpec.WithVolumes(corev1apply.Volume().WithName("storage").
+			WithPersistentVolumeClaim(corev1apply.PersistentVolumeClaimVolumeSource().WithClaimName(storageClaimName(collector.SinkID))))
+		// the claim can only be attached to a single node, the previous pod releases it before the next one starts
+		strategy = appsv1apply.DeploymentStrategy().
+			WithType(k8sappsv1.RollingUpdateDeploymentStrategyType).
+			WithRollingUpdate(appsv1apply.RollingUpdateDeployment().
+				WithMaxSurge(intstr.FromInt32(0)).
+				WithMaxUnavailable(intstr.FromInt32(1)))
+	} else {
+		// the collector keeps the directory writable, its queues are lost with the pod
+		podSpec.WithVolumes(corev1apply.Volume().WithName("storage").WithEmptyDir(corev1apply.EmptyDirVolumeSource()))
+	}
+
+	annotations := map[string]string{
+		"prometheus.io/path":   "/metrics",
+		"prometheus.io/port":   fmt.Sprint(metricsPort),
+		"prometheus.io/scrape": "true",
+	}
+	if collector.HotReload {
+		reloader := corev1apply.Container().
+			WithName("config-reloader").
+			WithImage(ReloaderImage).
+			WithImagePullPolicy(k8scorev1.PullIfNotPresent).
+			WithCommand("/bin/sh", "-c", reloaderScript).
+			WithSecurityContext(corev1apply.SecurityContext().WithRunAsUser(collectorUser)).
+			WithVolumeMounts(corev1apply.VolumeMount().WithName("data").WithReadOnly(true).WithMountPath(configDir))
+		podSpec.WithShareProcessNamespace(true).WithContainers(reloader)
+	} else {
+		annotations[annotationConfigHash] = hex.EncodeToString(hash[:])
+	}
+
+	template := corev1apply.PodTemplateSpec().
+		WithLabels(labels).
+		WithAnnotations(annotations).
+		WithSpec(podSpec)
+
+	spec := appsv1apply.DeploymentSpec().
+		WithReplicas(1).
+		WithSelector(k8smetav1apply.LabelSelector().WithMatchLabels(selector)).
+		WithTemplate(template).
+		WithRevisionHistoryLimit(10).
+		WithProgressDeadlineSeconds(600)
+	if strategy != nil {
+		spec.WithStrategy(strategy)
+	}
+
+	return appsv1apply.Deployment(CollectorName(collector.SinkID), r.namespace).
+		WithLabels(labels).
+		WithSpec(spec)
+}
+
+func (r *kubernetesRuntime) service(collector Collector, labels map[string]string) *corev1apply.ServiceApplyConfiguration {
+	return corev1apply.Service(CollectorName(collector.SinkID), r.namespace).
+		WithLabels(labels).
+		WithSpec(corev1apply.ServiceSpec().
+			WithType(k8scorev1.ServiceTypeClusterIP).
+			WithSelector(map[string]string{"component": componentLabel(collector.SinkID)}).
+			WithPorts(
+				corev1apply.ServicePort().WithName("metrics").WithProtocol(k8scorev1.ProtocolTCP).
+					WithPort(metricsPort).WithTargetPort(intstr.FromInt32(metricsPort)),
+				corev1apply.ServicePort().WithName("healthcheck").WithProtocol(k8scorev1.ProtocolTCP).
+					WithPort(healthCheckPort).WithTargetPort(intstr.FromInt32(healthCheckPort)),
+			))
+}
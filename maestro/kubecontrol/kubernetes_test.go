@@ -0,0 +1,229 @@
+package kubecontrol
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/orb-community/orb/maestro/config"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newFakeClientSet returns a fake clientset creating the objects on their first server-side apply,
+// its object tracker only patches existing objects
+func newFakeClientSet(objects ...runtime.Object) *fake.Clientset {
+	clientSet := fake.NewSimpleClientset(objects...)
+	clientSet.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		if _, err := clientSet.Tracker().Get(patch.GetResource(), patch.GetNamespace(), patch.GetName()); !k8serrors.IsNotFound(err) {
+			return false, nil, nil
+		}
+		var obj runtime.Object
+		switch patch.GetResource().Resource {
+		case "configmaps":
+			obj = &k8scorev1.ConfigMap{}
+		case "services":
+			obj = &k8scorev1.Service{}
+		case "deployments":
+			obj = &k8sappsv1.Deployment{}
+		case "persistentvolumeclaims":
+			obj = &k8scorev1.PersistentVolumeClaim{}
+		default:
+			return false, nil, nil
+		}
+		if err := json.Unmarshal(patch.GetPatch(), obj); err != nil {
+			return true, nil, err
+		}
+		return true, obj, clientSet.Tracker().Create(patch.GetResource(), obj, patch.GetNamespace())
+	})
+	return clientSet
+}
+
+func TestKubernetesRuntimeApply(t *testing.T) {
+	ctx := context.Background()
+	clientSet := newFakeClientSet()
+	runtime := NewKubernetesRuntime(zap.NewNop(), clientSet, DefaultNamespace, DefaultImage, "", DefaultStorageSize)
+
+	collector := Collector{OwnerID: "owner-1", SinkID: "sink-1", Config: "receivers: {}\n"}
+	name, err := runtime.Apply(ctx, collector)
+	require.NoError(t, err)
+	require.Equal(t, "otel-sink-1", name)
+
+	configMap, err := clientSet.CoreV1().ConfigMaps(DefaultNamespace).Get(ctx, "otel-collector-config-sink-1", k8smetav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, collector.Config, configMap.Data[configKey])
+	require.Equal(t, "owner-1", configMap.Labels[LabelOwnerID])
+
+	deployment, err := clientSet.AppsV1().Deployments(DefaultNamespace).Get(ctx, name, k8smetav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "sink-1", deployment.Labels[LabelSinkID])
+	require.Equal(t, "otel-collector-sink-1", deployment.Spec.Selector.MatchLabels["component"])
+	require.Equal(t, DefaultImage, deployment.Spec.Template.Spec.Containers[0].Image)
+	require.Contains(t, deployment.Spec.Template.Spec.Containers[0].VolumeMounts,
+		k8scorev1.VolumeMount{Name: "storage", MountPath: config.FileStorageDirectory}, "expected a writable directory for the persistent queues")
+	firstHash := deployment.Spec.Template.Annotations[annotationConfigHash]
+	require.NotEmpty(t, firstHash)
+
+	service, err := clientSet.CoreV1().Services(DefaultNamespace).Get(ctx, name, k8smetav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "otel-collector-sink-1", service.Spec.Selector["component"])
+
+	collector.Config = "receivers: {}\nexporters: {}\n"
+	_, err = runtime.Apply(ctx, collector)
+	require.NoError(t, err)
+	configMap, err = clientSet.CoreV1().ConfigMaps(DefaultNamespace).Get(ctx, "otel-collector-config-sink-1", k8smetav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, collector.Config, configMap.Data[configKey], "expected the config map to be updated in place")
+	deployment, err = clientSet.AppsV1().Deployments(DefaultNamespace).Get(ctx, name, k8smetav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotEqual(t, firstHash, deployment.Spec.Template.Annotations[annotationConfigHash], "expected the pods to roll out with the new config")
+}
+
+func TestKubernetesRuntimeApplyPersistentStorage(t *testing.T) {
+	ctx := context.Background()
+	clientSet := newFakeClientSet()
+	runtime := NewKubernetesRuntime(zap.NewNop(), clientSet, DefaultNamespace, DefaultImage, "ssd", "2Gi")
+
+	persistentConfig := "extensions:\n  file_storage:\n    directory: " + config.FileStorageDirectory + "\n"
+	collector := Collector{OwnerID: "owner-1", SinkID: "sink-1", Config: persistentConfig}
+	name, err := runtime.Apply(ctx, collector)
+	require.NoError(t, err)
+
+	claim, err := clientSet.CoreV1().PersistentVolumeClaims(DefaultNamespace).Get(ctx, storageClaimName("sink-1"), k8smetav1.GetOptions{})
+	require.NoError(t, err, "expected the persistent queues to get their own volume")
+	require.Equal(t, "ssd", *claim.Spec.StorageClassName)
+	require.Equal(t, "2Gi", claim.Spec.Resources.Requests.Storage().String())
+
+	deployment, err := clientSet.AppsV1().Deployments(DefaultNamespace).Get(ctx, name, k8smetav1.GetOptions{})
+	require.NoError(t, err)
+	var storage *k8scorev1.Volume
+	for i, volume := range deployment.Spec.Template.Spec.Volumes {
+		if volume.Name == "storage" {
+			storage = &deployment.Spec.Template.Spec.Volumes[i]
+		}
+	}
+	require.NotNil(t, storage)
+	require.NotNil(t, storage.PersistentVolumeClaim, "expected the queues to survive the pod")
+	require.Equal(t, storageClaimName("sink-1"), storage.PersistentVolumeClaim.ClaimName)
+	require.Equal(t, int32(0), deployment.Spec.Strategy.RollingUpdate.MaxSurge.IntVal, "expected the previous pod to release the claim first")
+
+	collector.Config = "receivers: {}\n"
+	_, err = runtime.Apply(ctx, collector)
+	require.NoError(t, err)
+	_, err = clientSet.CoreV1().PersistentVolumeClaims(DefaultNamespace).Get(ctx, storageClaimName("sink-1"), k8smetav1.GetOptions{})
+	require.True(t, k8serrors.IsNotFound(err), "expected the claim to be released with the persistence")
+
+	collector.Config = persistentConfig
+	_, err = runtime.Apply(ctx, collector)
+	require.NoError(t, err)
+	require.NoError(t, runtime.Delete(ctx, "sink-1"))
+	_, err = clientSet.CoreV1().PersistentVolumeClaims(DefaultNamespace).Get(ctx, storageClaimName("sink-1"), k8smetav1.GetOptions{})
+	require.True(t, k8serrors.IsNotFound(err), "expected the claim to be deleted with the collector")
+}
+
+func TestKubernetesRuntimeApplyHotReload(t *testing.T) {
+	ctx := context.Background()
+	clientSet := newFakeClientSet()
+	runtime := NewKubernetesRuntime(zap.NewNop(), clientSet, DefaultNamespace, DefaultImage, "", DefaultStorageSize)
+
+	name, err := runtime.Apply(ctx, Collector{SinkID: "pool-0", Config: "receivers: {}\n", HotReload: true})
+	require.NoError(t, err)
+	require.Equal(t, "otel-pool-0", name)
+
+	deployment, err := clientSet.AppsV1().Deployments(DefaultNamespace).Get(ctx, name, k8smetav1.GetOptions{})
+	require.NoError(t, err)
+	podSpec := deployment.Spec.Template.Spec
+	require.Empty(t, deployment.Spec.Template.Annotations[annotationConfigHash], "expected the pods not to roll out on config changes")
+	require.NotNil(t, podSpec.ShareProcessNamespace)
+	require.True(t, *podSpec.ShareProcessNamespace, "expected the reloader to see the collector process")
+	require.Len(t, podSpec.Containers, 2)
+	require.Equal(t, "config-reloader", podSpec.Containers[1].Name)
+	require.Equal(t, ReloaderImage, podSpec.Containers[1].Image)
+	for _, container := range podSpec.Containers {
+		var mounted bool
+		for _, mount := range container.VolumeMounts {
+			if mount.Name == "data" {
+				mounted = true
+				require.Equal(t, configDir, mount.MountPath)
+				require.Empty(t, mount.SubPath, "expected the config map to be mounted as a directory to receive updates")
+			}
+		}
+		require.True(t, mounted, "expected %s to mount the config", container.Name)
+	}
+}
+
+func TestKubernetesRuntimeApplyBrokenDeployment(t *testing.T) {
+	ctx := context.Background()
+	broken := &k8sappsv1.Deployment{
+		ObjectMeta: k8smetav1.ObjectMeta{Name: "otel-sink-1", Namespace: DefaultNamespace, Labels: map[string]string{"stale": "true"}},
+		Status: k8sappsv1.DeploymentStatus{Conditions: []k8sappsv1.DeploymentCondition{
+			{Type: k8sappsv1.DeploymentReplicaFailure, Status: k8scorev1.ConditionTrue},
+		}},
+	}
+	clientSet := newFakeClientSet(broken)
+	runtime := NewKubernetesRuntime(zap.NewNop(), clientSet, DefaultNamespace, DefaultImage, "", DefaultStorageSize)
+
+	_, err := runtime.Apply(ctx, Collector{OwnerID: "owner-1", SinkID: "sink-1", Config: "receivers: {}\n"})
+	require.NoError(t, err)
+
+	deployment, err := clientSet.AppsV1().Deployments(DefaultNamespace).Get(ctx, "otel-sink-1", k8smetav1.GetOptions{})
+	require.NoError(t, err)
+	require.Empty(t, deployment.Labels["stale"], "expected the broken deployment to be recreated")
+	require.Empty(t, deployment.Status.Conditions)
+}
+
+func TestKubernetesRuntimeDelete(t *testing.T) {
+	ctx := context.Background()
+	clientSet := newFakeClientSet()
+	runtime := NewKubernetesRuntime(zap.NewNop(), clientSet, DefaultNamespace, DefaultImage, "", DefaultStorageSize)
+
+	_, err := runtime.Apply(ctx, Collector{OwnerID: "owner-1", SinkID: "sink-1", Config: "receivers: {}\n"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		sinkID string
+	}{
+		{name: "delete a running collector", sinkID: "sink-1"},
+		{name: "delete a missing collector", sinkID: "sink-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, runtime.Delete(ctx, tt.sinkID))
+			_, err := clientSet.AppsV1().Deployments(DefaultNamespace).Get(ctx, CollectorName(tt.sinkID), k8smetav1.GetOptions{})
+			require.True(t, k8serrors.IsNotFound(err))
+			_, err = clientSet.CoreV1().Services(DefaultNamespace).Get(ctx, CollectorName(tt.sinkID), k8smetav1.GetOptions{})
+			require.True(t, k8serrors.IsNotFound(err))
+			_, err = clientSet.CoreV1().ConfigMaps(DefaultNamespace).Get(ctx, configMapName(tt.sinkID), k8smetav1.GetOptions{})
+			require.True(t, k8serrors.IsNotFound(err))
+		})
+	}
+}
+
+func TestKubernetesRuntimeRunning(t *testing.T) {
+	ctx := context.Background()
+	unlabelled := &k8sappsv1.Deployment{ObjectMeta: k8smetav1.ObjectMeta{Name: CollectorName("sink-0"), Namespace: DefaultNamespace}}
+	other := &k8sappsv1.Deployment{ObjectMeta: k8smetav1.ObjectMeta{Name: "other", Namespace: DefaultNamespace}}
+	clientSet := newFakeClientSet(unlabelled, other)
+	runtime := NewKubernetesRuntime(zap.NewNop(), clientSet, DefaultNamespace, DefaultImage, "", DefaultStorageSize)
+
+	_, err := runtime.Apply(ctx, Collector{OwnerID: "owner-1", SinkID: "sink-1", Config: "receivers: {}\n"})
+	require.NoError(t, err)
+
+	running, err := runtime.Running(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"sink-0", "sink-1"}, running)
+	require.Equal(t, "http://otel-sink-1.otelcollectors.svc:8888/metrics", runtime.MetricsURL("sink-1"))
+}
@@ -0,0 +1,226 @@
+package kubecontrol
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/orb-community/orb/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zapio"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DefaultBinary  = "otelcol-contrib"
+	DefaultWorkDir = "/tmp/orb-collectors"
+
+	// local collectors share the host, each one gets its own ports counting up from these
+	localMetricsBasePort = 18888
+	localPProfBasePort   = 11888
+	localStopTimeout     = 10 * time.Second
+)
+
+var _ CollectorRuntime = (*localRuntime)(nil)
+
+type localCollector struct {
+	cmd  *exec.Cmd
+	slot int
+	done chan struct{}
+}
+
+type localRuntime struct {
+	logger     *zap.Logger
+	binary     string
+	workDir    string
+	mu         sync.Mutex
+	collectors map[string]*localCollector
+}
+
+// NewLocalRuntime returns the runtime running each collector as a process of the otelcol-contrib binary,
+// with its configuration written to the work directory
+func NewLocalRuntime(logger *zap.Logger, binary, workDir string) (CollectorRuntime, error) {
+	if err := os.MkdirAll(workDir, 0700); err != nil {
+		return nil, errors.Wrap(errors.New("failed to create collectors work directory"), err)
+	}
+	return &localRuntime{
+		logger:     logger,
+		binary:     binary,
+		workDir:    workDir,
+		collectors: make(map[string]*localCollector),
+	}, nil
+}
+
+func (r *localRuntime) Apply(_ context.Context, collector Collector) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	slot := r.freeSlot()
+	running, isRunning := r.collectors[collector.SinkID]
+	if isRunning {
+		slot = running.slot
+	}
+
+	config, err := localConfig(collector.Config, slot, r.storagePath(collector.SinkID))
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(r.storagePath(collector.SinkID), 0700); err != nil {
+		return "", errors.Wrap(errors.New("failed to create collector storage directory"), err)
+	}
+	path := r.configPath(collector.SinkID)
+	// the configuration holds the sink credentials
+	if err := os.WriteFile(path, config, 0600); err != nil {
+		return "", errors.Wrap(errors.New("failed to write collector config"), err)
+	}
+	if isRunning && collector.HotReload {
+		if err := running.cmd.Process.Signal(syscall.SIGHUP); err != nil {
+			return "", errors.Wrap(errors.New("failed to reload collector"), err)
+		}
+		return CollectorName(collector.SinkID), nil
+	}
+	if isRunning {
+		r.stop(running)
+		delete(r.collectors, collector.SinkID)
+	}
+
+	logger := r.logger.With(zap.String("sink_id", collector.SinkID), zap.String("owner_id", collector.OwnerID))
+	cmd := exec.Command(r.binary, "--config", path)
+	cmd.Stdout = &zapio.Writer{Log: logger, Level: zapcore.InfoLevel}
+	cmd.Stderr = &zapio.Writer{Log: logger, Level: zapcore.InfoLevel}
+	if err := cmd.Start(); err != nil {
+		return "", errors.Wrap(errors.New("failed to start collector"), err)
+	}
+
+	started := &localCollector{cmd: cmd, slot: slot, done: make(chan struct{})}
+	r.collectors[collector.SinkID] = started
+	go func() {
+		err := cmd.Wait()
+		logger.Info("collector process exited", zap.Error(err))
+		close(started.done)
+		r.mu.Lock()
+		if r.collectors[collector.SinkID] == started {
+			delete(r.collectors, collector.SinkID)
+		}
+		r.mu.Unlock()
+	}()
+
+	return CollectorName(collector.SinkID), nil
+}
+
+func (r *localRuntime) Delete(_ context.Context, sinkID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if running, ok := r.collectors[sinkID]; ok {
+		r.stop(running)
+		delete(r.collectors, sinkID)
+	}
+	if err := os.Remove(r.configPath(sinkID)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(errors.New("failed to remove collector config"), err)
+	}
+	if err := os.RemoveAll(r.storagePath(sinkID)); err != nil {
+		return errors.Wrap(errors.New("failed to remove collector storage directory"), err)
+	}
+	return nil
+}
+
+func (r *localRuntime) Running(_ context.Context) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sinkIDs := make([]string, 0, len(r.collectors))
+	for sinkID := range r.collectors {
+		sinkIDs = append(sinkIDs, sinkID)
+	}
+	sort.Strings(sinkIDs)
+	return sinkIDs, nil
+}
+
+func (r *localRuntime) MetricsURL(sinkID string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	running, ok := r.collectors[sinkID]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("http://127.0.0.1:%d/metrics", localMetricsBasePort+running.slot)
+}
+
+// stop terminates the collector process, killing it if it does not exit in time
+func (r *localRuntime) stop(running *localCollector) {
+	_ = running.cmd.Process.Signal(syscall.SIGTERM)
+	select {
+	case <-running.done:
+	case <-time.After(localStopTimeout):
+		_ = running.cmd.Process.Kill()
+		<-running.done
+	}
+}
+
+// freeSlot returns the lowest port slot no running collector uses
+func (r *localRuntime) freeSlot() int {
+	used := make(map[int]bool, len(r.collectors))
+	for _, running := range r.collectors {
+		used[running.slot] = true
+	}
+	slot := 0
+	for used[slot] {
+		slot++
+	}
+	return slot
+}
+
+func (r *localRuntime) configPath(sinkID string) string {
+	return filepath.Join(r.workDir, fmt.Sprintf("%s.yaml", CollectorName(sinkID)))
+}
+
+// storagePath is the directory of the persistent sending queues of the collector
+func (r *localRuntime) storagePath(sinkID string) string {
+	return filepath.Join(r.workDir, fmt.Sprintf("%s-storage", CollectorName(sinkID)))
+}
+
+// localConfig moves the telemetry and pprof endpoints of the collector configuration to the ports of the slot,
+// and its file storages to the storage directory of the collector
+func localConfig(collectorConfig string, slot int, storageDir string) ([]byte, error) {
+	var config map[string]interface{}
+	if err := yaml.Unmarshal([]byte(collectorConfig), &config); err != nil {
+		return nil, errors.Wrap(errors.New("failed to parse collector config"), err)
+	}
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+	setConfigValue(config, fmt.Sprintf("127.0.0.1:%d", localMetricsBasePort+slot), "service", "telemetry", "metrics", "address")
+	if extensions, ok := config["extensions"].(map[string]interface{}); ok {
+		if _, ok := extensions["pprof"]; ok {
+			setConfigValue(config, fmt.Sprintf("127.0.0.1:%d", localPProfBasePort+slot), "extensions", "pprof", "endpoint")
+		}
+		for id := range extensions {
+			if id == "file_storage" || strings.HasPrefix(id, "file_storage/") {
+				setConfigValue(config, storageDir, "extensions", id, "directory")
+			}
+		}
+	}
+	return yaml.Marshal(config)
+}
+
+func setConfigValue(config map[string]interface{}, value string, keys ...string) {
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := config[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			config[key] = next
+		}
+		config = next
+	}
+	config[keys[len(keys)-1]] = value
+}
@@ -0,0 +1,105 @@
+package kubecontrol
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// fakeCollector stands in for otelcol-contrib, it runs until terminated
+const fakeCollector = "#!/bin/sh\nexec sleep 60\n"
+
+// reloadingCollector stands in for otelcol-contrib, it counts the reloads it is signaled
+const reloadingCollector = "#!/bin/sh\ntrap 'echo reload >> \"$0.reloads\"' HUP\nwhile true; do sleep 1; done\n"
+
+func TestLocalRuntime(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "otelcol-contrib")
+	require.NoError(t, os.WriteFile(binary, []byte(fakeCollector), 0700))
+
+	rt, err := NewLocalRuntime(zap.NewNop(), binary, filepath.Join(dir, "collectors"))
+	require.NoError(t, err)
+	runtime := rt.(*localRuntime)
+
+	config := "extensions:\n  pprof:\n    endpoint: 0.0.0.0:1888\n  file_storage/sink:\n    directory: /var/lib/otelcol/file_storage\nservice:\n  extensions:\n  - pprof\n  - file_storage/sink\n"
+	for _, sinkID := range []string{"sink-1", "sink-2"} {
+		name, err := runtime.Apply(ctx, Collector{OwnerID: "owner-1", SinkID: sinkID, Config: config})
+		require.NoError(t, err)
+		require.Equal(t, CollectorName(sinkID), name)
+	}
+	require.Len(t, runtime.collectors, 2)
+
+	written, err := os.ReadFile(runtime.configPath("sink-2"))
+	require.NoError(t, err)
+	var got map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(written, &got))
+	require.Equal(t, "127.0.0.1:11889", got["extensions"].(map[string]interface{})["pprof"].(map[string]interface{})["endpoint"],
+		"expected the second collector to get its own pprof port")
+	require.Equal(t, "127.0.0.1:18889",
+		got["service"].(map[string]interface{})["telemetry"].(map[string]interface{})["metrics"].(map[string]interface{})["address"],
+		"expected the second collector to get its own telemetry port")
+	require.Equal(t, runtime.storagePath("sink-2"), got["extensions"].(map[string]interface{})["file_storage/sink"].(map[string]interface{})["directory"],
+		"expected the second collector to get its own storage directory")
+	require.DirExists(t, runtime.storagePath("sink-2"))
+
+	running, err := runtime.Running(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"sink-1", "sink-2"}, running)
+	require.Equal(t, "http://127.0.0.1:18889/metrics", runtime.MetricsURL("sink-2"))
+	require.Empty(t, runtime.MetricsURL("sink-3"))
+
+	previous := runtime.collectors["sink-1"]
+	_, err = runtime.Apply(ctx, Collector{OwnerID: "owner-1", SinkID: "sink-1", Config: config})
+	require.NoError(t, err)
+	<-previous.done
+	require.Equal(t, previous.slot, runtime.collectors["sink-1"].slot, "expected the restarted collector to keep its ports")
+
+	for _, sinkID := range []string{"sink-1", "sink-2", "sink-3"} {
+		require.NoError(t, runtime.Delete(ctx, sinkID))
+		_, err := os.Stat(runtime.configPath(sinkID))
+		require.True(t, os.IsNotExist(err), "expected the collector config to be removed")
+		require.NoDirExists(t, runtime.storagePath(sinkID))
+	}
+	require.Empty(t, runtime.collectors)
+}
+
+func TestLocalRuntimeHotReload(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "otelcol-contrib")
+	require.NoError(t, os.WriteFile(binary, []byte(reloadingCollector), 0700))
+
+	rt, err := NewLocalRuntime(zap.NewNop(), binary, filepath.Join(dir, "collectors"))
+	require.NoError(t, err)
+	runtime := rt.(*localRuntime)
+	defer func() {
+		require.NoError(t, runtime.Delete(ctx, "pool-0"))
+	}()
+
+	_, err = runtime.Apply(ctx, Collector{SinkID: "pool-0", Config: "receivers: {}\n", HotReload: true})
+	require.NoError(t, err)
+	started := runtime.collectors["pool-0"]
+	// give the shell the time to set its trap
+	time.Sleep(200 * time.Millisecond)
+
+	config := "receivers: {}\nexporters: {}\n"
+	_, err = runtime.Apply(ctx, Collector{SinkID: "pool-0", Config: config, HotReload: true})
+	require.NoError(t, err)
+	require.Same(t, started, runtime.collectors["pool-0"], "expected the collector to keep running")
+
+	written, err := os.ReadFile(runtime.configPath("pool-0"))
+	require.NoError(t, err)
+	require.Contains(t, string(written), "exporters")
+	require.Eventually(t, func() bool {
+		reloads, err := os.ReadFile(binary + ".reloads")
+		return err == nil && strings.Count(string(reloads), "reload") == 1
+	}, 5*time.Second, 50*time.Millisecond, "expected the collector to be signaled to reload")
+}
@@ -0,0 +1,207 @@
+package kubecontrol
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/orb-community/orb/maestro/config"
+	"github.com/orb-community/orb/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// PoolMemberPrefix names the collectors of the pool, pool-0 to pool-<size-1>
+	PoolMemberPrefix = "pool-"
+	// ringReplicas places each member many times on the ring to spread the sinks evenly
+	ringReplicas = 128
+)
+
+var (
+	_ CollectorRuntime = (*poolRuntime)(nil)
+	_ Restorer         = (*poolRuntime)(nil)
+)
+
+type poolRuntime struct {
+	logger  *zap.Logger
+	members CollectorRuntime
+	ring    hashRing
+	mu      sync.Mutex
+	// sinks holds the collector of each sink the pool delivers for
+	sinks map[string]Collector
+}
+
+// NewPoolRuntime returns the runtime delivering for the sinks with a pool of collectors, run on the members runtime.
+// Each sink is assigned to a member by consistent hashing, so resizing the pool only moves the sinks of the members
+// added or removed, and each member runs one pipeline per sink, reloading its configuration when its sinks change
+func NewPoolRuntime(logger *zap.Logger, members CollectorRuntime, size int) (CollectorRuntime, error) {
+	if size < 1 {
+		return nil, errors.Wrap(ErrUnknownMode, errors.New("the collector pool needs at least one member"))
+	}
+	memberIDs := make([]string, size)
+	for i := range memberIDs {
+		memberIDs[i] = PoolMemberPrefix + strconv.Itoa(i)
+	}
+	return &poolRuntime{
+		logger:  logger,
+		members: members,
+		ring:    newHashRing(memberIDs, ringReplicas),
+		sinks:   make(map[string]Collector),
+	}, nil
+}
+
+func (r *poolRuntime) Apply(ctx context.Context, collector Collector) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	member := r.ring.member(collector.SinkID)
+	previous, existed := r.sinks[collector.SinkID]
+	r.sinks[collector.SinkID] = collector
+	if err := r.applyMember(ctx, member); err != nil {
+		// keep the member running the configuration it had
+		if existed {
+			r.sinks[collector.SinkID] = previous
+		} else {
+			delete(r.sinks, collector.SinkID)
+		}
+		return "", err
+	}
+	return CollectorName(member), nil
+}
+
+func (r *poolRuntime) Delete(ctx context.Context, sinkID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.sinks[sinkID]; !ok {
+		// the sink may still have the dedicated collector it had before the pool
+		return r.members.Delete(ctx, sinkID)
+	}
+	delete(r.sinks, sinkID)
+	return r.applyMember(ctx, r.ring.member(sinkID))
+}
+
+func (r *poolRuntime) Running(_ context.Context) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sinkIDs := make([]string, 0, len(r.sinks))
+	for sinkID := range r.sinks {
+		sinkIDs = append(sinkIDs, sinkID)
+	}
+	sort.Strings(sinkIDs)
+	return sinkIDs, nil
+}
+
+func (r *poolRuntime) MetricsURL(sinkID string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.sinks[sinkID]; !ok {
+		return ""
+	}
+	return r.members.MetricsURL(r.ring.member(sinkID))
+}
+
+// Restore assigns the collectors to the members of the pool and applies them, then removes the collectors left by
+// a former pool size or by the dedicated mode
+func (r *poolRuntime) Restore(ctx context.Context, collectors []Collector) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sinks = make(map[string]Collector, len(collectors))
+	for _, collector := range collectors {
+		r.sinks[collector.SinkID] = collector
+	}
+	// a failing member does not keep the others from being restored
+	var restoreErr error
+	for _, member := range r.ring.memberIDs {
+		if err := r.applyMember(ctx, member); err != nil {
+			r.logger.Error("failed to restore pool member", zap.String("collector", CollectorName(member)), zap.Error(err))
+			restoreErr = err
+		}
+	}
+
+	running, err := r.members.Running(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range running {
+		if r.ring.hasMember(id) {
+			continue
+		}
+		r.logger.Info("removing collector out of the pool", zap.String("collector", CollectorName(id)))
+		if err := r.members.Delete(ctx, id); err != nil {
+			r.logger.Error("failed to remove collector out of the pool", zap.String("collector", CollectorName(id)), zap.Error(err))
+			restoreErr = err
+		}
+	}
+	return restoreErr
+}
+
+// applyMember deploys the member with the pipelines of its sinks, a member left without sinks is removed
+func (r *poolRuntime) applyMember(ctx context.Context, member string) error {
+	configs := map[string]string{}
+	for sinkID, collector := range r.sinks {
+		if r.ring.member(sinkID) == member {
+			configs[sinkID] = collector.Config
+		}
+	}
+	if len(configs) == 0 {
+		return r.members.Delete(ctx, member)
+	}
+	memberConfig, err := config.MergeCollectorConfigs(configs)
+	if err != nil {
+		return err
+	}
+	if _, err := r.members.Apply(ctx, Collector{SinkID: member, Config: memberConfig, HotReload: true}); err != nil {
+		return err
+	}
+	r.logger.Debug("applied pool member", zap.String("collector", CollectorName(member)), zap.Int("sinks", len(configs)))
+	return nil
+}
+
+// hashRing is a consistent hash ring of the pool members
+type hashRing struct {
+	memberIDs []string
+	points    []uint32
+	owners    map[uint32]string
+}
+
+func newHashRing(memberIDs []string, replicas int) hashRing {
+	ring := hashRing{memberIDs: memberIDs, owners: make(map[uint32]string, len(memberIDs)*replicas)}
+	for _, member := range memberIDs {
+		for i := 0; i < replicas; i++ {
+			point := crc32.ChecksumIEEE([]byte(fmt.Sprintf("%s#%d", member, i)))
+			if _, taken := ring.owners[point]; taken {
+				continue
+			}
+			ring.owners[point] = member
+			ring.points = append(ring.points, point)
+		}
+	}
+	sort.Slice(ring.points, func(i, j int) bool { return ring.points[i] < ring.points[j] })
+	return ring
+}
+
+// member returns the member owning the key, the first one clockwise of the key on the ring
+func (h hashRing) member(key string) string {
+	point := crc32.ChecksumIEEE([]byte(key))
+	i := sort.Search(len(h.points), func(i int) bool { return h.points[i] >= point })
+	if i == len(h.points) {
+		i = 0
+	}
+	return h.owners[h.points[i]]
+}
+
+func (h hashRing) hasMember(id string) bool {
+	for _, member := range h.memberIDs {
+		if member == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package kubecontrol
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const poolSinkConfig = "---\nreceivers:\n  kafka:\n    topic: otlp_metrics-%[1]s\nexporters:\n  otlphttp:\n    endpoint: https://acme.com/%[1]s\nservice:\n  pipelines:\n    metrics:\n      receivers:\n      - kafka\n      exporters:\n      - otlphttp\n"
+
+// recordingRuntime keeps the collectors applied on it in memory
+type recordingRuntime struct {
+	collectors map[string]Collector
+	deleted    []string
+}
+
+func newRecordingRuntime(running ...string) *recordingRuntime {
+	r := &recordingRuntime{collectors: map[string]Collector{}}
+	for _, id := range running {
+		r.collectors[id] = Collector{SinkID: id}
+	}
+	return r
+}
+
+func (r *recordingRuntime) Apply(_ context.Context, collector Collector) (string, error) {
+	r.collectors[collector.SinkID] = collector
+	return CollectorName(collector.SinkID), nil
+}
+
+func (r *recordingRuntime) Delete(_ context.Context, sinkID string) error {
+	delete(r.collectors, sinkID)
+	r.deleted = append(r.deleted, sinkID)
+	return nil
+}
+
+func (r *recordingRuntime) Running(_ context.Context) ([]string, error) {
+	ids := make([]string, 0, len(r.collectors))
+	for id := range r.collectors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (r *recordingRuntime) MetricsURL(sinkID string) string {
+	if _, ok := r.collectors[sinkID]; !ok {
+		return ""
+	}
+	return fmt.Sprintf("http://%s:8888/metrics", CollectorName(sinkID))
+}
+
+func sinkCollector(sinkID string) Collector {
+	return Collector{OwnerID: "owner-1", SinkID: sinkID, Config: fmt.Sprintf(poolSinkConfig, sinkID)}
+}
+
+func TestHashRing(t *testing.T) {
+	ring := newHashRing([]string{"pool-0", "pool-1", "pool-2", "pool-3"}, ringReplicas)
+	grown := newHashRing([]string{"pool-0", "pool-1", "pool-2", "pool-3", "pool-4"}, ringReplicas)
+
+	assigned := map[string]int{}
+	var moved int
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("sink-%d", i)
+		member := ring.member(key)
+		require.Equal(t, member, ring.member(key), "expected the assignment to be stable")
+		assigned[member]++
+		if grownMember := grown.member(key); grownMember != member {
+			require.Equal(t, "pool-4", grownMember, "expected only the sinks of the new member to move")
+			moved++
+		}
+	}
+	require.Len(t, assigned, 4)
+	for member, count := range assigned {
+		require.Greater(t, count, 100, "expected %s to get its share of the sinks", member)
+	}
+	require.Greater(t, moved, 0)
+	require.Less(t, moved, 400)
+}
+
+func TestPoolRuntime(t *testing.T) {
+	ctx := context.Background()
+	members := newRecordingRuntime()
+	rt, err := NewPoolRuntime(zap.NewNop(), members, 2)
+	require.NoError(t, err)
+	pool := rt.(*poolRuntime)
+
+	for i := 0; i < 6; i++ {
+		sinkID := fmt.Sprintf("sink-%d", i)
+		name, err := pool.Apply(ctx, sinkCollector(sinkID))
+		require.NoError(t, err)
+		require.Equal(t, CollectorName(pool.ring.member(sinkID)), name)
+	}
+	running, err := pool.Running(ctx)
+	require.NoError(t, err)
+	require.Len(t, running, 6)
+
+	for sinkID := range pool.sinks {
+		member, ok := members.collectors[pool.ring.member(sinkID)]
+		require.True(t, ok, "expected the member of %s to run", sinkID)
+		require.True(t, member.HotReload)
+		require.Contains(t, member.Config, "metrics/"+sinkID+":")
+		require.Equal(t, members.MetricsURL(pool.ring.member(sinkID)), pool.MetricsURL(sinkID))
+	}
+	require.Empty(t, pool.MetricsURL("sink-9"))
+
+	require.NoError(t, pool.Delete(ctx, "sink-0"))
+	member := pool.ring.member("sink-0")
+	require.NotContains(t, members.collectors[member].Config, "metrics/sink-0:", "expected the member to stop delivering for the sink")
+
+	// a sink out of the pool may still run a dedicated collector
+	require.NoError(t, pool.Delete(ctx, "sink-9"))
+	require.Contains(t, members.deleted, "sink-9")
+
+	for sinkID := range pool.sinks {
+		require.NoError(t, pool.Delete(ctx, sinkID))
+	}
+	require.Empty(t, members.collectors, "expected the members left without sinks to be removed")
+}
+
+func TestPoolRuntimeRestore(t *testing.T) {
+	ctx := context.Background()
+	// a former larger pool and a dedicated collector of the former mode
+	members := newRecordingRuntime("pool-0", "pool-1", "pool-2", "sink-legacy")
+	rt, err := NewPoolRuntime(zap.NewNop(), members, 2)
+	require.NoError(t, err)
+	pool := rt.(*poolRuntime)
+
+	var collectors []Collector
+	for i := 0; i < 6; i++ {
+		collectors = append(collectors, sinkCollector(fmt.Sprintf("sink-%d", i)))
+	}
+	require.NoError(t, rt.(Restorer).Restore(ctx, collectors))
+
+	running, err := members.Running(ctx)
+	require.NoError(t, err)
+	for _, id := range running {
+		require.True(t, strings.HasPrefix(id, PoolMemberPrefix) && pool.ring.hasMember(id), "expected %s to be removed", id)
+	}
+	require.Contains(t, members.deleted, "pool-2")
+	require.Contains(t, members.deleted, "sink-legacy")
+	for _, collector := range collectors {
+		require.Contains(t, members.collectors[pool.ring.member(collector.SinkID)].Config, "metrics/"+collector.SinkID+":")
+	}
+}
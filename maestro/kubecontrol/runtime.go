@@ -0,0 +1,113 @@
+package kubecontrol
+
+import (
+	"context"
+
+	"github.com/orb-community/orb/pkg/config"
+	"github.com/orb-community/orb/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// RuntimeKubernetes runs each collector as a deployment of the cluster maestro runs in
+	RuntimeKubernetes = "kubernetes"
+	// RuntimeLocal runs each collector as a local otelcol-contrib process, for dev environments without a cluster
+	RuntimeLocal = "local"
+
+	// ModeDedicated runs a collector for each sink
+	ModeDedicated = "dedicated"
+	// ModePool runs a pool of collectors, each delivering for many sinks
+	ModePool = "pool"
+
+	// LabelSinkID and LabelOwnerID label the resources of a collector with the sink it delivers for
+	LabelSinkID  = "orb.community/sink-id"
+	LabelOwnerID = "orb.community/owner-id"
+
+	fieldManager = "orb-maestro"
+)
+
+var (
+	// ErrDeployCollector indicates failure to create or update the collector of a sink
+	ErrDeployCollector = errors.New("failed to deploy otel collector")
+
+	// ErrKillCollector indicates failure to stop the collector of a sink
+	ErrKillCollector = errors.New("failed to kill otel collector")
+
+	// ErrUnknownRuntime indicates a collector runtime other than kubernetes or local
+	ErrUnknownRuntime = errors.New("unknown collector runtime")
+
+	// ErrUnknownMode indicates a collector mode other than dedicated or pool
+	ErrUnknownMode = errors.New("unknown collector mode")
+)
+
+// Collector is the otel collector delivering the metrics of a sink
+type Collector struct {
+	OwnerID string
+	SinkID  string
+	// Config is the otel collector configuration, in YAML
+	Config string
+	// HotReload has a running collector reload its new configuration instead of restarting it
+	HotReload bool
+}
+
+// CollectorRuntime runs the otel collectors of the sinks
+type CollectorRuntime interface {
+	// Apply creates the collector of the sink, or updates it in place with the new configuration, and returns its name
+	Apply(ctx context.Context, collector Collector) (string, error)
+	// Delete stops the collector of the sink and removes its resources, deleting a missing collector is not an error
+	Delete(ctx context.Context, sinkID string) error
+	// Running returns the sink IDs of the collectors the runtime runs
+	Running(ctx context.Context) ([]string, error)
+	// MetricsURL returns the URL of the prometheus self-metrics of the collector of the sink,
+	// empty when the runtime does not run it
+	MetricsURL(sinkID string) string
+}
+
+// Restorer is implemented by the runtimes keeping the collectors they run in memory, to restore them when maestro restarts
+type Restorer interface {
+	// Restore hands the runtime the collectors that should be running
+	Restore(ctx context.Context, collectors []Collector) error
+}
+
+// NewRuntime returns the collector runtime of the configuration
+func NewRuntime(logger *zap.Logger, cfg config.CollectorRuntimeConfig) (CollectorRuntime, error) {
+	var runtime CollectorRuntime
+	var err error
+	switch cfg.Runtime {
+	case RuntimeKubernetes:
+		runtime, err = NewInClusterRuntime(logger, cfg.Namespace, cfg.Image, cfg.StorageClass, cfg.StorageSize)
+	case RuntimeLocal:
+		runtime, err = NewLocalRuntime(logger, cfg.Binary, cfg.WorkDir)
+	default:
+		return nil, errors.Wrap(ErrUnknownRuntime, errors.New(cfg.Runtime))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Mode {
+	case ModeDedicated:
+		return runtime, nil
+	case ModePool:
+		return NewPoolRuntime(logger, runtime, cfg.PoolSize)
+	default:
+		return nil, errors.Wrap(ErrUnknownMode, errors.New(cfg.Mode))
+	}
+}
+
+// CollectorName returns the name of the collector of the sink
+func CollectorName(sinkID string) string {
+	return "otel-" + sinkID
+}
+
+func storageClaimName(sinkID string) string {
+	return "otel-collector-storage-" + sinkID
+}
+
+func configMapName(sinkID string) string {
+	return "otel-collector-config-" + sinkID
+}
+
+func componentLabel(sinkID string) string {
+	return "otel-collector-" + sinkID
+}
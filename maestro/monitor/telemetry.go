@@ -0,0 +1,155 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/orb-community/orb/maestro/deployment"
+	"github.com/orb-community/orb/pkg/config"
+	"github.com/orb-community/orb/pkg/errors"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// the exporter self-metrics of the otel collector, the sent and failed counters are reported per signal
+// e.g. otelcol_exporter_sent_metric_points or otelcol_exporter_send_failed_spans
+const (
+	sentMetricPrefix    = "otelcol_exporter_sent_"
+	failedMetricPrefix  = "otelcol_exporter_send_failed_"
+	queueSizeMetric     = "otelcol_exporter_queue_size"
+	queueCapacityMetric = "otelcol_exporter_queue_capacity"
+	exporterLabel       = "exporter"
+)
+
+// ErrScrapeCollector indicates failure to read the self-metrics of a collector
+var ErrScrapeCollector = errors.New("failed to scrape collector metrics")
+
+// exporterSample is a scrape of the exporter self-metrics of a collector
+type exporterSample struct {
+	sent          float64
+	failed        float64
+	queueSize     float64
+	queueCapacity float64
+	scrapedAt     time.Time
+}
+
+func (svc *monitorService) scrape(ctx context.Context, sinkID string) (exporterSample, error) {
+	url := svc.runtime.MetricsURL(sinkID)
+	if url == "" {
+		return exporterSample{}, errors.Wrap(ErrScrapeCollector, errors.New("collector is not running"))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return exporterSample{}, errors.Wrap(ErrScrapeCollector, err)
+	}
+	req.Header.Set("Accept", string(expfmt.FmtText))
+	res, err := svc.httpClient.Do(req)
+	if err != nil {
+		return exporterSample{}, errors.Wrap(ErrScrapeCollector, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return exporterSample{}, errors.Wrap(ErrScrapeCollector, errors.New(res.Status))
+	}
+	return parseExporterMetrics(res.Body, sinkID, time.Now())
+}
+
+// parseExporterMetrics sums the exporter self-metrics of the sink from the prometheus text exposition
+func parseExporterMetrics(r io.Reader, sinkID string, scrapedAt time.Time) (exporterSample, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return exporterSample{}, errors.Wrap(ErrScrapeCollector, err)
+	}
+	sample := exporterSample{scrapedAt: scrapedAt}
+	for name, family := range families {
+		switch {
+		case strings.HasPrefix(name, sentMetricPrefix):
+			sample.sent += sumFamily(family, sinkID)
+		case strings.HasPrefix(name, failedMetricPrefix):
+			sample.failed += sumFamily(family, sinkID)
+		case name == queueSizeMetric:
+			sample.queueSize += sumFamily(family, sinkID)
+		case name == queueCapacityMetric:
+			sample.queueCapacity += sumFamily(family, sinkID)
+		}
+	}
+	return sample, nil
+}
+
+func sumFamily(family *dto.MetricFamily, sinkID string) (sum float64) {
+	for _, metric := range family.GetMetric() {
+		if !isSinkExporter(metric, sinkID) {
+			continue
+		}
+		switch {
+		case metric.GetCounter() != nil:
+			sum += metric.GetCounter().GetValue()
+		case metric.GetGauge() != nil:
+			sum += metric.GetGauge().GetValue()
+		case metric.GetUntyped() != nil:
+			sum += metric.GetUntyped().GetValue()
+		}
+	}
+	return sum
+}
+
+// isSinkExporter tells whether the metric is of an exporter of the sink. The exporters of a dedicated collector are
+// all of its sink, the exporters of a pooled collector are named after their sink, e.g. prometheusremotewrite/<sink id>
+func isSinkExporter(metric *dto.Metric, sinkID string) bool {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() != exporterLabel {
+			continue
+		}
+		_, name, found := strings.Cut(label.GetValue(), "/")
+		return !found || name == sinkID || strings.HasPrefix(name, sinkID+"_")
+	}
+	return true
+}
+
+// analyzeDelivery derives the sink status from the delivery since the previous scrape, as follows
+// failing at least the error ratio of the items will send an "error" state, plus the failure message
+// failing at least the warning ratio of the items, or filling the sending queue up to its warning ratio, will send a "warning" state
+// sending items with no such failures will send an "active" state
+// sending nothing keeps the current state, as the sinker reports idle sinks
+func analyzeDelivery(thresholds config.MonitorConfig, previous exporterSample, hasPrevious bool, current exporterSample) (status string, errorMessage string, stats deployment.DeliveryStats) {
+	sent, failed := current.sent, current.failed
+	var interval float64
+	// counters going down means the collector restarted, its counters then cover the whole delivery
+	if hasPrevious && current.sent >= previous.sent && current.failed >= previous.failed {
+		sent -= previous.sent
+		failed -= previous.failed
+		interval = current.scrapedAt.Sub(previous.scrapedAt).Seconds()
+	}
+
+	stats = deployment.DeliveryStats{
+		SentItems:     int64(current.sent),
+		FailedItems:   int64(current.failed),
+		QueueSize:     int64(current.queueSize),
+		QueueCapacity: int64(current.queueCapacity),
+		ScrapedAt:     current.scrapedAt,
+	}
+	if interval > 0 {
+		stats.ItemsPerSecond = sent / interval
+	}
+	if sent+failed > 0 {
+		stats.FailureRatio = failed / (sent + failed)
+	}
+
+	switch {
+	case failed > 0 && stats.FailureRatio >= thresholds.ErrorFailureRatio:
+		return "error", fmt.Sprintf("error: exporter failed to send %d of %d items", int64(failed), int64(sent+failed)), stats
+	case failed > 0 && stats.FailureRatio >= thresholds.WarningFailureRatio:
+		return "warning", fmt.Sprintf("error: exporter failed to send %d of %d items", int64(failed), int64(sent+failed)), stats
+	case current.queueCapacity > 0 && current.queueSize/current.queueCapacity >= thresholds.QueueWarningRatio:
+		return "warning", fmt.Sprintf("error: exporter sending queue is %d%% full", int64(100*current.queueSize/current.queueCapacity)), stats
+	case sent > 0:
+		return "active", "", stats
+	default:
+		return "", "", stats
+	}
+}
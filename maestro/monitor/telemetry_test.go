@@ -0,0 +1,243 @@
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orb-community/orb/maestro/kubecontrol"
+	"github.com/orb-community/orb/pkg/config"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const collectorMetrics = `# HELP otelcol_exporter_queue_capacity Fixed capacity of the retry queue (in batches)
+# TYPE otelcol_exporter_queue_capacity gauge
+otelcol_exporter_queue_capacity{exporter="prometheusremotewrite"} 1000
+# HELP otelcol_exporter_queue_size Current size of the retry queue (in batches)
+# TYPE otelcol_exporter_queue_size gauge
+otelcol_exporter_queue_size{exporter="prometheusremotewrite"} 20
+# HELP otelcol_exporter_send_failed_metric_points Number of metric points in failed attempts to send to destination.
+# TYPE otelcol_exporter_send_failed_metric_points counter
+otelcol_exporter_send_failed_metric_points{exporter="prometheusremotewrite"} 30
+# HELP otelcol_exporter_sent_metric_points Number of metric points successfully sent to destination.
+# TYPE otelcol_exporter_sent_metric_points counter
+otelcol_exporter_sent_metric_points{exporter="prometheusremotewrite"} 1200
+otelcol_exporter_sent_metric_points{exporter="logging"} 300
+# HELP otelcol_receiver_accepted_metric_points Number of metric points successfully pushed into the pipeline.
+# TYPE otelcol_receiver_accepted_metric_points counter
+otelcol_receiver_accepted_metric_points{receiver="kafka"} 1530
+`
+
+type fakeRuntime struct {
+	urls map[string]string
+}
+
+func (r fakeRuntime) Apply(_ context.Context, collector kubecontrol.Collector) (string, error) {
+	return kubecontrol.CollectorName(collector.SinkID), nil
+}
+
+func (r fakeRuntime) Delete(_ context.Context, _ string) error {
+	return nil
+}
+
+func (r fakeRuntime) Running(_ context.Context) ([]string, error) {
+	var sinkIDs []string
+	for sinkID := range r.urls {
+		sinkIDs = append(sinkIDs, sinkID)
+	}
+	return sinkIDs, nil
+}
+
+func (r fakeRuntime) MetricsURL(sinkID string) string {
+	return r.urls[sinkID]
+}
+
+const pooledCollectorMetrics = `# TYPE otelcol_exporter_queue_capacity gauge
+otelcol_exporter_queue_capacity{exporter="prometheusremotewrite/sink-1"} 1000
+otelcol_exporter_queue_capacity{exporter="otlphttp/sink-10"} 1000
+# TYPE otelcol_exporter_queue_size gauge
+otelcol_exporter_queue_size{exporter="prometheusremotewrite/sink-1"} 5
+otelcol_exporter_queue_size{exporter="otlphttp/sink-10"} 700
+# TYPE otelcol_exporter_send_failed_metric_points counter
+otelcol_exporter_send_failed_metric_points{exporter="prometheusremotewrite/sink-1"} 3
+otelcol_exporter_send_failed_metric_points{exporter="otlphttp/sink-10"} 400
+# TYPE otelcol_exporter_sent_metric_points counter
+otelcol_exporter_sent_metric_points{exporter="prometheusremotewrite/sink-1"} 900
+otelcol_exporter_sent_metric_points{exporter="otlphttp/sink-10"} 100
+# TYPE otelcol_exporter_sent_spans counter
+otelcol_exporter_sent_spans{exporter="otlphttp/sink-10_traces"} 50
+`
+
+func TestParseExporterMetrics(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		metrics string
+		sinkID  string
+		want    exporterSample
+		wantErr bool
+	}{
+		{
+			name:    "dedicated collector",
+			metrics: collectorMetrics,
+			sinkID:  "sink-1",
+			want:    exporterSample{sent: 1500, failed: 30, queueSize: 20, queueCapacity: 1000, scrapedAt: now},
+		},
+		{
+			name:    "sink of a pooled collector",
+			metrics: pooledCollectorMetrics,
+			sinkID:  "sink-1",
+			want:    exporterSample{sent: 900, failed: 3, queueSize: 5, queueCapacity: 1000, scrapedAt: now},
+		},
+		{
+			name:    "sink with traces of a pooled collector",
+			metrics: pooledCollectorMetrics,
+			sinkID:  "sink-10",
+			want:    exporterSample{sent: 150, failed: 400, queueSize: 700, queueCapacity: 1000, scrapedAt: now},
+		},
+		{
+			name:    "invalid exposition",
+			metrics: "otelcol_exporter_sent_metric_points{",
+			sinkID:  "sink-1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExporterMetrics(strings.NewReader(tt.metrics), tt.sinkID, now)
+			if tt.wantErr {
+				require.True(t, errors.Contains(err, ErrScrapeCollector))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestScrape(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(collectorMetrics))
+	}))
+	defer server.Close()
+
+	svc := &monitorService{
+		logger: zap.NewNop(),
+		runtime: fakeRuntime{urls: map[string]string{
+			"sink-1": server.URL + "/metrics",
+			"sink-2": server.URL + "/missing",
+		}},
+		httpClient: server.Client(),
+	}
+	tests := []struct {
+		name    string
+		sinkID  string
+		sent    float64
+		wantErr bool
+	}{
+		{name: "scrape a running collector", sinkID: "sink-1", sent: 1500},
+		{name: "scrape a collector without metrics", sinkID: "sink-2", wantErr: true},
+		{name: "scrape a stopped collector", sinkID: "sink-3", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.scrape(context.Background(), tt.sinkID)
+			if tt.wantErr {
+				require.True(t, errors.Contains(err, ErrScrapeCollector))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.sent, got.sent)
+		})
+	}
+}
+
+func TestAnalyzeDelivery(t *testing.T) {
+	thresholds := config.MonitorConfig{WarningFailureRatio: 0.05, ErrorFailureRatio: 0.5, QueueWarningRatio: 0.8}
+	start := time.Now()
+	previous := exporterSample{sent: 1000, failed: 10, queueCapacity: 1000, scrapedAt: start}
+	later := start.Add(time.Minute)
+	tests := []struct {
+		name         string
+		hasPrevious  bool
+		current      exporterSample
+		status       string
+		errorMessage string
+		perSecond    float64
+		failureRatio float64
+	}{
+		{
+			name:        "healthy delivery",
+			hasPrevious: true,
+			current:     exporterSample{sent: 7000, failed: 10, queueCapacity: 1000, scrapedAt: later},
+			status:      "active",
+			perSecond:   100,
+		},
+		{
+			name:         "some items failing",
+			hasPrevious:  true,
+			current:      exporterSample{sent: 1900, failed: 110, queueCapacity: 1000, scrapedAt: later},
+			status:       "warning",
+			errorMessage: "error: exporter failed to send 100 of 1000 items",
+			perSecond:    15,
+			failureRatio: 0.1,
+		},
+		{
+			name:         "every item failing",
+			hasPrevious:  true,
+			current:      exporterSample{sent: 1000, failed: 610, queueCapacity: 1000, scrapedAt: later},
+			status:       "error",
+			errorMessage: "error: exporter failed to send 600 of 600 items",
+			failureRatio: 1,
+		},
+		{
+			name:         "sending queue filling up",
+			hasPrevious:  true,
+			current:      exporterSample{sent: 1600, failed: 10, queueSize: 900, queueCapacity: 1000, scrapedAt: later},
+			status:       "warning",
+			errorMessage: "error: exporter sending queue is 90% full",
+			perSecond:    10,
+		},
+		{
+			name:        "nothing delivered keeps the current state",
+			hasPrevious: true,
+			current:     exporterSample{sent: 1000, failed: 10, queueCapacity: 1000, scrapedAt: later},
+		},
+		{
+			name:    "first scrape of the collector",
+			current: exporterSample{sent: 1000, failed: 10, queueCapacity: 1000, scrapedAt: later},
+			status:  "active",
+			// no interval to measure the throughput over yet
+			failureRatio: 10.0 / 1010,
+		},
+		{
+			name:         "restarted collector",
+			hasPrevious:  true,
+			current:      exporterSample{sent: 10, failed: 90, queueCapacity: 1000, scrapedAt: later},
+			status:       "error",
+			errorMessage: "error: exporter failed to send 90 of 100 items",
+			failureRatio: 0.9,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, errorMessage, stats := analyzeDelivery(thresholds, previous, tt.hasPrevious, tt.current)
+			require.Equal(t, tt.status, status)
+			require.Equal(t, tt.errorMessage, errorMessage)
+			require.InDelta(t, tt.perSecond, stats.ItemsPerSecond, 0.001)
+			require.InDelta(t, tt.failureRatio, stats.FailureRatio, 0.001)
+			require.Equal(t, int64(tt.current.sent), stats.SentItems)
+			require.Equal(t, int64(tt.current.failed), stats.FailedItems)
+			require.Equal(t, later, stats.ScrapedAt)
+		})
+	}
+}
@@ -53,6 +53,15 @@ func migrateDB(db *sqlx.DB) error {
 					"DROP TABLE deployments",
 				},
 			},
+			{
+				Id: "2",
+				Up: []string{
+					`ALTER TABLE deployments ADD COLUMN IF NOT EXISTS delivery_stats JSONB;`,
+				},
+				Down: []string{
+					`ALTER TABLE deployments DROP COLUMN IF EXISTS delivery_stats;`,
+				},
+			},
 		},
 	}
 	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
@@ -2,6 +2,7 @@ package producer
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -18,21 +19,30 @@ type SinkStatusEvent struct {
 	sinkId       string
 	status       string
 	errorMessage string
+	// deliveryStats is the JSON encoded delivery of the sink, only sent by the monitor
+	deliveryStats string
 }
 
 func (e SinkStatusEvent) Encode() map[string]interface{} {
-	return map[string]interface{}{
+	values := map[string]interface{}{
 		"owner_id":      e.ownerId,
 		"sink_id":       e.sinkId,
 		"status":        e.status,
 		"error_message": e.errorMessage,
 		"timestamp":     time.Now().Format(time.RFC3339),
 	}
+	if e.deliveryStats != "" {
+		values["delivery_stats"] = e.deliveryStats
+	}
+	return values
 }
 
 type Producer interface {
 	// PublishSinkStatus to be used to publish the sink activity to the sinker
 	PublishSinkStatus(ctx context.Context, ownerId string, sinkId string, status string, errorMessage string) error
+	// PublishSinkDeliveryStats to be used to publish the delivery stats of the sink collector, along with its activity
+	// when status is not empty
+	PublishSinkDeliveryStats(ctx context.Context, ownerId string, sinkId string, status string, errorMessage string, deliveryStats interface{}) error
 }
 
 type maestroProducer struct {
@@ -52,6 +62,28 @@ func (p *maestroProducer) PublishSinkStatus(ctx context.Context, ownerId string,
 		status:       status,
 		errorMessage: errorMessage,
 	}
+	return p.publish(ctx, event)
+}
+
+// PublishSinkDeliveryStats to be used to publish the delivery stats of the sink collector, along with its activity
+// when status is not empty
+func (p *maestroProducer) PublishSinkDeliveryStats(ctx context.Context, ownerId string, sinkId string, status string, errorMessage string, deliveryStats interface{}) error {
+	encoded, err := json.Marshal(deliveryStats)
+	if err != nil {
+		p.logger.Error("error encoding delivery stats", zap.String("sink_id", sinkId), zap.Error(err))
+		return err
+	}
+	event := SinkStatusEvent{
+		ownerId:       ownerId,
+		sinkId:        sinkId,
+		status:        status,
+		errorMessage:  errorMessage,
+		deliveryStats: string(encoded),
+	}
+	return p.publish(ctx, event)
+}
+
+func (p *maestroProducer) publish(ctx context.Context, event SinkStatusEvent) error {
 	streamEvent := event.Encode()
 	record := &redis.XAddArgs{
 		Stream: streamID,
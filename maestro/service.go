@@ -48,13 +48,14 @@ type maestroService struct {
 }
 
 func NewMaestroService(logger *zap.Logger, streamRedisClient *redis.Client, sinkerRedisClient *redis.Client,
-	sinksGrpcClient sinkspb.SinkServiceClient, otelCfg config.OtelConfig, db *sqlx.DB, svcCfg config.BaseSvcConfig) Service {
-	kubectr := kubecontrol.NewService(logger)
+	sinksGrpcClient sinkspb.SinkServiceClient, otelCfg config.OtelConfig, db *sqlx.DB, svcCfg config.BaseSvcConfig,
+	collectorRuntime kubecontrol.CollectorRuntime, monitorCfg config.MonitorConfig) Service {
+	kubectr := kubecontrol.NewService(logger, collectorRuntime)
 	repo := deployment.NewRepositoryService(db, logger)
 	maestroProducer := producer.NewMaestroProducer(logger, streamRedisClient)
 	deploymentService := deployment.NewDeploymentService(logger, repo, otelCfg.KafkaUrl, svcCfg.EncryptionKey, maestroProducer, kubectr)
 	ps := producer.NewMaestroProducer(logger, streamRedisClient)
-	monitorService := monitor.NewMonitorService(logger, &sinksGrpcClient, ps, &kubectr, deploymentService)
+	monitorService := monitor.NewMonitorService(logger, &sinksGrpcClient, ps, &kubectr, deploymentService, collectorRuntime, monitorCfg)
 	eventService := service.NewEventService(logger, deploymentService, &sinksGrpcClient)
 	eventService = service.NewTracingService(logger, eventService,
 		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
@@ -94,6 +95,10 @@ func (svc *maestroService) Start(ctx context.Context, cancelFunction context.Can
 	svc.serviceContext = ctx
 	svc.serviceCancelFunc = cancelFunction
 
+	if err := svc.deploymentService.RestoreCollectors(ctx); err != nil {
+		svc.logger.Error("error restoring the running collectors", zap.Error(err))
+	}
+
 	go svc.subscribeToSinksEvents(ctx)
 	go svc.subscribeToSinkerIdleEvents(ctx)
 	go svc.subscribeToSinkerActivityEvents(ctx)
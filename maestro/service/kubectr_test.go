@@ -22,3 +22,7 @@ func (t *testKubeCtr) CreateOtelCollector(ctx context.Context, ownerID, sinkID,
 func (t *testKubeCtr) KillOtelCollector(ctx context.Context, deploymentName, sinkID string) error {
 	return nil
 }
+
+func (t *testKubeCtr) RestoreOtelCollectors(ctx context.Context, collectors []kubecontrol.Collector) error {
+	return nil
+}
@@ -17,3 +17,7 @@ func NewTestProducer(logger *zap.Logger) producer.Producer {
 func (t *testProducer) PublishSinkStatus(_ context.Context, _ string, _ string, _ string, _ string) error {
 	return nil
 }
+
+func (t *testProducer) PublishSinkDeliveryStats(_ context.Context, _ string, _ string, _ string, _ string, _ interface{}) error {
+	return nil
+}
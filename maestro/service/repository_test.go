@@ -42,6 +42,15 @@ func (f *fakeRepository) UpdateStatus(_ context.Context, _ string, _ string, _ s
 	return nil
 }
 
+func (f *fakeRepository) UpdateDeliveryStats(_ context.Context, _ string, sinkId string, stats deployment.DeliveryStats) error {
+	deploy, ok := f.inMemoryDict[sinkId]
+	if !ok {
+		return errors.New("not found")
+	}
+	deploy.DeliveryStats = &stats
+	return nil
+}
+
 func (f *fakeRepository) Remove(_ context.Context, _ string, sinkId string) error {
 	delete(f.inMemoryDict, sinkId)
 	return nil
@@ -74,6 +83,7 @@ func copyDeploy(src *deployment.Deployment) deployment.Deployment {
 		CollectorName:           src.CollectorName,
 		LastCollectorDeployTime: src.LastCollectorDeployTime,
 		LastCollectorStopTime:   src.LastCollectorStopTime,
+		DeliveryStats:           src.DeliveryStats,
 	}
 	return deploy
 }
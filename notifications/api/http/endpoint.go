@@ -0,0 +1,223 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package http
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/orb-community/orb/notifications"
+	"github.com/orb-community/orb/pkg/types"
+)
+
+func addChannelEndpoint(svc notifications.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(addChannelReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		name, err := types.NewIdentifier(req.Name)
+		if err != nil {
+			return nil, err
+		}
+		channel := notifications.Channel{
+			Name:   name,
+			Type:   req.Type,
+			URL:    req.URL,
+			Secret: req.Secret,
+		}
+		saved, err := svc.CreateChannel(ctx, req.token, channel)
+		if err != nil {
+			return nil, err
+		}
+
+		res := toChannelRes(saved)
+		res.created = true
+		return res, nil
+	}
+}
+
+func viewChannelEndpoint(svc notifications.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		channel, err := svc.ViewChannel(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toChannelRes(channel), nil
+	}
+}
+
+func listChannelsEndpoint(svc notifications.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listResourcesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		channels, err := svc.ListChannels(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := channelsRes{Channels: []channelRes{}}
+		for _, c := range channels {
+			res.Channels = append(res.Channels, toChannelRes(c))
+		}
+
+		return res, nil
+	}
+}
+
+func removeChannelEndpoint(svc notifications.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveChannel(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func addRuleEndpoint(svc notifications.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(addRuleReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		name, err := types.NewIdentifier(req.Name)
+		if err != nil {
+			return nil, err
+		}
+		rule := notifications.Rule{
+			Name:       name,
+			Entity:     req.Entity,
+			EntityID:   req.EntityID,
+			States:     req.States,
+			ChannelIDs: req.ChannelIDs,
+		}
+		saved, err := svc.CreateRule(ctx, req.token, rule)
+		if err != nil {
+			return nil, err
+		}
+
+		res := toRuleRes(saved)
+		res.created = true
+		return res, nil
+	}
+}
+
+func listRulesEndpoint(svc notifications.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listResourcesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		rules, err := svc.ListRules(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := rulesRes{Rules: []ruleRes{}}
+		for _, r := range rules {
+			res.Rules = append(res.Rules, toRuleRes(r))
+		}
+
+		return res, nil
+	}
+}
+
+func removeRuleEndpoint(svc notifications.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveRule(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func listDeliveriesEndpoint(svc notifications.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listDeliveriesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListDeliveries(ctx, req.token, req.pageMetadata)
+		if err != nil {
+			return nil, err
+		}
+
+		res := deliveriesPageRes{
+			Total:      page.Total,
+			Offset:     page.Offset,
+			Limit:      page.Limit,
+			Deliveries: []deliveryRes{},
+		}
+		for _, d := range page.Deliveries {
+			res.Deliveries = append(res.Deliveries, deliveryRes{
+				ID:          d.ID,
+				RuleID:      d.RuleID,
+				ChannelID:   d.ChannelID,
+				Entity:      d.Event.Entity,
+				EntityID:    d.Event.EntityID,
+				EntityName:  d.Event.EntityName,
+				State:       d.Event.State,
+				Message:     d.Event.Message,
+				AgentID:     d.Event.AgentID,
+				Status:      d.Status,
+				Attempts:    d.Attempts,
+				LastError:   d.LastError,
+				NextAttempt: d.NextAttempt,
+				Created:     d.Created,
+				Updated:     d.Updated,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func toChannelRes(c notifications.Channel) channelRes {
+	return channelRes{
+		ID:        c.ID,
+		Name:      c.Name.String(),
+		Type:      c.Type,
+		URL:       c.URL,
+		HasSecret: c.Secret != "",
+		Created:   c.Created,
+	}
+}
+
+func toRuleRes(r notifications.Rule) ruleRes {
+	return ruleRes{
+		ID:         r.ID,
+		Name:       r.Name.String(),
+		Entity:     r.Entity,
+		EntityID:   r.EntityID,
+		States:     r.States,
+		ChannelIDs: r.ChannelIDs,
+		Created:    r.Created,
+	}
+}
@@ -0,0 +1,345 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/orb-community/orb/notifications"
+	"github.com/orb-community/orb/notifications/mocks"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	token        = "token"
+	invalidToken = "invalid"
+	email        = "user@example.com"
+	contentType  = "application/json"
+)
+
+type testRequest struct {
+	client      *http.Client
+	method      string
+	url         string
+	contentType string
+	token       string
+	body        io.Reader
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
+	if err != nil {
+		return nil, err
+	}
+	if tr.token != "" {
+		req.Header.Set("Authorization", tr.token)
+	}
+	if tr.contentType != "" {
+		req.Header.Set("Content-Type", tr.contentType)
+	}
+	return tr.client.Do(req)
+}
+
+func newService(tokens map[string]string) notifications.Service {
+	auth := mocks.NewAuthService(tokens)
+	return notifications.NewService(zap.NewNop(), auth, mocks.NewChannelRepository(), mocks.NewRuleRepository(), mocks.NewDeliveryRepository())
+}
+
+func newServer(svc notifications.Service) *httptest.Server {
+	mux := MakeHandler(mocktracer.New(), "notifications", svc)
+	return httptest.NewServer(mux)
+}
+
+func createChannel(t *testing.T, svc notifications.Service, name string) notifications.Channel {
+	n, err := types.NewIdentifier(name)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	c, err := svc.CreateChannel(context.Background(), token, notifications.Channel{Name: n, Type: notifications.ChannelWebhook, URL: "https://example.com/" + name, Secret: "s3cr3t"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	return c
+}
+
+func TestAddChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	server := newServer(svc)
+	defer server.Close()
+
+	createChannel(t, svc, "existing")
+
+	cases := map[string]struct {
+		req         string
+		contentType string
+		auth        string
+		status      int
+	}{
+		"add a webhook channel with a secret": {
+			req:         `{"name": "ops", "type": "webhook", "url": "https://example.com/hooks/orb", "secret": "s3cr3t"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+		},
+		"add a slack channel": {
+			req:         `{"name": "team", "type": "slack", "url": "https://hooks.slack.com/services/T/B/X"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+		},
+		"add a channel with an existing name": {
+			req:         `{"name": "existing", "type": "slack", "url": "https://hooks.slack.com/services/T/B/X"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusConflict,
+		},
+		"add a channel of an unknown type": {
+			req:         `{"name": "mail", "type": "email", "url": "https://example.com/hooks/orb"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		"add a channel with an invalid name": {
+			req:         `{"name": "*", "type": "webhook", "url": "https://example.com/hooks/orb"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		"add a channel with invalid json": {
+			req:         `{"name": "ops"`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		"add a channel without content type": {
+			req:         `{"name": "ci", "type": "webhook", "url": "https://example.com/hooks/orb"}`,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		"add a channel with an invalid token": {
+			req:         `{"name": "ci", "type": "webhook", "url": "https://example.com/hooks/orb"}`,
+			contentType: contentType,
+			auth:        invalidToken,
+			status:      http.StatusUnauthorized,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			req := testRequest{
+				client:      server.Client(),
+				method:      http.MethodPost,
+				url:         fmt.Sprintf("%s/notifications/channels", server.URL),
+				contentType: tc.contentType,
+				token:       fmt.Sprintf("Bearer %s", tc.auth),
+				body:        strings.NewReader(tc.req),
+			}
+			res, err := req.make()
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+			assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+		})
+	}
+}
+
+func TestViewChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	server := newServer(svc)
+	defer server.Close()
+
+	channel := createChannel(t, svc, "ops")
+
+	cases := map[string]struct {
+		id     string
+		auth   string
+		status int
+	}{
+		"view a channel": {
+			id:     channel.ID,
+			auth:   token,
+			status: http.StatusOK,
+		},
+		"view a non-existing channel": {
+			id:     "unknown",
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		"view a channel with an invalid token": {
+			id:     channel.ID,
+			auth:   invalidToken,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			req := testRequest{
+				client: server.Client(),
+				method: http.MethodGet,
+				url:    fmt.Sprintf("%s/notifications/channels/%s", server.URL, tc.id),
+				token:  fmt.Sprintf("Bearer %s", tc.auth),
+			}
+			res, err := req.make()
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+			assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			body, err := io.ReadAll(res.Body)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error reading response %s", desc, err))
+			assert.NotContains(t, string(body), "s3cr3t", fmt.Sprintf("%s: expected the secret not to be returned", desc))
+
+			var c channelRes
+			err = json.Unmarshal(body, &c)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error decoding response %s", desc, err))
+			assert.True(t, c.HasSecret, fmt.Sprintf("%s: expected the channel to have a secret", desc))
+		})
+	}
+}
+
+func TestAddRule(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	server := newServer(svc)
+	defer server.Close()
+
+	channel := createChannel(t, svc, "ops")
+
+	cases := map[string]struct {
+		req    string
+		auth   string
+		status int
+	}{
+		"add a rule": {
+			req:    fmt.Sprintf(`{"name": "failing-policies", "entity": "policy", "states": ["failed_to_apply", "no_tap_match"], "channel_ids": ["%s"]}`, channel.ID),
+			auth:   token,
+			status: http.StatusCreated,
+		},
+		"add a rule on a state of another entity": {
+			req:    fmt.Sprintf(`{"name": "stale-sinks", "entity": "sink", "states": ["stale"], "channel_ids": ["%s"]}`, channel.ID),
+			auth:   token,
+			status: http.StatusBadRequest,
+		},
+		"add a rule without a name": {
+			req:    fmt.Sprintf(`{"entity": "sink", "states": ["error"], "channel_ids": ["%s"]}`, channel.ID),
+			auth:   token,
+			status: http.StatusBadRequest,
+		},
+		"add a rule sending to an unknown channel": {
+			req:    `{"name": "sinks", "entity": "sink", "states": ["error"], "channel_ids": ["unknown"]}`,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		"add a rule with an invalid token": {
+			req:    fmt.Sprintf(`{"name": "sinks", "entity": "sink", "states": ["error"], "channel_ids": ["%s"]}`, channel.ID),
+			auth:   invalidToken,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			req := testRequest{
+				client:      server.Client(),
+				method:      http.MethodPost,
+				url:         fmt.Sprintf("%s/notifications/rules", server.URL),
+				contentType: contentType,
+				token:       fmt.Sprintf("Bearer %s", tc.auth),
+				body:        strings.NewReader(tc.req),
+			}
+			res, err := req.make()
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+			assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+		})
+	}
+}
+
+func TestListDeliveries(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	server := newServer(svc)
+	defer server.Close()
+
+	ops := createChannel(t, svc, "ops")
+	oncall := createChannel(t, svc, "oncall")
+	name, err := types.NewIdentifier("sinks")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = svc.CreateRule(context.Background(), token, notifications.Rule{Name: name, Entity: notifications.EntitySink, States: []string{notifications.StateError}, ChannelIDs: []string{ops.ID, oncall.ID}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	for _, sinkID := range []string{"sink-1", "sink-2"} {
+		event := notifications.StateEvent{OwnerID: email, Entity: notifications.EntitySink, EntityID: sinkID, State: notifications.StateError}
+		require.Nil(t, svc.HandleStateEvent(context.Background(), event), "unexpected error handling state event")
+	}
+
+	cases := map[string]struct {
+		auth   string
+		query  string
+		status int
+		total  uint64
+	}{
+		"list deliveries": {
+			auth:   token,
+			query:  "",
+			status: http.StatusOK,
+			total:  4,
+		},
+		"list deliveries to a channel": {
+			auth:   token,
+			query:  fmt.Sprintf("?channel_id=%s", ops.ID),
+			status: http.StatusOK,
+			total:  2,
+		},
+		"list pending deliveries of an entity": {
+			auth:   token,
+			query:  "?entity_id=sink-1&status=pending",
+			status: http.StatusOK,
+			total:  2,
+		},
+		"list deliveries of an unknown status": {
+			auth:   token,
+			query:  "?status=sent",
+			status: http.StatusBadRequest,
+		},
+		"list deliveries with a limit too large": {
+			auth:   token,
+			query:  "?limit=1000",
+			status: http.StatusBadRequest,
+		},
+		"list deliveries with an invalid token": {
+			auth:   invalidToken,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			req := testRequest{
+				client: server.Client(),
+				method: http.MethodGet,
+				url:    fmt.Sprintf("%s/notifications/deliveries%s", server.URL, tc.query),
+				token:  fmt.Sprintf("Bearer %s", tc.auth),
+			}
+			res, err := req.make()
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+			assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var page deliveriesPageRes
+			err = json.NewDecoder(res.Body).Decode(&page)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error decoding response %s", desc, err))
+			assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d", desc, tc.total, page.Total))
+			assert.Len(t, page.Deliveries, int(tc.total), fmt.Sprintf("%s: expected %d deliveries", desc, tc.total))
+		})
+	}
+}
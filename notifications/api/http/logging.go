@@ -0,0 +1,150 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/orb-community/orb/notifications"
+	"go.uber.org/zap"
+)
+
+var _ notifications.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger *zap.Logger
+	svc    notifications.Service
+}
+
+func (l loggingMiddleware) CreateChannel(ctx context.Context, token string, c notifications.Channel) (_ notifications.Channel, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: create_channel",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: create_channel",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.CreateChannel(ctx, token, c)
+}
+
+func (l loggingMiddleware) ListChannels(ctx context.Context, token string) (_ []notifications.Channel, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: list_channels",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: list_channels",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ListChannels(ctx, token)
+}
+
+func (l loggingMiddleware) ViewChannel(ctx context.Context, token string, id string) (_ notifications.Channel, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: view_channel",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: view_channel",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ViewChannel(ctx, token, id)
+}
+
+func (l loggingMiddleware) RemoveChannel(ctx context.Context, token string, id string) (err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: remove_channel",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: remove_channel",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.RemoveChannel(ctx, token, id)
+}
+
+func (l loggingMiddleware) CreateRule(ctx context.Context, token string, r notifications.Rule) (_ notifications.Rule, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: create_rule",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: create_rule",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.CreateRule(ctx, token, r)
+}
+
+func (l loggingMiddleware) ListRules(ctx context.Context, token string) (_ []notifications.Rule, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: list_rules",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: list_rules",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ListRules(ctx, token)
+}
+
+func (l loggingMiddleware) RemoveRule(ctx context.Context, token string, id string) (err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: remove_rule",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: remove_rule",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.RemoveRule(ctx, token, id)
+}
+
+func (l loggingMiddleware) ListDeliveries(ctx context.Context, token string, pm notifications.DeliveryPageMetadata) (_ notifications.DeliveryPage, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: list_deliveries",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: list_deliveries",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ListDeliveries(ctx, token, pm)
+}
+
+func (l loggingMiddleware) HandleStateEvent(ctx context.Context, e notifications.StateEvent) (err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: handle_state_event",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: handle_state_event",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.HandleStateEvent(ctx, e)
+}
+
+func NewLoggingMiddleware(svc notifications.Service, logger *zap.Logger) notifications.Service {
+	return &loggingMiddleware{logger, svc}
+}
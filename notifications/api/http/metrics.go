@@ -0,0 +1,221 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux"
+	"github.com/orb-community/orb/notifications"
+	"github.com/orb-community/orb/pkg/errors"
+)
+
+var _ notifications.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	auth    mainflux.AuthServiceClient
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     notifications.Service
+}
+
+func (m metricsMiddleware) CreateChannel(ctx context.Context, token string, c notifications.Channel) (notifications.Channel, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return notifications.Channel{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "createChannel",
+			"owner_id", ownerID,
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.CreateChannel(ctx, token, c)
+}
+
+func (m metricsMiddleware) ListChannels(ctx context.Context, token string) ([]notifications.Channel, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "listChannels",
+			"owner_id", ownerID,
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ListChannels(ctx, token)
+}
+
+func (m metricsMiddleware) ViewChannel(ctx context.Context, token string, id string) (notifications.Channel, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return notifications.Channel{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "viewChannel",
+			"owner_id", ownerID,
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ViewChannel(ctx, token, id)
+}
+
+func (m metricsMiddleware) RemoveChannel(ctx context.Context, token string, id string) error {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "removeChannel",
+			"owner_id", ownerID,
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.RemoveChannel(ctx, token, id)
+}
+
+func (m metricsMiddleware) CreateRule(ctx context.Context, token string, r notifications.Rule) (notifications.Rule, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return notifications.Rule{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "createRule",
+			"owner_id", ownerID,
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.CreateRule(ctx, token, r)
+}
+
+func (m metricsMiddleware) ListRules(ctx context.Context, token string) ([]notifications.Rule, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "listRules",
+			"owner_id", ownerID,
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ListRules(ctx, token)
+}
+
+func (m metricsMiddleware) RemoveRule(ctx context.Context, token string, id string) error {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "removeRule",
+			"owner_id", ownerID,
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.RemoveRule(ctx, token, id)
+}
+
+func (m metricsMiddleware) ListDeliveries(ctx context.Context, token string, pm notifications.DeliveryPageMetadata) (notifications.DeliveryPage, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return notifications.DeliveryPage{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "listDeliveries",
+			"owner_id", ownerID,
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ListDeliveries(ctx, token, pm)
+}
+
+func (m metricsMiddleware) HandleStateEvent(ctx context.Context, e notifications.StateEvent) error {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "handleStateEvent",
+			"owner_id", e.OwnerID,
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.HandleStateEvent(ctx, e)
+}
+
+func (m metricsMiddleware) identify(token string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	res, err := m.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", errors.Wrap(errors.ErrUnauthorizedAccess, err)
+	}
+
+	return res.GetId(), nil
+}
+
+// MetricsMiddleware instruments core service by tracking request count and latency.
+func MetricsMiddleware(auth mainflux.AuthServiceClient, svc notifications.Service, counter metrics.Counter, latency metrics.Histogram) notifications.Service {
+	return &metricsMiddleware{
+		auth:    auth,
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
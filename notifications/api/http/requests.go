@@ -0,0 +1,114 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package http
+
+import (
+	"github.com/orb-community/orb/notifications"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+)
+
+const maxLimitSize = 100
+
+type addChannelReq struct {
+	token  string
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	URL    string `json:"url"`
+	Secret string `json:"secret,omitempty"`
+}
+
+func (req addChannelReq) validate() error {
+	if req.token == "" {
+		return errors.ErrUnauthorizedAccess
+	}
+
+	if req.Name == "" {
+		return errors.Wrap(errors.ErrMalformedEntity, errors.New("name not found"))
+	}
+
+	if _, err := types.NewIdentifier(req.Name); err != nil {
+		return errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return nil
+}
+
+type addRuleReq struct {
+	token      string
+	Name       string   `json:"name"`
+	Entity     string   `json:"entity"`
+	EntityID   string   `json:"entity_id,omitempty"`
+	States     []string `json:"states"`
+	ChannelIDs []string `json:"channel_ids"`
+}
+
+func (req addRuleReq) validate() error {
+	if req.token == "" {
+		return errors.ErrUnauthorizedAccess
+	}
+
+	if req.Name == "" {
+		return errors.Wrap(errors.ErrMalformedEntity, errors.New("name not found"))
+	}
+
+	if _, err := types.NewIdentifier(req.Name); err != nil {
+		return errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return nil
+}
+
+type viewResourceReq struct {
+	token string
+	id    string
+}
+
+func (req viewResourceReq) validate() error {
+	if req.token == "" {
+		return errors.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listResourcesReq struct {
+	token string
+}
+
+func (req listResourcesReq) validate() error {
+	if req.token == "" {
+		return errors.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+type listDeliveriesReq struct {
+	token        string
+	pageMetadata notifications.DeliveryPageMetadata
+}
+
+func (req listDeliveriesReq) validate() error {
+	if req.token == "" {
+		return errors.ErrUnauthorizedAccess
+	}
+
+	if req.pageMetadata.Limit == 0 || req.pageMetadata.Limit > maxLimitSize {
+		return errors.ErrMalformedEntity
+	}
+
+	switch req.pageMetadata.Status {
+	case "", notifications.DeliveryPending, notifications.DeliveryDelivered, notifications.DeliveryFailed:
+	default:
+		return errors.Wrap(errors.ErrMalformedEntity, errors.New("status must be pending, delivered or failed"))
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package http
+
+import (
+	"net/http"
+	"time"
+)
+
+type channelRes struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	URL  string `json:"url"`
+	// HasSecret tells whether the webhook payloads are signed, the secret itself is never returned
+	HasSecret bool      `json:"has_secret"`
+	Created   time.Time `json:"ts_created"`
+	created   bool
+}
+
+func (res channelRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res channelRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res channelRes) Empty() bool {
+	return false
+}
+
+type channelsRes struct {
+	Channels []channelRes `json:"channels"`
+}
+
+func (res channelsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res channelsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res channelsRes) Empty() bool {
+	return false
+}
+
+type ruleRes struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Entity     string    `json:"entity"`
+	EntityID   string    `json:"entity_id,omitempty"`
+	States     []string  `json:"states"`
+	ChannelIDs []string  `json:"channel_ids"`
+	Created    time.Time `json:"ts_created"`
+	created    bool
+}
+
+func (res ruleRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res ruleRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res ruleRes) Empty() bool {
+	return false
+}
+
+type rulesRes struct {
+	Rules []ruleRes `json:"rules"`
+}
+
+func (res rulesRes) Code() int {
+	return http.StatusOK
+}
+
+func (res rulesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res rulesRes) Empty() bool {
+	return false
+}
+
+type deliveryRes struct {
+	ID          string    `json:"id"`
+	RuleID      string    `json:"rule_id"`
+	ChannelID   string    `json:"channel_id"`
+	Entity      string    `json:"entity"`
+	EntityID    string    `json:"entity_id"`
+	EntityName  string    `json:"entity_name,omitempty"`
+	State       string    `json:"state"`
+	Message     string    `json:"message,omitempty"`
+	AgentID     string    `json:"agent_id,omitempty"`
+	Status      string    `json:"status"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error,omitempty"`
+	NextAttempt time.Time `json:"ts_next_attempt"`
+	Created     time.Time `json:"ts_created"`
+	Updated     time.Time `json:"ts_updated"`
+}
+
+type deliveriesPageRes struct {
+	Total      uint64        `json:"total"`
+	Offset     uint64        `json:"offset"`
+	Limit      uint64        `json:"limit"`
+	Deliveries []deliveryRes `json:"deliveries"`
+}
+
+func (res deliveriesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res deliveriesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res deliveriesPageRes) Empty() bool {
+	return false
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
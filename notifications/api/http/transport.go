@@ -0,0 +1,221 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	kitot "github.com/go-kit/kit/tracing/opentracing"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/opentracing/opentracing-go"
+	"github.com/orb-community/orb/buildinfo"
+	"github.com/orb-community/orb/internal/httputil"
+	"github.com/orb-community/orb/notifications"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	offsetKey    = "offset"
+	limitKey     = "limit"
+	ruleIDKey    = "rule_id"
+	channelIDKey = "channel_id"
+	entityIDKey  = "entity_id"
+	statusKey    = "status"
+	defOffset    = 0
+	defLimit     = 10
+)
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(tracer opentracing.Tracer, svcName string, svc notifications.Service) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	r := bone.New()
+	r.Post("/notifications/channels", kithttp.NewServer(
+		kitot.TraceServer(tracer, "add_notification_channel")(addChannelEndpoint(svc)),
+		decodeAddChannel,
+		types.EncodeResponse,
+		opts...,
+	))
+	r.Get("/notifications/channels", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_notification_channels")(listChannelsEndpoint(svc)),
+		decodeListResources,
+		types.EncodeResponse,
+		opts...,
+	))
+	r.Get("/notifications/channels/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_notification_channel")(viewChannelEndpoint(svc)),
+		decodeView,
+		types.EncodeResponse,
+		opts...,
+	))
+	r.Delete("/notifications/channels/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_notification_channel")(removeChannelEndpoint(svc)),
+		decodeView,
+		types.EncodeResponse,
+		opts...,
+	))
+	r.Post("/notifications/rules", kithttp.NewServer(
+		kitot.TraceServer(tracer, "add_notification_rule")(addRuleEndpoint(svc)),
+		decodeAddRule,
+		types.EncodeResponse,
+		opts...,
+	))
+	r.Get("/notifications/rules", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_notification_rules")(listRulesEndpoint(svc)),
+		decodeListResources,
+		types.EncodeResponse,
+		opts...,
+	))
+	r.Delete("/notifications/rules/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_notification_rule")(removeRuleEndpoint(svc)),
+		decodeView,
+		types.EncodeResponse,
+		opts...,
+	))
+	r.Get("/notifications/deliveries", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_notification_deliveries")(listDeliveriesEndpoint(svc)),
+		decodeListDeliveries,
+		types.EncodeResponse,
+		opts...,
+	))
+
+	r.GetFunc("/version", buildinfo.Version(svcName))
+	r.Handle("/metrics", promhttp.Handler())
+
+	return r
+}
+
+func decodeAddChannel(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	req := addChannelReq{token: parseJwt(r)}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeAddRule(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	req := addRuleReq{token: parseJwt(r)}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeView(_ context.Context, r *http.Request) (interface{}, error) {
+	req := viewResourceReq{
+		token: parseJwt(r),
+		id:    bone.GetValue(r, "id"),
+	}
+
+	return req, nil
+}
+
+func decodeListResources(_ context.Context, r *http.Request) (interface{}, error) {
+	return listResourcesReq{token: parseJwt(r)}, nil
+}
+
+func decodeListDeliveries(_ context.Context, r *http.Request) (interface{}, error) {
+	o, err := httputil.ReadUintQuery(r, offsetKey, defOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := httputil.ReadUintQuery(r, limitKey, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleID, err := httputil.ReadStringQuery(r, ruleIDKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	channelID, err := httputil.ReadStringQuery(r, channelIDKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	entityID, err := httputil.ReadStringQuery(r, entityIDKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := httputil.ReadStringQuery(r, statusKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	req := listDeliveriesReq{
+		token: parseJwt(r),
+		pageMetadata: notifications.DeliveryPageMetadata{
+			Offset:    o,
+			Limit:     l,
+			RuleID:    ruleID,
+			ChannelID: channelID,
+			EntityID:  entityID,
+			Status:    status,
+		},
+	}
+
+	return req, nil
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	switch errorVal := err.(type) {
+	case errors.Error:
+		w.Header().Set("Content-Type", types.ContentType)
+		switch {
+		case errors.Contains(errorVal, errors.ErrUnauthorizedAccess):
+			w.WriteHeader(http.StatusUnauthorized)
+		case errors.Contains(errorVal, errors.ErrInvalidQueryParams):
+			w.WriteHeader(http.StatusBadRequest)
+		case errors.Contains(errorVal, errors.ErrUnsupportedContentType):
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+		case errors.Contains(errorVal, errors.ErrMalformedEntity),
+			errors.Contains(errorVal, io.ErrUnexpectedEOF),
+			errors.Contains(errorVal, io.EOF):
+			w.WriteHeader(http.StatusBadRequest)
+		case errors.Contains(errorVal, errors.ErrNotFound):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.Contains(errorVal, errors.ErrConflict):
+			w.WriteHeader(http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		if errorVal.Msg() != "" {
+			if err := json.NewEncoder(w).Encode(types.ErrorRes{Err: errorVal.Msg()}); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func parseJwt(r *http.Request) (token string) {
+	if strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		token = r.Header.Get("Authorization")[7:]
+	}
+	return
+}
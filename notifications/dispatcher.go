@@ -0,0 +1,130 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/orb-community/orb/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// DispatchFreq how often to send the deliveries due
+	DispatchFreq = 5 * time.Second
+	// MaxAttempts is how many times a delivery is sent before it is failed
+	MaxAttempts = 6
+	// BaseRetryDelay is the delay before the first retry, doubling on each further one up to MaxRetryDelay
+	BaseRetryDelay = 10 * time.Second
+	MaxRetryDelay  = 10 * time.Minute
+
+	dispatchBatch = 100
+	// claimTimeout is how long the deliveries of a batch are kept from the other dispatchers, once gone by they are
+	// sent again, as when the dispatcher stopped before recording their attempt
+	claimTimeout = 30 * time.Minute
+)
+
+// Backoff returns the delay before the next attempt of a delivery already attempted the given number of times
+func Backoff(attempts int) time.Duration {
+	delay := BaseRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= MaxRetryDelay {
+			return MaxRetryDelay
+		}
+	}
+	return delay
+}
+
+// Dispatcher sends the pending deliveries to their channels
+type Dispatcher interface {
+	// Run dispatches the deliveries due every DispatchFreq until the context is done
+	Run(ctx context.Context)
+	// Dispatch sends the deliveries due at the given time, recording the outcome of each attempt
+	Dispatch(ctx context.Context, now time.Time)
+}
+
+var _ Dispatcher = (*dispatcher)(nil)
+
+type dispatcher struct {
+	logger       *zap.Logger
+	channelRepo  ChannelRepository
+	deliveryRepo DeliveryRepository
+	sender       Sender
+}
+
+// NewDispatcher returns a dispatcher sending the deliveries with the sender
+func NewDispatcher(logger *zap.Logger, channelRepo ChannelRepository, deliveryRepo DeliveryRepository, sender Sender) Dispatcher {
+	return &dispatcher{
+		logger:       logger.Named("notifications_dispatcher"),
+		channelRepo:  channelRepo,
+		deliveryRepo: deliveryRepo,
+		sender:       sender,
+	}
+}
+
+func (d *dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(DispatchFreq)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			d.logger.Info("stopping notifications dispatcher")
+			return
+		case t := <-ticker.C:
+			d.Dispatch(ctx, t)
+		}
+	}
+}
+
+func (d *dispatcher) Dispatch(ctx context.Context, now time.Time) {
+	due, err := d.deliveryRepo.ClaimDueDeliveries(ctx, now, now.Add(claimTimeout), dispatchBatch)
+	if err != nil {
+		d.logger.Error("failed to retrieve the deliveries due", zap.Error(err))
+		return
+	}
+
+	for _, delivery := range due {
+		d.attempt(ctx, delivery, now)
+	}
+}
+
+func (d *dispatcher) attempt(ctx context.Context, delivery Delivery, now time.Time) {
+	delivery.Attempts++
+	delivery.Updated = now
+
+	channel, err := d.channelRepo.RetrieveChannelByID(ctx, delivery.MFOwnerID, delivery.ChannelID)
+	switch {
+	case errors.Contains(err, errors.ErrNotFound):
+		// the channel was removed since the delivery was queued
+		delivery.Status = DeliveryFailed
+		delivery.LastError = "channel not found"
+	case err != nil:
+		d.logger.Error("failed to retrieve the channel of a delivery", zap.String("delivery_id", delivery.ID), zap.Error(err))
+		return
+	default:
+		err = d.sender.Send(ctx, channel, delivery)
+		switch {
+		case err == nil:
+			delivery.Status = DeliveryDelivered
+			delivery.LastError = ""
+		case delivery.Attempts >= MaxAttempts:
+			delivery.Status = DeliveryFailed
+			delivery.LastError = err.Error()
+		default:
+			delivery.LastError = err.Error()
+			delivery.NextAttempt = now.Add(Backoff(delivery.Attempts))
+		}
+	}
+
+	if delivery.Status == DeliveryFailed {
+		d.logger.Warn("notification delivery failed", zap.String("delivery_id", delivery.ID),
+			zap.String("channel_id", delivery.ChannelID), zap.Int("attempts", delivery.Attempts), zap.String("error", delivery.LastError))
+	}
+	if err := d.deliveryRepo.UpdateDelivery(ctx, delivery); err != nil {
+		d.logger.Error("failed to update delivery", zap.String("delivery_id", delivery.ID), zap.Error(err))
+	}
+}
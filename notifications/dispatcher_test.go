@@ -0,0 +1,160 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package notifications_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/orb-community/orb/notifications"
+	"github.com/orb-community/orb/notifications/mocks"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type receiver struct {
+	mu       sync.Mutex
+	failures int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, body)
+	if rc.failures > 0 {
+		rc.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestDispatch(t *testing.T) {
+	rc := &receiver{failures: 1}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	ctx := context.Background()
+	channelRepo := mocks.NewChannelRepository()
+	deliveryRepo := mocks.NewDeliveryRepository()
+	dispatcher := notifications.NewDispatcher(zap.NewNop(), channelRepo, deliveryRepo, notifications.NewLoopbackSender(time.Second))
+
+	name, _ := types.NewIdentifier("ops")
+	webhookID, err := channelRepo.SaveChannel(ctx, notifications.Channel{MFOwnerID: email, Name: name, Type: notifications.ChannelWebhook, URL: server.URL, Secret: "s3cr3t"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	now := time.Now()
+	event := notifications.StateEvent{OwnerID: email, Entity: notifications.EntitySink, EntityID: "sink-1", EntityName: "prom", State: notifications.StateError, Message: "401 Unauthorized", Timestamp: now}
+	id, err := deliveryRepo.SaveDelivery(ctx, notifications.Delivery{MFOwnerID: email, ChannelID: webhookID, DedupKey: event.DedupKey(), Event: event, Status: notifications.DeliveryPending, NextAttempt: now})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	delivery := func() notifications.Delivery {
+		page, err := deliveryRepo.RetrieveDeliveries(ctx, email, notifications.DeliveryPageMetadata{Limit: 10, ChannelID: webhookID})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		require.Len(t, page.Deliveries, 1, "expected a single delivery")
+		return page.Deliveries[0]
+	}
+
+	// the first attempt is refused, so the delivery is retried after the backoff
+	dispatcher.Dispatch(ctx, now)
+	d := delivery()
+	assert.Equal(t, notifications.DeliveryPending, d.Status, "expected the delivery to stay pending")
+	assert.Equal(t, 1, d.Attempts, "expected a single attempt")
+	assert.Equal(t, now.Add(notifications.Backoff(1)), d.NextAttempt, "expected the next attempt after the backoff")
+	assert.NotEmpty(t, d.LastError, "expected the error of the attempt to be recorded")
+
+	dispatcher.Dispatch(ctx, now.Add(time.Second))
+	assert.Equal(t, 1, delivery().Attempts, "expected no attempt before the backoff")
+
+	dispatcher.Dispatch(ctx, d.NextAttempt)
+	d = delivery()
+	assert.Equal(t, notifications.DeliveryDelivered, d.Status, "expected the delivery to be delivered")
+	assert.Equal(t, 2, d.Attempts, "expected two attempts")
+	assert.Empty(t, d.LastError, "expected the error to be cleared")
+
+	require.Len(t, rc.requests, 2, "expected the receiver to get two requests")
+	req, body := rc.requests[1], rc.bodies[1]
+	assert.Equal(t, notifications.Sign("s3cr3t", body), req.Header.Get(notifications.SignatureHeader), "expected the body to be signed")
+	assert.Equal(t, id, req.Header.Get(notifications.DeliveryHeader), "expected the delivery id header")
+	assert.Equal(t, "sink.error", req.Header.Get(notifications.EventHeader), "expected the event header")
+	var payload notifications.WebhookPayload
+	require.Nil(t, json.Unmarshal(body, &payload), "expected a JSON payload")
+	assert.Equal(t, "sink-1", payload.EntityID, "expected the entity of the event")
+	assert.Equal(t, "401 Unauthorized", payload.Message, "expected the message of the event")
+}
+
+func TestDispatchFailure(t *testing.T) {
+	rc := &receiver{failures: notifications.MaxAttempts}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	ctx := context.Background()
+	channelRepo := mocks.NewChannelRepository()
+	deliveryRepo := mocks.NewDeliveryRepository()
+	dispatcher := notifications.NewDispatcher(zap.NewNop(), channelRepo, deliveryRepo, notifications.NewLoopbackSender(time.Second))
+
+	name, _ := types.NewIdentifier("slack")
+	slackID, err := channelRepo.SaveChannel(ctx, notifications.Channel{MFOwnerID: email, Name: name, Type: notifications.ChannelSlack, URL: server.URL})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	now := time.Now()
+	event := notifications.StateEvent{OwnerID: email, Entity: notifications.EntityAgent, EntityID: "agent-1", EntityName: "edge", State: notifications.StateStale}
+	_, err = deliveryRepo.SaveDelivery(ctx, notifications.Delivery{MFOwnerID: email, ChannelID: slackID, Event: event, Status: notifications.DeliveryPending, NextAttempt: now})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = deliveryRepo.SaveDelivery(ctx, notifications.Delivery{MFOwnerID: email, ChannelID: "removed", Event: event, Status: notifications.DeliveryPending, NextAttempt: now})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	for i := 0; i < notifications.MaxAttempts; i++ {
+		dispatcher.Dispatch(ctx, now.Add(time.Duration(i)*notifications.MaxRetryDelay))
+	}
+
+	page, err := deliveryRepo.RetrieveDeliveries(ctx, email, notifications.DeliveryPageMetadata{Limit: 10, Status: notifications.DeliveryFailed})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Len(t, page.Deliveries, 2, "expected both deliveries to fail")
+	for _, d := range page.Deliveries {
+		if d.ChannelID == slackID {
+			assert.Equal(t, notifications.MaxAttempts, d.Attempts, "expected the delivery to be attempted MaxAttempts times")
+		} else {
+			assert.Equal(t, 1, d.Attempts, "expected the delivery to a removed channel to fail at once")
+		}
+	}
+
+	require.Len(t, rc.bodies, notifications.MaxAttempts, "expected an attempt per dispatch")
+	assert.JSONEq(t, `{"text": "Orb: agent edge is stale"}`, string(rc.bodies[0]), "expected a slack payload")
+	assert.Empty(t, rc.requests[0].Header.Get(notifications.SignatureHeader), "expected slack payloads not to be signed")
+}
+
+func TestSendRestrictions(t *testing.T) {
+	rc := &receiver{}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+	redirect := httptest.NewServer(http.RedirectHandler(server.URL, http.StatusFound))
+	defer redirect.Close()
+
+	name, _ := types.NewIdentifier("ops")
+	event := notifications.StateEvent{OwnerID: email, Entity: notifications.EntityAgent, EntityID: "agent-1", State: notifications.StateStale}
+	delivery := notifications.Delivery{ID: "delivery-1", Event: event}
+
+	err := notifications.NewSender(time.Second).Send(context.Background(), notifications.Channel{Name: name, Type: notifications.ChannelWebhook, URL: server.URL}, delivery)
+	assert.NotNil(t, err, "expected a loopback channel to be refused")
+
+	err = notifications.NewLoopbackSender(time.Second).Send(context.Background(), notifications.Channel{Name: name, Type: notifications.ChannelWebhook, URL: redirect.URL}, delivery)
+	assert.NotNil(t, err, "expected the redirect not to be followed")
+	assert.Empty(t, rc.requests, "expected the receiver to get no request")
+}
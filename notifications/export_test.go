@@ -0,0 +1,15 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package notifications
+
+import (
+	"net"
+	"time"
+)
+
+// NewLoopbackSender returns a sender also connecting to loopback addresses, where the test receivers listen
+func NewLoopbackSender(timeout time.Duration) Sender {
+	return newSender(timeout, func(ip net.IP) bool { return ip.IsLoopback() || publicIP(ip) })
+}
@@ -0,0 +1,80 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/orb-community/orb/notifications"
+	"github.com/orb-community/orb/pkg/errors"
+)
+
+var _ notifications.ChannelRepository = (*channelRepositoryMock)(nil)
+
+type channelRepositoryMock struct {
+	mu       sync.Mutex
+	counter  int
+	channels map[string]notifications.Channel
+}
+
+// NewChannelRepository returns an in memory repository of channels
+func NewChannelRepository() notifications.ChannelRepository {
+	return &channelRepositoryMock{channels: map[string]notifications.Channel{}}
+}
+
+func (r *channelRepositoryMock) SaveChannel(_ context.Context, c notifications.Channel) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, ch := range r.channels {
+		if ch.MFOwnerID == c.MFOwnerID && ch.Name == c.Name {
+			return "", errors.ErrConflict
+		}
+	}
+	r.counter++
+	c.ID = strconv.Itoa(r.counter)
+	r.channels[c.ID] = c
+	return c.ID, nil
+}
+
+func (r *channelRepositoryMock) RetrieveChannelByID(_ context.Context, ownerID string, id string) (notifications.Channel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.channels[id]
+	if !ok || c.MFOwnerID != ownerID {
+		return notifications.Channel{}, errors.ErrNotFound
+	}
+	return c, nil
+}
+
+func (r *channelRepositoryMock) RetrieveChannels(_ context.Context, ownerID string) ([]notifications.Channel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var channels []notifications.Channel
+	for _, c := range r.channels {
+		if c.MFOwnerID == ownerID {
+			channels = append(channels, c)
+		}
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].Name.String() < channels[j].Name.String()
+	})
+	return channels, nil
+}
+
+func (r *channelRepositoryMock) DeleteChannel(_ context.Context, ownerID string, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c, ok := r.channels[id]; ok && c.MFOwnerID == ownerID {
+		delete(r.channels, id)
+	}
+	return nil
+}
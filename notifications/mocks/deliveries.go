@@ -0,0 +1,106 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package mocks
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/orb-community/orb/notifications"
+	"github.com/orb-community/orb/pkg/errors"
+)
+
+var _ notifications.DeliveryRepository = (*deliveryRepositoryMock)(nil)
+
+type deliveryRepositoryMock struct {
+	mu         sync.Mutex
+	deliveries []notifications.Delivery
+}
+
+// NewDeliveryRepository returns an in memory repository of deliveries
+func NewDeliveryRepository() notifications.DeliveryRepository {
+	return &deliveryRepositoryMock{}
+}
+
+func (r *deliveryRepositoryMock) SaveDelivery(_ context.Context, d notifications.Delivery) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d.ID = strconv.Itoa(len(r.deliveries) + 1)
+	r.deliveries = append(r.deliveries, d)
+	return d.ID, nil
+}
+
+func (r *deliveryRepositoryMock) UpdateDelivery(_ context.Context, d notifications.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, saved := range r.deliveries {
+		if saved.ID == d.ID {
+			r.deliveries[i] = d
+			return nil
+		}
+	}
+	return errors.ErrNotFound
+}
+
+func (r *deliveryRepositoryMock) RetrieveLastDelivery(_ context.Context, ownerID string, channelID string, dedupKey string) (notifications.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := len(r.deliveries) - 1; i >= 0; i-- {
+		d := r.deliveries[i]
+		if d.MFOwnerID == ownerID && d.ChannelID == channelID && d.DedupKey == dedupKey {
+			return d, nil
+		}
+	}
+	return notifications.Delivery{}, errors.ErrNotFound
+}
+
+func (r *deliveryRepositoryMock) ClaimDueDeliveries(_ context.Context, now time.Time, until time.Time, limit uint64) ([]notifications.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []notifications.Delivery
+	for i, d := range r.deliveries {
+		if d.Status == notifications.DeliveryPending && !d.NextAttempt.After(now) && uint64(len(due)) < limit {
+			d.NextAttempt = until
+			r.deliveries[i] = d
+			due = append(due, d)
+		}
+	}
+	return due, nil
+}
+
+func (r *deliveryRepositoryMock) RetrieveDeliveries(_ context.Context, ownerID string, pm notifications.DeliveryPageMetadata) (notifications.DeliveryPage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var matching []notifications.Delivery
+	for i := len(r.deliveries) - 1; i >= 0; i-- {
+		d := r.deliveries[i]
+		if d.MFOwnerID != ownerID ||
+			pm.RuleID != "" && d.RuleID != pm.RuleID ||
+			pm.ChannelID != "" && d.ChannelID != pm.ChannelID ||
+			pm.EntityID != "" && d.Event.EntityID != pm.EntityID ||
+			pm.Status != "" && d.Status != pm.Status {
+			continue
+		}
+		matching = append(matching, d)
+	}
+
+	page := notifications.DeliveryPage{DeliveryPageMetadata: pm, Total: uint64(len(matching))}
+	if pm.Offset >= uint64(len(matching)) {
+		return page, nil
+	}
+	end := pm.Offset + pm.Limit
+	if end > uint64(len(matching)) {
+		end = uint64(len(matching))
+	}
+	page.Deliveries = matching[pm.Offset:end]
+	return page, nil
+}
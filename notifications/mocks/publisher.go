@@ -0,0 +1,38 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/orb-community/orb/notifications"
+)
+
+var _ notifications.Publisher = (*PublisherMock)(nil)
+
+// PublisherMock keeps the state changes published, for the tests of the services publishing them
+type PublisherMock struct {
+	mu     sync.Mutex
+	events []notifications.StateEvent
+}
+
+// NewPublisher returns a publisher keeping the state changes in memory
+func NewPublisher() *PublisherMock {
+	return &PublisherMock{}
+}
+
+func (p *PublisherMock) Publish(_ context.Context, e notifications.StateEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, e)
+}
+
+// Events returns the state changes published so far
+func (p *PublisherMock) Events() []notifications.StateEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]notifications.StateEvent(nil), p.events...)
+}
@@ -0,0 +1,62 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package mocks
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/orb-community/orb/notifications"
+)
+
+var _ notifications.RuleRepository = (*ruleRepositoryMock)(nil)
+
+type ruleRepositoryMock struct {
+	mu      sync.Mutex
+	counter int
+	rules   []notifications.Rule
+}
+
+// NewRuleRepository returns an in memory repository of rules
+func NewRuleRepository() notifications.RuleRepository {
+	return &ruleRepositoryMock{}
+}
+
+func (r *ruleRepositoryMock) SaveRule(_ context.Context, rule notifications.Rule) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.counter++
+	rule.ID = strconv.Itoa(r.counter)
+	r.rules = append(r.rules, rule)
+	return rule.ID, nil
+}
+
+func (r *ruleRepositoryMock) RetrieveRules(_ context.Context, ownerID string) ([]notifications.Rule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var rules []notifications.Rule
+	for _, rule := range r.rules {
+		if rule.MFOwnerID == ownerID {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (r *ruleRepositoryMock) DeleteRule(_ context.Context, ownerID string, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, rule := range r.rules {
+		if rule.ID == id && rule.MFOwnerID == ownerID {
+			r.rules = append(r.rules[:i], r.rules[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,315 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package notifications
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+)
+
+const (
+	// StreamID is the Redis stream the services publish the state changes owners can be notified about to
+	StreamID = "orb.notifications"
+
+	EntityAgent  = "agent"
+	EntityPolicy = "policy"
+	EntitySink   = "sink"
+
+	StateStale         = "stale"
+	StateFailedToApply = "failed_to_apply"
+	StateNoTapMatch    = "no_tap_match"
+	StateError         = "error"
+
+	// ChannelWebhook posts the notification as JSON, signed with the secret of the channel when it has one
+	ChannelWebhook = "webhook"
+	// ChannelSlack posts the notification as a Slack-compatible incoming webhook message
+	ChannelSlack = "slack"
+
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+var (
+	// ErrMalformedEvent indicates a state change read from the stream which can not be decoded
+	ErrMalformedEvent = errors.New("malformed notification event")
+
+	// ErrInvalidChannel indicates a channel of an unknown type or without a valid URL
+	ErrInvalidChannel = errors.New("invalid notification channel")
+
+	// ErrInvalidRule indicates a rule on an unknown entity or state, or without channels
+	ErrInvalidRule = errors.New("invalid notification rule")
+
+	// ErrRemoveEntity indicates error while removing a channel or rule
+	ErrRemoveEntity = errors.New("failed to remove entity")
+
+	// entityStates are the states each entity can be notified about
+	entityStates = map[string][]string{
+		EntityAgent:  {StateStale},
+		EntityPolicy: {StateFailedToApply, StateNoTapMatch},
+		EntitySink:   {StateError},
+	}
+)
+
+// StateEvent is a state change of an entity of an owner
+type StateEvent struct {
+	OwnerID    string
+	Entity     string
+	EntityID   string
+	EntityName string
+	State      string
+	Message    string
+	// AgentID and AgentName identify the agent reporting the state of a policy
+	AgentID   string
+	AgentName string
+	Timestamp time.Time
+}
+
+// DedupKey identifies the state changes which are the same notification, so a policy failing on two agents is
+// notified twice but a sink going back and forth in error is notified once per DedupWindow
+func (e StateEvent) DedupKey() string {
+	return strings.Join([]string{e.Entity, e.EntityID, e.State, e.AgentID}, ":")
+}
+
+// Encode lays the event out as the values of a stream message
+func (e StateEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"owner_id":    e.OwnerID,
+		"entity":      e.Entity,
+		"entity_id":   e.EntityID,
+		"entity_name": e.EntityName,
+		"state":       e.State,
+		"message":     e.Message,
+		"agent_id":    e.AgentID,
+		"agent_name":  e.AgentName,
+		"timestamp":   e.Timestamp.UnixNano(),
+	}
+}
+
+// Decode reads an event out of the values of a stream message
+func Decode(values map[string]interface{}) (StateEvent, error) {
+	read := func(key string) string {
+		val, _ := values[key].(string)
+		return val
+	}
+
+	e := StateEvent{
+		OwnerID:    read("owner_id"),
+		Entity:     read("entity"),
+		EntityID:   read("entity_id"),
+		EntityName: read("entity_name"),
+		State:      read("state"),
+		Message:    read("message"),
+		AgentID:    read("agent_id"),
+		AgentName:  read("agent_name"),
+	}
+	if e.OwnerID == "" || e.Entity == "" || e.EntityID == "" || e.State == "" {
+		return StateEvent{}, ErrMalformedEvent
+	}
+
+	ts, err := strconv.ParseInt(read("timestamp"), 10, 64)
+	if err != nil {
+		return StateEvent{}, errors.Wrap(ErrMalformedEvent, err)
+	}
+	e.Timestamp = time.Unix(0, ts).UTC()
+
+	return e, nil
+}
+
+// Channel is where the notifications of an owner are sent to
+type Channel struct {
+	ID        string
+	MFOwnerID string
+	Name      types.Identifier
+	Type      string
+	URL       string
+	// Secret signs the payloads of webhook channels, it is never returned once set
+	Secret  string
+	Created time.Time
+}
+
+// Validate checks the channel has a name, its type is known and its URL is an absolute http(s) one, not on a local or
+// private address. Hostnames are checked again once resolved when sending
+func (c Channel) Validate() error {
+	if !c.Name.IsValid() {
+		return errors.Wrap(ErrInvalidChannel, errors.New("a valid name is required"))
+	}
+	if c.Type != ChannelWebhook && c.Type != ChannelSlack {
+		return errors.Wrap(ErrInvalidChannel, errors.New("type must be webhook or slack"))
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Wrap(ErrInvalidChannel, errors.New("url must be an absolute http or https url"))
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errors.Wrap(ErrInvalidChannel, errors.New("url must not be on a local address"))
+	}
+	if ip := net.ParseIP(host); ip != nil && !publicIP(ip) {
+		return errors.Wrap(ErrInvalidChannel, errors.New("url must not be on a local or private address"))
+	}
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, private although net.IP.IsPrivate doesn't tell
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// publicIP tells whether notifications can be sent to the address, loopback, link-local and private ones are kept
+// for the services of the deployment
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() && !ip.IsUnspecified() && !sharedAddressSpace.Contains(ip)
+}
+
+// Rule sends the state changes of an entity kind to channels. With EntityID set it only applies to that entity
+type Rule struct {
+	ID         string
+	MFOwnerID  string
+	Name       types.Identifier
+	Entity     string
+	EntityID   string
+	States     []string
+	ChannelIDs []string
+	Created    time.Time
+}
+
+// Validate checks the rule has a name, is on a known entity and its states, and sends to at least one channel
+func (r Rule) Validate() error {
+	if !r.Name.IsValid() {
+		return errors.Wrap(ErrInvalidRule, errors.New("a valid name is required"))
+	}
+	states, ok := entityStates[r.Entity]
+	if !ok {
+		return errors.Wrap(ErrInvalidRule, errors.New("entity must be agent, policy or sink"))
+	}
+	if len(r.States) == 0 {
+		return errors.Wrap(ErrInvalidRule, errors.New("at least one state is required"))
+	}
+	for _, s := range r.States {
+		if !contains(states, s) {
+			return errors.Wrap(ErrInvalidRule, errors.New("state "+s+" can not be notified for "+r.Entity))
+		}
+	}
+	if len(r.ChannelIDs) == 0 {
+		return errors.Wrap(ErrInvalidRule, errors.New("at least one channel is required"))
+	}
+	return nil
+}
+
+// Matches tells whether the state change is one the rule sends
+func (r Rule) Matches(e StateEvent) bool {
+	if r.Entity != e.Entity || (r.EntityID != "" && r.EntityID != e.EntityID) {
+		return false
+	}
+	return contains(r.States, e.State)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery records the sending of a state change to a channel, along with its attempts
+type Delivery struct {
+	ID          string
+	MFOwnerID   string
+	RuleID      string
+	ChannelID   string
+	DedupKey    string
+	Event       StateEvent
+	Status      string
+	Attempts    int
+	LastError   string
+	NextAttempt time.Time
+	Created     time.Time
+	Updated     time.Time
+}
+
+// DeliveryPageMetadata filters the deliveries of an owner, zero values match every delivery
+type DeliveryPageMetadata struct {
+	Offset    uint64
+	Limit     uint64
+	RuleID    string
+	ChannelID string
+	EntityID  string
+	Status    string
+}
+
+// DeliveryPage holds a page of deliveries, newest first
+type DeliveryPage struct {
+	DeliveryPageMetadata
+	Total      uint64
+	Deliveries []Delivery
+}
+
+type Service interface {
+	// CreateChannel adds a channel to the owner of the token
+	CreateChannel(ctx context.Context, token string, c Channel) (Channel, error)
+	// ListChannels retrieves the channels of the owner of the token
+	ListChannels(ctx context.Context, token string) ([]Channel, error)
+	// ViewChannel retrieves a channel of the owner of the token
+	ViewChannel(ctx context.Context, token string, id string) (Channel, error)
+	// RemoveChannel removes a channel, and drops it from the rules sending to it
+	RemoveChannel(ctx context.Context, token string, id string) error
+
+	// CreateRule adds a rule to the owner of the token, its channels must belong to the owner
+	CreateRule(ctx context.Context, token string, r Rule) (Rule, error)
+	// ListRules retrieves the rules of the owner of the token
+	ListRules(ctx context.Context, token string) ([]Rule, error)
+	// RemoveRule removes a rule of the owner of the token
+	RemoveRule(ctx context.Context, token string, id string) error
+
+	// ListDeliveries retrieves the delivery history of the owner of the token
+	ListDeliveries(ctx context.Context, token string, pm DeliveryPageMetadata) (DeliveryPage, error)
+
+	// HandleStateEvent queues a delivery of a state change read from the stream to every channel of the rules it
+	// matches, unless the same notification was already sent to the channel within DedupWindow
+	HandleStateEvent(ctx context.Context, e StateEvent) error
+}
+
+type ChannelRepository interface {
+	// SaveChannel persists a channel, returning its id
+	SaveChannel(ctx context.Context, c Channel) (string, error)
+	// RetrieveChannelByID retrieves a channel of the owner
+	RetrieveChannelByID(ctx context.Context, ownerID string, id string) (Channel, error)
+	// RetrieveChannels retrieves the channels of the owner
+	RetrieveChannels(ctx context.Context, ownerID string) ([]Channel, error)
+	// DeleteChannel removes a channel of the owner, and drops it from the rules of the owner
+	DeleteChannel(ctx context.Context, ownerID string, id string) error
+}
+
+type RuleRepository interface {
+	// SaveRule persists a rule, returning its id
+	SaveRule(ctx context.Context, r Rule) (string, error)
+	// RetrieveRules retrieves the rules of the owner
+	RetrieveRules(ctx context.Context, ownerID string) ([]Rule, error)
+	// DeleteRule removes a rule of the owner
+	DeleteRule(ctx context.Context, ownerID string, id string) error
+}
+
+type DeliveryRepository interface {
+	// SaveDelivery persists a delivery, returning its id
+	SaveDelivery(ctx context.Context, d Delivery) (string, error)
+	// UpdateDelivery persists the status, attempts, last error and next attempt of a delivery
+	UpdateDelivery(ctx context.Context, d Delivery) error
+	// RetrieveLastDelivery retrieves the latest delivery of the dedup key to the channel
+	RetrieveLastDelivery(ctx context.Context, ownerID string, channelID string, dedupKey string) (Delivery, error)
+	// ClaimDueDeliveries takes the pending deliveries whose next attempt is due, oldest first, moving their next
+	// attempt to until so no other dispatcher takes them meanwhile. They are taken again then if left pending
+	ClaimDueDeliveries(ctx context.Context, now time.Time, until time.Time, limit uint64) ([]Delivery, error)
+	// RetrieveDeliveries retrieves the deliveries of the owner matching the page metadata
+	RetrieveDeliveries(ctx context.Context, ownerID string, pm DeliveryPageMetadata) (DeliveryPage, error)
+}
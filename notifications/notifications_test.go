@@ -0,0 +1,189 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package notifications_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/orb-community/orb/notifications"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelValidate(t *testing.T) {
+	name, err := types.NewIdentifier("ops")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		channel notifications.Channel
+		err     error
+	}{
+		"valid webhook channel": {
+			channel: notifications.Channel{Name: name, Type: notifications.ChannelWebhook, URL: "https://example.com/hook", Secret: "s3cr3t"},
+			err:     nil,
+		},
+		"valid slack channel": {
+			channel: notifications.Channel{Name: name, Type: notifications.ChannelSlack, URL: "https://hooks.slack.com/services/T/B/X"},
+			err:     nil,
+		},
+		"channel without a name": {
+			channel: notifications.Channel{Type: notifications.ChannelWebhook, URL: "https://example.com/hook"},
+			err:     notifications.ErrInvalidChannel,
+		},
+		"channel of an unknown type": {
+			channel: notifications.Channel{Name: name, Type: "email", URL: "https://example.com/hook"},
+			err:     notifications.ErrInvalidChannel,
+		},
+		"channel with a relative url": {
+			channel: notifications.Channel{Name: name, Type: notifications.ChannelWebhook, URL: "/hook"},
+			err:     notifications.ErrInvalidChannel,
+		},
+		"channel with a non http url": {
+			channel: notifications.Channel{Name: name, Type: notifications.ChannelWebhook, URL: "ftp://example.com/hook"},
+			err:     notifications.ErrInvalidChannel,
+		},
+		"channel on localhost": {
+			channel: notifications.Channel{Name: name, Type: notifications.ChannelWebhook, URL: "http://localhost:8080/hook"},
+			err:     notifications.ErrInvalidChannel,
+		},
+		"channel on a loopback address": {
+			channel: notifications.Channel{Name: name, Type: notifications.ChannelWebhook, URL: "http://127.0.0.1/hook"},
+			err:     notifications.ErrInvalidChannel,
+		},
+		"channel on a private address": {
+			channel: notifications.Channel{Name: name, Type: notifications.ChannelWebhook, URL: "http://10.0.0.12/hook"},
+			err:     notifications.ErrInvalidChannel,
+		},
+		"channel on the link-local metadata address": {
+			channel: notifications.Channel{Name: name, Type: notifications.ChannelWebhook, URL: "http://169.254.169.254/latest"},
+			err:     notifications.ErrInvalidChannel,
+		},
+		"channel on a loopback ipv6 address": {
+			channel: notifications.Channel{Name: name, Type: notifications.ChannelWebhook, URL: "http://[::1]/hook"},
+			err:     notifications.ErrInvalidChannel,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			err := tc.channel.Validate()
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+		})
+	}
+}
+
+func TestRuleValidateAndMatches(t *testing.T) {
+	name, err := types.NewIdentifier("policies")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	event := notifications.StateEvent{Entity: notifications.EntityPolicy, EntityID: "policy-1", State: notifications.StateNoTapMatch}
+
+	cases := map[string]struct {
+		rule    notifications.Rule
+		err     error
+		matches bool
+	}{
+		"rule on every policy": {
+			rule:    notifications.Rule{Name: name, Entity: notifications.EntityPolicy, States: []string{notifications.StateFailedToApply, notifications.StateNoTapMatch}, ChannelIDs: []string{"1"}},
+			err:     nil,
+			matches: true,
+		},
+		"rule on another policy": {
+			rule:    notifications.Rule{Name: name, Entity: notifications.EntityPolicy, EntityID: "policy-2", States: []string{notifications.StateNoTapMatch}, ChannelIDs: []string{"1"}},
+			err:     nil,
+			matches: false,
+		},
+		"rule on another state": {
+			rule:    notifications.Rule{Name: name, Entity: notifications.EntityPolicy, States: []string{notifications.StateFailedToApply}, ChannelIDs: []string{"1"}},
+			err:     nil,
+			matches: false,
+		},
+		"rule on a state of another entity": {
+			rule: notifications.Rule{Name: name, Entity: notifications.EntityPolicy, States: []string{notifications.StateStale}, ChannelIDs: []string{"1"}},
+			err:  notifications.ErrInvalidRule,
+		},
+		"rule on an unknown entity": {
+			rule: notifications.Rule{Name: name, Entity: "dataset", States: []string{notifications.StateError}, ChannelIDs: []string{"1"}},
+			err:  notifications.ErrInvalidRule,
+		},
+		"rule without channels": {
+			rule: notifications.Rule{Name: name, Entity: notifications.EntitySink, States: []string{notifications.StateError}},
+			err:  notifications.ErrInvalidRule,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			err := tc.rule.Validate()
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+			if tc.err == nil {
+				assert.Equal(t, tc.matches, tc.rule.Matches(event), fmt.Sprintf("%s: unexpected match", desc))
+			}
+		})
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	e := notifications.StateEvent{
+		OwnerID:    "owner",
+		Entity:     notifications.EntityPolicy,
+		EntityID:   "policy-1",
+		EntityName: "dns",
+		State:      notifications.StateFailedToApply,
+		Message:    "invalid tap",
+		AgentID:    "agent-1",
+		AgentName:  "edge",
+		Timestamp:  time.Unix(1700000000, 42).UTC(),
+	}
+
+	// the values of stream messages are read back as strings
+	read := map[string]interface{}{}
+	for k, v := range e.Encode() {
+		read[k] = fmt.Sprint(v)
+	}
+	decoded, err := notifications.Decode(read)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, e, decoded, "expected the decoded event to match the encoded one")
+
+	delete(read, "state")
+	_, err = notifications.Decode(read)
+	assert.True(t, errors.Contains(err, notifications.ErrMalformedEvent), fmt.Sprintf("expected %s got %s", notifications.ErrMalformedEvent, err))
+}
+
+func TestSummary(t *testing.T) {
+	cases := map[string]struct {
+		event   notifications.StateEvent
+		summary string
+	}{
+		"stale agent": {
+			event:   notifications.StateEvent{Entity: notifications.EntityAgent, EntityID: "agent-1", EntityName: "edge", State: notifications.StateStale},
+			summary: "Orb: agent edge is stale",
+		},
+		"failing policy": {
+			event:   notifications.StateEvent{Entity: notifications.EntityPolicy, EntityID: "policy-1", EntityName: "dns", State: notifications.StateFailedToApply, Message: "invalid tap", AgentName: "edge"},
+			summary: "Orb: policy dns is failed_to_apply on agent edge: invalid tap",
+		},
+		"sink in error without name": {
+			event:   notifications.StateEvent{Entity: notifications.EntitySink, EntityID: "sink-1", State: notifications.StateError},
+			summary: "Orb: sink sink-1 is error",
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			assert.Equal(t, tc.summary, notifications.Summary(tc.event), fmt.Sprintf("%s: unexpected summary", desc))
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, notifications.BaseRetryDelay, notifications.Backoff(1), "expected the base delay after the first attempt")
+	assert.Equal(t, 4*notifications.BaseRetryDelay, notifications.Backoff(3), "expected the delay to double on each attempt")
+	assert.Equal(t, notifications.MaxRetryDelay, notifications.Backoff(20), "expected the delay to be capped")
+}
@@ -0,0 +1,192 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/orb-community/orb/notifications"
+	"github.com/orb-community/orb/pkg/db"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/orb-community/orb/sinks/authentication_type"
+	"go.uber.org/zap"
+)
+
+var _ notifications.ChannelRepository = (*channelRepository)(nil)
+
+type channelRepository struct {
+	db Database
+	// passwordService encrypts the secrets of the channels at rest, as sink credentials are
+	passwordService authentication_type.PasswordService
+	logger          *zap.Logger
+}
+
+// NewChannelRepository returns the repository of notification channels
+func NewChannelRepository(db Database, passwordService authentication_type.PasswordService, logger *zap.Logger) notifications.ChannelRepository {
+	return &channelRepository{db: db, passwordService: passwordService, logger: logger}
+}
+
+func (r channelRepository) SaveChannel(ctx context.Context, c notifications.Channel) (string, error) {
+	q := `INSERT INTO notification_channels (name, mf_owner_id, type, url, secret)
+			VALUES (:name, :mf_owner_id, :type, :url, :secret) RETURNING id`
+
+	if c.MFOwnerID == "" || c.Type == "" || c.URL == "" {
+		return "", errors.ErrMalformedEntity
+	}
+
+	dbc, err := r.toDBChannel(c)
+	if err != nil {
+		return "", errors.Wrap(db.ErrSaveDB, err)
+	}
+	rows, err := r.db.NamedQueryContext(ctx, q, dbc)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case db.ErrInvalid, db.ErrTruncation:
+				return "", errors.Wrap(errors.ErrMalformedEntity, err)
+			case db.ErrDuplicate:
+				return "", errors.Wrap(errors.ErrConflict, err)
+			}
+		}
+		return "", errors.Wrap(db.ErrSaveDB, err)
+	}
+	defer rows.Close()
+
+	var id string
+	if rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return "", errors.Wrap(db.ErrSaveDB, err)
+		}
+	}
+
+	return id, nil
+}
+
+func (r channelRepository) RetrieveChannelByID(ctx context.Context, ownerID string, id string) (notifications.Channel, error) {
+	q := `SELECT id, name, mf_owner_id, type, url, secret, ts_created
+			FROM notification_channels WHERE mf_owner_id = $1 AND id = $2`
+
+	if ownerID == "" || id == "" {
+		return notifications.Channel{}, errors.ErrMalformedEntity
+	}
+
+	var dbc dbChannel
+	if err := r.db.QueryRowxContext(ctx, q, ownerID, id).StructScan(&dbc); err != nil {
+		if err == sql.ErrNoRows {
+			return notifications.Channel{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == db.ErrInvalid {
+			return notifications.Channel{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return notifications.Channel{}, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+
+	return r.toChannel(dbc)
+}
+
+func (r channelRepository) RetrieveChannels(ctx context.Context, ownerID string) ([]notifications.Channel, error) {
+	q := `SELECT id, name, mf_owner_id, type, url, secret, ts_created
+			FROM notification_channels WHERE mf_owner_id = :mf_owner_id ORDER BY name`
+
+	rows, err := r.db.NamedQueryContext(ctx, q, map[string]interface{}{"mf_owner_id": ownerID})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+	defer rows.Close()
+
+	var items []notifications.Channel
+	for rows.Next() {
+		var dbc dbChannel
+		if err := rows.StructScan(&dbc); err != nil {
+			return nil, errors.Wrap(errors.ErrSelectEntity, err)
+		}
+		c, err := r.toChannel(dbc)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, c)
+	}
+
+	return items, nil
+}
+
+func (r channelRepository) DeleteChannel(ctx context.Context, ownerID string, id string) error {
+	// the rules of the owner stop sending to the channel in the same statement
+	q := `WITH deleted AS (
+				DELETE FROM notification_channels WHERE mf_owner_id = :mf_owner_id AND id = :id RETURNING id
+			)
+			UPDATE notification_rules SET channel_ids = array_remove(channel_ids, :id)
+			WHERE mf_owner_id = :mf_owner_id AND EXISTS (SELECT 1 FROM deleted)`
+
+	params := map[string]interface{}{
+		"mf_owner_id": ownerID,
+		"id":          id,
+	}
+	if _, err := r.db.NamedExecContext(ctx, q, params); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == db.ErrInvalid {
+			return errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		return errors.Wrap(notifications.ErrRemoveEntity, err)
+	}
+
+	return nil
+}
+
+type dbChannel struct {
+	ID        string           `db:"id"`
+	Name      types.Identifier `db:"name"`
+	MFOwnerID string           `db:"mf_owner_id"`
+	Type      string           `db:"type"`
+	URL       string           `db:"url"`
+	Secret    string           `db:"secret"`
+	Created   time.Time        `db:"ts_created"`
+}
+
+func (r channelRepository) toDBChannel(c notifications.Channel) (dbChannel, error) {
+	secret := c.Secret
+	if secret != "" {
+		var err error
+		if secret, err = r.passwordService.EncodePassword(secret); err != nil {
+			return dbChannel{}, err
+		}
+	}
+
+	return dbChannel{
+		ID:        c.ID,
+		Name:      c.Name,
+		MFOwnerID: c.MFOwnerID,
+		Type:      c.Type,
+		URL:       c.URL,
+		Secret:    secret,
+		Created:   c.Created,
+	}, nil
+}
+
+func (r channelRepository) toChannel(dbc dbChannel) (notifications.Channel, error) {
+	secret := dbc.Secret
+	if secret != "" {
+		var err error
+		if secret, err = r.passwordService.DecodePassword(secret); err != nil {
+			return notifications.Channel{}, errors.Wrap(errors.ErrSelectEntity, err)
+		}
+	}
+
+	return notifications.Channel{
+		ID:        dbc.ID,
+		Name:      dbc.Name,
+		MFOwnerID: dbc.MFOwnerID,
+		Type:      dbc.Type,
+		URL:       dbc.URL,
+		Secret:    secret,
+		Created:   dbc.Created,
+	}, nil
+}
@@ -0,0 +1,143 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/orb-community/orb/notifications"
+	"github.com/orb-community/orb/notifications/postgres"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/orb-community/orb/sinks/authentication_type"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var logger, _ = zap.NewDevelopment()
+
+var passwordService = authentication_type.NewPasswordService(logger, "test")
+
+func TestChannelSave(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.NewChannelRepository(dbMiddleware, passwordService, logger)
+
+	oID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	nameID, err := types.NewIdentifier("oncall")
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	channel := notifications.Channel{
+		MFOwnerID: oID.String(),
+		Name:      nameID,
+		Type:      notifications.ChannelWebhook,
+		URL:       "https://example.com/hooks/orb",
+		Secret:    "s3cr3t",
+	}
+
+	// the second save conflicts with the first one, so the cases run in order
+	tests := []struct {
+		name    string
+		channel notifications.Channel
+		err     error
+	}{
+		{
+			name:    "save a channel",
+			channel: channel,
+			err:     nil,
+		},
+		{
+			name:    "save a channel with an existing name",
+			channel: channel,
+			err:     errors.ErrConflict,
+		},
+		{
+			name:    "save a channel without owner",
+			channel: notifications.Channel{Name: nameID, Type: notifications.ChannelSlack, URL: "https://hooks.slack.com/x"},
+			err:     errors.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := repo.SaveChannel(context.Background(), tc.channel)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.name, tc.err, err))
+		})
+	}
+}
+
+func TestChannelRetrieveAndDelete(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	channelRepo := postgres.NewChannelRepository(dbMiddleware, passwordService, logger)
+	ruleRepo := postgres.NewRuleRepository(dbMiddleware, logger)
+
+	oID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	var ids []string
+	for _, name := range []string{"oncall", "team"} {
+		nameID, err := types.NewIdentifier(name)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		id, err := channelRepo.SaveChannel(context.Background(), notifications.Channel{
+			MFOwnerID: oID.String(),
+			Name:      nameID,
+			Type:      notifications.ChannelWebhook,
+			URL:       "https://example.com/hooks/" + name,
+			Secret:    "s3cr3t",
+		})
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		ids = append(ids, id)
+	}
+
+	ruleName, err := types.NewIdentifier("sinks")
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, err = ruleRepo.SaveRule(context.Background(), notifications.Rule{
+		MFOwnerID:  oID.String(),
+		Name:       ruleName,
+		Entity:     notifications.EntitySink,
+		States:     []string{notifications.StateError},
+		ChannelIDs: ids,
+	})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	channel, err := channelRepo.RetrieveChannelByID(context.Background(), oID.String(), ids[0])
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, "oncall", channel.Name.String(), "expected the oncall channel")
+	assert.Equal(t, "s3cr3t", channel.Secret, "expected the secret of the channel")
+
+	var stored string
+	err = db.QueryRowx("SELECT secret FROM notification_channels WHERE id = $1", ids[0]).Scan(&stored)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.NotContains(t, stored, "s3cr3t", "expected the secret to be encrypted at rest")
+
+	_, err = channelRepo.RetrieveChannelByID(context.Background(), oID.String(), "not-a-uuid")
+	assert.True(t, errors.Contains(err, errors.ErrNotFound), fmt.Sprintf("expected %s got %s", errors.ErrNotFound, err))
+
+	channels, err := channelRepo.RetrieveChannels(context.Background(), oID.String())
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Len(t, channels, 2, "expected the two channels of the owner")
+
+	err = channelRepo.DeleteChannel(context.Background(), oID.String(), ids[0])
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	_, err = channelRepo.RetrieveChannelByID(context.Background(), oID.String(), ids[0])
+	assert.True(t, errors.Contains(err, errors.ErrNotFound), fmt.Sprintf("expected %s got %s", errors.ErrNotFound, err))
+
+	rules, err := ruleRepo.RetrieveRules(context.Background(), oID.String())
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	require.Len(t, rules, 1, "expected the rule of the owner")
+	assert.Equal(t, []string{ids[1]}, rules[0].ChannelIDs, "expected the removed channel to be dropped from the rule")
+
+	err = ruleRepo.DeleteRule(context.Background(), oID.String(), rules[0].ID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	rules, err = ruleRepo.RetrieveRules(context.Background(), oID.String())
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Len(t, rules, 0, "expected the rule to be removed")
+}
@@ -0,0 +1,291 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/orb-community/orb/notifications"
+	"github.com/orb-community/orb/pkg/db"
+	"github.com/orb-community/orb/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var _ notifications.DeliveryRepository = (*deliveryRepository)(nil)
+
+type deliveryRepository struct {
+	db     Database
+	logger *zap.Logger
+}
+
+// NewDeliveryRepository returns the repository of notification deliveries
+func NewDeliveryRepository(db Database, logger *zap.Logger) notifications.DeliveryRepository {
+	return &deliveryRepository{db: db, logger: logger}
+}
+
+const deliveryColumns = `id, mf_owner_id, rule_id, channel_id, dedup_key, entity, entity_id, state, event, status, attempts,
+			last_error, ts_next_attempt, ts_created, ts_updated`
+
+func (r deliveryRepository) SaveDelivery(ctx context.Context, d notifications.Delivery) (string, error) {
+	q := `INSERT INTO notification_deliveries (mf_owner_id, rule_id, channel_id, dedup_key, entity, entity_id, state, event,
+			status, attempts, last_error, ts_next_attempt, ts_created, ts_updated)
+		VALUES (:mf_owner_id, :rule_id, :channel_id, :dedup_key, :entity, :entity_id, :state, :event,
+			:status, :attempts, :last_error, :ts_next_attempt, :ts_created, :ts_updated) RETURNING id`
+
+	if d.MFOwnerID == "" || d.ChannelID == "" || d.Status == "" {
+		return "", errors.ErrMalformedEntity
+	}
+
+	dbd, err := toDBDelivery(d)
+	if err != nil {
+		return "", errors.Wrap(db.ErrSaveDB, err)
+	}
+	rows, err := r.db.NamedQueryContext(ctx, q, dbd)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case db.ErrInvalid, db.ErrTruncation:
+				return "", errors.Wrap(errors.ErrMalformedEntity, err)
+			}
+		}
+		return "", errors.Wrap(db.ErrSaveDB, err)
+	}
+	defer rows.Close()
+
+	var id string
+	if rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return "", errors.Wrap(db.ErrSaveDB, err)
+		}
+	}
+
+	return id, nil
+}
+
+func (r deliveryRepository) UpdateDelivery(ctx context.Context, d notifications.Delivery) error {
+	q := `UPDATE notification_deliveries SET status = :status, attempts = :attempts, last_error = :last_error,
+			ts_next_attempt = :ts_next_attempt, ts_updated = :ts_updated
+		WHERE id = :id`
+
+	dbd, err := toDBDelivery(d)
+	if err != nil {
+		return errors.Wrap(db.ErrUpdateDB, err)
+	}
+	res, err := r.db.NamedExecContext(ctx, q, dbd)
+	if err != nil {
+		return errors.Wrap(db.ErrUpdateDB, err)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	if cnt == 0 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r deliveryRepository) RetrieveLastDelivery(ctx context.Context, ownerID string, channelID string, dedupKey string) (notifications.Delivery, error) {
+	q := fmt.Sprintf(`SELECT %s FROM notification_deliveries
+		WHERE mf_owner_id = $1 AND channel_id = $2 AND dedup_key = $3 ORDER BY ts_created DESC LIMIT 1`, deliveryColumns)
+
+	var dbd dbDelivery
+	if err := r.db.QueryRowxContext(ctx, q, ownerID, channelID, dedupKey).StructScan(&dbd); err != nil {
+		if err == sql.ErrNoRows {
+			return notifications.Delivery{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return notifications.Delivery{}, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+
+	return toDelivery(dbd)
+}
+
+func (r deliveryRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, until time.Time, limit uint64) ([]notifications.Delivery, error) {
+	// SKIP LOCKED lets concurrent dispatchers each claim different deliveries
+	q := fmt.Sprintf(`WITH claimed AS (
+			UPDATE notification_deliveries SET ts_next_attempt = :until
+			WHERE id IN (SELECT id FROM notification_deliveries WHERE status = :status AND ts_next_attempt <= :now
+				ORDER BY ts_next_attempt LIMIT :limit FOR UPDATE SKIP LOCKED)
+			RETURNING %s
+		)
+		SELECT * FROM claimed ORDER BY ts_created`, deliveryColumns)
+
+	params := map[string]interface{}{
+		"status": notifications.DeliveryPending,
+		"now":    now,
+		"until":  until,
+		"limit":  limit,
+	}
+	return r.retrieve(ctx, q, params)
+}
+
+func (r deliveryRepository) RetrieveDeliveries(ctx context.Context, ownerID string, pm notifications.DeliveryPageMetadata) (notifications.DeliveryPage, error) {
+	filter := ""
+	params := map[string]interface{}{
+		"mf_owner_id": ownerID,
+		"limit":       pm.Limit,
+		"offset":      pm.Offset,
+	}
+	if pm.RuleID != "" {
+		filter += " AND rule_id = :rule_id"
+		params["rule_id"] = pm.RuleID
+	}
+	if pm.ChannelID != "" {
+		filter += " AND channel_id = :channel_id"
+		params["channel_id"] = pm.ChannelID
+	}
+	if pm.EntityID != "" {
+		filter += " AND entity_id = :entity_id"
+		params["entity_id"] = pm.EntityID
+	}
+	if pm.Status != "" {
+		filter += " AND status = :status"
+		params["status"] = pm.Status
+	}
+
+	q := fmt.Sprintf(`SELECT %s FROM notification_deliveries WHERE mf_owner_id = :mf_owner_id%s
+		ORDER BY ts_created DESC LIMIT :limit OFFSET :offset`, deliveryColumns, filter)
+	items, err := r.retrieve(ctx, q, params)
+	if err != nil {
+		return notifications.DeliveryPage{}, err
+	}
+
+	count := fmt.Sprintf(`SELECT COUNT(*) FROM notification_deliveries WHERE mf_owner_id = :mf_owner_id%s`, filter)
+	total, err := total(ctx, r.db, count, params)
+	if err != nil {
+		return notifications.DeliveryPage{}, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+
+	return notifications.DeliveryPage{
+		DeliveryPageMetadata: pm,
+		Total:                total,
+		Deliveries:           items,
+	}, nil
+}
+
+func (r deliveryRepository) retrieve(ctx context.Context, query string, params map[string]interface{}) ([]notifications.Delivery, error) {
+	rows, err := r.db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+	defer rows.Close()
+
+	var items []notifications.Delivery
+	for rows.Next() {
+		var dbd dbDelivery
+		if err := rows.StructScan(&dbd); err != nil {
+			return nil, errors.Wrap(errors.ErrSelectEntity, err)
+		}
+		d, err := toDelivery(dbd)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrSelectEntity, err)
+		}
+		items = append(items, d)
+	}
+
+	return items, nil
+}
+
+func total(ctx context.Context, db Database, query string, params interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+type dbDelivery struct {
+	ID          string    `db:"id"`
+	MFOwnerID   string    `db:"mf_owner_id"`
+	RuleID      string    `db:"rule_id"`
+	ChannelID   string    `db:"channel_id"`
+	DedupKey    string    `db:"dedup_key"`
+	Entity      string    `db:"entity"`
+	EntityID    string    `db:"entity_id"`
+	State       string    `db:"state"`
+	Event       []byte    `db:"event"`
+	Status      string    `db:"status"`
+	Attempts    int       `db:"attempts"`
+	LastError   string    `db:"last_error"`
+	NextAttempt time.Time `db:"ts_next_attempt"`
+	Created     time.Time `db:"ts_created"`
+	Updated     time.Time `db:"ts_updated"`
+}
+
+// dbEvent is the state change of a delivery as stored in its event column
+type dbEvent struct {
+	OwnerID    string    `json:"owner_id"`
+	Entity     string    `json:"entity"`
+	EntityID   string    `json:"entity_id"`
+	EntityName string    `json:"entity_name"`
+	State      string    `json:"state"`
+	Message    string    `json:"message"`
+	AgentID    string    `json:"agent_id"`
+	AgentName  string    `json:"agent_name"`
+	Timestamp  time.Time `json:"ts"`
+}
+
+func toDBDelivery(d notifications.Delivery) (dbDelivery, error) {
+	event, err := json.Marshal(dbEvent(d.Event))
+	if err != nil {
+		return dbDelivery{}, err
+	}
+
+	return dbDelivery{
+		ID:          d.ID,
+		MFOwnerID:   d.MFOwnerID,
+		RuleID:      d.RuleID,
+		ChannelID:   d.ChannelID,
+		DedupKey:    d.DedupKey,
+		Entity:      d.Event.Entity,
+		EntityID:    d.Event.EntityID,
+		State:       d.Event.State,
+		Event:       event,
+		Status:      d.Status,
+		Attempts:    d.Attempts,
+		LastError:   d.LastError,
+		NextAttempt: d.NextAttempt,
+		Created:     d.Created,
+		Updated:     d.Updated,
+	}, nil
+}
+
+func toDelivery(dbd dbDelivery) (notifications.Delivery, error) {
+	var event dbEvent
+	if len(dbd.Event) > 0 {
+		if err := json.Unmarshal(dbd.Event, &event); err != nil {
+			return notifications.Delivery{}, err
+		}
+	}
+
+	return notifications.Delivery{
+		ID:          dbd.ID,
+		MFOwnerID:   dbd.MFOwnerID,
+		RuleID:      dbd.RuleID,
+		ChannelID:   dbd.ChannelID,
+		DedupKey:    dbd.DedupKey,
+		Event:       notifications.StateEvent(event),
+		Status:      dbd.Status,
+		Attempts:    dbd.Attempts,
+		LastError:   dbd.LastError,
+		NextAttempt: dbd.NextAttempt,
+		Created:     dbd.Created,
+		Updated:     dbd.Updated,
+	}, nil
+}
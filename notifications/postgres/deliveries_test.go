@@ -0,0 +1,168 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/orb-community/orb/notifications"
+	"github.com/orb-community/orb/notifications/postgres"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliverySaveAndUpdate(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.NewDeliveryRepository(dbMiddleware, logger)
+
+	oID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	event := notifications.StateEvent{
+		OwnerID:    oID.String(),
+		Entity:     notifications.EntitySink,
+		EntityID:   "sink-1",
+		EntityName: "prom",
+		State:      notifications.StateError,
+		Message:    "remote write failed",
+		Timestamp:  now,
+	}
+	d := notifications.Delivery{
+		MFOwnerID:   oID.String(),
+		RuleID:      "rule-1",
+		ChannelID:   "channel-1",
+		DedupKey:    event.DedupKey(),
+		Event:       event,
+		Status:      notifications.DeliveryPending,
+		NextAttempt: now,
+		Created:     now,
+		Updated:     now,
+	}
+
+	_, err = repo.SaveDelivery(context.Background(), notifications.Delivery{ChannelID: "channel-1"})
+	assert.True(t, errors.Contains(err, errors.ErrMalformedEntity), fmt.Sprintf("expected %s got %s", errors.ErrMalformedEntity, err))
+
+	d.ID, err = repo.SaveDelivery(context.Background(), d)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	last, err := repo.RetrieveLastDelivery(context.Background(), oID.String(), "channel-1", event.DedupKey())
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, d.ID, last.ID, "expected the saved delivery")
+	assert.Equal(t, event.Message, last.Event.Message, "expected the event of the delivery")
+
+	_, err = repo.RetrieveLastDelivery(context.Background(), oID.String(), "channel-2", event.DedupKey())
+	assert.True(t, errors.Contains(err, errors.ErrNotFound), fmt.Sprintf("expected %s got %s", errors.ErrNotFound, err))
+
+	due, err := repo.ClaimDueDeliveries(context.Background(), now, now.Add(time.Minute), 100)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.True(t, containsDelivery(due, d.ID), "expected the delivery to be due")
+
+	due, err = repo.ClaimDueDeliveries(context.Background(), now, now.Add(time.Minute), 100)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.False(t, containsDelivery(due, d.ID), "expected a claimed delivery not to be claimed again")
+
+	d.Attempts = 1
+	d.LastError = "channel responded with status 503"
+	d.NextAttempt = now.Add(notifications.Backoff(1))
+	d.Updated = now
+	err = repo.UpdateDelivery(context.Background(), d)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	due, err = repo.ClaimDueDeliveries(context.Background(), now, now.Add(time.Minute), 100)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.False(t, containsDelivery(due, d.ID), "expected the delivery to be retried later")
+
+	d.Status = notifications.DeliveryDelivered
+	d.Attempts = 2
+	d.LastError = ""
+	err = repo.UpdateDelivery(context.Background(), d)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	due, err = repo.ClaimDueDeliveries(context.Background(), now.Add(time.Hour), now.Add(2*time.Hour), 100)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.False(t, containsDelivery(due, d.ID), "expected a delivered delivery not to be due")
+}
+
+func TestDeliveryRetrieveAll(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.NewDeliveryRepository(dbMiddleware, logger)
+
+	oID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Now()
+	deliveries := []notifications.Delivery{
+		{RuleID: "rule-1", ChannelID: "channel-1", Status: notifications.DeliveryDelivered, Event: notifications.StateEvent{Entity: notifications.EntityAgent, EntityID: "agent-1", State: notifications.StateStale}},
+		{RuleID: "rule-1", ChannelID: "channel-2", Status: notifications.DeliveryFailed, Event: notifications.StateEvent{Entity: notifications.EntityAgent, EntityID: "agent-1", State: notifications.StateStale}},
+		{RuleID: "rule-2", ChannelID: "channel-1", Status: notifications.DeliveryPending, Event: notifications.StateEvent{Entity: notifications.EntitySink, EntityID: "sink-1", State: notifications.StateError}},
+	}
+	for i, d := range deliveries {
+		d.MFOwnerID = oID.String()
+		d.DedupKey = d.Event.DedupKey()
+		d.NextAttempt = now
+		d.Created = now.Add(time.Duration(i) * time.Second)
+		d.Updated = d.Created
+		_, err := repo.SaveDelivery(context.Background(), d)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	cases := map[string]struct {
+		pm    notifications.DeliveryPageMetadata
+		total uint64
+	}{
+		"retrieve all deliveries": {
+			pm:    notifications.DeliveryPageMetadata{Limit: 10},
+			total: 3,
+		},
+		"retrieve deliveries of a rule": {
+			pm:    notifications.DeliveryPageMetadata{Limit: 10, RuleID: "rule-1"},
+			total: 2,
+		},
+		"retrieve deliveries to a channel": {
+			pm:    notifications.DeliveryPageMetadata{Limit: 10, ChannelID: "channel-1"},
+			total: 2,
+		},
+		"retrieve deliveries of an entity": {
+			pm:    notifications.DeliveryPageMetadata{Limit: 10, EntityID: "sink-1"},
+			total: 1,
+		},
+		"retrieve failed deliveries": {
+			pm:    notifications.DeliveryPageMetadata{Limit: 10, Status: notifications.DeliveryFailed},
+			total: 1,
+		},
+		"retrieve deliveries with a page": {
+			pm:    notifications.DeliveryPageMetadata{Limit: 1},
+			total: 3,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			page, err := repo.RetrieveDeliveries(context.Background(), oID.String(), tc.pm)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+			assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d", desc, tc.total, page.Total))
+			size := tc.total
+			if size > tc.pm.Limit {
+				size = tc.pm.Limit
+			}
+			assert.Len(t, page.Deliveries, int(size), fmt.Sprintf("%s: expected %d deliveries", desc, size))
+		})
+	}
+}
+
+func containsDelivery(deliveries []notifications.Delivery, id string) bool {
+	for _, d := range deliveries {
+		if d.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // required for SQL access
+	"github.com/orb-community/orb/pkg/config"
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Connect creates a connection to the PostgreSQL instance and applies any
+// unapplied database migrations. A non-nil error is returned to indicate
+// failure.
+func Connect(cfg config.PostgresConfig) (*sqlx.DB, error) {
+	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.Host, cfg.Port, cfg.User, cfg.DB, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+
+	db, err := sqlx.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "notifications_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS notification_channels (
+						id          UUID NOT NULL DEFAULT gen_random_uuid(),
+						name        TEXT NOT NULL,
+						mf_owner_id UUID NOT NULL,
+						type        TEXT NOT NULL,
+						url         TEXT NOT NULL,
+						secret      TEXT NOT NULL DEFAULT '',
+						ts_created  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+						PRIMARY KEY (id),
+						UNIQUE (mf_owner_id, name)
+					)`,
+					`CREATE TABLE IF NOT EXISTS notification_rules (
+						id          UUID NOT NULL DEFAULT gen_random_uuid(),
+						name        TEXT NOT NULL,
+						mf_owner_id UUID NOT NULL,
+						entity      TEXT NOT NULL,
+						entity_id   TEXT NOT NULL DEFAULT '',
+						states      TEXT[] NOT NULL,
+						channel_ids TEXT[] NOT NULL,
+						ts_created  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+						PRIMARY KEY (id)
+					)`,
+					`CREATE INDEX ON notification_rules (mf_owner_id)`,
+					`CREATE TABLE IF NOT EXISTS notification_deliveries (
+						id              UUID NOT NULL DEFAULT gen_random_uuid(),
+						mf_owner_id     UUID NOT NULL,
+						rule_id         TEXT NOT NULL DEFAULT '',
+						channel_id      TEXT NOT NULL,
+						dedup_key       TEXT NOT NULL,
+						entity          TEXT NOT NULL,
+						entity_id       TEXT NOT NULL,
+						state           TEXT NOT NULL,
+						event           JSONB NOT NULL DEFAULT '{}',
+						status          TEXT NOT NULL,
+						attempts        INTEGER NOT NULL DEFAULT 0,
+						last_error      TEXT NOT NULL DEFAULT '',
+						ts_next_attempt TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+						ts_created      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+						ts_updated      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+						PRIMARY KEY (id)
+					)`,
+					`CREATE INDEX ON notification_deliveries (mf_owner_id, ts_created DESC)`,
+					`CREATE INDEX ON notification_deliveries (mf_owner_id, channel_id, dedup_key, ts_created DESC)`,
+					`CREATE INDEX ON notification_deliveries (ts_next_attempt) WHERE status = 'pending'`,
+				},
+				Down: []string{
+					"DROP TABLE notification_deliveries",
+					"DROP TABLE notification_rules",
+					"DROP TABLE notification_channels",
+				},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+
+	return err
+}
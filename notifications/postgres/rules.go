@@ -0,0 +1,137 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/orb-community/orb/notifications"
+	"github.com/orb-community/orb/pkg/db"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"go.uber.org/zap"
+)
+
+var _ notifications.RuleRepository = (*ruleRepository)(nil)
+
+type ruleRepository struct {
+	db     Database
+	logger *zap.Logger
+}
+
+// NewRuleRepository returns the repository of notification rules
+func NewRuleRepository(db Database, logger *zap.Logger) notifications.RuleRepository {
+	return &ruleRepository{db: db, logger: logger}
+}
+
+func (r ruleRepository) SaveRule(ctx context.Context, rule notifications.Rule) (string, error) {
+	q := `INSERT INTO notification_rules (name, mf_owner_id, entity, entity_id, states, channel_ids)
+			VALUES (:name, :mf_owner_id, :entity, :entity_id, :states, :channel_ids) RETURNING id`
+
+	if rule.MFOwnerID == "" || rule.Entity == "" {
+		return "", errors.ErrMalformedEntity
+	}
+
+	rows, err := r.db.NamedQueryContext(ctx, q, toDBRule(rule))
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case db.ErrInvalid, db.ErrTruncation:
+				return "", errors.Wrap(errors.ErrMalformedEntity, err)
+			}
+		}
+		return "", errors.Wrap(db.ErrSaveDB, err)
+	}
+	defer rows.Close()
+
+	var id string
+	if rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return "", errors.Wrap(db.ErrSaveDB, err)
+		}
+	}
+
+	return id, nil
+}
+
+func (r ruleRepository) RetrieveRules(ctx context.Context, ownerID string) ([]notifications.Rule, error) {
+	q := `SELECT id, name, mf_owner_id, entity, entity_id, states, channel_ids, ts_created
+			FROM notification_rules WHERE mf_owner_id = :mf_owner_id ORDER BY ts_created`
+
+	rows, err := r.db.NamedQueryContext(ctx, q, map[string]interface{}{"mf_owner_id": ownerID})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+	defer rows.Close()
+
+	var items []notifications.Rule
+	for rows.Next() {
+		var dbr dbRule
+		if err := rows.StructScan(&dbr); err != nil {
+			return nil, errors.Wrap(errors.ErrSelectEntity, err)
+		}
+		items = append(items, toRule(dbr))
+	}
+
+	return items, nil
+}
+
+func (r ruleRepository) DeleteRule(ctx context.Context, ownerID string, id string) error {
+	q := `DELETE FROM notification_rules WHERE mf_owner_id = :mf_owner_id AND id = :id`
+
+	params := map[string]interface{}{
+		"mf_owner_id": ownerID,
+		"id":          id,
+	}
+	if _, err := r.db.NamedExecContext(ctx, q, params); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == db.ErrInvalid {
+			return errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		return errors.Wrap(notifications.ErrRemoveEntity, err)
+	}
+
+	return nil
+}
+
+type dbRule struct {
+	ID         string           `db:"id"`
+	Name       types.Identifier `db:"name"`
+	MFOwnerID  string           `db:"mf_owner_id"`
+	Entity     string           `db:"entity"`
+	EntityID   string           `db:"entity_id"`
+	States     pq.StringArray   `db:"states"`
+	ChannelIDs pq.StringArray   `db:"channel_ids"`
+	Created    time.Time        `db:"ts_created"`
+}
+
+func toDBRule(r notifications.Rule) dbRule {
+	return dbRule{
+		ID:         r.ID,
+		Name:       r.Name,
+		MFOwnerID:  r.MFOwnerID,
+		Entity:     r.Entity,
+		EntityID:   r.EntityID,
+		States:     r.States,
+		ChannelIDs: r.ChannelIDs,
+		Created:    r.Created,
+	}
+}
+
+func toRule(dbr dbRule) notifications.Rule {
+	return notifications.Rule{
+		ID:         dbr.ID,
+		Name:       dbr.Name,
+		MFOwnerID:  dbr.MFOwnerID,
+		Entity:     dbr.Entity,
+		EntityID:   dbr.EntityID,
+		States:     dbr.States,
+		ChannelIDs: dbr.ChannelIDs,
+		Created:    dbr.Created,
+	}
+}
@@ -0,0 +1,53 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+const streamLen = 10000
+
+// Publisher publishes the state changes of a service owners can be notified about
+type Publisher interface {
+	// Publish sends the state change to the notifications stream. The change already happened, so failures are
+	// logged rather than returned
+	Publish(ctx context.Context, e StateEvent)
+}
+
+var _ Publisher = (*streamPublisher)(nil)
+
+type streamPublisher struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+// NewPublisher returns a publisher sending state changes to the notifications stream
+func NewPublisher(client *redis.Client, logger *zap.Logger) Publisher {
+	return streamPublisher{
+		client: client,
+		logger: logger.Named("notifications_publisher"),
+	}
+}
+
+func (p streamPublisher) Publish(ctx context.Context, e StateEvent) {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	record := &redis.XAddArgs{
+		Stream: StreamID,
+		MaxLen: streamLen,
+		Approx: true,
+		Values: e.Encode(),
+	}
+	if err := p.client.XAdd(ctx, record).Err(); err != nil {
+		p.logger.Error("error sending event to notifications event store", zap.String("entity", e.Entity),
+			zap.String("entity_id", e.EntityID), zap.String("state", e.State), zap.Error(err))
+	}
+}
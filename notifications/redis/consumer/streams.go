@@ -0,0 +1,55 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package consumer
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/orb-community/orb/notifications"
+	"github.com/orb-community/orb/pkg/streams"
+	"go.uber.org/zap"
+)
+
+const group = "orb.notifications"
+
+type Subscriber interface {
+	Subscribe(context context.Context) error
+}
+
+type eventStore struct {
+	notificationsService notifications.Service
+	client               *redis.Client
+	esconsumer           string
+	logger               *zap.Logger
+}
+
+func NewEventStore(notificationsService notifications.Service, client *redis.Client, esconsumer string, logger *zap.Logger) Subscriber {
+	return eventStore{
+		notificationsService: notificationsService,
+		client:               client,
+		esconsumer:           esconsumer,
+		logger:               logger,
+	}
+}
+
+func (es eventStore) Subscribe(context context.Context) error {
+	return streams.Consume(context, es.client, notifications.StreamID, group, es.esconsumer, es.logger, es.handle)
+}
+
+func (es eventStore) handle(ctx context.Context, msg redis.XMessage) error {
+	event, err := notifications.Decode(msg.Values)
+	if err != nil {
+		// retrying can't fix a state event with missing fields, so it is acknowledged and nobody is notified
+		es.logger.Error("failed to decode state event", zap.String("message_id", msg.ID), zap.Error(err))
+		return nil
+	}
+	if err := es.notificationsService.HandleStateEvent(ctx, event); err != nil {
+		es.logger.Error("failed to queue notifications of state event", zap.String("entity", event.Entity),
+			zap.String("entity_id", event.EntityID), zap.String("state", event.State), zap.Error(err))
+		return err
+	}
+	return nil
+}
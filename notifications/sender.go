@@ -0,0 +1,173 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+const (
+	// SignatureHeader carries the hex HMAC-SHA256 of the body of webhook notifications, keyed with the channel secret
+	SignatureHeader = "X-Orb-Signature"
+	// DeliveryHeader carries the id of the delivery, the same on every attempt so receivers can ignore retries
+	DeliveryHeader = "X-Orb-Delivery"
+	// EventHeader carries the entity and state of webhook notifications, such as "agent.stale"
+	EventHeader = "X-Orb-Event"
+)
+
+// Sender sends a delivery to a channel
+type Sender interface {
+	// Send posts the event of the delivery to the channel, any response other than a 2xx one is an error
+	Send(ctx context.Context, c Channel, d Delivery) error
+}
+
+var _ Sender = (*httpSender)(nil)
+
+type httpSender struct {
+	client *http.Client
+}
+
+// NewSender returns a sender posting to the channels over HTTP, giving up on each attempt after the timeout. It only
+// connects to public addresses, and doesn't follow redirects which could lead elsewhere
+func NewSender(timeout time.Duration) Sender {
+	return newSender(timeout, publicIP)
+}
+
+func newSender(timeout time.Duration, allowed func(net.IP) bool) Sender {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		// the address is checked once resolved, so a hostname can't point to the services of the deployment
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !allowed(ip) {
+				return fmt.Errorf("channel address %s is not a public one", host)
+			}
+			return nil
+		},
+	}
+	transport := &http.Transport{
+		// a proxy would be dialed instead of the channel
+		Proxy:               nil,
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: timeout,
+		MaxIdleConns:        100,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	return httpSender{client: &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// WebhookPayload is the body of webhook notifications
+type WebhookPayload struct {
+	DeliveryID string    `json:"delivery_id"`
+	Entity     string    `json:"entity"`
+	EntityID   string    `json:"entity_id"`
+	EntityName string    `json:"entity_name,omitempty"`
+	State      string    `json:"state"`
+	Message    string    `json:"message,omitempty"`
+	AgentID    string    `json:"agent_id,omitempty"`
+	AgentName  string    `json:"agent_name,omitempty"`
+	Timestamp  time.Time `json:"ts"`
+}
+
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// Sign returns the signature of a webhook body, as sent in SignatureHeader
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Summary describes the event in a sentence, as sent to Slack
+func Summary(e StateEvent) string {
+	name := e.EntityName
+	if name == "" {
+		name = e.EntityID
+	}
+	text := fmt.Sprintf("Orb: %s %s is %s", e.Entity, name, e.State)
+	if e.AgentName != "" || e.AgentID != "" {
+		agent := e.AgentName
+		if agent == "" {
+			agent = e.AgentID
+		}
+		text = fmt.Sprintf("%s on agent %s", text, agent)
+	}
+	if e.Message != "" {
+		text = fmt.Sprintf("%s: %s", text, e.Message)
+	}
+	return text
+}
+
+func (s httpSender) Send(ctx context.Context, c Channel, d Delivery) error {
+	var payload interface{}
+	switch c.Type {
+	case ChannelSlack:
+		payload = slackPayload{Text: Summary(d.Event)}
+	default:
+		payload = WebhookPayload{
+			DeliveryID: d.ID,
+			Entity:     d.Event.Entity,
+			EntityID:   d.Event.EntityID,
+			EntityName: d.Event.EntityName,
+			State:      d.Event.State,
+			Message:    d.Event.Message,
+			AgentID:    d.Event.AgentID,
+			AgentName:  d.Event.AgentName,
+			Timestamp:  d.Event.Timestamp,
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.Type == ChannelWebhook {
+		req.Header.Set(DeliveryHeader, d.ID)
+		req.Header.Set(EventHeader, fmt.Sprintf("%s.%s", d.Event.Entity, d.Event.State))
+		if c.Secret != "" {
+			req.Header.Set(SignatureHeader, Sign(c.Secret, body))
+		}
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 4096))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("channel responded with status %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/orb-community/orb/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// DedupWindow is how long the same notification is not sent again to a channel
+const DedupWindow = 15 * time.Minute
+
+var _ Service = (*notificationsService)(nil)
+
+type notificationsService struct {
+	logger *zap.Logger
+	// for AuthN/AuthZ
+	auth         mainflux.AuthServiceClient
+	channelRepo  ChannelRepository
+	ruleRepo     RuleRepository
+	deliveryRepo DeliveryRepository
+}
+
+// NewService returns the notifications service
+func NewService(logger *zap.Logger, auth mainflux.AuthServiceClient, channelRepo ChannelRepository, ruleRepo RuleRepository, deliveryRepo DeliveryRepository) Service {
+	return &notificationsService{
+		logger:       logger,
+		auth:         auth,
+		channelRepo:  channelRepo,
+		ruleRepo:     ruleRepo,
+		deliveryRepo: deliveryRepo,
+	}
+}
+
+func (svc notificationsService) identify(token string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	res, err := svc.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", errors.Wrap(errors.ErrUnauthorizedAccess, err)
+	}
+
+	return res.GetId(), nil
+}
+
+func (svc notificationsService) CreateChannel(ctx context.Context, token string, c Channel) (Channel, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	if err := c.Validate(); err != nil {
+		return Channel{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	c.MFOwnerID = ownerID
+	c.Created = time.Now()
+	id, err := svc.channelRepo.SaveChannel(ctx, c)
+	if err != nil {
+		return Channel{}, err
+	}
+	c.ID = id
+
+	return c, nil
+}
+
+func (svc notificationsService) ListChannels(ctx context.Context, token string) ([]Channel, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.channelRepo.RetrieveChannels(ctx, ownerID)
+}
+
+func (svc notificationsService) ViewChannel(ctx context.Context, token string, id string) (Channel, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	return svc.channelRepo.RetrieveChannelByID(ctx, ownerID, id)
+}
+
+func (svc notificationsService) RemoveChannel(ctx context.Context, token string, id string) error {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return err
+	}
+
+	return svc.channelRepo.DeleteChannel(ctx, ownerID, id)
+}
+
+func (svc notificationsService) CreateRule(ctx context.Context, token string, r Rule) (Rule, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	if err := r.Validate(); err != nil {
+		return Rule{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	for _, channelID := range r.ChannelIDs {
+		if _, err := svc.channelRepo.RetrieveChannelByID(ctx, ownerID, channelID); err != nil {
+			return Rule{}, err
+		}
+	}
+
+	r.MFOwnerID = ownerID
+	r.Created = time.Now()
+	id, err := svc.ruleRepo.SaveRule(ctx, r)
+	if err != nil {
+		return Rule{}, err
+	}
+	r.ID = id
+
+	return r, nil
+}
+
+func (svc notificationsService) ListRules(ctx context.Context, token string) ([]Rule, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.ruleRepo.RetrieveRules(ctx, ownerID)
+}
+
+func (svc notificationsService) RemoveRule(ctx context.Context, token string, id string) error {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return err
+	}
+
+	return svc.ruleRepo.DeleteRule(ctx, ownerID, id)
+}
+
+func (svc notificationsService) ListDeliveries(ctx context.Context, token string, pm DeliveryPageMetadata) (DeliveryPage, error) {
+	ownerID, err := svc.identify(token)
+	if err != nil {
+		return DeliveryPage{}, err
+	}
+
+	return svc.deliveryRepo.RetrieveDeliveries(ctx, ownerID, pm)
+}
+
+func (svc notificationsService) HandleStateEvent(ctx context.Context, e StateEvent) error {
+	rules, err := svc.ruleRepo.RetrieveRules(ctx, e.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	key := e.DedupKey()
+	queued := map[string]bool{}
+	for _, r := range rules {
+		if !r.Matches(e) {
+			continue
+		}
+		for _, channelID := range r.ChannelIDs {
+			// a channel of several matching rules is sent the notification once
+			if queued[channelID] {
+				continue
+			}
+			queued[channelID] = true
+
+			last, err := svc.deliveryRepo.RetrieveLastDelivery(ctx, e.OwnerID, channelID, key)
+			if err != nil && !errors.Contains(err, errors.ErrNotFound) {
+				return err
+			}
+			if err == nil && now.Sub(last.Created) < DedupWindow {
+				svc.logger.Debug("skipping duplicate notification", zap.String("channel_id", channelID), zap.String("dedup_key", key))
+				continue
+			}
+
+			d := Delivery{
+				MFOwnerID:   e.OwnerID,
+				RuleID:      r.ID,
+				ChannelID:   channelID,
+				DedupKey:    key,
+				Event:       e,
+				Status:      DeliveryPending,
+				NextAttempt: now,
+				Created:     now,
+				Updated:     now,
+			}
+			if _, err := svc.deliveryRepo.SaveDelivery(ctx, d); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
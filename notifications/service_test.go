@@ -0,0 +1,162 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package notifications_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/orb-community/orb/notifications"
+	"github.com/orb-community/orb/notifications/mocks"
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	token        = "token"
+	invalidToken = "invalid"
+	email        = "user@example.com"
+)
+
+func newService() notifications.Service {
+	auth := mocks.NewAuthService(map[string]string{token: email})
+	return notifications.NewService(zap.NewNop(), auth, mocks.NewChannelRepository(), mocks.NewRuleRepository(), mocks.NewDeliveryRepository())
+}
+
+func createChannel(t *testing.T, svc notifications.Service, name string) notifications.Channel {
+	n, err := types.NewIdentifier(name)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	c, err := svc.CreateChannel(context.Background(), token, notifications.Channel{Name: n, Type: notifications.ChannelWebhook, URL: "https://example.com/" + name})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	return c
+}
+
+func TestCreateRule(t *testing.T) {
+	svc := newService()
+	channel := createChannel(t, svc, "ops")
+	name, err := types.NewIdentifier("sinks")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		token string
+		rule  notifications.Rule
+		err   error
+	}{
+		"create a rule": {
+			token: token,
+			rule:  notifications.Rule{Name: name, Entity: notifications.EntitySink, States: []string{notifications.StateError}, ChannelIDs: []string{channel.ID}},
+			err:   nil,
+		},
+		"create an invalid rule": {
+			token: token,
+			rule:  notifications.Rule{Name: name, Entity: notifications.EntitySink, States: []string{notifications.StateStale}, ChannelIDs: []string{channel.ID}},
+			err:   errors.ErrMalformedEntity,
+		},
+		"create a rule without a name": {
+			token: token,
+			rule:  notifications.Rule{Entity: notifications.EntitySink, States: []string{notifications.StateError}, ChannelIDs: []string{channel.ID}},
+			err:   errors.ErrMalformedEntity,
+		},
+		"create a rule sending to an unknown channel": {
+			token: token,
+			rule:  notifications.Rule{Name: name, Entity: notifications.EntitySink, States: []string{notifications.StateError}, ChannelIDs: []string{"unknown"}},
+			err:   errors.ErrNotFound,
+		},
+		"create a rule with wrong credentials": {
+			token: invalidToken,
+			rule:  notifications.Rule{Name: name, Entity: notifications.EntitySink, States: []string{notifications.StateError}, ChannelIDs: []string{channel.ID}},
+			err:   errors.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			_, err := svc.CreateRule(context.Background(), tc.token, tc.rule)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+		})
+	}
+}
+
+func TestHandleStateEvent(t *testing.T) {
+	svc := newService()
+	ops := createChannel(t, svc, "ops")
+	oncall := createChannel(t, svc, "oncall")
+	name, err := types.NewIdentifier("policies")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	rules := []notifications.Rule{
+		{Name: name, Entity: notifications.EntityPolicy, States: []string{notifications.StateFailedToApply}, ChannelIDs: []string{ops.ID}},
+		{Name: name, Entity: notifications.EntityPolicy, EntityID: "policy-1", States: []string{notifications.StateFailedToApply}, ChannelIDs: []string{ops.ID, oncall.ID}},
+	}
+	for _, r := range rules {
+		_, err := svc.CreateRule(context.Background(), token, r)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	failing := notifications.StateEvent{OwnerID: email, Entity: notifications.EntityPolicy, EntityID: "policy-1", State: notifications.StateFailedToApply, AgentID: "agent-1"}
+	otherAgent := failing
+	otherAgent.AgentID = "agent-2"
+	otherPolicy := failing
+	otherPolicy.EntityID = "policy-2"
+	noTap := failing
+	noTap.State = notifications.StateNoTapMatch
+
+	// the cases build on each other, so they run in order
+	tests := []struct {
+		name       string
+		event      notifications.StateEvent
+		deliveries uint64
+	}{
+		{
+			name:       "state change sent once to each channel of the matching rules",
+			event:      failing,
+			deliveries: 2,
+		},
+		{
+			name:       "same state change within the dedup window",
+			event:      failing,
+			deliveries: 2,
+		},
+		{
+			name:       "same state change on another agent",
+			event:      otherAgent,
+			deliveries: 4,
+		},
+		{
+			name:       "state change matching a single rule",
+			event:      otherPolicy,
+			deliveries: 5,
+		},
+		{
+			name:       "state change matching no rule",
+			event:      noTap,
+			deliveries: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.HandleStateEvent(context.Background(), tt.event)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tt.name, err))
+
+			page, err := svc.ListDeliveries(context.Background(), token, notifications.DeliveryPageMetadata{Limit: 10})
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tt.name, err))
+			assert.Equal(t, tt.deliveries, page.Total, fmt.Sprintf("%s: expected %d deliveries got %d", tt.name, tt.deliveries, page.Total))
+		})
+	}
+
+	page, err := svc.ListDeliveries(context.Background(), token, notifications.DeliveryPageMetadata{Limit: 10, ChannelID: oncall.ID})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(2), page.Total, "expected the deliveries of the channel only")
+	for _, d := range page.Deliveries {
+		assert.Equal(t, notifications.DeliveryPending, d.Status, "expected the deliveries to be pending")
+		assert.False(t, d.NextAttempt.After(time.Now()), "expected the deliveries to be due")
+	}
+}
@@ -35,6 +35,26 @@ type OtelConfig struct {
 	KafkaUrl string `mapstructure:"kafka_url"`
 }
 
+type CollectorRuntimeConfig struct {
+	Runtime   string `mapstructure:"runtime"`
+	Namespace string `mapstructure:"namespace"`
+	Image     string `mapstructure:"image"`
+	Binary    string `mapstructure:"binary"`
+	WorkDir   string `mapstructure:"work_dir"`
+	Mode      string `mapstructure:"mode"`
+	PoolSize  int    `mapstructure:"pool_size"`
+
+	// StorageClass and StorageSize are those of the volumes of the persistent sending queues, the default class when empty
+	StorageClass string `mapstructure:"storage_class"`
+	StorageSize  string `mapstructure:"storage_size"`
+}
+
+type MonitorConfig struct {
+	WarningFailureRatio float64 `mapstructure:"warning_failure_ratio"`
+	ErrorFailureRatio   float64 `mapstructure:"error_failure_ratio"`
+	QueueWarningRatio   float64 `mapstructure:"queue_warning_ratio"`
+}
+
 type CacheConfig struct {
 	URL  string `mapstructure:"url"`
 	Pass string `mapstructure:"pass"`
@@ -125,6 +145,42 @@ func LoadOtelConfig(prefix string) OtelConfig {
 	return nC
 }
 
+func LoadCollectorRuntimeConfig(prefix string) CollectorRuntimeConfig {
+	cfg := viper.New()
+	cfg.SetEnvPrefix(fmt.Sprintf("%s_collector", prefix))
+
+	cfg.SetDefault("runtime", "kubernetes")
+	cfg.SetDefault("namespace", "otelcollectors")
+	cfg.SetDefault("image", "otel/opentelemetry-collector-contrib:0.91.0")
+	cfg.SetDefault("binary", "otelcol-contrib")
+	cfg.SetDefault("work_dir", "/tmp/orb-collectors")
+	cfg.SetDefault("mode", "dedicated")
+	cfg.SetDefault("pool_size", 4)
+	cfg.SetDefault("storage_class", "")
+	cfg.SetDefault("storage_size", "1Gi")
+	cfg.AllowEmptyEnv(true)
+	cfg.AutomaticEnv()
+	var rC CollectorRuntimeConfig
+	cfg.Unmarshal(&rC)
+
+	return rC
+}
+
+func LoadMonitorConfig(prefix string) MonitorConfig {
+	cfg := viper.New()
+	cfg.SetEnvPrefix(fmt.Sprintf("%s_monitor", prefix))
+
+	cfg.SetDefault("warning_failure_ratio", 0.05)
+	cfg.SetDefault("error_failure_ratio", 0.5)
+	cfg.SetDefault("queue_warning_ratio", 0.8)
+	cfg.AllowEmptyEnv(true)
+	cfg.AutomaticEnv()
+	var mC MonitorConfig
+	cfg.Unmarshal(&mC)
+
+	return mC
+}
+
 func LoadPostgresConfig(prefix string, db string) PostgresConfig {
 
 	cfg := viper.New()
@@ -81,6 +81,9 @@ var (
 	// ErrInvalidRemoteHost indicates that remote host field is invalid
 	ErrInvalidRemoteHost = New("malformed entity specification. remote host type is invalid")
 
+	// ErrInvalidDelivery indicates that delivery field is invalid
+	ErrInvalidDelivery = New("malformed entity specification. delivery field is invalid")
+
 	// ErrNotFound indicates a non-existent entity request.
 	ErrNotFound = New("non-existent entity")
 
@@ -0,0 +1,102 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+// Package streams reads redis streams through a consumer group without ever skipping an unacknowledged entry
+package streams
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+const (
+	exists = "BUSYGROUP Consumer Group name already exists"
+
+	batchSize  = 100
+	minBackoff = 100 * time.Millisecond
+	maxBackoff = 30 * time.Second
+)
+
+// Handler processes one stream entry. An entry is acknowledged only when its handler returns nil, a handler that
+// wants an entry dropped (for example one that never decodes) must log it and return nil itself.
+type Handler func(ctx context.Context, msg redis.XMessage) error
+
+// Consume creates the consumer group if needed and hands every entry of the stream to handle, in order. Entries
+// this consumer read but did not acknowledge are re-read before any new entry, so a failed entry is retried with
+// an exponential backoff until its handler succeeds. It only returns when ctx is done or the group can't be created.
+func Consume(ctx context.Context, client *redis.Client, stream, group, consumer string, logger *zap.Logger, handle Handler) error {
+	err := client.XGroupCreateMkStream(ctx, stream, group, "$").Err()
+	if err != nil && err.Error() != exists {
+		return err
+	}
+
+	backoff := minBackoff
+	wait := func() error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+		return nil
+	}
+
+	// "0" reads this consumer's pending entries, ">" only entries never delivered to the group
+	pending := true
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		id := ">"
+		if pending {
+			id = "0"
+		}
+		streams, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: consumer,
+			Streams:  []string{stream, id},
+			Count:    batchSize,
+		}).Result()
+		if err != nil && err != redis.Nil {
+			logger.Error("failed to read stream", zap.String("stream", stream), zap.Error(err))
+			if err := wait(); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(streams) == 0 || len(streams[0].Messages) == 0 {
+			pending = false
+			continue
+		}
+
+		failed := false
+		for _, msg := range streams[0].Messages {
+			if err := handle(ctx, msg); err != nil {
+				logger.Warn("retrying stream entry", zap.String("stream", stream),
+					zap.String("message_id", msg.ID), zap.Duration("backoff", backoff))
+				failed = true
+				break
+			}
+			if err := client.XAck(ctx, stream, group, msg.ID).Err(); err != nil {
+				logger.Error("failed to acknowledge stream entry", zap.String("stream", stream),
+					zap.String("message_id", msg.ID), zap.Error(err))
+			}
+		}
+		if failed {
+			if err := wait(); err != nil {
+				return err
+			}
+		} else {
+			backoff = minBackoff
+		}
+		// a failed or unacknowledged entry stays pending, so it is picked up again before anything new
+		pending = true
+	}
+}
@@ -25,6 +25,7 @@ var _ pb.PolicyServiceClient = (*grpcClient)(nil)
 type grpcClient struct {
 	timeout                  time.Duration
 	retrievePolicy           endpoint.Endpoint
+	retrievePolicyVersion    endpoint.Endpoint
 	retrievePoliciesByGroups endpoint.Endpoint
 	retrieveDataset          endpoint.Endpoint
 	retrieveDatasetsByGroups endpoint.Endpoint
@@ -46,7 +47,7 @@ func (client grpcClient) RetrieveDatasetsByGroups(ctx context.Context, in *pb.Da
 	ir := res.(datasetListRes)
 	dsList := make([]*pb.DatasetRes, len(ir.datasets))
 	for i, ds := range ir.datasets {
-		dsList[i] = &pb.DatasetRes{Id: ds.id, SinkIds: ds.sinkIDs, PolicyId: ds.policyID, AgentGroupId: ds.agentGroupID}
+		dsList[i] = &pb.DatasetRes{Id: ds.id, SinkIds: ds.sinkIDs, SinkFilters: ds.sinkFilters, PolicyId: ds.policyID, AgentGroupId: ds.agentGroupID}
 	}
 	return &pb.DatasetsRes{DatasetList: dsList}, nil
 
@@ -69,6 +70,24 @@ func (client grpcClient) RetrievePolicy(ctx context.Context, in *pb.PolicyByIDRe
 	return &pb.PolicyRes{Id: ir.id, Name: ir.name, Data: ir.data, Backend: ir.backend, Format: ir.format, Version: ir.version}, nil
 }
 
+func (client grpcClient) RetrievePolicyVersion(ctx context.Context, in *pb.PolicyVersionReq, _ ...grpc.CallOption) (*pb.PolicyRes, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	ar := accessPolicyVersionReq{
+		PolicyID: in.PolicyID,
+		OwnerID:  in.OwnerID,
+		Version:  in.Version,
+	}
+	res, err := client.retrievePolicyVersion(ctx, ar)
+	if err != nil {
+		return nil, err
+	}
+
+	ir := res.(policyRes)
+	return &pb.PolicyRes{Id: ir.id, Name: ir.name, Data: ir.data, Backend: ir.backend, Format: ir.format, Version: ir.version}, nil
+}
+
 func (client grpcClient) RetrievePoliciesByGroups(ctx context.Context, in *pb.PoliciesByGroupsReq, _ ...grpc.CallOption) (*pb.PolicyInDSListRes, error) {
 	ctx, cancel := context.WithTimeout(ctx, client.timeout)
 	defer cancel()
@@ -118,6 +137,7 @@ func (client grpcClient) RetrieveDataset(ctx context.Context, in *pb.DatasetByID
 		AgentGroupId: ir.agentGroupID,
 		PolicyId:     ir.policyID,
 		SinkIds:      ir.sinkIDs,
+		SinkFilters:  ir.sinkFilters,
 	}, nil
 }
 
@@ -135,6 +155,14 @@ func NewClient(tracer opentracing.Tracer, conn *grpc.ClientConn, timeout time.Du
 			decodePolicyResponse,
 			pb.PolicyRes{},
 		).Endpoint()),
+		retrievePolicyVersion: kitot.TraceClient(tracer, "retrieve_policy_version")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"RetrievePolicyVersion",
+			encodeRetrievePolicyVersionRequest,
+			decodePolicyResponse,
+			pb.PolicyRes{},
+		).Endpoint()),
 		retrievePoliciesByGroups: kitot.TraceClient(tracer, "retrieve_policies_by_groups")(kitgrpc.NewClient(
 			conn,
 			svcName,
@@ -159,6 +187,11 @@ func encodeRetrievePolicyRequest(_ context.Context, grpcReq interface{}) (interf
 	return &pb.PolicyByIDReq{PolicyID: req.PolicyID, OwnerID: req.OwnerID}, nil
 }
 
+func encodeRetrievePolicyVersionRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(accessPolicyVersionReq)
+	return &pb.PolicyVersionReq{PolicyID: req.PolicyID, OwnerID: req.OwnerID, Version: req.Version}, nil
+}
+
 func encodeRetrievePoliciesByGroupsRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(accessByGroupIDReq)
 	return &pb.PoliciesByGroupsReq{GroupIDs: req.GroupIDs, OwnerID: req.OwnerID}, nil
@@ -183,6 +216,7 @@ func decodeDatasetResponse(_ context.Context, grpcRes interface{}) (interface{},
 		agentGroupID: res.GetAgentGroupId(),
 		policyID:     res.GetPolicyId(),
 		sinkIDs:      res.GetSinkIds(),
+		sinkFilters:  res.GetSinkFilters(),
 	}, nil
 }
 
@@ -13,6 +13,7 @@ import (
 	"encoding/json"
 	"github.com/go-kit/kit/endpoint"
 	"github.com/orb-community/orb/policies"
+	"github.com/orb-community/orb/policies/pb"
 	"gopkg.in/yaml.v3"
 )
 
@@ -43,6 +44,38 @@ func retrievePolicyEndpoint(svc policies.Service) endpoint.Endpoint {
 	}
 }
 
+func retrievePolicyVersionEndpoint(svc policies.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(accessPolicyVersionReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		policy, err := svc.ViewPolicyByIDInternal(ctx, req.PolicyID, req.OwnerID)
+		if err != nil {
+			return policyRes{}, err
+		}
+		version, err := svc.ViewPolicyVersionInternal(ctx, req.PolicyID, req.OwnerID, req.Version)
+		if err != nil {
+			return policyRes{}, err
+		}
+		// the backend can not change between versions, the name is kept as agents track the policy by it
+		data, format, err := extractData(policies.Policy{Policy: version.Policy, Format: version.Format})
+		if err != nil {
+			return policyRes{}, err
+		}
+
+		return policyRes{
+			id:      policy.ID,
+			name:    policy.Name.String(),
+			format:  format,
+			backend: policy.Backend,
+			version: version.Version,
+			data:    data,
+		}, nil
+	}
+}
+
 func extractData(policy policies.Policy) (data []byte, format string, err error) {
 	if policy.Format == "yaml" {
 		data, err = yaml.Marshal(policy.Policy)
@@ -105,6 +138,7 @@ func retrieveDatasetEnpoint(svc policies.Service) endpoint.Endpoint {
 			agentGroupID: dataset.AgentGroupID,
 			policyID:     dataset.PolicyID,
 			sinkIDs:      *dataset.SinkIDs,
+			sinkFilters:  toPBSinkFilters(dataset.SinkFilters),
 		}, nil
 	}
 }
@@ -126,6 +160,7 @@ func retrieveDatasetsByGroupsEndpoint(svc policies.Service) endpoint.Endpoint {
 				id:           ds.ID,
 				agentGroupID: ds.AgentGroupID,
 				sinkIDs:      *ds.SinkIDs,
+				sinkFilters:  toPBSinkFilters(ds.SinkFilters),
 				policyID:     ds.PolicyID,
 			}
 		}
@@ -133,3 +168,21 @@ func retrieveDatasetsByGroupsEndpoint(svc policies.Service) endpoint.Endpoint {
 		return datasetListRes{datasets: datasets}, nil
 	}
 }
+
+// toPBSinkFilters converts the dataset sink filters for the sinker
+func toPBSinkFilters(filters map[string]policies.SinkFilter) map[string]*pb.SinkFilter {
+	if len(filters) == 0 {
+		return nil
+	}
+	res := make(map[string]*pb.SinkFilter, len(filters))
+	for sinkID, f := range filters {
+		res[sinkID] = &pb.SinkFilter{
+			IncludeMetrics:   f.IncludeMetrics,
+			ExcludeMetrics:   f.ExcludeMetrics,
+			DropAttributes:   f.DropAttributes,
+			RenameAttributes: f.RenameAttributes,
+			AddLabels:        f.AddLabels,
+		}
+	}
+	return res
+}
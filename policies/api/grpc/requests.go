@@ -23,6 +23,20 @@ func (req accessByIDReq) validate() error {
 	return nil
 }
 
+type accessPolicyVersionReq struct {
+	PolicyID string
+	OwnerID  string
+	Version  int32
+}
+
+func (req accessPolicyVersionReq) validate() error {
+	if req.PolicyID == "" || req.OwnerID == "" || req.Version < 0 {
+		return policies.ErrMalformedEntity
+	}
+
+	return nil
+}
+
 type accessByGroupIDReq struct {
 	GroupIDs []string
 	OwnerID  string
@@ -8,6 +8,8 @@
 
 package grpc
 
+import "github.com/orb-community/orb/policies/pb"
+
 type policyRes struct {
 	id      string
 	name    string
@@ -37,6 +39,7 @@ type datasetRes struct {
 	agentGroupID string
 	policyID     string
 	sinkIDs      []string
+	sinkFilters  map[string]*pb.SinkFilter
 }
 
 type datasetListRes struct {
@@ -26,6 +26,7 @@ var _ pb.PolicyServiceServer = (*grpcServer)(nil)
 type grpcServer struct {
 	pb.UnimplementedPolicyServiceServer
 	retrievePolicy           kitgrpc.Handler
+	retrievePolicyVersion    kitgrpc.Handler
 	retrievePoliciesByGroups kitgrpc.Handler
 	retrieveDataset          kitgrpc.Handler
 	retrieveDatasetsByGroups kitgrpc.Handler
@@ -39,6 +40,11 @@ func NewServer(tracer opentracing.Tracer, svc policies.Service) pb.PolicyService
 			decodeRetrievePolicyRequest,
 			encodePolicyResponse,
 		),
+		retrievePolicyVersion: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "retrieve_policy_version")(retrievePolicyVersionEndpoint(svc)),
+			decodeRetrievePolicyVersionRequest,
+			encodePolicyResponse,
+		),
 		retrievePoliciesByGroups: kitgrpc.NewServer(
 			kitot.TraceServer(tracer, "retrieve_policies_by_groups")(retrievePoliciesByGroupsEndpoint(svc)),
 			decodeRetrievePoliciesByGroupRequest,
@@ -75,6 +81,15 @@ func (gs *grpcServer) RetrievePolicy(ctx context.Context, req *pb.PolicyByIDReq)
 	return res.(*pb.PolicyRes), nil
 }
 
+func (gs *grpcServer) RetrievePolicyVersion(ctx context.Context, req *pb.PolicyVersionReq) (*pb.PolicyRes, error) {
+	_, res, err := gs.retrievePolicyVersion.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*pb.PolicyRes), nil
+}
+
 func (gs *grpcServer) RetrieveDataset(ctx context.Context, req *pb.DatasetByIDReq) (*pb.DatasetRes, error) {
 	_, res, err := gs.retrieveDataset.ServeGRPC(ctx, req)
 	if err != nil {
@@ -98,6 +113,11 @@ func decodeRetrievePolicyRequest(_ context.Context, grpcReq interface{}) (interf
 	return accessByIDReq{PolicyID: req.PolicyID, OwnerID: req.OwnerID}, nil
 }
 
+func decodeRetrievePolicyVersionRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.PolicyVersionReq)
+	return accessPolicyVersionReq{PolicyID: req.PolicyID, OwnerID: req.OwnerID, Version: req.Version}, nil
+}
+
 func decodeRetrievePoliciesByGroupRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*pb.PoliciesByGroupsReq)
 	return accessByGroupIDReq{GroupIDs: req.GroupIDs, OwnerID: req.OwnerID}, nil
@@ -155,6 +175,7 @@ func encodeDatasetResponse(_ context.Context, grpcRes interface{}) (interface{},
 		AgentGroupId: res.agentGroupID,
 		PolicyId:     res.policyID,
 		SinkIds:      res.sinkIDs,
+		SinkFilters:  res.sinkFilters,
 	}, nil
 }
 
@@ -163,7 +184,7 @@ func encodeDatasetListResponse(_ context.Context, grpcRes interface{}) (interfac
 
 	dsList := make([]*pb.DatasetRes, len(res.datasets))
 	for i, ds := range res.datasets {
-		dsList[i] = &pb.DatasetRes{Id: ds.id, PolicyId: ds.policyID, AgentGroupId: ds.agentGroupID, SinkIds: ds.sinkIDs}
+		dsList[i] = &pb.DatasetRes{Id: ds.id, PolicyId: ds.policyID, AgentGroupId: ds.agentGroupID, SinkIds: ds.sinkIDs, SinkFilters: ds.sinkFilters}
 	}
 	return &pb.DatasetsRes{DatasetList: dsList}, nil
 }
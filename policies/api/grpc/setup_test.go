@@ -87,5 +87,9 @@ func newService(tokens map[string]string) policies.Service {
 	SinkServiceClient := sinkmocks.NewClient()
 	logger := zap.NewNop()
 
-	return policies.New(logger, auth, repo, fleetGrpcClient, SinkServiceClient)
+	svc, err := policies.New(logger, auth, repo, fleetGrpcClient, SinkServiceClient)
+	if err != nil {
+		panic(err)
+	}
+	return svc
 }
@@ -10,7 +10,6 @@ package http
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/go-kit/kit/endpoint"
 	"github.com/orb-community/orb/pkg/errors"
@@ -43,9 +42,6 @@ func addPolicyEndpoint(svc policies.Service) endpoint.Endpoint {
 
 		saved, err := svc.AddPolicy(ctx, req.token, policy)
 		if err != nil {
-			if e, ok := err.(errors.Error); ok && errors.Contains(err, policies.ErrValidatePolicy) {
-				err = errors.New(fmt.Sprintf("%s : %s", e.Msg(), e.Err()))
-			}
 			return nil, err
 		}
 
@@ -160,6 +156,9 @@ func editPoliciyEndpoint(svc policies.Service) endpoint.Endpoint {
 			PolicyData:  req.PolicyData,
 			Format:      req.Format,
 		}
+		if req.Rollout != nil {
+			plcy.Rollout = req.Rollout.strategy()
+		}
 
 		res, err := svc.EditPolicy(ctx, req.token, plcy)
 		if err != nil {
@@ -176,6 +175,14 @@ func editPoliciyEndpoint(svc policies.Service) endpoint.Endpoint {
 			PolicyData:  res.PolicyData,
 			Version:     res.Version,
 		}
+		if res.Rollout != nil {
+			plcyRes.Rollout = &rolloutRes{
+				CanaryPercent: res.Rollout.CanaryPercent,
+				CanaryCount:   res.Rollout.CanaryCount,
+				OnFailure:     res.Rollout.OnFailure,
+				Timeout:       int(res.Rollout.Timeout.Seconds()),
+			}
+		}
 
 		return plcyRes, nil
 	}
@@ -212,6 +219,7 @@ func addDatasetEndpoint(svc policies.Service) endpoint.Endpoint {
 			AgentGroupID: req.AgentGroupID,
 			PolicyID:     req.PolicyID,
 			SinkIDs:      &req.SinkIDs,
+			SinkFilters:  req.SinkFilters,
 			Tags:         req.Tags,
 		}
 
@@ -227,6 +235,7 @@ func addDatasetEndpoint(svc policies.Service) endpoint.Endpoint {
 			AgentGroupID: saved.AgentGroupID,
 			PolicyID:     saved.PolicyID,
 			SinkIDs:      *saved.SinkIDs,
+			SinkFilters:  saved.SinkFilters,
 			Metadata:     saved.Metadata,
 			TsCreated:    saved.Created,
 			Tags:         saved.Tags,
@@ -253,10 +262,11 @@ func editDatasetEndpoint(svc policies.Service) endpoint.Endpoint {
 		}
 
 		dataset := policies.Dataset{
-			Name:    nameID,
-			ID:      req.id,
-			Tags:    req.Tags,
-			SinkIDs: req.SinkIDs,
+			Name:        nameID,
+			ID:          req.id,
+			Tags:        req.Tags,
+			SinkIDs:     req.SinkIDs,
+			SinkFilters: req.SinkFilters,
 		}
 
 		ds, err := svc.EditDataset(ctx, req.token, dataset)
@@ -271,6 +281,7 @@ func editDatasetEndpoint(svc policies.Service) endpoint.Endpoint {
 			AgentGroupID: ds.AgentGroupID,
 			PolicyID:     ds.PolicyID,
 			SinkIDs:      *ds.SinkIDs,
+			SinkFilters:  ds.SinkFilters,
 			Metadata:     ds.Metadata,
 			TsCreated:    ds.Created,
 			Tags:         ds.Tags,
@@ -351,6 +362,7 @@ func validateDatasetEndpoint(svc policies.Service) endpoint.Endpoint {
 			AgentGroupID: req.AgentGroupID,
 			PolicyID:     req.PolicyID,
 			SinkIDs:      &req.SinkIDs,
+			SinkFilters:  req.SinkFilters,
 			Tags:         req.Tags,
 		}
 
@@ -366,6 +378,7 @@ func validateDatasetEndpoint(svc policies.Service) endpoint.Endpoint {
 			AgentGroupID: validated.AgentGroupID,
 			PolicyID:     validated.PolicyID,
 			SinkIDs:      *validated.SinkIDs,
+			SinkFilters:  validated.SinkFilters,
 		}
 
 		return res, nil
@@ -391,6 +404,7 @@ func viewDatasetEndpoint(svc policies.Service) endpoint.Endpoint {
 			AgentGroupID: dataset.AgentGroupID,
 			Valid:        dataset.Valid,
 			TsCreated:    dataset.Created,
+			SinkFilters:  dataset.SinkFilters,
 		}
 		if dataset.SinkIDs != nil {
 			res.SinkIDs = *dataset.SinkIDs
@@ -432,6 +446,7 @@ func listDatasetEndpoint(svc policies.Service) endpoint.Endpoint {
 				TsCreated:    dataset.Created,
 				Valid:        dataset.Valid,
 				Tags:         dataset.Tags,
+				SinkFilters:  dataset.SinkFilters,
 			}
 			if dataset.SinkIDs != nil {
 				view.SinkIDs = *dataset.SinkIDs
@@ -471,3 +486,125 @@ func duplicatePolicyEndpoint(svc policies.Service) endpoint.Endpoint {
 		return res, nil
 	}
 }
+
+func listPolicyVersionsEndpoint(svc policies.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(viewResourceReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		versions, err := svc.ListPolicyVersions(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := policyVersionsRes{
+			PolicyID: req.id,
+			Versions: []policyVersionRes{},
+		}
+		for _, v := range versions {
+			// the policy contents are only returned when viewing a single version
+			res.Versions = append(res.Versions, policyVersionRes{
+				PolicyID:    v.PolicyID,
+				Version:     v.Version,
+				Name:        v.Name.String(),
+				Description: derefString(v.Description),
+				Tags:        v.OrbTags,
+				Format:      v.Format,
+				Created:     v.Created,
+			})
+		}
+		return res, nil
+	}
+}
+
+func viewPolicyVersionEndpoint(svc policies.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(policyVersionReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		v, err := svc.ViewPolicyVersion(ctx, req.token, req.id, req.version)
+		if err != nil {
+			return nil, err
+		}
+
+		res := policyVersionRes{
+			PolicyID:    v.PolicyID,
+			Version:     v.Version,
+			Name:        v.Name.String(),
+			Description: derefString(v.Description),
+			Tags:        v.OrbTags,
+			Policy:      v.Policy,
+			Format:      v.Format,
+			PolicyData:  v.PolicyData,
+			Created:     v.Created,
+		}
+		return res, nil
+	}
+}
+
+func diffPolicyVersionsEndpoint(svc policies.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(diffPolicyVersionsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		diff, err := svc.DiffPolicyVersions(ctx, req.token, req.id, req.from, req.to)
+		if err != nil {
+			return nil, err
+		}
+
+		res := policyDiffRes{
+			PolicyID:    diff.PolicyID,
+			FromVersion: diff.FromVersion,
+			ToVersion:   diff.ToVersion,
+			Changes:     []policyChangeRes{},
+		}
+		for _, c := range diff.Changes {
+			res.Changes = append(res.Changes, policyChangeRes{
+				Path:      c.Path,
+				Operation: c.Operation,
+				From:      c.From,
+				To:        c.To,
+			})
+		}
+		return res, nil
+	}
+}
+
+func rollbackPolicyEndpoint(svc policies.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(rollbackPolicyReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.RollbackPolicy(ctx, req.token, req.id, *req.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		plcyRes := policyUpdateRes{
+			ID:          res.ID,
+			Name:        res.Name.String(),
+			Description: derefString(res.Description),
+			Tags:        res.OrbTags,
+			Policy:      res.Policy,
+			Format:      res.Format,
+			PolicyData:  res.PolicyData,
+			Version:     res.Version,
+		}
+		return plcyRes, nil
+	}
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
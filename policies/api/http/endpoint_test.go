@@ -119,7 +119,11 @@ func newService(auth mainflux.AuthServiceClient) policies.Service {
 	SinkServiceClient := sinkmocks.NewClient()
 	logger := zap.NewNop()
 
-	return policies.New(logger, auth, policyRepo, fleetGrpcClient, SinkServiceClient)
+	svc, err := policies.New(logger, auth, policyRepo, fleetGrpcClient, SinkServiceClient)
+	if err != nil {
+		panic(err)
+	}
+	return svc
 }
 
 func newServer(svc policies.Service) *httptest.Server {
@@ -480,6 +484,61 @@ func TestPolicyEdition(t *testing.T) {
 				},
 			}),
 		},
+		"update a existing policy with a canary rollout": {
+			id:          policy.ID,
+			contentType: "application/json",
+			auth:        token,
+			status:      http.StatusOK,
+			data: toJSON(updatePolicyReq{
+				Name:       "mypktvisorpolicyyaml-3",
+				PolicyData: policy_data,
+				Format:     "yaml",
+				Rollout:    &rolloutReq{CanaryPercent: 10, OnFailure: "halt", Timeout: 600},
+			}),
+		},
+		"update a existing policy with a rollout without policy changes": {
+			id:          policy.ID,
+			contentType: "application/json",
+			auth:        token,
+			status:      http.StatusBadRequest,
+			data: toJSON(updatePolicyReq{
+				Name:    "mypktvisorpolicyyaml-3",
+				Rollout: &rolloutReq{CanaryCount: 1},
+			}),
+		},
+		"update a existing policy with both canary percent and count": {
+			id:          policy.ID,
+			contentType: "application/json",
+			auth:        token,
+			status:      http.StatusBadRequest,
+			data: toJSON(updatePolicyReq{
+				PolicyData: policy_data,
+				Format:     "yaml",
+				Rollout:    &rolloutReq{CanaryPercent: 10, CanaryCount: 1},
+			}),
+		},
+		"update a existing policy with a canary percent above 100": {
+			id:          policy.ID,
+			contentType: "application/json",
+			auth:        token,
+			status:      http.StatusBadRequest,
+			data: toJSON(updatePolicyReq{
+				PolicyData: policy_data,
+				Format:     "yaml",
+				Rollout:    &rolloutReq{CanaryPercent: 150},
+			}),
+		},
+		"update a existing policy with an invalid rollout failure action": {
+			id:          policy.ID,
+			contentType: "application/json",
+			auth:        token,
+			status:      http.StatusBadRequest,
+			data: toJSON(updatePolicyReq{
+				PolicyData: policy_data,
+				Format:     "yaml",
+				Rollout:    &rolloutReq{CanaryCount: 1, OnFailure: "ignore"},
+			}),
+		},
 	}
 
 	for desc, tc := range cases {
@@ -652,6 +711,38 @@ func TestValidatePolicy(t *testing.T) {
 
 }
 
+func TestValidatePolicyFieldErrors(t *testing.T) {
+	cli := newClientServer(t)
+	defer cli.server.Close()
+
+	invalidHandlerYaml := `{"name": "mypktvisorpolicyyaml-3", "backend": "pktvisor", "format": "yaml", "policy_data": "handlers:\n  modules:\n    default_dns:\n      type: dnz\ninput:\n  input_type: pcap\n  tap: default_pcap\nkind: collection"}`
+
+	req := testRequest{
+		client:      cli.server.Client(),
+		method:      http.MethodPost,
+		url:         fmt.Sprintf("%s/policies/agent/validate", cli.server.URL),
+		contentType: contentType,
+		token:       fmt.Sprintf("Bearer %s", token),
+		body:        strings.NewReader(invalidHandlerYaml),
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode, fmt.Sprintf("expected %d got %d", http.StatusBadRequest, res.StatusCode))
+
+	var body struct {
+		Error  string `json:"error"`
+		Errors []struct {
+			Path    string `json:"path"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&body)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Len(t, body.Errors, 1)
+	assert.Equal(t, "handlers.modules.default_dns.type", body.Errors[0].Path)
+	assert.Contains(t, body.Error, "failed to validate policy")
+}
+
 func TestCreatePolicy(t *testing.T) {
 	cli := newClientServer(t)
 	defer cli.server.Close()
@@ -1494,6 +1585,124 @@ func TestDuplicatePolicy(t *testing.T) {
 	}
 }
 
+func TestPolicyVersionsAndRollback(t *testing.T) {
+	cli := newClientServer(t)
+	defer cli.server.Close()
+
+	policy := createPolicy(t, &cli, "policy")
+	newDescription := "edited description"
+	_, err := cli.service.EditPolicy(context.Background(), token, policies.Policy{ID: policy.ID, Description: &newDescription})
+	require.Nil(t, err, fmt.Sprintf("Unexpected error: %s", err))
+
+	cases := map[string]struct {
+		method string
+		url    string
+		body   string
+		auth   string
+		status int
+	}{
+		"list versions of a existing policy": {
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/policies/agent/%s/versions", policy.ID),
+			auth:   token,
+			status: http.StatusOK,
+		},
+		"list versions of a non-existing policy": {
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/policies/agent/%s/versions", wrongID),
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		"list versions with an invalid token": {
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/policies/agent/%s/versions", policy.ID),
+			auth:   invalidToken,
+			status: http.StatusUnauthorized,
+		},
+		"view a existing policy version": {
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/policies/agent/%s/versions/0", policy.ID),
+			auth:   token,
+			status: http.StatusOK,
+		},
+		"view a non-existing policy version": {
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/policies/agent/%s/versions/9", policy.ID),
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		"view a policy version with an invalid version": {
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/policies/agent/%s/versions/latest", policy.ID),
+			auth:   token,
+			status: http.StatusBadRequest,
+		},
+		"diff two policy versions": {
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/policies/agent/%s/diff?from=0&to=1", policy.ID),
+			auth:   token,
+			status: http.StatusOK,
+		},
+		"diff policy versions without a target version": {
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/policies/agent/%s/diff?from=0", policy.ID),
+			auth:   token,
+			status: http.StatusBadRequest,
+		},
+		"rollback a policy to its current version": {
+			method: http.MethodPost,
+			url:    fmt.Sprintf("/policies/agent/%s/rollback", policy.ID),
+			body:   `{"version": 1}`,
+			auth:   token,
+			status: http.StatusBadRequest,
+		},
+		"rollback a policy without a version": {
+			method: http.MethodPost,
+			url:    fmt.Sprintf("/policies/agent/%s/rollback", policy.ID),
+			body:   `{}`,
+			auth:   token,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			req := testRequest{
+				client:      cli.server.Client(),
+				method:      tc.method,
+				url:         fmt.Sprintf("%s%s", cli.server.URL, tc.url),
+				contentType: contentType,
+				token:       fmt.Sprintf("Bearer %s", tc.auth),
+				body:        strings.NewReader(tc.body),
+			}
+			res, err := req.make()
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+			assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+		})
+	}
+
+	req := testRequest{
+		client:      cli.server.Client(),
+		method:      http.MethodPost,
+		url:         fmt.Sprintf("%s/policies/agent/%s/rollback", cli.server.URL, policy.ID),
+		contentType: contentType,
+		token:       fmt.Sprintf("Bearer %s", token),
+		body:        strings.NewReader(`{"version": 0}`),
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, http.StatusOK, res.StatusCode, fmt.Sprintf("expected status code %d got %d", http.StatusOK, res.StatusCode))
+
+	var body struct {
+		Description string `json:"description"`
+		Version     int32  `json:"version"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&body)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, "description example", body.Description)
+	assert.Equal(t, int32(2), body.Version)
+}
+
 func createPolicy(t *testing.T, cli *clientServer, name string) policies.Policy {
 	t.Helper()
 	ID, err := uuid.NewV4()
@@ -1619,4 +1828,12 @@ type updatePolicyReq struct {
 	Format      string         `json:"format,omitempty"`
 	Policy      types.Metadata `json:"policy,omitempty"`
 	PolicyData  string         `json:"policy_data,omitempty"`
+	Rollout     *rolloutReq    `json:"rollout,omitempty"`
+}
+
+type rolloutReq struct {
+	CanaryPercent int    `json:"canary_percent,omitempty"`
+	CanaryCount   int    `json:"canary_count,omitempty"`
+	OnFailure     string `json:"on_failure,omitempty"`
+	Timeout       int    `json:"timeout,omitempty"`
 }
@@ -340,6 +340,104 @@ func (l loggingMiddleware) DuplicatePolicy(ctx context.Context, token string, po
 	return l.svc.DuplicatePolicy(ctx, token, policyID, name)
 }
 
+func (l loggingMiddleware) ListPolicyVersions(ctx context.Context, token string, policyID string) (_ []policies.PolicyVersion, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: list_policy_versions",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: list_policy_versions",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ListPolicyVersions(ctx, token, policyID)
+}
+
+func (l loggingMiddleware) ViewPolicyVersion(ctx context.Context, token string, policyID string, version int32) (_ policies.PolicyVersion, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: view_policy_version",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: view_policy_version",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ViewPolicyVersion(ctx, token, policyID, version)
+}
+
+func (l loggingMiddleware) DiffPolicyVersions(ctx context.Context, token string, policyID string, from int32, to int32) (_ policies.PolicyDiff, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: diff_policy_versions",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: diff_policy_versions",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.DiffPolicyVersions(ctx, token, policyID, from, to)
+}
+
+func (l loggingMiddleware) RollbackPolicy(ctx context.Context, token string, policyID string, version int32) (_ policies.Policy, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: rollback_policy",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: rollback_policy",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.RollbackPolicy(ctx, token, policyID, version)
+}
+
+func (l loggingMiddleware) ViewPolicyVersionInternal(ctx context.Context, policyID string, ownerID string, version int32) (_ policies.PolicyVersion, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: view_policy_version_internal",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: view_policy_version_internal",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ViewPolicyVersionInternal(ctx, policyID, ownerID, version)
+}
+
+func (l loggingMiddleware) RollbackPolicyInternal(ctx context.Context, ownerID string, policyID string, version int32) (_ policies.Policy, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: rollback_policy_internal",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: rollback_policy_internal",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.RollbackPolicyInternal(ctx, ownerID, policyID, version)
+}
+
+func (l loggingMiddleware) ListDatasetsByPolicyOwnerInternal(ctx context.Context, policyID string, ownerID string) (_ []policies.Dataset, err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: list_datasets_by_policy_owner_internal",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: list_datasets_by_policy_owner_internal",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.ListDatasetsByPolicyOwnerInternal(ctx, policyID, ownerID)
+}
+
 func NewLoggingMiddleware(svc policies.Service, logger *zap.Logger) policies.Service {
 	return &loggingMiddleware{logger, svc}
 }
@@ -493,6 +493,145 @@ func (m metricsMiddleware) DuplicatePolicy(ctx context.Context, token string, po
 	return m.svc.DuplicatePolicy(ctx, token, policyID, name)
 }
 
+func (m metricsMiddleware) ListPolicyVersions(ctx context.Context, token string, policyID string) ([]policies.PolicyVersion, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "listPolicyVersions",
+			"owner_id", ownerID,
+			"policy_id", policyID,
+			"dataset_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ListPolicyVersions(ctx, token, policyID)
+}
+
+func (m metricsMiddleware) ViewPolicyVersion(ctx context.Context, token string, policyID string, version int32) (policies.PolicyVersion, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return policies.PolicyVersion{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "viewPolicyVersion",
+			"owner_id", ownerID,
+			"policy_id", policyID,
+			"dataset_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ViewPolicyVersion(ctx, token, policyID, version)
+}
+
+func (m metricsMiddleware) DiffPolicyVersions(ctx context.Context, token string, policyID string, from int32, to int32) (policies.PolicyDiff, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return policies.PolicyDiff{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "diffPolicyVersions",
+			"owner_id", ownerID,
+			"policy_id", policyID,
+			"dataset_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.DiffPolicyVersions(ctx, token, policyID, from, to)
+}
+
+func (m metricsMiddleware) RollbackPolicy(ctx context.Context, token string, policyID string, version int32) (policies.Policy, error) {
+	ownerID, err := m.identify(token)
+	if err != nil {
+		return policies.Policy{}, err
+	}
+
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "rollbackPolicy",
+			"owner_id", ownerID,
+			"policy_id", policyID,
+			"dataset_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.RollbackPolicy(ctx, token, policyID, version)
+}
+
+func (m metricsMiddleware) ViewPolicyVersionInternal(ctx context.Context, policyID string, ownerID string, version int32) (policies.PolicyVersion, error) {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "viewPolicyVersionInternal",
+			"owner_id", ownerID,
+			"policy_id", policyID,
+			"dataset_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ViewPolicyVersionInternal(ctx, policyID, ownerID, version)
+}
+
+func (m metricsMiddleware) RollbackPolicyInternal(ctx context.Context, ownerID string, policyID string, version int32) (policies.Policy, error) {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "rollbackPolicyInternal",
+			"owner_id", ownerID,
+			"policy_id", policyID,
+			"dataset_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.RollbackPolicyInternal(ctx, ownerID, policyID, version)
+}
+
+func (m metricsMiddleware) ListDatasetsByPolicyOwnerInternal(ctx context.Context, policyID string, ownerID string) ([]policies.Dataset, error) {
+	defer func(begin time.Time) {
+		labels := []string{
+			"method", "listDatasetsByPolicyOwnerInternal",
+			"owner_id", ownerID,
+			"policy_id", policyID,
+			"dataset_id", "",
+		}
+
+		m.counter.With(labels...).Add(1)
+		m.latency.With(labels...).Observe(float64(time.Since(begin).Microseconds()))
+
+	}(time.Now())
+
+	return m.svc.ListDatasetsByPolicyOwnerInternal(ctx, policyID, ownerID)
+}
+
 func (m metricsMiddleware) identify(token string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
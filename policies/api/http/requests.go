@@ -9,6 +9,8 @@
 package http
 
 import (
+	"time"
+
 	"github.com/orb-community/orb/pkg/errors"
 	"github.com/orb-community/orb/pkg/types"
 	"github.com/orb-community/orb/policies"
@@ -21,6 +23,9 @@ const (
 	idOrder      = "id"
 	ascDir       = "asc"
 	descDir      = "desc"
+
+	defRolloutTimeout = 10 * time.Minute
+	maxRolloutTimeout = 24 * 60 * 60
 )
 
 type addPolicyReq struct {
@@ -94,6 +99,7 @@ type updatePolicyReq struct {
 	Format      string         `json:"format,omitempty"`
 	Policy      types.Metadata `json:"policy,omitempty"`
 	PolicyData  string         `json:"policy_data,omitempty"`
+	Rollout     *rolloutReq    `json:"rollout,omitempty"`
 }
 
 func (req updatePolicyReq) validate() error {
@@ -106,15 +112,69 @@ func (req updatePolicyReq) validate() error {
 		return errors.ErrMalformedEntity
 	}
 
+	if req.Rollout != nil {
+		// only a new policy body can be staged, metadata edits do not reach the agents
+		if req.PolicyData == "" && req.Policy == nil {
+			return errors.Wrap(errors.ErrMalformedEntity, errors.New("rollout requires a policy change"))
+		}
+		if err := req.Rollout.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type rolloutReq struct {
+	CanaryPercent int    `json:"canary_percent,omitempty"`
+	CanaryCount   int    `json:"canary_count,omitempty"`
+	OnFailure     string `json:"on_failure,omitempty"`
+	Timeout       int    `json:"timeout,omitempty"`
+}
+
+func (req rolloutReq) validate() error {
+	if (req.CanaryPercent == 0) == (req.CanaryCount == 0) {
+		return errors.Wrap(errors.ErrMalformedEntity, errors.New("rollout requires either canary_percent or canary_count"))
+	}
+
+	if req.CanaryPercent < 0 || req.CanaryPercent > 100 || req.CanaryCount < 0 {
+		return errors.Wrap(errors.ErrMalformedEntity, errors.New("invalid rollout canary size"))
+	}
+
+	if req.OnFailure != "" && req.OnFailure != policies.RolloutHalt && req.OnFailure != policies.RolloutRollback {
+		return errors.Wrap(errors.ErrMalformedEntity, errors.New("rollout on_failure must be halt or rollback"))
+	}
+
+	if req.Timeout < 0 || req.Timeout > maxRolloutTimeout {
+		return errors.Wrap(errors.ErrMalformedEntity, errors.New("invalid rollout timeout"))
+	}
+
 	return nil
 }
 
+func (req rolloutReq) strategy() *policies.RolloutStrategy {
+	rs := &policies.RolloutStrategy{
+		CanaryPercent: req.CanaryPercent,
+		CanaryCount:   req.CanaryCount,
+		OnFailure:     req.OnFailure,
+		Timeout:       time.Duration(req.Timeout) * time.Second,
+	}
+	if rs.OnFailure == "" {
+		rs.OnFailure = policies.RolloutRollback
+	}
+	if rs.Timeout == 0 {
+		rs.Timeout = defRolloutTimeout
+	}
+	return rs
+}
+
 type addDatasetReq struct {
-	Name         string     `json:"name"`
-	AgentGroupID string     `json:"agent_group_id"`
-	PolicyID     string     `json:"agent_policy_id"`
-	SinkIDs      []string   `json:"sink_ids"`
-	Tags         types.Tags `json:"tags"`
+	Name         string                         `json:"name"`
+	AgentGroupID string                         `json:"agent_group_id"`
+	PolicyID     string                         `json:"agent_policy_id"`
+	SinkIDs      []string                       `json:"sink_ids"`
+	SinkFilters  map[string]policies.SinkFilter `json:"sink_filters,omitempty"`
+	Tags         types.Tags                     `json:"tags"`
 	token        string
 }
 
@@ -171,11 +231,12 @@ func (req *listResourcesReq) validate() error {
 }
 
 type updateDatasetReq struct {
-	Name    string `json:"name,omitempty"`
-	id      string
-	token   string
-	Tags    types.Tags `json:"tags,omitempty"`
-	SinkIDs *[]string  `json:"sink_ids,omitempty"`
+	Name        string `json:"name,omitempty"`
+	id          string
+	token       string
+	Tags        types.Tags                     `json:"tags,omitempty"`
+	SinkIDs     *[]string                      `json:"sink_ids,omitempty"`
+	SinkFilters map[string]policies.SinkFilter `json:"sink_filters,omitempty"`
 }
 
 func (req updateDatasetReq) validate() error {
@@ -186,7 +247,7 @@ func (req updateDatasetReq) validate() error {
 		return errors.ErrUnauthorizedAccess
 	}
 
-	if req.Name == "" && req.Tags == nil && req.SinkIDs == nil {
+	if req.Name == "" && req.Tags == nil && req.SinkIDs == nil && req.SinkFilters == nil {
 		return errors.ErrMalformedEntity
 	}
 
@@ -216,3 +277,52 @@ func (req duplicatePolicyReq) validate() error {
 
 	return nil
 }
+
+type policyVersionReq struct {
+	token   string
+	id      string
+	version int32
+}
+
+func (req policyVersionReq) validate() error {
+	if req.token == "" {
+		return errors.ErrUnauthorizedAccess
+	}
+	if req.id == "" || req.version < 0 {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+type diffPolicyVersionsReq struct {
+	token string
+	id    string
+	from  int32
+	to    int32
+}
+
+func (req diffPolicyVersionsReq) validate() error {
+	if req.token == "" {
+		return errors.ErrUnauthorizedAccess
+	}
+	if req.id == "" || req.from < 0 || req.to < 0 {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+type rollbackPolicyReq struct {
+	id      string
+	token   string
+	Version *int32 `json:"version"`
+}
+
+func (req rollbackPolicyReq) validate() error {
+	if req.token == "" {
+		return errors.ErrUnauthorizedAccess
+	}
+	if req.id == "" || req.Version == nil || *req.Version < 0 {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
@@ -6,6 +6,8 @@ package http
 
 import (
 	"github.com/orb-community/orb/pkg/types"
+	"github.com/orb-community/orb/policies"
+	"github.com/orb-community/orb/policies/backend"
 	"net/http"
 	"time"
 )
@@ -51,6 +53,14 @@ type policyUpdateRes struct {
 	Format      string         `json:"format,omitempty"`
 	PolicyData  string         `json:"policy_data,omitempty"`
 	Version     int32          `json:"version,omitempty"`
+	Rollout     *rolloutRes    `json:"rollout,omitempty"`
+}
+
+type rolloutRes struct {
+	CanaryPercent int    `json:"canary_percent,omitempty"`
+	CanaryCount   int    `json:"canary_count,omitempty"`
+	OnFailure     string `json:"on_failure"`
+	Timeout       int    `json:"timeout"`
 }
 
 func (s policyUpdateRes) Code() int {
@@ -123,6 +133,7 @@ type validateDatasetRes struct {
 	AgentGroupID string
 	PolicyID     string
 	SinkIDs      []string
+	SinkFilters  map[string]policies.SinkFilter
 	Valid        bool
 	Tags         types.Tags
 }
@@ -140,15 +151,16 @@ func (s validateDatasetRes) Empty() bool {
 }
 
 type datasetRes struct {
-	ID           string         `json:"id"`
-	Name         string         `json:"name"`
-	Valid        bool           `json:"valid"`
-	AgentGroupID string         `json:"agent_group_id"`
-	PolicyID     string         `json:"agent_policy_id"`
-	SinkIDs      []string       `json:"sink_ids"`
-	Metadata     types.Metadata `json:"metadata"`
-	TsCreated    time.Time      `json:"ts_created"`
-	Tags         types.Tags     `json:"tags"`
+	ID           string                         `json:"id"`
+	Name         string                         `json:"name"`
+	Valid        bool                           `json:"valid"`
+	AgentGroupID string                         `json:"agent_group_id"`
+	PolicyID     string                         `json:"agent_policy_id"`
+	SinkIDs      []string                       `json:"sink_ids"`
+	SinkFilters  map[string]policies.SinkFilter `json:"sink_filters,omitempty"`
+	Metadata     types.Metadata                 `json:"metadata"`
+	TsCreated    time.Time                      `json:"ts_created"`
+	Tags         types.Tags                     `json:"tags"`
 	created      bool
 }
 
@@ -192,3 +204,75 @@ type pageRes struct {
 	Order  string `json:"order"`
 	Dir    string `json:"direction"`
 }
+
+type policyVersionRes struct {
+	PolicyID    string         `json:"policy_id"`
+	Version     int32          `json:"version"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Tags        types.Tags     `json:"tags,omitempty"`
+	Policy      types.Metadata `json:"policy,omitempty"`
+	Format      string         `json:"format,omitempty"`
+	PolicyData  string         `json:"policy_data,omitempty"`
+	Created     time.Time      `json:"ts_created"`
+}
+
+func (s policyVersionRes) Code() int {
+	return http.StatusOK
+}
+
+func (s policyVersionRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (s policyVersionRes) Empty() bool {
+	return false
+}
+
+type policyVersionsRes struct {
+	PolicyID string             `json:"policy_id"`
+	Versions []policyVersionRes `json:"data"`
+}
+
+func (res policyVersionsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res policyVersionsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res policyVersionsRes) Empty() bool {
+	return false
+}
+
+type policyChangeRes struct {
+	Path      string      `json:"path"`
+	Operation string      `json:"op"`
+	From      interface{} `json:"from,omitempty"`
+	To        interface{} `json:"to,omitempty"`
+}
+
+type policyDiffRes struct {
+	PolicyID    string            `json:"policy_id"`
+	FromVersion int32             `json:"from_version"`
+	ToVersion   int32             `json:"to_version"`
+	Changes     []policyChangeRes `json:"changes"`
+}
+
+func (res policyDiffRes) Code() int {
+	return http.StatusOK
+}
+
+func (res policyDiffRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res policyDiffRes) Empty() bool {
+	return false
+}
+
+type validationErrorRes struct {
+	Err    string               `json:"error"`
+	Fields []backend.FieldError `json:"errors,omitempty"`
+}
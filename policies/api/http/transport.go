@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	kitot "github.com/go-kit/kit/tracing/opentracing"
@@ -22,6 +23,7 @@ import (
 	"github.com/orb-community/orb/pkg/errors"
 	"github.com/orb-community/orb/pkg/types"
 	"github.com/orb-community/orb/policies"
+	"github.com/orb-community/orb/policies/backend"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
@@ -34,6 +36,8 @@ const (
 	dirKey      = "dir"
 	metadataKey = "metadata"
 	tagsKey     = "tags"
+	fromKey     = "from"
+	toKey       = "to"
 	defOffset   = 0
 	defLimit    = 10
 )
@@ -69,6 +73,26 @@ func MakeHandler(tracer opentracing.Tracer, svcName string, svc policies.Service
 		decodePolicyDuplicate,
 		types.EncodeResponse,
 		opts...))
+	r.Get("/policies/agent/:id/versions", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_policy_versions")(listPolicyVersionsEndpoint(svc)),
+		decodeView,
+		types.EncodeResponse,
+		opts...))
+	r.Get("/policies/agent/:id/versions/:version", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_policy_version")(viewPolicyVersionEndpoint(svc)),
+		decodePolicyVersion,
+		types.EncodeResponse,
+		opts...))
+	r.Get("/policies/agent/:id/diff", kithttp.NewServer(
+		kitot.TraceServer(tracer, "diff_policy_versions")(diffPolicyVersionsEndpoint(svc)),
+		decodePolicyVersionsDiff,
+		types.EncodeResponse,
+		opts...))
+	r.Post("/policies/agent/:id/rollback", kithttp.NewServer(
+		kitot.TraceServer(tracer, "rollback_policy")(rollbackPolicyEndpoint(svc)),
+		decodePolicyRollback,
+		types.EncodeResponse,
+		opts...))
 	r.Delete("/policies/agent/:id", kithttp.NewServer(
 		kitot.TraceServer(tracer, "remove_policy")(removePolicyEndpoint(svc)),
 		decodeView,
@@ -251,10 +275,80 @@ func decodePolicyDuplicate(_ context.Context, r *http.Request) (interface{}, err
 	return req, nil
 }
 
+func decodePolicyVersion(_ context.Context, r *http.Request) (interface{}, error) {
+	v, err := parseVersion(bone.GetValue(r, "version"))
+	if err != nil {
+		return nil, err
+	}
+
+	req := policyVersionReq{
+		token:   parseJwt(r),
+		id:      bone.GetValue(r, "id"),
+		version: v,
+	}
+	return req, nil
+}
+
+func decodePolicyVersionsDiff(_ context.Context, r *http.Request) (interface{}, error) {
+	f, err := httputil.ReadStringQuery(r, fromKey, "")
+	if err != nil {
+		return nil, err
+	}
+	from, err := parseVersion(f)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := httputil.ReadStringQuery(r, toKey, "")
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseVersion(t)
+	if err != nil {
+		return nil, err
+	}
+
+	req := diffPolicyVersionsReq{
+		token: parseJwt(r),
+		id:    bone.GetValue(r, "id"),
+		from:  from,
+		to:    to,
+	}
+	return req, nil
+}
+
+func decodePolicyRollback(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	req := rollbackPolicyReq{
+		token: parseJwt(r),
+		id:    bone.GetValue(r, "id"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func parseVersion(v string) (int32, error) {
+	version, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		return 0, errors.Wrap(errors.ErrInvalidQueryParams, err)
+	}
+	return int32(version), nil
+}
+
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	switch errorVal := err.(type) {
 	case errors.Error:
 		w.Header().Set("Content-Type", types.ContentType)
+		if errors.Contains(errorVal, policies.ErrValidatePolicy) {
+			encodeValidationError(errorVal, w)
+			return
+		}
 		switch {
 		case errors.Contains(errorVal, errors.ErrUnauthorizedAccess):
 			w.WriteHeader(http.StatusUnauthorized)
@@ -291,6 +385,28 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	}
 }
 
+// encodeValidationError reports an invalid policy with the reason and, when the backend
+// provides them, the path of every invalid field
+func encodeValidationError(err errors.Error, w http.ResponseWriter) {
+	// skip the layers added on top of the validation failure, e.g. failed to create policy
+	reason := err
+	for reason != nil && reason.Msg() != policies.ErrValidatePolicy.Error() {
+		reason = reason.Err()
+	}
+	if reason == nil {
+		reason = err
+	}
+
+	w.WriteHeader(http.StatusBadRequest)
+	res := validationErrorRes{
+		Err:    reason.Error(),
+		Fields: backend.FieldErrors(err),
+	}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
 func parseJwt(r *http.Request) (token string) {
 	if strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
 		token = r.Header.Get("Authorization")[7:]
@@ -12,6 +12,12 @@ type Backend interface {
 	Validate(policy types.Metadata) error
 }
 
+// ComponentsValidator is implemented by backends whose policies can only use the components reported
+// in the capabilities of the owner's agents, components maps a component kind to the reported names
+type ComponentsValidator interface {
+	ValidateComponents(policy types.Metadata, components map[string][]string) error
+}
+
 var registry = make(map[string]Backend)
 
 func Register(name string, b Backend) {
@@ -0,0 +1,78 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package backend
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/orb-community/orb/pkg/errors"
+)
+
+var _ errors.Error = (*ValidationError)(nil)
+
+// FieldError is a validation failure of a single policy field, Path is the dotted path of the field in the policy
+type FieldError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ValidationError collects every field error found while validating a policy.
+// It implements errors.Error so the field errors survive errors.Wrap
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Add records a field error, the message is formatted with fmt.Sprintf
+func (e *ValidationError) Add(path string, format string, args ...interface{}) {
+	e.Fields = append(e.Fields, FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// OrNil returns the validation error if any field error was recorded, sorted by path
+func (e *ValidationError) OrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	sort.SliceStable(e.Fields, func(i, j int) bool {
+		return e.Fields[i].Path < e.Fields[j].Path
+	})
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", f.Path, f.Message))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Msg() string {
+	return e.Error()
+}
+
+func (e *ValidationError) Err() errors.Error {
+	return nil
+}
+
+// FieldErrors returns the field errors contained in any layer of err
+func FieldErrors(err error) []FieldError {
+	for err != nil {
+		if ve, ok := err.(*ValidationError); ok {
+			return ve.Fields
+		}
+		e, ok := err.(errors.Error)
+		if !ok {
+			return nil
+		}
+		next := e.Err()
+		if next == nil {
+			return nil
+		}
+		err = next
+	}
+	return nil
+}
@@ -27,10 +27,16 @@ func (o otelBackend) ConvertFromFormat(format string, policy string) (metadata t
 	return
 }
 
-// Validate Will not validate anything until we have a better way to do this
-func (o otelBackend) Validate(_ types.Metadata) error {
-	// block everything related to the exporter tag, this is not supported
-	return nil
+var _ backend.ComponentsValidator = (*otelBackend)(nil)
+
+// Validate checks the policy structure, without checking the components available on the agents
+func (o otelBackend) Validate(policy types.Metadata) error {
+	return validate(policy, nil)
+}
+
+// ValidateComponents checks the policy structure and that its receivers and processors were reported by the agents
+func (o otelBackend) ValidateComponents(policy types.Metadata, components map[string][]string) error {
+	return validate(policy, components)
 }
 
 func Register(logger *zap.Logger) bool {
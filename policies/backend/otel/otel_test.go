@@ -0,0 +1,124 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package otel
+
+import (
+	"testing"
+
+	"github.com/orb-community/orb/policies/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestValidateComponents(t *testing.T) {
+	o := otelBackend{logger: zap.NewNop()}
+	available := map[string][]string{
+		"receivers":  {"hostmetrics", "otlp"},
+		"processors": {"batch", "filter"},
+	}
+
+	cases := map[string]struct {
+		policy     string
+		components map[string][]string
+		paths      []string
+	}{
+		"valid policy": {
+			policy: `
+receivers:
+  hostmetrics/cpu:
+    collection_interval: 60s
+processors:
+  batch:
+service:
+  pipelines:
+    metrics:
+      receivers: [hostmetrics/cpu]
+      processors: [batch]`,
+			components: available,
+		},
+		"exporters override": {
+			policy: `
+receivers:
+  otlp:
+exporters:
+  otlp/external:
+    endpoint: collector.example.com:4317
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [otlp/external]`,
+			components: available,
+			paths:      []string{"exporters", "service.pipelines.traces.exporters"},
+		},
+		"components not available on the agents": {
+			policy: `
+receivers:
+  kafka:
+processors:
+  transform:
+service:
+  pipelines:
+    metrics:
+      receivers: [kafka]
+      processors: [transform]`,
+			components: available,
+			paths:      []string{"processors.transform", "receivers.kafka"},
+		},
+		"components are not checked without agent capabilities": {
+			policy: `
+receivers:
+  kafka:
+service:
+  pipelines:
+    metrics:
+      receivers: [kafka]`,
+		},
+		"undeclared references and unsupported pipeline": {
+			policy: `
+receivers:
+  otlp:
+service:
+  pipelines:
+    metrics:
+      receivers: [hostmetrics]
+      processors: [batch]
+    metrics/custom:
+      receivers: [otlp]`,
+			components: available,
+			paths: []string{
+				"service.pipelines.metrics.processors[0]",
+				"service.pipelines.metrics.receivers[0]",
+				"service.pipelines.metrics/custom",
+			},
+		},
+		"missing sections": {
+			policy: `
+processors:
+  batch:`,
+			components: available,
+			paths:      []string{"receivers", "service"},
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			policy, err := o.ConvertFromFormat("yaml", tc.policy)
+			require.NoError(t, err)
+			err = o.ValidateComponents(policy, tc.components)
+			if len(tc.paths) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			var paths []string
+			for _, f := range backend.FieldErrors(err) {
+				paths = append(paths, f.Path)
+			}
+			assert.Equal(t, tc.paths, paths)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package otel
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/orb-community/orb/policies/backend"
+)
+
+const (
+	receiversKind  = "receivers"
+	processorsKind = "processors"
+)
+
+// supportedPipelines are the pipelines the agent wires to its MQTT exporter
+var supportedPipelines = []string{"logs", "metrics", "traces"}
+
+// validate checks the policy can run on the agent collector: exporters are always replaced by the agent
+// so they can not be set, every pipeline must only reference declared receivers and processors and, when
+// the owner's agents reported their components, receivers and processors must be available on them
+func validate(policy types.Metadata, components map[string][]string) error {
+	verr := &backend.ValidationError{}
+
+	if _, ok := policy["exporters"]; ok {
+		verr.Add("exporters", "exporters are managed by the agent, data is always exported through orb")
+	}
+
+	receivers := validateComponents(verr, receiversKind, policy[receiversKind], components[receiversKind], true)
+	processors := validateComponents(verr, processorsKind, policy[processorsKind], components[processorsKind], false)
+
+	service, ok := asMap(policy["service"])
+	if !ok {
+		verr.Add("service", "is required")
+		return verr.OrNil()
+	}
+	pipelines, ok := asMap(service["pipelines"])
+	if !ok || len(pipelines) == 0 {
+		verr.Add("service.pipelines", "at least one pipeline is required")
+		return verr.OrNil()
+	}
+
+	for _, name := range sortedKeys(pipelines) {
+		path := "service.pipelines." + name
+		if !contains(supportedPipelines, name) {
+			verr.Add(path, "unsupported pipeline, must be one of: %s", strings.Join(supportedPipelines, ", "))
+			continue
+		}
+		pipeline, ok := asMap(pipelines[name])
+		if !ok {
+			verr.Add(path, "must be an object")
+			continue
+		}
+		if _, ok := pipeline["exporters"]; ok {
+			verr.Add(path+".exporters", "pipeline exporters are managed by the agent and can not be overridden")
+		}
+		validateReferences(verr, path+"."+receiversKind, pipeline[receiversKind], receivers, true)
+		validateReferences(verr, path+"."+processorsKind, pipeline[processorsKind], processors, false)
+	}
+
+	return verr.OrNil()
+}
+
+// validateComponents checks the components declared in a top level section and returns their ids,
+// a component id is its type optionally followed by a name, i.e. "hostmetrics/cpu"
+func validateComponents(verr *backend.ValidationError, kind string, value interface{}, available []string, required bool) map[string]bool {
+	ids := make(map[string]bool)
+	if value == nil {
+		if required {
+			verr.Add(kind, "at least one %s entry is required", strings.TrimSuffix(kind, "s"))
+		}
+		return ids
+	}
+	section, ok := asMap(value)
+	if !ok {
+		verr.Add(kind, "must be an object")
+		return ids
+	}
+	if required && len(section) == 0 {
+		verr.Add(kind, "at least one %s entry is required", strings.TrimSuffix(kind, "s"))
+	}
+
+	for _, id := range sortedKeys(section) {
+		ids[id] = true
+		componentType, _, _ := strings.Cut(id, "/")
+		if len(available) > 0 && !contains(available, componentType) {
+			verr.Add(kind+"."+id, "%s '%s' is not available on the agents", strings.TrimSuffix(kind, "s"), componentType)
+		}
+	}
+	return ids
+}
+
+func validateReferences(verr *backend.ValidationError, path string, value interface{}, declared map[string]bool, required bool) {
+	if value == nil {
+		if required {
+			verr.Add(path, "is required")
+		}
+		return
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		verr.Add(path, "must be a list")
+		return
+	}
+	if required && len(list) == 0 {
+		verr.Add(path, "is required")
+	}
+	for i, v := range list {
+		id, ok := v.(string)
+		if !ok {
+			verr.Add(fmt.Sprintf("%s[%d]", path, i), "must be a string")
+			continue
+		}
+		if !declared[id] {
+			verr.Add(fmt.Sprintf("%s[%d]", path, i), "'%s' is not declared", id)
+		}
+	}
+}
+
+func asMap(value interface{}) (map[string]interface{}, bool) {
+	switch m := value.(type) {
+	case map[string]interface{}:
+		return m, true
+	case types.Metadata:
+		return m, true
+	}
+	return nil, false
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
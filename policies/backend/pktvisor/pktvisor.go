@@ -5,9 +5,8 @@
 package pktvisor
 
 import (
-	"errors"
-
 	"github.com/ghodss/yaml"
+	"github.com/orb-community/orb/pkg/errors"
 	"github.com/orb-community/orb/pkg/types"
 	"github.com/orb-community/orb/policies/backend"
 )
@@ -15,11 +14,13 @@ import (
 var _ backend.Backend = (*pktvisorBackend)(nil)
 
 type pktvisorBackend struct {
+	schema schema
 }
 
+// Validate checks the policy against the pktvisor handler and input schema, returning a
+// backend.ValidationError with every invalid field
 func (p pktvisorBackend) Validate(policy types.Metadata) error {
-	// todo finish validation
-	return nil
+	return p.schema.validate(policy)
 }
 
 func (p pktvisorBackend) convertFromYAML(policy string) (types.Metadata, error) {
@@ -59,7 +60,12 @@ func (p pktvisorBackend) SupportsFormat(format string) bool {
 	return false
 }
 
-func Register() bool {
-	backend.Register("pktvisor", &pktvisorBackend{})
-	return true
+// Register registers the pktvisor backend, failing when the handler and input schemas it validates policies with can't be loaded
+func Register() error {
+	s, err := loadSchema()
+	if err != nil {
+		return errors.Wrap(errors.New("failed to load pktvisor policy schema"), err)
+	}
+	backend.Register("pktvisor", &pktvisorBackend{schema: s})
+	return nil
 }
@@ -0,0 +1,138 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package pktvisor
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/orb-community/orb/policies/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	s, err := loadSchema()
+	require.NoError(t, err)
+	p := pktvisorBackend{schema: s}
+
+	cases := map[string]struct {
+		policy string
+		paths  []string
+	}{
+		"valid policy": {
+			policy: `
+kind: collection
+input:
+  input_type: pcap
+  tap: default_pcap
+  filter:
+    bpf: udp port 53
+handlers:
+  modules:
+    default_dns:
+      type: dns
+      filter:
+        only_rcode: 3
+        exclude_noerror: true
+        only_qname_suffix: [".example.com"]
+      metric_groups:
+        enable: [top_qnames, dns_transactions]
+        disable: [all]
+    default_net:
+      type: net
+      filter:
+        geoloc_notfound: true`,
+		},
+		"unknown handler module": {
+			policy: `
+kind: collection
+input:
+  input_type: pcap
+  tap: default_pcap
+handlers:
+  modules:
+    default_dns:
+      type: dnz`,
+			paths: []string{"handlers.modules.default_dns.type"},
+		},
+		"wrong filter types": {
+			policy: `
+kind: collection
+input:
+  input_type: pcap
+  tap: default_pcap
+  filter:
+    bpf: 53
+handlers:
+  modules:
+    default_dns:
+      type: dns
+      filter:
+        exclude_noerror: "yes"
+        only_qname_suffix: .example.com`,
+			paths: []string{
+				"handlers.modules.default_dns.filter.exclude_noerror",
+				"handlers.modules.default_dns.filter.only_qname_suffix",
+				"input.filter.bpf",
+			},
+		},
+		"unknown metric groups": {
+			policy: `
+kind: collection
+input:
+  input_type: pcap
+  tap: default_pcap
+handlers:
+  modules:
+    default_net:
+      type: net
+      metric_groups:
+        enable: [top_ips, top_qnames]
+        toggle: [top_geo]`,
+			paths: []string{
+				"handlers.modules.default_net.metric_groups.enable[1]",
+				"handlers.modules.default_net.metric_groups.toggle",
+			},
+		},
+		"missing required input config": {
+			policy: `
+kind: collection
+input:
+  input_type: pcap
+  config:
+    pcap_source: dpdk
+handlers:
+  modules:
+    default_dns:
+      type: dns`,
+			paths: []string{"input.config.iface", "input.config.pcap_source"},
+		},
+		"missing sections": {
+			policy: `
+kind: stream`,
+			paths: []string{"handlers", "input", "kind"},
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			var policy types.Metadata
+			require.NoError(t, yaml.Unmarshal([]byte(tc.policy), &policy))
+			err := p.Validate(policy)
+			if len(tc.paths) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			var paths []string
+			for _, f := range backend.FieldErrors(err) {
+				paths = append(paths, f.Path)
+			}
+			assert.Equal(t, tc.paths, paths)
+		})
+	}
+}
@@ -0,0 +1,300 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package pktvisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	fleetpktvisor "github.com/orb-community/orb/fleet/backend/pktvisor"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/orb-community/orb/policies/backend"
+)
+
+const (
+	collectionKind = "collection"
+	allMetricGroup = "all"
+)
+
+type fieldProps struct {
+	Required           bool                   `json:"required"`
+	AllowCustomOptions bool                   `json:"allow_custom_options"`
+	Options            map[string]interface{} `json:"options"`
+}
+
+type fieldSchema struct {
+	Type  string     `json:"type"`
+	Props fieldProps `json:"props"`
+}
+
+type inputSchema struct {
+	Filter map[string]fieldSchema `json:"filter"`
+	Config map[string]fieldSchema `json:"config"`
+}
+
+type handlerSchema struct {
+	Filter       map[string]fieldSchema     `json:"filter"`
+	Config       map[string]fieldSchema     `json:"config"`
+	MetricGroups map[string]json.RawMessage `json:"metric_groups"`
+}
+
+// schema is the pktvisor handler and input schema served by fleet, indexed by type and schema version
+type schema struct {
+	handlers map[string]map[string]handlerSchema
+	inputs   map[string]map[string]inputSchema
+}
+
+func loadSchema() (schema, error) {
+	var s schema
+	if err := json.Unmarshal([]byte(fleetpktvisor.HandlersJson), &s.handlers); err != nil {
+		return schema{}, err
+	}
+	if err := json.Unmarshal([]byte(fleetpktvisor.InputsJson), &s.inputs); err != nil {
+		return schema{}, err
+	}
+	return s, nil
+}
+
+func (s schema) validate(policy types.Metadata) error {
+	verr := &backend.ValidationError{}
+
+	kind, ok := policy["kind"]
+	if !ok {
+		verr.Add("kind", "is required")
+	} else if kind != collectionKind {
+		verr.Add("kind", "unsupported kind '%v', must be '%s'", kind, collectionKind)
+	}
+
+	if input, ok := policy["input"]; !ok {
+		verr.Add("input", "is required")
+	} else if m, ok := asMap(input); !ok {
+		verr.Add("input", "must be an object")
+	} else {
+		s.validateInput(verr, m)
+	}
+
+	if handlers, ok := policy["handlers"]; !ok {
+		verr.Add("handlers", "is required")
+	} else if m, ok := asMap(handlers); !ok {
+		verr.Add("handlers", "must be an object")
+	} else {
+		s.validateHandlers(verr, m)
+	}
+
+	return verr.OrNil()
+}
+
+func (s schema) validateInput(verr *backend.ValidationError, input map[string]interface{}) {
+	inputType, ok := input["input_type"].(string)
+	if !ok || inputType == "" {
+		verr.Add("input.input_type", "is required")
+		return
+	}
+	versions, ok := s.inputs[inputType]
+	if !ok {
+		verr.Add("input.input_type", "unknown input type '%s', must be one of: %s", inputType, strings.Join(sortedKeys(s.inputs), ", "))
+		return
+	}
+	is := versions[CurrentSchemaVersion]
+
+	tap, hasTap := input["tap"]
+	if hasTap {
+		if t, ok := tap.(string); !ok || t == "" {
+			verr.Add("input.tap", "must be a non-empty string")
+		}
+	}
+
+	validateFields(verr, "input.filter", input["filter"], is.Filter)
+	validateFields(verr, "input.config", input["config"], is.Config)
+
+	// a tap carries the input configuration defined on the agent
+	if !hasTap {
+		config, _ := asMap(input["config"])
+		for _, name := range sortedKeys(is.Config) {
+			if _, ok := config[name]; !ok && is.Config[name].Props.Required {
+				verr.Add("input.config."+name, "is required when the input does not reference a tap")
+			}
+		}
+	}
+}
+
+func (s schema) validateHandlers(verr *backend.ValidationError, handlers map[string]interface{}) {
+	modules, ok := asMap(handlers["modules"])
+	if !ok || len(modules) == 0 {
+		verr.Add("handlers.modules", "at least one handler module is required")
+		return
+	}
+
+	for _, name := range sortedKeys(modules) {
+		path := "handlers.modules." + name
+		module, ok := asMap(modules[name])
+		if !ok {
+			verr.Add(path, "must be an object")
+			continue
+		}
+
+		handlerType, ok := module["type"].(string)
+		if !ok || handlerType == "" {
+			verr.Add(path+".type", "is required")
+			continue
+		}
+		versions, ok := s.handlers[handlerType]
+		if !ok {
+			verr.Add(path+".type", "unknown handler type '%s', must be one of: %s", handlerType, strings.Join(sortedKeys(s.handlers), ", "))
+			continue
+		}
+		hs := versions[CurrentSchemaVersion]
+
+		validateFields(verr, path+".filter", module["filter"], hs.Filter)
+		validateFields(verr, path+".config", module["config"], hs.Config)
+		validateMetricGroups(verr, path+".metric_groups", module["metric_groups"], hs.MetricGroups)
+	}
+}
+
+func validateMetricGroups(verr *backend.ValidationError, path string, value interface{}, known map[string]json.RawMessage) {
+	if value == nil {
+		return
+	}
+	groups, ok := asMap(value)
+	if !ok {
+		verr.Add(path, "must be an object")
+		return
+	}
+	for _, op := range sortedKeys(groups) {
+		if op != "enable" && op != "disable" {
+			verr.Add(path+"."+op, "unknown key, must be 'enable' or 'disable'")
+			continue
+		}
+		list, ok := groups[op].([]interface{})
+		if !ok {
+			verr.Add(path+"."+op, "must be a list of metric groups")
+			continue
+		}
+		for i, g := range list {
+			group, ok := g.(string)
+			if !ok {
+				verr.Add(fmt.Sprintf("%s.%s[%d]", path, op, i), "must be a string")
+				continue
+			}
+			if _, ok := known[group]; !ok && group != allMetricGroup {
+				verr.Add(fmt.Sprintf("%s.%s[%d]", path, op, i), "unknown metric group '%s'", group)
+			}
+		}
+	}
+}
+
+// validateFields checks the type of the fields known to the schema, fields the schema does not
+// describe are left for pktvisor to validate since the schema only lists the ones shown in the UI
+func validateFields(verr *backend.ValidationError, path string, value interface{}, fields map[string]fieldSchema) {
+	if value == nil {
+		return
+	}
+	m, ok := asMap(value)
+	if !ok {
+		verr.Add(path, "must be an object")
+		return
+	}
+	for _, name := range sortedKeys(m) {
+		fs, ok := fields[name]
+		if !ok {
+			continue
+		}
+		if msg := fs.check(m[name]); msg != "" {
+			verr.Add(path+"."+name, msg)
+		}
+	}
+}
+
+func (fs fieldSchema) check(value interface{}) string {
+	switch fs.Type {
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Sprintf("must be a string, got %s", typeName(value))
+		}
+	case "number":
+		if !isNumber(value) {
+			return fmt.Sprintf("must be a number, got %s", typeName(value))
+		}
+	case "bool":
+		if _, ok := value.(bool); !ok {
+			return fmt.Sprintf("must be a boolean, got %s", typeName(value))
+		}
+	case "string[]":
+		list, ok := value.([]interface{})
+		if !ok {
+			return fmt.Sprintf("must be a list of strings, got %s", typeName(value))
+		}
+		for _, v := range list {
+			if _, ok := v.(string); !ok {
+				return "must be a list of strings"
+			}
+		}
+	}
+
+	if len(fs.Props.Options) > 0 && !fs.Props.AllowCustomOptions {
+		for _, option := range fs.Props.Options {
+			if reflect.DeepEqual(option, value) || (isNumber(option) && isNumber(value) && toFloat(option) == toFloat(value)) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("unsupported value '%v'", value)
+	}
+	return ""
+}
+
+func asMap(value interface{}) (map[string]interface{}, bool) {
+	switch m := value.(type) {
+	case map[string]interface{}:
+		return m, true
+	case types.Metadata:
+		return m, true
+	}
+	return nil, false
+}
+
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case float64, float32, int, int32, int64, uint, uint32, uint64:
+		return true
+	}
+	return false
+}
+
+func toFloat(value interface{}) float64 {
+	return reflect.ValueOf(value).Convert(reflect.TypeOf(float64(0))).Float()
+}
+
+func typeName(value interface{}) string {
+	switch {
+	case value == nil:
+		return "null"
+	case isNumber(value):
+		return "number"
+	}
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "list"
+	}
+	if _, ok := asMap(value); ok {
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
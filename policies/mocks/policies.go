@@ -19,6 +19,7 @@ type mockPoliciesRepository struct {
 	dataSetCounter uint64
 	ddb            map[string]policies.Dataset
 	gdb            map[string][]policies.PolicyInDataset
+	vdb            map[string][]policies.PolicyVersion
 }
 
 func (m *mockPoliciesRepository) RetrieveDatasetsByGroupID(ctx context.Context, groupIDs []string, ownerID string) ([]policies.Dataset, error) {
@@ -190,9 +191,39 @@ func NewPoliciesRepository() policies.Repository {
 		pdb: make(map[string]policies.Policy),
 		ddb: make(map[string]policies.Dataset),
 		gdb: make(map[string][]policies.PolicyInDataset),
+		vdb: make(map[string][]policies.PolicyVersion),
 	}
 }
 
+func (m *mockPoliciesRepository) SavePolicyVersion(ctx context.Context, version policies.PolicyVersion) error {
+	for _, v := range m.vdb[version.PolicyID] {
+		if v.Version == version.Version {
+			return nil
+		}
+	}
+	m.vdb[version.PolicyID] = append([]policies.PolicyVersion{version}, m.vdb[version.PolicyID]...)
+	return nil
+}
+
+func (m *mockPoliciesRepository) RetrievePolicyVersions(ctx context.Context, policyID string, ownerID string) ([]policies.PolicyVersion, error) {
+	var versions []policies.PolicyVersion
+	for _, v := range m.vdb[policyID] {
+		if v.MFOwnerID == ownerID {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+func (m *mockPoliciesRepository) RetrievePolicyVersion(ctx context.Context, policyID string, ownerID string, version int32) (policies.PolicyVersion, error) {
+	for _, v := range m.vdb[policyID] {
+		if v.MFOwnerID == ownerID && v.Version == version {
+			return v, nil
+		}
+	}
+	return policies.PolicyVersion{}, policies.ErrNotFound
+}
+
 func (m *mockPoliciesRepository) RetrieveAll(ctx context.Context, owner string, pm policies.PageMetadata) (policies.Page, error) {
 	first := uint64(pm.Offset)
 	last := first + uint64(pm.Limit)
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.28.1
+// 	protoc-gen-go v1.32.0
 // 	protoc        v3.12.4
 // source: policies/pb/policies.proto
 
@@ -75,6 +75,69 @@ func (x *PolicyByIDReq) GetOwnerID() string {
 	return ""
 }
 
+type PolicyVersionReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PolicyID string `protobuf:"bytes,1,opt,name=policyID,proto3" json:"policyID,omitempty"`
+	OwnerID  string `protobuf:"bytes,2,opt,name=ownerID,proto3" json:"ownerID,omitempty"`
+	Version  int32  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *PolicyVersionReq) Reset() {
+	*x = PolicyVersionReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_policies_pb_policies_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PolicyVersionReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyVersionReq) ProtoMessage() {}
+
+func (x *PolicyVersionReq) ProtoReflect() protoreflect.Message {
+	mi := &file_policies_pb_policies_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyVersionReq.ProtoReflect.Descriptor instead.
+func (*PolicyVersionReq) Descriptor() ([]byte, []int) {
+	return file_policies_pb_policies_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PolicyVersionReq) GetPolicyID() string {
+	if x != nil {
+		return x.PolicyID
+	}
+	return ""
+}
+
+func (x *PolicyVersionReq) GetOwnerID() string {
+	if x != nil {
+		return x.OwnerID
+	}
+	return ""
+}
+
+func (x *PolicyVersionReq) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 type DatasetsByGroupsReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -87,7 +150,7 @@ type DatasetsByGroupsReq struct {
 func (x *DatasetsByGroupsReq) Reset() {
 	*x = DatasetsByGroupsReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_policies_pb_policies_proto_msgTypes[1]
+		mi := &file_policies_pb_policies_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -100,7 +163,7 @@ func (x *DatasetsByGroupsReq) String() string {
 func (*DatasetsByGroupsReq) ProtoMessage() {}
 
 func (x *DatasetsByGroupsReq) ProtoReflect() protoreflect.Message {
-	mi := &file_policies_pb_policies_proto_msgTypes[1]
+	mi := &file_policies_pb_policies_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -113,7 +176,7 @@ func (x *DatasetsByGroupsReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DatasetsByGroupsReq.ProtoReflect.Descriptor instead.
 func (*DatasetsByGroupsReq) Descriptor() ([]byte, []int) {
-	return file_policies_pb_policies_proto_rawDescGZIP(), []int{1}
+	return file_policies_pb_policies_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *DatasetsByGroupsReq) GetGroupIDs() []string {
@@ -142,7 +205,7 @@ type PoliciesByGroupsReq struct {
 func (x *PoliciesByGroupsReq) Reset() {
 	*x = PoliciesByGroupsReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_policies_pb_policies_proto_msgTypes[2]
+		mi := &file_policies_pb_policies_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -155,7 +218,7 @@ func (x *PoliciesByGroupsReq) String() string {
 func (*PoliciesByGroupsReq) ProtoMessage() {}
 
 func (x *PoliciesByGroupsReq) ProtoReflect() protoreflect.Message {
-	mi := &file_policies_pb_policies_proto_msgTypes[2]
+	mi := &file_policies_pb_policies_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -168,7 +231,7 @@ func (x *PoliciesByGroupsReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PoliciesByGroupsReq.ProtoReflect.Descriptor instead.
 func (*PoliciesByGroupsReq) Descriptor() ([]byte, []int) {
-	return file_policies_pb_policies_proto_rawDescGZIP(), []int{2}
+	return file_policies_pb_policies_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *PoliciesByGroupsReq) GetGroupIDs() []string {
@@ -197,7 +260,7 @@ type DatasetByIDReq struct {
 func (x *DatasetByIDReq) Reset() {
 	*x = DatasetByIDReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_policies_pb_policies_proto_msgTypes[3]
+		mi := &file_policies_pb_policies_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -210,7 +273,7 @@ func (x *DatasetByIDReq) String() string {
 func (*DatasetByIDReq) ProtoMessage() {}
 
 func (x *DatasetByIDReq) ProtoReflect() protoreflect.Message {
-	mi := &file_policies_pb_policies_proto_msgTypes[3]
+	mi := &file_policies_pb_policies_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -223,7 +286,7 @@ func (x *DatasetByIDReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DatasetByIDReq.ProtoReflect.Descriptor instead.
 func (*DatasetByIDReq) Descriptor() ([]byte, []int) {
-	return file_policies_pb_policies_proto_rawDescGZIP(), []int{3}
+	return file_policies_pb_policies_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *DatasetByIDReq) GetDatasetID() string {
@@ -256,7 +319,7 @@ type PolicyRes struct {
 func (x *PolicyRes) Reset() {
 	*x = PolicyRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_policies_pb_policies_proto_msgTypes[4]
+		mi := &file_policies_pb_policies_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -269,7 +332,7 @@ func (x *PolicyRes) String() string {
 func (*PolicyRes) ProtoMessage() {}
 
 func (x *PolicyRes) ProtoReflect() protoreflect.Message {
-	mi := &file_policies_pb_policies_proto_msgTypes[4]
+	mi := &file_policies_pb_policies_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -282,7 +345,7 @@ func (x *PolicyRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PolicyRes.ProtoReflect.Descriptor instead.
 func (*PolicyRes) Descriptor() ([]byte, []int) {
-	return file_policies_pb_policies_proto_rawDescGZIP(), []int{4}
+	return file_policies_pb_policies_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *PolicyRes) GetId() string {
@@ -345,7 +408,7 @@ type PolicyInDSRes struct {
 func (x *PolicyInDSRes) Reset() {
 	*x = PolicyInDSRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_policies_pb_policies_proto_msgTypes[5]
+		mi := &file_policies_pb_policies_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -358,7 +421,7 @@ func (x *PolicyInDSRes) String() string {
 func (*PolicyInDSRes) ProtoMessage() {}
 
 func (x *PolicyInDSRes) ProtoReflect() protoreflect.Message {
-	mi := &file_policies_pb_policies_proto_msgTypes[5]
+	mi := &file_policies_pb_policies_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -371,7 +434,7 @@ func (x *PolicyInDSRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PolicyInDSRes.ProtoReflect.Descriptor instead.
 func (*PolicyInDSRes) Descriptor() ([]byte, []int) {
-	return file_policies_pb_policies_proto_rawDescGZIP(), []int{5}
+	return file_policies_pb_policies_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *PolicyInDSRes) GetId() string {
@@ -441,7 +504,7 @@ type PolicyInDSListRes struct {
 func (x *PolicyInDSListRes) Reset() {
 	*x = PolicyInDSListRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_policies_pb_policies_proto_msgTypes[6]
+		mi := &file_policies_pb_policies_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -454,7 +517,7 @@ func (x *PolicyInDSListRes) String() string {
 func (*PolicyInDSListRes) ProtoMessage() {}
 
 func (x *PolicyInDSListRes) ProtoReflect() protoreflect.Message {
-	mi := &file_policies_pb_policies_proto_msgTypes[6]
+	mi := &file_policies_pb_policies_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -467,7 +530,7 @@ func (x *PolicyInDSListRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PolicyInDSListRes.ProtoReflect.Descriptor instead.
 func (*PolicyInDSListRes) Descriptor() ([]byte, []int) {
-	return file_policies_pb_policies_proto_rawDescGZIP(), []int{6}
+	return file_policies_pb_policies_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *PolicyInDSListRes) GetPolicies() []*PolicyInDSRes {
@@ -482,16 +545,17 @@ type DatasetRes struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id           string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	AgentGroupId string   `protobuf:"bytes,2,opt,name=agent_group_id,json=agentGroupId,proto3" json:"agent_group_id,omitempty"`
-	PolicyId     string   `protobuf:"bytes,3,opt,name=policy_id,json=policyId,proto3" json:"policy_id,omitempty"`
-	SinkIds      []string `protobuf:"bytes,4,rep,name=sink_ids,json=sinkIds,proto3" json:"sink_ids,omitempty"`
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AgentGroupId string                 `protobuf:"bytes,2,opt,name=agent_group_id,json=agentGroupId,proto3" json:"agent_group_id,omitempty"`
+	PolicyId     string                 `protobuf:"bytes,3,opt,name=policy_id,json=policyId,proto3" json:"policy_id,omitempty"`
+	SinkIds      []string               `protobuf:"bytes,4,rep,name=sink_ids,json=sinkIds,proto3" json:"sink_ids,omitempty"`
+	SinkFilters  map[string]*SinkFilter `protobuf:"bytes,5,rep,name=sink_filters,json=sinkFilters,proto3" json:"sink_filters,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (x *DatasetRes) Reset() {
 	*x = DatasetRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_policies_pb_policies_proto_msgTypes[7]
+		mi := &file_policies_pb_policies_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -504,7 +568,7 @@ func (x *DatasetRes) String() string {
 func (*DatasetRes) ProtoMessage() {}
 
 func (x *DatasetRes) ProtoReflect() protoreflect.Message {
-	mi := &file_policies_pb_policies_proto_msgTypes[7]
+	mi := &file_policies_pb_policies_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -517,7 +581,7 @@ func (x *DatasetRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DatasetRes.ProtoReflect.Descriptor instead.
 func (*DatasetRes) Descriptor() ([]byte, []int) {
-	return file_policies_pb_policies_proto_rawDescGZIP(), []int{7}
+	return file_policies_pb_policies_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *DatasetRes) GetId() string {
@@ -548,6 +612,13 @@ func (x *DatasetRes) GetSinkIds() []string {
 	return nil
 }
 
+func (x *DatasetRes) GetSinkFilters() map[string]*SinkFilter {
+	if x != nil {
+		return x.SinkFilters
+	}
+	return nil
+}
+
 type DatasetsRes struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -559,7 +630,7 @@ type DatasetsRes struct {
 func (x *DatasetsRes) Reset() {
 	*x = DatasetsRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_policies_pb_policies_proto_msgTypes[8]
+		mi := &file_policies_pb_policies_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -572,7 +643,7 @@ func (x *DatasetsRes) String() string {
 func (*DatasetsRes) ProtoMessage() {}
 
 func (x *DatasetsRes) ProtoReflect() protoreflect.Message {
-	mi := &file_policies_pb_policies_proto_msgTypes[8]
+	mi := &file_policies_pb_policies_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -585,7 +656,7 @@ func (x *DatasetsRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DatasetsRes.ProtoReflect.Descriptor instead.
 func (*DatasetsRes) Descriptor() ([]byte, []int) {
-	return file_policies_pb_policies_proto_rawDescGZIP(), []int{8}
+	return file_policies_pb_policies_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *DatasetsRes) GetDatasetList() []*DatasetRes {
@@ -595,6 +666,85 @@ func (x *DatasetsRes) GetDatasetList() []*DatasetRes {
 	return nil
 }
 
+type SinkFilter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IncludeMetrics   []string          `protobuf:"bytes,1,rep,name=include_metrics,json=includeMetrics,proto3" json:"include_metrics,omitempty"`
+	ExcludeMetrics   []string          `protobuf:"bytes,2,rep,name=exclude_metrics,json=excludeMetrics,proto3" json:"exclude_metrics,omitempty"`
+	DropAttributes   []string          `protobuf:"bytes,3,rep,name=drop_attributes,json=dropAttributes,proto3" json:"drop_attributes,omitempty"`
+	RenameAttributes map[string]string `protobuf:"bytes,4,rep,name=rename_attributes,json=renameAttributes,proto3" json:"rename_attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	AddLabels        map[string]string `protobuf:"bytes,5,rep,name=add_labels,json=addLabels,proto3" json:"add_labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *SinkFilter) Reset() {
+	*x = SinkFilter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_policies_pb_policies_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SinkFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SinkFilter) ProtoMessage() {}
+
+func (x *SinkFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_policies_pb_policies_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SinkFilter.ProtoReflect.Descriptor instead.
+func (*SinkFilter) Descriptor() ([]byte, []int) {
+	return file_policies_pb_policies_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SinkFilter) GetIncludeMetrics() []string {
+	if x != nil {
+		return x.IncludeMetrics
+	}
+	return nil
+}
+
+func (x *SinkFilter) GetExcludeMetrics() []string {
+	if x != nil {
+		return x.ExcludeMetrics
+	}
+	return nil
+}
+
+func (x *SinkFilter) GetDropAttributes() []string {
+	if x != nil {
+		return x.DropAttributes
+	}
+	return nil
+}
+
+func (x *SinkFilter) GetRenameAttributes() map[string]string {
+	if x != nil {
+		return x.RenameAttributes
+	}
+	return nil
+}
+
+func (x *SinkFilter) GetAddLabels() map[string]string {
+	if x != nil {
+		return x.AddLabels
+	}
+	return nil
+}
+
 var File_policies_pb_policies_proto protoreflect.FileDescriptor
 
 var file_policies_pb_policies_proto_rawDesc = []byte{
@@ -604,83 +754,131 @@ var file_policies_pb_policies_proto_rawDesc = []byte{
 	0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63,
 	0x79, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63,
 	0x79, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x22, 0x4b, 0x0a,
-	0x13, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x73, 0x42, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70,
-	0x73, 0x52, 0x65, 0x71, 0x12, 0x1a, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x73,
-	0x12, 0x18, 0x0a, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x22, 0x4b, 0x0a, 0x13, 0x50, 0x6f,
-	0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x42, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65,
-	0x71, 0x12, 0x1a, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x73, 0x18, 0x01, 0x20,
-	0x03, 0x28, 0x09, 0x52, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44, 0x73, 0x12, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x22, 0x62, 0x0a,
+	0x10, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x71, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x49, 0x44, 0x12, 0x18, 0x0a,
 	0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
-	0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x22, 0x48, 0x0a, 0x0e, 0x44, 0x61, 0x74, 0x61, 0x73,
-	0x65, 0x74, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x61, 0x74,
-	0x61, 0x73, 0x65, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x61,
-	0x74, 0x61, 0x73, 0x65, 0x74, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72,
-	0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49,
-	0x44, 0x22, 0x8f, 0x01, 0x0a, 0x09, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x73, 0x12,
-	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
-	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x12, 0x18, 0x0a,
-	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07,
-	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x66,
-	0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x72,
-	0x6d, 0x61, 0x74, 0x22, 0xd8, 0x01, 0x0a, 0x0d, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x49, 0x6e,
-	0x44, 0x53, 0x52, 0x65, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61, 0x63,
-	0x6b, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x61, 0x63, 0x6b,
-	0x65, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a,
-	0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74,
-	0x61, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18,
-	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x49, 0x64,
-	0x12, 0x24, 0x0a, 0x0e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f,
-	0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x47,
-	0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74,
-	0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x22, 0x48,
-	0x0a, 0x11, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x49, 0x6e, 0x44, 0x53, 0x4c, 0x69, 0x73, 0x74,
-	0x52, 0x65, 0x73, 0x12, 0x33, 0x0a, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73,
-	0x2e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x49, 0x6e, 0x44, 0x53, 0x52, 0x65, 0x73, 0x52, 0x08,
-	0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x22, 0x7a, 0x0a, 0x0a, 0x44, 0x61, 0x74, 0x61,
-	0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x24, 0x0a, 0x0e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f,
-	0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
-	0x61, 0x67, 0x65, 0x6e, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09,
-	0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x69, 0x6e,
-	0x6b, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x73, 0x69, 0x6e,
-	0x6b, 0x49, 0x64, 0x73, 0x22, 0x45, 0x0a, 0x0b, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x73,
-	0x52, 0x65, 0x73, 0x12, 0x36, 0x0a, 0x0b, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x4c, 0x69,
-	0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x63,
-	0x69, 0x65, 0x73, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x52, 0x0b,
-	0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x32, 0xc4, 0x02, 0x0a, 0x0d,
-	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x40, 0x0a,
-	0x0e, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12,
-	0x17, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x2e, 0x50, 0x6f, 0x6c, 0x69, 0x63,
-	0x79, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x63,
-	0x69, 0x65, 0x73, 0x2e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x73, 0x22, 0x00, 0x12,
-	0x58, 0x0a, 0x18, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63,
-	0x69, 0x65, 0x73, 0x42, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x1d, 0x2e, 0x70, 0x6f,
-	0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x2e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x42,
-	0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x1b, 0x2e, 0x70, 0x6f, 0x6c,
-	0x69, 0x63, 0x69, 0x65, 0x73, 0x2e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x49, 0x6e, 0x44, 0x53,
-	0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x0f, 0x52, 0x65, 0x74,
-	0x72, 0x69, 0x65, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12, 0x18, 0x2e, 0x70,
-	0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x42,
-	0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x1a, 0x14, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65,
-	0x73, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x22, 0x00, 0x12, 0x52,
-	0x0a, 0x18, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65,
-	0x74, 0x73, 0x42, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x1d, 0x2e, 0x70, 0x6f, 0x6c,
-	0x69, 0x63, 0x69, 0x65, 0x73, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x73, 0x42, 0x79,
-	0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x15, 0x2e, 0x70, 0x6f, 0x6c, 0x69,
-	0x63, 0x69, 0x65, 0x73, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x73, 0x52, 0x65, 0x73,
-	0x22, 0x00, 0x42, 0x0d, 0x5a, 0x0b, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x2f, 0x70,
-	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x22, 0x4b, 0x0a, 0x13, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x73, 0x42, 0x79, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65, 0x71, 0x12, 0x1a, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x49, 0x44, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x49, 0x44, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x22, 0x4b,
+	0x0a, 0x13, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x42, 0x79, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x73, 0x52, 0x65, 0x71, 0x12, 0x1a, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x44,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x22, 0x48, 0x0a, 0x0e, 0x44,
+	0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x12, 0x1c, 0x0a,
+	0x09, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x6f,
+	0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x77,
+	0x6e, 0x65, 0x72, 0x49, 0x44, 0x22, 0x8f, 0x01, 0x0a, 0x09, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x52, 0x65, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x65,
+	0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e,
+	0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12,
+	0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x22, 0xd8, 0x01, 0x0a, 0x0d, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x49, 0x6e, 0x44, 0x53, 0x52, 0x65, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74,
+	0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x61, 0x74, 0x61, 0x73,
+	0x65, 0x74, 0x49, 0x64, 0x12, 0x24, 0x0a, 0x0e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f,
+	0x72, 0x6d, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d,
+	0x61, 0x74, 0x22, 0x48, 0x0a, 0x11, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x49, 0x6e, 0x44, 0x53,
+	0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x12, 0x33, 0x0a, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63,
+	0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x6f, 0x6c, 0x69,
+	0x63, 0x69, 0x65, 0x73, 0x2e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x49, 0x6e, 0x44, 0x53, 0x52,
+	0x65, 0x73, 0x52, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x22, 0x9a, 0x02, 0x0a,
+	0x0a, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x24, 0x0a, 0x0e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49,
+	0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x49, 0x64, 0x12, 0x19,
+	0x0a, 0x08, 0x73, 0x69, 0x6e, 0x6b, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x07, 0x73, 0x69, 0x6e, 0x6b, 0x49, 0x64, 0x73, 0x12, 0x48, 0x0a, 0x0c, 0x73, 0x69, 0x6e,
+	0x6b, 0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x25, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x73,
+	0x65, 0x74, 0x52, 0x65, 0x73, 0x2e, 0x53, 0x69, 0x6e, 0x6b, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x73, 0x69, 0x6e, 0x6b, 0x46, 0x69, 0x6c, 0x74,
+	0x65, 0x72, 0x73, 0x1a, 0x54, 0x0a, 0x10, 0x53, 0x69, 0x6e, 0x6b, 0x46, 0x69, 0x6c, 0x74, 0x65,
+	0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x63,
+	0x69, 0x65, 0x73, 0x2e, 0x53, 0x69, 0x6e, 0x6b, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x45, 0x0a, 0x0b, 0x44, 0x61, 0x74,
+	0x61, 0x73, 0x65, 0x74, 0x73, 0x52, 0x65, 0x73, 0x12, 0x36, 0x0a, 0x0b, 0x64, 0x61, 0x74, 0x61,
+	0x73, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74,
+	0x52, 0x65, 0x73, 0x52, 0x0b, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74,
+	0x22, 0xa7, 0x03, 0x0a, 0x0a, 0x53, 0x69, 0x6e, 0x6b, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12,
+	0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x6d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64,
+	0x65, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x78, 0x63, 0x6c,
+	0x75, 0x64, 0x65, 0x5f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0e, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63,
+	0x73, 0x12, 0x27, 0x0a, 0x0f, 0x64, 0x72, 0x6f, 0x70, 0x5f, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62,
+	0x75, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x64, 0x72, 0x6f, 0x70,
+	0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12, 0x57, 0x0a, 0x11, 0x72, 0x65,
+	0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73,
+	0x2e, 0x53, 0x69, 0x6e, 0x6b, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x6e, 0x61,
+	0x6d, 0x65, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x10, 0x72, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75,
+	0x74, 0x65, 0x73, 0x12, 0x42, 0x0a, 0x0a, 0x61, 0x64, 0x64, 0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69,
+	0x65, 0x73, 0x2e, 0x53, 0x69, 0x6e, 0x6b, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x2e, 0x41, 0x64,
+	0x64, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x61, 0x64,
+	0x64, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x1a, 0x43, 0x0a, 0x15, 0x52, 0x65, 0x6e, 0x61, 0x6d,
+	0x65, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3c, 0x0a, 0x0e,
+	0x41, 0x64, 0x64, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x32, 0x90, 0x03, 0x0a, 0x0d, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x40, 0x0a, 0x0e,
+	0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x17,
+	0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x2e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69,
+	0x65, 0x73, 0x2e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x73, 0x22, 0x00, 0x12, 0x58,
+	0x0a, 0x18, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x69,
+	0x65, 0x73, 0x42, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x1d, 0x2e, 0x70, 0x6f, 0x6c,
+	0x69, 0x63, 0x69, 0x65, 0x73, 0x2e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x42, 0x79,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x1b, 0x2e, 0x70, 0x6f, 0x6c, 0x69,
+	0x63, 0x69, 0x65, 0x73, 0x2e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x49, 0x6e, 0x44, 0x53, 0x4c,
+	0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x0f, 0x52, 0x65, 0x74, 0x72,
+	0x69, 0x65, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12, 0x18, 0x2e, 0x70, 0x6f,
+	0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x42, 0x79,
+	0x49, 0x44, 0x52, 0x65, 0x71, 0x1a, 0x14, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73,
+	0x2e, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x22, 0x00, 0x12, 0x52, 0x0a,
+	0x18, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74,
+	0x73, 0x42, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x1d, 0x2e, 0x70, 0x6f, 0x6c, 0x69,
+	0x63, 0x69, 0x65, 0x73, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x73, 0x42, 0x79, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x15, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x63,
+	0x69, 0x65, 0x73, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x73, 0x52, 0x65, 0x73, 0x22,
+	0x00, 0x12, 0x4a, 0x0a, 0x15, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x50, 0x6f, 0x6c,
+	0x69, 0x63, 0x79, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x2e, 0x70, 0x6f, 0x6c,
+	0x69, 0x63, 0x69, 0x65, 0x73, 0x2e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65,
+	0x73, 0x2e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x73, 0x22, 0x00, 0x42, 0x0d, 0x5a,
+	0x0b, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -695,34 +893,45 @@ func file_policies_pb_policies_proto_rawDescGZIP() []byte {
 	return file_policies_pb_policies_proto_rawDescData
 }
 
-var file_policies_pb_policies_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_policies_pb_policies_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_policies_pb_policies_proto_goTypes = []interface{}{
 	(*PolicyByIDReq)(nil),       // 0: policies.PolicyByIDReq
-	(*DatasetsByGroupsReq)(nil), // 1: policies.DatasetsByGroupsReq
-	(*PoliciesByGroupsReq)(nil), // 2: policies.PoliciesByGroupsReq
-	(*DatasetByIDReq)(nil),      // 3: policies.DatasetByIDReq
-	(*PolicyRes)(nil),           // 4: policies.PolicyRes
-	(*PolicyInDSRes)(nil),       // 5: policies.PolicyInDSRes
-	(*PolicyInDSListRes)(nil),   // 6: policies.PolicyInDSListRes
-	(*DatasetRes)(nil),          // 7: policies.DatasetRes
-	(*DatasetsRes)(nil),         // 8: policies.DatasetsRes
+	(*PolicyVersionReq)(nil),    // 1: policies.PolicyVersionReq
+	(*DatasetsByGroupsReq)(nil), // 2: policies.DatasetsByGroupsReq
+	(*PoliciesByGroupsReq)(nil), // 3: policies.PoliciesByGroupsReq
+	(*DatasetByIDReq)(nil),      // 4: policies.DatasetByIDReq
+	(*PolicyRes)(nil),           // 5: policies.PolicyRes
+	(*PolicyInDSRes)(nil),       // 6: policies.PolicyInDSRes
+	(*PolicyInDSListRes)(nil),   // 7: policies.PolicyInDSListRes
+	(*DatasetRes)(nil),          // 8: policies.DatasetRes
+	(*DatasetsRes)(nil),         // 9: policies.DatasetsRes
+	(*SinkFilter)(nil),          // 10: policies.SinkFilter
+	nil,                         // 11: policies.DatasetRes.SinkFiltersEntry
+	nil,                         // 12: policies.SinkFilter.RenameAttributesEntry
+	nil,                         // 13: policies.SinkFilter.AddLabelsEntry
 }
 var file_policies_pb_policies_proto_depIdxs = []int32{
-	5, // 0: policies.PolicyInDSListRes.policies:type_name -> policies.PolicyInDSRes
-	7, // 1: policies.DatasetsRes.datasetList:type_name -> policies.DatasetRes
-	0, // 2: policies.PolicyService.RetrievePolicy:input_type -> policies.PolicyByIDReq
-	2, // 3: policies.PolicyService.RetrievePoliciesByGroups:input_type -> policies.PoliciesByGroupsReq
-	3, // 4: policies.PolicyService.RetrieveDataset:input_type -> policies.DatasetByIDReq
-	1, // 5: policies.PolicyService.RetrieveDatasetsByGroups:input_type -> policies.DatasetsByGroupsReq
-	4, // 6: policies.PolicyService.RetrievePolicy:output_type -> policies.PolicyRes
-	6, // 7: policies.PolicyService.RetrievePoliciesByGroups:output_type -> policies.PolicyInDSListRes
-	7, // 8: policies.PolicyService.RetrieveDataset:output_type -> policies.DatasetRes
-	8, // 9: policies.PolicyService.RetrieveDatasetsByGroups:output_type -> policies.DatasetsRes
-	6, // [6:10] is the sub-list for method output_type
-	2, // [2:6] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	6,  // 0: policies.PolicyInDSListRes.policies:type_name -> policies.PolicyInDSRes
+	11, // 1: policies.DatasetRes.sink_filters:type_name -> policies.DatasetRes.SinkFiltersEntry
+	8,  // 2: policies.DatasetsRes.datasetList:type_name -> policies.DatasetRes
+	12, // 3: policies.SinkFilter.rename_attributes:type_name -> policies.SinkFilter.RenameAttributesEntry
+	13, // 4: policies.SinkFilter.add_labels:type_name -> policies.SinkFilter.AddLabelsEntry
+	10, // 5: policies.DatasetRes.SinkFiltersEntry.value:type_name -> policies.SinkFilter
+	0,  // 6: policies.PolicyService.RetrievePolicy:input_type -> policies.PolicyByIDReq
+	3,  // 7: policies.PolicyService.RetrievePoliciesByGroups:input_type -> policies.PoliciesByGroupsReq
+	4,  // 8: policies.PolicyService.RetrieveDataset:input_type -> policies.DatasetByIDReq
+	2,  // 9: policies.PolicyService.RetrieveDatasetsByGroups:input_type -> policies.DatasetsByGroupsReq
+	1,  // 10: policies.PolicyService.RetrievePolicyVersion:input_type -> policies.PolicyVersionReq
+	5,  // 11: policies.PolicyService.RetrievePolicy:output_type -> policies.PolicyRes
+	7,  // 12: policies.PolicyService.RetrievePoliciesByGroups:output_type -> policies.PolicyInDSListRes
+	8,  // 13: policies.PolicyService.RetrieveDataset:output_type -> policies.DatasetRes
+	9,  // 14: policies.PolicyService.RetrieveDatasetsByGroups:output_type -> policies.DatasetsRes
+	5,  // 15: policies.PolicyService.RetrievePolicyVersion:output_type -> policies.PolicyRes
+	11, // [11:16] is the sub-list for method output_type
+	6,  // [6:11] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_policies_pb_policies_proto_init() }
@@ -744,7 +953,7 @@ func file_policies_pb_policies_proto_init() {
 			}
 		}
 		file_policies_pb_policies_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DatasetsByGroupsReq); i {
+			switch v := v.(*PolicyVersionReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -756,7 +965,7 @@ func file_policies_pb_policies_proto_init() {
 			}
 		}
 		file_policies_pb_policies_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PoliciesByGroupsReq); i {
+			switch v := v.(*DatasetsByGroupsReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -768,7 +977,7 @@ func file_policies_pb_policies_proto_init() {
 			}
 		}
 		file_policies_pb_policies_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DatasetByIDReq); i {
+			switch v := v.(*PoliciesByGroupsReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -780,7 +989,7 @@ func file_policies_pb_policies_proto_init() {
 			}
 		}
 		file_policies_pb_policies_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PolicyRes); i {
+			switch v := v.(*DatasetByIDReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -792,7 +1001,7 @@ func file_policies_pb_policies_proto_init() {
 			}
 		}
 		file_policies_pb_policies_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PolicyInDSRes); i {
+			switch v := v.(*PolicyRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -804,7 +1013,7 @@ func file_policies_pb_policies_proto_init() {
 			}
 		}
 		file_policies_pb_policies_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PolicyInDSListRes); i {
+			switch v := v.(*PolicyInDSRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -816,7 +1025,7 @@ func file_policies_pb_policies_proto_init() {
 			}
 		}
 		file_policies_pb_policies_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DatasetRes); i {
+			switch v := v.(*PolicyInDSListRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -828,6 +1037,18 @@ func file_policies_pb_policies_proto_init() {
 			}
 		}
 		file_policies_pb_policies_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DatasetRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_policies_pb_policies_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DatasetsRes); i {
 			case 0:
 				return &v.state
@@ -839,6 +1060,18 @@ func file_policies_pb_policies_proto_init() {
 				return nil
 			}
 		}
+		file_policies_pb_policies_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SinkFilter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -846,7 +1079,7 @@ func file_policies_pb_policies_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_policies_pb_policies_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -26,6 +26,7 @@ type PolicyServiceClient interface {
 	RetrievePoliciesByGroups(ctx context.Context, in *PoliciesByGroupsReq, opts ...grpc.CallOption) (*PolicyInDSListRes, error)
 	RetrieveDataset(ctx context.Context, in *DatasetByIDReq, opts ...grpc.CallOption) (*DatasetRes, error)
 	RetrieveDatasetsByGroups(ctx context.Context, in *DatasetsByGroupsReq, opts ...grpc.CallOption) (*DatasetsRes, error)
+	RetrievePolicyVersion(ctx context.Context, in *PolicyVersionReq, opts ...grpc.CallOption) (*PolicyRes, error)
 }
 
 type policyServiceClient struct {
@@ -72,6 +73,15 @@ func (c *policyServiceClient) RetrieveDatasetsByGroups(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *policyServiceClient) RetrievePolicyVersion(ctx context.Context, in *PolicyVersionReq, opts ...grpc.CallOption) (*PolicyRes, error) {
+	out := new(PolicyRes)
+	err := c.cc.Invoke(ctx, "/policies.PolicyService/RetrievePolicyVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PolicyServiceServer is the server API for PolicyService service.
 // All implementations must embed UnimplementedPolicyServiceServer
 // for forward compatibility
@@ -80,6 +90,7 @@ type PolicyServiceServer interface {
 	RetrievePoliciesByGroups(context.Context, *PoliciesByGroupsReq) (*PolicyInDSListRes, error)
 	RetrieveDataset(context.Context, *DatasetByIDReq) (*DatasetRes, error)
 	RetrieveDatasetsByGroups(context.Context, *DatasetsByGroupsReq) (*DatasetsRes, error)
+	RetrievePolicyVersion(context.Context, *PolicyVersionReq) (*PolicyRes, error)
 	mustEmbedUnimplementedPolicyServiceServer()
 }
 
@@ -99,6 +110,9 @@ func (UnimplementedPolicyServiceServer) RetrieveDataset(context.Context, *Datase
 func (UnimplementedPolicyServiceServer) RetrieveDatasetsByGroups(context.Context, *DatasetsByGroupsReq) (*DatasetsRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RetrieveDatasetsByGroups not implemented")
 }
+func (UnimplementedPolicyServiceServer) RetrievePolicyVersion(context.Context, *PolicyVersionReq) (*PolicyRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetrievePolicyVersion not implemented")
+}
 func (UnimplementedPolicyServiceServer) mustEmbedUnimplementedPolicyServiceServer() {}
 
 // UnsafePolicyServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -184,6 +198,24 @@ func _PolicyService_RetrieveDatasetsByGroups_Handler(srv interface{}, ctx contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PolicyService_RetrievePolicyVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PolicyVersionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolicyServiceServer).RetrievePolicyVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/policies.PolicyService/RetrievePolicyVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolicyServiceServer).RetrievePolicyVersion(ctx, req.(*PolicyVersionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PolicyService_ServiceDesc is the grpc.ServiceDesc for PolicyService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -207,6 +239,10 @@ var PolicyService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RetrieveDatasetsByGroups",
 			Handler:    _PolicyService_RetrieveDatasetsByGroups_Handler,
 		},
+		{
+			MethodName: "RetrievePolicyVersion",
+			Handler:    _PolicyService_RetrievePolicyVersion_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "policies/pb/policies.proto",
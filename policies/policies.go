@@ -25,6 +25,24 @@ type Policy struct {
 	Format        string
 	Created       time.Time
 	LastModified  time.Time
+	// Rollout is only set on edits that ask for a staged rollout, it is not persisted
+	Rollout *RolloutStrategy
+}
+
+const (
+	// RolloutHalt leaves the canary agents on the new version when the rollout fails
+	RolloutHalt = "halt"
+	// RolloutRollback restores the previous version on every agent when the rollout fails
+	RolloutRollback = "rollback"
+)
+
+// RolloutStrategy stages a policy edit: the new version first goes to a share of the agents of the groups
+// running the policy (the canaries) and only reaches the other agents once every canary runs it
+type RolloutStrategy struct {
+	CanaryPercent int
+	CanaryCount   int
+	OnFailure     string
+	Timeout       time.Duration
 }
 
 type Dataset struct {
@@ -38,6 +56,8 @@ type Dataset struct {
 	Created      time.Time
 	Tags         types.Tags
 	SinkIDs      *[]string
+	// SinkFilters holds the metric filter of the sinks, by sink ID, the sinks without one get every metric
+	SinkFilters map[string]SinkFilter
 }
 
 type PolicyInDataset struct {
@@ -46,6 +66,35 @@ type PolicyInDataset struct {
 	AgentGroupID string
 }
 
+// PolicyVersion is a snapshot of a Policy as it was at a given version
+type PolicyVersion struct {
+	PolicyID    string
+	MFOwnerID   string
+	Version     int32
+	Name        types.Identifier
+	Description *string
+	OrbTags     types.Tags
+	Policy      types.Metadata
+	PolicyData  string
+	Format      string
+	Created     time.Time
+}
+
+// PolicyChange is a single field difference between two policy versions
+type PolicyChange struct {
+	Path      string
+	Operation string
+	From      interface{}
+	To        interface{}
+}
+
+type PolicyDiff struct {
+	PolicyID    string
+	FromVersion int32
+	ToVersion   int32
+	Changes     []PolicyChange
+}
+
 type Page struct {
 	PageMetadata
 	Policies []Policy
@@ -124,6 +173,27 @@ type Service interface {
 
 	// ListDatasetsByGroupIDInternal gRPC version of retrieving list of datasets belonging to specified agent group with no token
 	ListDatasetsByGroupIDInternal(ctx context.Context, groupIDs []string, ownerID string) ([]Dataset, error)
+
+	// ListPolicyVersions retrieves the version history of a policy, newest first
+	ListPolicyVersions(ctx context.Context, token string, policyID string) ([]PolicyVersion, error)
+
+	// ViewPolicyVersion retrieves a policy as it was at the given version
+	ViewPolicyVersion(ctx context.Context, token string, policyID string, version int32) (PolicyVersion, error)
+
+	// DiffPolicyVersions compares two versions of a policy
+	DiffPolicyVersions(ctx context.Context, token string, policyID string, from int32, to int32) (PolicyDiff, error)
+
+	// RollbackPolicy restores the policy contents of the given version as a new version
+	RollbackPolicy(ctx context.Context, token string, policyID string, version int32) (Policy, error)
+
+	// ViewPolicyVersionInternal gRPC version of retrieving a policy as it was at the given version with no token
+	ViewPolicyVersionInternal(ctx context.Context, policyID string, ownerID string, version int32) (PolicyVersion, error)
+
+	// RollbackPolicyInternal restores the policy contents of the given version with no token, used by failed rollouts
+	RollbackPolicyInternal(ctx context.Context, ownerID string, policyID string, version int32) (Policy, error)
+
+	// ListDatasetsByPolicyOwnerInternal retrieves the Datasets running a policy with the provided ownerID instead of a token
+	ListDatasetsByPolicyOwnerInternal(ctx context.Context, policyID string, ownerID string) ([]Dataset, error)
 }
 
 type Repository interface {
@@ -188,4 +258,13 @@ type Repository interface {
 
 	// RetrieveDatasetsByGroupID Retrieve dataset list by group id
 	RetrieveDatasetsByGroupID(ctx context.Context, groupIDs []string, ownerID string) ([]Dataset, error)
+
+	// SavePolicyVersion persists a policy version snapshot, an already saved version is left untouched
+	SavePolicyVersion(ctx context.Context, version PolicyVersion) error
+
+	// RetrievePolicyVersions retrieves all the saved versions of a policy, newest first
+	RetrievePolicyVersions(ctx context.Context, policyID string, ownerID string) ([]PolicyVersion, error)
+
+	// RetrievePolicyVersion retrieves a single saved version of a policy
+	RetrievePolicyVersion(ctx context.Context, policyID string, ownerID string, version int32) (PolicyVersion, error)
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/orb-community/orb/pkg/types"
 	"github.com/orb-community/orb/policies/backend"
 	sinkpb "github.com/orb-community/orb/sinks/pb"
+	"go.uber.org/zap"
 )
 
 var (
@@ -72,6 +73,10 @@ func (s policiesService) AddDataset(ctx context.Context, token string, d Dataset
 
 	d.MFOwnerID = mfOwnerID
 
+	if err := validateSinkFilters(datasetSinkIDs(d), d.SinkFilters); err != nil {
+		return Dataset{}, err
+	}
+
 	id, err := s.repo.SaveDataset(ctx, d)
 	if err != nil {
 		return Dataset{}, errors.Wrap(ErrCreateDataset, err)
@@ -99,7 +104,7 @@ func (s policiesService) AddPolicy(ctx context.Context, token string, p Policy)
 		return Policy{}, err
 	}
 
-	err = validatePolicyBackend(&p)
+	err = s.validatePolicyBackend(ctx, mfOwnerID, &p)
 	if err != nil {
 		return Policy{}, err
 	}
@@ -138,6 +143,11 @@ func (s policiesService) EditPolicy(ctx context.Context, token string, pol Polic
 	if err != nil {
 		return Policy{}, err
 	}
+
+	return s.editPolicy(ctx, ownerID, currentPol, pol)
+}
+
+func (s policiesService) editPolicy(ctx context.Context, ownerID string, currentPol Policy, pol Policy) (Policy, error) {
 	pol.Backend = currentPol.Backend
 	pol.MFOwnerID = ownerID
 	pol.Version = currentPol.Version
@@ -149,7 +159,7 @@ func (s policiesService) EditPolicy(ctx context.Context, token string, pol Polic
 		pol.Format = currentPol.Format
 	}
 
-	err = validatePolicyBackend(&pol)
+	err := s.validatePolicyBackend(ctx, ownerID, &pol)
 	if err != nil {
 		return Policy{}, err
 	}
@@ -167,6 +177,12 @@ func (s policiesService) EditPolicy(ctx context.Context, token string, pol Polic
 		pol.OrbTags = currentPol.OrbTags
 	}
 
+	// Policies created before the version history existed have no snapshot of their current version yet
+	err = s.repo.SavePolicyVersion(ctx, toPolicyVersion(currentPol))
+	if err != nil {
+		return Policy{}, errors.Wrap(ErrUpdateEntity, err)
+	}
+
 	pol.Version++
 	err = s.repo.UpdatePolicy(ctx, ownerID, pol)
 	if err != nil {
@@ -179,6 +195,12 @@ func (s policiesService) EditPolicy(ctx context.Context, token string, pol Polic
 		return Policy{}, err
 	}
 
+	// a missing snapshot of the new version is saved on the next edit
+	if err := s.repo.SavePolicyVersion(ctx, toPolicyVersion(res)); err != nil {
+		s.logger.Warn("failed to save policy version", zap.String("policy_id", res.ID), zap.Int32("version", res.Version), zap.Error(err))
+	}
+
+	res.Rollout = pol.Rollout
 	return res, nil
 }
 
@@ -195,6 +217,14 @@ func (s policiesService) ListDatasetsByPolicyIDInternal(ctx context.Context, pol
 	return res, nil
 }
 
+func (s policiesService) ListDatasetsByPolicyOwnerInternal(ctx context.Context, policyID string, ownerID string) ([]Dataset, error) {
+	res, err := s.repo.RetrieveDatasetsByPolicyID(ctx, policyID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func (s policiesService) RemovePolicy(ctx context.Context, token string, policyID string) error {
 	ownerID, err := s.identify(token)
 	if err != nil {
@@ -243,7 +273,7 @@ func (s policiesService) ViewDatasetByIDInternal(ctx context.Context, ownerID st
 	}
 	return res, nil
 }
-func validatePolicyBackend(p *Policy) (err error) {
+func (s policiesService) validatePolicyBackend(ctx context.Context, ownerID string, p *Policy) (err error) {
 	if !backend.HaveBackend(p.Backend) {
 		return errors.Wrap(ErrValidatePolicy, errors.New(fmt.Sprintf("unsupported backend: '%s'", p.Backend)))
 	}
@@ -264,13 +294,34 @@ func validatePolicyBackend(p *Policy) (err error) {
 		p.Format = "json"
 	}
 
-	err = backend.GetBackend(p.Backend).Validate(p.Policy)
+	be := backend.GetBackend(p.Backend)
+	if cv, ok := be.(backend.ComponentsValidator); ok {
+		err = cv.ValidateComponents(p.Policy, s.backendComponents(ctx, ownerID, p.Backend))
+	} else {
+		err = be.Validate(p.Policy)
+	}
 	if err != nil {
-		return errors.Wrap(ErrCreatePolicy, err)
+		return errors.Wrap(ErrValidatePolicy, err)
 	}
 	return nil
 }
 
+// backendComponents retrieves the components the owner's agents reported for the backend, validation
+// falls back to the policy structure alone when fleet can not be reached
+func (s policiesService) backendComponents(ctx context.Context, ownerID string, backendName string) map[string][]string {
+	res, err := s.fleetGrpcClient.RetrieveBackendComponents(ctx, &pb.BackendComponentsReq{OwnerID: ownerID, Backend: backendName})
+	if err != nil {
+		s.logger.Warn("failed to retrieve backend components, skipping components validation",
+			zap.String("backend", backendName), zap.Error(err))
+		return nil
+	}
+	components := make(map[string][]string, len(res.GetComponents()))
+	for kind, list := range res.GetComponents() {
+		components[kind] = list.GetNames()
+	}
+	return components
+}
+
 func (s policiesService) ValidatePolicy(ctx context.Context, token string, p Policy) (Policy, error) {
 
 	mfOwnerID, err := s.identify(token)
@@ -278,7 +329,7 @@ func (s policiesService) ValidatePolicy(ctx context.Context, token string, p Pol
 		return Policy{}, err
 	}
 
-	err = validatePolicyBackend(&p)
+	err = s.validatePolicyBackend(ctx, mfOwnerID, &p)
 	if err != nil {
 		return p, errors.Wrap(ErrCreatePolicy, err)
 	}
@@ -312,11 +363,20 @@ func (s policiesService) EditDataset(ctx context.Context, token string, ds Datas
 		ds.SinkIDs = currentDataset.SinkIDs
 	}
 
+	if ds.SinkFilters == nil {
+		ds.SinkFilters = keepSinkFilters(datasetSinkIDs(ds), currentDataset.SinkFilters)
+	}
+
 	err = s.validateDatasetSink(ctx, ds.MFOwnerID, *ds.SinkIDs)
 	if err != nil {
 		return Dataset{}, err
 	}
 
+	err = validateSinkFilters(datasetSinkIDs(ds), ds.SinkFilters)
+	if err != nil {
+		return Dataset{}, err
+	}
+
 	err = s.repo.UpdateDataset(ctx, mfOwnerID, ds)
 	if err != nil {
 		return Dataset{}, err
@@ -366,6 +426,11 @@ func (s policiesService) ValidateDataset(ctx context.Context, token string, d Da
 		return Dataset{}, err
 	}
 
+	err = validateSinkFilters(*d.SinkIDs, d.SinkFilters)
+	if err != nil {
+		return Dataset{}, err
+	}
+
 	err = s.validateDatasetPolicy(ctx, d.MFOwnerID, d.PolicyID)
 	if err != nil {
 		return Dataset{}, err
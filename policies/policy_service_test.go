@@ -47,7 +47,11 @@ func newService(auth mainflux.AuthServiceClient) policies.Service {
 	SinkServiceClient := sinkmocks.NewClient()
 	logger := zap.NewNop()
 
-	return policies.New(logger, auth, policyRepo, fleetGrpcClient, SinkServiceClient)
+	svc, err := policies.New(logger, auth, policyRepo, fleetGrpcClient, SinkServiceClient)
+	if err != nil {
+		panic(err)
+	}
+	return svc
 }
 
 func TestRetrievePolicyByID(t *testing.T) {
@@ -681,6 +685,93 @@ func TestEditDataset(t *testing.T) {
 	}
 }
 
+func TestDatasetSinkFilters(t *testing.T) {
+	users := flmocks.NewAuthService(map[string]string{token: email})
+	svc := newService(users)
+
+	policy := createPolicy(t, svc, "policy")
+
+	groupID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("Unexpected error: %s", err))
+
+	prometheusID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("Unexpected error: %s", err))
+
+	saasID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("Unexpected error: %s", err))
+
+	validName, err := types.NewIdentifier("filtered")
+	require.Nil(t, err, fmt.Sprintf("Unexpected error: %s", err))
+
+	trimmed := policies.SinkFilter{
+		IncludeMetrics:   []string{"dns_*"},
+		ExcludeMetrics:   []string{"dns_*_histogram"},
+		DropAttributes:   []string{"instance"},
+		RenameAttributes: map[string]string{"agent": "host"},
+		AddLabels:        map[string]string{"tier": "saas"},
+	}
+
+	cases := map[string]struct {
+		filters map[string]policies.SinkFilter
+		err     error
+	}{
+		"create a dataset with a filter on one of its sinks": {
+			filters: map[string]policies.SinkFilter{saasID.String(): trimmed},
+			err:     nil,
+		},
+		"create a dataset with a filter on a sink it does not send to": {
+			filters: map[string]policies.SinkFilter{wrongID: trimmed},
+			err:     policies.ErrInvalidSinkFilter,
+		},
+		"create a dataset with a malformed metric glob": {
+			filters: map[string]policies.SinkFilter{saasID.String(): {IncludeMetrics: []string{"dns_["}}},
+			err:     policies.ErrInvalidSinkFilter,
+		},
+		"create a dataset renaming an attribute to an empty name": {
+			filters: map[string]policies.SinkFilter{saasID.String(): {RenameAttributes: map[string]string{"agent": ""}}},
+			err:     policies.ErrInvalidSinkFilter,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			sinkIDs := []string{prometheusID.String(), saasID.String()}
+			_, err := svc.AddDataset(context.Background(), token, policies.Dataset{
+				Name:         validName,
+				AgentGroupID: groupID.String(),
+				PolicyID:     policy.ID,
+				SinkIDs:      &sinkIDs,
+				SinkFilters:  tc.filters,
+			})
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s got %s", desc, tc.err, err))
+		})
+	}
+
+	t.Run("edit a dataset keeps the filters of the sinks it still sends to", func(t *testing.T) {
+		keptName, err := types.NewIdentifier("kept")
+		require.Nil(t, err, fmt.Sprintf("Unexpected error: %s", err))
+
+		sinkIDs := []string{prometheusID.String(), saasID.String()}
+		dataset, err := svc.AddDataset(context.Background(), token, policies.Dataset{
+			Name:         keptName,
+			AgentGroupID: groupID.String(),
+			PolicyID:     policy.ID,
+			SinkIDs:      &sinkIDs,
+			SinkFilters:  map[string]policies.SinkFilter{saasID.String(): trimmed},
+		})
+		require.Nil(t, err, fmt.Sprintf("Unexpected error: %s", err))
+
+		res, err := svc.EditDataset(context.Background(), token, policies.Dataset{ID: dataset.ID, Tags: types.Tags{"env": "prod"}})
+		require.Nil(t, err, fmt.Sprintf("Unexpected error: %s", err))
+		assert.Equal(t, map[string]policies.SinkFilter{saasID.String(): trimmed}, res.SinkFilters, "expected the filters to be kept")
+
+		prometheusOnly := []string{prometheusID.String()}
+		res, err = svc.EditDataset(context.Background(), token, policies.Dataset{ID: dataset.ID, SinkIDs: &prometheusOnly})
+		require.Nil(t, err, fmt.Sprintf("Unexpected error: %s", err))
+		assert.Empty(t, res.SinkFilters, "expected the filter of the removed sink to be dropped")
+	})
+}
+
 func TestRemoveDataset(t *testing.T) {
 	users := flmocks.NewAuthService(map[string]string{token: email})
 	svc := newService(users)
@@ -1697,6 +1788,86 @@ func TestDuplicatePolicy(t *testing.T) {
 	}
 }
 
+func TestPolicyVersions(t *testing.T) {
+	users := flmocks.NewAuthService(map[string]string{token: email})
+	svc := newService(users)
+
+	policy := createPolicy(t, svc, "policy")
+
+	newDescription := "dns only policy"
+	edited, err := svc.EditPolicy(context.Background(), token, policies.Policy{
+		ID:          policy.ID,
+		Description: &newDescription,
+		Format:      format,
+		PolicyData: `handlers:
+  modules:
+    default_dns:
+      type: dns
+input:
+  input_type: pcap
+  tap: default_pcap
+kind: collection`,
+	})
+	require.Nil(t, err, fmt.Sprintf("Unexpected error: %s", err))
+	require.Equal(t, int32(1), edited.Version)
+
+	versions, err := svc.ListPolicyVersions(context.Background(), token, policy.ID)
+	require.Nil(t, err, fmt.Sprintf("Unexpected error: %s", err))
+	require.Len(t, versions, 2)
+	assert.Equal(t, int32(1), versions[0].Version)
+	assert.Equal(t, int32(0), versions[1].Version)
+
+	original, err := svc.ViewPolicyVersion(context.Background(), token, policy.ID, 0)
+	require.Nil(t, err, fmt.Sprintf("Unexpected error: %s", err))
+	assert.Equal(t, policy_data, original.PolicyData)
+
+	_, err = svc.ViewPolicyVersion(context.Background(), token, policy.ID, 5)
+	assert.True(t, errors.Contains(err, errors.ErrNotFound), fmt.Sprintf("expected %s got %s", errors.ErrNotFound, err))
+
+	diff, err := svc.DiffPolicyVersions(context.Background(), token, policy.ID, 0, 1)
+	require.Nil(t, err, fmt.Sprintf("Unexpected error: %s", err))
+	assert.Equal(t, []policies.PolicyChange{
+		{Path: "description", Operation: policies.ChangeModified, From: "example policy", To: newDescription},
+		{Path: "policy.handlers.modules.default_net", Operation: policies.ChangeRemoved, From: map[string]interface{}{"type": "net"}},
+	}, diff.Changes)
+
+	cases := map[string]struct {
+		version int32
+		token   string
+		err     error
+	}{
+		"rollback a policy with wrong credentials": {
+			version: 0,
+			token:   invalidToken,
+			err:     policies.ErrUnauthorizedAccess,
+		},
+		"rollback a policy to its current version": {
+			version: 1,
+			token:   token,
+			err:     errors.ErrMalformedEntity,
+		},
+		"rollback a policy to a non-existing version": {
+			version: 7,
+			token:   token,
+			err:     errors.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			_, err := svc.RollbackPolicy(context.Background(), tc.token, policy.ID, tc.version)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+		})
+	}
+
+	restored, err := svc.RollbackPolicy(context.Background(), token, policy.ID, 0)
+	require.Nil(t, err, fmt.Sprintf("Unexpected error: %s", err))
+	assert.Equal(t, int32(2), restored.Version)
+	assert.Equal(t, policy_data, restored.PolicyData)
+	assert.Equal(t, *policy.Description, *restored.Description)
+	assert.Equal(t, policy.Name, restored.Name)
+}
+
 func testSortDataset(t *testing.T, pm policies.PageMetadata, ags []policies.Dataset) {
 	t.Helper()
 	switch pm.Order {
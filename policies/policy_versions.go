@@ -0,0 +1,264 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package policies
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+)
+
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+var ErrRollbackPolicy = errors.New("failed to rollback policy")
+
+func (s policiesService) ListPolicyVersions(ctx context.Context, token string, policyID string) ([]PolicyVersion, error) {
+	ownerID, err := s.identify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.RetrievePolicyByID(ctx, policyID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := s.repo.RetrievePolicyVersions(ctx, policyID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the current version is only snapshotted once the policy is edited
+	if len(versions) == 0 || versions[0].Version != current.Version {
+		versions = append([]PolicyVersion{toPolicyVersion(current)}, versions...)
+	}
+
+	return versions, nil
+}
+
+func (s policiesService) ViewPolicyVersion(ctx context.Context, token string, policyID string, version int32) (PolicyVersion, error) {
+	ownerID, err := s.identify(token)
+	if err != nil {
+		return PolicyVersion{}, err
+	}
+
+	current, err := s.repo.RetrievePolicyByID(ctx, policyID, ownerID)
+	if err != nil {
+		return PolicyVersion{}, err
+	}
+
+	return s.retrievePolicyVersion(ctx, current, version)
+}
+
+func (s policiesService) DiffPolicyVersions(ctx context.Context, token string, policyID string, from int32, to int32) (PolicyDiff, error) {
+	ownerID, err := s.identify(token)
+	if err != nil {
+		return PolicyDiff{}, err
+	}
+
+	current, err := s.repo.RetrievePolicyByID(ctx, policyID, ownerID)
+	if err != nil {
+		return PolicyDiff{}, err
+	}
+
+	fromVersion, err := s.retrievePolicyVersion(ctx, current, from)
+	if err != nil {
+		return PolicyDiff{}, err
+	}
+
+	toVersion, err := s.retrievePolicyVersion(ctx, current, to)
+	if err != nil {
+		return PolicyDiff{}, err
+	}
+
+	return PolicyDiff{
+		PolicyID:    policyID,
+		FromVersion: from,
+		ToVersion:   to,
+		Changes:     diffPolicyVersions(fromVersion, toVersion),
+	}, nil
+}
+
+func (s policiesService) ViewPolicyVersionInternal(ctx context.Context, policyID string, ownerID string, version int32) (PolicyVersion, error) {
+	current, err := s.repo.RetrievePolicyByID(ctx, policyID, ownerID)
+	if err != nil {
+		return PolicyVersion{}, err
+	}
+
+	return s.retrievePolicyVersion(ctx, current, version)
+}
+
+func (s policiesService) RollbackPolicy(ctx context.Context, token string, policyID string, version int32) (Policy, error) {
+	ownerID, err := s.identify(token)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	return s.rollbackPolicy(ctx, ownerID, policyID, version)
+}
+
+func (s policiesService) RollbackPolicyInternal(ctx context.Context, ownerID string, policyID string, version int32) (Policy, error) {
+	return s.rollbackPolicy(ctx, ownerID, policyID, version)
+}
+
+func (s policiesService) rollbackPolicy(ctx context.Context, ownerID string, policyID string, version int32) (Policy, error) {
+	current, err := s.repo.RetrievePolicyByID(ctx, policyID, ownerID)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	if version == current.Version {
+		return Policy{}, errors.Wrap(ErrRollbackPolicy, errors.Wrap(errors.ErrMalformedEntity, errors.New(fmt.Sprintf("policy is already at version %d", version))))
+	}
+
+	target, err := s.retrievePolicyVersion(ctx, current, version)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	// the policy name is kept, so agents keep tracking the same policy
+	pol := Policy{
+		ID:          current.ID,
+		Description: target.Description,
+		OrbTags:     target.OrbTags,
+		Format:      target.Format,
+		PolicyData:  target.PolicyData,
+	}
+	// policies written in another format are converted again from the stored policy data
+	if target.PolicyData == "" {
+		pol.Policy = target.Policy
+	}
+
+	res, err := s.editPolicy(ctx, ownerID, current, pol)
+	if err != nil {
+		return Policy{}, errors.Wrap(ErrRollbackPolicy, err)
+	}
+
+	return res, nil
+}
+
+func (s policiesService) retrievePolicyVersion(ctx context.Context, current Policy, version int32) (PolicyVersion, error) {
+	if version < 0 || version > current.Version {
+		return PolicyVersion{}, errors.Wrap(errors.ErrNotFound, errors.New(fmt.Sprintf("policy version %d does not exist", version)))
+	}
+
+	if version == current.Version {
+		return toPolicyVersion(current), nil
+	}
+
+	return s.repo.RetrievePolicyVersion(ctx, current.ID, current.MFOwnerID, version)
+}
+
+func toPolicyVersion(p Policy) PolicyVersion {
+	created := p.LastModified
+	if created.IsZero() {
+		created = p.Created
+	}
+	if created.IsZero() {
+		created = time.Now()
+	}
+
+	return PolicyVersion{
+		PolicyID:    p.ID,
+		MFOwnerID:   p.MFOwnerID,
+		Version:     p.Version,
+		Name:        p.Name,
+		Description: p.Description,
+		OrbTags:     p.OrbTags,
+		Policy:      p.Policy,
+		PolicyData:  p.PolicyData,
+		Format:      p.Format,
+		Created:     created,
+	}
+}
+
+// diffPolicyVersions lists the field changes needed to go from one version to the other,
+// nested policy fields are reported with their dotted path
+func diffPolicyVersions(from PolicyVersion, to PolicyVersion) []PolicyChange {
+	changes := []PolicyChange{}
+
+	if from.Name.String() != to.Name.String() {
+		changes = append(changes, PolicyChange{Path: "name", Operation: ChangeModified, From: from.Name.String(), To: to.Name.String()})
+	}
+	if description(from.Description) != description(to.Description) {
+		changes = append(changes, PolicyChange{Path: "description", Operation: ChangeModified, From: description(from.Description), To: description(to.Description)})
+	}
+	if from.Format != to.Format {
+		changes = append(changes, PolicyChange{Path: "format", Operation: ChangeModified, From: from.Format, To: to.Format})
+	}
+
+	fromTags := make(map[string]interface{}, len(from.OrbTags))
+	for k, v := range from.OrbTags {
+		fromTags[k] = v
+	}
+	toTags := make(map[string]interface{}, len(to.OrbTags))
+	for k, v := range to.OrbTags {
+		toTags[k] = v
+	}
+	changes = diffMaps(changes, "tags", fromTags, toTags)
+
+	return diffMaps(changes, "policy", from.Policy, to.Policy)
+}
+
+func diffMaps(changes []PolicyChange, path string, from map[string]interface{}, to map[string]interface{}) []PolicyChange {
+	keys := make([]string, 0, len(from)+len(to))
+	for k := range from {
+		keys = append(keys, k)
+	}
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		keyPath := path + "." + k
+		fromValue, inFrom := from[k]
+		toValue, inTo := to[k]
+		switch {
+		case !inTo:
+			changes = append(changes, PolicyChange{Path: keyPath, Operation: ChangeRemoved, From: fromValue})
+		case !inFrom:
+			changes = append(changes, PolicyChange{Path: keyPath, Operation: ChangeAdded, To: toValue})
+		default:
+			fromFields, fromIsMap := toMap(fromValue)
+			toFields, toIsMap := toMap(toValue)
+			if fromIsMap && toIsMap {
+				changes = diffMaps(changes, keyPath, fromFields, toFields)
+			} else if !reflect.DeepEqual(fromValue, toValue) {
+				changes = append(changes, PolicyChange{Path: keyPath, Operation: ChangeModified, From: fromValue, To: toValue})
+			}
+		}
+	}
+
+	return changes
+}
+
+func toMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case types.Metadata:
+		return m, true
+	}
+	return nil, false
+}
+
+func description(d *string) string {
+	if d == nil {
+		return ""
+	}
+	return *d
+}
@@ -105,6 +105,39 @@ func migrateDB(db *sqlx.DB) error {
 					format TEXT NOT NULL DEFAULT ''`,
 				},
 			},
+			{
+				Id: "policies_5",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS policy_versions (
+						policy_id      UUID NOT NULL REFERENCES agent_policies(id) ON DELETE CASCADE,
+						mf_owner_id    UUID NOT NULL,
+						version        INTEGER NOT NULL,
+
+						name           TEXT NOT NULL,
+						description    TEXT NOT NULL DEFAULT '',
+						orb_tags       JSONB NOT NULL DEFAULT '{}',
+						policy         JSONB NOT NULL DEFAULT '{}',
+						policy_data    TEXT NOT NULL DEFAULT '',
+						format         TEXT NOT NULL DEFAULT '',
+
+						ts_created     TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+						PRIMARY KEY (policy_id, version)
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE policy_versions",
+				},
+			},
+			{
+				Id: "policies_6",
+				Up: []string{
+					`ALTER TABLE IF EXISTS datasets ADD COLUMN IF NOT EXISTS
+					sink_filters JSONB NOT NULL DEFAULT '{}'`,
+				},
+				Down: []string{
+					"ALTER TABLE datasets DROP COLUMN IF EXISTS sink_filters",
+				},
+			},
 		},
 	}
 
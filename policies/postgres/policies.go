@@ -11,6 +11,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -35,7 +36,7 @@ type policiesRepository struct {
 }
 
 func (r policiesRepository) RetrieveDatasetsByGroupID(ctx context.Context, groupIDs []string, ownerID string) ([]policies.Dataset, error) {
-	q := `SELECT id, agent_group_id, sink_ids, agent_policy_id
+	q := `SELECT id, agent_group_id, sink_ids, sink_filters, agent_policy_id
 			FROM datasets
 			WHERE valid = TRUE AND agent_group_id IN (?) AND mf_owner_id = ?`
 
@@ -64,7 +65,7 @@ func (r policiesRepository) RetrieveDatasetsByGroupID(ctx context.Context, group
 		}
 
 		th := toDataset(dbth)
-		items = append(items, policies.Dataset{ID: th.ID, PolicyID: th.PolicyID, SinkIDs: th.SinkIDs, AgentGroupID: th.AgentGroupID})
+		items = append(items, policies.Dataset{ID: th.ID, PolicyID: th.PolicyID, SinkIDs: th.SinkIDs, SinkFilters: th.SinkFilters, AgentGroupID: th.AgentGroupID})
 	}
 
 	return items, nil
@@ -89,7 +90,7 @@ func (r policiesRepository) DeletePolicy(ctx context.Context, ownerID string, po
 }
 
 func (r policiesRepository) UpdatePolicy(ctx context.Context, owner string, plcy policies.Policy) error {
-	q := `UPDATE agent_policies SET name = :name, description = :description, orb_tags = :orb_tags, policy = :policy, version = :version, ts_last_modified = CURRENT_TIMESTAMP, policy_data = :policy_data WHERE mf_owner_id = :mf_owner_id AND id = :id;`
+	q := `UPDATE agent_policies SET name = :name, description = :description, orb_tags = :orb_tags, policy = :policy, version = :version, ts_last_modified = CURRENT_TIMESTAMP, policy_data = :policy_data, format = :format WHERE mf_owner_id = :mf_owner_id AND id = :id;`
 	plcyDB, err := toDBPolicy(plcy)
 	if err != nil {
 		return errors.Wrap(policies.ErrUpdateEntity, err)
@@ -241,14 +242,15 @@ func (r policiesRepository) RetrievePolicyByID(ctx context.Context, policyID str
 }
 
 func (r policiesRepository) UpdateDataset(ctx context.Context, ownerID string, ds policies.Dataset) error {
-	q := `UPDATE datasets SET tags = :tags, sink_ids = :sink_ids, name = :name WHERE mf_owner_id = :mf_owner_id AND id = :id;`
+	q := `UPDATE datasets SET tags = :tags, sink_ids = :sink_ids, sink_filters = :sink_filters, name = :name WHERE mf_owner_id = :mf_owner_id AND id = :id;`
 
 	params := map[string]interface{}{
-		"mf_owner_id": ds.MFOwnerID,
-		"tags":        db.Tags(ds.Tags),
-		"sink_ids":    pq.Array(ds.SinkIDs),
-		"id":          ds.ID,
-		"name":        ds.Name,
+		"mf_owner_id":  ds.MFOwnerID,
+		"tags":         db.Tags(ds.Tags),
+		"sink_ids":     pq.Array(ds.SinkIDs),
+		"sink_filters": dbSinkFilters(ds.SinkFilters),
+		"id":           ds.ID,
+		"name":         ds.Name,
 	}
 
 	res, err := r.db.NamedExecContext(ctx, q, params)
@@ -309,8 +311,8 @@ func (r policiesRepository) DeleteDataset(ctx context.Context, ownerID string, d
 
 func (r policiesRepository) SaveDataset(ctx context.Context, dataset policies.Dataset) (string, error) {
 
-	q := `INSERT INTO datasets (name, mf_owner_id, metadata, valid, agent_group_id, agent_policy_id, sink_ids, tags)         
-			  VALUES (:name, :mf_owner_id, :metadata, :valid, :agent_group_id, :agent_policy_id, :sink_ids_str, :tags) RETURNING id`
+	q := `INSERT INTO datasets (name, mf_owner_id, metadata, valid, agent_group_id, agent_policy_id, sink_ids, sink_filters, tags)         
+			  VALUES (:name, :mf_owner_id, :metadata, :valid, :agent_group_id, :agent_policy_id, :sink_ids_str, :sink_filters, :tags) RETURNING id`
 
 	if !dataset.Name.IsValid() || dataset.MFOwnerID == "" {
 		return "", errors.ErrMalformedEntity
@@ -438,7 +440,7 @@ func (r policiesRepository) SavePolicy(ctx context.Context, policy policies.Poli
 
 func (r policiesRepository) RetrieveDatasetsByPolicyID(ctx context.Context, policyID string, ownerID string) ([]policies.Dataset, error) {
 
-	q := `SELECT id, name, mf_owner_id, valid, agent_group_id, agent_policy_id, sink_ids, metadata, ts_created 
+	q := `SELECT id, name, mf_owner_id, valid, agent_group_id, agent_policy_id, sink_ids, sink_filters, metadata, ts_created 
 			FROM datasets
 			WHERE agent_policy_id = ? AND mf_owner_id = ?`
 
@@ -474,7 +476,7 @@ func (r policiesRepository) RetrieveDatasetsByPolicyID(ctx context.Context, poli
 }
 
 func (r policiesRepository) RetrieveDatasetByID(ctx context.Context, datasetID string, ownerID string) (policies.Dataset, error) {
-	q := `SELECT id, name, mf_owner_id, valid, agent_group_id, agent_policy_id, sink_ids, metadata, ts_created FROM datasets WHERE id = $1 AND mf_owner_id = $2`
+	q := `SELECT id, name, mf_owner_id, valid, agent_group_id, agent_policy_id, sink_ids, sink_filters, metadata, ts_created FROM datasets WHERE id = $1 AND mf_owner_id = $2`
 
 	if datasetID == "" || ownerID == "" {
 		return policies.Dataset{}, errors.ErrMalformedEntity
@@ -496,7 +498,7 @@ func (r policiesRepository) RetrieveAllDatasetsByOwner(ctx context.Context, owne
 	orderQuery := getOrderQuery(pm.Order)
 	dirQuery := getDirQuery(pm.Dir)
 
-	q := fmt.Sprintf(`SELECT id, name, mf_owner_id, valid, agent_group_id, agent_policy_id, sink_ids, metadata, tags, ts_created 
+	q := fmt.Sprintf(`SELECT id, name, mf_owner_id, valid, agent_group_id, agent_policy_id, sink_ids, sink_filters, metadata, tags, ts_created 
 			FROM datasets
 			WHERE mf_owner_id = :mf_owner_id %s ORDER BY %s %s LIMIT :limit OFFSET :offset;`, nameQuery, orderQuery, dirQuery)
 
@@ -569,7 +571,7 @@ func (r policiesRepository) InactivateDatasetByID(ctx context.Context, id string
 }
 
 func (r policiesRepository) DeleteSinkFromAllDatasets(ctx context.Context, sinkID string, ownerID string) ([]policies.Dataset, error) {
-	q := `UPDATE datasets SET sink_ids = array_remove(sink_ids, :sink_ids) WHERE mf_owner_id = :mf_owner_id RETURNING *`
+	q := `UPDATE datasets SET sink_ids = array_remove(sink_ids, :sink_ids), sink_filters = sink_filters - :sink_ids WHERE mf_owner_id = :mf_owner_id RETURNING *`
 
 	if ownerID == "" {
 		return []policies.Dataset{}, errors.ErrMalformedEntity
@@ -695,6 +697,133 @@ func (r policiesRepository) DeleteAllDatasetsPolicy(ctx context.Context, policyI
 	return nil
 }
 
+func (r policiesRepository) SavePolicyVersion(ctx context.Context, version policies.PolicyVersion) error {
+	q := `INSERT INTO policy_versions (policy_id, mf_owner_id, version, name, description, orb_tags, policy, policy_data, format, ts_created)
+			  VALUES (:policy_id, :mf_owner_id, :version, :name, :description, :orb_tags, :policy, :policy_data, :format, :ts_created)
+			  ON CONFLICT (policy_id, version) DO NOTHING`
+
+	if version.PolicyID == "" || version.MFOwnerID == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	dbv, err := toDBPolicyVersion(version)
+	if err != nil {
+		return errors.Wrap(db.ErrSaveDB, err)
+	}
+
+	if _, err := r.db.NamedExecContext(ctx, q, dbv); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case db.ErrInvalid, db.ErrTruncation:
+				return errors.Wrap(errors.ErrMalformedEntity, err)
+			}
+		}
+		return errors.Wrap(db.ErrSaveDB, err)
+	}
+
+	return nil
+}
+
+func (r policiesRepository) RetrievePolicyVersions(ctx context.Context, policyID string, ownerID string) ([]policies.PolicyVersion, error) {
+	q := `SELECT policy_id, mf_owner_id, version, name, description, orb_tags, policy, policy_data, format, ts_created
+			FROM policy_versions WHERE policy_id = $1 AND mf_owner_id = $2 ORDER BY version DESC`
+
+	if policyID == "" || ownerID == "" {
+		return nil, errors.ErrMalformedEntity
+	}
+
+	rows, err := r.db.QueryxContext(ctx, q, policyID, ownerID)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+	defer rows.Close()
+
+	var items []policies.PolicyVersion
+	for rows.Next() {
+		var dbv dbPolicyVersion
+		if err := rows.StructScan(&dbv); err != nil {
+			return nil, errors.Wrap(errors.ErrSelectEntity, err)
+		}
+		items = append(items, toPolicyVersion(dbv))
+	}
+
+	return items, nil
+}
+
+func (r policiesRepository) RetrievePolicyVersion(ctx context.Context, policyID string, ownerID string, version int32) (policies.PolicyVersion, error) {
+	q := `SELECT policy_id, mf_owner_id, version, name, description, orb_tags, policy, policy_data, format, ts_created
+			FROM policy_versions WHERE policy_id = $1 AND mf_owner_id = $2 AND version = $3`
+
+	if policyID == "" || ownerID == "" {
+		return policies.PolicyVersion{}, errors.ErrMalformedEntity
+	}
+
+	var dbv dbPolicyVersion
+	if err := r.db.QueryRowxContext(ctx, q, policyID, ownerID, version).StructScan(&dbv); err != nil {
+		if err == sql.ErrNoRows {
+			return policies.PolicyVersion{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return policies.PolicyVersion{}, errors.Wrap(errors.ErrSelectEntity, err)
+	}
+
+	return toPolicyVersion(dbv), nil
+}
+
+type dbPolicyVersion struct {
+	PolicyID    string           `db:"policy_id"`
+	MFOwnerID   string           `db:"mf_owner_id"`
+	Version     int32            `db:"version"`
+	Name        types.Identifier `db:"name"`
+	Description string           `db:"description"`
+	OrbTags     db.Tags          `db:"orb_tags"`
+	Policy      db.Metadata      `db:"policy"`
+	PolicyData  string           `db:"policy_data"`
+	Format      string           `db:"format"`
+	Created     time.Time        `db:"ts_created"`
+}
+
+func toDBPolicyVersion(version policies.PolicyVersion) (dbPolicyVersion, error) {
+	var uID uuid.UUID
+	err := uID.Scan(version.MFOwnerID)
+	if err != nil {
+		return dbPolicyVersion{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	var description string
+	if version.Description != nil {
+		description = *version.Description
+	}
+
+	return dbPolicyVersion{
+		PolicyID:    version.PolicyID,
+		MFOwnerID:   uID.String(),
+		Version:     version.Version,
+		Name:        version.Name,
+		Description: description,
+		OrbTags:     db.Tags(version.OrbTags),
+		Policy:      db.Metadata(version.Policy),
+		PolicyData:  version.PolicyData,
+		Format:      version.Format,
+		Created:     version.Created,
+	}, nil
+}
+
+func toPolicyVersion(dbv dbPolicyVersion) policies.PolicyVersion {
+	return policies.PolicyVersion{
+		PolicyID:    dbv.PolicyID,
+		MFOwnerID:   dbv.MFOwnerID,
+		Version:     dbv.Version,
+		Name:        dbv.Name,
+		Description: &dbv.Description,
+		OrbTags:     types.Tags(dbv.OrbTags),
+		Policy:      types.Metadata(dbv.Policy),
+		PolicyData:  dbv.PolicyData,
+		Format:      dbv.Format,
+		Created:     dbv.Created,
+	}
+}
+
 type dbPolicy struct {
 	ID            string           `db:"id"`
 	Name          types.Identifier `db:"name"`
@@ -756,6 +885,7 @@ type dbDataset struct {
 	Tags         db.Tags          `db:"tags"`
 	SinkIDs      pq.StringArray   `db:"sink_ids"`
 	SinksIDsStr  interface{}      `db:"sink_ids_str"`
+	SinkFilters  dbSinkFilters    `db:"sink_filters"`
 }
 
 func toDBDataset(dataset policies.Dataset) (dbDataset, error) {
@@ -773,6 +903,7 @@ func toDBDataset(dataset policies.Dataset) (dbDataset, error) {
 		Metadata:    db.Metadata(dataset.Metadata),
 		Tags:        db.Tags(dataset.Tags),
 		SinksIDsStr: pq.Array(dataset.SinkIDs),
+		SinkFilters: dbSinkFilters(dataset.SinkFilters),
 	}
 
 	d.Valid = true
@@ -831,11 +962,40 @@ func toDataset(dba dbDataset) policies.Dataset {
 		Metadata:     types.Metadata(dba.Metadata),
 		Created:      dba.TsCreated,
 		Tags:         types.Tags(dba.Tags),
+		SinkFilters:  dba.SinkFilters,
 	}
 
 	return dataset
 }
 
+// dbSinkFilters stores the dataset sink filters as a JSONB object keyed by sink ID
+type dbSinkFilters map[string]policies.SinkFilter
+
+func (f *dbSinkFilters) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	b, ok := value.([]byte)
+	if !ok {
+		return db.ErrScanMetadata
+	}
+
+	return json.Unmarshal(b, f)
+}
+
+func (f dbSinkFilters) Value() (driver.Value, error) {
+	if len(f) == 0 {
+		return "{}", nil
+	}
+
+	b, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
 func getNameQuery(name string) (string, string) {
 	if name == "" {
 		return "", ""
@@ -488,3 +488,78 @@ func testSortPolicies(t *testing.T, pm policies.PageMetadata, ags []policies.Pol
 		break
 	}
 }
+
+func TestPolicyVersionSaveAndRetrieve(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.NewPoliciesRepository(dbMiddleware, logger)
+
+	oID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	nameID, err := types.NewIdentifier("mypolicy-versions")
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	policy := policies.Policy{
+		Name:      nameID,
+		MFOwnerID: oID.String(),
+		Policy:    types.Metadata{"kind": "collection"},
+	}
+
+	policyID, err := repo.SavePolicy(context.Background(), policy)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	for i := int32(0); i < 3; i++ {
+		version := policies.PolicyVersion{
+			PolicyID:  policyID,
+			MFOwnerID: oID.String(),
+			Version:   i,
+			Name:      nameID,
+			Policy:    types.Metadata{"kind": "collection", "version": float64(i)},
+			Created:   time.Now(),
+		}
+		err = repo.SavePolicyVersion(context.Background(), version)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	// saving an existing version leaves the snapshot untouched
+	err = repo.SavePolicyVersion(context.Background(), policies.PolicyVersion{
+		PolicyID:  policyID,
+		MFOwnerID: oID.String(),
+		Version:   1,
+		Name:      nameID,
+		Policy:    types.Metadata{"kind": "changed"},
+		Created:   time.Now(),
+	})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	versions, err := repo.RetrievePolicyVersions(context.Background(), policyID, oID.String())
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	require.Len(t, versions, 3)
+	assert.Equal(t, int32(2), versions[0].Version)
+
+	cases := map[string]struct {
+		version int32
+		kind    string
+		err     error
+	}{
+		"retrieve a saved version": {
+			version: 1,
+			kind:    "collection",
+			err:     nil,
+		},
+		"retrieve a non-existing version": {
+			version: 5,
+			err:     errors.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			version, err := repo.RetrievePolicyVersion(context.Background(), policyID, oID.String(), tc.version)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected '%s' got '%s'", desc, tc.err, err))
+			if err == nil {
+				assert.Equal(t, tc.kind, version.Policy["kind"])
+			}
+		})
+	}
+}
@@ -16,6 +16,13 @@ type removeAgentGroupEvent struct {
 	timestamp time.Time
 }
 
+type rollbackPolicyEvent struct {
+	policyID  string
+	ownerID   string
+	version   int32
+	timestamp time.Time
+}
+
 type removeSinkEvent struct {
 	sinkID    string
 	ownerID   string
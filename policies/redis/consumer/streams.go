@@ -10,6 +10,8 @@ package consumer
 
 import (
 	"context"
+	"strconv"
+
 	"github.com/go-redis/redis/v8"
 	"github.com/orb-community/orb/policies"
 	"go.uber.org/zap"
@@ -22,6 +24,8 @@ const (
 
 	agentGroupPrefix = "agent_group."
 	agentGroupRemove = agentGroupPrefix + "remove"
+	policyPrefix     = "policy."
+	policyRollback   = policyPrefix + "rollback"
 	sinkPrefix       = "sinks."
 	sinkRemove       = sinkPrefix + "remove"
 
@@ -75,6 +79,9 @@ func (es eventStore) SubscribeToFleet(context context.Context) error {
 			case agentGroupRemove:
 				rte := decodeAgentGroupRemove(event)
 				err = es.handleAgentGroupRemove(context, rte.groupID, rte.token)
+			case policyRollback:
+				rte := decodePolicyRollback(event)
+				err = es.handlePolicyRollback(context, rte.ownerID, rte.policyID, rte.version)
 			}
 			if err != nil {
 				es.logger.Error("Failed to handle event", zap.String("operation", event["operation"].(string)), zap.Error(err))
@@ -128,6 +135,14 @@ func decodeAgentGroupRemove(event map[string]interface{}) removeAgentGroupEvent
 	}
 }
 
+func decodePolicyRollback(event map[string]interface{}) rollbackPolicyEvent {
+	return rollbackPolicyEvent{
+		policyID: read(event, "policy_id", ""),
+		ownerID:  read(event, "owner_id", ""),
+		version:  readInt32(event, "version", -1),
+	}
+}
+
 func decodeSinkRemove(event map[string]interface{}) removeSinkEvent {
 	return removeSinkEvent{
 		sinkID:  read(event, "sink_id", ""),
@@ -150,6 +165,15 @@ func (es eventStore) handleAgentGroupRemove(ctx context.Context, groupID string,
 	return nil
 }
 
+// Restore the previous version of a policy after fleet reported a failed rollout
+func (es eventStore) handlePolicyRollback(ctx context.Context, ownerID string, policyID string, version int32) error {
+	_, err := es.policiesService.RollbackPolicyInternal(ctx, ownerID, policyID, version)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 func (es eventStore) handleSinkRemove(ctx context.Context, sinkID string, ownerID string) error {
 
 	datasets, err := es.policiesService.DeleteSinkFromAllDatasetsInternal(ctx, sinkID, ownerID)
@@ -176,3 +200,15 @@ func read(event map[string]interface{}, key, def string) string {
 	}
 	return val
 }
+
+func readInt32(event map[string]interface{}, key string, def int32) int32 {
+	val, ok := event[key].(string)
+	if !ok {
+		return def
+	}
+	i, err := strconv.ParseInt(val, 10, 32)
+	if err != nil {
+		return def
+	}
+	return int32(i)
+}
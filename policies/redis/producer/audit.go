@@ -0,0 +1,56 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package producer
+
+import (
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/orb-community/orb/policies"
+)
+
+// The snapshots below are what the audit log compares to find what a change did
+
+type policySnapshot struct {
+	Name          string         `json:"name"`
+	Description   *string        `json:"description"`
+	Backend       string         `json:"backend"`
+	SchemaVersion string         `json:"schema_version"`
+	Version       int32          `json:"version"`
+	Tags          types.Tags     `json:"tags"`
+	Policy        types.Metadata `json:"policy"`
+}
+
+func toPolicySnapshot(p policies.Policy) *policySnapshot {
+	return &policySnapshot{
+		Name:          p.Name.String(),
+		Description:   p.Description,
+		Backend:       p.Backend,
+		SchemaVersion: p.SchemaVersion,
+		Version:       p.Version,
+		Tags:          p.OrbTags,
+		Policy:        p.Policy,
+	}
+}
+
+type datasetSnapshot struct {
+	Name         string                         `json:"name"`
+	Valid        bool                           `json:"valid"`
+	AgentGroupID string                         `json:"agent_group_id"`
+	PolicyID     string                         `json:"agent_policy_id"`
+	SinkIDs      *[]string                      `json:"sink_ids"`
+	SinkFilters  map[string]policies.SinkFilter `json:"sink_filters,omitempty"`
+	Tags         types.Tags                     `json:"tags"`
+}
+
+func toDatasetSnapshot(ds policies.Dataset) *datasetSnapshot {
+	return &datasetSnapshot{
+		Name:         ds.Name.String(),
+		Valid:        ds.Valid,
+		AgentGroupID: ds.AgentGroupID,
+		PolicyID:     ds.PolicyID,
+		SinkIDs:      ds.SinkIDs,
+		SinkFilters:  ds.SinkFilters,
+		Tags:         ds.Tags,
+	}
+}
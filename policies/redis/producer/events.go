@@ -10,6 +10,8 @@ package producer
 
 import (
 	"time"
+
+	"github.com/orb-community/orb/policies"
 )
 
 const (
@@ -77,6 +79,8 @@ type updatePolicyEvent struct {
 	id        string
 	ownerID   string
 	groupIDs  string
+	version   int32
+	rollout   *policies.RolloutStrategy
 	timestamp time.Time
 }
 
@@ -139,13 +143,22 @@ func (cce createPolicyEvent) Encode() map[string]interface{} {
 }
 
 func (cce updatePolicyEvent) Encode() map[string]interface{} {
-	return map[string]interface{}{
+	val := map[string]interface{}{
 		"id":         cce.id,
 		"owner_id":   cce.ownerID,
 		"groups_ids": cce.groupIDs,
+		"version":    cce.version,
 		"timestamp":  cce.timestamp.Unix(),
 		"operation":  PolicyUpdate,
 	}
+	// fleet stages the update to canary agents only when the rollout fields are present
+	if cce.rollout != nil {
+		val["rollout_canary_percent"] = cce.rollout.CanaryPercent
+		val["rollout_canary_count"] = cce.rollout.CanaryCount
+		val["rollout_on_failure"] = cce.rollout.OnFailure
+		val["rollout_timeout"] = int64(cce.rollout.Timeout.Seconds())
+	}
+	return val
 }
 
 func (cce removePolicyEvent) Encode() map[string]interface{} {
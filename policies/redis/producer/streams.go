@@ -12,6 +12,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/go-redis/redis/v8"
+	"github.com/orb-community/orb/audit"
 	"github.com/orb-community/orb/pkg/errors"
 	"github.com/orb-community/orb/policies"
 	"github.com/orb-community/orb/policies/backend"
@@ -31,9 +32,10 @@ var (
 var _ policies.Service = (*eventStore)(nil)
 
 type eventStore struct {
-	svc    policies.Service
-	client *redis.Client
-	logger *zap.Logger
+	svc      policies.Service
+	client   *redis.Client
+	recorder audit.Recorder
+	logger   *zap.Logger
 }
 
 func (e eventStore) ListDatasetsByGroupIDInternal(ctx context.Context, groupIDs []string, ownerID string) ([]policies.Dataset, error) {
@@ -53,6 +55,7 @@ func (e eventStore) RemoveDataset(ctx context.Context, token string, dsID string
 	if err := e.svc.RemoveDataset(ctx, token, dsID); err != nil {
 		return err
 	}
+	e.recorder.Record(ctx, token, audit.EntityDataset, dsID, audit.ActionRemove, toDatasetSnapshot(ds), nil)
 
 	event := removeDatasetEvent{
 		id:           dsID,
@@ -87,6 +90,8 @@ func (e eventStore) EditDataset(ctx context.Context, token string, ds policies.D
 	if err != nil {
 		return policies.Dataset{}, err
 	}
+	e.recorder.Record(ctx, token, audit.EntityDataset, editedDataset.ID, audit.ActionUpdate,
+		toDatasetSnapshot(previousDataset), toDatasetSnapshot(editedDataset))
 
 	event := updateDatasetEvent{
 		id:           editedDataset.ID,
@@ -130,6 +135,7 @@ func (e eventStore) RemovePolicy(ctx context.Context, token string, policyID str
 	if err := e.svc.RemovePolicy(ctx, token, policyID); err != nil {
 		return err
 	}
+	e.recorder.Record(ctx, token, audit.EntityPolicy, policyID, audit.ActionRemove, toPolicySnapshot(policy), nil)
 
 	datasets, err := e.svc.ListDatasetsByPolicyIDInternal(ctx, policyID, token)
 	if err != nil {
@@ -139,6 +145,10 @@ func (e eventStore) RemovePolicy(ctx context.Context, token string, policyID str
 	err = e.svc.RemoveAllDatasetsByPolicyIDInternal(ctx, token, policyID)
 	if err != nil {
 		e.logger.Error("error while removing datasets", zap.Error(err))
+	} else {
+		for _, ds := range datasets {
+			e.recorder.Record(ctx, token, audit.EntityDataset, ds.ID, audit.ActionRemove, toDatasetSnapshot(ds), nil)
+		}
 	}
 
 	if len(datasets) == 0 {
@@ -179,10 +189,16 @@ func (e eventStore) ListDatasetsByPolicyIDInternal(ctx context.Context, policyID
 }
 
 func (e eventStore) EditPolicy(ctx context.Context, token string, pol policies.Policy) (policies.Policy, error) {
+	previousPol, err := e.svc.ViewPolicyByID(ctx, token, pol.ID)
+	if err != nil {
+		return policies.Policy{}, err
+	}
+
 	editedPol, err := e.svc.EditPolicy(ctx, token, pol)
 	if err != nil {
 		return policies.Policy{}, err
 	}
+	e.recorder.Record(ctx, token, audit.EntityPolicy, editedPol.ID, audit.ActionUpdate, toPolicySnapshot(previousPol), toPolicySnapshot(editedPol))
 
 	datasets, err := e.svc.ListDatasetsByPolicyIDInternal(ctx, editedPol.ID, token)
 	if err != nil {
@@ -199,10 +215,84 @@ func (e eventStore) EditPolicy(ctx context.Context, token string, pol policies.P
 		return policies.Policy{}, err
 	}
 
+	err = e.sendPolicyUpdate(ctx, editedPol, groupsIDs)
+	if err != nil {
+		return editedPol, err
+	}
+
+	return editedPol, nil
+}
+
+func (e eventStore) RollbackPolicy(ctx context.Context, token string, policyID string, version int32) (policies.Policy, error) {
+	previousPol, err := e.svc.ViewPolicyByID(ctx, token, policyID)
+	if err != nil {
+		return policies.Policy{}, err
+	}
+
+	restoredPol, err := e.svc.RollbackPolicy(ctx, token, policyID, version)
+	if err != nil {
+		return policies.Policy{}, err
+	}
+	e.recorder.Record(ctx, token, audit.EntityPolicy, restoredPol.ID, audit.ActionUpdate, toPolicySnapshot(previousPol), toPolicySnapshot(restoredPol))
+
+	datasets, err := e.svc.ListDatasetsByPolicyIDInternal(ctx, restoredPol.ID, token)
+	if err != nil {
+		return policies.Policy{}, err
+	}
+
+	var groupsIDs []string
+	for _, ds := range datasets {
+		groupsIDs = append(groupsIDs, ds.AgentGroupID)
+	}
+
+	err = e.sendPolicyUpdate(ctx, restoredPol, groupsIDs)
+	if err != nil {
+		return restoredPol, err
+	}
+
+	return restoredPol, nil
+}
+
+func (e eventStore) RollbackPolicyInternal(ctx context.Context, ownerID string, policyID string, version int32) (policies.Policy, error) {
+	restoredPol, err := e.svc.RollbackPolicyInternal(ctx, ownerID, policyID, version)
+	if err != nil {
+		return policies.Policy{}, err
+	}
+
+	datasets, err := e.svc.ListDatasetsByPolicyOwnerInternal(ctx, restoredPol.ID, ownerID)
+	if err != nil {
+		return policies.Policy{}, err
+	}
+
+	var groupsIDs []string
+	for _, ds := range datasets {
+		groupsIDs = append(groupsIDs, ds.AgentGroupID)
+	}
+
+	err = e.sendPolicyUpdate(ctx, restoredPol, groupsIDs)
+	if err != nil {
+		return restoredPol, err
+	}
+
+	return restoredPol, nil
+}
+
+func (e eventStore) ViewPolicyVersionInternal(ctx context.Context, policyID string, ownerID string, version int32) (policies.PolicyVersion, error) {
+	return e.svc.ViewPolicyVersionInternal(ctx, policyID, ownerID, version)
+}
+
+func (e eventStore) ListDatasetsByPolicyOwnerInternal(ctx context.Context, policyID string, ownerID string) ([]policies.Dataset, error) {
+	return e.svc.ListDatasetsByPolicyOwnerInternal(ctx, policyID, ownerID)
+}
+
+// sendPolicyUpdate notifies fleet so the agent groups running the policy receive the new version
+func (e eventStore) sendPolicyUpdate(ctx context.Context, pol policies.Policy, groupsIDs []string) error {
 	event := updatePolicyEvent{
-		id:       editedPol.ID,
-		ownerID:  editedPol.MFOwnerID,
+		id:       pol.ID,
+		ownerID:  pol.MFOwnerID,
 		groupIDs: strings.Join(groupsIDs, ","),
+		version:  pol.Version,
+		rollout:  pol.Rollout,
 	}
 	record := &redis.XAddArgs{
 		Stream: streamID,
@@ -210,17 +300,35 @@ func (e eventStore) EditPolicy(ctx context.Context, token string, pol policies.P
 		Approx: true,
 		Values: event.Encode(),
 	}
-	err = e.client.XAdd(ctx, record).Err()
+	err := e.client.XAdd(ctx, record).Err()
 	if err != nil {
 		e.logger.Error("error sending event to event store", zap.Error(err))
-		return editedPol, err
+		return err
 	}
 
-	return editedPol, nil
+	return nil
+}
+
+func (e eventStore) ListPolicyVersions(ctx context.Context, token string, policyID string) ([]policies.PolicyVersion, error) {
+	return e.svc.ListPolicyVersions(ctx, token, policyID)
+}
+
+func (e eventStore) ViewPolicyVersion(ctx context.Context, token string, policyID string, version int32) (policies.PolicyVersion, error) {
+	return e.svc.ViewPolicyVersion(ctx, token, policyID, version)
+}
+
+func (e eventStore) DiffPolicyVersions(ctx context.Context, token string, policyID string, from int32, to int32) (policies.PolicyDiff, error) {
+	return e.svc.DiffPolicyVersions(ctx, token, policyID, from, to)
 }
 
 func (e eventStore) AddPolicy(ctx context.Context, token string, p policies.Policy) (policies.Policy, error) {
-	return e.svc.AddPolicy(ctx, token, p)
+	pol, err := e.svc.AddPolicy(ctx, token, p)
+	if err != nil {
+		return pol, err
+	}
+
+	e.recorder.Record(ctx, token, audit.EntityPolicy, pol.ID, audit.ActionCreate, nil, toPolicySnapshot(pol))
+	return pol, nil
 }
 
 func (e eventStore) ViewPolicyByID(ctx context.Context, token string, policyID string) (policies.Policy, error) {
@@ -244,6 +352,7 @@ func (e eventStore) AddDataset(ctx context.Context, token string, d policies.Dat
 	if err != nil {
 		return ds, err
 	}
+	e.recorder.Record(ctx, token, audit.EntityDataset, ds.ID, audit.ActionCreate, nil, toDatasetSnapshot(ds))
 
 	event := createDatasetEvent{
 		id:           ds.ID,
@@ -321,7 +430,13 @@ func (e eventStore) DeleteAgentGroupFromAllDatasets(ctx context.Context, groupID
 }
 
 func (e eventStore) DuplicatePolicy(ctx context.Context, token string, policyID string, name string) (policies.Policy, error) {
-	return e.svc.DuplicatePolicy(ctx, token, policyID, name)
+	pol, err := e.svc.DuplicatePolicy(ctx, token, policyID, name)
+	if err != nil {
+		return pol, err
+	}
+
+	e.recorder.Record(ctx, token, audit.EntityPolicy, pol.ID, audit.ActionCreate, nil, toPolicySnapshot(pol))
+	return pol, nil
 }
 
 func (e eventStore) RemoveAllDatasetsByPolicyIDInternal(ctx context.Context, token string, policyID string) error {
@@ -329,12 +444,13 @@ func (e eventStore) RemoveAllDatasetsByPolicyIDInternal(ctx context.Context, tok
 }
 
 // NewEventStoreMiddleware returns wrapper around policies service that sends
-// events to event store.
-func NewEventStoreMiddleware(svc policies.Service, client *redis.Client, logger *zap.Logger) policies.Service {
+// events to event store, and records the changes made through it to the audit log.
+func NewEventStoreMiddleware(svc policies.Service, client *redis.Client, recorder audit.Recorder, logger *zap.Logger) policies.Service {
 	return eventStore{
-		logger: logger,
-		svc:    svc,
-		client: client,
+		logger:   logger,
+		svc:      svc,
+		client:   client,
+		recorder: recorder,
 	}
 }
 
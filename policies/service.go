@@ -48,12 +48,14 @@ func (s policiesService) identify(token string) (string, error) {
 	return res.GetId(), nil
 }
 
-func New(logger *zap.Logger, auth mainflux.AuthServiceClient, repo Repository, fleetGrpcClient fleetpb.FleetServiceClient, sinksGrpcclient sinkpb.SinkServiceClient) Service {
+func New(logger *zap.Logger, auth mainflux.AuthServiceClient, repo Repository, fleetGrpcClient fleetpb.FleetServiceClient, sinksGrpcclient sinkpb.SinkServiceClient) (Service, error) {
 
 	orb.Register()
 	//TODO it might not need the logger here, just added for debugging for now
 	otel.Register(logger)
-	pktvisor.Register()
+	if err := pktvisor.Register(); err != nil {
+		return nil, err
+	}
 
 	return &policiesService{
 		logger:          logger,
@@ -61,5 +63,5 @@ func New(logger *zap.Logger, auth mainflux.AuthServiceClient, repo Repository, f
 		repo:            repo,
 		fleetGrpcClient: fleetGrpcClient,
 		sinksGrpcClient: sinksGrpcclient,
-	}
+	}, nil
 }
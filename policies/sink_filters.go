@@ -0,0 +1,94 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package policies
+
+import (
+	"path"
+
+	"github.com/orb-community/orb/pkg/errors"
+)
+
+// ErrInvalidSinkFilter indicates a sink filter with a malformed glob or attribute, or on a sink the dataset does not send to
+var ErrInvalidSinkFilter = errors.New("invalid sink filter")
+
+// SinkFilter trims the metrics a dataset sends to one of its sinks, it is applied by the sinker before exporting them
+type SinkFilter struct {
+	// IncludeMetrics keeps only the metrics whose name matches one of the globs, every metric is kept when empty
+	IncludeMetrics []string `json:"include_metrics,omitempty"`
+	// ExcludeMetrics drops the metrics whose name matches one of the globs, even when included
+	ExcludeMetrics []string `json:"exclude_metrics,omitempty"`
+	// DropAttributes removes the attributes from every data point
+	DropAttributes []string `json:"drop_attributes,omitempty"`
+	// RenameAttributes renames the attributes of every data point, from the key to the value
+	RenameAttributes map[string]string `json:"rename_attributes,omitempty"`
+	// AddLabels sets static attributes on every data point, after the drops and renames
+	AddLabels map[string]string `json:"add_labels,omitempty"`
+}
+
+// Validate checks the globs of the filter are well-formed and its attributes named
+func (f SinkFilter) Validate() error {
+	for _, globs := range [][]string{f.IncludeMetrics, f.ExcludeMetrics} {
+		for _, glob := range globs {
+			if glob == "" {
+				return errors.Wrap(ErrInvalidSinkFilter, errors.New("empty metric glob"))
+			}
+			if _, err := path.Match(glob, ""); err != nil {
+				return errors.Wrap(ErrInvalidSinkFilter, errors.New("malformed metric glob "+glob))
+			}
+		}
+	}
+	for _, attr := range f.DropAttributes {
+		if attr == "" {
+			return errors.Wrap(ErrInvalidSinkFilter, errors.New("empty attribute to drop"))
+		}
+	}
+	for from, to := range f.RenameAttributes {
+		if from == "" || to == "" {
+			return errors.Wrap(ErrInvalidSinkFilter, errors.New("attributes must be renamed from and to a non empty name"))
+		}
+	}
+	for label := range f.AddLabels {
+		if label == "" {
+			return errors.Wrap(ErrInvalidSinkFilter, errors.New("empty label to add"))
+		}
+	}
+	return nil
+}
+
+// validateSinkFilters checks every filter is valid and on one of the sinks of the dataset
+func validateSinkFilters(sinkIDs []string, filters map[string]SinkFilter) error {
+	sinks := make(map[string]bool, len(sinkIDs))
+	for _, id := range sinkIDs {
+		sinks[id] = true
+	}
+	for sinkID, f := range filters {
+		if !sinks[sinkID] {
+			return errors.Wrap(ErrMalformedEntity, errors.Wrap(ErrInvalidSinkFilter, errors.New("sink "+sinkID+" is not a sink of the dataset")))
+		}
+		if err := f.Validate(); err != nil {
+			return errors.Wrap(ErrMalformedEntity, err)
+		}
+	}
+	return nil
+}
+
+// keepSinkFilters returns the filters on the sinks the dataset still sends to
+func keepSinkFilters(sinkIDs []string, filters map[string]SinkFilter) map[string]SinkFilter {
+	kept := make(map[string]SinkFilter, len(filters))
+	for _, id := range sinkIDs {
+		if f, ok := filters[id]; ok {
+			kept[id] = f
+		}
+	}
+	return kept
+}
+
+// datasetSinkIDs returns the sinks of the dataset, if any
+func datasetSinkIDs(d Dataset) []string {
+	if d.SinkIDs == nil {
+		return nil
+	}
+	return *d.SinkIDs
+}
@@ -128,24 +128,53 @@ func (bs *SinkerOtelBridgeService) GetSinkIdsFromDatasetIDs(ctx context.Context,
 	mapSinkIdPolicy := make(map[string]string)
 	sort.Strings(datasetIDs)
 	for i := 0; i < len(datasetIDs); i++ {
-		datasetID := datasetIDs[i]
-		cacheKey := fmt.Sprintf("ds-%s-%s", mfOwnerId, datasetID)
-		value, found := bs.inMemoryCache.Get(cacheKey)
-		if !found {
-			datasetRes, err := bs.policiesClient.RetrieveDataset(ctx, &policiespb.DatasetByIDReq{
-				DatasetID: datasetID,
-				OwnerID:   mfOwnerId,
-			})
-			if err != nil {
-				bs.logger.Info("unable to retrieve datasets from policy")
-				return nil, err
-			}
-			value = datasetRes.SinkIds
-			bs.inMemoryCache.Set(cacheKey, value, cache.DefaultExpiration)
+		datasetRes, err := bs.retrieveDataset(ctx, mfOwnerId, datasetIDs[i])
+		if err != nil {
+			return nil, err
 		}
-		for _, sinkId := range value.([]string) {
+		for _, sinkId := range datasetRes.SinkIds {
 			mapSinkIdPolicy[sinkId] = "active"
 		}
 	}
 	return mapSinkIdPolicy, nil
 }
+
+// GetSinkFiltersFromDatasetIDs retrieve the sinks of the datasets with their metric filter, a nil filter sends every metric.
+// When datasets of the same policy send to a sink with different filters, an unfiltered dataset wins, otherwise the first dataset in order does
+func (bs *SinkerOtelBridgeService) GetSinkFiltersFromDatasetIDs(ctx context.Context, mfOwnerId string, datasetIDs []string) (map[string]*policiespb.SinkFilter, error) {
+	sinkFilters := make(map[string]*policiespb.SinkFilter)
+	sort.Strings(datasetIDs)
+	for i := 0; i < len(datasetIDs); i++ {
+		datasetRes, err := bs.retrieveDataset(ctx, mfOwnerId, datasetIDs[i])
+		if err != nil {
+			return nil, err
+		}
+		for _, sinkId := range datasetRes.SinkIds {
+			filter := datasetRes.SinkFilters[sinkId]
+			current, found := sinkFilters[sinkId]
+			if !found || (filter == nil && current != nil) {
+				sinkFilters[sinkId] = filter
+			}
+		}
+	}
+	return sinkFilters, nil
+}
+
+// retrieveDataset retrieve a dataset from policies service, or cache
+func (bs *SinkerOtelBridgeService) retrieveDataset(ctx context.Context, mfOwnerId string, datasetID string) (*policiespb.DatasetRes, error) {
+	cacheKey := fmt.Sprintf("ds-%s-%s", mfOwnerId, datasetID)
+	value, found := bs.inMemoryCache.Get(cacheKey)
+	if !found {
+		datasetRes, err := bs.policiesClient.RetrieveDataset(ctx, &policiespb.DatasetByIDReq{
+			DatasetID: datasetID,
+			OwnerID:   mfOwnerId,
+		})
+		if err != nil {
+			bs.logger.Info("unable to retrieve datasets from policy")
+			return nil, err
+		}
+		bs.inMemoryCache.Set(cacheKey, datasetRes, cache.DefaultExpiration)
+		return datasetRes, nil
+	}
+	return value.(*policiespb.DatasetRes), nil
+}
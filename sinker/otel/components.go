@@ -201,7 +201,7 @@ func StartOtelTracesComponents(ctx context.Context, bridgeService *bridgeservice
 	log := logger.Sugar()
 	log.Info("Starting to create Otel Traces Components in routine: ", ctx.Value("routine"))
 	exporterFactory := kafkaexporter.NewFactory()
-	exporterCtx := context.WithValue(otelContext, "component", "kafkaexporter")
+	exporterCtx := context.WithValue(otelContext, "component", "kafkaexportertraces")
 	exporterCreateSettings := exporter.CreateSettings{
 		TelemetrySettings: component.TelemetrySettings{
 			Logger:         logger,
@@ -229,7 +229,7 @@ func StartOtelTracesComponents(ctx context.Context, bridgeService *bridgeservice
 		return nil, err
 	}
 	transformFactory := transformprocessor.NewFactory()
-	transformCtx := context.WithValue(otelContext, "component", "transformprocessor")
+	transformCtx := context.WithValue(otelContext, "component", "transformprocessortraces")
 	log.Info("start to create traces component", zap.Any("component", transformCtx.Value("component")))
 	transformCfg := transformFactory.CreateDefaultConfig().(*transformprocessor.Config)
 	transformSet := processor.CreateSettings{
@@ -250,7 +250,7 @@ func StartOtelTracesComponents(ctx context.Context, bridgeService *bridgeservice
 	log.Info("created kafka traces exporter successfully")
 	// receiver Factory
 	orbReceiverFactory := orbreceiver.NewFactory()
-	receiverCtx := context.WithValue(otelContext, "component", "orbreceiver")
+	receiverCtx := context.WithValue(otelContext, "component", "orbreceivertraces")
 	receiverCfg := orbReceiverFactory.CreateDefaultConfig().(*orbreceiver.Config)
 	receiverCfg.Logger = logger
 	receiverCfg.PubSub = pubSub
@@ -0,0 +1,98 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package orbreceiver
+
+import (
+	"path"
+	"sort"
+
+	policiespb "github.com/orb-community/orb/policies/pb"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// applySinkFilter trims the scope metrics copied for a sink with the dataset filter of that sink:
+// metrics not included or excluded by name are removed, then the data point attributes are dropped, renamed and labels added
+func applySinkFilter(metricsScope pmetric.ScopeMetrics, filter *policiespb.SinkFilter) {
+	if filter == nil {
+		return
+	}
+	metricsScope.Metrics().RemoveIf(func(metricItem pmetric.Metric) bool {
+		return !keepMetric(filter, metricItem.Name())
+	})
+
+	renames := make([]string, 0, len(filter.RenameAttributes))
+	for from := range filter.RenameAttributes {
+		renames = append(renames, from)
+	}
+	sort.Strings(renames)
+
+	metrics := metricsScope.Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		for _, attributes := range dataPointsAttributes(metrics.At(i)) {
+			for _, attr := range filter.DropAttributes {
+				attributes.Remove(attr)
+			}
+			for _, from := range renames {
+				value, ok := attributes.Get(from)
+				if !ok {
+					continue
+				}
+				renamed := pcommon.NewValueEmpty()
+				value.CopyTo(renamed)
+				attributes.Remove(from)
+				renamed.CopyTo(attributes.PutEmpty(filter.RenameAttributes[from]))
+			}
+			for label, value := range filter.AddLabels {
+				attributes.PutStr(label, value)
+			}
+		}
+	}
+}
+
+// keepMetric tells whether the metric name passes the include and exclude globs of the filter
+func keepMetric(filter *policiespb.SinkFilter, name string) bool {
+	if len(filter.IncludeMetrics) > 0 && !matchAny(filter.IncludeMetrics, name) {
+		return false
+	}
+	return !matchAny(filter.ExcludeMetrics, name)
+}
+
+func matchAny(globs []string, name string) bool {
+	for _, glob := range globs {
+		if matched, _ := path.Match(glob, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// dataPointsAttributes returns the attributes of every data point of the metric
+func dataPointsAttributes(metricItem pmetric.Metric) []pcommon.Map {
+	var attributes []pcommon.Map
+	switch metricItem.Type() {
+	case pmetric.MetricTypeExponentialHistogram:
+		for i := 0; i < metricItem.ExponentialHistogram().DataPoints().Len(); i++ {
+			attributes = append(attributes, metricItem.ExponentialHistogram().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeGauge:
+		for i := 0; i < metricItem.Gauge().DataPoints().Len(); i++ {
+			attributes = append(attributes, metricItem.Gauge().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeHistogram:
+		for i := 0; i < metricItem.Histogram().DataPoints().Len(); i++ {
+			attributes = append(attributes, metricItem.Histogram().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeSum:
+		for i := 0; i < metricItem.Sum().DataPoints().Len(); i++ {
+			attributes = append(attributes, metricItem.Sum().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeSummary:
+		for i := 0; i < metricItem.Summary().DataPoints().Len(); i++ {
+			attributes = append(attributes, metricItem.Summary().DataPoints().At(i).Attributes())
+		}
+	}
+	return attributes
+}
@@ -0,0 +1,76 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package orbreceiver
+
+import (
+	"testing"
+
+	policiespb "github.com/orb-community/orb/policies/pb"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func newScope(names ...string) pmetric.ScopeMetrics {
+	scope := pmetric.NewScopeMetrics()
+	for _, name := range names {
+		metricItem := scope.Metrics().AppendEmpty()
+		metricItem.SetName(name)
+		dp := metricItem.SetEmptyGauge().DataPoints().AppendEmpty()
+		dp.Attributes().PutStr("agent", "agent-1")
+		dp.Attributes().PutStr("instance", "10.0.0.1")
+	}
+	return scope
+}
+
+func metricNames(scope pmetric.ScopeMetrics) []string {
+	var names []string
+	for i := 0; i < scope.Metrics().Len(); i++ {
+		names = append(names, scope.Metrics().At(i).Name())
+	}
+	return names
+}
+
+func TestApplySinkFilter(t *testing.T) {
+	cases := map[string]struct {
+		filter     *policiespb.SinkFilter
+		names      []string
+		attributes map[string]interface{}
+	}{
+		"no filter sends every metric untouched": {
+			filter:     nil,
+			names:      []string{"dns_wire_packets", "dns_latency_histogram", "packets_total"},
+			attributes: map[string]interface{}{"agent": "agent-1", "instance": "10.0.0.1"},
+		},
+		"include and exclude by glob": {
+			filter: &policiespb.SinkFilter{
+				IncludeMetrics: []string{"dns_*"},
+				ExcludeMetrics: []string{"*_histogram"},
+			},
+			names:      []string{"dns_wire_packets"},
+			attributes: map[string]interface{}{"agent": "agent-1", "instance": "10.0.0.1"},
+		},
+		"drop, rename and add attributes": {
+			filter: &policiespb.SinkFilter{
+				DropAttributes:   []string{"instance"},
+				RenameAttributes: map[string]string{"agent": "host"},
+				AddLabels:        map[string]string{"tier": "saas"},
+			},
+			names:      []string{"dns_wire_packets", "dns_latency_histogram", "packets_total"},
+			attributes: map[string]interface{}{"host": "agent-1", "tier": "saas"},
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			scope := newScope("dns_wire_packets", "dns_latency_histogram", "packets_total")
+			applySinkFilter(scope, tc.filter)
+			assert.Equal(t, tc.names, metricNames(scope), "unexpected metrics")
+			for i := 0; i < scope.Metrics().Len(); i++ {
+				attributes := scope.Metrics().At(i).Gauge().DataPoints().At(0).Attributes().AsRaw()
+				assert.Equal(t, tc.attributes, attributes, "unexpected data point attributes")
+			}
+		})
+	}
+}
@@ -101,7 +101,7 @@ func (r *OrbReceiver) ProccessMetricsContext(scope pmetric.ScopeMetrics, channel
 	r.injectScopeMetricsAttribute(scope, "policy_id", polID)
 
 	scope = r.replaceScopeMetricsTimestamp(scope, pcommon.NewTimestampFromTime(time.Now()))
-	sinkIds, err := r.sinkerService.GetSinkIdsFromDatasetIDs(execCtx, agentPb.OwnerID, datasetIDs)
+	sinkFilters, err := r.sinkerService.GetSinkFiltersFromDatasetIDs(execCtx, agentPb.OwnerID, datasetIDs)
 	if err != nil {
 		execCancelF()
 		r.cfg.Logger.Info("No data extracting metrics sinks information from datasetIds = " + datasets)
@@ -113,14 +113,20 @@ func (r *OrbReceiver) ProccessMetricsContext(scope pmetric.ScopeMetrics, channel
 	attributeCtx = context.WithValue(attributeCtx, "agent_groups", agentPb.AgentGroupIDs)
 	attributeCtx = context.WithValue(attributeCtx, "agent_ownerID", agentPb.OwnerID)
 
-	for sinkId := range sinkIds {
+	for sinkId, sinkFilter := range sinkFilters {
 		err := r.cfg.SinkerService.NotifyActiveSink(r.ctx, agentPb.OwnerID, sinkId, strconv.Itoa(size))
 		if err != nil {
 			r.cfg.Logger.Error("error notifying metrics sink active, changing state, skipping sink", zap.String("sink-id", sinkId), zap.Error(err))
 		}
 		attributeCtx = context.WithValue(attributeCtx, "sink_id", sinkId)
 		mr := pmetric.NewMetrics()
-		scope.CopyTo(mr.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty())
+		sinkScope := mr.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+		scope.CopyTo(sinkScope)
+		applySinkFilter(sinkScope, sinkFilter)
+		if sinkScope.Metrics().Len() == 0 {
+			r.cfg.Logger.Debug("every metric filtered out for sink, skipping sink", zap.String("sink-id", sinkId))
+			continue
+		}
 		mr.ResourceMetrics().At(0).Resource().Attributes().PutStr("service.name", agentPb.AgentName)
 		mr.ResourceMetrics().At(0).Resource().Attributes().PutStr("service.instance.id", polID)
 		request := pmetricotlp.NewExportRequestFromMetrics(mr)
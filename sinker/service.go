@@ -39,6 +39,7 @@ type SinkerService struct {
 	otel                   bool
 	otelMetricsCancelFunct context.CancelFunc
 	otelLogsCancelFunct    context.CancelFunc
+	otelTracesCancelFunct  context.CancelFunc
 	otelKafkaUrl           string
 
 	inMemoryCacheExpiration time.Duration
@@ -99,12 +100,22 @@ func (svc SinkerService) startOtel(ctx context.Context) error {
 		bridgeService := bridgeservice.NewBridgeService(svc.logger, svc.inMemoryCacheExpiration, svc.sinkActivitySvc,
 			svc.policiesClient, svc.sinksClient, svc.fleetClient, svc.messageInputCounter)
 		svc.otelMetricsCancelFunct, err = otel.StartOtelMetricsComponents(ctx, &bridgeService, svc.logger, svc.otelKafkaUrl, svc.pubSub)
+		if err != nil {
+			svc.logger.Error("error during StartOtelMetricsComponents", zap.Error(err))
+			return err
+		}
 
 		// starting Otel Logs components
 		svc.otelLogsCancelFunct, err = otel.StartOtelLogsComponents(ctx, &bridgeService, svc.logger, svc.otelKafkaUrl, svc.pubSub)
+		if err != nil {
+			svc.logger.Error("error during StartOtelLogsComponents", zap.Error(err))
+			return err
+		}
 
+		// starting Otel Traces components
+		svc.otelTracesCancelFunct, err = otel.StartOtelTracesComponents(ctx, &bridgeService, svc.logger, svc.otelKafkaUrl, svc.pubSub)
 		if err != nil {
-			svc.logger.Error("error during StartOtelComponents", zap.Error(err))
+			svc.logger.Error("error during StartOtelTracesComponents", zap.Error(err))
 			return err
 		}
 	}
@@ -423,17 +423,18 @@ func viewSinkEndpoint(svc sinks.SinkService) endpoint.Endpoint {
 		}
 		responseSink, err := omitSecretInformation(&cfg, sink)
 		res := sinkRes{
-			ID:          sink.ID,
-			Name:        sink.Name.String(),
-			Description: *sink.Description,
-			Tags:        sink.Tags,
-			State:       sink.State.String(),
-			Error:       sink.Error,
-			Backend:     sink.Backend,
-			Config:      responseSink.Config,
-			ConfigData:  responseSink.ConfigData,
-			Format:      sink.Format,
-			TsCreated:   sink.Created,
+			ID:            sink.ID,
+			Name:          sink.Name.String(),
+			Description:   *sink.Description,
+			Tags:          sink.Tags,
+			State:         sink.State.String(),
+			Error:         sink.Error,
+			Backend:       sink.Backend,
+			Config:        responseSink.Config,
+			ConfigData:    responseSink.ConfigData,
+			Format:        sink.Format,
+			TsCreated:     sink.Created,
+			DeliveryStats: sink.DeliveryStats,
 		}
 		if sink.Description != nil {
 			res.Description = *sink.Description
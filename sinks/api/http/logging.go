@@ -49,6 +49,20 @@ func (l loggingMiddleware) ChangeSinkStateInternal(ctx context.Context, sinkID s
 	return l.svc.ChangeSinkStateInternal(ctx, sinkID, msg, ownerID, state)
 }
 
+func (l loggingMiddleware) UpdateDeliveryStatsInternal(ctx context.Context, sinkID string, ownerID string, stats sinks.DeliveryStats) (err error) {
+	defer func(begin time.Time) {
+		if err != nil {
+			l.logger.Warn("method call: update_delivery_stats_internal",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(begin)))
+		} else {
+			l.logger.Debug("method call: update_delivery_stats_internal",
+				zap.Duration("duration", time.Since(begin)))
+		}
+	}(time.Now())
+	return l.svc.UpdateDeliveryStatsInternal(ctx, sinkID, ownerID, stats)
+}
+
 func (l loggingMiddleware) CreateSink(ctx context.Context, token string, s sinks.Sink) (_ sinks.Sink, err error) {
 	defer func(begin time.Time) {
 		if err != nil {
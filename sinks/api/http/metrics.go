@@ -47,6 +47,11 @@ func (m metricsMiddleware) ChangeSinkStateInternal(ctx context.Context, sinkID s
 	return m.svc.ChangeSinkStateInternal(ctx, sinkID, msg, ownerID, state)
 }
 
+// UpdateDeliveryStatsInternal Will not count metrics since maestro reports the stats of every sink at each scan
+func (m metricsMiddleware) UpdateDeliveryStatsInternal(ctx context.Context, sinkID string, ownerID string, stats sinks.DeliveryStats) error {
+	return m.svc.UpdateDeliveryStatsInternal(ctx, sinkID, ownerID, stats)
+}
+
 func (m metricsMiddleware) ListAuthenticationTypes(ctx context.Context, token string) ([]authentication_type.AuthenticationTypeConfig, error) {
 	return m.svc.ListAuthenticationTypes(ctx, token)
 }
@@ -6,24 +6,26 @@ package http
 
 import (
 	"github.com/orb-community/orb/pkg/types"
+	"github.com/orb-community/orb/sinks"
 	"github.com/orb-community/orb/sinks/authentication_type"
 	"net/http"
 	"time"
 )
 
 type sinkRes struct {
-	ID          string         `json:"id"`
-	Name        string         `json:"name"`
-	Description string         `json:"description"`
-	Tags        types.Tags     `json:"tags,omitempty"`
-	State       string         `json:"state,omitempty"`
-	Error       string         `json:"error,omitempty"`
-	Backend     string         `json:"backend,omitempty"`
-	Config      types.Metadata `json:"config,omitempty"`
-	Format      string         `json:"format,omitempty"`
-	ConfigData  string         `json:"config_data,omitempty"`
-	TsCreated   time.Time      `json:"ts_created,omitempty"`
-	created     bool
+	ID            string               `json:"id"`
+	Name          string               `json:"name"`
+	Description   string               `json:"description"`
+	Tags          types.Tags           `json:"tags,omitempty"`
+	State         string               `json:"state,omitempty"`
+	Error         string               `json:"error,omitempty"`
+	Backend       string               `json:"backend,omitempty"`
+	Config        types.Metadata       `json:"config,omitempty"`
+	Format        string               `json:"format,omitempty"`
+	ConfigData    string               `json:"config_data,omitempty"`
+	TsCreated     time.Time            `json:"ts_created,omitempty"`
+	DeliveryStats *sinks.DeliveryStats `json:"delivery_stats,omitempty"`
+	created       bool
 }
 
 func (s sinkRes) Code() int {
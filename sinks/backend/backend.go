@@ -16,8 +16,15 @@ type Backend interface {
 	ConfigToFormat(format string, metadata types.Metadata) (string, error)
 }
 
+// AuthenticationRestricted is implemented by the backends able to authenticate with only some of the authentication types
+type AuthenticationRestricted interface {
+	SupportsAuthentication(authType string) bool
+}
+
 const ConfigFeatureTypePassword = "password"
 const ConfigFeatureTypeText = "text"
+const ConfigFeatureTypeBoolean = "boolean"
+const ConfigFeatureTypeObject = "object"
 
 type ConfigFeature struct {
 	Type     string `json:"type"`
@@ -25,6 +32,8 @@ type ConfigFeature struct {
 	Title    string `json:"title"`
 	Name     string `json:"name"`
 	Required bool   `json:"required"`
+	// Fields are the settings of an object feature, sent as an object under the name of the feature
+	Fields []ConfigFeature `json:"fields,omitempty"`
 }
 
 type SinkFeature struct {
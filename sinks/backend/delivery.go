@@ -0,0 +1,168 @@
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+)
+
+// DeliveryConfigFeature is the optional field of the exporter configuration tuning how the sink collector delivers, e.g.
+//
+//	exporter:
+//	  remote_host: https://acme.com/prom/push
+//	  delivery:
+//	    batch:
+//	      send_batch_size: 1000
+//	      timeout: 5s
+//	    retry:
+//	      initial_interval: 5s
+//	      max_interval: 30s
+//	      max_elapsed_time: 5m
+//	    sending_queue:
+//	      queue_size: 5000
+//	      persistent: true
+const DeliveryConfigFeature = "delivery"
+
+// limits of the delivery settings, keeping a single sink from exhausting the memory of its collector
+const (
+	maxBatchSize      = 100000
+	maxBatchTimeout   = time.Minute
+	maxRetryInterval  = 10 * time.Minute
+	maxRetryElapsed   = time.Hour
+	maxQueueSize      = 100000
+	maxQueueConsumers = 100
+	minRetryInterval  = 100 * time.Millisecond
+)
+
+type DeliveryConfig struct {
+	Batch        *BatchConfig        `json:"batch,omitempty"`
+	Retry        *RetryConfig        `json:"retry,omitempty"`
+	SendingQueue *SendingQueueConfig `json:"sending_queue,omitempty"`
+}
+
+type BatchConfig struct {
+	SendBatchSize    int    `json:"send_batch_size,omitempty"`
+	SendBatchMaxSize int    `json:"send_batch_max_size,omitempty"`
+	Timeout          string `json:"timeout,omitempty"`
+}
+
+type RetryConfig struct {
+	Enabled         *bool  `json:"enabled,omitempty"`
+	InitialInterval string `json:"initial_interval,omitempty"`
+	MaxInterval     string `json:"max_interval,omitempty"`
+	MaxElapsedTime  string `json:"max_elapsed_time,omitempty"`
+}
+
+type SendingQueueConfig struct {
+	Enabled      *bool `json:"enabled,omitempty"`
+	NumConsumers int   `json:"num_consumers,omitempty"`
+	QueueSize    int   `json:"queue_size,omitempty"`
+	// Persistent keeps the queue on the file storage of the collector, so it survives restarts. On kubernetes the
+	// storage is a persistent volume claim of the collector, deleted along with the sink
+	Persistent bool `json:"persistent,omitempty"`
+}
+
+// IsEnabled tells whether retrying is on, it is unless explicitly disabled
+func (r RetryConfig) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// IsEnabled tells whether the sending queue is on, it is unless explicitly disabled
+func (q SendingQueueConfig) IsEnabled() bool {
+	return q.Enabled == nil || *q.Enabled
+}
+
+// GetDeliveryConfig reads and validates the delivery settings of the exporter configuration, it returns nil when the sink
+// has none, keeping the collector defaults
+func GetDeliveryConfig(exporterConfig types.Metadata) (*DeliveryConfig, error) {
+	raw, ok := exporterConfig[DeliveryConfigFeature]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	// the configuration is either decoded from JSON or YAML, numbers don't share a type
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrInvalidDelivery, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.DisallowUnknownFields()
+	var config DeliveryConfig
+	if err := decoder.Decode(&config); err != nil {
+		return nil, errors.Wrap(errors.ErrInvalidDelivery, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, errors.Wrap(errors.ErrInvalidDelivery, err)
+	}
+	return &config, nil
+}
+
+func (c DeliveryConfig) validate() error {
+	if c.Batch != nil {
+		if err := validateSize("batch send_batch_size", c.Batch.SendBatchSize, maxBatchSize); err != nil {
+			return err
+		}
+		if err := validateSize("batch send_batch_max_size", c.Batch.SendBatchMaxSize, maxBatchSize); err != nil {
+			return err
+		}
+		if c.Batch.SendBatchMaxSize > 0 && c.Batch.SendBatchMaxSize < c.Batch.SendBatchSize {
+			return errors.New("batch send_batch_max_size must not be lower than send_batch_size")
+		}
+		if _, err := parseDuration("batch timeout", c.Batch.Timeout, 0, maxBatchTimeout); err != nil {
+			return err
+		}
+	}
+	if c.Retry != nil {
+		initial, err := parseDuration("retry initial_interval", c.Retry.InitialInterval, minRetryInterval, maxRetryInterval)
+		if err != nil {
+			return err
+		}
+		maxInterval, err := parseDuration("retry max_interval", c.Retry.MaxInterval, minRetryInterval, maxRetryInterval)
+		if err != nil {
+			return err
+		}
+		if initial > 0 && maxInterval > 0 && maxInterval < initial {
+			return errors.New("retry max_interval must not be lower than initial_interval")
+		}
+		if _, err := parseDuration("retry max_elapsed_time", c.Retry.MaxElapsedTime, 0, maxRetryElapsed); err != nil {
+			return err
+		}
+	}
+	if c.SendingQueue != nil {
+		if err := validateSize("sending_queue queue_size", c.SendingQueue.QueueSize, maxQueueSize); err != nil {
+			return err
+		}
+		if err := validateSize("sending_queue num_consumers", c.SendingQueue.NumConsumers, maxQueueConsumers); err != nil {
+			return err
+		}
+		if c.SendingQueue.Persistent && !c.SendingQueue.IsEnabled() {
+			return errors.New("sending_queue can not be persistent when disabled")
+		}
+	}
+	return nil
+}
+
+func validateSize(field string, size int, maxSize int) error {
+	if size < 0 || size > maxSize {
+		return errors.New(fmt.Sprintf("%s must be between 0 and %d", field, maxSize))
+	}
+	return nil
+}
+
+// parseDuration parses the optional duration of the field, zero when unset
+func parseDuration(field string, value string, minDuration time.Duration, maxDuration time.Duration) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("%s is not a valid duration", field))
+	}
+	if duration < minDuration || duration > maxDuration {
+		return 0, errors.New(fmt.Sprintf("%s must be between %s and %s", field, minDuration, maxDuration))
+	}
+	return duration, nil
+}
@@ -0,0 +1,105 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDeliveryConfig(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name    string
+		config  types.Metadata
+		want    *DeliveryConfig
+		wantErr bool
+	}{
+		{
+			name:   "no delivery settings",
+			config: types.Metadata{"remote_host": "https://acme.com/prom/push"},
+		},
+		{
+			name: "settings decoded from yaml",
+			config: types.Metadata{DeliveryConfigFeature: map[string]interface{}{
+				"batch":         map[string]interface{}{"send_batch_size": 1000, "send_batch_max_size": 2000, "timeout": "5s"},
+				"retry":         map[string]interface{}{"initial_interval": "5s", "max_interval": "30s", "max_elapsed_time": "0s"},
+				"sending_queue": map[string]interface{}{"queue_size": 5000, "num_consumers": 4, "persistent": true},
+			}},
+			want: &DeliveryConfig{
+				Batch:        &BatchConfig{SendBatchSize: 1000, SendBatchMaxSize: 2000, Timeout: "5s"},
+				Retry:        &RetryConfig{InitialInterval: "5s", MaxInterval: "30s", MaxElapsedTime: "0s"},
+				SendingQueue: &SendingQueueConfig{QueueSize: 5000, NumConsumers: 4, Persistent: true},
+			},
+		},
+		{
+			name: "settings decoded from json",
+			config: types.Metadata{DeliveryConfigFeature: map[string]interface{}{
+				"retry":         map[string]interface{}{"enabled": false},
+				"sending_queue": map[string]interface{}{"queue_size": float64(100)},
+			}},
+			want: &DeliveryConfig{
+				Retry:        &RetryConfig{Enabled: &disabled},
+				SendingQueue: &SendingQueueConfig{QueueSize: 100},
+			},
+		},
+		{
+			name:    "unknown setting",
+			config:  types.Metadata{DeliveryConfigFeature: map[string]interface{}{"compression": "gzip"}},
+			wantErr: true,
+		},
+		{
+			name:    "negative batch size",
+			config:  types.Metadata{DeliveryConfigFeature: map[string]interface{}{"batch": map[string]interface{}{"send_batch_size": -1}}},
+			wantErr: true,
+		},
+		{
+			name: "batch max size lower than batch size",
+			config: types.Metadata{DeliveryConfigFeature: map[string]interface{}{
+				"batch": map[string]interface{}{"send_batch_size": 1000, "send_batch_max_size": 500},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid retry interval",
+			config:  types.Metadata{DeliveryConfigFeature: map[string]interface{}{"retry": map[string]interface{}{"initial_interval": "5 seconds"}}},
+			wantErr: true,
+		},
+		{
+			name: "retry max interval lower than initial interval",
+			config: types.Metadata{DeliveryConfigFeature: map[string]interface{}{
+				"retry": map[string]interface{}{"initial_interval": "30s", "max_interval": "5s"},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "retrying for too long",
+			config:  types.Metadata{DeliveryConfigFeature: map[string]interface{}{"retry": map[string]interface{}{"max_elapsed_time": "24h"}}},
+			wantErr: true,
+		},
+		{
+			name:    "queue too large",
+			config:  types.Metadata{DeliveryConfigFeature: map[string]interface{}{"sending_queue": map[string]interface{}{"queue_size": 1000000}}},
+			wantErr: true,
+		},
+		{
+			name: "persistent disabled queue",
+			config: types.Metadata{DeliveryConfigFeature: map[string]interface{}{
+				"sending_queue": map[string]interface{}{"enabled": false, "persistent": true},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetDeliveryConfig(tt.config)
+			if tt.wantErr {
+				require.True(t, errors.Contains(err, errors.ErrInvalidDelivery), "expected invalid delivery error, got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
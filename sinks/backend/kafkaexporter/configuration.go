@@ -0,0 +1,267 @@
+package kafkaexporter
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/orb-community/orb/pkg/errors"
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/orb-community/orb/sinks/authentication_type/basicauth"
+	"github.com/orb-community/orb/sinks/backend"
+	"gopkg.in/yaml.v3"
+)
+
+// Kafka Exporter Example, the sink authentication is the SASL credentials
+// exporter:
+//   brokers: kafka-1.acme.com:9093,kafka-2.acme.com:9093
+//   topic: orb-metrics
+//   encoding: otlp_json
+//   compression: zstd
+//   sasl_mechanism: SCRAM-SHA-512
+//   tls:
+//     insecure_skip_verify: false
+//     ca_pem: |
+//       -----BEGIN CERTIFICATE-----
+
+const (
+	BackendName                 = "kafka"
+	BrokersConfigFeature        = "brokers"
+	TopicConfigFeature          = "topic"
+	EncodingConfigFeature       = "encoding"
+	CompressionConfigFeature    = "compression"
+	SASLMechanismConfigFeature  = "sasl_mechanism"
+	TLSConfigFeature            = "tls"
+	TLSInsecureSkipVerifyConfig = "insecure_skip_verify"
+	TLSCAPemConfig              = "ca_pem"
+
+	DefaultEncoding      = "otlp_proto"
+	DefaultSASLMechanism = "PLAIN"
+)
+
+var (
+	Encodings      = []string{"otlp_proto", "otlp_json"}
+	Compressions   = []string{"none", "gzip", "snappy", "lz4", "zstd"}
+	SASLMechanisms = []string{"PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512"}
+	// topicPattern are the topic names kafka accepts
+	topicPattern = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,249}$`)
+	// knownConfig are the settings of the exporter configuration
+	knownConfig = map[string]bool{
+		BrokersConfigFeature:          true,
+		TopicConfigFeature:            true,
+		EncodingConfigFeature:         true,
+		CompressionConfigFeature:      true,
+		SASLMechanismConfigFeature:    true,
+		TLSConfigFeature:              true,
+		backend.DeliveryConfigFeature: true,
+	}
+)
+
+var _ backend.Backend = (*KafkaBackend)(nil)
+var _ backend.AuthenticationRestricted = (*KafkaBackend)(nil)
+
+type KafkaBackend struct {
+}
+
+func (b *KafkaBackend) Metadata() interface{} {
+	return backend.SinkFeature{
+		Backend:     BackendName,
+		Description: "Kafka topic of a customer cluster, authenticated with SASL",
+		Config:      b.CreateFeatureConfig(),
+	}
+}
+
+func Register() bool {
+	backend.Register(BackendName, &KafkaBackend{})
+	return true
+}
+
+func (b *KafkaBackend) CreateFeatureConfig() []backend.ConfigFeature {
+	return []backend.ConfigFeature{
+		{
+			Type:     backend.ConfigFeatureTypeText,
+			Input:    "text",
+			Title:    "Brokers (comma separated host:port)",
+			Name:     BrokersConfigFeature,
+			Required: true,
+		},
+		{
+			Type:     backend.ConfigFeatureTypeText,
+			Input:    "text",
+			Title:    "Topic",
+			Name:     TopicConfigFeature,
+			Required: true,
+		},
+		{
+			Type:     backend.ConfigFeatureTypeText,
+			Input:    "text",
+			Title:    "Encoding (otlp_proto, otlp_json)",
+			Name:     EncodingConfigFeature,
+			Required: false,
+		},
+		{
+			Type:     backend.ConfigFeatureTypeText,
+			Input:    "text",
+			Title:    "Compression (none, gzip, snappy, lz4, zstd)",
+			Name:     CompressionConfigFeature,
+			Required: false,
+		},
+		{
+			Type:     backend.ConfigFeatureTypeText,
+			Input:    "text",
+			Title:    "SASL Mechanism (PLAIN, SCRAM-SHA-256, SCRAM-SHA-512)",
+			Name:     SASLMechanismConfigFeature,
+			Required: false,
+		},
+		{
+			Type:     backend.ConfigFeatureTypeObject,
+			Input:    "object",
+			Title:    "TLS",
+			Name:     TLSConfigFeature,
+			Required: false,
+			Fields: []backend.ConfigFeature{
+				{
+					Type:     backend.ConfigFeatureTypeBoolean,
+					Input:    "checkbox",
+					Title:    "Skip TLS Certificate Verification",
+					Name:     TLSInsecureSkipVerifyConfig,
+					Required: false,
+				},
+				{
+					Type:     backend.ConfigFeatureTypeText,
+					Input:    "textarea",
+					Title:    "TLS CA Certificate (PEM)",
+					Name:     TLSCAPemConfig,
+					Required: false,
+				},
+			},
+		},
+	}
+}
+
+// SupportsAuthentication the sink authentication is sent as the SASL username and password
+func (b *KafkaBackend) SupportsAuthentication(authType string) bool {
+	return authType == basicauth.AuthType
+}
+
+func (b *KafkaBackend) ValidateConfiguration(config types.Metadata) error {
+	// a setting the exporter builder does not read would be silently ignored, so it is rejected instead
+	for key := range config {
+		if !knownConfig[key] {
+			return errors.Wrap(errors.ErrMalformedEntity, errors.New(fmt.Sprintf("unknown kafka setting %s", key)))
+		}
+	}
+	brokers, err := GetBrokers(config)
+	if err != nil {
+		return err
+	}
+	if len(brokers) == 0 {
+		return errors.Wrap(errors.ErrEndpointNotFound, errors.New("brokers not found"))
+	}
+	for _, broker := range brokers {
+		if _, port, err := net.SplitHostPort(broker); err != nil || port == "" {
+			return errors.Wrap(errors.ErrInvalidEndpoint, errors.New(fmt.Sprintf("broker %s must be host:port", broker)))
+		}
+	}
+	topic, _ := config[TopicConfigFeature].(string)
+	if !topicPattern.MatchString(topic) {
+		return errors.Wrap(errors.ErrMalformedEntity, errors.New("topic must be a valid kafka topic name"))
+	}
+	if err := validateOption(config, EncodingConfigFeature, Encodings); err != nil {
+		return err
+	}
+	if err := validateOption(config, CompressionConfigFeature, Compressions); err != nil {
+		return err
+	}
+	if err := validateOption(config, SASLMechanismConfigFeature, SASLMechanisms); err != nil {
+		return err
+	}
+	if tls, ok := config[TLSConfigFeature]; ok && tls != nil {
+		tlsConfig, ok := tls.(map[string]interface{})
+		if !ok {
+			return errors.Wrap(errors.ErrMalformedEntity, errors.New("tls must be an object"))
+		}
+		for key, value := range tlsConfig {
+			switch key {
+			case TLSInsecureSkipVerifyConfig:
+				if _, ok := value.(bool); !ok {
+					return errors.Wrap(errors.ErrMalformedEntity, errors.New("tls insecure_skip_verify must be a boolean"))
+				}
+			case TLSCAPemConfig:
+				if pem, ok := value.(string); !ok || !strings.Contains(pem, "BEGIN CERTIFICATE") {
+					return errors.Wrap(errors.ErrMalformedEntity, errors.New("tls ca_pem must be a PEM encoded certificate"))
+				}
+			default:
+				return errors.Wrap(errors.ErrMalformedEntity, errors.New(fmt.Sprintf("unknown tls setting %s", key)))
+			}
+		}
+	}
+	if _, err := backend.GetDeliveryConfig(config); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetBrokers returns the brokers of the configuration, given either as a list or as a comma separated string
+func GetBrokers(config types.Metadata) ([]string, error) {
+	var brokers []string
+	switch value := config[BrokersConfigFeature].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		for _, broker := range strings.Split(value, ",") {
+			if broker = strings.TrimSpace(broker); broker != "" {
+				brokers = append(brokers, broker)
+			}
+		}
+	case []interface{}:
+		for _, broker := range value {
+			brokerStr, ok := broker.(string)
+			if !ok {
+				return nil, errors.Wrap(errors.ErrInvalidEndpoint, errors.New("brokers must be strings"))
+			}
+			brokers = append(brokers, strings.TrimSpace(brokerStr))
+		}
+	case []string:
+		brokers = value
+	default:
+		return nil, errors.Wrap(errors.ErrInvalidEndpoint, errors.New("brokers must be a list or a comma separated string"))
+	}
+	return brokers, nil
+}
+
+func validateOption(config types.Metadata, field string, options []string) error {
+	value, ok := config[field]
+	if !ok || value == nil {
+		return nil
+	}
+	for _, option := range options {
+		if value == option {
+			return nil
+		}
+	}
+	return errors.Wrap(errors.ErrMalformedEntity, errors.New(fmt.Sprintf("%s must be one of %s", field, strings.Join(options, ", "))))
+}
+
+func (b *KafkaBackend) ParseConfig(format string, config string) (retConfig types.Metadata, err error) {
+	if format == "yaml" {
+		retConfig = make(types.Metadata)
+		err = yaml.Unmarshal([]byte(config), &retConfig)
+		if err != nil {
+			return nil, errors.Wrap(errors.New("failed to unmarshal config"), err)
+		}
+	} else {
+		return nil, errors.New("format not supported")
+	}
+	return
+}
+
+func (b *KafkaBackend) ConfigToFormat(format string, metadata types.Metadata) (string, error) {
+	if format == "yaml" {
+		value, err := yaml.Marshal(metadata)
+		return string(value), err
+	} else {
+		return "", errors.New("format not supported")
+	}
+}
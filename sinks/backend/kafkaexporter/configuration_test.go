@@ -0,0 +1,111 @@
+package kafkaexporter
+
+import (
+	"testing"
+
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testCAPem = "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+
+func TestKafkaBackend_ValidateConfiguration(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  types.Metadata
+		wantErr bool
+	}{
+		{
+			name:   "valid configuration",
+			config: types.Metadata{BrokersConfigFeature: "kafka-1.acme.com:9093, kafka-2.acme.com:9093", TopicConfigFeature: "orb-metrics"},
+		},
+		{
+			name: "valid configuration with options",
+			config: types.Metadata{
+				BrokersConfigFeature:       []interface{}{"kafka-1.acme.com:9093"},
+				TopicConfigFeature:         "orb.metrics_v1",
+				EncodingConfigFeature:      "otlp_json",
+				CompressionConfigFeature:   "zstd",
+				SASLMechanismConfigFeature: "SCRAM-SHA-512",
+				TLSConfigFeature:           map[string]interface{}{TLSInsecureSkipVerifyConfig: false, TLSCAPemConfig: testCAPem},
+				"delivery":                 map[string]interface{}{"sending_queue": map[string]interface{}{"persistent": true}},
+			},
+		},
+		{
+			name:    "missing brokers",
+			config:  types.Metadata{TopicConfigFeature: "orb-metrics"},
+			wantErr: true,
+		},
+		{
+			name:    "broker without port",
+			config:  types.Metadata{BrokersConfigFeature: "kafka-1.acme.com", TopicConfigFeature: "orb-metrics"},
+			wantErr: true,
+		},
+		{
+			name:    "missing topic",
+			config:  types.Metadata{BrokersConfigFeature: "kafka-1.acme.com:9093"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid topic",
+			config:  types.Metadata{BrokersConfigFeature: "kafka-1.acme.com:9093", TopicConfigFeature: "orb metrics"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid encoding",
+			config:  types.Metadata{BrokersConfigFeature: "kafka-1.acme.com:9093", TopicConfigFeature: "orb-metrics", EncodingConfigFeature: "jaeger_proto"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid compression",
+			config:  types.Metadata{BrokersConfigFeature: "kafka-1.acme.com:9093", TopicConfigFeature: "orb-metrics", CompressionConfigFeature: "brotli"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid sasl mechanism",
+			config:  types.Metadata{BrokersConfigFeature: "kafka-1.acme.com:9093", TopicConfigFeature: "orb-metrics", SASLMechanismConfigFeature: "GSSAPI"},
+			wantErr: true,
+		},
+		{
+			name: "invalid ca certificate",
+			config: types.Metadata{BrokersConfigFeature: "kafka-1.acme.com:9093", TopicConfigFeature: "orb-metrics",
+				TLSConfigFeature: map[string]interface{}{TLSCAPemConfig: "not a certificate"}},
+			wantErr: true,
+		},
+		{
+			name: "unknown tls setting",
+			config: types.Metadata{BrokersConfigFeature: "kafka-1.acme.com:9093", TopicConfigFeature: "orb-metrics",
+				TLSConfigFeature: map[string]interface{}{"cert_file": "/etc/client.crt"}},
+			wantErr: true,
+		},
+		{
+			name: "unknown setting",
+			config: types.Metadata{BrokersConfigFeature: "kafka-1.acme.com:9093", TopicConfigFeature: "orb-metrics",
+				"tls.ca_pem": testCAPem},
+			wantErr: true,
+		},
+		{
+			name: "invalid delivery",
+			config: types.Metadata{BrokersConfigFeature: "kafka-1.acme.com:9093", TopicConfigFeature: "orb-metrics",
+				"delivery": map[string]interface{}{"batch": map[string]interface{}{"timeout": "1h"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &KafkaBackend{}
+			err := b.ValidateConfiguration(tt.config)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestKafkaBackend_SupportsAuthentication(t *testing.T) {
+	b := &KafkaBackend{}
+	require.True(t, b.SupportsAuthentication("basicauth"))
+	require.False(t, b.SupportsAuthentication("bearertokenauth"))
+}
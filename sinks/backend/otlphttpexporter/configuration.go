@@ -105,6 +105,9 @@ func (b *OTLPHTTPBackend) ValidateConfiguration(config types.Metadata) error {
 			}
 		}
 	}
+	if _, err := backend.GetDeliveryConfig(config); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -66,6 +66,14 @@ func (p *Backend) ValidateConfiguration(config types.Metadata) error {
 			}
 		}
 	}
+	delivery, err := backend.GetDeliveryConfig(config)
+	if err != nil {
+		return err
+	}
+	// the remote write exporter only queues in memory
+	if delivery != nil && delivery.SendingQueue != nil && delivery.SendingQueue.Persistent {
+		return errors.Wrap(errors.ErrInvalidDelivery, errors.New("prometheus sinks do not support a persistent sending_queue"))
+	}
 	return nil
 }
 
@@ -35,6 +35,26 @@ func TestBackend_ValidateConfiguration(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid delivery configuration",
+			args: args{
+				config: map[string]interface{}{
+					RemoteHostURLConfigFeature: "https://acme.com/prom/push",
+					"delivery":                 map[string]interface{}{"sending_queue": map[string]interface{}{"queue_size": 5000}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "persistent queue configuration",
+			args: args{
+				config: map[string]interface{}{
+					RemoteHostURLConfigFeature: "https://acme.com/prom/push",
+					"delivery":                 map[string]interface{}{"sending_queue": map[string]interface{}{"persistent": true}},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "missing host configuration",
 			args: args{
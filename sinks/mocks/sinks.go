@@ -46,6 +46,10 @@ func (s *sinkRepositoryMock) UpdateSinkState(_ context.Context, _ string, _ stri
 	return nil
 }
 
+func (s *sinkRepositoryMock) UpdateDeliveryStats(_ context.Context, _ string, _ string, _ sinks.DeliveryStats) error {
+	return nil
+}
+
 func (s *sinkRepositoryMock) RetrieveByOwnerAndId(_ context.Context, ownerID string, key string) (sinks.Sink, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
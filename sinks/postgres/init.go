@@ -103,6 +103,15 @@ func migrateDB(db *sqlx.DB) error {
 					`ALTER TYPE public.sinks_state DROP VALUE IF EXISTS 'provisioning_error';`,
 				},
 			},
+			{
+				Id: "sinks_5",
+				Up: []string{
+					`ALTER TABLE sinks ADD COLUMN IF NOT EXISTS delivery_stats JSONB;`,
+				},
+				Down: []string{
+					`ALTER TABLE sinks DROP COLUMN IF EXISTS delivery_stats;`,
+				},
+			},
 		},
 	}
 
@@ -264,7 +264,7 @@ func (s sinksRepository) RetrieveAllByOwnerID(ctx context.Context, owner string,
 
 func (s sinksRepository) RetrieveById(ctx context.Context, id string) (sinks.Sink, error) {
 
-	q := `SELECT id, name, mf_owner_id, description, tags, backend, metadata, format, config_data, ts_created, state, coalesce(error, '') as error
+	q := `SELECT id, name, mf_owner_id, description, tags, backend, metadata, format, config_data, ts_created, state, coalesce(error, '') as error, delivery_stats
 			FROM sinks where id = $1`
 
 	dba := dbSink{}
@@ -282,7 +282,7 @@ func (s sinksRepository) RetrieveById(ctx context.Context, id string) (sinks.Sin
 
 func (s sinksRepository) RetrieveByOwnerAndId(ctx context.Context, ownerID string, id string) (sinks.Sink, error) {
 
-	q := `SELECT id, name, mf_owner_id, description, tags, backend, metadata, format, config_data, ts_created, state, coalesce(error, '') as error
+	q := `SELECT id, name, mf_owner_id, description, tags, backend, metadata, format, config_data, ts_created, state, coalesce(error, '') as error, delivery_stats
 			FROM sinks where id = $1 and mf_owner_id = $2`
 
 	if ownerID == "" || id == "" {
@@ -343,19 +343,46 @@ func (s sinksRepository) UpdateSinkState(ctx context.Context, sinkID string, msg
 	return nil
 }
 
+func (s sinksRepository) UpdateDeliveryStats(ctx context.Context, sinkID string, ownerID string, stats sinks.DeliveryStats) error {
+	dbsk := dbSink{
+		ID:            sinkID,
+		MFOwnerID:     ownerID,
+		DeliveryStats: &stats,
+	}
+
+	q := "update sinks set delivery_stats = :delivery_stats where mf_owner_id = :mf_owner_id and id = :id"
+
+	res, err := s.db.NamedExecContext(ctx, q, dbsk)
+	if err != nil {
+		return errors.Wrap(sinks.ErrUpdateEntity, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(sinks.ErrUpdateEntity, err)
+	}
+
+	if count == 0 {
+		return sinks.ErrUpdateEntity
+	}
+
+	return nil
+}
+
 type dbSink struct {
-	ID          string           `db:"id"`
-	Name        types.Identifier `db:"name"`
-	MFOwnerID   string           `db:"mf_owner_id"`
-	Metadata    db.Metadata      `db:"metadata"`
-	ConfigData  *string          `db:"config_data"`
-	Format      *string          `db:"format"`
-	Backend     string           `db:"backend"`
-	Description string           `db:"description"`
-	Created     time.Time        `db:"ts_created"`
-	Tags        db.Tags          `db:"tags"`
-	State       sinks.State      `db:"state"`
-	Error       string           `db:"error"`
+	ID            string               `db:"id"`
+	Name          types.Identifier     `db:"name"`
+	MFOwnerID     string               `db:"mf_owner_id"`
+	Metadata      db.Metadata          `db:"metadata"`
+	ConfigData    *string              `db:"config_data"`
+	Format        *string              `db:"format"`
+	Backend       string               `db:"backend"`
+	Description   string               `db:"description"`
+	Created       time.Time            `db:"ts_created"`
+	Tags          db.Tags              `db:"tags"`
+	State         sinks.State          `db:"state"`
+	Error         string               `db:"error"`
+	DeliveryStats *sinks.DeliveryStats `db:"delivery_stats"`
 }
 
 func toDBSink(sink sinks.Sink) (dbSink, error) {
@@ -402,18 +429,19 @@ func toSink(dba dbSink) (sinks.Sink, error) {
 	}
 
 	sink := sinks.Sink{
-		ID:          dba.ID,
-		Name:        dba.Name,
-		MFOwnerID:   dba.MFOwnerID,
-		Backend:     dba.Backend,
-		Description: &dba.Description,
-		State:       dba.State,
-		Error:       dba.Error,
-		Config:      types.Metadata(dba.Metadata),
-		ConfigData:  configData,
-		Format:      format,
-		Created:     dba.Created,
-		Tags:        types.Tags(dba.Tags),
+		ID:            dba.ID,
+		Name:          dba.Name,
+		MFOwnerID:     dba.MFOwnerID,
+		Backend:       dba.Backend,
+		Description:   &dba.Description,
+		State:         dba.State,
+		Error:         dba.Error,
+		Config:        types.Metadata(dba.Metadata),
+		ConfigData:    configData,
+		Format:        format,
+		Created:       dba.Created,
+		Tags:          types.Tags(dba.Tags),
+		DeliveryStats: dba.DeliveryStats,
 	}
 	return sink, nil
 }
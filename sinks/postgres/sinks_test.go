@@ -595,6 +595,80 @@ func TestUpdateSinkState(t *testing.T) {
 
 }
 
+func TestUpdateDeliveryStats(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	sinkRepo := postgres.NewSinksRepository(dbMiddleware, logger)
+
+	oID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	fakeSinkID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	nameID, err := types.NewIdentifier("my-delivering-sink")
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	sink := sinks.Sink{
+		Name:        nameID,
+		Description: &description,
+		Backend:     "prometheus",
+		Created:     time.Now(),
+		MFOwnerID:   oID.String(),
+		Config:      map[string]interface{}{"remote_host": "data", "username": "dbuser"},
+		Tags:        map[string]string{"cloud": "aws"},
+	}
+
+	sinkID, err := sinkRepo.Save(context.Background(), sink)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	got, err := sinkRepo.RetrieveById(context.Background(), sinkID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Nil(t, got.DeliveryStats, "expected no delivery stats before the first scrape")
+
+	stats := sinks.DeliveryStats{
+		SentItems:      1200,
+		FailedItems:    30,
+		QueueSize:      2,
+		QueueCapacity:  1000,
+		ItemsPerSecond: 20,
+		FailureRatio:   0.025,
+		ScrapedAt:      time.Now().UTC().Truncate(time.Second),
+	}
+
+	cases := map[string]struct {
+		sinkID  string
+		ownerID string
+		err     error
+	}{
+		"update delivery stats of an existing sink": {
+			sinkID:  sinkID,
+			ownerID: sink.MFOwnerID,
+			err:     nil,
+		},
+		"update delivery stats of a non-existent sink": {
+			sinkID:  fakeSinkID.String(),
+			ownerID: sink.MFOwnerID,
+			err:     sinks.ErrUpdateEntity,
+		},
+	}
+
+	for desc, tc := range cases {
+		t.Run(desc, func(t *testing.T) {
+			err := sinkRepo.UpdateDeliveryStats(context.Background(), tc.sinkID, tc.ownerID, stats)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+			// only validate success scenarios
+			if tc.err == nil {
+				got, err := sinkRepo.RetrieveByOwnerAndId(context.Background(), tc.ownerID, tc.sinkID)
+				require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+				require.NotNil(t, got.DeliveryStats, fmt.Sprintf("%s: expected delivery stats", desc))
+				assert.Equal(t, stats.SentItems, got.DeliveryStats.SentItems, desc)
+				assert.Equal(t, stats.FailureRatio, got.DeliveryStats.FailureRatio, desc)
+				assert.True(t, stats.ScrapedAt.Equal(got.DeliveryStats.ScrapedAt), desc)
+			}
+		})
+	}
+}
+
 func testSortSinks(t *testing.T, pm sinks.PageMetadata, sks []sinks.Sink) {
 	t.Helper()
 	switch pm.Order {
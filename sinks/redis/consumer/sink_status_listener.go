@@ -2,6 +2,7 @@ package consumer
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/go-redis/redis/v8"
@@ -75,7 +76,11 @@ func (s *sinkStatusListener) SubscribeToMaestroSinkStatus(ctx context.Context) e
 func (s *sinkStatusListener) ReceiveMessage(ctx context.Context, message redis.XMessage) error {
 	logger := s.logger.Named(fmt.Sprintf("sink_status_msg:%s", message.ID))
 	go func(ctx context.Context, logger *zap.Logger, message redis.XMessage) {
-		event := s.decodeMessage(message.Values)
+		event, err := s.decodeMessage(message.Values)
+		if err != nil {
+			logger.Error("failed to decode message", zap.Error(err))
+			return
+		}
 		logger.Info("received message from maestro", zap.String("owner_id", event.OwnerID),
 			zap.String("sink_id", event.SinkID), zap.String("state", event.State), zap.String("msg", event.Msg))
 		gotSink, err := s.sinkService.ViewSinkInternal(ctx, event.OwnerID, event.SinkID)
@@ -84,27 +89,45 @@ func (s *sinkStatusListener) ReceiveMessage(ctx context.Context, message redis.X
 				zap.String("sink_id", event.SinkID), zap.Error(err))
 			return
 		}
-		newState := sinks.NewStateFromString(event.State)
-		if newState == sinks.Error || newState == sinks.ProvisioningError || newState == sinks.Warning {
-			gotSink.Error = event.Msg
+		// the delivery stats of the monitor come without a state when the state of the sink did not change
+		if event.State != "" {
+			newState := sinks.NewStateFromString(event.State)
+			if newState == sinks.Error || newState == sinks.ProvisioningError || newState == sinks.Warning {
+				gotSink.Error = event.Msg
+			}
+			gotSink.State = newState
+			err = s.sinkService.ChangeSinkStateInternal(ctx, gotSink.ID, gotSink.Error, gotSink.MFOwnerID, gotSink.State)
+			if err != nil {
+				logger.Error("failed to update sink", zap.String("owner_id", event.OwnerID),
+					zap.String("sink_id", event.SinkID), zap.Error(err))
+				return
+			}
 		}
-		gotSink.State = newState
-		err = s.sinkService.ChangeSinkStateInternal(ctx, gotSink.ID, gotSink.Error, gotSink.MFOwnerID, gotSink.State)
-		if err != nil {
-			logger.Error("failed to update sink", zap.String("owner_id", event.OwnerID),
-				zap.String("sink_id", event.SinkID), zap.Error(err))
-			return
+		if event.DeliveryStats != nil {
+			err = s.sinkService.UpdateDeliveryStatsInternal(ctx, gotSink.ID, gotSink.MFOwnerID, *event.DeliveryStats)
+			if err != nil {
+				logger.Error("failed to update sink delivery stats", zap.String("owner_id", event.OwnerID),
+					zap.String("sink_id", event.SinkID), zap.Error(err))
+			}
 		}
 	}(ctx, logger, message)
 	return nil
 }
 
 // func (es eventStore) decodeSinkerStateUpdate(event map[string]interface{}) *sinks.SinkerStateUpdate {
-func (s *sinkStatusListener) decodeMessage(content map[string]interface{}) redis2.StateUpdateEvent {
-	return redis2.StateUpdateEvent{
+func (s *sinkStatusListener) decodeMessage(content map[string]interface{}) (redis2.StateUpdateEvent, error) {
+	event := redis2.StateUpdateEvent{
 		OwnerID: content["owner_id"].(string),
 		SinkID:  content["sink_id"].(string),
 		State:   content["status"].(string),
 		Msg:     content["error_message"].(string),
 	}
+	if encoded, ok := content["delivery_stats"].(string); ok {
+		var stats sinks.DeliveryStats
+		if err := json.Unmarshal([]byte(encoded), &stats); err != nil {
+			return redis2.StateUpdateEvent{}, err
+		}
+		event.DeliveryStats = &stats
+	}
+	return event, nil
 }
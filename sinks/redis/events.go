@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"github.com/orb-community/orb/maestro/redis"
 	"github.com/orb-community/orb/pkg/types"
+	"github.com/orb-community/orb/sinks"
 	"time"
 )
 
@@ -23,6 +24,8 @@ type StateUpdateEvent struct {
 	State     string
 	Msg       string
 	Timestamp time.Time
+	// DeliveryStats is only set on the events of the maestro monitor
+	DeliveryStats *sinks.DeliveryStats
 }
 
 func DecodeSinksEvent(event map[string]interface{}, operation string) (redis.SinksUpdateEvent, error) {
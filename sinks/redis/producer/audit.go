@@ -0,0 +1,64 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at https://mozilla.org/MPL/2.0/. */
+
+package producer
+
+import (
+	"github.com/orb-community/orb/pkg/types"
+	"github.com/orb-community/orb/sinks"
+	"github.com/orb-community/orb/sinks/authentication_type"
+	"github.com/orb-community/orb/sinks/authentication_type/basicauth"
+	"github.com/orb-community/orb/sinks/authentication_type/bearertokenauth"
+)
+
+// redacted replaces the secrets of a sink configuration in the audit log. Sinks are viewed encrypted and returned
+// decrypted, so a constant keeps an unchanged secret out of the diff as well as its value out of the log
+const redacted = "[redacted]"
+
+// sinkSnapshot is what the audit log compares to find what a change did
+type sinkSnapshot struct {
+	Name        string         `json:"name"`
+	Description *string        `json:"description"`
+	Backend     string         `json:"backend"`
+	Config      types.Metadata `json:"config"`
+	Tags        types.Tags     `json:"tags"`
+}
+
+func toSinkSnapshot(s sinks.Sink) *sinkSnapshot {
+	return &sinkSnapshot{
+		Name:        s.Name.String(),
+		Description: s.Description,
+		Backend:     s.Backend,
+		Config:      redactConfig(s.Config),
+		Tags:        s.Tags,
+	}
+}
+
+// redactConfig copies the configuration with the secrets of its authentication replaced, leaving the sink untouched
+func redactConfig(config types.Metadata) types.Metadata {
+	if config == nil {
+		return nil
+	}
+
+	copied := types.Metadata{}
+	for k, v := range config {
+		copied[k] = v
+	}
+	authMeta := config.GetSubMetadata(authentication_type.AuthenticationKey)
+	if authMeta == nil {
+		return copied
+	}
+
+	auth := types.Metadata{}
+	for k, v := range authMeta {
+		switch k {
+		case basicauth.PasswordConfigFeature, bearertokenauth.TokenConfigFeature:
+			auth[k] = redacted
+		default:
+			auth[k] = v
+		}
+	}
+	copied[authentication_type.AuthenticationKey] = auth
+	return copied
+}
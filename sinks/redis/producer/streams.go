@@ -14,6 +14,8 @@ import (
 	"github.com/orb-community/orb/sinks/authentication_type"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/orb-community/orb/audit"
+	"github.com/orb-community/orb/notifications"
 	"github.com/orb-community/orb/sinks"
 	"github.com/orb-community/orb/sinks/backend"
 	"go.uber.org/zap"
@@ -27,9 +29,11 @@ const (
 var _ sinks.SinkService = (*sinksStreamProducer)(nil)
 
 type sinksStreamProducer struct {
-	svc    sinks.SinkService
-	client *redis.Client
-	logger *zap.Logger
+	svc      sinks.SinkService
+	client   *redis.Client
+	recorder audit.Recorder
+	notifier notifications.Publisher
+	logger   *zap.Logger
 }
 
 // ListSinksInternal will only call following service
@@ -38,7 +42,31 @@ func (es sinksStreamProducer) ListSinksInternal(ctx context.Context, filter sink
 }
 
 func (es sinksStreamProducer) ChangeSinkStateInternal(ctx context.Context, sinkID string, msg string, ownerID string, state sinks.State) error {
-	return es.svc.ChangeSinkStateInternal(ctx, sinkID, msg, ownerID, state)
+	if state != sinks.Error {
+		return es.svc.ChangeSinkStateInternal(ctx, sinkID, msg, ownerID, state)
+	}
+
+	// only a sink entering the error state is notified, not every error status maestro reports on it
+	previous, viewErr := es.svc.ViewSinkInternal(ctx, ownerID, sinkID)
+	if err := es.svc.ChangeSinkStateInternal(ctx, sinkID, msg, ownerID, state); err != nil {
+		return err
+	}
+	if viewErr == nil && previous.State != sinks.Error {
+		es.notifier.Publish(ctx, notifications.StateEvent{
+			OwnerID:    ownerID,
+			Entity:     notifications.EntitySink,
+			EntityID:   sinkID,
+			EntityName: previous.Name.String(),
+			State:      notifications.StateError,
+			Message:    msg,
+		})
+	}
+	return nil
+}
+
+// UpdateDeliveryStatsInternal will only call following service
+func (es sinksStreamProducer) UpdateDeliveryStatsInternal(ctx context.Context, sinkID string, ownerID string, stats sinks.DeliveryStats) error {
+	return es.svc.UpdateDeliveryStatsInternal(ctx, sinkID, ownerID, stats)
 }
 
 func (es sinksStreamProducer) ViewSinkInternal(ctx context.Context, ownerID string, key string) (sinks.Sink, error) {
@@ -73,7 +101,13 @@ func (es sinksStreamProducer) CreateSink(ctx context.Context, token string, s si
 
 	}()
 
-	return es.svc.CreateSink(ctx, token, s)
+	sink, err = es.svc.CreateSink(ctx, token, s)
+	if err != nil {
+		return sink, err
+	}
+
+	es.recorder.Record(ctx, token, audit.EntitySink, sink.ID, audit.ActionCreate, nil, toSinkSnapshot(sink))
+	return sink, nil
 }
 
 func (es sinksStreamProducer) UpdateSinkInternal(ctx context.Context, s sinks.Sink) (sink sinks.Sink, err error) {
@@ -130,7 +164,19 @@ func (es sinksStreamProducer) UpdateSink(ctx context.Context, token string, s si
 			es.logger.Error("error sending event to sinks event store", zap.Error(err))
 		}
 	}()
-	return es.svc.UpdateSink(ctx, token, s)
+
+	previous, err := es.svc.ViewSink(ctx, token, s.ID)
+	if err != nil {
+		return sinks.Sink{}, err
+	}
+
+	sink, err = es.svc.UpdateSink(ctx, token, s)
+	if err != nil {
+		return sink, err
+	}
+
+	es.recorder.Record(ctx, token, audit.EntitySink, sink.ID, audit.ActionUpdate, toSinkSnapshot(previous), toSinkSnapshot(sink))
+	return sink, nil
 }
 
 func (es sinksStreamProducer) ListSinks(ctx context.Context, token string, pm sinks.PageMetadata) (sinks.Page, error) {
@@ -170,6 +216,7 @@ func (es sinksStreamProducer) DeleteSink(ctx context.Context, token, id string)
 	if err := es.svc.DeleteSink(ctx, token, id); err != nil {
 		return err
 	}
+	es.recorder.Record(ctx, token, audit.EntitySink, id, audit.ActionRemove, toSinkSnapshot(sink), nil)
 
 	event := deleteSinkEvent{
 		sinkID:  id,
@@ -201,10 +248,12 @@ func (es sinksStreamProducer) ValidateSink(ctx context.Context, token string, si
 }
 
 // NewSinkStreamProducerMiddleware returns wrapper around sinks service that sends
-// events to event store.
-func NewSinkStreamProducerMiddleware(svc sinks.SinkService, client *redis.Client) sinks.SinkService {
+// events to event store, and records the changes made through it to the audit log.
+func NewSinkStreamProducerMiddleware(svc sinks.SinkService, client *redis.Client, recorder audit.Recorder, notifier notifications.Publisher) sinks.SinkService {
 	return sinksStreamProducer{
-		svc:    svc,
-		client: client,
+		svc:      svc,
+		client:   client,
+		recorder: recorder,
+		notifier: notifier,
 	}
 }
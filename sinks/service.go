@@ -21,6 +21,7 @@ import (
 	"github.com/orb-community/orb/sinks/authentication_type"
 	"github.com/orb-community/orb/sinks/authentication_type/basicauth"
 	"github.com/orb-community/orb/sinks/authentication_type/bearertokenauth"
+	"github.com/orb-community/orb/sinks/backend/kafkaexporter"
 	"github.com/orb-community/orb/sinks/backend/otlphttpexporter"
 	"github.com/orb-community/orb/sinks/backend/prometheus"
 )
@@ -67,6 +68,7 @@ func (svc sinkService) GetLogger() *zap.Logger {
 }
 
 func NewSinkService(logger *zap.Logger, auth mainflux.AuthServiceClient, sinkRepo SinkRepository, mfsdk mfsdk.SDK, passwordService authentication_type.PasswordService) SinkService {
+	kafkaexporter.Register()
 	otlphttpexporter.Register()
 	prometheus.Register()
 	basicauth.Register(passwordService)
@@ -7,6 +7,7 @@ package sinks
 import (
 	"context"
 	"database/sql/driver"
+	"encoding/json"
 	"time"
 
 	"github.com/orb-community/orb/pkg/errors"
@@ -132,8 +133,34 @@ type Sink struct {
 	State       State
 	Error       string
 	Created     time.Time
+	// DeliveryStats is the delivery of the sink collector reported by maestro, nil until it first scraped the collector
+	DeliveryStats *DeliveryStats
 }
 
+// DeliveryStats is the delivery of the sink metrics by its otel collector
+type DeliveryStats struct {
+	SentItems      int64     `json:"sent_items"`
+	FailedItems    int64     `json:"failed_items"`
+	QueueSize      int64     `json:"queue_size"`
+	QueueCapacity  int64     `json:"queue_capacity"`
+	ItemsPerSecond float64   `json:"items_per_second"`
+	FailureRatio   float64   `json:"failure_ratio"`
+	ScrapedAt      time.Time `json:"scraped_at"`
+}
+
+func (s *DeliveryStats) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	asBytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("Scan source is not []byte")
+	}
+	return json.Unmarshal(asBytes, s)
+}
+
+func (s DeliveryStats) Value() (driver.Value, error) { return json.Marshal(s) }
+
 func (s *Sink) GetAuthenticationTypeName() string {
 	authMeta := s.Config.GetSubMetadata("authentication")
 	// Defaults to basicauth
@@ -180,6 +207,8 @@ type SinkService interface {
 	ValidateSink(ctx context.Context, token string, sink Sink) (Sink, error)
 	// ChangeSinkStateInternal change the sink internal state from new/idle/active
 	ChangeSinkStateInternal(ctx context.Context, sinkID string, msg string, ownerID string, state State) error
+	// UpdateDeliveryStatsInternal stores the delivery stats maestro scraped from the sink collector
+	UpdateDeliveryStatsInternal(ctx context.Context, sinkID string, ownerID string, stats DeliveryStats) error
 	// GetLogger gets service logger to log within gokit's packages
 	GetLogger() *zap.Logger
 }
@@ -202,6 +231,8 @@ type SinkRepository interface {
 	Remove(ctx context.Context, owner string, key string) error
 	// UpdateSinkState updates sink state like active, idle, new, unknown
 	UpdateSinkState(ctx context.Context, sinkID string, msg string, ownerID string, state State) error
+	// UpdateDeliveryStats updates the delivery stats of the sink collector
+	UpdateDeliveryStats(ctx context.Context, sinkID string, ownerID string, stats DeliveryStats) error
 	// GetVersion for migrate service
 	GetVersion(ctx context.Context) (string, error)
 	// UpsertVersion for migrate service
@@ -2,8 +2,11 @@ package sinks
 
 import (
 	"fmt"
+	"github.com/orb-community/orb/pkg/errors"
 	"github.com/orb-community/orb/sinks/authentication_type"
 	"github.com/orb-community/orb/sinks/authentication_type/basicauth"
+	"github.com/orb-community/orb/sinks/authentication_type/bearertokenauth"
+	"github.com/orb-community/orb/sinks/backend/kafkaexporter"
 	"github.com/orb-community/orb/sinks/backend/otlphttpexporter"
 	"github.com/orb-community/orb/sinks/backend/prometheus"
 	"github.com/stretchr/testify/assert"
@@ -14,6 +17,7 @@ import (
 
 func Test_sinkService_validateBackend(t *testing.T) {
 	logger := zap.NewNop()
+	kafkaexporter.Register()
 	otlphttpexporter.Register()
 	prometheus.Register()
 	passwordService := authentication_type.NewPasswordService(logger, "unit-test")
@@ -67,6 +71,24 @@ func Test_sinkService_validateBackend(t *testing.T) {
 			wantBe:  reflect.TypeOf(&otlphttpexporter.OTLPHTTPBackend{}),
 			wantErr: nil,
 		},
+		{
+			name: "kafka over yaml",
+			fields: fields{
+				svc: sinkService{
+					logger: logger,
+				},
+			},
+			args: args{
+				sink: &Sink{
+					Backend:    "kafka",
+					Config:     nil,
+					Format:     "yaml",
+					ConfigData: "authentication:\n  type: basicauth\n  password: \"password\"\n  username: \"user\"\nexporter:\n  brokers: \"kafka.acme.com:9093\"\n  topic: \"orb-metrics\"\n",
+				},
+			},
+			wantBe:  reflect.TypeOf(&kafkaexporter.KafkaBackend{}),
+			wantErr: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -78,3 +100,53 @@ func Test_sinkService_validateBackend(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateAuthType(t *testing.T) {
+	logger := zap.NewNop()
+	kafkaexporter.Register()
+	prometheus.Register()
+	passwordService := authentication_type.NewPasswordService(logger, "unit-test")
+	basicauth.Register(passwordService)
+	bearertokenauth.Register(passwordService)
+	tests := []struct {
+		name    string
+		sink    *Sink
+		wantErr bool
+	}{
+		{
+			name: "kafka with basicauth",
+			sink: &Sink{
+				Backend:    "kafka",
+				Format:     "yaml",
+				ConfigData: "authentication:\n  type: basicauth\n  password: \"password\"\n  username: \"user\"\n",
+			},
+		},
+		{
+			name: "kafka with bearertokenauth",
+			sink: &Sink{
+				Backend:    "kafka",
+				Format:     "yaml",
+				ConfigData: "authentication:\n  type: bearertokenauth\n  scheme: \"Bearer\"\n  token: \"token\"\n",
+			},
+			wantErr: true,
+		},
+		{
+			name: "prometheus with bearertokenauth",
+			sink: &Sink{
+				Backend:    "prometheus",
+				Format:     "yaml",
+				ConfigData: "authentication:\n  type: bearertokenauth\n  scheme: \"Bearer\"\n  token: \"token\"\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateAuthType(tt.sink)
+			if tt.wantErr {
+				assert.True(t, errors.Contains(err, errors.ErrAuthInvalidType), "validateAuthType(%v)", tt.sink.Backend)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
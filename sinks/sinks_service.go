@@ -103,6 +103,9 @@ func validateAuthType(s *Sink) (authentication_type.AuthenticationType, error) {
 	if !ok {
 		return nil, errors.Wrap(errors.ErrAuthInvalidType, errors.New("invalid authentication type"))
 	}
+	if be, ok := backend.GetBackend(s.Backend).(backend.AuthenticationRestricted); ok && !be.SupportsAuthentication(authTypeStr.(string)) {
+		return nil, errors.Wrap(errors.ErrAuthInvalidType, errors.New("authentication type not supported by the sink backend"))
+	}
 
 	err := authType.ValidateConfiguration("object", authMetadata)
 	if err != nil {
@@ -530,6 +533,10 @@ func (svc sinkService) ChangeSinkStateInternal(ctx context.Context, sinkID strin
 	return svc.sinkRepo.UpdateSinkState(ctx, sinkID, msg, ownerID, state)
 }
 
+func (svc sinkService) UpdateDeliveryStatsInternal(ctx context.Context, sinkID string, ownerID string, stats DeliveryStats) error {
+	return svc.sinkRepo.UpdateDeliveryStats(ctx, sinkID, ownerID, stats)
+}
+
 func (svc sinkService) validateBackend(sink *Sink) (be backend.Backend, err error) {
 	if !backend.HaveBackend(sink.Backend) {
 		return nil, ErrInvalidBackend